@@ -27,4 +27,51 @@ type Adapter interface {
 	MergeInto(db *gorm.DB, table string, columns []string, values [][]interface{}, keyColumns []string, updateColumns []string) error
 
 	QueryPage(dbOption interface{}, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter ...interface{}) (int64, error)
+
+	// CreateOrReplaceView 创建视图，同名视图已存在时替换其定义
+	CreateOrReplaceView(db *gorm.DB, name, query string) error
+
+	// DropView 删除视图，不存在时忽略
+	DropView(db *gorm.DB, name string) error
+
+	// ListViews 列出当前数据库（模式）中的视图
+	ListViews(db *gorm.DB) ([]string, error)
+
+	// CreateTrigger 创建行级触发器，同名触发器已存在时替换；不支持触发器的数据库返回 ErrTriggerUnsupported
+	CreateTrigger(db *gorm.DB, trigger Trigger) error
+
+	// DropTrigger 删除表上的触发器，不存在时忽略
+	DropTrigger(db *gorm.DB, table, name string) error
+
+	// Capabilities 数据库支持的特性
+	Capabilities() Capabilities
+}
+
+// Capabilities 数据库支持的特性，上层按特性分支而不是按数据库类型分支
+// 随版本变化的特性由实现 VersionAware 的适配器按连接的服务端版本返回
+type Capabilities struct {
+	Returning        bool // 写语句直接返回结果集（RETURNING 或 OUTPUT）
+	OutputClause     bool // 返回结果集使用 OUTPUT INSERTED 子句（SQLServer），否则为 RETURNING
+	CTE              bool // WITH 公共表表达式
+	RecursiveCTE     bool // WITH RECURSIVE 递归查询
+	WindowFunctions  bool // 窗口函数
+	Upsert           bool // 合并插入（ON CONFLICT、ON DUPLICATE KEY UPDATE 或 MERGE）
+	SkipLocked       bool // 跳过已锁定的行（SKIP LOCKED 或 READPAST）
+	NoWait           bool // 行已锁定时立即报错
+	Transactions     bool // 通过 GORM 使用事务
+	Savepoints       bool // 事务内保存点
+	ReleaseSavepoint bool // 可以 RELEASE SAVEPOINT 释放保存点（Oracle、SQLServer 不支持）
+	MaxBindParams    int  // 单条语句的参数上限（已预留余量），0 表示不使用绑定参数
+	MaxInList        int  // IN 列表的元素上限，0 表示只受 MaxBindParams 限制
+}
+
+// SavepointDialect 保存点语句不是 SAVEPOINT/ROLLBACK TO SAVEPOINT/RELEASE SAVEPOINT 的适配器实现，
+// 不支持释放保存点时 release 为空
+type SavepointDialect interface {
+	SavepointSQL(name string) (savepoint, rollbackTo, release string)
+}
+
+// RowEstimator 可以从统计信息估算表行数的适配器实现，没有统计信息时 ok 为 false
+type RowEstimator interface {
+	EstimateRows(db *gorm.DB, table string) (rows int64, ok bool, err error)
 }
@@ -0,0 +1,23 @@
+package adapter
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// dryRun 不连接数据库、只生成语句的会话，返回的函数取最近一条原始语句及参数
+func dryRun(t *testing.T, dialector gorm.Dialector) (*gorm.DB, func() (string, []interface{})) {
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("打开试运行会话失败: %v", err)
+	}
+	var sqlStr string
+	var vars []interface{}
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		sqlStr, vars = tx.Statement.SQL.String(), tx.Statement.Vars
+	}); err != nil {
+		t.Fatalf("注册回调失败: %v", err)
+	}
+	return db, func() (string, []interface{}) { return sqlStr, vars }
+}
@@ -121,6 +121,15 @@ func (c *ClickHouse) ForShare() string {
 	return ""
 }
 
+// Capabilities 数据库支持的特性，没有事务和合并插入，WITH 不支持递归
+func (c *ClickHouse) Capabilities() Capabilities {
+	return Capabilities{
+		CTE:             true,
+		WindowFunctions: true,
+		MaxBindParams:   60000,
+	}
+}
+
 // Limit 生成分页语句
 func (c *ClickHouse) Limit(offset, limit int) string {
 	return fmt.Sprintf("LIMIT %d, %d", offset, limit)
@@ -204,12 +213,9 @@ func (c *ClickHouse) QueryPage(dbOption interface{}, out interface{}, page, page
 	}
 
 	// 从参数中提取 db
-	if dbOption == nil {
-		return 0, fmt.Errorf("缺少必要参数：数据库连接")
-	}
-	db, ok := dbOption.(*gorm.DB)
-	if !ok {
-		return 0, fmt.Errorf("数据库连接参数必须是 *gorm.DB 类型")
+	db, options, err := pageDB(dbOption)
+	if err != nil {
+		return 0, err
 	}
 
 	// 计算偏移量
@@ -262,21 +268,12 @@ func (c *ClickHouse) QueryPage(dbOption interface{}, out interface{}, page, page
 		}
 	}
 
-	// 获取总记录数
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return 0, fmt.Errorf("查询总记录数失败: %w", err)
-	}
-
-	// 如果没有记录，直接返回
-	if total == 0 {
-		return 0, nil
-	}
-
-	// 执行分页查询
-	if err := query.Limit(pageSize).Offset(offset).Find(out).Error; err != nil {
-		return 0, fmt.Errorf("查询分页数据失败: %w", err)
-	}
-
-	return total, nil
+	// 查询总记录数和分页数据，不在事务中时并发执行
+	return RunPage(query, options, out, pageSize, func(db *gorm.DB) (int64, error) {
+		var total int64
+		err := db.Count(&total).Error
+		return total, err
+	}, func(db *gorm.DB, limit int) error {
+		return db.Limit(limit).Offset(offset).Find(out).Error
+	})
 }
@@ -0,0 +1,264 @@
+package adapter
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// DuckDBDriverName DuckDB 的 database/sql 驱动名
+// gosqlx 不直接依赖 DuckDB 驱动（需要 CGO），使用前需在程序中导入驱动:
+//
+//	import _ "github.com/duckdb/duckdb-go/v2"
+var DuckDBDriverName = "duckdb"
+
+// DuckDB 适配器结构体，用于嵌入式分析，DSN 为数据库文件路径，空字符串表示内存数据库
+type DuckDB struct {
+	// 基础配置
+	DSN         string        // 数据源名称（数据库文件路径）
+	MaxIdle     int           // 最大空闲连接数
+	MaxOpen     int           // 最大打开连接数
+	MaxLifetime time.Duration // 连接最大生命周期
+	Debug       bool          // 调试模式
+}
+
+// NewDuckDB 创建新的DuckDB适配器
+func NewDuckDB(dsn string) *DuckDB {
+	return &DuckDB{
+		DSN:         dsn,
+		MaxIdle:     10,
+		MaxOpen:     100,
+		MaxLifetime: time.Hour,
+		Debug:       false,
+	}
+}
+
+// WithMaxIdle 设置最大空闲连接数
+func (d *DuckDB) WithMaxIdle(maxIdle int) *DuckDB {
+	d.MaxIdle = maxIdle
+	return d
+}
+
+// WithMaxOpen 设置最大打开连接数
+func (d *DuckDB) WithMaxOpen(maxOpen int) *DuckDB {
+	d.MaxOpen = maxOpen
+	return d
+}
+
+// WithMaxLifetime 设置连接最大生命周期
+func (d *DuckDB) WithMaxLifetime(maxLifetime time.Duration) *DuckDB {
+	d.MaxLifetime = maxLifetime
+	return d
+}
+
+// WithDebug 设置调试模式
+func (d *DuckDB) WithDebug(debug bool) *DuckDB {
+	d.Debug = debug
+	return d
+}
+
+// DuckDBDialector DuckDB 的 GORM 方言
+// DuckDB 的语法与 PostgreSQL 兼容（$n 参数、RETURNING、ON CONFLICT），复用 Postgres 方言，仅调整名称和自增类型
+type DuckDBDialector struct {
+	postgres.Dialector
+}
+
+// OpenDuckDB 创建DuckDB方言
+func OpenDuckDB(dsn string) gorm.Dialector {
+	return &DuckDBDialector{Dialector: postgres.Dialector{Config: &postgres.Config{
+		DriverName: DuckDBDriverName,
+		DSN:        dsn,
+	}}}
+}
+
+// Name 方言名称
+func (DuckDBDialector) Name() string {
+	return "duckdb"
+}
+
+// Migrator 迁移器，使用 DuckDB 方言生成列类型
+func (d DuckDBDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return postgres.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+// DataTypeOf DuckDB 没有 serial 类型，自增列使用普通整数类型（需配合序列 DEFAULT nextval 使用）
+func (d DuckDBDialector) DataTypeOf(field *schema.Field) string {
+	dataType := d.Dialector.DataTypeOf(field)
+	switch dataType {
+	case "smallserial":
+		return "smallint"
+	case "serial":
+		return "integer"
+	case "bigserial":
+		return "bigint"
+	}
+	return dataType
+}
+
+// Connect 连接数据库
+func (d *DuckDB) Connect() (*gorm.DB, *sql.DB, error) {
+	// 创建GORM配置
+	config := &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true, // 使用单数表名
+		},
+		Logger: logger.Default.LogMode(logger.Silent),
+	}
+
+	// 如果开启调试模式，设置日志级别
+	if d.Debug {
+		config.Logger = logger.Default.LogMode(logger.Info)
+	}
+
+	// 连接数据库
+	db, err := gorm.Open(OpenDuckDB(d.DSN), config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 获取原生SQL连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 设置连接池参数
+	sqlDB.SetMaxIdleConns(d.MaxIdle)
+	sqlDB.SetMaxOpenConns(d.MaxOpen)
+	sqlDB.SetConnMaxLifetime(d.MaxLifetime)
+
+	return db, sqlDB, nil
+}
+
+// ForUpdate 生成FOR UPDATE锁定语句
+// DuckDB 使用乐观并发控制，不支持行锁
+func (d *DuckDB) ForUpdate() string {
+	return ""
+}
+
+// ForShare 生成共享锁语句
+// DuckDB 不支持行锁
+func (d *DuckDB) ForShare() string {
+	return ""
+}
+
+// Capabilities 数据库支持的特性
+func (d *DuckDB) Capabilities() Capabilities {
+	return Capabilities{
+		Returning:       true,
+		CTE:             true,
+		RecursiveCTE:    true,
+		WindowFunctions: true,
+		Upsert:          true,
+		Transactions:    true,
+		MaxBindParams:   60000,
+	}
+}
+
+// Limit 生成分页语句
+func (d *DuckDB) Limit(offset, limit int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+// BatchInsert 批量插入
+func (d *DuckDB) BatchInsert(db *gorm.DB, table string, columns []string, values [][]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	sqlStr, flatValues := d.insertValues(table, columns, values)
+	return db.Exec(sqlStr, flatValues...).Error
+}
+
+// MergeInto 合并插入（UPSERT），使用 INSERT ... ON CONFLICT DO UPDATE
+func (d *DuckDB) MergeInto(db *gorm.DB, table string, columns []string, values [][]interface{}, keyColumns []string, updateColumns []string) error {
+	if len(values) == 0 || len(keyColumns) == 0 {
+		return nil
+	}
+	sqlStr, flatValues := d.insertValues(table, columns, values)
+
+	action := "DO NOTHING"
+	if len(updateColumns) > 0 {
+		var updates []string
+		for _, col := range updateColumns {
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", col, col))
+		}
+		action = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+	sqlStr += fmt.Sprintf(" ON CONFLICT (%s) %s", strings.Join(keyColumns, ", "), action)
+	return db.Exec(sqlStr, flatValues...).Error
+}
+
+// insertValues 构建多行 INSERT 语句
+func (d *DuckDB) insertValues(table string, columns []string, values [][]interface{}) (string, []interface{}) {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	placeholders := make([]string, len(values))
+	flatValues := make([]interface{}, 0, len(values)*len(columns))
+	for i, v := range values {
+		placeholders[i] = row
+		flatValues = append(flatValues, v...)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(columns, ", "), strings.Join(placeholders, ", ")), flatValues
+}
+
+// QueryPage 分页查询，DuckDB 与 SQLite 的分页语法相同
+func (d *DuckDB) QueryPage(dbOption interface{}, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter ...interface{}) (int64, error) {
+	return (&SQLite{}).QueryPage(dbOption, out, page, pageSize, tableName, orderBy, filter...)
+}
+
+// ReadParquet 返回读取 Parquet 文件的表函数，可直接作为表名使用，支持通配符
+// 示例: db.NewQuery().Table(adapter.ReadParquet("data/events-*.parquet")).Where("day = ?", day)
+func ReadParquet(paths ...string) string {
+	return "read_parquet(" + duckdbList(paths) + ")"
+}
+
+// ReadCSV 返回自动识别格式读取 CSV 文件的表函数
+func ReadCSV(paths ...string) string {
+	return "read_csv_auto(" + duckdbList(paths) + ")"
+}
+
+// CopyToParquet 将查询结果导出为 Parquet 文件
+func (d *DuckDB) CopyToParquet(db *gorm.DB, query, path string, args ...interface{}) error {
+	return db.Exec(fmt.Sprintf("COPY (%s) TO %s (FORMAT PARQUET)", query, duckdbString(path)), args...).Error
+}
+
+// GetVersion 获取数据库版本
+func (d *DuckDB) GetVersion(db *gorm.DB) (string, error) {
+	var version string
+	err := db.Raw("SELECT version()").Scan(&version).Error
+	return version, err
+}
+
+// ShowTables 显示所有表
+func (d *DuckDB) ShowTables(db *gorm.DB) ([]string, error) {
+	var tables []string
+	err := db.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() ORDER BY table_name").Scan(&tables).Error
+	return tables, err
+}
+
+// duckdbList 单个路径返回字符串字面量，多个路径返回列表字面量
+func duckdbList(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = duckdbString(path)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// duckdbString 字符串字面量
+func duckdbString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,86 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// noConn 试运行不访问连接，未导入 DuckDB 驱动时代替连接
+type noConn struct{}
+
+var (
+	_         gorm.ConnPool = noConn{}
+	errNoConn               = errors.New("试运行不访问连接")
+)
+
+func (noConn) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, errNoConn }
+func (noConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errNoConn
+}
+func (noConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errNoConn
+}
+func (noConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+
+// 测试 DuckDB 适配器生成的语句，不需要 DuckDB 驱动
+func TestDuckDBStatements(t *testing.T) {
+	d := NewDuckDB("")
+	db, last := dryRun(t, &DuckDBDialector{Dialector: postgres.Dialector{Config: &postgres.Config{Conn: noConn{}}}})
+	columns := []string{"id", "kind", "amount"}
+	rows := [][]interface{}{{1, "view", 1.5}, {2, "click", 2.5}}
+
+	if err := d.BatchInsert(db, "events", columns, rows); err != nil {
+		t.Fatalf("BatchInsert: %v", err)
+	}
+	sqlStr, vars := last()
+	if want := "INSERT INTO events (id, kind, amount) VALUES ($1, $2, $3), ($4, $5, $6)"; sqlStr != want || len(vars) != 6 {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, vars)
+	}
+
+	if err := d.MergeInto(db, "events", columns, rows[:1], []string{"id"}, []string{"kind", "amount"}); err != nil {
+		t.Fatalf("MergeInto: %v", err)
+	}
+	sqlStr, vars = last()
+	want := "INSERT INTO events (id, kind, amount) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET kind = excluded.kind, amount = excluded.amount"
+	if sqlStr != want || !reflect.DeepEqual(vars, []interface{}{1, "view", 1.5}) {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, vars)
+	}
+	if err := d.MergeInto(db, "events", columns, rows[:1], []string{"id"}, nil); err != nil {
+		t.Fatalf("MergeInto: %v", err)
+	}
+	if sqlStr, _ = last(); sqlStr != "INSERT INTO events (id, kind, amount) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING" {
+		t.Errorf("没有更新列时应 DO NOTHING，实际为 %q", sqlStr)
+	}
+
+	if err := d.CopyToParquet(db, "SELECT * FROM events WHERE amount > ?", "/tmp/it's.parquet", 2); err != nil {
+		t.Fatalf("CopyToParquet: %v", err)
+	}
+	sqlStr, vars = last()
+	if want := "COPY (SELECT * FROM events WHERE amount > $1) TO '/tmp/it''s.parquet' (FORMAT PARQUET)"; sqlStr != want || !reflect.DeepEqual(vars, []interface{}{2}) {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, vars)
+	}
+
+	if got := ReadParquet("data/events-*.parquet"); got != "read_parquet('data/events-*.parquet')" {
+		t.Errorf("ReadParquet = %q", got)
+	}
+	if got := ReadCSV("a.csv", "b.csv"); got != "read_csv_auto(['a.csv', 'b.csv'])" {
+		t.Errorf("ReadCSV = %q", got)
+	}
+
+	// 自增列不使用 serial 类型
+	dialector := DuckDBDialector{Dialector: postgres.Dialector{Config: &postgres.Config{}}}
+	field := &schema.Field{DataType: schema.Int, Size: 64, AutoIncrement: true}
+	if got := dialector.DataTypeOf(field); got != "bigint" {
+		t.Errorf("DataTypeOf(自增 int64) = %q", got)
+	}
+	if dialector.Name() != "duckdb" {
+		t.Errorf("Name() = %q", dialector.Name())
+	}
+}
@@ -0,0 +1,88 @@
+package adapter
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 行数估算 ====================
+
+// mysqlEstimateSQL MySQL 系从 information_schema.tables 读取估算行数
+const mysqlEstimateSQL = "SELECT table_rows FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+
+// EstimateRows 从 pg_class 读取估算行数，从未 ANALYZE 时为 -1
+func (p *Postgres) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass(?)", table)
+}
+
+// EstimateRows 从 information_schema.tables 读取估算行数
+func (m *MySQL) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, mysqlEstimateSQL, table)
+}
+
+// EstimateRows 从 information_schema.tables 读取估算行数
+func (m *MariaDB) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, mysqlEstimateSQL, table)
+}
+
+// EstimateRows 从 information_schema.tables 读取估算行数
+func (t *TiDB) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, mysqlEstimateSQL, table)
+}
+
+// EstimateRows 从 information_schema.tables 读取估算行数
+func (o *OceanBase) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, mysqlEstimateSQL, table)
+}
+
+// EstimateRows 从 sys.dm_db_partition_stats 汇总堆或聚集索引的行数
+func (s *SQLServer) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, "SELECT SUM(row_count) FROM sys.dm_db_partition_stats WHERE object_id = OBJECT_ID(?) AND index_id IN (0, 1)", table)
+}
+
+// EstimateRows 从 system.parts 汇总活动分区的行数
+func (c *ClickHouse) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, "SELECT sum(rows) FROM system.parts WHERE active AND database = currentDatabase() AND table = ?", table)
+}
+
+// EstimateRows 从 user_tables 读取最近一次收集统计信息时的行数
+func (o *Oracle) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	return estimateRows(db, "SELECT num_rows FROM user_tables WHERE table_name = UPPER(?)", table)
+}
+
+// EstimateRows 从 ANALYZE 生成的 sqlite_stat1 读取行数，未执行过 ANALYZE 时没有统计信息
+func (s *SQLite) EstimateRows(db *gorm.DB, table string) (int64, bool, error) {
+	var stat string
+	if err := db.Raw("SELECT stat FROM sqlite_stat1 WHERE tbl = ? LIMIT 1", table).Row().Scan(&stat); err != nil {
+		return 0, false, nil
+	}
+	fields := strings.Fields(stat)
+	if len(fields) == 0 {
+		return 0, false, nil
+	}
+	rows, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	return rows, true, nil
+}
+
+// estimateRows 执行返回单个行数的统计查询，没有结果、为 NULL 或负数时 ok 为 false
+func estimateRows(db *gorm.DB, query string, args ...interface{}) (int64, bool, error) {
+	var rows sql.NullInt64
+	if err := db.Raw(query, args...).Row().Scan(&rows); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("估算表 %v 行数失败: %w", args[0], err)
+	}
+	if !rows.Valid || rows.Int64 < 0 {
+		return 0, false, nil
+	}
+	return rows.Int64, true, nil
+}
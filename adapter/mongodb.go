@@ -127,6 +127,11 @@ func (m *MongoDB) ForShare() string {
 	return ""
 }
 
+// Capabilities 数据库支持的特性，不通过 SQL 访问，均不支持
+func (m *MongoDB) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 // Limit 生成分页语句（MongoDB不使用SQL语法，返回空字符串）
 func (m *MongoDB) Limit(offset, limit int) string {
 	return ""
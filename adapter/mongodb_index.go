@@ -0,0 +1,349 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm/schema"
+)
+
+// MongoIndex 由模型标签生成的 MongoDB 索引定义
+type MongoIndex struct {
+	Name        string // 索引名，未指定时与 MongoDB 的默认命名一致（如 email_1、created_at_-1）
+	Keys        bson.D // 索引键，值为 1、-1、"text"、"2dsphere" 或 "hashed"
+	Unique      bool   // 唯一索引
+	Sparse      bool   // 稀疏索引
+	ExpireAfter *int32 // TTL 秒数，nil 表示不是 TTL 索引
+}
+
+// mongoIndexTypes 标签中的索引类型对应的键值
+var mongoIndexTypes = map[string]interface{}{
+	"index":    int32(1),
+	"unique":   int32(1),
+	"text":     "text",
+	"2dsphere": "2dsphere",
+	"hashed":   "hashed",
+}
+
+// MongoIndexes 解析模型的 mongo 标签生成索引定义，字段名取 db 标签，其次 bson 标签，否则为小写的字段名
+// 标签由分号分隔多个索引，每个索引为 "类型[:索引名][,选项...]"：
+//
+//	类型：index、unique、text、2dsphere、hashed
+//	选项：desc（降序）、sparse、ttl:秒数（仅单字段索引）
+//
+// 同名索引按字段顺序组成复合索引，任一字段为 unique 时整个索引唯一；未命名的 text 字段合并为一个文本索引
+//
+//	type Session struct {
+//		TenantID  int64     `db:"tenant_id" mongo:"unique:uk_tenant_token"`
+//		Token     string    `db:"token" mongo:"unique:uk_tenant_token"`
+//		UserID    int64     `db:"user_id" mongo:"index"`
+//		ExpiresAt time.Time `db:"expires_at" mongo:"index,ttl:0"`
+//	}
+func MongoIndexes(model interface{}) ([]MongoIndex, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("模型必须是结构体，得到 %T", model)
+	}
+
+	var (
+		indexes []*MongoIndex
+		named   = make(map[string]*MongoIndex)
+		text    *MongoIndex
+	)
+	err := eachMongoField(t, func(field, tag string) error {
+		for _, entry := range strings.Split(tag, ";") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			parts := strings.Split(entry, ",")
+			kind, name, _ := strings.Cut(strings.TrimSpace(parts[0]), ":")
+			value, ok := mongoIndexTypes[kind]
+			if !ok {
+				return fmt.Errorf("字段 %s 的索引类型 %q 不支持", field, kind)
+			}
+			var (
+				sparse bool
+				ttl    *int32
+			)
+			for _, option := range parts[1:] {
+				option = strings.TrimSpace(option)
+				switch {
+				case option == "desc" && value == int32(1):
+					value = int32(-1)
+				case option == "sparse":
+					sparse = true
+				case strings.HasPrefix(option, "ttl:"):
+					seconds, err := strconv.ParseInt(option[len("ttl:"):], 10, 32)
+					if err != nil || seconds < 0 {
+						return fmt.Errorf("字段 %s 的 TTL %q 不合法", field, option)
+					}
+					ttl = new(int32)
+					*ttl = int32(seconds)
+				default:
+					return fmt.Errorf("字段 %s 的索引选项 %q 不支持", field, option)
+				}
+			}
+
+			// 未命名的文本字段合并为一个文本索引
+			if kind == "text" && name == "" {
+				if text == nil {
+					text = &MongoIndex{}
+					indexes = append(indexes, text)
+				}
+				text.Keys = append(text.Keys, bson.E{Key: field, Value: value})
+				continue
+			}
+
+			index := named[name]
+			if index == nil {
+				index = &MongoIndex{Name: name}
+				indexes = append(indexes, index)
+				if name != "" {
+					named[name] = index
+				}
+			}
+			index.Keys = append(index.Keys, bson.E{Key: field, Value: value})
+			index.Unique = index.Unique || kind == "unique"
+			index.Sparse = index.Sparse || sparse
+			if ttl != nil {
+				index.ExpireAfter = ttl
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MongoIndex, 0, len(indexes))
+	for _, index := range indexes {
+		if index.ExpireAfter != nil && len(index.Keys) > 1 {
+			return nil, fmt.Errorf("TTL 只能用于单字段索引: %s", index.Name)
+		}
+		if index.Name == "" {
+			index.Name = defaultMongoIndexName(index.Keys)
+		}
+		result = append(result, *index)
+	}
+	return result, nil
+}
+
+// eachMongoField 遍历带 mongo 标签的字段，嵌入的结构体展开其字段
+func eachMongoField(t reflect.Type, fn func(field, tag string) error) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("db") == "" && field.Tag.Get("bson") == "" {
+			if err := eachMongoField(fieldType, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		tag, ok := field.Tag.Lookup("mongo")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		if err := fn(mongoFieldName(field), tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mongoFieldName 字段在文档中的名称
+func mongoFieldName(field reflect.StructField) string {
+	for _, key := range []string{"db", "bson"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+// defaultMongoIndexName 与 MongoDB 默认索引名一致：字段_值 以下划线连接
+func defaultMongoIndexName(keys bson.D) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", key.Key, key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// mongoCollectionName 模型对应的集合名：实现 TableName() 时使用其返回值，否则按 GORM 的命名规则
+func mongoCollectionName(model interface{}) string {
+	if tabler, ok := model.(schema.Tabler); ok {
+		return tabler.TableName()
+	}
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if tabler, ok := reflect.New(t).Interface().(schema.Tabler); ok {
+		return tabler.TableName()
+	}
+	return schema.NamingStrategy{}.TableName(t.Name())
+}
+
+// mongoExistingIndex 集合中已有的索引
+type mongoExistingIndex struct {
+	Name               string `bson:"name"`
+	Key                bson.D `bson:"key"`
+	Unique             bool   `bson:"unique"`
+	Sparse             bool   `bson:"sparse"`
+	ExpireAfterSeconds *int32 `bson:"expireAfterSeconds"`
+}
+
+// EnsureIndexes 按模型的 mongo 标签创建或更新集合索引，可在每次启动时重复执行：
+// 已存在且定义相同的索引跳过，只有 TTL 不同时通过 collMod 修改，其他定义不同时删除后重建；
+// 模型中没有的索引不会删除。集合名取模型的 TableName()，未实现时按 GORM 的命名规则
+func (m *MongoDB) EnsureIndexes(model interface{}) error {
+	if m.client == nil {
+		return fmt.Errorf("MongoDB客户端未初始化")
+	}
+	indexes, err := MongoIndexes(model)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	collection := mongoCollectionName(model)
+	database := m.client.Database(m.Database)
+	coll := database.Collection(collection)
+	var existing []mongoExistingIndex
+	cursor, err := coll.Indexes().List(ctx)
+	var commandErr mongo.CommandError
+	switch {
+	case errors.As(err, &commandErr) && commandErr.Code == 26:
+		// 集合不存在（NamespaceNotFound），创建索引时自动创建集合
+	case err != nil:
+		return fmt.Errorf("查询集合 %s 的索引失败: %w", collection, err)
+	default:
+		if err := cursor.All(ctx, &existing); err != nil {
+			return fmt.Errorf("读取集合 %s 的索引失败: %w", collection, err)
+		}
+	}
+
+	for _, index := range indexes {
+		current, found := findMongoIndex(existing, index)
+		if found {
+			if sameMongoIndex(current, index, true) {
+				continue
+			}
+			// 只有 TTL 不同时原地修改，避免重建大索引
+			if sameMongoIndex(current, index, false) && current.ExpireAfterSeconds != nil && index.ExpireAfter != nil {
+				command := bson.D{
+					{Key: "collMod", Value: collection},
+					{Key: "index", Value: bson.D{{Key: "name", Value: current.Name}, {Key: "expireAfterSeconds", Value: *index.ExpireAfter}}},
+				}
+				if err := database.RunCommand(ctx, command).Err(); err != nil {
+					return fmt.Errorf("修改索引 %s 的 TTL 失败: %w", current.Name, err)
+				}
+				continue
+			}
+			if _, err := coll.Indexes().DropOne(ctx, current.Name); err != nil {
+				return fmt.Errorf("删除索引 %s 失败: %w", current.Name, err)
+			}
+		}
+
+		opts := options.Index().SetName(index.Name)
+		if index.Unique {
+			opts.SetUnique(true)
+		}
+		if index.Sparse {
+			opts.SetSparse(true)
+		}
+		if index.ExpireAfter != nil {
+			opts.SetExpireAfterSeconds(*index.ExpireAfter)
+		}
+		if _, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: index.Keys, Options: opts}); err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %w", index.Name, err)
+		}
+	}
+	return nil
+}
+
+// findMongoIndex 按索引名或索引键查找已有的索引
+func findMongoIndex(existing []mongoExistingIndex, index MongoIndex) (mongoExistingIndex, bool) {
+	for _, current := range existing {
+		if current.Name == index.Name || (!isMongoTextIndex(index.Keys) && sameMongoKeys(current.Key, index.Keys)) {
+			return current, true
+		}
+	}
+	return mongoExistingIndex{}, false
+}
+
+// sameMongoIndex 比较索引定义，withTTL 为 false 时不比较 TTL
+func sameMongoIndex(current mongoExistingIndex, index MongoIndex, withTTL bool) bool {
+	// 文本索引的键在服务端存储为 _fts/_ftsx，按名称匹配即视为相同
+	if !isMongoTextIndex(index.Keys) && !sameMongoKeys(current.Key, index.Keys) {
+		return false
+	}
+	if current.Unique != index.Unique || current.Sparse != index.Sparse {
+		return false
+	}
+	if !withTTL {
+		return true
+	}
+	if current.ExpireAfterSeconds == nil || index.ExpireAfter == nil {
+		return current.ExpireAfterSeconds == nil && index.ExpireAfter == nil
+	}
+	return *index.ExpireAfter == *current.ExpireAfterSeconds
+}
+
+// sameMongoKeys 比较索引键的字段、顺序和方向
+func sameMongoKeys(a, b bson.D) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || normalizeMongoKeyValue(a[i].Value) != normalizeMongoKeyValue(b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeMongoKeyValue 统一索引键值的数字类型（shell 创建的索引为 double）
+func normalizeMongoKeyValue(value interface{}) string {
+	switch v := value.(type) {
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// isMongoTextIndex 是否为文本索引
+func isMongoTextIndex(keys bson.D) bool {
+	for _, key := range keys {
+		if key.Value == "text" {
+			return true
+		}
+	}
+	return false
+}
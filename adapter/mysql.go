@@ -3,6 +3,7 @@ package adapter
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,6 +22,8 @@ type MySQL struct {
 	MaxOpen     int           // 最大打开连接数
 	MaxLifetime time.Duration // 连接最大生命周期
 	Debug       bool          // 调试模式
+
+	version serverVersion // 服务端版本，由 SetServerVersion 设置
 }
 
 // NewMySQL 创建新的MySQL适配器
@@ -123,6 +126,33 @@ func (m *MySQL) ForShare() string {
 	return "LOCK IN SHARE MODE"
 }
 
+// Capabilities 数据库支持的特性，不支持 RETURNING；公共表表达式、窗口函数和 SKIP LOCKED/NOWAIT 需要 8.0，版本未知时按 8.0 返回
+func (m *MySQL) Capabilities() Capabilities {
+	v8 := m.version.atLeast(8, 0, true)
+	return Capabilities{
+		CTE:              v8,
+		RecursiveCTE:     v8,
+		WindowFunctions:  v8,
+		Upsert:           true,
+		SkipLocked:       v8,
+		NoWait:           v8,
+		Transactions:     true,
+		Savepoints:       true,
+		ReleaseSavepoint: true,
+		MaxBindParams:    60000, // 上限 65535
+	}
+}
+
+// VersionQuery 查询服务端版本的语句
+func (m *MySQL) VersionQuery() string {
+	return "SELECT VERSION()"
+}
+
+// SetServerVersion 设置服务端版本
+func (m *MySQL) SetServerVersion(version string) {
+	m.version = parseServerVersion(version)
+}
+
 // Limit 生成LIMIT语句
 func (m *MySQL) Limit(offset, limit int) string {
 	return fmt.Sprintf("LIMIT %d, %d", offset, limit)
@@ -220,6 +250,82 @@ func (m *MySQL) BatchInsertOrUpdate(db *gorm.DB, table string, columns []string,
 	return db.Exec(sqlStr, flatValues...).Error
 }
 
+// BatchInsertIgnore 批量插入，唯一键冲突的行忽略（INSERT IGNORE），返回实际插入的行数
+func (m *MySQL) BatchInsertIgnore(db *gorm.DB, table string, columns []string, values [][]interface{}) (int64, error) {
+	return mysqlInsertIgnore(db, table, columns, values)
+}
+
+// BatchInsertOnDuplicate 批量插入，唯一键冲突时按 updates 更新（ON DUPLICATE KEY UPDATE），按列名排序生成
+// updates 的值为 gorm.Expr（或同一类型的 query.Raw）时作为表达式，否则作为参数绑定：
+//
+//	m.BatchInsertOnDuplicate(db, "counters", []string{"name", "count"}, values, map[string]interface{}{
+//		"count":      gorm.Expr("count + VALUES(count)"),
+//		"updated_at": gorm.Expr("NOW()"),
+//		"source":     "import",
+//	})
+func (m *MySQL) BatchInsertOnDuplicate(db *gorm.DB, table string, columns []string, values [][]interface{}, updates map[string]interface{}) error {
+	return mysqlInsertOnDuplicate(db, table, columns, values, updates)
+}
+
+// mysqlInsertIgnore MySQL 系（MySQL、MariaDB、TiDB、OceanBase）共用的 INSERT IGNORE 批量插入
+func mysqlInsertIgnore(db *gorm.DB, table string, columns []string, values [][]interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+	placeholders, flatValues := mysqlValues(columns, values)
+	sqlStr := fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES %s", table, strings.Join(columns, ","), placeholders)
+	result := db.Exec(sqlStr, flatValues...)
+	return result.RowsAffected, result.Error
+}
+
+// mysqlInsertOnDuplicate MySQL 系共用的 INSERT ... ON DUPLICATE KEY UPDATE 批量插入
+func mysqlInsertOnDuplicate(db *gorm.DB, table string, columns []string, values [][]interface{}, updates map[string]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("ON DUPLICATE KEY UPDATE 的更新列不能为空")
+	}
+	placeholders, args := mysqlValues(columns, values)
+
+	updateColumns := make([]string, 0, len(updates))
+	for column := range updates {
+		updateColumns = append(updateColumns, column)
+	}
+	sort.Strings(updateColumns)
+	assigns := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		if expr, ok := updates[column].(clause.Expr); ok {
+			assigns = append(assigns, column+" = "+expr.SQL)
+			args = append(args, expr.Vars...)
+			continue
+		}
+		assigns = append(assigns, column+" = ?")
+		args = append(args, updates[column])
+	}
+
+	sqlStr := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+		table,
+		strings.Join(columns, ","),
+		placeholders,
+		strings.Join(assigns, ", "),
+	)
+	return db.Exec(sqlStr, args...).Error
+}
+
+// mysqlValues 生成多行 VALUES 的占位符和展平的参数
+func mysqlValues(columns []string, values [][]interface{}) (string, []interface{}) {
+	placeholder := "(" + strings.TrimRight(strings.Repeat("?,", len(columns)), ",") + ")"
+	placeholders := make([]string, len(values))
+	flatValues := make([]interface{}, 0, len(values)*len(columns))
+	for i, row := range values {
+		placeholders[i] = placeholder
+		flatValues = append(flatValues, row...)
+	}
+	return strings.Join(placeholders, ","), flatValues
+}
+
 // MergeInto 合并插入（UPSERT）- MySQL实现
 func (m *MySQL) MergeInto(db *gorm.DB, table string, columns []string, values [][]interface{}, keyColumns []string, updateColumns []string) error {
 	if len(values) == 0 || len(keyColumns) == 0 {
@@ -339,6 +445,29 @@ func (m *MySQL) GetVersion(db *gorm.DB) (string, error) {
 	return version, err
 }
 
+// GetTableIndexes 获取表的索引信息
+func (m *MySQL) GetTableIndexes(db *gorm.DB, table string) ([]map[string]interface{}, error) {
+	var results []map[string]interface{}
+	err := db.Raw(`
+		SELECT
+			INDEX_NAME AS index_name,
+			INDEX_TYPE AS index_type,
+			GROUP_CONCAT(COLUMN_NAME ORDER BY SEQ_IN_INDEX SEPARATOR ', ') AS column_names,
+			IF(NON_UNIQUE = 0, 1, 0) AS is_unique,
+			IF(INDEX_NAME = 'PRIMARY', 1, 0) AS is_primary
+		FROM
+			information_schema.STATISTICS
+		WHERE
+			TABLE_SCHEMA = DATABASE()
+			AND TABLE_NAME = ?
+		GROUP BY
+			INDEX_NAME, INDEX_TYPE, NON_UNIQUE
+		ORDER BY
+			INDEX_NAME
+	`, table).Scan(&results).Error
+	return results, err
+}
+
 // GetVariables 获取MySQL变量
 func (m *MySQL) GetVariables(db *gorm.DB, pattern string) (map[string]string, error) {
 	var results []struct {
@@ -469,245 +598,5 @@ func (m *MySQL) FlushPrivileges(db *gorm.DB) error {
 
 // QueryPage 分页查询
 func (m *MySQL) QueryPage(dbOption interface{}, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter ...interface{}) (int64, error) {
-	// 参数验证
-	if page <= 0 {
-		page = 1
-	}
-	if pageSize <= 0 {
-		pageSize = 10
-	}
-	if tableName == "" {
-		return 0, fmt.Errorf("表名不能为空")
-	}
-
-	// 从参数中提取 db
-	if dbOption == nil {
-		return 0, fmt.Errorf("缺少必要参数：数据库连接")
-	}
-	db, ok := dbOption.(*gorm.DB)
-	if !ok {
-		return 0, fmt.Errorf("数据库连接参数必须是 *gorm.DB 类型")
-	}
-
-	// 处理查询条件和参数
-	var sqlStr string
-	var values []interface{}
-	var orderClause string
-
-	// 处理排序
-	if len(orderBy) > 0 {
-		var orders []string
-		for _, order := range orderBy {
-			if orderStr, ok := order.(string); ok {
-				orders = append(orders, orderStr)
-			}
-		}
-		if len(orders) > 0 {
-			orderClause = " ORDER BY " + strings.Join(orders, ", ")
-		}
-	}
-
-	// 处理查询条件
-	if len(filter) == 0 {
-		// 没有查询条件，构建基本查询
-		sqlStr = fmt.Sprintf("SELECT * FROM %s", tableName)
-	} else if len(filter) == 1 {
-
-		// 单个查询条件
-		switch f := filter[0].(type) {
-		case nil:
-			sqlStr = fmt.Sprintf("SELECT * FROM %s", tableName)
-		case string:
-			// 如果是SQL字符串
-			sqlStr = f
-			// 提取剩余的参数
-			if len(filter) > 1 {
-				values = append(values, filter[1:]...)
-			}
-		case []interface{}:
-			// 如果是切片，处理第一个元素
-			if len(f) > 0 {
-				if sqlCond, ok := f[0].(string); ok {
-					sqlStr = sqlCond
-					// 提取剩余的参数
-					if len(f) > 1 {
-						values = append(values, f[1:]...)
-					}
-				} else {
-					return 0, fmt.Errorf("切片的第一个元素必须是SQL字符串")
-				}
-			} else {
-				sqlStr = fmt.Sprintf("SELECT * FROM %s", tableName)
-			}
-		case map[string]interface{}:
-			// 如果是条件映射
-			var conditions []string
-			for k, v := range f {
-				conditions = append(conditions, fmt.Sprintf("%s = ?", k))
-				values = append(values, v)
-			}
-
-			baseSQL := fmt.Sprintf("SELECT * FROM %s", tableName)
-			if len(conditions) > 0 {
-				sqlStr = fmt.Sprintf("%s WHERE %s", baseSQL, strings.Join(conditions, " AND "))
-			} else {
-				sqlStr = baseSQL
-			}
-		default:
-			return 0, fmt.Errorf("不支持的查询条件类型")
-		}
-	} else {
-		// 多个查询条件，第一个是SQL字符串，后面的是参数
-		if sqlCond, ok := filter[0].(string); ok {
-			sqlStr = sqlCond
-			values = append(values, filter[1:]...)
-		} else {
-			return 0, fmt.Errorf("多参数查询时，第一个参数必须是SQL字符串")
-		}
-	}
-
-	// 检查 SQL 语句是否包含 SELECT 和 FROM 关键字
-	hasSelect := strings.Contains(strings.ToUpper(sqlStr), "SELECT ")
-	hasFrom := strings.Contains(strings.ToUpper(sqlStr), " FROM ")
-
-	// 如果不是完整的 SQL 查询语句，则将其视为条件表达式
-	if !hasSelect || !hasFrom {
-		// 构建完整的 SQL 查询语句
-		sqlStr = fmt.Sprintf("SELECT * FROM %s WHERE %s", tableName, sqlStr)
-	} else {
-		// 检查 SQL 语句是否包含 WHERE 子句
-		hasWhere := strings.Contains(strings.ToUpper(sqlStr), " WHERE ")
-		hasOrder := strings.Contains(strings.ToUpper(sqlStr), " ORDER BY ")
-
-		// 如果没有 WHERE 子句，但有条件需要添加
-		if !hasWhere {
-			// 查找 FROM 子句后面的位置
-			fromIndex := strings.Index(strings.ToUpper(sqlStr), " FROM ")
-			if fromIndex >= 0 {
-				// 查找可能的子句位置
-				clauseKeywords := []string{" ORDER BY ", " GROUP BY ", " HAVING ", " LIMIT "}
-				insertPos := len(sqlStr)
-
-				for _, keyword := range clauseKeywords {
-					pos := strings.Index(strings.ToUpper(sqlStr), keyword)
-					if pos >= 0 && pos < insertPos {
-						insertPos = pos
-					}
-				}
-
-				// 插入 WHERE 子句
-				sqlStr = sqlStr[:insertPos] + " WHERE 1=1" + sqlStr[insertPos:]
-			}
-		}
-
-		// 如果没有 ORDER BY 子句，但有排序需要添加
-		if !hasOrder && orderClause != "" {
-			// 查找可能的子句位置
-			clauseKeywords := []string{" LIMIT ", " OFFSET "}
-			insertPos := len(sqlStr)
-
-			for _, keyword := range clauseKeywords {
-				pos := strings.Index(strings.ToUpper(sqlStr), keyword)
-				if pos >= 0 && pos < insertPos {
-					insertPos = pos
-				}
-			}
-
-			// 插入 ORDER BY 子句
-			sqlStr = sqlStr[:insertPos] + orderClause + sqlStr[insertPos:]
-		}
-	}
-
-	// 计算偏移量
-	offset := (page - 1) * pageSize
-
-	// 查询总记录数
-	var total int64
-	var countSQL string
-
-	// 检查是否是复杂查询
-	isComplexQuery := false
-
-	// 检查是否包含子查询 - 通过查找括号内包含 SELECT 关键字
-	if strings.Contains(strings.ToUpper(sqlStr), "(SELECT ") ||
-		strings.Contains(strings.ToUpper(sqlStr), "( SELECT ") {
-		isComplexQuery = true
-	}
-
-	// 检查其他复杂查询关键字
-	complexKeywords := []string{
-		" JOIN ", " LEFT JOIN ", " RIGHT JOIN ", " INNER JOIN ", " OUTER JOIN ",
-		" GROUP BY ", " HAVING ", " DISTINCT ", " UNION ", " INTERSECT ", " EXCEPT ",
-	}
-
-	if !isComplexQuery {
-		for _, keyword := range complexKeywords {
-			if strings.Contains(strings.ToUpper(sqlStr), keyword) {
-				isComplexQuery = true
-				break
-			}
-		}
-	}
-
-	// 使用固定别名以避免冲突
-	const countTableAlias = "count_table"
-
-	if !isComplexQuery {
-		// 对于简单查询，直接从表中计数
-		// 提取 FROM 和 WHERE 部分
-		fromIndex := strings.Index(strings.ToUpper(sqlStr), " FROM ")
-		if fromIndex >= 0 {
-			// 获取 FROM 之后的部分
-			fromPart := sqlStr[fromIndex:]
-
-			// 检查 FROM 部分是否包含子查询
-			if strings.Contains(strings.ToUpper(fromPart), "(SELECT ") ||
-				strings.Contains(strings.ToUpper(fromPart), "( SELECT ") {
-				// 如果 FROM 部分包含子查询，使用子查询方式
-				countSQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS %s", sqlStr, countTableAlias)
-			} else {
-				// 否则直接使用 FROM 部分
-				countSQL = "SELECT COUNT(*)" + fromPart
-			}
-		} else {
-			// 如果无法解析，回退到子查询方式
-			countSQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS %s", sqlStr, countTableAlias)
-		}
-	} else {
-		// 对于复杂查询，使用子查询
-		countSQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS %s", sqlStr, countTableAlias)
-	}
-
-	// 移除 ORDER BY 子句以提高计数查询性能
-	orderByIndex := strings.Index(strings.ToUpper(countSQL), " ORDER BY ")
-	if orderByIndex > 0 {
-		// 查找 ORDER BY 后面可能的 LIMIT 或结束位置
-		limitIndex := strings.Index(strings.ToUpper(countSQL[orderByIndex:]), " LIMIT ")
-		if limitIndex > 0 {
-			// 有 LIMIT 子句，移除 ORDER BY 到 LIMIT 之间的部分
-			countSQL = countSQL[:orderByIndex] + countSQL[orderByIndex+limitIndex:]
-		} else {
-			// 没有 LIMIT 子句，直接移除 ORDER BY 到结束
-			countSQL = countSQL[:orderByIndex]
-		}
-	}
-
-	err := db.Raw(countSQL, values...).Count(&total).Error
-	if err != nil {
-		return 0, fmt.Errorf("查询总记录数失败: %w", err)
-	}
-
-	// 如果没有记录，直接返回
-	if total == 0 {
-		return 0, nil
-	}
-
-	// 查询分页数据
-	pageSQL := fmt.Sprintf("%s LIMIT %d OFFSET %d", sqlStr, pageSize, offset)
-	err = db.Raw(pageSQL, values...).Scan(out).Error
-	if err != nil {
-		return 0, fmt.Errorf("查询分页数据失败: %w", err)
-	}
-
-	return total, nil
+	return queryPlannedPage(PageDialect{Syntax: LimitOffset}, dbOption, out, page, pageSize, tableName, orderBy, filter)
 }
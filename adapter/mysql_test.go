@@ -0,0 +1,47 @@
+package adapter
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// mysqlFamilyUpserter MySQL 系适配器共有的冲突处理批量插入
+type mysqlFamilyUpserter interface {
+	BatchInsertIgnore(db *gorm.DB, table string, columns []string, values [][]interface{}) (int64, error)
+	BatchInsertOnDuplicate(db *gorm.DB, table string, columns []string, values [][]interface{}, updates map[string]interface{}) error
+}
+
+// 测试 MySQL 系适配器共用 INSERT IGNORE 与 ON DUPLICATE KEY UPDATE
+func TestMySQLFamilyInsertOnDuplicate(t *testing.T) {
+	for name, a := range map[string]mysqlFamilyUpserter{
+		"mysql": NewMySQL(""), "mariadb": NewMariaDB(""), "tidb": NewTiDB(""), "oceanbase": NewOceanBase(""),
+	} {
+		db, last := dryRun(t, mysql.New(mysql.Config{DSN: "root@tcp(127.0.0.1:1)/test", SkipInitializeWithVersion: true}))
+		columns := []string{"name", "count"}
+		values := [][]interface{}{{"visits", 1}, {"clicks", 2}}
+
+		if _, err := a.BatchInsertIgnore(db, "counters", columns, values); err != nil {
+			t.Fatalf("%s BatchInsertIgnore: %v", name, err)
+		}
+		sqlStr, vars := last()
+		if want := "INSERT IGNORE INTO counters (name,count) VALUES (?,?),(?,?)"; sqlStr != want || len(vars) != 4 {
+			t.Errorf("%s 期望 %q，实际为 %q %v", name, want, sqlStr, vars)
+		}
+
+		updates := map[string]interface{}{"count": gorm.Expr("count + VALUES(count)"), "source": "import", "weight": gorm.Expr("GREATEST(weight, ?)", 5)}
+		if err := a.BatchInsertOnDuplicate(db, "counters", columns, values, updates); err != nil {
+			t.Fatalf("%s BatchInsertOnDuplicate: %v", name, err)
+		}
+		sqlStr, vars = last()
+		want := "INSERT INTO counters (name,count) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE count = count + VALUES(count), source = ?, weight = GREATEST(weight, ?)"
+		if sqlStr != want || !reflect.DeepEqual(vars, []interface{}{"visits", 1, "clicks", 2, "import", 5}) {
+			t.Errorf("%s 期望 %q，实际为 %q %v", name, want, sqlStr, vars)
+		}
+		if err := a.BatchInsertOnDuplicate(db, "counters", columns, values, nil); err == nil {
+			t.Errorf("%s 更新列为空时应返回错误", name)
+		}
+	}
+}
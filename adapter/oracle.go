@@ -3,6 +3,8 @@ package adapter
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -119,6 +121,22 @@ func (o *Oracle) ForShare() string {
 	return "FOR UPDATE NOWAIT"
 }
 
+// Capabilities 数据库支持的特性，RETURNING INTO 只能写入输出参数，不视为返回结果集
+func (o *Oracle) Capabilities() Capabilities {
+	return Capabilities{
+		CTE:             true,
+		RecursiveCTE:    true,
+		WindowFunctions: true,
+		Upsert:          true,
+		SkipLocked:      true,
+		NoWait:          true,
+		Transactions:    true,
+		Savepoints:      true,
+		MaxBindParams:   32000,
+		MaxInList:       1000, // ORA-01795
+	}
+}
+
 // Limit 生成分页语句
 func (o *Oracle) Limit(offset, limit int) string {
 	// Oracle 使用 ROWNUM 或 ROW_NUMBER() 实现分页
@@ -425,9 +443,131 @@ func (o *Oracle) GetProcessList(db *gorm.DB) ([]map[string]interface{}, error) {
 	return results, err
 }
 
-// KillProcess 终止会话
+// KillProcess 终止会话，sid 和 serial 为 V$SESSION 的 SID 和 SERIAL#
 func (o *Oracle) KillProcess(db *gorm.DB, sid int, serial int) error {
-	return db.Exec("ALTER SYSTEM KILL SESSION '??,??'", sid, serial).Error
+	return o.KillSession(db, sid, serial, 0, false)
+}
+
+// KillSession 终止会话，instance 大于 0 时指定 RAC 实例号，immediate 为 true 时立即回滚并释放会话资源
+// ALTER SYSTEM 不支持绑定变量，会话标识只接受非负整数
+func (o *Oracle) KillSession(db *gorm.DB, sid, serial, instance int, immediate bool) error {
+	if sid < 0 || serial < 0 || instance < 0 {
+		return fmt.Errorf("会话标识不合法: %d,%d,@%d", sid, serial, instance)
+	}
+	session := fmt.Sprintf("%d,%d", sid, serial)
+	if instance > 0 {
+		session += fmt.Sprintf(",@%d", instance)
+	}
+	sqlStr := fmt.Sprintf("ALTER SYSTEM KILL SESSION '%s'", session)
+	if immediate {
+		sqlStr += " IMMEDIATE"
+	}
+	return db.Exec(sqlStr).Error
+}
+
+// oracleIdentRegex Oracle 不加引号的标识符
+var oracleIdentRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*$`)
+
+// oracleIdent 校验标识符，ALTER SESSION 不支持绑定变量
+func oracleIdent(kind, name string) error {
+	if !oracleIdentRegex.MatchString(name) {
+		return fmt.Errorf("%s不合法: %q", kind, name)
+	}
+	return nil
+}
+
+// AlterSessionSQL 生成 ALTER SESSION SET 语句，可用于 Config.ConnInit
+// 字符串值作为字符串字面量（单引号转义），整数和浮点数原样写入，布尔值写为 TRUE/FALSE；
+// CURRENT_SCHEMA 的值必须是标识符，不加引号
+// 示例: AlterSessionSQL("NLS_DATE_FORMAT", "YYYY-MM-DD HH24:MI:SS")
+func (o *Oracle) AlterSessionSQL(key string, value interface{}) (string, error) {
+	if err := oracleIdent("会话参数名", key); err != nil {
+		return "", err
+	}
+	key = strings.ToUpper(key)
+	var literal string
+	switch v := value.(type) {
+	case string:
+		if key == "CURRENT_SCHEMA" {
+			if err := oracleIdent("模式名", v); err != nil {
+				return "", err
+			}
+			literal = strings.ToUpper(v)
+		} else {
+			literal = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+	case bool:
+		literal = strings.ToUpper(strconv.FormatBool(v))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		literal = fmt.Sprint(v)
+	default:
+		return "", fmt.Errorf("会话参数 %s 的值类型 %T 不支持", key, value)
+	}
+	return fmt.Sprintf("ALTER SESSION SET %s = %s", key, literal), nil
+}
+
+// AlterSession 修改当前连接的会话参数
+// 连接池中只有执行语句的连接生效，需要所有连接生效时将 AlterSessionSQL 的结果加入 Config.ConnInit，
+// 或在事务、独占连接中使用
+// 示例: AlterSession(tx, "NLS_DATE_FORMAT", "YYYY-MM-DD")
+func (o *Oracle) AlterSession(db *gorm.DB, key string, value interface{}) error {
+	sqlStr, err := o.AlterSessionSQL(key, value)
+	if err != nil {
+		return err
+	}
+	return db.Exec(sqlStr).Error
+}
+
+// SetCurrentSchema 设置当前连接的默认模式，不带模式名的对象按该模式解析
+func (o *Oracle) SetCurrentSchema(db *gorm.DB, schema string) error {
+	return o.AlterSession(db, "CURRENT_SCHEMA", schema)
+}
+
+// CurrentSchema 获取当前连接的默认模式
+func (o *Oracle) CurrentSchema(db *gorm.DB) (string, error) {
+	var result string
+	err := db.Raw("SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL").Scan(&result).Error
+	return result, err
+}
+
+// SessionParameters 获取当前连接的 NLS 会话参数和 CURRENT_SCHEMA，键为大写的参数名
+func (o *Oracle) SessionParameters(db *gorm.DB) (map[string]string, error) {
+	var rows []struct {
+		Parameter string `gorm:"column:PARAMETER"`
+		Value     string `gorm:"column:VALUE"`
+	}
+	err := db.Raw(`
+		SELECT PARAMETER, VALUE FROM NLS_SESSION_PARAMETERS
+		UNION ALL
+		SELECT 'CURRENT_SCHEMA', SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL
+	`).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]string, len(rows))
+	for _, row := range rows {
+		params[strings.ToUpper(row.Parameter)] = row.Value
+	}
+	return params, nil
+}
+
+// SessionParameter 获取当前连接的单个会话参数，NLS 参数和 CURRENT_SCHEMA 之外的参数从 V$PARAMETER 读取（需要查询权限）
+func (o *Oracle) SessionParameter(db *gorm.DB, key string) (string, error) {
+	params, err := o.SessionParameters(db)
+	if err != nil {
+		return "", err
+	}
+	if value, ok := params[strings.ToUpper(key)]; ok {
+		return value, nil
+	}
+	var values []string
+	if err := db.Raw("SELECT VALUE FROM V$PARAMETER WHERE NAME = ?", strings.ToLower(key)).Scan(&values).Error; err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("会话参数 %s 不存在", key)
+	}
+	return values[0], nil
 }
 
 // GetTablespace 获取表空间信息
@@ -517,246 +657,5 @@ func (o *Oracle) RevokePrivileges(db *gorm.DB, privileges string, objects string
 
 // QueryPage 分页查询
 func (o *Oracle) QueryPage(dbOption interface{}, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter ...interface{}) (int64, error) {
-	// 参数验证
-	if page <= 0 {
-		page = 1
-	}
-	if pageSize <= 0 {
-		pageSize = 10
-	}
-	if tableName == "" {
-		return 0, fmt.Errorf("表名不能为空")
-	}
-
-	// 从参数中提取 db
-	if dbOption == nil {
-		return 0, fmt.Errorf("缺少必要参数：数据库连接")
-	}
-	db, ok := dbOption.(*gorm.DB)
-	if !ok {
-		return 0, fmt.Errorf("数据库连接参数必须是 *gorm.DB 类型")
-	}
-
-	// 处理查询条件和参数
-	var sqlStr string
-	var values []interface{}
-	var orderClause string
-
-	// 处理排序
-	if len(orderBy) > 0 {
-		var orders []string
-		for _, order := range orderBy {
-			if orderStr, ok := order.(string); ok {
-				orders = append(orders, orderStr)
-			}
-		}
-		if len(orders) > 0 {
-			orderClause = " ORDER BY " + strings.Join(orders, ", ")
-		}
-	}
-
-	// 处理查询条件
-	if len(filter) == 0 {
-		// 没有查询条件，构建基本查询
-		sqlStr = fmt.Sprintf("SELECT * FROM %s", tableName)
-	} else if len(filter) == 1 {
-		// 单个查询条件
-		switch f := filter[0].(type) {
-		case nil:
-			sqlStr = fmt.Sprintf("SELECT * FROM %s", tableName)
-		case string:
-			// 如果是SQL字符串
-			sqlStr = f
-			// 提取剩余的参数
-			if len(filter) > 1 {
-				values = append(values, filter[1:]...)
-			}
-		case []interface{}:
-			// 如果是切片，处理第一个元素
-			if len(f) > 0 {
-				if sqlCond, ok := f[0].(string); ok {
-					sqlStr = sqlCond
-					// 提取剩余的参数
-					if len(f) > 1 {
-						values = append(values, f[1:]...)
-					}
-				} else {
-					return 0, fmt.Errorf("切片的第一个元素必须是SQL字符串")
-				}
-			} else {
-				sqlStr = fmt.Sprintf("SELECT * FROM %s", tableName)
-			}
-		case map[string]interface{}:
-			// 如果是条件映射
-			var conditions []string
-			for k, v := range f {
-				// Oracle 使用 :n 形式的参数占位符
-				conditions = append(conditions, fmt.Sprintf("%s = :%d", k, len(values)+1))
-				values = append(values, v)
-			}
-
-			baseSQL := fmt.Sprintf("SELECT * FROM %s", tableName)
-			if len(conditions) > 0 {
-				sqlStr = fmt.Sprintf("%s WHERE %s", baseSQL, strings.Join(conditions, " AND "))
-			} else {
-				sqlStr = baseSQL
-			}
-		default:
-			return 0, fmt.Errorf("不支持的查询条件类型")
-		}
-	} else {
-		// 多个查询条件，第一个是SQL字符串，后面的是参数
-		if sqlCond, ok := filter[0].(string); ok {
-			sqlStr = sqlCond
-			values = append(values, filter[1:]...)
-		} else {
-			return 0, fmt.Errorf("多参数查询时，第一个参数必须是SQL字符串")
-		}
-	}
-
-	// 检查 SQL 语句是否包含 SELECT 和 FROM 关键字
-	hasSelect := strings.Contains(strings.ToUpper(sqlStr), "SELECT ")
-	hasFrom := strings.Contains(strings.ToUpper(sqlStr), " FROM ")
-
-	// 如果不是完整的 SQL 查询语句，则将其视为条件表达式
-	if !hasSelect || !hasFrom {
-		// 构建完整的 SQL 查询语句
-		sqlStr = fmt.Sprintf("SELECT * FROM %s WHERE %s", tableName, sqlStr)
-	} else {
-		// 检查 SQL 语句是否包含 WHERE 子句
-		hasWhere := strings.Contains(strings.ToUpper(sqlStr), " WHERE ")
-		hasOrder := strings.Contains(strings.ToUpper(sqlStr), " ORDER BY ")
-
-		// 如果没有 WHERE 子句，但有条件需要添加
-		if !hasWhere {
-			// 查找 FROM 子句后面的位置
-			fromIndex := strings.Index(strings.ToUpper(sqlStr), " FROM ")
-			if fromIndex >= 0 {
-				// 查找可能的子句位置
-				clauseKeywords := []string{" ORDER BY ", " GROUP BY ", " HAVING ", " LIMIT "}
-				insertPos := len(sqlStr)
-
-				for _, keyword := range clauseKeywords {
-					pos := strings.Index(strings.ToUpper(sqlStr), keyword)
-					if pos >= 0 && pos < insertPos {
-						insertPos = pos
-					}
-				}
-
-				// 插入 WHERE 子句
-				sqlStr = sqlStr[:insertPos] + " WHERE 1=1" + sqlStr[insertPos:]
-			}
-		}
-
-		// 如果没有 ORDER BY 子句，但有排序需要添加
-		if !hasOrder && orderClause != "" {
-			// 查找可能的子句位置
-			clauseKeywords := []string{" OFFSET ", " FETCH "}
-			insertPos := len(sqlStr)
-
-			for _, keyword := range clauseKeywords {
-				pos := strings.Index(strings.ToUpper(sqlStr), keyword)
-				if pos >= 0 && pos < insertPos {
-					insertPos = pos
-				}
-			}
-
-			// 插入 ORDER BY 子句
-			sqlStr = sqlStr[:insertPos] + orderClause + sqlStr[insertPos:]
-		}
-	}
-
-	// 计算偏移量
-	offset := (page - 1) * pageSize
-
-	// 查询总记录数
-	var total int64
-	var countSQL string
-
-	// 检查是否是复杂查询
-	isComplexQuery := false
-
-	// 检查是否包含子查询 - 通过查找括号内包含 SELECT 关键字
-	if strings.Contains(strings.ToUpper(sqlStr), "(SELECT ") ||
-		strings.Contains(strings.ToUpper(sqlStr), "( SELECT ") {
-		isComplexQuery = true
-	}
-
-	// 检查其他复杂查询关键字
-	complexKeywords := []string{
-		" JOIN ", " LEFT JOIN ", " RIGHT JOIN ", " INNER JOIN ", " OUTER JOIN ",
-		" GROUP BY ", " HAVING ", " DISTINCT ", " UNION ", " INTERSECT ", " EXCEPT ",
-	}
-
-	if !isComplexQuery {
-		for _, keyword := range complexKeywords {
-			if strings.Contains(strings.ToUpper(sqlStr), keyword) {
-				isComplexQuery = true
-				break
-			}
-		}
-	}
-
-	// 使用固定别名以避免冲突
-	const countTableAlias = "count_table"
-
-	if !isComplexQuery {
-		// 对于简单查询，直接从表中计数
-		// 提取 FROM 和 WHERE 部分
-		fromIndex := strings.Index(strings.ToUpper(sqlStr), " FROM ")
-		if fromIndex >= 0 {
-			// 获取 FROM 之后的部分
-			fromPart := sqlStr[fromIndex:]
-
-			// 检查 FROM 部分是否包含子查询
-			if strings.Contains(strings.ToUpper(fromPart), "(SELECT ") ||
-				strings.Contains(strings.ToUpper(fromPart), "( SELECT ") {
-				// 如果 FROM 部分包含子查询，使用子查询方式
-				countSQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) %s", sqlStr, countTableAlias)
-			} else {
-				// 否则直接使用 FROM 部分
-				countSQL = "SELECT COUNT(*)" + fromPart
-			}
-		} else {
-			// 如果无法解析，回退到子查询方式
-			countSQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) %s", sqlStr, countTableAlias)
-		}
-	} else {
-		// 对于复杂查询，使用子查询
-		countSQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) %s", sqlStr, countTableAlias)
-	}
-
-	// 移除 ORDER BY 子句以提高计数查询性能
-	orderByIndex := strings.Index(strings.ToUpper(countSQL), " ORDER BY ")
-	if orderByIndex > 0 {
-		// 查找 ORDER BY 后面可能的 OFFSET 或结束位置
-		offsetIndex := strings.Index(strings.ToUpper(countSQL[orderByIndex:]), " OFFSET ")
-		if offsetIndex > 0 {
-			// 有 OFFSET 子句，移除 ORDER BY 到 OFFSET 之间的部分
-			countSQL = countSQL[:orderByIndex] + countSQL[orderByIndex+offsetIndex:]
-		} else {
-			// 没有 OFFSET 子句，直接移除 ORDER BY 到结束
-			countSQL = countSQL[:orderByIndex]
-		}
-	}
-
-	err := db.Raw(countSQL, values...).Count(&total).Error
-	if err != nil {
-		return 0, fmt.Errorf("查询总记录数失败: %w", err)
-	}
-
-	// 如果没有记录，直接返回
-	if total == 0 {
-		return 0, nil
-	}
-
-	// 查询分页数据
-	// Oracle 12c 及以上版本使用 OFFSET ... ROWS FETCH NEXT ... ROWS ONLY 语法
-	pageSQL := fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", sqlStr, offset, pageSize)
-	err = db.Raw(pageSQL, values...).Scan(out).Error
-	if err != nil {
-		return 0, fmt.Errorf("查询分页数据失败: %w", err)
-	}
-
-	return total, nil
+	return queryPlannedPage(PageDialect{Syntax: OffsetFetch, NoAliasAS: true}, dbOption, out, page, pageSize, tableName, orderBy, filter)
 }
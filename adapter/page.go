@@ -0,0 +1,99 @@
+package adapter
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// PageOptions 分页查询选项，可代替 *gorm.DB 作为 QueryPage 的 dbOption 传入
+type PageOptions struct {
+	DB           *gorm.DB // 数据库连接
+	WithoutTotal bool     // 不查询总数，多取一行判断是否有下一页，QueryPage 返回的总数为 -1
+	HasMore      bool     // 查询后设置：WithoutTotal 时是否还有下一页
+	// StrictOrder SQLServer 分页缺少排序时返回 ErrPageOrderRequired，默认使用 ORDER BY (SELECT NULL)
+	StrictOrder bool
+}
+
+// pageDB 从 dbOption 中取出数据库连接和分页选项，dbOption 为 *gorm.DB 或 *PageOptions
+func pageDB(dbOption interface{}) (*gorm.DB, *PageOptions, error) {
+	switch option := dbOption.(type) {
+	case nil:
+		return nil, nil, fmt.Errorf("缺少必要参数：数据库连接")
+	case *gorm.DB:
+		return option, &PageOptions{DB: option}, nil
+	case *PageOptions:
+		if option.DB == nil {
+			return nil, nil, fmt.Errorf("缺少必要参数：数据库连接")
+		}
+		return option.DB, option, nil
+	}
+	return nil, nil, fmt.Errorf("数据库连接参数必须是 *gorm.DB 类型")
+}
+
+// Sequential 判断 db 上的多条语句是否只能顺序执行：事务只有一个连接，试运行不执行语句
+func Sequential(db *gorm.DB) bool {
+	_, inTx := db.Statement.ConnPool.(gorm.TxCommitter)
+	return inTx || db.DryRun
+}
+
+// RunPage 执行分页的计数和数据查询，count 返回总数，find 按 limit 查询当前页
+// 不在事务中时两条语句在不同的连接上并发执行；WithoutTotal 时不计数，查询 pageSize+1 行，
+// 多出的一行用于设置 HasMore 后从 out 中去掉，返回的总数为 -1
+func RunPage(db *gorm.DB, options *PageOptions, out interface{}, pageSize int, count func(db *gorm.DB) (int64, error), find func(db *gorm.DB, limit int) error) (int64, error) {
+	if options != nil && options.WithoutTotal {
+		if err := find(db, pageSize+1); err != nil {
+			return 0, fmt.Errorf("查询分页数据失败: %w", err)
+		}
+		options.HasMore = trimPage(out, pageSize)
+		return -1, nil
+	}
+
+	if Sequential(db) {
+		total, err := count(db)
+		if err != nil {
+			return 0, fmt.Errorf("查询总记录数失败: %w", err)
+		}
+		if total == 0 {
+			return 0, nil
+		}
+		if err := find(db, pageSize); err != nil {
+			return 0, fmt.Errorf("查询分页数据失败: %w", err)
+		}
+		return total, nil
+	}
+
+	var (
+		total    int64
+		countErr error
+		done     = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		total, countErr = count(db.Session(&gorm.Session{}))
+	}()
+	findErr := find(db.Session(&gorm.Session{}), pageSize)
+	<-done
+	if countErr != nil {
+		return 0, fmt.Errorf("查询总记录数失败: %w", countErr)
+	}
+	if findErr != nil {
+		return 0, fmt.Errorf("查询分页数据失败: %w", findErr)
+	}
+	return total, nil
+}
+
+// trimPage 结果超过 pageSize 行时去掉多出的行，返回是否有多出的行
+func trimPage(out interface{}, pageSize int) bool {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return false
+	}
+	slice := value.Elem()
+	if slice.Len() <= pageSize {
+		return false
+	}
+	slice.Set(slice.Slice(0, pageSize))
+	return true
+}
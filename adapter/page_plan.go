@@ -0,0 +1,280 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrPageOrderRequired 分页语法要求 ORDER BY 但查询没有排序（PageDialect.StrictOrder 时返回）
+var ErrPageOrderRequired = errors.New("分页查询缺少排序，OFFSET ... FETCH 要求 ORDER BY")
+
+// ErrPageLimitPresent 查询已包含顶层 LIMIT、OFFSET 或 FETCH，不能再追加分页子句
+var ErrPageLimitPresent = errors.New("分页查询已包含 LIMIT/OFFSET/FETCH 子句")
+
+// PageSyntax 分页子句语法
+type PageSyntax int
+
+const (
+	LimitOffset PageSyntax = iota // LIMIT ? OFFSET ?（MySQL、PostgreSQL、SQLite 等）
+	OffsetFetch                   // OFFSET ? ROWS FETCH NEXT ? ROWS ONLY（SQLServer、Oracle 12c+）
+)
+
+// PageDialect 分页查询的方言差异
+type PageDialect struct {
+	Syntax       PageSyntax
+	RequireOrder bool // 分页子句必须跟在 ORDER BY 之后（SQLServer）
+	StrictOrder  bool // RequireOrder 时缺少排序返回 ErrPageOrderRequired，否则使用 ORDER BY (SELECT NULL)
+	NoAliasAS    bool // 派生表别名前不能写 AS（Oracle）
+}
+
+// PagePlan 分页查询计划，由 PlanPage 生成
+type PagePlan struct {
+	Query    string        // 不含分页子句的数据查询
+	CountSQL string        // 计数查询
+	Args     []interface{} // 数据查询和计数查询共用的参数
+	syntax   PageSyntax
+}
+
+// nativeBindVar 查询中已有的驱动原生占位符（@p1、$1、:1），此时分页参数不能再用 ? 绑定
+var nativeBindVar = regexp.MustCompile(`(@p|\$|:)\d+\b`)
+
+// complexPageKeywords 出现这些关键字时计数查询使用子查询包装
+var complexPageKeywords = []string{"JOIN", "GROUP BY", "HAVING", "DISTINCT", "UNION", "INTERSECT", "EXCEPT"}
+
+// PlanPage 按 QueryPage 的参数生成数据查询和计数查询，filter 支持：
+//
+//	空或 nil：查询整表
+//	"age > ?", 18：条件表达式及其参数
+//	"SELECT ... FROM ...", args...：完整查询
+//	[]interface{}{"age > ?", 18}：条件或完整查询及其参数
+//	map[string]interface{}{"status": 1}：等值条件（按键排序）
+//
+// orderBy 中的字符串在查询没有顶层 ORDER BY 时追加；计数查询去掉顶层 ORDER BY；
+// 查询已有顶层 LIMIT、OFFSET 或 FETCH 时返回 ErrPageLimitPresent
+func PlanPage(d PageDialect, tableName string, orderBy []interface{}, filter ...interface{}) (*PagePlan, error) {
+	if tableName == "" {
+		return nil, fmt.Errorf("表名不能为空")
+	}
+	query, args, err := pageFilter(tableName, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 不是完整的查询语句时视为条件表达式
+	if findKeyword(query, "SELECT") < 0 || findKeyword(query, "FROM") < 0 {
+		query = fmt.Sprintf("SELECT * FROM %s WHERE %s", tableName, query)
+	}
+
+	// 分页子句追加在查询末尾，已有分页的查询无法再分页
+	if findKeyword(query, "LIMIT", "OFFSET", "FETCH") >= 0 {
+		return nil, ErrPageLimitPresent
+	}
+
+	// 追加排序
+	var orders []string
+	for _, order := range orderBy {
+		if orderStr, ok := order.(string); ok && strings.TrimSpace(orderStr) != "" {
+			orders = append(orders, orderStr)
+		}
+	}
+	ordered := findKeyword(query, "ORDER BY") >= 0
+	if !ordered && len(orders) > 0 {
+		query = strings.TrimRight(query, " \t\r\n") + " ORDER BY " + strings.Join(orders, ", ")
+		ordered = true
+	}
+
+	// 计数查询去掉顶层排序
+	body := query
+	if pos := findKeyword(body, "ORDER BY"); pos >= 0 {
+		body = strings.TrimRight(body[:pos], " \t\r\n")
+	}
+	alias := " AS count_table"
+	if d.NoAliasAS {
+		alias = " count_table"
+	}
+	countSQL := "SELECT COUNT(*) FROM (" + body + ")" + alias
+	if from := findKeyword(body, "FROM"); from >= 0 && !complexPageQuery(body) {
+		countSQL = "SELECT COUNT(*) " + body[from:]
+	}
+
+	if d.RequireOrder && !ordered {
+		if d.StrictOrder {
+			return nil, ErrPageOrderRequired
+		}
+		query += " ORDER BY (SELECT NULL)"
+	}
+	return &PagePlan{Query: query, CountSQL: countSQL, Args: args, syntax: d.Syntax}, nil
+}
+
+// PageSQL 返回从第 offset 行开始取 limit 行的查询和参数，偏移量和行数作为参数绑定；
+// 查询中已有驱动原生占位符时写为整数字面量，避免与 ? 混用
+func (p *PagePlan) PageSQL(offset, limit int) (string, []interface{}) {
+	args := append([]interface{}{}, p.Args...)
+	if nativeBindVar.MatchString(p.Query) {
+		if p.syntax == OffsetFetch {
+			return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", p.Query, offset, limit), args
+		}
+		return fmt.Sprintf("%s LIMIT %d OFFSET %d", p.Query, limit, offset), args
+	}
+	if p.syntax == OffsetFetch {
+		return p.Query + " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", append(args, offset, limit)
+	}
+	return p.Query + " LIMIT ? OFFSET ?", append(args, limit, offset)
+}
+
+// queryPlannedPage 按分页计划执行 QueryPage：计数和数据查询在不在事务中时并发执行
+func queryPlannedPage(d PageDialect, dbOption interface{}, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter []interface{}) (int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if tableName == "" {
+		return 0, fmt.Errorf("表名不能为空")
+	}
+	db, options, err := pageDB(dbOption)
+	if err != nil {
+		return 0, err
+	}
+	d.StrictOrder = d.StrictOrder || options.StrictOrder
+	plan, err := PlanPage(d, tableName, orderBy, filter...)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	return RunPage(db, options, out, pageSize, func(db *gorm.DB) (int64, error) {
+		var total int64
+		err := db.Raw(plan.CountSQL, plan.Args...).Count(&total).Error
+		return total, err
+	}, func(db *gorm.DB, limit int) error {
+		sqlStr, args := plan.PageSQL(offset, limit)
+		return db.Raw(sqlStr, args...).Scan(out).Error
+	})
+}
+
+// pageFilter 解析 QueryPage 的 filter 参数为查询（或条件）和参数
+func pageFilter(tableName string, filter []interface{}) (string, []interface{}, error) {
+	base := fmt.Sprintf("SELECT * FROM %s", tableName)
+	if len(filter) == 0 {
+		return base, nil, nil
+	}
+	if len(filter) > 1 {
+		sqlCond, ok := filter[0].(string)
+		if !ok {
+			return "", nil, fmt.Errorf("多参数查询时，第一个参数必须是SQL字符串")
+		}
+		return sqlCond, filter[1:], nil
+	}
+
+	switch f := filter[0].(type) {
+	case nil:
+		return base, nil, nil
+	case string:
+		return f, nil, nil
+	case []interface{}:
+		if len(f) == 0 {
+			return base, nil, nil
+		}
+		sqlCond, ok := f[0].(string)
+		if !ok {
+			return "", nil, fmt.Errorf("切片的第一个元素必须是SQL字符串")
+		}
+		return sqlCond, f[1:], nil
+	case map[string]interface{}:
+		if len(f) == 0 {
+			return base, nil, nil
+		}
+		keys := make([]string, 0, len(f))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		conditions := make([]string, len(keys))
+		args := make([]interface{}, len(keys))
+		for i, k := range keys {
+			conditions[i] = k + " = ?"
+			args[i] = f[k]
+		}
+		return base + " WHERE " + strings.Join(conditions, " AND "), args, nil
+	}
+	return "", nil, fmt.Errorf("不支持的查询条件类型")
+}
+
+// complexPageQuery 查询包含连接、分组、去重、集合运算或子查询时，计数需要包装为子查询
+func complexPageQuery(query string) bool {
+	if findKeyword(query, complexPageKeywords...) >= 0 {
+		return true
+	}
+	upper := strings.ToUpper(query)
+	return strings.Contains(upper, "(SELECT ") || strings.Contains(upper, "( SELECT ")
+}
+
+// findKeyword 查找括号、引号之外第一个出现的关键字，返回其位置，不存在时返回-1
+// 关键字按单词匹配且不区分大小写，多个单词之间可以是任意空白
+func findKeyword(query string, keywords ...string) int {
+	var (
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			quote = c
+			if c == '[' {
+				quote = ']'
+			}
+			continue
+		case c == '(':
+			depth++
+			continue
+		case c == ')':
+			depth--
+			continue
+		}
+		if depth != 0 || (i > 0 && isWordByte(query[i-1])) {
+			continue
+		}
+		for _, keyword := range keywords {
+			if matchKeyword(query[i:], keyword) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchKeyword 判断 s 是否以关键字开头，关键字中的空格匹配任意空白
+func matchKeyword(s, keyword string) bool {
+	for n, word := range strings.Fields(keyword) {
+		if n > 0 {
+			trimmed := strings.TrimLeft(s, " \t\r\n")
+			if len(trimmed) == len(s) {
+				return false
+			}
+			s = trimmed
+		}
+		if len(s) < len(word) || !strings.EqualFold(s[:len(word)], word) {
+			return false
+		}
+		s = s[len(word):]
+	}
+	return s == "" || !isWordByte(s[0])
+}
+
+// isWordByte 是否为标识符字符
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
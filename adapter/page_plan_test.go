@@ -0,0 +1,79 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// 测试分页查询计划：排序校验、参数化 OFFSET/FETCH 和计数查询
+func TestPlanPage(t *testing.T) {
+	sqlserver := PageDialect{Syntax: OffsetFetch, RequireOrder: true}
+
+	plan, err := PlanPage(sqlserver, "users", []interface{}{"id DESC"}, "age > ?", 18)
+	if err != nil {
+		t.Fatalf("PlanPage() error = %v", err)
+	}
+	sqlStr, args := plan.PageSQL(20, 10)
+	if sqlStr != "SELECT * FROM users WHERE age > ? ORDER BY id DESC OFFSET ? ROWS FETCH NEXT ? ROWS ONLY" || fmt.Sprint(args) != "[18 20 10]" {
+		t.Errorf("PageSQL() = %q, %v", sqlStr, args)
+	}
+	if plan.CountSQL != "SELECT COUNT(*) FROM users WHERE age > ?" {
+		t.Errorf("CountSQL = %q", plan.CountSQL)
+	}
+
+	// 没有排序时默认使用 ORDER BY (SELECT NULL)，StrictOrder 时返回错误
+	plan, err = PlanPage(sqlserver, "users", nil, map[string]interface{}{"status": 1, "active": true})
+	if err != nil || plan.Query != "SELECT * FROM users WHERE active = ? AND status = ? ORDER BY (SELECT NULL)" {
+		t.Errorf("Query = %q, %v", plan.Query, err)
+	}
+	strict := sqlserver
+	strict.StrictOrder = true
+	if _, err := PlanPage(strict, "users", nil); !errors.Is(err, ErrPageOrderRequired) {
+		t.Errorf("缺少排序时 err = %v", err)
+	}
+
+	// 已有分页子句的查询不能再分页，子查询中的 LIMIT/FETCH 不受影响
+	for _, query := range []string{
+		"SELECT * FROM users ORDER BY id LIMIT 5",
+		"SELECT * FROM users ORDER BY id OFFSET 10 ROWS",
+		"SELECT * FROM users ORDER BY id FETCH FIRST 5 ROWS ONLY",
+	} {
+		if _, err := PlanPage(PageDialect{Syntax: LimitOffset}, "users", []interface{}{"id"}, query); !errors.Is(err, ErrPageLimitPresent) {
+			t.Errorf("%q err = %v", query, err)
+		}
+	}
+	nested := "SELECT * FROM users WHERE id IN (SELECT user_id FROM articles ORDER BY id LIMIT 5)"
+	if plan, err := PlanPage(PageDialect{Syntax: LimitOffset}, "users", []interface{}{"id"}, nested); err != nil || plan.Query != nested+" ORDER BY id" {
+		t.Errorf("子查询分页: %v", err)
+	}
+
+	// 子查询中的 ORDER BY 不算作外层排序，计数查询只去掉外层排序
+	full := "SELECT u.id, COUNT(a.id) AS n FROM users u LEFT JOIN (SELECT * FROM articles ORDER BY id) a ON a.user_id = u.id GROUP BY u.id"
+	plan, err = PlanPage(PageDialect{Syntax: OffsetFetch, NoAliasAS: true}, "users", []interface{}{"u.id"}, full)
+	if err != nil {
+		t.Fatalf("PlanPage() error = %v", err)
+	}
+	if plan.Query != full+" ORDER BY u.id" || plan.CountSQL != "SELECT COUNT(*) FROM ("+full+") count_table" {
+		t.Errorf("Query = %q, CountSQL = %q", plan.Query, plan.CountSQL)
+	}
+
+	// 查询中已有原生占位符时分页值写为整数
+	plan, err = PlanPage(PageDialect{Syntax: LimitOffset}, "users", nil, "SELECT * FROM users WHERE id > $1 ORDER BY id", 5)
+	if err != nil {
+		t.Fatalf("PlanPage() error = %v", err)
+	}
+	if sqlStr, args := plan.PageSQL(0, 10); sqlStr != "SELECT * FROM users WHERE id > $1 ORDER BY id LIMIT 10 OFFSET 0" || len(args) != 1 {
+		t.Errorf("PageSQL() = %q, %v", sqlStr, args)
+	}
+	if plan.CountSQL != "SELECT COUNT(*) FROM users WHERE id > $1" {
+		t.Errorf("CountSQL = %q", plan.CountSQL)
+	}
+
+	if _, err := PlanPage(sqlserver, "", nil); err == nil {
+		t.Error("表名为空应返回错误")
+	}
+	if _, err := PlanPage(sqlserver, "users", nil, 20, 1); err == nil {
+		t.Error("多参数时第一个参数不是字符串应返回错误")
+	}
+}
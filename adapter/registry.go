@@ -0,0 +1,84 @@
+package adapter
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options 创建适配器使用的连接配置
+type Options struct {
+	DSN         string        // 数据源名称
+	MaxIdle     int           // 最大空闲连接数
+	MaxOpen     int           // 最大打开连接数
+	MaxLifetime time.Duration // 连接最大生命周期
+	Debug       bool          // 调试模式
+}
+
+// AdapterFactory 适配器工厂
+type AdapterFactory func(opts Options) Adapter
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]AdapterFactory{
+		"mysql": func(o Options) Adapter {
+			return NewMySQL(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"postgres": func(o Options) Adapter {
+			return NewPostgres(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"sqlserver": func(o Options) Adapter {
+			return NewSQLServer(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"sqlite3": func(o Options) Adapter {
+			return NewSQLite(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"oracle": func(o Options) Adapter {
+			return NewOracle(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"tidb": func(o Options) Adapter {
+			return NewTiDB(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"mariadb": func(o Options) Adapter {
+			return NewMariaDB(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"clickhouse": func(o Options) Adapter {
+			return NewClickHouse(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"oceanbase": func(o Options) Adapter {
+			return NewOceanBase(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+		"duckdb": func(o Options) Adapter {
+			return NewDuckDB(o.DSN).WithMaxIdle(o.MaxIdle).WithMaxOpen(o.MaxOpen).WithMaxLifetime(o.MaxLifetime).WithDebug(o.Debug)
+		},
+	}
+)
+
+// Register 注册适配器，name 与 gosqlx.DatabaseType 一致（不区分大小写），同名时覆盖已有适配器
+// 示例: adapter.Register("db2", func(o adapter.Options) adapter.Adapter { return NewDB2(o.DSN) })
+func Register(name string, factory AdapterFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = factory
+}
+
+// Lookup 获取已注册的适配器工厂
+func Lookup(name string) (AdapterFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[strings.ToLower(name)]
+	return factory, ok
+}
+
+// Registered 返回已注册的适配器名称（已排序）
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,121 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// sqliteBackupPages 在线备份每步复制的页数，步与步之间释放源库的读锁，写入不会被长时间阻塞
+const sqliteBackupPages = 1024
+
+// BackupTo 通过 SQLite 在线备份接口将数据库复制到 path，备份期间其他连接可以继续读写（写入后备份从头继续）
+// 先写入同目录下的临时文件，完成后重命名为 path，失败时不会留下不完整的备份；path 已存在时被覆盖
+// 备份占用一个连接，单连接的内存库不能在事务中调用
+func (s *SQLite) BackupTo(db *gorm.DB, path string) error {
+	if path == "" {
+		return fmt.Errorf("备份文件路径不能为空")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建备份文件失败: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		src, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("连接不是 SQLite 连接: %T", driverConn)
+		}
+		return sqliteBackup(ctx, src, tmpPath)
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("保存备份文件失败: %w", err)
+	}
+	return nil
+}
+
+// sqliteBackup 将 src 的 main 库分步复制到文件 dest
+func sqliteBackup(ctx context.Context, src *sqlite3.SQLiteConn, dest string) error {
+	destConn, err := (&sqlite3.SQLiteDriver{}).Open(dest)
+	if err != nil {
+		return fmt.Errorf("打开备份文件失败: %w", err)
+	}
+	defer destConn.Close()
+
+	backup, err := destConn.(*sqlite3.SQLiteConn).Backup("main", src, "main")
+	if err != nil {
+		return fmt.Errorf("开始备份失败: %w", err)
+	}
+	remaining := -1
+	for {
+		done, err := backup.Step(sqliteBackupPages)
+		if err != nil {
+			backup.Close()
+			return fmt.Errorf("备份失败: %w", err)
+		}
+		if done {
+			break
+		}
+		// 源库被锁定时本步没有进展，稍后重试
+		if backup.Remaining() == remaining {
+			select {
+			case <-ctx.Done():
+				backup.Close()
+				return ctx.Err()
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		remaining = backup.Remaining()
+	}
+	if err := backup.Finish(); err != nil {
+		return fmt.Errorf("完成备份失败: %w", err)
+	}
+	return nil
+}
+
+// IntegrityCheck 执行 PRAGMA integrity_check 检查数据库文件，数据库完好时返回空，否则返回发现的问题（最多 100 条）
+// quick 为 true 时执行 quick_check，不检查索引与表数据是否一致，大库上快得多
+func (s *SQLite) IntegrityCheck(db *gorm.DB, quick bool) ([]string, error) {
+	pragma := "integrity_check"
+	if quick {
+		pragma = "quick_check"
+	}
+	var results []string
+	if err := db.Raw("PRAGMA " + pragma).Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("执行 %s 失败: %w", pragma, err)
+	}
+	if len(results) == 1 && results[0] == "ok" {
+		return nil, nil
+	}
+	return results, nil
+}
+
+// Optimize 执行 PRAGMA optimize，按需更新查询优化器的统计信息，建议在关闭连接前或定期执行
+func (s *SQLite) Optimize(db *gorm.DB) error {
+	return db.Exec("PRAGMA optimize").Error
+}
@@ -0,0 +1,43 @@
+package adapter
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// VersionAware 特性随服务端版本变化的适配器，NewDatabase 连接后执行 VersionQuery 并将结果传给 SetServerVersion，
+// 之后 Capabilities 按该版本返回；未设置版本时按各适配器注释中的假定版本返回
+type VersionAware interface {
+	// VersionQuery 查询服务端版本的语句
+	VersionQuery() string
+	// SetServerVersion 设置服务端版本，如 8.0.36、10.6.12-MariaDB-log
+	SetServerVersion(version string)
+}
+
+// serverVersion 服务端版本号，known 为 false 表示未知
+type serverVersion struct {
+	major, minor int
+	known        bool
+}
+
+// leadingVersion 版本字符串中的第一个 主版本.次版本
+var leadingVersion = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// parseServerVersion 提取版本字符串中的主次版本号，无法识别时返回未知版本
+func parseServerVersion(version string) serverVersion {
+	match := leadingVersion.FindStringSubmatch(version)
+	if match == nil {
+		return serverVersion{}
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return serverVersion{major: major, minor: minor, known: true}
+}
+
+// atLeast 判断版本是否不低于 major.minor，版本未知时返回 assumed
+func (v serverVersion) atLeast(major, minor int, assumed bool) bool {
+	if !v.known {
+		return assumed
+	}
+	return v.major > major || v.major == major && v.minor >= minor
+}
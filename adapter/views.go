@@ -0,0 +1,528 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+)
+
+// ==================== 视图与触发器 ====================
+
+// ErrTriggerUnsupported 数据库不支持触发器（TiDB、ClickHouse、DuckDB、MongoDB）
+var ErrTriggerUnsupported = errors.New("数据库不支持触发器")
+
+// Trigger 行级触发器定义
+// Body 为每行执行的语句，按各数据库的语法书写，多条语句以分号分隔，适配器负责包上 BEGIN ... END：
+// MySQL 系、PostgreSQL 和 SQLite 通过 NEW.列名/OLD.列名 引用行，Oracle 为 :NEW.列名/:OLD.列名，
+// SQLServer 没有行级触发器，按语句触发，通过 inserted/deleted 伪表引用受影响的行
+//
+//	adapter.Trigger{
+//		Name:   "orders_audit",
+//		Table:  "orders",
+//		Timing: "AFTER",
+//		Events: []string{"UPDATE"},
+//		Body:   "INSERT INTO order_audit (order_id, status) VALUES (NEW.id, NEW.status)",
+//	}
+type Trigger struct {
+	Name   string   // 触发器名
+	Table  string   // 表名，INSTEAD OF 触发器为视图名
+	Timing string   // 触发时机：BEFORE、AFTER 或 INSTEAD OF（SQLServer 只支持 AFTER 和 INSTEAD OF）
+	Events []string // 触发事件：INSERT、UPDATE、DELETE，MySQL 系和 SQLite 只能指定一个
+	Body   string   // 执行的语句
+}
+
+// triggerParts 校验触发器定义，返回大写的触发时机和事件
+func triggerParts(trigger Trigger, timings ...string) (string, []string, error) {
+	if trigger.Name == "" || trigger.Table == "" || strings.TrimSpace(trigger.Body) == "" {
+		return "", nil, fmt.Errorf("触发器 %q 的名称、表名和语句不能为空", trigger.Name)
+	}
+	timing := strings.Join(strings.Fields(strings.ToUpper(trigger.Timing)), " ")
+	valid := false
+	for _, t := range timings {
+		valid = valid || t == timing
+	}
+	if !valid {
+		return "", nil, fmt.Errorf("触发器 %s 的触发时机 %q 不支持，可选值为 %s", trigger.Name, trigger.Timing, strings.Join(timings, "、"))
+	}
+	if len(trigger.Events) == 0 {
+		return "", nil, fmt.Errorf("触发器 %s 未指定触发事件", trigger.Name)
+	}
+	events := make([]string, len(trigger.Events))
+	for i, event := range trigger.Events {
+		events[i] = strings.ToUpper(strings.TrimSpace(event))
+		switch events[i] {
+		case "INSERT", "UPDATE", "DELETE":
+		default:
+			return "", nil, fmt.Errorf("触发器 %s 的触发事件 %q 不支持", trigger.Name, event)
+		}
+	}
+	return timing, events, nil
+}
+
+// triggerBody 去掉语句首尾的空白，并以分号结尾
+func triggerBody(body string) string {
+	return strings.TrimRight(strings.TrimSpace(body), "; \t\r\n") + ";"
+}
+
+// singleEvent MySQL 系和 SQLite 的触发器只能指定一个事件
+func singleEvent(trigger Trigger, events []string) (string, error) {
+	if len(events) != 1 {
+		return "", fmt.Errorf("触发器 %s 只能指定一个触发事件，实际为 %v", trigger.Name, trigger.Events)
+	}
+	return events[0], nil
+}
+
+// createOrReplaceView 执行 CREATE OR REPLACE VIEW
+func createOrReplaceView(db *gorm.DB, name, query string) error {
+	if err := db.Exec(fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", db.Statement.Quote(name), query)).Error; err != nil {
+		return fmt.Errorf("创建视图 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// dropView 执行 DROP VIEW IF EXISTS
+func dropView(db *gorm.DB, name string) error {
+	if err := db.Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", db.Statement.Quote(name))).Error; err != nil {
+		return fmt.Errorf("删除视图 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// listViews 执行返回视图名的查询
+func listViews(db *gorm.DB, query string) ([]string, error) {
+	var views []string
+	if err := db.Raw(query).Scan(&views).Error; err != nil {
+		return nil, fmt.Errorf("查询视图失败: %w", err)
+	}
+	return views, nil
+}
+
+// ==================== MySQL 系 ====================
+
+// mysqlListViewsSQL MySQL 系从 information_schema.views 读取当前库的视图
+const mysqlListViewsSQL = "SELECT table_name FROM information_schema.views WHERE table_schema = DATABASE() ORDER BY table_name"
+
+// mysqlCreateTrigger 先删除同名触发器再创建，两条语句分别执行，不要求开启 multiStatements
+func mysqlCreateTrigger(db *gorm.DB, trigger Trigger) error {
+	timing, events, err := triggerParts(trigger, "BEFORE", "AFTER")
+	if err != nil {
+		return err
+	}
+	event, err := singleEvent(trigger, events)
+	if err != nil {
+		return err
+	}
+	if err := mysqlDropTrigger(db, trigger.Name); err != nil {
+		return err
+	}
+	sqlStr := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW BEGIN %s END",
+		db.Statement.Quote(trigger.Name), timing, event, db.Statement.Quote(trigger.Table), triggerBody(trigger.Body))
+	if err := db.Exec(sqlStr).Error; err != nil {
+		return fmt.Errorf("创建触发器 %s 失败: %w", trigger.Name, err)
+	}
+	return nil
+}
+
+// mysqlDropTrigger 执行 DROP TRIGGER IF EXISTS，MySQL 的触发器名在库内唯一，不需要表名
+func mysqlDropTrigger(db *gorm.DB, name string) error {
+	if err := db.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s", db.Statement.Quote(name))).Error; err != nil {
+		return fmt.Errorf("删除触发器 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW
+func (m *MySQL) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (m *MySQL) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 information_schema.views 读取当前库的视图
+func (m *MySQL) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, mysqlListViewsSQL)
+}
+
+// CreateTrigger 删除同名触发器后创建，只能指定一个事件，时机为 BEFORE 或 AFTER
+func (m *MySQL) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return mysqlCreateTrigger(db, trigger)
+}
+
+// DropTrigger 执行 DROP TRIGGER IF EXISTS
+func (m *MySQL) DropTrigger(db *gorm.DB, table, name string) error {
+	return mysqlDropTrigger(db, name)
+}
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW
+func (m *MariaDB) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (m *MariaDB) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 information_schema.views 读取当前库的视图
+func (m *MariaDB) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, mysqlListViewsSQL)
+}
+
+// CreateTrigger 删除同名触发器后创建，只能指定一个事件，时机为 BEFORE 或 AFTER
+func (m *MariaDB) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return mysqlCreateTrigger(db, trigger)
+}
+
+// DropTrigger 执行 DROP TRIGGER IF EXISTS
+func (m *MariaDB) DropTrigger(db *gorm.DB, table, name string) error {
+	return mysqlDropTrigger(db, name)
+}
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW
+func (t *TiDB) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (t *TiDB) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 information_schema.views 读取当前库的视图
+func (t *TiDB) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, mysqlListViewsSQL)
+}
+
+// CreateTrigger TiDB 不支持触发器，返回 ErrTriggerUnsupported
+func (t *TiDB) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return ErrTriggerUnsupported
+}
+
+// DropTrigger TiDB 不支持触发器，返回 ErrTriggerUnsupported
+func (t *TiDB) DropTrigger(db *gorm.DB, table, name string) error {
+	return ErrTriggerUnsupported
+}
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW
+func (o *OceanBase) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (o *OceanBase) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 information_schema.views 读取当前库的视图
+func (o *OceanBase) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, mysqlListViewsSQL)
+}
+
+// CreateTrigger 删除同名触发器后创建，只能指定一个事件，时机为 BEFORE 或 AFTER
+func (o *OceanBase) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return mysqlCreateTrigger(db, trigger)
+}
+
+// DropTrigger 执行 DROP TRIGGER IF EXISTS
+func (o *OceanBase) DropTrigger(db *gorm.DB, table, name string) error {
+	return mysqlDropTrigger(db, name)
+}
+
+// ==================== PostgreSQL ====================
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW，新定义只能在原有列之后追加列，删除或修改列需要先 DropView
+func (p *Postgres) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (p *Postgres) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 information_schema.views 读取当前模式的视图
+func (p *Postgres) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, "SELECT table_name FROM information_schema.views WHERE table_schema = current_schema() ORDER BY table_name")
+}
+
+// CreateTrigger 将 Body 包装为触发器函数 <触发器名>_fn（末尾返回 NEW，DELETE 时返回 OLD），再替换同名触发器
+// 三条语句在同一次 Exec 中执行（简单查询协议下为同一个事务）
+func (p *Postgres) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	timing, events, err := triggerParts(trigger, "BEFORE", "AFTER", "INSTEAD OF")
+	if err != nil {
+		return err
+	}
+	name, table, function := db.Statement.Quote(trigger.Name), db.Statement.Quote(trigger.Table), db.Statement.Quote(trigger.Name+"_fn")
+	sqlStr := fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $gosqlx$ BEGIN %s RETURN COALESCE(NEW, OLD); END $gosqlx$; "+
+		"DROP TRIGGER IF EXISTS %s ON %s; "+
+		"CREATE TRIGGER %s %s %s ON %s FOR EACH ROW EXECUTE FUNCTION %s()",
+		function, triggerBody(trigger.Body), name, table, name, timing, strings.Join(events, " OR "), table, function)
+	if err := db.Exec(sqlStr).Error; err != nil {
+		return fmt.Errorf("创建触发器 %s 失败: %w", trigger.Name, err)
+	}
+	return nil
+}
+
+// DropTrigger 删除触发器和 CreateTrigger 创建的触发器函数
+func (p *Postgres) DropTrigger(db *gorm.DB, table, name string) error {
+	sqlStr := fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s; DROP FUNCTION IF EXISTS %s()",
+		db.Statement.Quote(name), db.Statement.Quote(table), db.Statement.Quote(name+"_fn"))
+	if err := db.Exec(sqlStr).Error; err != nil {
+		return fmt.Errorf("删除触发器 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// ==================== SQLServer ====================
+
+// CreateOrReplaceView 执行 CREATE OR ALTER VIEW（SQL Server 2016 SP1 及以上）
+func (s *SQLServer) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	if err := db.Exec(fmt.Sprintf("CREATE OR ALTER VIEW %s AS %s", db.Statement.Quote(name), query)).Error; err != nil {
+		return fmt.Errorf("创建视图 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// DropView 执行 DROP VIEW IF EXISTS（SQL Server 2016 及以上）
+func (s *SQLServer) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 INFORMATION_SCHEMA.VIEWS 读取视图
+func (s *SQLServer) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.VIEWS ORDER BY TABLE_NAME")
+}
+
+// CreateTrigger 执行 CREATE OR ALTER TRIGGER，时机为 AFTER 或 INSTEAD OF，按语句触发，Body 通过 inserted/deleted 引用受影响的行
+func (s *SQLServer) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	timing, events, err := triggerParts(trigger, "AFTER", "INSTEAD OF")
+	if err != nil {
+		return err
+	}
+	sqlStr := fmt.Sprintf("CREATE OR ALTER TRIGGER %s ON %s %s %s AS BEGIN SET NOCOUNT ON; %s END",
+		db.Statement.Quote(trigger.Name), db.Statement.Quote(trigger.Table), timing, strings.Join(events, ", "), triggerBody(trigger.Body))
+	if err := db.Exec(sqlStr).Error; err != nil {
+		return fmt.Errorf("创建触发器 %s 失败: %w", trigger.Name, err)
+	}
+	return nil
+}
+
+// DropTrigger 执行 DROP TRIGGER IF EXISTS，DML 触发器名在模式内唯一，不需要表名
+func (s *SQLServer) DropTrigger(db *gorm.DB, table, name string) error {
+	if err := db.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s", db.Statement.Quote(name))).Error; err != nil {
+		return fmt.Errorf("删除触发器 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// ==================== Oracle ====================
+
+// oracleObjectName 校验不加引号的对象名，允许 模式名.对象名
+func oracleObjectName(kind, name string) error {
+	for _, part := range strings.Split(name, ".") {
+		if err := oracleIdent(kind, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oracleDropIgnoring 执行 DROP 语句，对象不存在（错误码 code）时忽略
+func oracleDropIgnoring(db *gorm.DB, statement string, code int) error {
+	return db.Exec(fmt.Sprintf("BEGIN EXECUTE IMMEDIATE '%s'; EXCEPTION WHEN OTHERS THEN IF SQLCODE != %d THEN RAISE; END IF; END;", statement, code)).Error
+}
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW，视图名不加引号，按 Oracle 的规则转为大写
+func (o *Oracle) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	if err := oracleObjectName("视图名", name); err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", name, query)).Error; err != nil {
+		return fmt.Errorf("创建视图 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// DropView 删除视图，忽略 ORA-00942（视图不存在）
+func (o *Oracle) DropView(db *gorm.DB, name string) error {
+	if err := oracleObjectName("视图名", name); err != nil {
+		return err
+	}
+	if err := oracleDropIgnoring(db, "DROP VIEW "+name, -942); err != nil {
+		return fmt.Errorf("删除视图 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// ListViews 从 USER_VIEWS 读取当前用户的视图
+func (o *Oracle) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, "SELECT VIEW_NAME FROM USER_VIEWS ORDER BY VIEW_NAME")
+}
+
+// CreateTrigger 执行 CREATE OR REPLACE TRIGGER，Body 通过 :NEW/:OLD 引用行
+func (o *Oracle) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	timing, events, err := triggerParts(trigger, "BEFORE", "AFTER", "INSTEAD OF")
+	if err != nil {
+		return err
+	}
+	if err := oracleObjectName("触发器名", trigger.Name); err != nil {
+		return err
+	}
+	if err := oracleObjectName("表名", trigger.Table); err != nil {
+		return err
+	}
+	sqlStr := fmt.Sprintf("CREATE OR REPLACE TRIGGER %s %s %s ON %s FOR EACH ROW BEGIN %s END;",
+		trigger.Name, timing, strings.Join(events, " OR "), trigger.Table, triggerBody(trigger.Body))
+	if err := db.Exec(sqlStr).Error; err != nil {
+		return fmt.Errorf("创建触发器 %s 失败: %w", trigger.Name, err)
+	}
+	return nil
+}
+
+// DropTrigger 删除触发器，忽略 ORA-04080（触发器不存在）
+func (o *Oracle) DropTrigger(db *gorm.DB, table, name string) error {
+	if err := oracleObjectName("触发器名", name); err != nil {
+		return err
+	}
+	if err := oracleDropIgnoring(db, "DROP TRIGGER "+name, -4080); err != nil {
+		return fmt.Errorf("删除触发器 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// ==================== SQLite ====================
+
+// CreateOrReplaceView SQLite 没有 CREATE OR REPLACE VIEW，先删除同名视图再创建
+func (s *SQLite) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	if err := dropView(db, name); err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("CREATE VIEW %s AS %s", db.Statement.Quote(name), query)).Error; err != nil {
+		return fmt.Errorf("创建视图 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (s *SQLite) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 sqlite_master 读取视图
+func (s *SQLite) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, "SELECT name FROM sqlite_master WHERE type = 'view' ORDER BY name")
+}
+
+// CreateTrigger 删除同名触发器后创建，只能指定一个事件
+func (s *SQLite) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	timing, events, err := triggerParts(trigger, "BEFORE", "AFTER", "INSTEAD OF")
+	if err != nil {
+		return err
+	}
+	event, err := singleEvent(trigger, events)
+	if err != nil {
+		return err
+	}
+	if err := s.DropTrigger(db, trigger.Table, trigger.Name); err != nil {
+		return err
+	}
+	sqlStr := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW BEGIN %s END",
+		db.Statement.Quote(trigger.Name), timing, event, db.Statement.Quote(trigger.Table), triggerBody(trigger.Body))
+	if err := db.Exec(sqlStr).Error; err != nil {
+		return fmt.Errorf("创建触发器 %s 失败: %w", trigger.Name, err)
+	}
+	return nil
+}
+
+// DropTrigger 执行 DROP TRIGGER IF EXISTS
+func (s *SQLite) DropTrigger(db *gorm.DB, table, name string) error {
+	if err := db.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s", db.Statement.Quote(name))).Error; err != nil {
+		return fmt.Errorf("删除触发器 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// ==================== ClickHouse、DuckDB ====================
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW
+func (c *ClickHouse) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (c *ClickHouse) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 system.tables 读取当前库的普通视图和物化视图
+func (c *ClickHouse) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, "SELECT name FROM system.tables WHERE database = currentDatabase() AND engine IN ('View', 'MaterializedView') ORDER BY name")
+}
+
+// CreateTrigger ClickHouse 不支持触发器，返回 ErrTriggerUnsupported，可使用物化视图在写入时处理数据
+func (c *ClickHouse) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return ErrTriggerUnsupported
+}
+
+// DropTrigger ClickHouse 不支持触发器，返回 ErrTriggerUnsupported
+func (c *ClickHouse) DropTrigger(db *gorm.DB, table, name string) error {
+	return ErrTriggerUnsupported
+}
+
+// CreateOrReplaceView 执行 CREATE OR REPLACE VIEW
+func (d *DuckDB) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return createOrReplaceView(db, name, query)
+}
+
+// DropView 执行 DROP VIEW IF EXISTS
+func (d *DuckDB) DropView(db *gorm.DB, name string) error {
+	return dropView(db, name)
+}
+
+// ListViews 从 duckdb_views() 读取当前模式中用户创建的视图
+func (d *DuckDB) ListViews(db *gorm.DB) ([]string, error) {
+	return listViews(db, "SELECT view_name FROM duckdb_views() WHERE NOT internal AND schema_name = current_schema() ORDER BY view_name")
+}
+
+// CreateTrigger DuckDB 不支持触发器，返回 ErrTriggerUnsupported
+func (d *DuckDB) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return ErrTriggerUnsupported
+}
+
+// DropTrigger DuckDB 不支持触发器，返回 ErrTriggerUnsupported
+func (d *DuckDB) DropTrigger(db *gorm.DB, table, name string) error {
+	return ErrTriggerUnsupported
+}
+
+// ==================== MongoDB ====================
+
+// CreateOrReplaceView MongoDB 的视图由源集合和聚合管道定义，不支持通过 SQL 创建
+func (m *MongoDB) CreateOrReplaceView(db *gorm.DB, name, query string) error {
+	return fmt.Errorf("MongoDB适配器不支持通过SQL创建视图，请使用GetDatabase().CreateView方法")
+}
+
+// DropView 删除视图
+func (m *MongoDB) DropView(db *gorm.DB, name string) error {
+	return m.DropCollection(name)
+}
+
+// ListViews 列出类型为 view 的集合
+func (m *MongoDB) ListViews(db *gorm.DB) ([]string, error) {
+	return m.ListCollections(bson.D{{Key: "type", Value: "view"}})
+}
+
+// CreateTrigger MongoDB 不支持触发器，返回 ErrTriggerUnsupported，可使用 Change Streams 监听变更
+func (m *MongoDB) CreateTrigger(db *gorm.DB, trigger Trigger) error {
+	return ErrTriggerUnsupported
+}
+
+// DropTrigger MongoDB 不支持触发器，返回 ErrTriggerUnsupported
+func (m *MongoDB) DropTrigger(db *gorm.DB, table, name string) error {
+	return ErrTriggerUnsupported
+}
@@ -0,0 +1,423 @@
+package advisor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gzorm/gosqlx/dialect"
+	"gorm.io/gorm"
+)
+
+// IndexProvider 可提供表索引信息的适配器
+// Postgres/Oracle/SQLServer/MySQL/SQLite 适配器均实现了该接口
+type IndexProvider interface {
+	GetTableIndexes(db *gorm.DB, table string) ([]map[string]interface{}, error)
+}
+
+// Shape 查询形态（去除参数值后的结构信息）
+type Shape struct {
+	Fingerprint string              // 归一化后的SQL
+	Table       string              // 主表
+	Equality    map[string][]string // 等值条件列（表 -> 列）
+	Range       map[string][]string // 范围条件列（表 -> 列）
+	Join        map[string][]string // 连接列（表 -> 列）
+	Order       map[string][]string // 排序列（表 -> 列）
+	Count       int                 // 执行次数
+	Total       time.Duration       // 累计耗时
+}
+
+// Suggestion 索引建议
+type Suggestion struct {
+	Table   string   // 表名
+	Columns []string // 建议的索引列（按顺序）
+	Reason  string   // 建议原因
+	Benefit float64  // 预估收益（受益查询的累计耗时，毫秒）
+	Queries int      // 受益的查询形态数
+	SQL     string   // 创建索引的DDL
+}
+
+// Advisor 索引顾问
+type Advisor struct {
+	dialect   dialect.Dialect
+	threshold time.Duration
+	mutex     sync.Mutex
+	shapes    map[string]*Shape
+}
+
+// NewAdvisor 创建索引顾问
+// dialectName: 数据库类型，用于生成DDL
+// threshold: 慢查询阈值，低于该耗时的查询不记录（0表示全部记录）
+func NewAdvisor(dialectName string, threshold time.Duration) *Advisor {
+	return &Advisor{
+		dialect:   dialect.GetDialect(dialectName),
+		threshold: threshold,
+		shapes:    make(map[string]*Shape),
+	}
+}
+
+// Record 记录一次查询
+func (a *Advisor) Record(sql string, duration time.Duration) {
+	if duration < a.threshold {
+		return
+	}
+
+	fingerprint := Fingerprint(sql)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	shape, ok := a.shapes[fingerprint]
+	if !ok {
+		shape = ParseShape(sql)
+		if shape == nil {
+			return
+		}
+		a.shapes[fingerprint] = shape
+	}
+	shape.Count++
+	shape.Total += duration
+}
+
+// Attach 注册GORM回调，自动记录查询耗时
+func (a *Advisor) Attach(db *gorm.DB) error {
+	const startKey = "gosqlx:advisor_start"
+
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(tx *gorm.DB) {
+		if value, ok := tx.InstanceGet(startKey); ok {
+			if start, ok := value.(time.Time); ok {
+				a.Record(tx.Statement.SQL.String(), time.Since(start))
+			}
+		}
+	}
+
+	if err := db.Callback().Query().Before("gorm:query").Register("gosqlx:advisor_before_query", before); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("gosqlx:advisor_after_query", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("gorm:raw").Register("gosqlx:advisor_before_raw", before); err != nil {
+		return err
+	}
+	return db.Callback().Raw().After("gorm:raw").Register("gosqlx:advisor_after_raw", after)
+}
+
+// Shapes 获取已记录的查询形态
+func (a *Advisor) Shapes() []*Shape {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	shapes := make([]*Shape, 0, len(a.shapes))
+	for _, shape := range a.shapes {
+		shapes = append(shapes, shape)
+	}
+	sort.Slice(shapes, func(i, j int) bool { return shapes[i].Total > shapes[j].Total })
+	return shapes
+}
+
+// Reset 清空记录
+func (a *Advisor) Reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.shapes = make(map[string]*Shape)
+}
+
+// Suggest 根据记录的查询形态与现有索引给出索引建议
+func (a *Advisor) Suggest(db *gorm.DB, provider IndexProvider) ([]Suggestion, error) {
+	existing := make(map[string][][]string)
+	candidates := make(map[string]*Suggestion)
+
+	for _, shape := range a.Shapes() {
+		for table, columns := range shape.candidates() {
+			if _, ok := existing[table]; !ok {
+				indexes, err := loadIndexes(db, provider, table)
+				if err != nil {
+					return nil, fmt.Errorf("获取表(%s)索引失败: %w", table, err)
+				}
+				existing[table] = indexes
+			}
+
+			for _, candidate := range columns {
+				if covered(existing[table], candidate.columns) {
+					continue
+				}
+
+				key := table + "(" + strings.Join(candidate.columns, ",") + ")"
+				suggestion, ok := candidates[key]
+				if !ok {
+					suggestion = &Suggestion{
+						Table:   table,
+						Columns: candidate.columns,
+						Reason:  candidate.reason,
+						SQL:     a.indexSQL(table, candidate.columns),
+					}
+					candidates[key] = suggestion
+				}
+				suggestion.Queries++
+				suggestion.Benefit += float64(shape.Total) / float64(time.Millisecond)
+			}
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for _, suggestion := range candidates {
+		suggestions = append(suggestions, *suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Benefit != suggestions[j].Benefit {
+			return suggestions[i].Benefit > suggestions[j].Benefit
+		}
+		return suggestions[i].Table+strings.Join(suggestions[i].Columns, ",") <
+			suggestions[j].Table+strings.Join(suggestions[j].Columns, ",")
+	})
+	return suggestions, nil
+}
+
+// indexSQL 生成创建索引的DDL
+func (a *Advisor) indexSQL(table string, columns []string) string {
+	// 通过接口调用 Quote 以使用各方言自身的引号规则
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = a.dialect.Quote(column)
+	}
+	name := fmt.Sprintf("idx_%s_%s", table, strings.Join(columns, "_"))
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", a.dialect.Quote(name), a.dialect.Quote(table), strings.Join(quoted, ", "))
+}
+
+// candidate 候选索引
+type candidate struct {
+	columns []string
+	reason  string
+}
+
+// candidates 根据查询形态生成候选索引：等值列在前，范围列随后，最后是排序列
+func (s *Shape) candidates() map[string][]candidate {
+	result := make(map[string][]candidate)
+
+	tables := make(map[string]bool)
+	for _, group := range []map[string][]string{s.Equality, s.Range, s.Order} {
+		for table := range group {
+			tables[table] = true
+		}
+	}
+
+	for table := range tables {
+		var columns []string
+		columns = appendUnique(columns, s.Equality[table]...)
+		var reasons []string
+		if len(s.Equality[table]) > 0 {
+			reasons = append(reasons, "WHERE等值")
+		}
+		if len(s.Range[table]) > 0 {
+			// 复合索引中只有第一个范围列能使用索引
+			columns = appendUnique(columns, s.Range[table][0])
+			reasons = append(reasons, "WHERE范围")
+		} else if len(s.Order[table]) > 0 {
+			columns = appendUnique(columns, s.Order[table]...)
+			reasons = append(reasons, "ORDER BY")
+		}
+		if len(columns) > 0 {
+			result[table] = append(result[table], candidate{columns: columns, reason: strings.Join(reasons, "+")})
+		}
+	}
+
+	for table, columns := range s.Join {
+		for _, column := range columns {
+			result[table] = append(result[table], candidate{columns: []string{column}, reason: "JOIN"})
+		}
+	}
+	return result
+}
+
+// loadIndexes 读取表现有索引的列
+func loadIndexes(db *gorm.DB, provider IndexProvider, table string) ([][]string, error) {
+	rows, err := provider.GetTableIndexes(db, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes [][]string
+	for _, row := range rows {
+		for key, value := range row {
+			switch strings.ToLower(key) {
+			case "column_names", "columnnames", "columns":
+				var columns []string
+				for _, column := range strings.Split(fmt.Sprintf("%v", value), ",") {
+					if column = strings.ToLower(strings.TrimSpace(column)); column != "" {
+						columns = append(columns, column)
+					}
+				}
+				indexes = append(indexes, columns)
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// covered 判断候选列是否已被现有索引的最左前缀覆盖
+func covered(indexes [][]string, columns []string) bool {
+	for _, index := range indexes {
+		if len(index) < len(columns) {
+			continue
+		}
+		match := true
+		for i, column := range columns {
+			if index[i] != strings.ToLower(column) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique 追加不重复的元素
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		exists := false
+		for _, v := range list {
+			if v == item {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+var (
+	literalRegex   = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b|\$\d+|@p\d+|:\d+`)
+	inListRegex    = regexp.MustCompile(`(?i)\bIN\s*\((?:\s*\?\s*,?)+\)`)
+	spaceRegex     = regexp.MustCompile(`\s+`)
+	fromRegex      = regexp.MustCompile(`(?i)\bFROM\s+([\w."\[\]` + "`" + `]+)(?:\s+(?:AS\s+)?(\w+))?`)
+	joinRegex      = regexp.MustCompile(`(?i)\bJOIN\s+([\w."\[\]` + "`" + `]+)(?:\s+(?:AS\s+)?(\w+))?\s+ON\s+(.+?)(?:\s+(?:LEFT|RIGHT|INNER|OUTER|CROSS|FULL)?\s*JOIN\b|\s+WHERE\b|\s+GROUP\s+BY\b|\s+ORDER\s+BY\b|\s+LIMIT\b|$)`)
+	whereRegex     = regexp.MustCompile(`(?i)\bWHERE\s+(.+?)(?:\s+GROUP\s+BY\b|\s+ORDER\s+BY\b|\s+LIMIT\b|\s+OFFSET\b|\s+FOR\s+UPDATE\b|$)`)
+	orderRegex     = regexp.MustCompile(`(?i)\bORDER\s+BY\s+(.+?)(?:\s+LIMIT\b|\s+OFFSET\b|\s+FETCH\b|\s+FOR\s+UPDATE\b|$)`)
+	equalityRegex  = regexp.MustCompile(`(?i)([\w.]+)\s*(?:=|\bIN\b|\bIS\s+NULL\b)\s*`)
+	rangeRegex     = regexp.MustCompile(`(?i)([\w.]+)\s*(?:<=|>=|<|>|\bBETWEEN\b|\bLIKE\b)`)
+	joinEqualRegex = regexp.MustCompile(`([\w.]+)\s*=\s*([\w.]+)`)
+	identRegex     = regexp.MustCompile(`^[A-Za-z_][\w.]*$`)
+)
+
+// Fingerprint 归一化SQL：替换字面量与占位符、合并IN列表、压缩空白
+func Fingerprint(sql string) string {
+	normalized := literalRegex.ReplaceAllString(sql, "?")
+	normalized = inListRegex.ReplaceAllString(normalized, "IN (?)")
+	normalized = spaceRegex.ReplaceAllString(strings.TrimSpace(normalized), " ")
+	return strings.ToLower(normalized)
+}
+
+// ParseShape 解析SELECT语句的查询形态，非SELECT语句返回nil
+func ParseShape(sql string) *Shape {
+	normalized := spaceRegex.ReplaceAllString(strings.TrimSpace(literalRegex.ReplaceAllString(sql, "?")), " ")
+	if !strings.HasPrefix(strings.ToUpper(normalized), "SELECT") {
+		return nil
+	}
+
+	shape := &Shape{
+		Fingerprint: Fingerprint(sql),
+		Equality:    make(map[string][]string),
+		Range:       make(map[string][]string),
+		Join:        make(map[string][]string),
+		Order:       make(map[string][]string),
+	}
+
+	// 别名 -> 表名
+	aliases := make(map[string]string)
+	from := fromRegex.FindStringSubmatch(normalized)
+	if from == nil {
+		return nil
+	}
+	shape.Table = cleanIdentifier(from[1])
+	aliases[strings.ToLower(shape.Table)] = shape.Table
+	if alias := from[2]; alias != "" && !isKeyword(alias) {
+		aliases[strings.ToLower(alias)] = shape.Table
+	}
+
+	for _, join := range joinRegex.FindAllStringSubmatch(normalized, -1) {
+		table := cleanIdentifier(join[1])
+		aliases[strings.ToLower(table)] = table
+		if alias := join[2]; alias != "" && !isKeyword(alias) {
+			aliases[strings.ToLower(alias)] = table
+		}
+	}
+
+	resolve := func(column string) (string, string, bool) {
+		column = cleanIdentifier(column)
+		if !identRegex.MatchString(column) || isKeyword(column) {
+			return "", "", false
+		}
+		if dot := strings.LastIndex(column, "."); dot >= 0 {
+			table, ok := aliases[strings.ToLower(column[:dot])]
+			if !ok {
+				return "", "", false
+			}
+			return table, column[dot+1:], true
+		}
+		return shape.Table, column, true
+	}
+
+	for _, join := range joinRegex.FindAllStringSubmatch(normalized, -1) {
+		for _, pair := range joinEqualRegex.FindAllStringSubmatch(join[3], -1) {
+			for _, side := range pair[1:] {
+				if table, column, ok := resolve(side); ok {
+					shape.Join[table] = appendUnique(shape.Join[table], column)
+				}
+			}
+		}
+	}
+
+	if where := whereRegex.FindStringSubmatch(normalized); where != nil {
+		for _, match := range equalityRegex.FindAllStringSubmatch(where[1], -1) {
+			if table, column, ok := resolve(match[1]); ok {
+				shape.Equality[table] = appendUnique(shape.Equality[table], column)
+			}
+		}
+		for _, match := range rangeRegex.FindAllStringSubmatch(where[1], -1) {
+			if table, column, ok := resolve(match[1]); ok {
+				shape.Range[table] = appendUnique(shape.Range[table], column)
+			}
+		}
+	}
+
+	if order := orderRegex.FindStringSubmatch(normalized); order != nil {
+		for _, part := range strings.Split(order[1], ",") {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+			if table, column, ok := resolve(fields[0]); ok {
+				shape.Order[table] = appendUnique(shape.Order[table], column)
+			}
+		}
+	}
+
+	return shape
+}
+
+// cleanIdentifier 去除标识符引号
+func cleanIdentifier(name string) string {
+	return strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(strings.TrimSpace(name))
+}
+
+// isKeyword 判断是否为SQL关键字
+func isKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "WHERE", "JOIN", "LEFT", "RIGHT", "INNER", "OUTER", "CROSS", "FULL", "ON", "GROUP", "ORDER",
+		"LIMIT", "OFFSET", "AND", "OR", "NOT", "NULL", "IS", "IN", "LIKE", "BETWEEN", "AS", "FOR":
+		return true
+	}
+	return false
+}
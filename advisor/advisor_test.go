@@ -0,0 +1,88 @@
+package advisor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 测试SQL归一化
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'tom'")
+	b := Fingerprint("select *  from users where id in (?, ?) and name = ?")
+	if a != b {
+		t.Errorf("期望归一化结果相同，实际为 %q 和 %q", a, b)
+	}
+}
+
+// 测试查询形态解析
+func TestParseShape(t *testing.T) {
+	shape := ParseShape("SELECT u.id, a.title FROM users u LEFT JOIN articles a ON a.user_id = u.id " +
+		"WHERE u.status = ? AND u.age >= ? ORDER BY u.created_at DESC LIMIT 10")
+	if shape == nil {
+		t.Fatal("解析查询形态失败")
+	}
+	if shape.Table != "users" {
+		t.Errorf("期望主表为 users，实际为 %s", shape.Table)
+	}
+	if !reflect.DeepEqual(shape.Equality["users"], []string{"status"}) {
+		t.Errorf("等值列不符合预期: %v", shape.Equality)
+	}
+	if !reflect.DeepEqual(shape.Range["users"], []string{"age"}) {
+		t.Errorf("范围列不符合预期: %v", shape.Range)
+	}
+	if !reflect.DeepEqual(shape.Join["articles"], []string{"user_id"}) {
+		t.Errorf("连接列不符合预期: %v", shape.Join)
+	}
+	if !reflect.DeepEqual(shape.Order["users"], []string{"created_at"}) {
+		t.Errorf("排序列不符合预期: %v", shape.Order)
+	}
+
+	if ParseShape("UPDATE users SET name = ? WHERE id = ?") != nil {
+		t.Error("非SELECT语句应返回nil")
+	}
+}
+
+// fakeProvider 测试用索引提供者
+type fakeProvider map[string][]map[string]interface{}
+
+func (p fakeProvider) GetTableIndexes(db *gorm.DB, table string) ([]map[string]interface{}, error) {
+	return p[table], nil
+}
+
+// 测试索引建议
+func TestSuggest(t *testing.T) {
+	a := NewAdvisor("mysql", 10*time.Millisecond)
+	a.Record("SELECT * FROM users WHERE status = 1 AND age > 18", 200*time.Millisecond)
+	a.Record("SELECT * FROM users WHERE status = 2 AND age > 30", 100*time.Millisecond)
+	a.Record("SELECT * FROM users WHERE email = 'a@b.c'", 50*time.Millisecond)
+	a.Record("SELECT * FROM users WHERE id = 1", time.Millisecond) // 低于阈值
+
+	if shapes := a.Shapes(); len(shapes) != 2 {
+		t.Fatalf("期望记录 2 种查询形态，实际为 %d", len(shapes))
+	}
+
+	provider := fakeProvider{
+		"users": {{"index_name": "idx_email", "column_names": "email, name"}},
+	}
+	suggestions, err := a.Suggest(nil, provider)
+	if err != nil {
+		t.Fatalf("生成索引建议失败: %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("期望 1 条建议，实际为 %d: %+v", len(suggestions), suggestions)
+	}
+
+	s := suggestions[0]
+	if s.Table != "users" || !reflect.DeepEqual(s.Columns, []string{"status", "age"}) {
+		t.Errorf("建议不符合预期: %+v", s)
+	}
+	if s.Benefit != 300 {
+		t.Errorf("期望收益为 300ms，实际为 %v", s.Benefit)
+	}
+	if s.SQL != "CREATE INDEX `idx_users_status_age` ON `users` (`status`, `age`)" {
+		t.Errorf("DDL不符合预期: %s", s.SQL)
+	}
+}
@@ -0,0 +1,248 @@
+// Package anonymize 提供脱敏和假名化的列值转换：哈希、保留格式的假邮箱/手机号/姓名、置空等，
+// 按 "表.列" 或 "列" 配置后可用于导出（export）、跨库同步（sync）和数据快照（snapshot）
+//
+// 哈希和假名使用 HMAC-SHA256，同一密钥下相同的输入得到相同的输出，跨表的关联仍然成立；
+// 没有密钥时无法通过字典反推原值。NULL 保持为 NULL（Null、Constant 除外）
+//
+//	rules := anonymize.Rules{
+//		"users.email": anonymize.Email(secret),
+//		"users.phone": anonymize.Phone(secret),
+//		"users.name":  anonymize.Name(secret),
+//		"id_card":     anonymize.Null(),
+//	}
+//	n, err := export.Export(q, w, export.CSV, export.Options{Converters: rules.Export("users")})
+//	syncer, err := sync.NewSyncer(prod, staging, sync.Options{Converters: rules.Sync()})
+//	snap, err := snapshot.Take(ctx, prod, tables, snapshot.Options{Transformers: rules.Snapshot()})
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/export"
+	"github.com/gzorm/gosqlx/snapshot"
+	gosqlxsync "github.com/gzorm/gosqlx/sync"
+)
+
+// Transformer 列值转换函数，与 export.Converter、sync.Converter、snapshot.Transformer 的签名相同
+type Transformer func(value interface{}) (interface{}, error)
+
+// Rules 脱敏规则，键为 "表.列" 或 "列"，"表.列" 优先
+type Rules map[string]Transformer
+
+// Lookup 返回表的列对应的转换
+func (r Rules) Lookup(table, column string) (Transformer, bool) {
+	if transformer, ok := r[table+"."+column]; ok {
+		return transformer, true
+	}
+	transformer, ok := r[column]
+	return transformer, ok
+}
+
+// Export 返回表的导出列转换，导出按列名匹配，因此需要指定表名选出 "表.列" 规则
+func (r Rules) Export(table string) map[string]export.Converter {
+	converters := make(map[string]export.Converter)
+	for key, transformer := range r {
+		if !strings.Contains(key, ".") {
+			converters[key] = export.Converter(transformer)
+		}
+	}
+	prefix := table + "."
+	for key, transformer := range r {
+		if strings.HasPrefix(key, prefix) {
+			converters[strings.TrimPrefix(key, prefix)] = export.Converter(transformer)
+		}
+	}
+	return converters
+}
+
+// Sync 返回跨库同步的列转换，键的规则与同步相同
+func (r Rules) Sync() map[string]gosqlxsync.Converter {
+	converters := make(map[string]gosqlxsync.Converter, len(r))
+	for key, transformer := range r {
+		converters[key] = gosqlxsync.Converter(transformer)
+	}
+	return converters
+}
+
+// Snapshot 返回数据快照的列转换，键的规则与快照相同
+func (r Rules) Snapshot() map[string]snapshot.Transformer {
+	transformers := make(map[string]snapshot.Transformer, len(r))
+	for key, transformer := range r {
+		transformers[key] = snapshot.Transformer(transformer)
+	}
+	return transformers
+}
+
+// ==================== 内置转换 ====================
+
+// Null 置为 NULL
+func Null() Transformer {
+	return func(interface{}) (interface{}, error) { return nil, nil }
+}
+
+// Constant 替换为固定值
+func Constant(v interface{}) Transformer {
+	return func(interface{}) (interface{}, error) { return v, nil }
+}
+
+// Hash 替换为 HMAC-SHA256 的十六进制文本，length 大于 0 时截取前 length 个字符（最多 64）
+// 结果是字符串，只适用于文本列
+func Hash(secret string, length int) Transformer {
+	if length <= 0 || length > sha256.Size*2 {
+		length = sha256.Size * 2
+	}
+	return func(value interface{}) (interface{}, error) {
+		if value == nil {
+			return nil, nil
+		}
+		return hex.EncodeToString(digest(secret, text(value)))[:length], nil
+	}
+}
+
+// Email 替换为假邮箱 user_<10 位十六进制>@example.com，保持邮箱格式，相同的邮箱（不区分大小写）得到相同的结果
+func Email(secret string) Transformer {
+	return func(value interface{}) (interface{}, error) {
+		if value == nil {
+			return nil, nil
+		}
+		email := strings.ToLower(strings.TrimSpace(text(value)))
+		return "user_" + hex.EncodeToString(digest(secret, email))[:10] + "@example.com", nil
+	}
+}
+
+// Phone 替换手机号中除前 3 位以外的数字，保持长度、+ 号、空格和连字符等格式，
+// 前 3 位通常是国家码或号段，保留后号码仍然符合校验规则
+func Phone(secret string) Transformer {
+	return func(value interface{}) (interface{}, error) {
+		if value == nil {
+			return nil, nil
+		}
+		phone := text(value)
+		var digits strings.Builder
+		for _, r := range phone {
+			if unicode.IsDigit(r) {
+				digits.WriteRune(r)
+			}
+		}
+		sum := digest(secret, digits.String())
+		var b strings.Builder
+		seen := 0
+		for _, r := range phone {
+			if r < '0' || r > '9' {
+				b.WriteRune(r)
+				continue
+			}
+			if seen < 3 {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('0' + sum[seen%len(sum)]%10)
+			}
+			seen++
+		}
+		return b.String(), nil
+	}
+}
+
+// Name 替换为假姓名：含汉字时生成中文姓名（姓 + 与原名字数相同的名），
+// 否则按单词数生成英文名（一个单词为名，多个单词为名和姓）
+func Name(secret string) Transformer {
+	return func(value interface{}) (interface{}, error) {
+		if value == nil {
+			return nil, nil
+		}
+		name := strings.TrimSpace(text(value))
+		if name == "" {
+			return name, nil
+		}
+		sum := digest(secret, name)
+		pick := func(list []string, i int) string {
+			return list[binary.BigEndian.Uint32(sum[i*4:])%uint32(len(list))]
+		}
+		if strings.IndexFunc(name, isHan) >= 0 {
+			given := max(len([]rune(name))-1, 1)
+			result := pick(chineseSurnames, 0)
+			for i := 0; i < min(given, 2); i++ {
+				result += pick(chineseGivenNames, i+1)
+			}
+			return result, nil
+		}
+		if len(strings.Fields(name)) == 1 {
+			return pick(firstNames, 0), nil
+		}
+		return pick(firstNames, 0) + " " + pick(lastNames, 1), nil
+	}
+}
+
+// Chain 依次执行多个转换
+func Chain(transformers ...Transformer) Transformer {
+	return func(value interface{}) (interface{}, error) {
+		var err error
+		for _, transformer := range transformers {
+			if value, err = transformer(value); err != nil {
+				return nil, err
+			}
+		}
+		return value, nil
+	}
+}
+
+// ==================== 工具函数 ====================
+
+// digest 计算 HMAC-SHA256
+func digest(secret, s string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+// text 将列值转换为文本
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return gosqlx.FormatValue(value)
+}
+
+// isHan 是否为汉字
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+var firstNames = []string{
+	"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+	"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+	"Thomas", "Sarah", "Charles", "Karen", "Daniel", "Nancy", "Matthew", "Lisa",
+	"Anthony", "Betty", "Mark", "Helen", "Paul", "Sandra", "Steven", "Donna",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+	"Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Taylor",
+	"Moore", "Jackson", "Martin", "Lee", "Thompson", "White", "Harris", "Clark",
+	"Lewis", "Robinson", "Walker", "Young", "Allen", "King", "Wright", "Scott",
+}
+
+var chineseSurnames = []string{
+	"王", "李", "张", "刘", "陈", "杨", "黄", "赵", "吴", "周", "徐", "孙", "马", "朱", "胡", "郭",
+	"何", "林", "高", "罗", "郑", "梁", "谢", "宋", "唐", "许", "韩", "冯", "邓", "曹", "彭", "曾",
+}
+
+var chineseGivenNames = []string{
+	"伟", "芳", "娜", "敏", "静", "丽", "强", "磊", "军", "洋", "勇", "艳", "杰", "娟", "涛", "明",
+	"超", "秀", "霞", "平", "刚", "桂", "英", "华", "玉", "萍", "红", "鹏", "辉", "建", "文", "宇",
+}
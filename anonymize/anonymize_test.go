@@ -0,0 +1,88 @@
+package anonymize
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// apply 执行转换，出错时终止测试
+func apply(t *testing.T, transformer Transformer, value interface{}) interface{} {
+	t.Helper()
+	result, err := transformer(value)
+	if err != nil {
+		t.Fatalf("转换 %v 失败: %v", value, err)
+	}
+	return result
+}
+
+// 测试内置转换的格式和确定性
+func TestTransformers(t *testing.T) {
+	const secret = "s3cret"
+
+	hash := apply(t, Hash(secret, 16), "ann@example.com").(string)
+	if len(hash) != 16 || hash != apply(t, Hash(secret, 16), []byte("ann@example.com")) {
+		t.Errorf("Hash = %q，期望确定的 16 位结果", hash)
+	}
+	if hash == apply(t, Hash("other", 16), "ann@example.com") {
+		t.Error("不同密钥的哈希应不同")
+	}
+
+	email := apply(t, Email(secret), "Ann.Lee@Corp.com").(string)
+	if !regexp.MustCompile(`^user_[0-9a-f]{10}@example\.com$`).MatchString(email) {
+		t.Errorf("Email = %q", email)
+	}
+	if email != apply(t, Email(secret), " ann.lee@corp.com") {
+		t.Error("大小写不同的同一邮箱应得到相同的结果")
+	}
+
+	phone := apply(t, Phone(secret), "+86 138-0013-8000").(string)
+	if !regexp.MustCompile(`^\+86 1\d\d-\d{4}-\d{4}$`).MatchString(phone) || phone == "+86 138-0013-8000" {
+		t.Errorf("Phone = %q，期望保留格式和前 3 位", phone)
+	}
+
+	if name := apply(t, Name(secret), "Ann Lee").(string); len(strings.Fields(name)) != 2 {
+		t.Errorf("Name = %q，期望名和姓", name)
+	}
+	if name := apply(t, Name(secret), "王小明").(string); len([]rune(name)) != 3 {
+		t.Errorf("Name = %q，期望三个字的中文姓名", name)
+	}
+
+	for _, transformer := range []Transformer{Hash(secret, 0), Email(secret), Phone(secret), Name(secret)} {
+		if apply(t, transformer, nil) != nil {
+			t.Error("NULL 应保持为 NULL")
+		}
+	}
+	if apply(t, Null(), "x") != nil || apply(t, Constant("***"), nil) != "***" {
+		t.Error("Null、Constant 结果不符")
+	}
+	if got := apply(t, Chain(Email(secret), Constant("x")), "a@b.c"); got != "x" {
+		t.Errorf("Chain = %v", got)
+	}
+}
+
+// 测试按表选出导出、同步和快照的列转换
+func TestRules(t *testing.T) {
+	rules := Rules{
+		"email":        Null(),
+		"users.email":  Constant("user"),
+		"orders.phone": Null(),
+	}
+	if transformer, ok := rules.Lookup("users", "email"); !ok || apply(t, transformer, "a") != "user" {
+		t.Error("Lookup 应优先使用 表.列")
+	}
+
+	converters := rules.Export("users")
+	if len(converters) != 1 {
+		t.Fatalf("Export(users) = %d 个转换，期望 1", len(converters))
+	}
+	if v, _ := converters["email"]("a"); v != "user" {
+		t.Errorf("users 的 email = %v，期望 表.列 规则", v)
+	}
+	if v, _ := rules.Export("orders")["email"]("a"); v != nil {
+		t.Errorf("orders 的 email = %v，期望列规则", v)
+	}
+	if len(rules.Sync()) != 3 || len(rules.Snapshot()) != 3 {
+		t.Error("同步和快照应包含所有规则")
+	}
+}
@@ -0,0 +1,499 @@
+// Package archive 将源表中满足条件的行复制到冷存储（另一个数据库的表，或通过 io.Writer 写出的 CSV/JSON Lines/Parquet），
+// 校验行数和校验和后从源表删除，按主键分批推进并记录断点，中断后可继续
+//
+// 数据库目标每批复制、回读校验、删除；文件目标要到 Close 成功后内容才完整，因此先写完所有批次，
+// 关闭目标后再按记录的主键删除源表的行并记录断点
+//
+//	target := archive.NewDatabaseTarget(coldDB, "orders_archive")
+//	a := archive.New(db, "orders", target, archive.Options{Where: "created_at < ?", Args: []interface{}{cutoff}})
+//	result, err := a.Run(ctx)
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/export"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVerify 归档目标中的数据与源表不一致
+var ErrVerify = errors.New("归档校验失败")
+
+// Target 归档目标
+type Target interface {
+	// Write 写入一批行，返回写入的行数
+	Write(ctx context.Context, columns []string, rows [][]interface{}) (int64, error)
+	// Close 完成写入
+	Close() error
+}
+
+// Verifier 可以回读数据的归档目标，每批写入后按主键回读并比较校验和
+type Verifier interface {
+	// Checksum 返回目标中主键为 keys 的行按主键排序后的校验和
+	Checksum(ctx context.Context, columns []string, key string, keys []interface{}) (string, error)
+}
+
+// Checkpoint 断点存储，记录任务已处理的最大主键
+type Checkpoint interface {
+	// Load 读取任务的断点，没有断点时返回空字符串
+	Load(ctx context.Context, name string) (string, error)
+	// Save 保存任务的断点
+	Save(ctx context.Context, name string, key string) error
+}
+
+// Options 归档选项
+type Options struct {
+	Name       string        // 任务名，用于断点，默认为表名
+	Key        string        // 主键列，按其分批推进，默认 id
+	Where      string        // 归档条件，如 "created_at < ?"
+	Args       []interface{} // 归档条件的参数
+	BatchSize  int           // 每批的行数，默认 1000
+	Sleep      time.Duration // 批次间的休眠时间
+	KeepSource bool          // 只复制不删除源表的行
+	NoVerify   bool          // 不回读校验，目标为不同类型的数据库、取值表示不同时使用
+	Checkpoint Checkpoint    // 断点存储，为nil时不记录断点
+}
+
+// Result 归档结果
+type Result struct {
+	Copied   int64  // 复制的行数
+	Deleted  int64  // 从源表删除的行数
+	Batches  int    // 批数
+	LastKey  string // 处理到的最大主键
+	Checksum string // 复制的所有行的校验和，可用于事后核对归档文件
+}
+
+// Archiver 归档任务
+type Archiver struct {
+	db      *gosqlx.Database
+	table   string
+	target  Target
+	options Options
+}
+
+// New 创建归档任务
+func New(db *gosqlx.Database, table string, target Target, options Options) *Archiver {
+	if options.Name == "" {
+		options.Name = table
+	}
+	if options.Key == "" {
+		options.Key = "id"
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	if keyed, ok := target.(interface{ setKey(string) }); ok {
+		keyed.setKey(options.Key)
+	}
+	return &Archiver{db: db, table: table, target: target, options: options}
+}
+
+// Run 执行归档，完成后关闭目标；从断点之后的主键开始，出错时已完成的批次保留在断点中
+func (a *Archiver) Run(ctx context.Context) (*Result, error) {
+	result := &Result{}
+	if a.options.Checkpoint != nil {
+		last, err := a.options.Checkpoint.Load(ctx, a.options.Name)
+		if err != nil {
+			return result, fmt.Errorf("读取断点失败: %w", err)
+		}
+		result.LastKey = last
+	}
+
+	verifier, verify := a.target.(Verifier)
+	verify = verify && !a.options.NoVerify
+	var (
+		pending [][]interface{} // 不能回读的目标关闭后再删除和记录断点的主键
+		total   = sha256.New()
+	)
+
+	err := a.each(ctx, result, func(columns []string, rows [][]interface{}, keys []interface{}) error {
+		written, err := a.target.Write(ctx, columns, rows)
+		if err != nil {
+			return fmt.Errorf("写入归档目标失败: %w", err)
+		}
+		if written != int64(len(rows)) {
+			return fmt.Errorf("%w: 写入 %d 行，期望 %d 行", ErrVerify, written, len(rows))
+		}
+		writeChecksum(total, rows)
+		result.Copied += written
+
+		if verify {
+			expected := checksum(rows)
+			actual, err := verifier.Checksum(ctx, columns, a.options.Key, keys)
+			if err != nil {
+				return fmt.Errorf("回读归档数据失败: %w", err)
+			}
+			if actual != expected {
+				return fmt.Errorf("%w: 主键 %v 到 %v 的校验和不一致", ErrVerify, keys[0], keys[len(keys)-1])
+			}
+		}
+
+		if !verify {
+			pending = append(pending, keys)
+			return nil
+		}
+		if !a.options.KeepSource {
+			if err := a.delete(ctx, result, keys); err != nil {
+				return err
+			}
+		}
+		return a.save(ctx, result, keys)
+	})
+	closeErr := a.target.Close()
+	result.Checksum = hex.EncodeToString(total.Sum(nil))
+	if err != nil {
+		return result, err
+	}
+	if closeErr != nil {
+		return result, fmt.Errorf("关闭归档目标失败: %w", closeErr)
+	}
+
+	for _, keys := range pending {
+		if !a.options.KeepSource {
+			if err := a.delete(ctx, result, keys); err != nil {
+				return result, err
+			}
+		}
+		if err := a.save(ctx, result, keys); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// each 从断点之后按主键分批读取源表
+func (a *Archiver) each(ctx context.Context, result *Result, fn func(columns []string, rows [][]interface{}, keys []interface{}) error) error {
+	last := result.LastKey
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		query := a.db.DB().WithContext(ctx).Table(a.table)
+		if a.options.Where != "" {
+			query = query.Where(a.options.Where, a.options.Args...)
+		}
+		if last != "" {
+			query = query.Where(clause.Gt{Column: clause.Column{Name: a.options.Key}, Value: last})
+		}
+		rows, err := query.Order(clause.OrderByColumn{Column: clause.Column{Name: a.options.Key}}).
+			Limit(a.options.BatchSize).Rows()
+		if err != nil {
+			return fmt.Errorf("查询源数据失败: %w", err)
+		}
+		columns, batch, err := scanAll(rows)
+		if err != nil {
+			return fmt.Errorf("读取源数据失败: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		index := columnIndex(columns, a.options.Key)
+		if index < 0 {
+			return fmt.Errorf("查询结果中没有主键列 %s", a.options.Key)
+		}
+		keys := make([]interface{}, len(batch))
+		for i, row := range batch {
+			keys[i] = row[index]
+		}
+		if err := fn(columns, batch, keys); err != nil {
+			return err
+		}
+		result.Batches++
+		last = gosqlx.FormatValue(keys[len(keys)-1])
+
+		if len(batch) < a.options.BatchSize {
+			return nil
+		}
+		if a.options.Sleep > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(a.options.Sleep):
+			}
+		}
+	}
+}
+
+// delete 按主键删除源表的行
+func (a *Archiver) delete(ctx context.Context, result *Result, keys []interface{}) error {
+	res := a.db.DB().WithContext(ctx).Exec("DELETE FROM ? WHERE ?", clause.Table{Name: a.table},
+		clause.IN{Column: clause.Column{Name: a.options.Key}, Values: keys})
+	if res.Error != nil {
+		return fmt.Errorf("删除源数据失败: %w", res.Error)
+	}
+	result.Deleted += res.RowsAffected
+	return nil
+}
+
+// save 记录一批处理完成后的断点
+func (a *Archiver) save(ctx context.Context, result *Result, keys []interface{}) error {
+	result.LastKey = gosqlx.FormatValue(keys[len(keys)-1])
+	if a.options.Checkpoint == nil {
+		return nil
+	}
+	if err := a.options.Checkpoint.Save(ctx, a.options.Name, result.LastKey); err != nil {
+		return fmt.Errorf("保存断点失败: %w", err)
+	}
+	return nil
+}
+
+// ==================== 数据库目标 ====================
+
+// DatabaseTarget 归档到数据库的表，表需已存在且包含源表的列
+// 写入前先删除目标中相同主键的行，重复执行同一批不会产生重复数据
+type DatabaseTarget struct {
+	db    *gosqlx.Database
+	table string
+	key   string // 由归档任务设置的主键列
+}
+
+// NewDatabaseTarget 创建数据库归档目标
+func NewDatabaseTarget(db *gosqlx.Database, table string) *DatabaseTarget {
+	return &DatabaseTarget{db: db, table: table}
+}
+
+// Write 在一个事务中删除已存在的主键并写入一批行
+func (t *DatabaseTarget) Write(ctx context.Context, columns []string, rows [][]interface{}) (int64, error) {
+	values := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make(map[string]interface{}, len(columns))
+		for j, column := range columns {
+			values[i][column] = row[j]
+		}
+	}
+	var written int64
+	err := t.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if index := columnIndex(columns, t.key); index >= 0 {
+			keys := make([]interface{}, len(rows))
+			for i, row := range rows {
+				keys[i] = row[index]
+			}
+			if err := tx.Exec("DELETE FROM ? WHERE ?", clause.Table{Name: t.table},
+				clause.IN{Column: clause.Column{Name: t.key}, Values: keys}).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Table(t.table).CreateInBatches(values, t.batchSize(len(columns)))
+		written = result.RowsAffected
+		return result.Error
+	})
+	return written, err
+}
+
+// Checksum 按主键回读行并计算校验和
+func (t *DatabaseTarget) Checksum(ctx context.Context, columns []string, key string, keys []interface{}) (string, error) {
+	selects := make([]clause.Column, len(columns))
+	for i, column := range columns {
+		selects[i] = clause.Column{Name: column}
+	}
+	rows, err := t.db.DB().WithContext(ctx).Table(t.table).
+		Clauses(clause.Select{Columns: selects}).
+		Where(clause.IN{Column: clause.Column{Name: key}, Values: keys}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: key}}).Rows()
+	if err != nil {
+		return "", err
+	}
+	_, values, err := scanAll(rows)
+	if err != nil {
+		return "", err
+	}
+	return checksum(values), nil
+}
+
+// setKey 设置主键列
+func (t *DatabaseTarget) setKey(key string) {
+	t.key = key
+}
+
+// Close 数据库目标无需关闭
+func (t *DatabaseTarget) Close() error {
+	return nil
+}
+
+// batchSize 按参数上限计算每条 INSERT 的行数
+func (t *DatabaseTarget) batchSize(columns int) int {
+	size := 500
+	if limit := t.db.ParamLimit(); limit > 0 && columns > 0 && limit/columns < size {
+		size = limit / columns
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// ==================== 文件目标 ====================
+
+// WriterTarget 以 CSV、JSON Lines 或 Parquet 格式写入 io.Writer（如对象存储的上传流）
+type WriterTarget struct {
+	w      io.Writer
+	format export.Format
+	opts   export.Options
+	writer *export.Writer
+}
+
+// NewWriterTarget 创建文件归档目标，CSV 表头和 Parquet 列按第一批的列生成
+func NewWriterTarget(w io.Writer, format export.Format, opts export.Options) *WriterTarget {
+	return &WriterTarget{w: w, format: format, opts: opts}
+}
+
+// Write 写出一批行
+func (t *WriterTarget) Write(ctx context.Context, columns []string, rows [][]interface{}) (int64, error) {
+	if t.writer == nil {
+		writer, err := export.NewWriter(t.w, t.format, columns, t.opts)
+		if err != nil {
+			return 0, err
+		}
+		t.writer = writer
+	}
+	if err := t.writer.WriteBatch(rows); err != nil {
+		return 0, err
+	}
+	return int64(len(rows)), nil
+}
+
+// Close 完成写出，io.Writer 实现了 io.Closer 时一并关闭
+func (t *WriterTarget) Close() error {
+	if t.writer != nil {
+		if err := t.writer.Close(); err != nil {
+			return err
+		}
+	}
+	if closer, ok := t.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ==================== 断点 ====================
+
+// DefaultCheckpointTable 默认断点表名
+const DefaultCheckpointTable = "gosqlx_archive_checkpoints"
+
+// checkpointRow 断点表的行
+type checkpointRow struct {
+	Name      string    `gorm:"column:name;primaryKey;size:128"`
+	LastKey   string    `gorm:"column:last_key;size:255"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableCheckpoint 保存在数据库表中的断点
+type TableCheckpoint struct {
+	db    *gosqlx.Database
+	table string
+}
+
+// NewTableCheckpoint 创建表断点存储，表不存在时自动创建，table 为空时使用 gosqlx_archive_checkpoints
+func NewTableCheckpoint(db *gosqlx.Database, table string) (*TableCheckpoint, error) {
+	if table == "" {
+		table = DefaultCheckpointTable
+	}
+	migrator := db.DB().Table(table).Migrator()
+	if !migrator.HasTable(table) {
+		if err := migrator.CreateTable(&checkpointRow{}); err != nil {
+			return nil, fmt.Errorf("创建断点表失败: %w", err)
+		}
+	}
+	return &TableCheckpoint{db: db, table: table}, nil
+}
+
+// Load 读取断点
+func (c *TableCheckpoint) Load(ctx context.Context, name string) (string, error) {
+	var rows []checkpointRow
+	if err := c.db.DB().WithContext(ctx).Table(c.table).Where("name = ?", name).Limit(1).Find(&rows).Error; err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return rows[0].LastKey, nil
+}
+
+// Save 保存断点
+func (c *TableCheckpoint) Save(ctx context.Context, name string, key string) error {
+	return c.db.DB().WithContext(ctx).Table(c.table).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_key", "updated_at"}),
+	}).Create(&checkpointRow{Name: name, LastKey: key, UpdatedAt: time.Now()}).Error
+}
+
+// Reset 清除断点，下次从头开始
+func (c *TableCheckpoint) Reset(ctx context.Context, name string) error {
+	return c.db.DB().WithContext(ctx).Table(c.table).Where("name = ?", name).Delete(&checkpointRow{}).Error
+}
+
+// ==================== 工具函数 ====================
+
+// scanAll 读取结果集的所有行，[]byte 转换为字符串
+func scanAll(rows *sql.Rows) ([]string, [][]interface{}, error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var result [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, nil, err
+		}
+		for i, value := range values {
+			if b, ok := value.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result = append(result, values)
+	}
+	return columns, result, rows.Err()
+}
+
+// columnIndex 查找列的位置（忽略大小写）
+func columnIndex(columns []string, name string) int {
+	for i, column := range columns {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// checksum 计算行的 SHA-256 校验和
+func checksum(rows [][]interface{}) string {
+	h := sha256.New()
+	writeChecksum(h, rows)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeChecksum 将行的规范化文本写入摘要，时间统一为 UTC，NULL 与空字符串区分
+func writeChecksum(h hash.Hash, rows [][]interface{}) {
+	for _, row := range rows {
+		for _, value := range row {
+			switch v := value.(type) {
+			case nil:
+				h.Write([]byte{0})
+			case time.Time:
+				h.Write([]byte(v.UTC().Format(time.RFC3339Nano)))
+			default:
+				h.Write([]byte(gosqlx.FormatValue(v)))
+			}
+			h.Write([]byte{0x1f})
+		}
+		h.Write([]byte{0x1e})
+	}
+}
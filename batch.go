@@ -0,0 +1,539 @@
+package gosqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gzorm/gosqlx/adapter"
+	"github.com/gzorm/gosqlx/builder"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 批量更新与删除 ====================
+
+// ParamLimit 单条语句可使用的参数上限（预留少量余量），取自适配器的 Capabilities
+func (d *Database) ParamLimit() int {
+	if limit := d.Capabilities().MaxBindParams; limit > 0 {
+		return limit
+	}
+	return defaultCapabilities.MaxBindParams
+}
+
+// inListLimit IN 列表的元素上限
+func (d *Database) inListLimit() int {
+	if limit := d.Capabilities().MaxInList; limit > 0 {
+		return min(limit, d.ParamLimit())
+	}
+	return d.ParamLimit()
+}
+
+// BatchUpdate 按主键批量更新多行，rows 中每行必须包含 keyColumn，其余键为待更新的列
+// 默认生成 CASE WHEN 单语句更新；SQLServer 使用临时表关联更新，Oracle 使用 MERGE 关联内联数据集。
+// 超过参数上限时自动分批，所有批次在同一事务中执行，返回受影响的行数
+func (d *Database) BatchUpdate(table string, keyColumn string, rows []map[string]interface{}) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	// 各批次在同一事务中执行，不支持事务时（ClickHouse、MongoDB）不能批量更新
+	if !d.Capabilities().Transactions {
+		return 0, fmt.Errorf("%s 不支持批量更新", d.dbType)
+	}
+	for i, row := range rows {
+		if _, ok := row[keyColumn]; !ok {
+			return 0, fmt.Errorf("第 %d 行缺少键列 %s", i+1, keyColumn)
+		}
+	}
+
+	var affected int64
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		switch d.dbType {
+		case SQLServer, Oracle:
+			// 关联更新要求各行列相同，按列集合分组执行
+			for _, group := range groupRowsByColumns(rows, keyColumn) {
+				var (
+					n   int64
+					err error
+				)
+				if d.dbType == SQLServer {
+					n, err = d.batchUpdateTempTable(tx, table, keyColumn, group.columns, group.rows)
+				} else {
+					n, err = d.batchUpdateMerge(tx, table, keyColumn, group.columns, group.rows)
+				}
+				if err != nil {
+					return err
+				}
+				affected += n
+			}
+			return nil
+		default:
+			n, err := d.batchUpdateCase(tx, table, keyColumn, rows)
+			affected = n
+			return err
+		}
+	})
+	if err != nil {
+		return 0, fmt.Errorf("批量更新失败: %w", err)
+	}
+	return affected, nil
+}
+
+// BatchDeleteIn 删除 column 在 values 中的记录，超过参数上限时自动分批，所有批次在同一事务中执行
+func (d *Database) BatchDeleteIn(table string, column string, values []interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+	if d.db == nil {
+		return 0, errors.New("数据库连接不支持批量删除")
+	}
+
+	size := d.inListLimit()
+	sqlStr := fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", d.db.Statement.Quote(table), d.db.Statement.Quote(column))
+	var affected int64
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(values); start += size {
+			end := min(start+size, len(values))
+			result := tx.Exec(sqlStr, values[start:end])
+			if result.Error != nil {
+				return result.Error
+			}
+			affected += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("批量删除失败: %w", err)
+	}
+	return affected, nil
+}
+
+// batchUpdateCase 使用 CASE WHEN 语句批量更新
+// UPDATE t SET c = CASE k WHEN ? THEN ? ... ELSE c END WHERE k IN (...)
+func (d *Database) batchUpdateCase(tx *gorm.DB, table, keyColumn string, rows []map[string]interface{}) (int64, error) {
+	columns := rowColumns(rows, keyColumn)
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	// 每行最多占用 2*列数+1 个参数
+	size := max(d.ParamLimit()/(2*len(columns)+1), 1)
+	quotedKey := tx.Statement.Quote(keyColumn)
+	var affected int64
+	for start := 0; start < len(rows); start += size {
+		chunk := rows[start:min(start+size, len(rows))]
+
+		var (
+			sets []string
+			args []interface{}
+		)
+		for _, column := range columns {
+			quoted := tx.Statement.Quote(column)
+			var caseSQL strings.Builder
+			caseSQL.WriteString(fmt.Sprintf("%s = CASE %s", quoted, quotedKey))
+			for _, row := range chunk {
+				if value, ok := row[column]; ok {
+					caseSQL.WriteString(" WHEN ? THEN ?")
+					args = append(args, row[keyColumn], value)
+				}
+			}
+			caseSQL.WriteString(fmt.Sprintf(" ELSE %s END", quoted))
+			sets = append(sets, caseSQL.String())
+		}
+
+		keys := make([]interface{}, len(chunk))
+		for i, row := range chunk {
+			keys[i] = row[keyColumn]
+		}
+		args = append(args, keys)
+
+		result := tx.Exec(fmt.Sprintf("UPDATE %s SET %s WHERE %s IN ?",
+			tx.Statement.Quote(table), strings.Join(sets, ", "), quotedKey), args...)
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		affected += result.RowsAffected
+	}
+	return affected, nil
+}
+
+// batchUpdateTempTable SQLServer 将数据写入临时表后关联更新
+func (d *Database) batchUpdateTempTable(tx *gorm.DB, table, keyColumn string, columns []string, rows []map[string]interface{}) (int64, error) {
+	all := append([]string{keyColumn}, columns...)
+	quoted := make([]string, len(all))
+	for i, column := range all {
+		quoted[i] = tx.Statement.Quote(column)
+	}
+	temp := fmt.Sprintf("#gosqlx_batch_%d", time.Now().UnixNano())
+
+	// UNION ALL 使临时表不继承自增属性，便于写入主键值
+	selectSQL := fmt.Sprintf("SELECT %s FROM %s WHERE 1 = 0", strings.Join(quoted, ", "), tx.Statement.Quote(table))
+	if err := tx.Exec(fmt.Sprintf("SELECT * INTO %s FROM (%s UNION ALL %s) AS src", temp, selectSQL, selectSQL)).Error; err != nil {
+		return 0, fmt.Errorf("创建临时表失败: %w", err)
+	}
+	defer tx.Exec("DROP TABLE " + temp)
+
+	size := max(d.ParamLimit()/len(all), 1)
+	for start := 0; start < len(rows); start += size {
+		chunk := rows[start:min(start+size, len(rows))]
+		placeholders := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*len(all))
+		for i, row := range chunk {
+			placeholders[i] = "(" + strings.TrimSuffix(strings.Repeat("?, ", len(all)), ", ") + ")"
+			for _, column := range all {
+				args = append(args, row[column])
+			}
+		}
+		insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", temp, strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+		if err := tx.Exec(insertSQL, args...).Error; err != nil {
+			return 0, fmt.Errorf("写入临时表失败: %w", err)
+		}
+	}
+
+	sets := make([]string, len(columns))
+	for i := range columns {
+		sets[i] = fmt.Sprintf("t.%s = s.%s", quoted[i+1], quoted[i+1])
+	}
+	result := tx.Exec(fmt.Sprintf("UPDATE t SET %s FROM %s AS t INNER JOIN %s AS s ON t.%s = s.%s",
+		strings.Join(sets, ", "), tx.Statement.Quote(table), temp, quoted[0], quoted[0]))
+	return result.RowsAffected, result.Error
+}
+
+// batchUpdateMerge Oracle 使用 MERGE 关联 DUAL 构造的内联数据集更新，避免临时表 DDL 隐式提交
+func (d *Database) batchUpdateMerge(tx *gorm.DB, table, keyColumn string, columns []string, rows []map[string]interface{}) (int64, error) {
+	all := append([]string{keyColumn}, columns...)
+	quoted := make([]string, len(all))
+	for i, column := range all {
+		quoted[i] = tx.Statement.Quote(column)
+	}
+	sets := make([]string, len(columns))
+	for i := range columns {
+		sets[i] = fmt.Sprintf("t.%s = s.%s", quoted[i+1], quoted[i+1])
+	}
+
+	size := max(min(d.ParamLimit()/len(all), 1000), 1)
+	var affected int64
+	for start := 0; start < len(rows); start += size {
+		chunk := rows[start:min(start+size, len(rows))]
+		selects := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*len(all))
+		for i, row := range chunk {
+			fields := make([]string, len(all))
+			for j, column := range all {
+				fields[j] = "? AS " + quoted[j]
+				args = append(args, row[column])
+			}
+			selects[i] = "SELECT " + strings.Join(fields, ", ") + " FROM DUAL"
+		}
+
+		result := tx.Exec(fmt.Sprintf("MERGE INTO %s t USING (%s) s ON (t.%s = s.%s) WHEN MATCHED THEN UPDATE SET %s",
+			tx.Statement.Quote(table), strings.Join(selects, " UNION ALL "), quoted[0], quoted[0], strings.Join(sets, ", ")), args...)
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		affected += result.RowsAffected
+	}
+	return affected, nil
+}
+
+// rowGroup 列集合相同的行
+type rowGroup struct {
+	columns []string
+	rows    []map[string]interface{}
+}
+
+// groupRowsByColumns 按待更新列集合对行分组，保持首次出现的顺序
+func groupRowsByColumns(rows []map[string]interface{}, keyColumn string) []*rowGroup {
+	var groups []*rowGroup
+	index := make(map[string]*rowGroup)
+	for _, row := range rows {
+		columns := rowColumns([]map[string]interface{}{row}, keyColumn)
+		if len(columns) == 0 {
+			continue
+		}
+		signature := strings.Join(columns, "\x00")
+		group, ok := index[signature]
+		if !ok {
+			group = &rowGroup{columns: columns}
+			index[signature] = group
+			groups = append(groups, group)
+		}
+		group.rows = append(group.rows, row)
+	}
+	return groups
+}
+
+// rowColumns 获取所有行中除键列外的列名（排序后）
+func rowColumns(rows []map[string]interface{}, keyColumn string) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, row := range rows {
+		for column := range row {
+			if column != keyColumn && !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// ==================== 批量插入 ====================
+
+// BatchInsertOptions 批量插入选项
+type BatchInsertOptions struct {
+	ChunkSize int  // 每批插入的行数，0 表示按数据库参数上限和列数计算，超过上限时按上限
+	ChunkTx   bool // 每批在独立事务中提交，失败时之前的批次保留；默认所有批次在同一事务中
+	// OnProgress 每批插入成功后回调，inserted 为已插入的行数
+	OnProgress func(inserted, total int)
+}
+
+// insertChunkSize 单条多行 INSERT 可插入的行数
+func (d *Database) insertChunkSize(columns int) int {
+	limit := d.ParamLimit()
+	switch d.dbType {
+	case Oracle:
+		limit = 1000 // INSERT ALL 的列总数上限（ORA-24335）
+	}
+	size := max(limit/max(columns, 1), 1)
+	if d.dbType == SQLServer {
+		size = min(size, 1000) // VALUES 行构造器上限 1000 行
+	}
+	return size
+}
+
+// BatchInsertWithOptions 批量插入，按数据库参数上限和列数自动分批
+// 示例: db.BatchInsertWithOptions("users", columns, values, gosqlx.BatchInsertOptions{ChunkTx: true, OnProgress: report})
+func (d *Database) BatchInsertWithOptions(table string, columns []string, values [][]interface{}, opts BatchInsertOptions) error {
+	if d.adapter == nil {
+		return errors.New("数据库适配器不支持批量插入")
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	size := d.insertChunkSize(len(columns))
+	if opts.ChunkSize > 0 {
+		size = min(opts.ChunkSize, size)
+	}
+	insert := func(tx *gorm.DB, start int) error {
+		end := min(start+size, len(values))
+		if err := d.adapter.BatchInsert(tx, table, columns, values[start:end]); err != nil {
+			return err
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(end, len(values))
+		}
+		return nil
+	}
+
+	if len(values) <= size {
+		return insert(d.db, 0)
+	}
+	// 不支持事务时（ClickHouse、MongoDB）逐批执行
+	if !d.Capabilities().Transactions {
+		for start := 0; start < len(values); start += size {
+			if err := insert(d.db, start); err != nil {
+				return fmt.Errorf("批量插入失败（已插入 %d 行）: %w", start, err)
+			}
+		}
+		return nil
+	}
+	if opts.ChunkTx {
+		for start := 0; start < len(values); start += size {
+			if err := d.db.Transaction(func(tx *gorm.DB) error { return insert(tx, start) }); err != nil {
+				return fmt.Errorf("批量插入失败（已插入 %d 行）: %w", start, err)
+			}
+		}
+		return nil
+	}
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(values); start += size {
+			if err := insert(tx, start); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("批量插入失败: %w", err)
+	}
+	return nil
+}
+
+// ==================== 分批查询 ====================
+
+// oversizedIn 查找条件参数中超过 IN 上限的切片，返回其下标，不存在时返回-1
+func (d *Database) oversizedIn(where []interface{}) int {
+	limit := builder.GetInLimit(string(d.dbType)).Size
+	if limit <= 0 {
+		return -1
+	}
+	for i, arg := range where {
+		rv := reflect.ValueOf(arg)
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 && rv.Len() > limit {
+			return i
+		}
+	}
+	return -1
+}
+
+// findChunked 将 where[index] 的切片拆分后分批查询，结果追加到 out
+func (d *Database) findChunked(out interface{}, where []interface{}, index int, relations []Relation) error {
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Ptr || outValue.Elem().Kind() != reflect.Slice {
+		return applyRelations(d.Model(out), relations).Find(out, where...).Error
+	}
+
+	size := builder.GetInLimit(string(d.dbType)).Size
+	values := reflect.ValueOf(where[index])
+	result := reflect.MakeSlice(outValue.Elem().Type(), 0, values.Len())
+	args := append([]interface{}(nil), where...)
+	for start := 0; start < values.Len(); start += size {
+		args[index] = values.Slice(start, min(start+size, values.Len())).Interface()
+		batch := reflect.New(outValue.Elem().Type())
+		if err := applyRelations(d.Model(out), relations).Find(batch.Interface(), args...).Error; err != nil {
+			return err
+		}
+		result = reflect.AppendSlice(result, batch.Elem())
+	}
+	outValue.Elem().Set(result)
+	return nil
+}
+
+// findByIDsConcurrency FindByIDs 同时执行的分批查询数
+const findByIDsConcurrency = 4
+
+// FindByIDs 按主键批量查询，out 为结构体切片指针，ids 为主键切片，返回不存在的主键
+// 主键去重后按方言 IN 上限分批，不在事务中时并发查询；结果按主键在 ids 中首次出现的顺序排列，
+// 主键与字段类型不同（如 int 与 int64）时按字面值匹配
+//
+//	var users []User
+//	missing, err := db.FindByIDs(&users, []int64{3, 1, 2})
+func (d *Database) FindByIDs(out interface{}, ids interface{}) ([]interface{}, error) {
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Ptr || outValue.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("FindByIDs 的结果必须是切片指针")
+	}
+	idValues := reflect.ValueOf(ids)
+	if idValues.Kind() != reflect.Slice && idValues.Kind() != reflect.Array {
+		return nil, errors.New("FindByIDs 的主键必须是切片")
+	}
+
+	stmt := &gorm.Statement{DB: d.db}
+	if err := stmt.Parse(out); err != nil {
+		return nil, fmt.Errorf("解析模型失败: %w", err)
+	}
+	primary := stmt.Schema.PrioritizedPrimaryField
+	if primary == nil {
+		return nil, fmt.Errorf("模型 %s 没有主键", stmt.Schema.Name)
+	}
+
+	// 去重并保留首次出现的顺序
+	order := make([]string, 0, idValues.Len())
+	unique := make([]interface{}, 0, idValues.Len())
+	seen := make(map[string]bool, idValues.Len())
+	for i := 0; i < idValues.Len(); i++ {
+		id := idValues.Index(i).Interface()
+		key := idKey(id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		order = append(order, key)
+		unique = append(unique, id)
+	}
+	sliceType := outValue.Elem().Type()
+	if len(unique) == 0 {
+		outValue.Elem().Set(reflect.MakeSlice(sliceType, 0, 0))
+		return nil, nil
+	}
+
+	size := builder.GetInLimit(string(d.dbType)).Size
+	if size <= 0 {
+		size = d.inListLimit()
+	}
+	var chunks [][]interface{}
+	for start := 0; start < len(unique); start += size {
+		chunks = append(chunks, unique[start:min(start+size, len(unique))])
+	}
+	batches := make([]reflect.Value, len(chunks))
+	column := clause.Column{Table: clause.CurrentTable, Name: primary.DBName}
+	find := func(i int) error {
+		batch := reflect.New(sliceType)
+		if err := d.Model(out).Where(clause.IN{Column: column, Values: chunks[i]}).Find(batch.Interface()).Error; err != nil {
+			return fmt.Errorf("查询第 %d 批主键失败: %w", i+1, err)
+		}
+		batches[i] = batch.Elem()
+		return nil
+	}
+
+	if adapter.Sequential(d.db) || len(chunks) == 1 {
+		for i := range chunks {
+			if err := find(i); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		var (
+			wg    sync.WaitGroup
+			slots = make(chan struct{}, findByIDsConcurrency)
+			errs  = make([]error, len(chunks))
+		)
+		for i := range chunks {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				errs[i] = find(i)
+			}()
+		}
+		wg.Wait()
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+	}
+
+	// 按主键重排结果
+	rows := make(map[string]reflect.Value, len(unique))
+	ctx := d.db.Statement.Context
+	for _, batch := range batches {
+		if !batch.IsValid() {
+			continue
+		}
+		for i := 0; i < batch.Len(); i++ {
+			row := batch.Index(i)
+			value, _ := primary.ValueOf(ctx, reflect.Indirect(row))
+			rows[idKey(value)] = row
+		}
+	}
+	result := reflect.MakeSlice(sliceType, 0, len(rows))
+	var missing []interface{}
+	for i, key := range order {
+		if row, ok := rows[key]; ok {
+			result = reflect.Append(result, row)
+		} else {
+			missing = append(missing, unique[i])
+		}
+	}
+	outValue.Elem().Set(result)
+	return missing, d.runHooks(afterFind, out)
+}
+
+// idKey 主键的匹配键，解引用指针后取字面值
+func idKey(id interface{}) string {
+	value := reflect.ValueOf(id)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return "<nil>"
+	}
+	return fmt.Sprint(value.Interface())
+}
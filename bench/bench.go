@@ -0,0 +1,170 @@
+// Package bench 提供跨适配器复用的基准测试和性能预算断言，便于在自己的 CI 中发现升级 gosqlx 带来的性能回退
+//
+// 基准测试针对任意已连接的 Database 运行，覆盖插入、批量插入、主键查询和结果扫描:
+//
+//	func BenchmarkGosqlx(b *testing.B) {
+//		bench.Run(b, db, bench.Options{})
+//	}
+//
+// PerformanceBudget 在普通测试中运行基准测试并断言每秒操作数和每次操作的分配次数:
+//
+//	func TestScanBudget(t *testing.T) {
+//		bench.Setup(t, db, 1000)
+//		bench.PerformanceBudget{MinOpsPerSec: 500, MaxAllocsPerOp: 20000}.Assert(t, "scan", func(b *testing.B) {
+//			bench.Scan(b, db, 1000)
+//		})
+//	}
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// Table 基准测试使用的表名
+const Table = "gosqlx_bench"
+
+// Row 基准测试表的行
+type Row struct {
+	ID     int64   `gorm:"column:id;primaryKey;autoIncrement:false" db:"id"`
+	Name   string  `gorm:"column:name;size:64" db:"name"`
+	Amount float64 `gorm:"column:amount" db:"amount"`
+	Note   string  `gorm:"column:note;size:255" db:"note"`
+}
+
+// TableName 表名
+func (Row) TableName() string {
+	return Table
+}
+
+// Options 基准测试选项
+type Options struct {
+	Rows      int // 预置行数，也是 Scan 每次读取的行数，默认 1000
+	BatchSize int // BatchInsert 每次插入的行数，默认 100
+}
+
+// Run 重建基准测试表并依次运行全部基准测试，结束后删除表
+func Run(b *testing.B, db *gosqlx.Database, opts Options) {
+	if opts.Rows <= 0 {
+		opts.Rows = 1000
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	Setup(b, db, opts.Rows)
+	b.Cleanup(func() {
+		_ = db.DB().Migrator().DropTable(Table)
+	})
+
+	b.Run("Insert", func(b *testing.B) { Insert(b, db) })
+	b.Run("BatchInsert", func(b *testing.B) { BatchInsert(b, db, opts.BatchSize) })
+	b.Run("SelectByID", func(b *testing.B) { SelectByID(b, db, opts.Rows) })
+	b.Run("Scan", func(b *testing.B) { Scan(b, db, opts.Rows) })
+	b.Run("QueryMaps", func(b *testing.B) { QueryMaps(b, db, opts.Rows) })
+}
+
+// Setup 重建基准测试表并插入 n 行，编号为 1..n
+func Setup(tb testing.TB, db *gosqlx.Database, n int) {
+	tb.Helper()
+	migrator := db.DB().Migrator()
+	if err := migrator.DropTable(Table); err != nil {
+		tb.Fatalf("删除基准测试表失败: %v", err)
+	}
+	if err := migrator.CreateTable(&Row{}); err != nil {
+		tb.Fatalf("创建基准测试表失败: %v", err)
+	}
+	for start := 0; start < n; start += 500 {
+		end := min(start+500, n)
+		if err := db.BatchInsert(Table, columns, rows(int64(start+1), end-start)); err != nil {
+			tb.Fatalf("插入基准测试数据失败: %v", err)
+		}
+	}
+}
+
+// Insert 基准测试：每次插入一行
+func Insert(b *testing.B, db *gosqlx.Database) {
+	next := maxID(b, db) + 1
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		row := Row{ID: next + int64(i), Name: "insert", Amount: float64(i), Note: "bench"}
+		if err := db.Create(&row); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BatchInsert 基准测试：每次批量插入 size 行
+func BatchInsert(b *testing.B, db *gosqlx.Database, size int) {
+	next := maxID(b, db) + 1
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		values := rows(next+int64(i*size), size)
+		b.StartTimer()
+		if err := db.BatchInsert(Table, columns, values); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// SelectByID 基准测试：按主键查询单行，编号在 1..n 间循环
+func SelectByID(b *testing.B, db *gosqlx.Database, n int) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var row Row
+		if err := db.NewQuery().Table(Table).Where("id = ?", i%n+1).First(&row); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Scan 基准测试：按编号顺序扫描 n 行到结构体切片
+func Scan(b *testing.B, db *gosqlx.Database, n int) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var rows []Row
+		if err := db.NewQuery().Table(Table).OrderBy("id").Limit(n).Get(&rows); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// QueryMaps 基准测试：以 map 形式读取 n 行
+func QueryMaps(b *testing.B, db *gosqlx.Database, n int) {
+	sqlStr := fmt.Sprintf("SELECT id, name, amount, note FROM %s WHERE id <= ?", Table)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.QueryMaps(sqlStr, n); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// columns 基准测试表的列
+var columns = []string{"id", "name", "amount", "note"}
+
+// rows 生成从 start 开始编号的 n 行
+func rows(start int64, n int) [][]interface{} {
+	values := make([][]interface{}, n)
+	for i := range values {
+		id := start + int64(i)
+		values[i] = []interface{}{id, fmt.Sprintf("row%d", id), float64(id) / 100, "bench"}
+	}
+	return values
+}
+
+// maxID 基准测试表当前的最大编号
+func maxID(b *testing.B, db *gosqlx.Database) int64 {
+	var id int64
+	if err := db.DB().Table(Table).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		b.Fatalf("查询最大编号失败: %v", err)
+	}
+	return id
+}
@@ -0,0 +1,61 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// openSQLite 打开内存 SQLite 数据库
+func openSQLite(tb testing.TB) *gosqlx.Database {
+	ctx := gosqlx.NewContext(context.Background(), "bench", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		tb.Fatalf("连接失败: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// 测试性能预算检查
+func TestPerformanceBudget(t *testing.T) {
+	// 100 次操作共 1 秒，每次 10ms，分配 50 次、4KB
+	result := testing.BenchmarkResult{N: 100, T: time.Second, MemAllocs: 5000, MemBytes: 409600}
+	if OpsPerSec(result) != 100 {
+		t.Errorf("OpsPerSec = %v", OpsPerSec(result))
+	}
+	if err := (PerformanceBudget{MinOpsPerSec: 100, MaxAllocsPerOp: 50, MaxBytesPerOp: 4096}).Check(result); err != nil {
+		t.Errorf("未超出预算: %v", err)
+	}
+
+	err := PerformanceBudget{MinOpsPerSec: 200, MaxNsPerOp: int64(5 * time.Millisecond), MaxAllocsPerOp: 49}.Check(result)
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || len(budgetErr.Violations) != 3 {
+		t.Fatalf("期望 3 项超出预算，实际为 %v", err)
+	}
+}
+
+// 测试在普通测试中断言 SQLite 扫描的性能预算
+func TestSQLiteScanBudget(t *testing.T) {
+	db := openSQLite(t)
+	Setup(t, db, 100)
+	result := PerformanceBudget{MinOpsPerSec: 10, MaxAllocsPerOp: 100000}.Assert(t, "scan", func(b *testing.B) {
+		Scan(b, db, 100)
+	})
+	if result.N == 0 || result.AllocsPerOp() == 0 {
+		t.Errorf("基准测试结果 = %+v", result)
+	}
+
+	var rows []Row
+	if err := db.NewQuery().Table(Table).Where("id = ?", 100).Get(&rows); err != nil || len(rows) != 1 || rows[0].Name != "row100" {
+		t.Errorf("预置数据 = %+v, %v", rows, err)
+	}
+}
+
+// 基准测试：SQLite 上的全部基准
+func BenchmarkSQLite(b *testing.B) {
+	Run(b, openSQLite(b), Options{})
+}
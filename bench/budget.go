@@ -0,0 +1,71 @@
+package bench
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// PerformanceBudget 基准测试的性能预算，零值的项不检查
+type PerformanceBudget struct {
+	MinOpsPerSec   float64 // 每秒最少操作数
+	MaxNsPerOp     int64   // 每次操作最多耗时（纳秒）
+	MaxAllocsPerOp int64   // 每次操作最多分配次数
+	MaxBytesPerOp  int64   // 每次操作最多分配字节数
+}
+
+// BudgetError 超出性能预算
+type BudgetError struct {
+	Result     testing.BenchmarkResult
+	Violations []string // 超出的各项
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("超出性能预算: %s (%s)", strings.Join(e.Violations, "; "), e.Result.String()+e.Result.MemString())
+}
+
+// OpsPerSec 基准测试结果的每秒操作数
+func OpsPerSec(r testing.BenchmarkResult) float64 {
+	if ns := r.NsPerOp(); ns > 0 {
+		return 1e9 / float64(ns)
+	}
+	return 0
+}
+
+// Check 检查基准测试结果，超出预算时返回 *BudgetError
+func (p PerformanceBudget) Check(r testing.BenchmarkResult) error {
+	var violations []string
+	if ops := OpsPerSec(r); p.MinOpsPerSec > 0 && ops < p.MinOpsPerSec {
+		violations = append(violations, fmt.Sprintf("每秒操作数 %.0f 低于 %.0f", ops, p.MinOpsPerSec))
+	}
+	if ns := r.NsPerOp(); p.MaxNsPerOp > 0 && ns > p.MaxNsPerOp {
+		violations = append(violations, fmt.Sprintf("每次操作耗时 %dns 超过 %dns", ns, p.MaxNsPerOp))
+	}
+	if allocs := r.AllocsPerOp(); p.MaxAllocsPerOp > 0 && allocs > p.MaxAllocsPerOp {
+		violations = append(violations, fmt.Sprintf("每次操作分配 %d 次超过 %d 次", allocs, p.MaxAllocsPerOp))
+	}
+	if bytes := r.AllocedBytesPerOp(); p.MaxBytesPerOp > 0 && bytes > p.MaxBytesPerOp {
+		violations = append(violations, fmt.Sprintf("每次操作分配 %dB 超过 %dB", bytes, p.MaxBytesPerOp))
+	}
+	if len(violations) > 0 {
+		return &BudgetError{Result: r, Violations: violations}
+	}
+	return nil
+}
+
+// Assert 在普通测试中运行基准测试并检查预算，超出时测试失败；-short 模式下跳过
+// 耗时类预算受机器负载影响，建议留出余量，分配次数的预算通常更稳定
+func (p PerformanceBudget) Assert(tb testing.TB, name string, fn func(b *testing.B)) testing.BenchmarkResult {
+	tb.Helper()
+	if testing.Short() {
+		tb.Skipf("-short 模式下跳过性能预算 %s", name)
+	}
+	result := testing.Benchmark(fn)
+	if result.N == 0 {
+		tb.Fatalf("基准测试 %s 未运行，可能已失败", name)
+	}
+	if err := p.Check(result); err != nil {
+		tb.Fatalf("%s: %v", name, err)
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// bindStyles 各方言的位置参数格式，未列出的方言（MySQL/SQLite/ClickHouse 等）直接使用 ?
+var bindStyles = map[string]func(n int) string{
+	"postgres":  func(n int) string { return "$" + strconv.Itoa(n) },
+	"sqlserver": func(n int) string { return "@p" + strconv.Itoa(n) },
+	"oracle":    func(n int) string { return ":" + strconv.Itoa(n) },
+}
+
+// Rebind 将SQL中的 ? 占位符改写为方言的位置参数: Postgres 为 $1，SQLServer 为 @p1，Oracle 为 :1
+// 字符串、带引号的标识符和注释中的 ? 保持不变；?? 输出为字面量 ?，用于 Postgres 的 jsonb ? 运算符
+func Rebind(dialect, sqlStr string) string {
+	style, ok := bindStyles[strings.ToLower(dialect)]
+	if !strings.Contains(sqlStr, "?") || (!ok && !strings.Contains(sqlStr, "??")) {
+		return sqlStr
+	}
+
+	var (
+		out strings.Builder
+		n   int
+	)
+	out.Grow(len(sqlStr) + 8)
+	for i := 0; i < len(sqlStr); i++ {
+		c := sqlStr[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(sqlStr, i, c)
+			out.WriteString(sqlStr[i:end])
+			i = end - 1
+		case c == '-' && strings.HasPrefix(sqlStr[i:], "--"):
+			end := strings.IndexByte(sqlStr[i:], '\n')
+			if end < 0 {
+				end = len(sqlStr) - i
+			}
+			out.WriteString(sqlStr[i : i+end])
+			i += end - 1
+		case c == '/' && strings.HasPrefix(sqlStr[i:], "/*"):
+			end := strings.Index(sqlStr[i+2:], "*/")
+			if end < 0 {
+				end = len(sqlStr) - i
+			} else {
+				end += 4
+			}
+			out.WriteString(sqlStr[i : i+end])
+			i += end - 1
+		case c == '?' && i+1 < len(sqlStr) && sqlStr[i+1] == '?':
+			out.WriteByte('?')
+			i++
+		case c == '?' && ok:
+			n++
+			out.WriteString(style(n))
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// skipQuoted 返回从 start 处引号开始的字面量结束位置，引号重复（”）视为转义
+func skipQuoted(sqlStr string, start int, quote byte) int {
+	for i := start + 1; i < len(sqlStr); i++ {
+		if sqlStr[i] != quote {
+			continue
+		}
+		if i+1 < len(sqlStr) && sqlStr[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sqlStr)
+}
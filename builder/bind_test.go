@@ -0,0 +1,29 @@
+package builder
+
+import "testing"
+
+// 测试占位符改写
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+		want    string
+	}{
+		{"postgres", "SELECT * FROM users WHERE id = ? AND name = ?", "SELECT * FROM users WHERE id = $1 AND name = $2"},
+		{"sqlserver", "UPDATE users SET name = ? WHERE id = ?", "UPDATE users SET name = @p1 WHERE id = @p2"},
+		{"oracle", "DELETE FROM users WHERE id = ?", "DELETE FROM users WHERE id = :1"},
+		{"mysql", "SELECT * FROM users WHERE id = ?", "SELECT * FROM users WHERE id = ?"},
+		{"sqlite3", "SELECT ? FROM t", "SELECT ? FROM t"},
+		// 字面量、引号标识符和注释中的 ? 不改写
+		{"postgres", "SELECT '?', \"a?\", 'it''s ?' FROM t WHERE x = ?", "SELECT '?', \"a?\", 'it''s ?' FROM t WHERE x = $1"},
+		{"postgres", "SELECT 1 -- why?\nFROM t /* ? */ WHERE x = ?", "SELECT 1 -- why?\nFROM t /* ? */ WHERE x = $1"},
+		// ?? 转义为字面量 ?
+		{"postgres", "SELECT * FROM t WHERE tags ?? ? AND id = ?", "SELECT * FROM t WHERE tags ? $1 AND id = $2"},
+		{"mysql", "SELECT ?? , ?", "SELECT ? , ?"},
+	}
+	for _, tt := range tests {
+		if got := Rebind(tt.dialect, tt.sql); got != tt.want {
+			t.Errorf("Rebind(%s, %q) = %q, 期望 %q", tt.dialect, tt.sql, got, tt.want)
+		}
+	}
+}
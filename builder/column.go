@@ -0,0 +1,32 @@
+package builder
+
+// Table 表名，gen/model 开启 ColumnConstants 时为每个模型生成，如 UserTable
+type Table string
+
+// Column 列名，gen/model 开启 ColumnConstants 时为每个模型生成，如 UserCols.Username；
+// 表结构变化后重新生成，引用已删除列的代码在编译时报错
+type Column string
+
+// String 返回表名
+func (t Table) String() string {
+	return string(t)
+}
+
+// String 返回列名
+func (c Column) String() string {
+	return string(c)
+}
+
+// Of 返回以表名或别名限定的列名，如 UserCols.ID.Of("u") 为 u.id
+func (c Column) Of(table string) Column {
+	return Column(table + "." + string(c))
+}
+
+// ColumnNames 将列名转换为字符串，用于接收 []string 的方法
+func ColumnNames(columns ...Column) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = string(column)
+	}
+	return names
+}
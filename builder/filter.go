@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidFilter 过滤条件的字段名、操作符或值不合法
+var ErrInvalidFilter = errors.New("过滤条件不合法")
+
+// filterFieldRegex 过滤条件允许的字段名：标识符或 表名.标识符
+var filterFieldRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// WhereMap 将 map 形式的过滤条件转换为参数化条件，键为 "字段 操作符"，省略操作符时为等于（值为切片时为 IN）
+// 支持的操作符：= != <> > >= < <= like, not like, in, not in, between, not between；
+// 值为 nil 时 = 和 != 分别生成 IS NULL 和 IS NOT NULL，in 的值为空切片时不匹配任何行，between 的值为两个元素的切片
+// allowed 不为空时只允许其中的字段；字段名、操作符或值不合法时记录 ErrInvalidFilter，条件按字段名排序后生成
+// 示例: WhereMap(map[string]interface{}{"age >=": 18, "name like": "A%", "status in": []int{1, 2}}, "age", "name", "status")
+func (w *Where) WhereMap(filters map[string]interface{}, allowed ...string) *Where {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, op, err := parseFilterKey(key, allowed)
+		if err == nil {
+			err = w.filter(field, op, filters[key])
+		}
+		if err != nil {
+			if w.err == nil {
+				w.err = err
+			}
+			return w
+		}
+	}
+	return w
+}
+
+// WhereMapIf 条件性添加 map 形式的过滤条件
+// 示例: WhereMapIf(len(filters) > 0, filters, "age", "name")
+func (w *Where) WhereMapIf(condition bool, filters map[string]interface{}, allowed ...string) *Where {
+	if condition {
+		return w.WhereMap(filters, allowed...)
+	}
+	return w
+}
+
+// parseFilterKey 拆分过滤条件键为字段名和小写操作符
+func parseFilterKey(key string, allowed []string) (string, string, error) {
+	parts := strings.Fields(key)
+	if len(parts) == 0 {
+		return "", "", fmt.Errorf("%w: 字段名为空", ErrInvalidFilter)
+	}
+	field := parts[0]
+	op := strings.ToLower(strings.Join(parts[1:], " "))
+	if op == "" {
+		op = "="
+	}
+	if !filterFieldRegex.MatchString(field) {
+		return "", "", fmt.Errorf("%w: 字段名 %q", ErrInvalidFilter, field)
+	}
+	if len(allowed) > 0 {
+		permitted := false
+		for _, name := range allowed {
+			if strings.EqualFold(name, field) {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return "", "", fmt.Errorf("%w: 不允许按字段 %s 过滤", ErrInvalidFilter, field)
+		}
+	}
+	return field, op, nil
+}
+
+// filter 按操作符添加单个过滤条件
+func (w *Where) filter(field, op string, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	isSlice := value != nil && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8
+
+	switch op {
+	case "=", "!=", "<>":
+		if value == nil {
+			if op == "=" {
+				w.where(field + " IS NULL")
+			} else {
+				w.where(field + " IS NOT NULL")
+			}
+			return nil
+		}
+		if isSlice {
+			if op == "=" {
+				return w.filter(field, "in", value)
+			}
+			return w.filter(field, "not in", value)
+		}
+		w.where(fmt.Sprintf("%s %s ?", field, op), value)
+	case ">", ">=", "<", "<=":
+		if value == nil || isSlice {
+			return fmt.Errorf("%w: %s %s 的值必须是单个值", ErrInvalidFilter, field, op)
+		}
+		w.where(fmt.Sprintf("%s %s ?", field, op), value)
+	case "like", "not like":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%w: %s %s 的值必须是字符串", ErrInvalidFilter, field, op)
+		}
+		w.where(fmt.Sprintf("%s %s ?", field, strings.ToUpper(op)), value)
+	case "in", "not in":
+		if !isSlice {
+			return fmt.Errorf("%w: %s %s 的值必须是切片", ErrInvalidFilter, field, op)
+		}
+		if rv.Len() == 0 {
+			if op == "in" {
+				w.where("1 = 0")
+			}
+			return nil
+		}
+		args := make([]interface{}, rv.Len())
+		for i := range args {
+			args[i] = rv.Index(i).Interface()
+		}
+		w.in(field, args, op == "not in")
+	case "between", "not between":
+		if !isSlice || rv.Len() != 2 {
+			return fmt.Errorf("%w: %s %s 的值必须是两个元素的切片", ErrInvalidFilter, field, op)
+		}
+		w.where(fmt.Sprintf("%s %s ? AND ?", field, strings.ToUpper(op)), rv.Index(0).Interface(), rv.Index(1).Interface())
+	default:
+		return fmt.Errorf("%w: 不支持的操作符 %q", ErrInvalidFilter, op)
+	}
+	return nil
+}
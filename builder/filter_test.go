@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// 测试 map 形式的过滤条件
+func TestWhereMap(t *testing.T) {
+	w := NewWhere().WhereMap(map[string]interface{}{
+		"age >=":            18,
+		"name like":         "A%",
+		"status in":         []int{1, 2},
+		"deleted_at":        nil,
+		"role !=":           "guest",
+		"type":              []string{"a", "b"},
+		"score between":     []float64{1.5, 9},
+		"u.id not in":       []int64{7},
+		"nick NOT LIKE":     "%bot",
+		"removed_at <>":     nil,
+		"tags in":           []int{},
+		"blocked not in":    []int{},
+		"created_at <":      "2024-01-01",
+		"level not between": [2]int{3, 4},
+	})
+	if err := w.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	query, args := w.Build()
+	expected := "age >= ? AND created_at < ? AND deleted_at IS NULL AND level NOT BETWEEN ? AND ? AND name LIKE ? AND nick NOT LIKE ? AND removed_at IS NOT NULL AND role != ? AND score BETWEEN ? AND ? AND status IN (?, ?) AND 1 = 0 AND type IN (?, ?) AND u.id NOT IN (?)"
+	if query != expected {
+		t.Errorf("query = %s", query)
+	}
+	expectedArgs := []interface{}{18, "2024-01-01", 3, 4, "A%", "%bot", "guest", 1.5, 9.0, 1, 2, "a", "b", int64(7)}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("args = %v", args)
+	}
+}
+
+// 测试不合法的过滤条件
+func TestWhereMapInvalid(t *testing.T) {
+	cases := []map[string]interface{}{
+		{"age; DROP TABLE users --": 1},
+		{"age >= 1 OR 1": 1},
+		{"age ==": 1},
+		{"age >": []int{1}},
+		{"name like": 1},
+		{"status in": 1},
+		{"age between": []int{1}},
+		{"": 1},
+		{"password": "x"},
+	}
+	for _, filters := range cases {
+		w := NewWhere().WhereMap(filters, "age", "name", "status")
+		if !errors.Is(w.Err(), ErrInvalidFilter) {
+			t.Errorf("WhereMap(%v) Err() = %v", filters, w.Err())
+		}
+		if !w.IsEmpty() {
+			t.Errorf("WhereMap(%v) 生成了条件 %v", filters, w.GetWheres())
+		}
+	}
+
+	// 允许的字段不区分大小写
+	if err := NewWhere().WhereMap(map[string]interface{}{"Age >": 1}, "age").Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if w := NewWhere().WhereMapIf(false, map[string]interface{}{"bad key!": 1}); w.Err() != nil || !w.IsEmpty() {
+		t.Error("条件为 false 时不应添加条件")
+	}
+}
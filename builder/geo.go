@@ -0,0 +1,37 @@
+package builder
+
+import "fmt"
+
+// DistanceSQL 返回空间列到坐标 (lat, lng) 的球面距离（米）表达式及参数
+// 支持 MySQL 8、MariaDB、PostGIS 和 SQLServer geography，其他方言返回错误
+func DistanceSQL(dialect, column string, lat, lng float64) (string, []interface{}, error) {
+	switch dialect {
+	case "mysql":
+		// 参考点使用与列相同的 SRID，避免 SRID 不一致的错误
+		return fmt.Sprintf("ST_Distance_Sphere(%s, ST_SRID(POINT(?, ?), ST_SRID(%s)))", column, column), []interface{}{lng, lat}, nil
+	case "mariadb", "oceanbase":
+		return fmt.Sprintf("ST_Distance_Sphere(%s, POINT(?, ?))", column), []interface{}{lng, lat}, nil
+	case "postgres":
+		return fmt.Sprintf("ST_Distance(%s::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography)", column), []interface{}{lng, lat}, nil
+	case "sqlserver":
+		return fmt.Sprintf("%s.STDistance(geography::Point(?, ?, 4326))", column), []interface{}{lat, lng}, nil
+	}
+	return "", nil, fmt.Errorf("%s 不支持空间距离查询", dialect)
+}
+
+// WhereWithinRadius 添加距离条件: 空间列与坐标 (lat, lng) 的球面距离不超过 meters 米
+// 需先通过 Dialect 设置方言；PostGIS 使用 ST_DWithin 以便利用空间索引
+// 示例: Dialect("mysql").WhereWithinRadius("location", 39.908, 116.397, 500)
+func (w *Where) WhereWithinRadius(column string, lat, lng, meters float64) *Where {
+	if w.dialect == "postgres" {
+		return w.where(fmt.Sprintf("ST_DWithin(%s::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)", column), lng, lat, meters)
+	}
+	expr, args, err := DistanceSQL(w.dialect, column, lat, lng)
+	if err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return w
+	}
+	return w.where(expr+" <= ?", append(args, meters)...)
+}
@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 测试空间距离条件
+func TestWhereWithinRadius(t *testing.T) {
+	tests := []struct {
+		dialect string
+		want    string
+		args    []interface{}
+	}{
+		{"mysql", "ST_Distance_Sphere(location, ST_SRID(POINT(?, ?), ST_SRID(location))) <= ?", []interface{}{116.397, 39.908, 500.0}},
+		{"mariadb", "ST_Distance_Sphere(location, POINT(?, ?)) <= ?", []interface{}{116.397, 39.908, 500.0}},
+		{"postgres", "ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)", []interface{}{116.397, 39.908, 500.0}},
+		{"sqlserver", "location.STDistance(geography::Point(?, ?, 4326)) <= ?", []interface{}{39.908, 116.397, 500.0}},
+	}
+	for _, tt := range tests {
+		w := NewWhere().Dialect(tt.dialect).WhereWithinRadius("location", 39.908, 116.397, 500)
+		sqlStr, args := w.Build()
+		if sqlStr != tt.want || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: 得到 %q %v, 期望 %q %v", tt.dialect, sqlStr, args, tt.want, tt.args)
+		}
+	}
+
+	w := NewWhere().Dialect("sqlite3").WhereWithinRadius("location", 39.908, 116.397, 500)
+	if w.Err() == nil {
+		t.Error("sqlite3 应返回不支持错误")
+	}
+}
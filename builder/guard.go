@@ -0,0 +1,110 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// GuardMode SQL注入防护模式
+type GuardMode int
+
+// 防护模式常量
+const (
+	GuardOff    GuardMode = iota // 关闭检查
+	GuardWarn                    // 仅记录日志
+	GuardStrict                  // 拒绝执行
+)
+
+// ErrUnsafeSQL 检测到未参数化字面量时返回的错误
+var ErrUnsafeSQL = errors.New("检测到未参数化的字面量")
+
+// literalRegex 匹配单引号包裹的字符串字面量（支持连续两个单引号的转义）
+var literalRegex = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// guard 全局防护配置
+var guard = struct {
+	mutex   sync.RWMutex
+	mode    GuardMode
+	allowed map[string]bool
+	logger  func(format string, args ...interface{})
+}{
+	mode:    GuardOff,
+	allowed: make(map[string]bool),
+	logger:  log.Printf,
+}
+
+// SetGuardMode 设置防护模式
+func SetGuardMode(mode GuardMode) {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	guard.mode = mode
+}
+
+// GetGuardMode 获取防护模式
+func GetGuardMode() GuardMode {
+	guard.mutex.RLock()
+	defer guard.mutex.RUnlock()
+	return guard.mode
+}
+
+// SetGuardLogger 设置违规日志输出函数，传入nil时恢复为标准日志
+func SetGuardLogger(logger func(format string, args ...interface{})) {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	if logger == nil {
+		logger = log.Printf
+	}
+	guard.logger = logger
+}
+
+// AllowLiterals 将字面量加入白名单（如日期格式 '%Y-%m-%d'），需包含引号
+// 示例: AllowLiterals("'%Y-%m-%d'", "'YYYY-MM-DD'")
+func AllowLiterals(literals ...string) {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+	for _, literal := range literals {
+		guard.allowed[literal] = true
+	}
+}
+
+// FindLiterals 查找SQL片段中未在白名单内的字符串字面量
+func FindLiterals(sql string) []string {
+	guard.mutex.RLock()
+	defer guard.mutex.RUnlock()
+
+	var literals []string
+	for _, literal := range literalRegex.FindAllString(sql, -1) {
+		if !guard.allowed[literal] {
+			literals = append(literals, literal)
+		}
+	}
+	return literals
+}
+
+// Inspect 按当前防护模式检查SQL片段
+// 关闭模式下直接返回nil；警告模式记录日志后返回nil；严格模式返回 ErrUnsafeSQL
+func Inspect(sql string) error {
+	mode := GetGuardMode()
+	if mode == GuardOff || sql == "" {
+		return nil
+	}
+
+	literals := FindLiterals(sql)
+	if len(literals) == 0 {
+		return nil
+	}
+
+	guard.mutex.RLock()
+	logger := guard.logger
+	guard.mutex.RUnlock()
+	logger("[gosqlx] 未参数化的字面量 %s: %s", strings.Join(literals, ", "), sql)
+
+	if mode == GuardStrict {
+		return fmt.Errorf("%w: %s", ErrUnsafeSQL, strings.Join(literals, ", "))
+	}
+	return nil
+}
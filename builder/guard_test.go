@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+// 测试查找字符串字面量
+func TestFindLiterals(t *testing.T) {
+	literals := FindLiterals("name = 'admin' AND note = 'it''s' AND id = ?")
+	if len(literals) != 2 {
+		t.Fatalf("期望找到 2 个字面量，实际为 %d: %v", len(literals), literals)
+	}
+	if literals[0] != "'admin'" || literals[1] != "'it''s'" {
+		t.Errorf("字面量不符合预期: %v", literals)
+	}
+
+	if literals := FindLiterals("id = ? AND status IN (?, ?)"); len(literals) != 0 {
+		t.Errorf("参数化条件不应包含字面量，实际为 %v", literals)
+	}
+}
+
+// 测试严格模式
+func TestGuardStrict(t *testing.T) {
+	SetGuardMode(GuardStrict)
+	SetGuardLogger(func(format string, args ...interface{}) {})
+	defer SetGuardMode(GuardOff)
+	defer SetGuardLogger(nil)
+
+	w := NewWhere()
+	w.Where("id = ?", 1)
+	if w.Err() != nil {
+		t.Errorf("参数化条件不应报错，实际为 %v", w.Err())
+	}
+
+	w.WhereRaw("name = 'admin'")
+	if !errors.Is(w.Err(), ErrUnsafeSQL) {
+		t.Errorf("期望错误为 ErrUnsafeSQL，实际为 %v", w.Err())
+	}
+
+	// 内部生成的格式化条件不做检查
+	w2 := NewWhere()
+	DateFormat(w2, "created_at", "%Y-%m-%d", "2023-01-01")
+	if w2.Err() != nil {
+		t.Errorf("DateFormat 不应报错，实际为 %v", w2.Err())
+	}
+
+	// 子条件组中的错误需要向上传递
+	w3 := NewWhere()
+	w3.Group(func(sub *Where) { sub.Where("type = 'x'") })
+	if w3.Err() == nil {
+		t.Error("期望条件组返回错误")
+	}
+}
+
+// 测试警告模式
+func TestGuardWarn(t *testing.T) {
+	var logged int
+	SetGuardMode(GuardWarn)
+	SetGuardLogger(func(format string, args ...interface{}) { logged++ })
+	defer SetGuardMode(GuardOff)
+	defer SetGuardLogger(nil)
+
+	if err := Inspect("name = 'admin'"); err != nil {
+		t.Errorf("警告模式不应返回错误，实际为 %v", err)
+	}
+	if logged != 1 {
+		t.Errorf("期望记录 1 条日志，实际为 %d", logged)
+	}
+
+	AllowLiterals("'%Y-%m-%d'")
+	if err := Inspect("DATE_FORMAT(created_at, '%Y-%m-%d') = ?"); err != nil || logged != 1 {
+		t.Errorf("白名单字面量不应记录日志，err=%v logged=%d", err, logged)
+	}
+}
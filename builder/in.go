@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// InStrategy IN 条件超过上限时的处理方式
+type InStrategy int
+
+const (
+	// InChunkOr 拆分为多个 IN 分组并以 OR 连接（NOT IN 以 AND 连接）
+	InChunkOr InStrategy = iota
+	// InJSON 将全部值序列化为一个 JSON 参数，由数据库展开为结果集后关联
+	// SQLServer 使用 OPENJSON，SQLite 使用 json_each，只占用一个参数
+	InJSON
+)
+
+// InLimit IN 条件的元素上限及超限处理方式
+type InLimit struct {
+	Size     int        // 单个 IN 列表的最大元素数，<=0 表示不限制
+	Strategy InStrategy // 超过上限时的处理方式
+}
+
+// DefaultInLimit 未配置方言时使用的上限
+var DefaultInLimit = InLimit{Size: 1000, Strategy: InChunkOr}
+
+var (
+	inLimitMutex sync.RWMutex
+	inLimits     = map[string]InLimit{
+		"oracle":     {Size: 1000, Strategy: InChunkOr},  // ORA-01795
+		"sqlserver":  {Size: 2000, Strategy: InJSON},     // 单条语句最多 2100 个参数
+		"sqlite":     {Size: 999, Strategy: InJSON},      // 旧版本默认最多 999 个参数
+		"sqlite3":    {Size: 999, Strategy: InJSON},      // 同上
+		"mysql":      {Size: 10000, Strategy: InChunkOr}, // 单条语句最多 65535 个参数
+		"mariadb":    {Size: 10000, Strategy: InChunkOr},
+		"tidb":       {Size: 10000, Strategy: InChunkOr},
+		"oceanbase":  {Size: 10000, Strategy: InChunkOr},
+		"postgres":   {Size: 10000, Strategy: InChunkOr},
+		"clickhouse": {Size: 0},
+	}
+)
+
+// SetInLimit 设置方言的 IN 条件上限
+// 示例: SetInLimit("postgres", InLimit{Size: 5000, Strategy: InChunkOr})
+func SetInLimit(dialect string, limit InLimit) {
+	inLimitMutex.Lock()
+	defer inLimitMutex.Unlock()
+	inLimits[strings.ToLower(dialect)] = limit
+}
+
+// GetInLimit 获取方言的 IN 条件上限，未配置时返回 DefaultInLimit
+func GetInLimit(dialect string) InLimit {
+	inLimitMutex.RLock()
+	defer inLimitMutex.RUnlock()
+	if limit, ok := inLimits[strings.ToLower(dialect)]; ok {
+		return limit
+	}
+	return DefaultInLimit
+}
+
+// Dialect 设置数据库方言，决定 WhereIn/WhereNotIn 超限时的处理方式
+func (w *Where) Dialect(name string) *Where {
+	w.dialect = strings.ToLower(name)
+	return w
+}
+
+// in 构建 IN/NOT IN 条件，超过方言上限时按策略拆分
+func (w *Where) in(field string, args []interface{}, not bool) *Where {
+	op, joiner := "IN", " OR "
+	if not {
+		op, joiner = "NOT IN", " AND "
+	}
+
+	limit := GetInLimit(w.dialect)
+	if limit.Size <= 0 || len(args) <= limit.Size {
+		return w.Where(fmt.Sprintf("%s %s (%s)", field, op, placeholders(len(args))), args...)
+	}
+
+	if limit.Strategy == InJSON {
+		if query, ok := jsonIn(w.dialect, field, op); ok {
+			data, err := json.Marshal(args)
+			if err != nil {
+				if w.err == nil {
+					w.err = fmt.Errorf("序列化IN条件失败: %w", err)
+				}
+				return w
+			}
+			return w.Where(query, string(data))
+		}
+	}
+
+	var groups []string
+	for start := 0; start < len(args); start += limit.Size {
+		end := min(start+limit.Size, len(args))
+		groups = append(groups, fmt.Sprintf("%s %s (%s)", field, op, placeholders(end-start)))
+	}
+	return w.Where("("+strings.Join(groups, joiner)+")", args...)
+}
+
+// jsonIn 生成使用 JSON 数组参数的 IN 子查询
+func jsonIn(dialect, field, op string) (string, bool) {
+	switch dialect {
+	case "sqlserver":
+		return fmt.Sprintf("%s %s (SELECT value FROM OPENJSON(?))", field, op), true
+	case "sqlite", "sqlite3":
+		return fmt.Sprintf("%s %s (SELECT value FROM json_each(?))", field, op), true
+	}
+	return "", false
+}
+
+// placeholders 生成 n 个以逗号分隔的占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
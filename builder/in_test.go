@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+// 测试IN条件超限拆分
+func TestWhereInChunk(t *testing.T) {
+	ids := make([]int, 2500)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	// Oracle 拆分为多个 IN 分组
+	query, args := NewWhere().Dialect("oracle").WhereIn("id", ids).Build()
+	if strings.Count(query, "id IN (") != 3 || strings.Count(query, " OR ") != 2 {
+		t.Errorf("期望拆分为 3 个 IN 分组，实际为 %s", query[:80])
+	}
+	if len(args) != 2500 {
+		t.Errorf("期望 2500 个参数，实际为 %d", len(args))
+	}
+
+	query, _ = NewWhere().Dialect("oracle").WhereNotIn("id", ids).Build()
+	if strings.Count(query, "id NOT IN (") != 3 || strings.Count(query, " AND ") != 2 {
+		t.Errorf("NOT IN 应以 AND 连接，实际为 %s", query[:80])
+	}
+
+	// SQLServer 使用 JSON 参数
+	query, args = NewWhere().Dialect("sqlserver").WhereIn("id", ids).Build()
+	if query != "id IN (SELECT value FROM OPENJSON(?))" || len(args) != 1 {
+		t.Errorf("SQLServer IN 条件不符合预期: %s", query)
+	}
+
+	// 未超限时保持原样，子条件组继承方言
+	query, _ = NewWhere().Dialect("oracle").Group(func(w *Where) { w.WhereIn("id", ids[:1500]) }).Build()
+	if !strings.HasPrefix(query, "((id IN (") {
+		t.Errorf("条件组中的IN条件应被拆分，实际为 %s", query[:40])
+	}
+	query, _ = NewWhere().WhereIn("id", []int{1, 2, 3}).Build()
+	if query != "id IN (?, ?, ?)" {
+		t.Errorf("期望 id IN (?, ?, ?)，实际为 %s", query)
+	}
+
+	SetInLimit("custom", InLimit{Size: 2})
+	query, _ = NewWhere().Dialect("custom").WhereIn("id", []int{1, 2, 3}).Build()
+	if query != "(id IN (?, ?) OR id IN (?))" {
+		t.Errorf("自定义上限未生效: %s", query)
+	}
+}
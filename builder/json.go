@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JSONColumn JSON 聚合的子查询结果列
+type JSONColumn struct {
+	Key  string // 结果列名，即 JSON 对象的键
+	JSON bool   // 列值本身是 JSON（如嵌套的聚合），嵌入为 JSON 而不是字符串
+}
+
+// JSONAggSQL 返回将子查询 sub 的所有行聚合为 JSON 对象数组的标量子查询表达式，没有行时为 []
+// PostgreSQL 使用 json_agg，MySQL 系使用 JSON_ARRAYAGG（MySQL 8.0.14+ 支持子查询引用外层的列），
+// SQLite 使用 json_group_array，SQLServer 使用 FOR JSON PATH；
+// MySQL 系和 SQLite 按 columns 构造 JSON 对象，PostgreSQL 和 SQLServer 直接使用子查询的结果列，可传 nil
+//
+//	JSONAggSQL("postgres", "SELECT id, amount FROM orders WHERE orders.user_id = u.id", nil)
+//	// COALESCE((SELECT json_agg(t) FROM (SELECT id, amount FROM orders WHERE orders.user_id = u.id) AS t), '[]'::json)
+func JSONAggSQL(dialect, sub string, columns []JSONColumn) (string, error) {
+	switch dialect {
+	case "postgres", "postgresql":
+		return fmt.Sprintf("COALESCE((SELECT json_agg(t) FROM (%s) AS t), '[]'::json)", sub), nil
+	case "sqlserver", "mssql":
+		return fmt.Sprintf("JSON_QUERY(COALESCE((%s FOR JSON PATH, INCLUDE_NULL_VALUES), '[]'))", sub), nil
+	}
+	object, err := jsonObjectSQL(dialect, columns)
+	if err != nil {
+		return "", err
+	}
+	switch dialect {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		return fmt.Sprintf("(SELECT COALESCE(JSON_ARRAYAGG(%s), JSON_ARRAY()) FROM (%s) AS t)", object, sub), nil
+	}
+	return fmt.Sprintf("(SELECT json_group_array(%s) FROM (%s) AS t)", object, sub), nil
+}
+
+// JSONObjectSQL 返回将子查询 sub 的第一行转换为 JSON 对象的标量子查询表达式，没有行时为 NULL，
+// 用于一对一或多对一的关联（如订单的收货地址）；方言和 columns 的规则同 JSONAggSQL
+func JSONObjectSQL(dialect, sub string, columns []JSONColumn) (string, error) {
+	switch dialect {
+	case "postgres", "postgresql":
+		return fmt.Sprintf("(SELECT row_to_json(t) FROM (%s) AS t LIMIT 1)", sub), nil
+	case "sqlserver", "mssql":
+		return fmt.Sprintf("JSON_QUERY((%s FOR JSON PATH, WITHOUT_ARRAY_WRAPPER, INCLUDE_NULL_VALUES))", sub), nil
+	}
+	object, err := jsonObjectSQL(dialect, columns)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(SELECT %s FROM (%s) AS t LIMIT 1)", object, sub), nil
+}
+
+// jsonObjectSQL 返回以派生表 t 的列构造 JSON 对象的表达式
+func jsonObjectSQL(dialect string, columns []JSONColumn) (string, error) {
+	var quote func(string) string
+	switch dialect {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		quote = func(name string) string { return "`" + strings.ReplaceAll(name, "`", "``") + "`" }
+	case "sqlite", "sqlite3":
+		quote = func(name string) string { return `"` + strings.ReplaceAll(name, `"`, `""`) + `"` }
+	default:
+		return "", fmt.Errorf("%s 不支持 JSON 聚合", dialect)
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("%s 的 JSON 聚合需要子查询的结果列名", dialect)
+	}
+	pairs := make([]string, len(columns))
+	for i, column := range columns {
+		value := "t." + quote(column.Key)
+		if column.JSON && strings.HasPrefix(dialect, "sqlite") {
+			value = "json(" + value + ")" // SQLite 子查询的结果失去 JSON 类型，需重新解析
+		}
+		pairs[i] = "'" + strings.ReplaceAll(column.Key, "'", "''") + "', " + value
+	}
+	return "JSON_OBJECT(" + strings.Join(pairs, ", ") + ")", nil
+}
@@ -0,0 +1,33 @@
+package builder
+
+import "testing"
+
+// 测试 JSON 聚合和单行 JSON 对象表达式
+func TestJSONAggSQL(t *testing.T) {
+	columns := []JSONColumn{{Key: "id"}, {Key: "items", JSON: true}}
+	tests := []struct {
+		name string
+		fn   func(dialect, sub string, columns []JSONColumn) (string, error)
+		dia  string
+		want string
+	}{
+		{"agg", JSONAggSQL, "sqlite", `(SELECT json_group_array(JSON_OBJECT('id', t."id", 'items', json(t."items"))) FROM (SELECT id, items FROM orders) AS t)`},
+		{"agg", JSONAggSQL, "mariadb", "(SELECT COALESCE(JSON_ARRAYAGG(JSON_OBJECT('id', t.`id`, 'items', t.`items`)), JSON_ARRAY()) FROM (SELECT id, items FROM orders) AS t)"},
+		{"object", JSONObjectSQL, "postgres", "(SELECT row_to_json(t) FROM (SELECT id, items FROM orders) AS t LIMIT 1)"},
+		{"object", JSONObjectSQL, "mysql", "(SELECT JSON_OBJECT('id', t.`id`, 'items', t.`items`) FROM (SELECT id, items FROM orders) AS t LIMIT 1)"},
+		{"object", JSONObjectSQL, "sqlserver", "JSON_QUERY((SELECT id, items FROM orders FOR JSON PATH, WITHOUT_ARRAY_WRAPPER, INCLUDE_NULL_VALUES))"},
+	}
+	for _, tt := range tests {
+		got, err := tt.fn(tt.dia, "SELECT id, items FROM orders", columns)
+		if err != nil || got != tt.want {
+			t.Errorf("%s %s: 得到 %q %v, 期望 %q", tt.name, tt.dia, got, err, tt.want)
+		}
+	}
+
+	if _, err := JSONAggSQL("mysql", "SELECT * FROM orders", nil); err == nil {
+		t.Error("MySQL 没有结果列名时应返回错误")
+	}
+	if _, err := JSONAggSQL("oracle", "SELECT id FROM orders", columns); err == nil {
+		t.Error("oracle 应返回不支持错误")
+	}
+}
@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -173,3 +174,56 @@ func (o *Order) GetOrderBy(prefix bool) string {
 func (o *Order) Build() string {
 	return o.String()
 }
+
+// ErrInvalidSort 排序参数中的字段不在白名单内或方向不合法
+var ErrInvalidSort = errors.New("排序参数不合法")
+
+// SafeOrder 按白名单校验外部传入的排序参数并生成排序语句（不含 ORDER BY）
+// input 为逗号分隔的排序项，每项为 "字段"、"-字段"（降序）、"+字段"、"字段 desc" 或 "字段:asc"；
+// allowed 将外部字段名映射为列名或表达式，映射值为空时使用字段名本身；重复的字段只取第一次
+// 示例: SafeOrder("-created_at,name", map[string]string{"created_at": "u.created_at", "name": ""})
+func SafeOrder(input string, allowed map[string]string) (string, error) {
+	var (
+		orders []string
+		seen   = make(map[string]bool)
+	)
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		field, direction := item, "ASC"
+		switch {
+		case strings.HasPrefix(item, "-"):
+			field, direction = item[1:], "DESC"
+		case strings.HasPrefix(item, "+"):
+			field = item[1:]
+		default:
+			if i := strings.IndexAny(item, " :"); i >= 0 {
+				field = item[:i]
+				switch strings.ToUpper(strings.TrimSpace(item[i+1:])) {
+				case "ASC":
+				case "DESC":
+					direction = "DESC"
+				default:
+					return "", fmt.Errorf("%w: 排序方向 %q", ErrInvalidSort, item[i+1:])
+				}
+			}
+		}
+
+		column, ok := allowed[field]
+		if !ok {
+			return "", fmt.Errorf("%w: 不允许按 %q 排序", ErrInvalidSort, field)
+		}
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		if column == "" {
+			column = field
+		}
+		orders = append(orders, column+" "+direction)
+	}
+	return strings.Join(orders, ", "), nil
+}
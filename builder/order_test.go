@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+// 测试按白名单生成排序语句
+func TestSafeOrder(t *testing.T) {
+	allowed := map[string]string{"created": "u.created_at", "name": "", "id": "u.id"}
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"name", "name ASC"},
+		{"-created, name", "u.created_at DESC, name ASC"},
+		{"+id,created desc", "u.id ASC, u.created_at DESC"},
+		{"name:DESC,name,-id", "name DESC, u.id DESC"},
+		{" id ASC ,", "u.id ASC"},
+	}
+	for _, c := range cases {
+		got, err := SafeOrder(c.input, allowed)
+		if err != nil || got != c.want {
+			t.Errorf("SafeOrder(%q) = %q, %v，期望 %q", c.input, got, err, c.want)
+		}
+	}
+
+	for _, input := range []string{
+		"password",
+		"name; DROP TABLE users",
+		"name desc, (SELECT 1)",
+		"name DESC NULLS FIRST",
+		"name asc--",
+		"Name",
+		"-",
+	} {
+		if got, err := SafeOrder(input, allowed); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("SafeOrder(%q) = %q, %v，期望 ErrInvalidSort", input, got, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package builder
+
+import "fmt"
+
+// NewUUIDSQL 返回在服务端生成 UUID 的表达式，生成值的形式与 uuid.UUID 在该数据库上的列类型一致，
+// 可用作列默认值或 INSERT 中的值：MySQL/TiDB 为 UUID_TO_BIN(UUID())，MariaDB/OceanBase 为去掉连字符的 UUID() 经 UNHEX 转换，
+// PostgreSQL/DuckDB 为 gen_random_uuid()，Oracle 为 SYS_GUID()，ClickHouse 为 generateUUIDv4()；
+// SQLServer 为 NEWID()，生成 uniqueidentifier，用于 uuid.GUID 列；SQLite 没有内置的 UUID 函数，返回错误
+func NewUUIDSQL(dialect string) (string, error) {
+	switch dialect {
+	case "mysql", "tidb":
+		return "UUID_TO_BIN(UUID())", nil
+	case "mariadb", "oceanbase":
+		return "UNHEX(REPLACE(UUID(), '-', ''))", nil
+	case "postgres", "duckdb":
+		return "gen_random_uuid()", nil
+	case "sqlserver":
+		return "NEWID()", nil
+	case "oracle":
+		return "SYS_GUID()", nil
+	case "clickhouse":
+		return "generateUUIDv4()", nil
+	}
+	return "", fmt.Errorf("%s 不支持在服务端生成 UUID", dialect)
+}
+
+// UUIDFromTextSQL 返回将 UUID 文本表达式 expr（如占位符 ?）转换为 uuid.UUID 列存储形式的表达式，
+// 用于原生SQL中以文本形式的 UUID 作为条件或写入值，如 WHERE id = UUID_TO_BIN(?)
+func UUIDFromTextSQL(dialect, expr string) (string, error) {
+	switch dialect {
+	case "mysql", "tidb":
+		return fmt.Sprintf("UUID_TO_BIN(%s)", expr), nil
+	case "mariadb", "oceanbase":
+		return fmt.Sprintf("UNHEX(REPLACE(%s, '-', ''))", expr), nil
+	case "postgres", "duckdb":
+		return fmt.Sprintf("CAST(%s AS uuid)", expr), nil
+	case "sqlserver":
+		return fmt.Sprintf("CONVERT(BINARY(16), REPLACE(%s, '-', ''), 2)", expr), nil
+	case "oracle":
+		return fmt.Sprintf("HEXTORAW(REPLACE(%s, '-', ''))", expr), nil
+	case "clickhouse":
+		return fmt.Sprintf("toUUID(%s)", expr), nil
+	case "sqlite3":
+		return fmt.Sprintf("unhex(replace(%s, '-', ''))", expr), nil
+	}
+	return "", fmt.Errorf("%s 不支持 UUID 转换", dialect)
+}
@@ -0,0 +1,28 @@
+package builder
+
+import "testing"
+
+// 测试服务端生成 UUID 和文本转换表达式
+func TestUUIDSQL(t *testing.T) {
+	tests := []struct {
+		dialect, generate, fromText string
+	}{
+		{"mysql", "UUID_TO_BIN(UUID())", "UUID_TO_BIN(?)"},
+		{"mariadb", "UNHEX(REPLACE(UUID(), '-', ''))", "UNHEX(REPLACE(?, '-', ''))"},
+		{"postgres", "gen_random_uuid()", "CAST(? AS uuid)"},
+		{"sqlserver", "NEWID()", "CONVERT(BINARY(16), REPLACE(?, '-', ''), 2)"},
+		{"oracle", "SYS_GUID()", "HEXTORAW(REPLACE(?, '-', ''))"},
+		{"clickhouse", "generateUUIDv4()", "toUUID(?)"},
+	}
+	for _, tt := range tests {
+		if got, err := NewUUIDSQL(tt.dialect); got != tt.generate || err != nil {
+			t.Errorf("NewUUIDSQL(%s) = %q, %v", tt.dialect, got, err)
+		}
+		if got, err := UUIDFromTextSQL(tt.dialect, "?"); got != tt.fromText || err != nil {
+			t.Errorf("UUIDFromTextSQL(%s) = %q, %v", tt.dialect, got, err)
+		}
+	}
+	if _, err := NewUUIDSQL("sqlite3"); err == nil {
+		t.Error("sqlite3 应返回不支持错误")
+	}
+}
@@ -8,8 +8,10 @@ import (
 
 // Where 条件构建器
 type Where struct {
-	wheres []string      // 条件语句
-	values []interface{} // 参数值
+	wheres  []string      // 条件语句
+	values  []interface{} // 参数值
+	err     error         // 防护检查错误
+	dialect string        // 数据库方言
 }
 
 // NewWhere 创建新的条件构建器
@@ -23,6 +25,12 @@ func NewWhere() *Where {
 // Where 添加条件
 // 示例: Where("id = ?", 1)
 func (w *Where) Where(query string, args ...interface{}) *Where {
+	w.inspect(query)
+	return w.where(query, args...)
+}
+
+// where 添加条件，不做防护检查（供内部生成的条件使用）
+func (w *Where) where(query string, args ...interface{}) *Where {
 	if query != "" {
 		w.wheres = append(w.wheres, query)
 		w.values = append(w.values, args...)
@@ -30,10 +38,23 @@ func (w *Where) Where(query string, args ...interface{}) *Where {
 	return w
 }
 
+// inspect 检查条件中的未参数化字面量，记录首个错误
+func (w *Where) inspect(query string) {
+	if err := Inspect(query); err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+// Err 获取防护检查错误
+func (w *Where) Err() error {
+	return w.err
+}
+
 // WhereIf 条件性添加条件
 // 示例: WhereIf(id > 0, "id = ?", id)
 func (w *Where) WhereIf(condition bool, query string, args ...interface{}) *Where {
 	if condition && query != "" {
+		w.inspect(query)
 		w.wheres = append(w.wheres, query)
 		w.values = append(w.values, args...)
 	}
@@ -56,6 +77,7 @@ func (w *Where) AndIf(condition bool, query string, args ...interface{}) *Where
 // 示例: Or("status = ?", 1)
 func (w *Where) Or(query string, args ...interface{}) *Where {
 	if query != "" {
+		w.inspect(query)
 		if len(w.wheres) > 0 {
 			lastIndex := len(w.wheres) - 1
 			w.wheres[lastIndex] = fmt.Sprintf("(%s) OR (%s)", w.wheres[lastIndex], query)
@@ -95,17 +117,13 @@ func (w *Where) WhereIn(field string, values interface{}) *Where {
 		return w
 	}
 
-	// 构建占位符
-	var placeholders []string
-	var args []interface{}
-
-	for i := 0; i < rv.Len(); i++ {
-		placeholders = append(placeholders, "?")
-		args = append(args, rv.Index(i).Interface())
+	args := make([]interface{}, rv.Len())
+	for i := range args {
+		args[i] = rv.Index(i).Interface()
 	}
 
-	query := fmt.Sprintf("%s IN (%s)", field, strings.Join(placeholders, ", "))
-	return w.Where(query, args...)
+	// 超过方言上限时自动拆分
+	return w.in(field, args, false)
 }
 
 // WhereInIf 条件性添加IN条件
@@ -135,17 +153,13 @@ func (w *Where) WhereNotIn(field string, values interface{}) *Where {
 		return w
 	}
 
-	// 构建占位符
-	var placeholders []string
-	var args []interface{}
-
-	for i := 0; i < rv.Len(); i++ {
-		placeholders = append(placeholders, "?")
-		args = append(args, rv.Index(i).Interface())
+	args := make([]interface{}, rv.Len())
+	for i := range args {
+		args[i] = rv.Index(i).Interface()
 	}
 
-	query := fmt.Sprintf("%s NOT IN (%s)", field, strings.Join(placeholders, ", "))
-	return w.Where(query, args...)
+	// 超过方言上限时自动拆分
+	return w.in(field, args, true)
 }
 
 // WhereNotInIf 条件性添加NOT IN条件
@@ -344,7 +358,11 @@ func (w *Where) Group(fn func(*Where)) *Where {
 
 	// 创建子条件构建器
 	subWhere := NewWhere()
+	subWhere.dialect = w.dialect
 	fn(subWhere)
+	if subWhere.err != nil && w.err == nil {
+		w.err = subWhere.err
+	}
 
 	// 如果子条件为空，直接返回
 	if len(subWhere.wheres) == 0 {
@@ -383,7 +401,11 @@ func (w *Where) OrGroup(fn func(*Where)) *Where {
 
 	// 创建子条件构建器
 	subWhere := NewWhere()
+	subWhere.dialect = w.dialect
 	fn(subWhere)
+	if subWhere.err != nil && w.err == nil {
+		w.err = subWhere.err
+	}
 
 	// 如果子条件为空，直接返回
 	if len(subWhere.wheres) == 0 {
@@ -424,6 +446,7 @@ func (w *Where) OrGroupIf(condition bool, fn func(*Where)) *Where {
 func (w *Where) Clear() *Where {
 	w.wheres = make([]string, 0)
 	w.values = make([]interface{}, 0)
+	w.err = nil
 	return w
 }
 
@@ -466,7 +489,7 @@ func DateFormat(w *Where, field string, format string, value interface{}) *Where
 	}
 
 	query := fmt.Sprintf("DATE_FORMAT(%s, '%s') = ?", field, format)
-	return w.Where(query, value)
+	return w.where(query, value)
 }
 
 // ToChar 添加 PostgresSQL 特定的日期格式化条件
@@ -477,5 +500,5 @@ func ToChar(w *Where, field string, format string, value interface{}) *Where {
 	}
 
 	query := fmt.Sprintf("TO_CHAR(%s, '%s') = ?", field, format)
-	return w.Where(query, value)
+	return w.where(query, value)
 }
@@ -0,0 +1,149 @@
+// Package cdc 变更数据捕获：从数据库复制流中读取行变更事件并分发给回调
+//
+// 内置 Postgres 逻辑复制数据源（test_decoding 插件，无需额外依赖）。
+// MySQL/MariaDB binlog 数据源基于 go-mysql，位于独立模块 github.com/gzorm/gosqlx/cdc/mysql，
+// 只有使用时才引入该依赖；其他数据源实现 Source 接口后即可复用分发与模型映射逻辑。
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// Operation 变更类型
+type Operation string
+
+// 变更类型常量
+const (
+	Insert Operation = "INSERT"
+	Update Operation = "UPDATE"
+	Delete Operation = "DELETE"
+)
+
+// Event 行变更事件
+type Event struct {
+	Schema    string                 // 模式/数据库名
+	Table     string                 // 表名
+	Op        Operation              // 变更类型
+	Before    map[string]interface{} // 变更前数据（更新、删除时可用，取决于复制标识配置）
+	After     map[string]interface{} // 变更后数据（插入、更新时可用）
+	Position  string                 // 复制位置（Postgres 为 LSN，MySQL 为 文件名:偏移）
+	Timestamp time.Time              // 事件接收时间
+}
+
+// DecodeBefore 将变更前数据映射到模型结构体
+func (e *Event) DecodeBefore(dest interface{}) error {
+	return decode(e.Before, dest)
+}
+
+// DecodeAfter 将变更后数据映射到模型结构体
+func (e *Event) DecodeAfter(dest interface{}) error {
+	return decode(e.After, dest)
+}
+
+// Handler 事件处理函数，返回错误时停止订阅且不确认该位置
+type Handler func(ctx context.Context, event *Event) error
+
+// Source 变更事件数据源
+type Source interface {
+	// Stream 持续读取事件并交给 handle 处理，handle 成功后确认位置；ctx 取消时返回
+	Stream(ctx context.Context, handle func(*Event) error) error
+	// Close 关闭数据源
+	Close() error
+}
+
+// Subscriber 变更事件订阅器
+type Subscriber struct {
+	source   Source
+	mutex    sync.RWMutex
+	handlers map[string][]Handler // 表名 -> 处理函数，"*" 表示全部表
+}
+
+// NewSubscriber 创建变更事件订阅器
+func NewSubscriber(source Source) *Subscriber {
+	return &Subscriber{
+		source:   source,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// On 订阅指定表的变更事件，表名可带模式前缀（如 public.users）
+func (s *Subscriber) On(table string, handler Handler) *Subscriber {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	table = strings.ToLower(table)
+	s.handlers[table] = append(s.handlers[table], handler)
+	return s
+}
+
+// OnAll 订阅全部表的变更事件
+func (s *Subscriber) OnAll(handler Handler) *Subscriber {
+	return s.On("*", handler)
+}
+
+// Run 启动订阅，阻塞直到 ctx 取消或处理函数返回错误
+func (s *Subscriber) Run(ctx context.Context) error {
+	return s.source.Stream(ctx, func(event *Event) error {
+		return s.dispatch(ctx, event)
+	})
+}
+
+// Close 关闭订阅
+func (s *Subscriber) Close() error {
+	return s.source.Close()
+}
+
+// dispatch 分发事件
+func (s *Subscriber) dispatch(ctx context.Context, event *Event) error {
+	s.mutex.RLock()
+	var handlers []Handler
+	handlers = append(handlers, s.handlers[strings.ToLower(event.Table)]...)
+	if event.Schema != "" {
+		handlers = append(handlers, s.handlers[strings.ToLower(event.Schema+"."+event.Table)]...)
+	}
+	handlers = append(handlers, s.handlers["*"]...)
+	s.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("处理 %s.%s %s 事件失败: %w", event.Schema, event.Table, event.Op, err)
+		}
+	}
+	return nil
+}
+
+// schemaCache 模型结构缓存
+var schemaCache = &sync.Map{}
+
+// decode 按列名将数据映射到结构体字段（使用gorm的列名规则）
+func decode(data map[string]interface{}, dest interface{}) error {
+	if data == nil {
+		return fmt.Errorf("事件中没有可用数据")
+	}
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("目标必须为非空结构体指针")
+	}
+
+	sch, err := schema.Parse(dest, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return fmt.Errorf("解析模型失败: %w", err)
+	}
+	ctx := context.Background()
+	for column, v := range data {
+		field := sch.LookUpField(column)
+		if field == nil {
+			continue
+		}
+		if err := field.Set(ctx, value.Elem(), v); err != nil {
+			return fmt.Errorf("设置字段 %s 失败: %w", field.Name, err)
+		}
+	}
+	return nil
+}
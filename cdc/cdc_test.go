@@ -0,0 +1,100 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// 测试解析 test_decoding 输出
+func TestParseTestDecoding(t *testing.T) {
+	event, err := ParseTestDecoding("table public.users: INSERT: id[integer]:1 name[character varying]:'O''Brien' score[double precision]:9.5 active[boolean]:true tags[text[]]:'{a,b}' note[text]:null")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if event.Schema != "public" || event.Table != "users" || event.Op != Insert {
+		t.Errorf("事件头不符合预期: %+v", event)
+	}
+	after := event.After
+	if after["id"] != int64(1) || after["name"] != "O'Brien" || after["score"] != 9.5 ||
+		after["active"] != true || after["tags"] != "{a,b}" || after["note"] != nil {
+		t.Errorf("数据不符合预期: %#v", after)
+	}
+
+	event, err = ParseTestDecoding("table public.users: UPDATE: old-key: id[integer]:1 new-tuple: id[integer]:2 name[text]:'tom jerry'")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if event.Before["id"] != int64(1) || event.After["id"] != int64(2) || event.After["name"] != "tom jerry" {
+		t.Errorf("更新事件不符合预期: %+v", event)
+	}
+
+	event, err = ParseTestDecoding("table public.users: DELETE: id[integer]:3")
+	if err != nil || event.Op != Delete || event.Before["id"] != int64(3) || event.After != nil {
+		t.Errorf("删除事件不符合预期: %+v, err=%v", event, err)
+	}
+
+	if event, _ := ParseTestDecoding("BEGIN 529"); event != nil {
+		t.Errorf("BEGIN 不应生成事件")
+	}
+}
+
+type cdcUser struct {
+	ID   int64  `gorm:"column:id;primaryKey"`
+	Name string `gorm:"column:name"`
+}
+
+// fakeSource 测试用数据源
+type fakeSource []*Event
+
+func (f fakeSource) Stream(ctx context.Context, handle func(*Event) error) error {
+	for _, event := range f {
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f fakeSource) Close() error { return nil }
+
+// 测试事件分发与模型映射
+func TestSubscriber(t *testing.T) {
+	source := fakeSource{
+		{Schema: "public", Table: "users", Op: Insert, After: map[string]interface{}{"id": int64(1), "name": "tom"}},
+		{Schema: "public", Table: "orders", Op: Delete, Before: map[string]interface{}{"id": int64(9)}},
+	}
+
+	var users []cdcUser
+	var all int
+	err := NewSubscriber(source).
+		On("public.users", func(ctx context.Context, event *Event) error {
+			var user cdcUser
+			if err := event.DecodeAfter(&user); err != nil {
+				return err
+			}
+			users = append(users, user)
+			return nil
+		}).
+		OnAll(func(ctx context.Context, event *Event) error {
+			all++
+			return nil
+		}).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("订阅失败: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != 1 || users[0].Name != "tom" {
+		t.Errorf("模型映射不符合预期: %+v", users)
+	}
+	if all != 2 {
+		t.Errorf("期望全部事件数为 2，实际为 %d", all)
+	}
+
+	// 处理失败时停止
+	stop := errors.New("stop")
+	err = NewSubscriber(source).OnAll(func(ctx context.Context, event *Event) error { return stop }).Run(context.Background())
+	if !errors.Is(err, stop) {
+		t.Errorf("期望返回处理错误，实际为 %v", err)
+	}
+}
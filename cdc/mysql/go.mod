@@ -0,0 +1,11 @@
+module github.com/gzorm/gosqlx/cdc/mysql
+
+go 1.24.3
+
+require (
+	github.com/go-mysql-org/go-mysql v1.13.0
+	github.com/go-sql-driver/mysql v1.7.1
+	github.com/gzorm/gosqlx v0.0.0
+)
+
+replace github.com/gzorm/gosqlx => ../..
@@ -0,0 +1,272 @@
+// Package mysql 基于 go-mysql 复制客户端的 MySQL/MariaDB binlog 变更数据源
+//
+// 作为独立模块发布，只有使用 MySQL binlog 的项目才引入 go-mysql 依赖:
+//
+//	source, err := mysql.NewSource(db, mysql.Options{Position: saved})
+//	sub := cdc.NewSubscriber(source).On("shop.orders", onOrder)
+//	err = sub.Run(ctx)
+//	saved = source.Position()
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/cdc"
+)
+
+// Options MySQL binlog 复制选项
+type Options struct {
+	ServerID  uint32        // 复制客户端的 server_id，需与复制拓扑中的其他实例不同，默认 1001
+	Position  string        // 起始位置，格式为 文件名:偏移（如 mysql-bin.000003:4），为空时从当前位置开始
+	Heartbeat time.Duration // 服务端心跳间隔，超过两个间隔未收到数据视为连接断开，默认 30 秒
+}
+
+// Source 基于 binlog 复制的 MySQL/MariaDB 数据源，实现 cdc.Source
+// 需要 binlog_format=ROW（完整的变更前数据需要 binlog_row_image=FULL），连接账号需要 REPLICATION SLAVE
+// 和 REPLICATION CLIENT 权限；列名从 information_schema 读取，DDL 后重新读取
+// 只在事务提交后推进 Position，处理失败后以 Position 重新订阅时从该事务开头重新投递（至少一次语义）
+type Source struct {
+	db      *gosqlx.Database
+	config  replication.BinlogSyncerConfig
+	options Options
+
+	mutex    sync.Mutex
+	syncer   *replication.BinlogSyncer
+	position string // 最近提交的事务之后的位置
+}
+
+// NewSource 创建 MySQL binlog 数据源，连接参数取自 db 的 DSN
+func NewSource(db *gosqlx.Database, options Options) (*Source, error) {
+	if db == nil || (db.Type() != gosqlx.MySQL && db.Type() != gosqlx.MariaDB) {
+		return nil, fmt.Errorf("binlog 复制仅支持 MySQL 和 MariaDB 数据库")
+	}
+	dsn, err := mysqldriver.ParseDSN(db.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("解析连接字符串失败: %w", err)
+	}
+	if dsn.Net != "tcp" {
+		return nil, fmt.Errorf("binlog 复制仅支持 TCP 连接")
+	}
+	host, portText, err := net.SplitHostPort(dsn.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("解析连接地址失败: %w", err)
+	}
+	port, err := strconv.ParseUint(portText, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("解析连接端口失败: %w", err)
+	}
+	if options.Position != "" {
+		if _, _, err := parsePosition(options.Position); err != nil {
+			return nil, err
+		}
+	}
+	if options.ServerID == 0 {
+		options.ServerID = 1001
+	}
+	if options.Heartbeat <= 0 {
+		options.Heartbeat = 30 * time.Second
+	}
+
+	config := replication.BinlogSyncerConfig{
+		ServerID:        options.ServerID,
+		Flavor:          gomysql.MySQLFlavor,
+		Host:            host,
+		Port:            uint16(port),
+		User:            dsn.User,
+		Password:        dsn.Passwd,
+		TLSConfig:       dsn.TLS,
+		HeartbeatPeriod: options.Heartbeat,
+		ReadTimeout:     2 * options.Heartbeat,
+		ParseTime:       true,
+	}
+	if db.Type() == gosqlx.MariaDB {
+		config.Flavor = gomysql.MariaDBFlavor
+	}
+	return &Source{db: db, config: config, options: options, position: options.Position}, nil
+}
+
+// Position 返回最近提交的事务之后的 binlog 位置，保存后作为 Options.Position 续传
+func (s *Source) Position() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.position
+}
+
+// Stream 持续读取 binlog 中的行变更
+func (s *Source) Stream(ctx context.Context, handle func(*cdc.Event) error) error {
+	file, pos, err := s.startPosition(ctx)
+	if err != nil {
+		return err
+	}
+
+	syncer := replication.NewBinlogSyncer(s.config)
+	s.mutex.Lock()
+	s.syncer = syncer
+	s.mutex.Unlock()
+	defer s.Close()
+
+	streamer, err := syncer.StartSync(gomysql.Position{Name: file, Pos: pos})
+	if err != nil {
+		return fmt.Errorf("请求 binlog 失败: %w", err)
+	}
+
+	columns := make(map[string][]columnInfo) // 表 -> 列信息，缓存到下一条 DDL
+	for {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return ctxErr(ctx, fmt.Errorf("读取 binlog 失败: %w", err))
+		}
+		switch e := ev.Event.(type) {
+		case *replication.RotateEvent:
+			file = string(e.NextLogName)
+		case *replication.XIDEvent:
+			s.commit(formatPosition(file, ev.Header.LogPos))
+		case *replication.QueryEvent:
+			switch strings.ToUpper(strings.TrimSpace(string(e.Query))) {
+			case "BEGIN":
+				continue
+			case "COMMIT":
+			default:
+				// DDL 隐式提交并可能改变列，清空列缓存
+				clear(columns)
+			}
+			s.commit(formatPosition(file, ev.Header.LogPos))
+		case *replication.RowsEvent:
+			op, ok := operation(ev.Header.EventType)
+			if !ok {
+				continue
+			}
+			key := string(e.Table.Schema) + "." + string(e.Table.Table)
+			tableColumns, ok := columns[key]
+			if !ok {
+				if tableColumns, err = s.loadColumns(ctx, string(e.Table.Schema), string(e.Table.Table)); err != nil {
+					return err
+				}
+				columns[key] = tableColumns
+			}
+			for _, event := range rowEvents(op, e, tableColumns) {
+				event.Position = formatPosition(file, ev.Header.LogPos)
+				if err := handle(event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Close 关闭复制连接（数据库连接由调用方管理）
+func (s *Source) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.syncer != nil {
+		s.syncer.Close()
+		s.syncer = nil
+	}
+	return nil
+}
+
+// commit 记录事务提交之后的位置
+func (s *Source) commit(position string) {
+	s.mutex.Lock()
+	s.position = position
+	s.mutex.Unlock()
+}
+
+// startPosition 返回起始位置，未指定时读取服务端当前位置
+func (s *Source) startPosition(ctx context.Context) (string, uint32, error) {
+	if position := s.Position(); position != "" {
+		return parsePosition(position)
+	}
+	db := s.db.DB().WithContext(ctx)
+	// MySQL 8.4 移除了 SHOW MASTER STATUS
+	for _, statement := range []string{"SHOW MASTER STATUS", "SHOW BINARY LOG STATUS"} {
+		rows, err := db.Raw(statement).Rows()
+		if err != nil {
+			continue
+		}
+		columns, _ := rows.Columns()
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		var file string
+		var pos uint64
+		if rows.Next() && len(columns) >= 2 {
+			if err := rows.Scan(dest...); err == nil {
+				file = asString(values[0])
+				pos, _ = strconv.ParseUint(asString(values[1]), 10, 32)
+			}
+		}
+		rows.Close()
+		if file == "" {
+			return "", 0, fmt.Errorf("未开启 binlog")
+		}
+		return file, uint32(pos), nil
+	}
+	return "", 0, fmt.Errorf("读取 binlog 位置失败")
+}
+
+// loadColumns 从 information_schema 读取表的列
+func (s *Source) loadColumns(ctx context.Context, schema, table string) ([]columnInfo, error) {
+	rows, err := s.db.DB().WithContext(ctx).Raw(
+		"SELECT COLUMN_NAME, DATA_TYPE, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+		schema, table).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s.%s 的列失败: %w", schema, table, err)
+	}
+	defer rows.Close()
+	var columns []columnInfo
+	for rows.Next() {
+		var name, dataType, columnType string
+		if err := rows.Scan(&name, &dataType, &columnType); err != nil {
+			return nil, err
+		}
+		columns = append(columns, newColumnInfo(name, dataType, columnType))
+	}
+	return columns, rows.Err()
+}
+
+// parsePosition 解析 文件名:偏移 格式的位置
+func parsePosition(position string) (string, uint32, error) {
+	idx := strings.LastIndex(position, ":")
+	if idx <= 0 {
+		return "", 0, fmt.Errorf("无效的 binlog 位置: %s", position)
+	}
+	pos, err := strconv.ParseUint(position[idx+1:], 10, 32)
+	if err != nil {
+		return "", 0, fmt.Errorf("无效的 binlog 位置: %s", position)
+	}
+	return position[:idx], uint32(pos), nil
+}
+
+// formatPosition 格式化位置
+func formatPosition(file string, pos uint32) string {
+	return file + ":" + strconv.FormatUint(uint64(pos), 10)
+}
+
+// asString 将扫描得到的值转换为字符串
+func asString(value interface{}) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// ctxErr ctx 已取消时返回 ctx 的错误
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
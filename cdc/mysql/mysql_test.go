@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/gzorm/gosqlx/cdc"
+)
+
+// 测试将行事件转换为变更事件
+func TestRowEvents(t *testing.T) {
+	columns := []columnInfo{
+		newColumnInfo("id", "int", "int(10) unsigned"),
+		newColumnInfo("name", "varchar", "varchar(255)"),
+		newColumnInfo("price", "decimal", "decimal(10,2)"),
+		newColumnInfo("created_at", "datetime", "datetime"),
+		newColumnInfo("doc", "json", "json"),
+		newColumnInfo("status", "enum", "enum('active','disabled')"),
+	}
+	table := &replication.TableMapEvent{Schema: []byte("shop"), Table: []byte("users"), ColumnCount: 6}
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	insert := &replication.RowsEvent{Table: table, Rows: [][]interface{}{
+		{int32(-1), "tom", "1234.56", createdAt, []byte(`{"a":1}`), int64(2)},
+	}}
+	events := rowEvents(cdc.Insert, insert, columns)
+	if len(events) != 1 {
+		t.Fatalf("期望一个事件，实际为 %d", len(events))
+	}
+	want := map[string]interface{}{
+		"id":         uint64(4294967295),
+		"name":       "tom",
+		"price":      "1234.56",
+		"created_at": createdAt,
+		"doc":        `{"a":1}`,
+		"status":     "disabled",
+	}
+	if event := events[0]; event.Op != cdc.Insert || event.Schema != "shop" || event.Table != "users" || event.Before != nil {
+		t.Errorf("事件头不符合预期: %+v", event)
+	}
+	if !reflect.DeepEqual(events[0].After, want) {
+		t.Errorf("插入数据不符合预期:\n%#v\n%#v", events[0].After, want)
+	}
+
+	// 部分行镜像的更新只记录了 id 和 name
+	skipped := []int{2, 3, 4, 5}
+	update := &replication.RowsEvent{
+		Table: table,
+		Rows: [][]interface{}{
+			{int32(7), "tom", nil, nil, nil, nil},
+			{int32(7), "jerry", nil, nil, nil, nil},
+		},
+		SkippedColumns: [][]int{skipped, skipped},
+	}
+	events = rowEvents(cdc.Update, update, columns)
+	if len(events) != 1 || events[0].Before["name"] != "tom" || events[0].After["name"] != "jerry" || len(events[0].After) != 2 {
+		t.Errorf("更新事件不符合预期: %+v", events)
+	}
+
+	// 表结构变化后列数不一致时以序号命名
+	remove := &replication.RowsEvent{Table: table, Rows: [][]interface{}{{int32(7), "tom", nil, nil, nil, int64(1)}}}
+	events = rowEvents(cdc.Delete, remove, columns[:2])
+	if len(events) != 1 || events[0].Before["@1"] != int64(7) || events[0].Before["@6"] != int64(1) || events[0].Before["@3"] != nil {
+		t.Errorf("删除事件不符合预期: %+v", events)
+	}
+}
+
+// 测试按列信息转换值
+func TestConvertValue(t *testing.T) {
+	cases := []struct {
+		value  interface{}
+		column columnInfo
+		want   interface{}
+	}{
+		{int8(-1), columnInfo{}, int64(-1)},
+		{int8(-1), columnInfo{unsigned: true}, uint64(255)},
+		{int32(-2), newColumnInfo("n", "mediumint", "mediumint(8) unsigned"), uint64(0xfffffe)},
+		{int64(-1), columnInfo{unsigned: true}, uint64(18446744073709551615)},
+		{float32(1.5), columnInfo{}, float64(1.5)},
+		{int64(5), newColumnInfo("s", "set", "set('a','b','c')"), "a,c"},
+		{int64(0), newColumnInfo("e", "enum", "enum('a')"), ""},
+		{[]byte("hi"), newColumnInfo("t", "text", "text"), "hi"},
+		{[]byte("hi"), newColumnInfo("b", "blob", "blob"), []byte("hi")},
+		{time.Unix(10, 0).In(time.FixedZone("CST", 8*3600)), columnInfo{}, time.Unix(10, 0).UTC()},
+	}
+	for _, c := range cases {
+		if got := convertValue(c.value, c.column); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("转换 %#v: 期望 %#v，实际为 %#v", c.value, c.want, got)
+		}
+	}
+	if elements := parseElements("enum('it''s','b')"); !reflect.DeepEqual(elements, []string{"it's", "b"}) {
+		t.Errorf("解析取值失败: %q", elements)
+	}
+}
+
+// 测试解析 binlog 位置
+func TestParsePosition(t *testing.T) {
+	file, pos, err := parsePosition("mysql-bin.000003:1024")
+	if err != nil || file != "mysql-bin.000003" || pos != 1024 {
+		t.Errorf("解析位置失败: %s %d %v", file, pos, err)
+	}
+	for _, position := range []string{"mysql-bin.000003", ":4", "mysql-bin.000003:x"} {
+		if _, _, err := parsePosition(position); err == nil {
+			t.Errorf("%q 应解析失败", position)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package mysql
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/gzorm/gosqlx/cdc"
+)
+
+// columnInfo information_schema 中的列信息
+type columnInfo struct {
+	name     string
+	dataType string   // 小写的 DATA_TYPE
+	text     bool     // 文本类型，值转换为字符串
+	unsigned bool     // 无符号整数
+	elements []string // ENUM/SET 的取值
+}
+
+// newColumnInfo 根据 DATA_TYPE 和 COLUMN_TYPE 创建列信息
+func newColumnInfo(name, dataType, columnType string) columnInfo {
+	column := columnInfo{
+		name:     name,
+		dataType: strings.ToLower(dataType),
+		unsigned: strings.Contains(strings.ToLower(columnType), "unsigned"),
+	}
+	switch column.dataType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "json":
+		column.text = true
+	case "enum", "set":
+		column.elements = parseElements(columnType)
+	}
+	return column
+}
+
+// parseElements 解析 enum('a','b') 中的取值
+func parseElements(columnType string) []string {
+	start, end := strings.Index(columnType, "("), strings.LastIndex(columnType, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+	var elements []string
+	s := columnType[start+1 : end]
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		var b strings.Builder
+		for i++; i < len(s); i++ {
+			if s[i] == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				break
+			}
+			b.WriteByte(s[i])
+		}
+		elements = append(elements, b.String())
+	}
+	return elements
+}
+
+// operation 返回行事件的变更类型
+func operation(eventType replication.EventType) (cdc.Operation, bool) {
+	switch eventType {
+	case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+		return cdc.Insert, true
+	case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+		return cdc.Update, true
+	case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+		return cdc.Delete, true
+	}
+	return "", false
+}
+
+// rowEvents 将行事件转换为变更事件，更新事件的行按 变更前、变更后 成对出现
+// 列数与 binlog 不一致时（表结构已变化）以 @序号 命名
+func rowEvents(op cdc.Operation, e *replication.RowsEvent, columns []columnInfo) []*cdc.Event {
+	schema, table := string(e.Table.Schema), string(e.Table.Table)
+	if len(columns) != int(e.Table.ColumnCount) {
+		columns = make([]columnInfo, e.Table.ColumnCount)
+		for i := range columns {
+			columns[i].name = "@" + strconv.Itoa(i+1)
+		}
+	}
+	row := func(i int) map[string]interface{} {
+		var skipped []int
+		if i < len(e.SkippedColumns) {
+			skipped = e.SkippedColumns[i]
+		}
+		return convertRow(e.Rows[i], skipped, columns)
+	}
+
+	var events []*cdc.Event
+	for i := 0; i < len(e.Rows); i++ {
+		event := &cdc.Event{Schema: schema, Table: table, Op: op, Timestamp: time.Now()}
+		switch op {
+		case cdc.Update:
+			if i+1 >= len(e.Rows) {
+				return events
+			}
+			event.Before, event.After = row(i), row(i+1)
+			i++
+		case cdc.Insert:
+			event.After = row(i)
+		default:
+			event.Before = row(i)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// convertRow 将一行转换为列名到值的映射，skipped 为部分行镜像（binlog_row_image=MINIMAL）中未记录的列
+func convertRow(values []interface{}, skipped []int, columns []columnInfo) map[string]interface{} {
+	row := make(map[string]interface{}, len(values)-len(skipped))
+	for i, value := range values {
+		if i >= len(columns) {
+			break
+		}
+		row[columns[i].name] = convertValue(value, columns[i])
+	}
+	for _, i := range skipped {
+		if i < len(columns) {
+			delete(row, columns[i].name)
+		}
+	}
+	return row
+}
+
+// convertValue 按列信息转换 go-mysql 解析出的值：整数统一为 int64（无符号列为 uint64），
+// 文本列的 []byte 转换为字符串，ENUM/SET 转换为取值，时间转换为 UTC
+func convertValue(value interface{}, column columnInfo) interface{} {
+	switch v := value.(type) {
+	case int8:
+		if column.unsigned {
+			return uint64(uint8(v))
+		}
+		return int64(v)
+	case int16:
+		if column.unsigned {
+			return uint64(uint16(v))
+		}
+		return int64(v)
+	case int32:
+		if column.unsigned && column.dataType == "mediumint" {
+			return uint64(uint32(v) & 0xffffff)
+		}
+		if column.unsigned {
+			return uint64(uint32(v))
+		}
+		return int64(v)
+	case int64:
+		switch {
+		case column.dataType == "enum":
+			return enumValue(column, uint64(v))
+		case column.dataType == "set":
+			return setValue(column, uint64(v))
+		case column.unsigned:
+			return uint64(v)
+		}
+		return v
+	case int:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []byte:
+		if column.text {
+			return string(v)
+		}
+	case time.Time:
+		return v.UTC()
+	}
+	return value
+}
+
+// enumValue 将 ENUM 的序号转换为取值，序号从 1 开始
+func enumValue(column columnInfo, index uint64) interface{} {
+	if index == 0 {
+		return ""
+	}
+	if int(index) <= len(column.elements) {
+		return column.elements[index-1]
+	}
+	return int64(index)
+}
+
+// setValue 将 SET 的位图转换为逗号分隔的取值
+func setValue(column columnInfo, bits uint64) interface{} {
+	if column.elements == nil {
+		return bits
+	}
+	var values []string
+	for i, element := range column.elements {
+		if bits&(1<<uint(i)) != 0 {
+			values = append(values, element)
+		}
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,322 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// PostgresOptions Postgres 逻辑复制选项
+type PostgresOptions struct {
+	Slot         string        // 复制槽名称
+	CreateSlot   bool          // 复制槽不存在时自动创建（test_decoding 插件）
+	PollInterval time.Duration // 无变更时的轮询间隔
+	BatchSize    int           // 每次最多读取的变更数
+}
+
+// PostgresSource 基于逻辑复制槽的 Postgres 数据源
+// 需要 wal_level=logical；如需更新/删除的完整变更前数据，表需设置 REPLICA IDENTITY FULL
+// 事务提交后才确认位置，处理失败时从上次提交处重新投递（至少一次语义）
+type PostgresSource struct {
+	db      *gosqlx.Database
+	options PostgresOptions
+}
+
+// NewPostgresSource 创建 Postgres 逻辑复制数据源
+func NewPostgresSource(db *gosqlx.Database, options PostgresOptions) (*PostgresSource, error) {
+	if db == nil || db.Type() != gosqlx.PostgresSQL {
+		return nil, fmt.Errorf("逻辑复制仅支持 Postgres 数据库")
+	}
+	if options.Slot == "" {
+		return nil, fmt.Errorf("复制槽名称不能为空")
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	return &PostgresSource{db: db, options: options}, nil
+}
+
+// Stream 持续读取复制槽中的变更
+func (p *PostgresSource) Stream(ctx context.Context, handle func(*Event) error) error {
+	if p.options.CreateSlot {
+		if err := p.ensureSlot(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		count, err := p.poll(ctx, handle)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.options.PollInterval):
+		}
+	}
+}
+
+// Close 关闭数据源（数据库连接由调用方管理）
+func (p *PostgresSource) Close() error {
+	return nil
+}
+
+// DropSlot 删除复制槽，不再订阅时调用以免WAL堆积
+func (p *PostgresSource) DropSlot(ctx context.Context) error {
+	return p.db.DB().WithContext(ctx).Exec("SELECT pg_drop_replication_slot(?)", p.options.Slot).Error
+}
+
+// ensureSlot 创建复制槽
+func (p *PostgresSource) ensureSlot(ctx context.Context) error {
+	var count int64
+	db := p.db.DB().WithContext(ctx)
+	if err := db.Raw("SELECT COUNT(*) FROM pg_replication_slots WHERE slot_name = ?", p.options.Slot).Scan(&count).Error; err != nil {
+		return fmt.Errorf("查询复制槽失败: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if err := db.Exec("SELECT pg_create_logical_replication_slot(?, 'test_decoding')", p.options.Slot).Error; err != nil {
+		return fmt.Errorf("创建复制槽失败: %w", err)
+	}
+	return nil
+}
+
+// poll 读取一批变更并在事务提交处确认位置，返回读取的变更数
+func (p *PostgresSource) poll(ctx context.Context, handle func(*Event) error) (int, error) {
+	db := p.db.DB().WithContext(ctx)
+	rows, err := db.Raw("SELECT lsn::text, data FROM pg_logical_slot_peek_changes(?, NULL, ?)",
+		p.options.Slot, p.options.BatchSize).Rows()
+	if err != nil {
+		return 0, fmt.Errorf("读取复制槽失败: %w", err)
+	}
+
+	type change struct{ lsn, data string }
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.lsn, &c.data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var committed string
+	var handleErr error
+	for _, c := range changes {
+		if strings.HasPrefix(c.data, "COMMIT") {
+			committed = c.lsn
+			continue
+		}
+		event, err := ParseTestDecoding(c.data)
+		if err != nil {
+			handleErr = err
+			break
+		}
+		if event == nil {
+			continue
+		}
+		event.Position = c.lsn
+		if err := handle(event); err != nil {
+			handleErr = err
+			break
+		}
+	}
+
+	if committed != "" {
+		if err := db.Exec("SELECT pg_replication_slot_advance(?, ?::pg_lsn)", p.options.Slot, committed).Error; err != nil {
+			return 0, fmt.Errorf("确认复制位置失败: %w", err)
+		}
+	}
+	return len(changes), handleErr
+}
+
+// ParseTestDecoding 解析 test_decoding 插件输出的一行变更，BEGIN/COMMIT 返回nil
+// 格式示例: table public.users: UPDATE: old-key: id[integer]:1 new-tuple: id[integer]:1 name[text]:'tom'
+func ParseTestDecoding(data string) (*Event, error) {
+	if !strings.HasPrefix(data, "table ") {
+		return nil, nil
+	}
+
+	rest := strings.TrimPrefix(data, "table ")
+	idx := strings.Index(rest, ": ")
+	if idx < 0 {
+		return nil, fmt.Errorf("无效的变更数据: %s", data)
+	}
+	name := rest[:idx]
+	rest = rest[idx+2:]
+
+	idx = strings.Index(rest, ":")
+	if idx < 0 {
+		return nil, fmt.Errorf("无效的变更数据: %s", data)
+	}
+	event := &Event{Op: Operation(rest[:idx]), Timestamp: time.Now()}
+	rest = strings.TrimSpace(rest[idx+1:])
+
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		event.Schema, event.Table = unquoteIdent(name[:dot]), unquoteIdent(name[dot+1:])
+	} else {
+		event.Table = unquoteIdent(name)
+	}
+
+	if rest == "(no-tuple-data)" {
+		return event, nil
+	}
+
+	switch event.Op {
+	case Insert:
+		tuple, err := parseTuple(rest)
+		if err != nil {
+			return nil, err
+		}
+		event.After = tuple
+	case Update:
+		if strings.HasPrefix(rest, "old-key: ") {
+			parts := strings.SplitN(strings.TrimPrefix(rest, "old-key: "), " new-tuple: ", 2)
+			before, err := parseTuple(parts[0])
+			if err != nil {
+				return nil, err
+			}
+			event.Before = before
+			if len(parts) == 2 {
+				rest = parts[1]
+			} else {
+				rest = ""
+			}
+		}
+		after, err := parseTuple(rest)
+		if err != nil {
+			return nil, err
+		}
+		event.After = after
+	case Delete:
+		before, err := parseTuple(rest)
+		if err != nil {
+			return nil, err
+		}
+		event.Before = before
+	default:
+		return nil, fmt.Errorf("未知的变更类型: %s", event.Op)
+	}
+	return event, nil
+}
+
+// parseTuple 解析 列名[类型]:值 序列
+func parseTuple(s string) (map[string]interface{}, error) {
+	tuple := make(map[string]interface{})
+	for i := 0; i < len(s); {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+
+		// 列名
+		start := i
+		for i < len(s) && s[i] != '[' {
+			i++
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("无效的元组数据: %s", s)
+		}
+		column := unquoteIdent(s[start:i])
+
+		// 类型（可能包含数组括号）
+		depth := 0
+		start = i + 1
+		for ; i < len(s); i++ {
+			if s[i] == '[' {
+				depth++
+			} else if s[i] == ']' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		if i+1 >= len(s) || s[i+1] != ':' {
+			return nil, fmt.Errorf("无效的元组数据: %s", s)
+		}
+		typeName := s[start:i]
+		i += 2
+
+		// 值
+		var raw string
+		quoted := i < len(s) && s[i] == '\''
+		if quoted {
+			var b strings.Builder
+			for i++; i < len(s); i++ {
+				if s[i] == '\'' {
+					if i+1 < len(s) && s[i+1] == '\'' {
+						b.WriteByte('\'')
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(s[i])
+			}
+			raw = b.String()
+		} else {
+			start = i
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			raw = s[start:i]
+		}
+		tuple[column] = convertText(typeName, raw, quoted)
+	}
+	return tuple, nil
+}
+
+// convertText 按列类型转换文本值
+func convertText(typeName, raw string, quoted bool) interface{} {
+	if !quoted && raw == "null" {
+		return nil
+	}
+	switch typeName {
+	case "smallint", "integer", "bigint":
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return v
+		}
+	case "real", "double precision":
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		return raw == "true"
+	case "timestamp without time zone", "timestamp with time zone", "date":
+		for _, layout := range []string{"2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t
+			}
+		}
+	}
+	return raw
+}
+
+// unquoteIdent 去除标识符的双引号
+func unquoteIdent(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`)
+	}
+	return s
+}
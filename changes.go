@@ -0,0 +1,92 @@
+package gosqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 变更更新 ====================
+
+// FieldChange 单个字段的变更
+type FieldChange struct {
+	Field  string      // 结构体字段名
+	Column string      // 列名
+	Old    interface{} // 原值
+	New    interface{} // 新值
+}
+
+// Diff 按模型字段顺序比较两个同类型的结构体，返回取值不同的列
+// 跳过主键、不可更新的字段和自动更新时间字段；model 和 original 可以是结构体或结构体指针
+func (d *Database) Diff(model, original interface{}) ([]FieldChange, error) {
+	modelValue := reflect.Indirect(reflect.ValueOf(model))
+	originalValue := reflect.Indirect(reflect.ValueOf(original))
+	if modelValue.Kind() != reflect.Struct || originalValue.Kind() != reflect.Struct {
+		return nil, errors.New("比较的值必须是结构体或结构体指针")
+	}
+	if modelValue.Type() != originalValue.Type() {
+		return nil, fmt.Errorf("比较的值类型不同: %s 和 %s", modelValue.Type(), originalValue.Type())
+	}
+
+	stmt := &gorm.Statement{DB: d.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("解析模型失败: %w", err)
+	}
+	ctx := d.db.Statement.Context
+	var changes []FieldChange
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Updatable || field.AutoUpdateTime > 0 {
+			continue
+		}
+		newValue, _ := field.ValueOf(ctx, modelValue)
+		oldValue, _ := field.ValueOf(ctx, originalValue)
+		if fieldEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field.Name, Column: field.DBName, Old: oldValue, New: newValue})
+	}
+	return changes, nil
+}
+
+// UpdateChanged 只更新 model 相对 original 发生变化的列，没有变化时不执行语句，返回变更的字段
+// 与 Save 整行写回相比减少锁冲突，也不会覆盖其他请求同时修改的列；model 必须是结构体指针，按其主键更新
+//
+//	original := user
+//	user.Name = "alice"
+//	changes, err := db.UpdateChanged(&user, original) // UPDATE users SET name = ?, updated_at = ? WHERE id = ?
+func (d *Database) UpdateChanged(model, original interface{}) ([]FieldChange, error) {
+	if value := reflect.ValueOf(model); value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("model 必须是结构体指针")
+	}
+	changes, err := d.Diff(model, original)
+	if err != nil || len(changes) == 0 {
+		return changes, err
+	}
+	values := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		values[change.Column] = change.New
+	}
+	if err := d.Updates(model, values); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// fieldEqual 判断字段取值是否相同，时间按时刻比较
+func fieldEqual(a, b interface{}) bool {
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return ok && at.Equal(bt)
+	}
+	if at, ok := a.(*time.Time); ok {
+		bt, ok := b.(*time.Time)
+		if !ok || at == nil || bt == nil {
+			return ok && at == bt
+		}
+		return at.Equal(*bt)
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile 未指定 -config 时读取的配置文件，不存在时使用默认配置
+const defaultConfigFile = "gosqlx.yaml"
+
+// config 命令行配置，对应配置文件的结构
+type config struct {
+	Database databaseConfig `yaml:"database"`
+	Model    modelConfig    `yaml:"model"`
+	Doc      docConfig      `yaml:"doc"`
+	Migrate  migrateConfig  `yaml:"migrate"`
+	Diff     diffConfig     `yaml:"diff"`
+}
+
+// databaseConfig 数据库连接配置，设置 dsn 时 doc/migrate/diff 直接使用，否则由各字段拼接
+type databaseConfig struct {
+	Type     string `yaml:"type"`     // 数据库类型，与 gosqlx.DatabaseType 一致
+	DSN      string `yaml:"dsn"`      // 连接字符串
+	Host     string `yaml:"host"`     // 主机地址
+	Port     int    `yaml:"port"`     // 端口
+	User     string `yaml:"user"`     // 用户名
+	Password string `yaml:"password"` // 密码，建议写成 ${DB_PASSWORD} 从环境变量读取
+	Name     string `yaml:"name"`     // 数据库名，SQLite/DuckDB 为文件路径
+}
+
+// modelConfig 模型生成配置
+type modelConfig struct {
+	Output      string   `yaml:"output"`      // 输出目录
+	Package     string   `yaml:"package"`     // 包名
+	Split       bool     `yaml:"split"`       // 每个表生成单独的文件
+	Columns     bool     `yaml:"columns"`     // 生成表名和列名常量
+	Enums       bool     `yaml:"enums"`       // 为枚举列生成类型和常量
+	NullStyle   string   `yaml:"nullStyle"`   // 可为 NULL 的列的字段类型：pointer/sql/generic
+	Handlers    []string `yaml:"handlers"`    // 生成 CRUD HTTP 处理器的表，支持通配符
+	Tags        []string `yaml:"tags"`        // 结构体标签，如 json:camel
+	Include     []string `yaml:"include"`     // 只生成匹配的表
+	Exclude     []string `yaml:"exclude"`     // 排除匹配的表
+	Concurrency int      `yaml:"concurrency"` // 并发获取表结构的数量
+	Resume      string   `yaml:"resume"`      // 断点文件
+}
+
+// docConfig 文档生成配置
+type docConfig struct {
+	Output      string   `yaml:"output"`      // 输出文件，.xlsx 结尾时生成 Excel
+	Format      string   `yaml:"format"`      // word 或 excel，为空时按输出文件扩展名判断
+	Title       string   `yaml:"title"`       // 文档标题
+	Author      string   `yaml:"author"`      // 文档作者
+	Company     string   `yaml:"company"`     // 公司名称
+	Template    string   `yaml:"template"`    // Word模板路径
+	Include     []string `yaml:"include"`     // 只生成匹配的表
+	Exclude     []string `yaml:"exclude"`     // 排除匹配的表
+	Concurrency int      `yaml:"concurrency"` // 并发获取表结构的数量
+}
+
+// migrateConfig 迁移配置
+type migrateConfig struct {
+	Dir   string `yaml:"dir"`   // 迁移文件目录
+	Table string `yaml:"table"` // 迁移记录表
+}
+
+// diffConfig 结构比较配置，database 为源库，target 为目标库
+type diffConfig struct {
+	Target databaseConfig `yaml:"target"`
+}
+
+// defaultConfig 默认配置
+func defaultConfig() *config {
+	return &config{
+		Model:   modelConfig{Output: "./model", Package: "poes"},
+		Doc:     docConfig{Output: "db_doc.docx", Title: "数据库设计文档"},
+		Migrate: migrateConfig{Dir: "./migrations"},
+	}
+}
+
+// loadConfig 读取配置文件，文件中的 ${VAR} 替换为环境变量
+// path 为空时读取 gosqlx.yaml，该文件不存在时返回默认配置；JSON 格式的配置文件同样可以读取
+func loadConfig(path string) (*config, error) {
+	cfg := defaultConfig()
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), cfg); err != nil {
+		return nil, fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// configPath 从参数中找出 -config 的值，需在定义其他参数之前读取配置文件
+func configPath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// databaseFlags 定义数据库连接参数，prefix 用于区分 diff 的目标库
+func databaseFlags(fs *flag.FlagSet, db *databaseConfig, prefix string) {
+	fs.StringVar(&db.Type, prefix+"type", db.Type, "数据库类型，如 mysql、postgres、sqlserver、oracle、sqlite3")
+	fs.StringVar(&db.DSN, prefix+"dsn", db.DSN, "连接字符串，设置后忽略 host/port/user/password")
+	fs.StringVar(&db.Host, prefix+"host", db.Host, "主机地址")
+	fs.IntVar(&db.Port, prefix+"port", db.Port, "端口")
+	fs.StringVar(&db.User, prefix+"user", db.User, "用户名")
+	fs.StringVar(&db.Password, prefix+"password", db.Password, "密码，建议通过配置文件中的 ${VAR} 从环境变量读取")
+	fs.StringVar(&db.Name, prefix+"name", db.Name, "数据库名，SQLite/DuckDB 为文件路径")
+}
+
+// listFlag 逗号分隔的列表参数
+type listFlag struct {
+	values *[]string
+}
+
+func (f listFlag) String() string {
+	if f.values == nil {
+		return ""
+	}
+	return strings.Join(*f.values, ",")
+}
+
+func (f listFlag) Set(s string) error {
+	*f.values = nil
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			*f.values = append(*f.values, value)
+		}
+	}
+	return nil
+}
+
+// dbType 统一数据库类型的别名，返回 gosqlx.DatabaseType 使用的名称
+func (db databaseConfig) dbType() string {
+	switch t := strings.ToLower(db.Type); t {
+	case "sqlite":
+		return "sqlite3"
+	case "postgresql":
+		return "postgres"
+	case "mssql":
+		return "sqlserver"
+	default:
+		return t
+	}
+}
+
+// source 返回连接字符串，未设置 dsn 时按数据库类型拼接
+func (db databaseConfig) source() (string, error) {
+	if db.DSN != "" {
+		return db.DSN, nil
+	}
+	userInfo := url.UserPassword(db.User, db.Password).String()
+	switch db.dbType() {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			db.User, db.Password, db.Host, db.Port, db.Name), nil
+	case "postgres":
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+			db.Host, db.Port, db.User, db.Password, db.Name), nil
+	case "sqlserver":
+		return fmt.Sprintf("sqlserver://%s@%s:%d?database=%s", userInfo, db.Host, db.Port, url.QueryEscape(db.Name)), nil
+	case "oracle":
+		return fmt.Sprintf("oracle://%s@%s:%d/%s", userInfo, db.Host, db.Port, db.Name), nil
+	case "clickhouse":
+		return fmt.Sprintf("clickhouse://%s@%s:%d/%s", userInfo, db.Host, db.Port, db.Name), nil
+	case "mongodb":
+		return fmt.Sprintf("mongodb://%s@%s:%d/%s", userInfo, db.Host, db.Port, db.Name), nil
+	case "sqlite3", "duckdb":
+		return db.Name, nil
+	case "":
+		return "", errors.New("未指定数据库类型，请设置 -type 或配置文件中的 database.type")
+	}
+	return "", fmt.Errorf("数据库类型 %s 需要通过 -dsn 指定连接字符串", db.Type)
+}
@@ -0,0 +1,455 @@
+// gosqlx 命令行工具：根据数据库生成模型和文档、执行SQL迁移、比较两个库的表结构
+//
+//	gosqlx model   -type mysql -host localhost -port 3306 -user root -name testdb -out ./model
+//	gosqlx doc     -type postgres -dsn "host=localhost user=postgres dbname=test" -out db.xlsx
+//	gosqlx migrate up | down | status | create <名称>
+//	gosqlx diff    -target-type mysql -target-dsn "root:pass@tcp(prod:3306)/testdb"
+//	gosqlx doctor  检查连接字符串、连接、服务器版本和权限
+//	gosqlx comments -dir ./model/poes -dry-run  将模型注释同步到数据库
+//
+// 配置默认从当前目录的 gosqlx.yaml 读取，可用 -config 指定其他文件，命令行参数覆盖配置文件；
+// 配置文件中的 ${VAR} 会替换为环境变量，密码无需写在文件或源码中:
+//
+//	database:
+//	  type: mysql
+//	  host: localhost
+//	  port: 3306
+//	  user: root
+//	  password: ${DB_PASSWORD}
+//	  name: testdb
+//	model:
+//	  output: ./model
+//	  package: poes
+//	  tags: [json:camel, gorm]
+//	migrate:
+//	  dir: ./migrations
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/gen/comment"
+	"github.com/gzorm/gosqlx/gen/doc"
+	"github.com/gzorm/gosqlx/gen/model"
+	"github.com/gzorm/gosqlx/introspect"
+	"github.com/gzorm/gosqlx/migrate"
+)
+
+const usage = `用法: gosqlx <命令> [参数]
+
+命令:
+  model     根据数据库表结构生成 Go 模型
+  doc       生成数据库设计文档（Word 或 Excel）
+  migrate   执行SQL迁移: up [-steps n] | down [-steps n] | status | create <名称>
+  diff      比较数据库与目标库的表结构
+  doctor    诊断数据库配置：连接字符串、连接、服务器版本和权限
+  comments  将模型的表注释和列注释同步到数据库
+
+所有命令都支持 -config 指定配置文件（默认 gosqlx.yaml），使用 gosqlx <命令> -h 查看参数
+`
+
+// errDiff diff -fail 发现差异时返回的错误
+var errDiff = errors.New("表结构存在差异")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "错误:", err)
+		os.Exit(1)
+	}
+}
+
+// run 执行命令，便于测试
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(out, usage)
+		return errors.New("缺少命令")
+	}
+
+	var err error
+	switch args[0] {
+	case "model":
+		err = runModel(args[1:], out)
+	case "doc":
+		err = runDoc(args[1:], out)
+	case "migrate":
+		err = runMigrate(args[1:], out)
+	case "diff":
+		err = runDiff(args[1:], out)
+	case "doctor":
+		err = runDoctor(args[1:], out)
+	case "comments":
+		err = runComments(args[1:], out)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(out, usage)
+		return nil
+	default:
+		fmt.Fprint(out, usage)
+		return fmt.Errorf("未知命令: %s", args[0])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// newFlagSet 读取配置文件并创建带有 -config 和数据库连接参数的参数集
+func newFlagSet(name string, args []string, out io.Writer) (*config, *flag.FlagSet, error) {
+	path := configPath(args)
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	fs := flag.NewFlagSet("gosqlx "+name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.String("config", path, "配置文件路径，默认 "+defaultConfigFile)
+	databaseFlags(fs, &cfg.Database, "")
+	return cfg, fs, nil
+}
+
+// parseInterspersed 解析参数，允许位置参数出现在参数之间，返回位置参数
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// runModel 生成模型
+func runModel(args []string, out io.Writer) error {
+	cfg, fs, err := newFlagSet("model", args, out)
+	if err != nil {
+		return err
+	}
+	m := &cfg.Model
+	fs.StringVar(&m.Output, "out", m.Output, "输出目录，模型写入其中的 poes 目录")
+	fs.StringVar(&m.Package, "package", m.Package, "包名")
+	fs.BoolVar(&m.Split, "split", m.Split, "每个表生成单独的文件")
+	fs.BoolVar(&m.Columns, "columns", m.Columns, "生成表名和列名常量")
+	fs.BoolVar(&m.Enums, "enums", m.Enums, "为 ENUM 和 CHECK IN 约束的列生成类型和常量")
+	fs.StringVar(&m.NullStyle, "null-style", m.NullStyle, "可为 NULL 的列的字段类型：pointer、sql 或 generic")
+	fs.Var(listFlag{&m.Handlers}, "handlers", "生成 CRUD HTTP 处理器的表，逗号分隔，支持通配符")
+	fs.Var(listFlag{&m.Tags}, "tags", "结构体标签，逗号分隔，如 json:camel,gorm")
+	fs.Var(listFlag{&m.Include}, "include", "只生成匹配的表，逗号分隔，支持通配符")
+	fs.Var(listFlag{&m.Exclude}, "exclude", "排除匹配的表，逗号分隔，支持通配符")
+	fs.IntVar(&m.Concurrency, "concurrency", m.Concurrency, "并发获取表结构的数量")
+	fs.StringVar(&m.Resume, "resume", m.Resume, "断点文件")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// 模型生成器的 SQLite 类型名为 sqlite
+	dbType := cfg.Database.dbType()
+	if dbType == "sqlite3" {
+		dbType = "sqlite"
+	}
+	if dbType == "" {
+		return errors.New("未指定数据库类型，请设置 -type 或配置文件中的 database.type")
+	}
+	return model.GenerateModels(&model.Config{
+		DBType:          dbType,
+		Host:            cfg.Database.Host,
+		Port:            cfg.Database.Port,
+		Username:        cfg.Database.User,
+		Password:        cfg.Database.Password,
+		DatabaseName:    cfg.Database.Name,
+		OutputDir:       m.Output,
+		PackageName:     m.Package,
+		SplitFiles:      m.Split,
+		ColumnConstants: m.Columns,
+		EnumTypes:       m.Enums,
+		NullStyle:       m.NullStyle,
+		HTTPHandlers:    m.Handlers,
+		TagStyles:       m.Tags,
+		Concurrency:     m.Concurrency,
+		IncludeTables:   m.Include,
+		ExcludeTables:   m.Exclude,
+		ResumeFile:      m.Resume,
+	})
+}
+
+// runDoc 生成数据库文档
+func runDoc(args []string, out io.Writer) error {
+	cfg, fs, err := newFlagSet("doc", args, out)
+	if err != nil {
+		return err
+	}
+	d := &cfg.Doc
+	fs.StringVar(&d.Output, "out", d.Output, "输出文件，.xlsx 结尾时生成 Excel")
+	fs.StringVar(&d.Format, "format", d.Format, "word 或 excel，为空时按输出文件扩展名判断")
+	fs.StringVar(&d.Title, "title", d.Title, "文档标题")
+	fs.StringVar(&d.Author, "author", d.Author, "文档作者")
+	fs.StringVar(&d.Company, "company", d.Company, "公司名称")
+	fs.StringVar(&d.Template, "template", d.Template, "Word模板路径")
+	fs.Var(listFlag{&d.Include}, "include", "只生成匹配的表，逗号分隔，支持通配符")
+	fs.Var(listFlag{&d.Exclude}, "exclude", "排除匹配的表，逗号分隔，支持通配符")
+	fs.IntVar(&d.Concurrency, "concurrency", d.Concurrency, "并发获取表结构的数量")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	source, err := cfg.Database.source()
+	if err != nil {
+		return err
+	}
+	docConfig := &doc.Config{
+		DBType:        gosqlx.DatabaseType(cfg.Database.dbType()),
+		Source:        source,
+		DBName:        cfg.Database.Name,
+		OutputPath:    d.Output,
+		Title:         d.Title,
+		Author:        d.Author,
+		Company:       d.Company,
+		TemplatePath:  d.Template,
+		Concurrency:   d.Concurrency,
+		IncludeTables: d.Include,
+		ExcludeTables: d.Exclude,
+	}
+
+	format := strings.ToLower(d.Format)
+	if format == "" && strings.EqualFold(filepath.Ext(d.Output), ".xlsx") {
+		format = "excel"
+	}
+	switch format {
+	case "excel":
+		err = doc.GenerateExcelDoc(docConfig)
+	case "", "word":
+		err = doc.GenerateDBDoc(docConfig)
+	default:
+		return fmt.Errorf("不支持的文档格式: %s", d.Format)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "生成文档: %s\n", d.Output)
+	return nil
+}
+
+// runMigrate 执行迁移
+func runMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return errors.New("缺少迁移操作: up、down、status 或 create")
+	}
+	action, args := args[0], args[1:]
+
+	cfg, fs, err := newFlagSet("migrate "+action, args, out)
+	if err != nil {
+		return err
+	}
+	fs.StringVar(&cfg.Migrate.Dir, "dir", cfg.Migrate.Dir, "迁移文件目录")
+	fs.StringVar(&cfg.Migrate.Table, "table", cfg.Migrate.Table, "迁移记录表，默认 "+migrate.DefaultTable)
+	steps := fs.Int("steps", 0, "执行或回滚的迁移数量，up 默认全部，down 默认 1")
+	names, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if action == "create" {
+		if len(names) == 0 {
+			return errors.New("缺少迁移名称: gosqlx migrate create <名称>")
+		}
+		version, _ := strconv.ParseInt(time.Now().Format("20060102150405"), 10, 64)
+		up, down, err := migrate.Create(cfg.Migrate.Dir, version, strings.Join(names, "_"))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "创建迁移文件: %s\n创建迁移文件: %s\n", up, down)
+		return nil
+	}
+
+	migrations, err := migrate.Load(cfg.Migrate.Dir)
+	if err != nil {
+		return err
+	}
+	db, err := openDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	migrator := migrate.New(db, cfg.Database.dbType())
+	if cfg.Migrate.Table != "" {
+		migrator = migrator.WithTable(cfg.Migrate.Table)
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(migrations, *steps)
+		for _, m := range applied {
+			fmt.Fprintf(out, "已执行: %d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "没有需要执行的迁移")
+		}
+		return err
+	case "down":
+		if *steps <= 0 {
+			*steps = 1
+		}
+		rolledBack, err := migrator.Down(migrations, *steps)
+		for _, m := range rolledBack {
+			fmt.Fprintf(out, "已回滚: %d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			fmt.Fprintln(out, "没有需要回滚的迁移")
+		}
+		return err
+	case "status":
+		status, err := migrator.Status(migrations)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			state := "未执行"
+			if s.Applied {
+				state = "已执行 " + s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%d_%s\t%s\n", s.Version, s.Name, state)
+		}
+		return nil
+	}
+	return fmt.Errorf("未知的迁移操作: %s", action)
+}
+
+// runDiff 比较数据库与目标库的表结构
+func runDiff(args []string, out io.Writer) error {
+	cfg, fs, err := newFlagSet("diff", args, out)
+	if err != nil {
+		return err
+	}
+	databaseFlags(fs, &cfg.Diff.Target, "target-")
+	fail := fs.Bool("fail", false, "存在差异时以非零状态退出，用于 CI 检查")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	target := cfg.Diff.Target
+	if target.Type == "" {
+		target.Type = cfg.Database.Type
+	}
+
+	from, err := openDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer from.Close()
+	to, err := openDB(target)
+	if err != nil {
+		return err
+	}
+	defer to.Close()
+
+	changes, err := introspect.Diff(introspect.New(from, cfg.Database.dbType()), introspect.New(to, target.dbType()))
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "表结构一致")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(out, change)
+	}
+	if *fail {
+		return fmt.Errorf("%w: %d 处", errDiff, len(changes))
+	}
+	return nil
+}
+
+// runDoctor 诊断数据库配置
+func runDoctor(args []string, out io.Writer) error {
+	cfg, fs, err := newFlagSet("doctor", args, out)
+	if err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	source, err := cfg.Database.source()
+	if err != nil {
+		return err
+	}
+
+	report := gosqlx.Doctor(&gosqlx.Config{
+		Type:   gosqlx.DatabaseType(cfg.Database.dbType()),
+		Source: source,
+	})
+	fmt.Fprint(out, report)
+	if !report.OK() {
+		return errors.New("诊断未通过")
+	}
+	return nil
+}
+
+// runComments 将模型注释同步到数据库
+func runComments(args []string, out io.Writer) error {
+	cfg, fs, err := newFlagSet("comments", args, out)
+	if err != nil {
+		return err
+	}
+	dir := fs.String("dir", filepath.Join(cfg.Model.Output, "poes"), "模型所在目录，默认为模型输出目录中的 poes 目录")
+	dryRun := fs.Bool("dry-run", false, "只输出语句，不执行")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tables, err := comment.Parse(*dir)
+	if err != nil {
+		return err
+	}
+	db, err := openDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	statements, err := comment.Sync(db, cfg.Database.dbType(), tables, comment.Options{DryRun: *dryRun})
+	for _, statement := range statements {
+		fmt.Fprintln(out, statement)
+	}
+	if err == nil && len(statements) == 0 {
+		fmt.Fprintln(out, "注释已是最新")
+	}
+	return err
+}
+
+// openDB 按配置连接数据库
+func openDB(db databaseConfig) (*sql.DB, error) {
+	source, err := db.source()
+	if err != nil {
+		return nil, err
+	}
+	dbType := gosqlx.DatabaseType(db.dbType())
+	database, err := gosqlx.NewDatabase(&gosqlx.Context{
+		Nick:    "gosqlx_cli",
+		Mode:    "rw",
+		DBType:  dbType,
+		Timeout: time.Minute,
+	}, &gosqlx.Config{
+		Type:        dbType,
+		Source:      source,
+		MaxIdle:     1,
+		MaxOpen:     2,
+		MaxLifetime: time.Hour,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+	if database.SqlDB() == nil {
+		return nil, fmt.Errorf("数据库类型 %s 不支持该命令", db.Type)
+	}
+	return database.SqlDB(), nil
+}
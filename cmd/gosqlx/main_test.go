@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("GOSQLX_TEST_PASSWORD", "s3cret")
+	path := filepath.Join(t.TempDir(), "gosqlx.yaml")
+	content := "database:\n  type: mysql\n  host: db\n  port: 3306\n  user: root\n  password: ${GOSQLX_TEST_PASSWORD}\n  name: app\n" +
+		"model:\n  tags: [json:camel, gorm]\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if cfg.Database.Password != "s3cret" || cfg.Model.Package != "poes" || !reflect.DeepEqual(cfg.Model.Tags, []string{"json:camel", "gorm"}) {
+		t.Errorf("loadConfig() = %+v", cfg)
+	}
+	if source, _ := cfg.Database.source(); source != "root:s3cret@tcp(db:3306)/app?charset=utf8mb4&parseTime=True&loc=Local" {
+		t.Errorf("source() = %s", source)
+	}
+
+	// 命令行参数覆盖配置文件
+	args := []string{"-name", "other", "--config=" + path, "-tags", "db"}
+	if got := configPath(args); got != path {
+		t.Errorf("configPath() = %q", got)
+	}
+	cfg, fs, err := newFlagSet("model", args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("newFlagSet() error = %v", err)
+	}
+	fs.Var(listFlag{&cfg.Model.Tags}, "tags", "")
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.Database.Name != "other" || cfg.Database.Host != "db" || !reflect.DeepEqual(cfg.Model.Tags, []string{"db"}) {
+		t.Errorf("参数覆盖后配置 = %+v", cfg)
+	}
+
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("指定的配置文件不存在时应返回错误")
+	}
+}
+
+// 测试在 SQLite 上执行迁移、比较结构并生成模型和文档
+func TestSQLiteCommands(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "app.db")
+	migrations := filepath.Join(dir, "migrations")
+	configFile := filepath.Join(dir, "gosqlx.yaml")
+	content := "database:\n  type: sqlite\n  name: " + dbFile + "\nmigrate:\n  dir: " + migrations + "\n"
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+
+	var out bytes.Buffer
+	exec := func(args ...string) error {
+		out.Reset()
+		return run(append(args, "-config", configFile), &out)
+	}
+
+	if err := exec("migrate", "create", "create users"); err != nil {
+		t.Fatalf("migrate create error = %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(migrations, "*_create_users.up.sql"))
+	if len(files) != 1 {
+		t.Fatalf("迁移文件 = %v", files)
+	}
+	if err := os.WriteFile(files[0], []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);"), 0644); err != nil {
+		t.Fatalf("写入迁移失败: %v", err)
+	}
+	if err := exec("migrate", "up"); err != nil || !strings.Contains(out.String(), "已执行") {
+		t.Fatalf("migrate up = %q, %v", out.String(), err)
+	}
+	if err := exec("migrate", "status"); err != nil || !strings.Contains(out.String(), "create_users\t已执行") {
+		t.Fatalf("migrate status = %q, %v", out.String(), err)
+	}
+
+	// 与另一个库比较
+	target := filepath.Join(dir, "target.db")
+	db, err := sql.Open("sqlite3", target)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT)")
+	db.Close()
+	if err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	err = exec("diff", "-target-name", target, "-fail")
+	if !errors.Is(err, errDiff) || !strings.Contains(out.String(), "+ column users.email TEXT") ||
+		!strings.Contains(out.String(), "- table gosqlx_migrations") {
+		t.Errorf("diff = %q, %v", out.String(), err)
+	}
+
+	if err := exec("model", "-out", dir, "-package", "models"); err != nil {
+		t.Fatalf("model error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "poes", "poes.go")); err != nil {
+		t.Errorf("模型文件未生成: %v", err)
+	}
+	if err := exec("doc", "-out", filepath.Join(dir, "db.xlsx")); err != nil {
+		t.Fatalf("doc error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "db.xlsx")); err != nil {
+		t.Errorf("文档未生成: %v", err)
+	}
+
+	if err := exec("doctor"); err != nil || !strings.Contains(out.String(), "[OK] privileges") {
+		t.Errorf("doctor = %q, %v", out.String(), err)
+	}
+
+	if err := run([]string{"unknown"}, &out); err == nil {
+		t.Error("未知命令应返回错误")
+	}
+}
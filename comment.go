@@ -0,0 +1,117 @@
+package gosqlx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gzorm/gosqlx/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== SQL注释 ====================
+
+// WithComment 返回携带SQL注释属性的上下文，kv 为成对的键和值
+// 经由该上下文执行的语句（GORM、Raw/Exec 和查询构建器）以 /* app=orders trace=abc123 */ 开头，
+// 便于 DBA 在 processlist 和慢日志中定位来源；固定不变的属性可通过 Config.Comment 设置:
+//
+//	ctx = gosqlx.WithComment(ctx, "trace", traceID)
+//	db.WithContext(ctx).Find(&orders)
+func WithComment(ctx context.Context, kv ...string) context.Context {
+	return query.WithComment(ctx, kv...)
+}
+
+// WithComment 返回携带SQL注释属性的新上下文
+func (c *Context) WithComment(kv ...string) *Context {
+	return &Context{
+		Context:      query.WithComment(c.parent(), kv...),
+		Nick:         c.Nick,
+		Mode:         c.Mode,
+		DBType:       c.DBType,
+		Timeout:      c.Timeout,
+		MaxStaleness: c.MaxStaleness,
+	}
+}
+
+// WithComment 返回为后续语句附加注释属性的数据库实例
+//
+//	db.WithComment("svc", "checkout", "trace", traceID).Exec("UPDATE orders SET status = ? WHERE id = ?", 2, id)
+func (d *Database) WithComment(kv ...string) *Database {
+	return d.session(d.db.WithContext(query.WithComment(d.db.Statement.Context, kv...)))
+}
+
+// commented 为绕过 GORM 执行的语句加上注释
+func (d *Database) commented(ctx context.Context, sqlStr string) string {
+	return query.PrependComment(query.FormatComment(d.comment, ctx), sqlStr)
+}
+
+// commentClauses 承载注释的主子句
+var commentClauses = []string{"INSERT", "SELECT", "UPDATE", "DELETE"}
+
+// registerCommentCallbacks 注册SQL注释回调，attrs 为 Config.Comment 中的固定属性
+// 已有SQL的语句（Raw/Exec）直接在开头加上注释，其余语句通过主子句的 BeforeExpression 在生成时加上注释
+func registerCommentCallbacks(db *gorm.DB, attrs map[string]string) error {
+	// 方言自定义的子句生成函数（如 SQLite 的 INSERT）不输出 BeforeExpression，需先输出注释
+	for _, name := range commentClauses {
+		if build, ok := db.ClauseBuilders[name]; ok {
+			db.ClauseBuilders[name] = func(c clause.Clause, builder clause.Builder) {
+				if c.BeforeExpression != nil {
+					c.BeforeExpression.Build(builder)
+					builder.WriteByte(' ')
+					c.BeforeExpression = nil
+				}
+				build(c, builder)
+			}
+		}
+	}
+
+	callback := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callback.Create().Before("gorm:create").Register("gosqlx:comment", commentCallback(attrs, "INSERT"))
+		},
+		func() error {
+			return callback.Query().Before("gorm:query").Register("gosqlx:comment", commentCallback(attrs, "SELECT"))
+		},
+		func() error {
+			return callback.Update().Before("gorm:update").Register("gosqlx:comment", commentCallback(attrs, "UPDATE"))
+		},
+		func() error {
+			return callback.Delete().Before("gorm:delete").Register("gosqlx:comment", commentCallback(attrs, "DELETE"))
+		},
+		func() error {
+			return callback.Raw().Before("gorm:raw").Register("gosqlx:comment", commentCallback(attrs, ""))
+		},
+		func() error {
+			return callback.Row().Before("gorm:row").Register("gosqlx:comment", commentCallback(attrs, ""))
+		},
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return fmt.Errorf("注册SQL注释回调失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// commentCallback 为语句加上注释，clauseName 为语句的主子句
+func commentCallback(attrs map[string]string, clauseName string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		comment := query.FormatComment(attrs, db.Statement.Context)
+		if comment == "" {
+			return
+		}
+		if db.Statement.SQL.Len() > 0 {
+			sqlStr := query.PrependComment(comment, db.Statement.SQL.String())
+			db.Statement.SQL.Reset()
+			db.Statement.SQL.WriteString(sqlStr)
+			return
+		}
+		if clauseName == "" {
+			return
+		}
+		c := db.Statement.Clauses[clauseName]
+		c.BeforeExpression = clause.Expr{SQL: comment}
+		db.Statement.Clauses[clauseName] = c
+	}
+}
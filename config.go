@@ -19,6 +19,7 @@ const (
 	MariaDB     DatabaseType = "mariadb"
 	ClickHouse  DatabaseType = "clickhouse"
 	OceanBase   DatabaseType = "oceanbase"
+	DuckDB      DatabaseType = "duckdb"
 )
 
 // Config 数据库配置结构
@@ -37,6 +38,38 @@ type Config struct {
 
 	// 调试模式
 	Debug bool `json:"debug"`
+
+	// 慢查询阈值，大于 0 时记录执行时间超过阈值的语句
+	SlowThreshold time.Duration `json:"slowThreshold"`
+
+	// 日志中是否输出参数值，默认将参数值和SQL中的字面量替换为 ?，避免敏感数据进入日志
+	LogParameterValues bool `json:"logParameterValues"`
+	// 不输出参数值时以取值的哈希代替 ?，相同取值得到相同的占位符
+	LogParameterHash bool `json:"logParameterHash"`
+
+	// 每条语句开头附带的注释属性，如 {"app": "orders", "svc": "checkout"}，
+	// 生成 /* app=orders svc=checkout */，请求级属性（如 trace）通过 WithComment 放入上下文
+	Comment map[string]string `json:"comment"`
+
+	// 只读模式（Context.Mode 为 ModeReadOnly）下同时在服务端声明只读：PostgreSQL 连接设置 default_transaction_read_only，
+	// SQLServer 连接设置 ApplicationIntent=ReadOnly，MySQL 系和 PostgreSQL 以只读事务开启事务
+	ReadOnlyIntent bool `json:"readOnlyIntent"`
+
+	// 启动时预先建立的连接数（不超过 MaxIdle），连接失败时 NewDatabase 返回错误，避免发布后首批请求建立连接的延迟
+	WarmUp int `json:"warmUp"`
+	// 空闲连接保活间隔，大于 0 时定期对空闲连接执行 Ping，避免被负载均衡器按空闲超时断开
+	KeepAlive time.Duration `json:"keepAlive"`
+
+	// 每个新建立的连接上依次执行的初始化语句，用于统一会话设置，如 "SET time_zone = '+08:00'"、
+	// "SET search_path TO app"、"ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY-MM-DD'"；任一语句失败时该连接不可用
+	ConnInit []string `json:"connInit"`
+
+	// SQLite 等待数据库锁的时间，连接字符串未指定 _busy_timeout 时加上，默认 5 秒；不在事务中的语句等待后
+	// 仍因 SQLITE_BUSY/SQLITE_LOCKED 失败时在该时间内退避重试，避免并发写入报 "database is locked"，负数表示不重试
+	BusyTimeout time.Duration `json:"busyTimeout"`
+
+	// 时间、定点数和 uint64 参数的绑定格式，为空时使用驱动的默认行为，各数据库常用的格式见 DefaultValueFormat
+	ValueFormat *ValueFormat `json:"valueFormat"`
 }
 
 // DefaultConfig 返回默认配置
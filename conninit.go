@@ -0,0 +1,102 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 连接初始化 ====================
+
+// initConnector 在每个新建立的连接上执行初始化语句的连接器
+type initConnector struct {
+	driver     driver.Driver
+	connector  driver.Connector // 驱动实现 driver.DriverContext 时使用，否则通过 driver.Open 建立连接
+	dsn        string
+	statements []string
+}
+
+// Connect 建立连接并执行初始化语句，任一语句失败时关闭连接并返回错误
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var (
+		conn driver.Conn
+		err  error
+	)
+	if c.connector != nil {
+		conn, err = c.connector.Connect(ctx)
+	} else {
+		conn, err = c.driver.Open(c.dsn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range c.statements {
+		if err := execConn(ctx, conn, statement); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("执行连接初始化语句 %q 失败: %w", statement, err)
+		}
+	}
+	return conn, nil
+}
+
+// Driver 返回底层驱动
+func (c *initConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// execConn 在驱动连接上执行不带参数的语句
+func execConn(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		if !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, statement)
+	} else {
+		stmt, err = conn.Prepare(statement)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+// applyConnInit 将 GORM 打开的连接池替换为每个新连接都执行初始化语句的连接池
+// 连接池由 database/sql 按需建立连接，只有在建立连接时执行才能保证池中所有连接的会话设置一致
+func applyConnInit(ctx context.Context, db *gorm.DB, dsn string, statements []string) error {
+	original, err := db.DB()
+	if err != nil {
+		return err
+	}
+	connector := &initConnector{driver: original.Driver(), dsn: dsn, statements: statements}
+	if driverCtx, ok := connector.driver.(driver.DriverContext); ok {
+		if connector.connector, err = driverCtx.OpenConnector(dsn); err != nil {
+			return err
+		}
+	}
+
+	sqlDB := sql.OpenDB(connector)
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return err
+	}
+	db.ConnPool = sqlDB
+	db.Statement.ConnPool = sqlDB
+	return original.Close()
+}
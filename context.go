@@ -22,6 +22,7 @@ type Context struct {
 	Mode            string        // 读写模式
 	DBType          DatabaseType  // 数据库类型
 	Timeout         time.Duration // 操作超时时间
+	MaxStaleness    time.Duration // 只读模式可接受的副本复制延迟，大于 0 时跳过延迟超过该值的副本
 }
 
 // NewContext 创建新的数据库上下文
@@ -49,55 +50,73 @@ func (c *Context) WithTimeout(timeout time.Duration) *Context {
 	return c
 }
 
+// WithMaxStaleness 设置只读模式可接受的副本复制延迟，所有副本都超过该延迟时读主库
+func (c *Context) WithMaxStaleness(staleness time.Duration) *Context {
+	c.MaxStaleness = staleness
+	return c
+}
+
 // IsReadOnly 判断是否为只读模式
 func (c *Context) IsReadOnly() bool {
 	return c.Mode == ModeReadOnly
 }
 
+// parent 返回派生上下文使用的父上下文，直接构造的 Context 未设置 Context 字段时为 context.Background()
+func (c *Context) parent() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
+}
+
 // WithValue 创建带值的新上下文
 func (c *Context) WithValue(key, val interface{}) *Context {
 	return &Context{
-		Context: context.WithValue(c.Context, key, val),
-		Nick:    c.Nick,
-		Mode:    c.Mode,
-		DBType:  c.DBType,
-		Timeout: c.Timeout,
+		Context:      context.WithValue(c.parent(), key, val),
+		Nick:         c.Nick,
+		Mode:         c.Mode,
+		DBType:       c.DBType,
+		Timeout:      c.Timeout,
+		MaxStaleness: c.MaxStaleness,
 	}
 }
 
 // WithCancel 创建可取消的上下文
 func (c *Context) WithCancel() (*Context, context.CancelFunc) {
-	ctx, cancel := context.WithCancel(c.Context)
+	ctx, cancel := context.WithCancel(c.parent())
 	return &Context{
-		Context: ctx,
-		Nick:    c.Nick,
-		Mode:    c.Mode,
-		DBType:  c.DBType,
-		Timeout: c.Timeout,
+		Context:      ctx,
+		Nick:         c.Nick,
+		Mode:         c.Mode,
+		DBType:       c.DBType,
+		Timeout:      c.Timeout,
+		MaxStaleness: c.MaxStaleness,
 	}, cancel
 }
 
 // WithDeadline 创建带截止时间的上下文
 func (c *Context) WithDeadline(d time.Time) (*Context, context.CancelFunc) {
-	ctx, cancel := context.WithDeadline(c.Context, d)
+	ctx, cancel := context.WithDeadline(c.parent(), d)
 	return &Context{
-		Context: ctx,
-		Nick:    c.Nick,
-		Mode:    c.Mode,
-		DBType:  c.DBType,
-		Timeout: c.Timeout,
+		Context:      ctx,
+		Nick:         c.Nick,
+		Mode:         c.Mode,
+		DBType:       c.DBType,
+		Timeout:      c.Timeout,
+		MaxStaleness: c.MaxStaleness,
 	}, cancel
 }
 
 // WithTimeout 创建带超时的上下文
 func (c *Context) WithContextTimeout(timeout time.Duration) (*Context, context.CancelFunc) {
-	ctx, cancel := context.WithTimeout(c.Context, timeout)
+	ctx, cancel := context.WithTimeout(c.parent(), timeout)
 	return &Context{
-		Context: ctx,
-		Nick:    c.Nick,
-		Mode:    c.Mode,
-		DBType:  c.DBType,
-		Timeout: c.Timeout,
+		Context:      ctx,
+		Nick:         c.Nick,
+		Mode:         c.Mode,
+		DBType:       c.DBType,
+		Timeout:      c.Timeout,
+		MaxStaleness: c.MaxStaleness,
 	}, cancel
 }
 
@@ -139,7 +158,7 @@ func (c *DBContext) WithTxOption(opt *sql.TxOptions) *DBContext {
 // Transaction 执行事务
 func (c *DBContext) Transaction(fn func(tx *DBContext) error) error {
 	// 使用GORM的事务
-	return c.DB.WithContext(c.Context).Transaction(func(tx *gorm.DB) error {
+	return c.DB.WithContext(c.parent()).Transaction(func(tx *gorm.DB) error {
 		txCtx := &DBContext{
 			Context:  c.Context,
 			DB:       tx,
@@ -153,7 +172,7 @@ func (c *DBContext) Transaction(fn func(tx *DBContext) error) error {
 // RawTransaction 执行原生SQL事务
 func (c *DBContext) RawTransaction(fn func(tx *sql.Tx) error) error {
 	// 使用原生SQL的事务
-	tx, err := c.SqlDB.BeginTx(c.Context, c.TxOption)
+	tx, err := c.SqlDB.BeginTx(c.parent(), c.TxOption)
 	if err != nil {
 		return err
 	}
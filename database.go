@@ -11,27 +11,34 @@ import (
 	"sync"
 
 	"github.com/gzorm/gosqlx/adapter"
-	oracle "github.com/seelly/gorm-oracle"
-	"gorm.io/driver/clickhouse"
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/postgres"
-	"gorm.io/driver/sqlite"
-	"gorm.io/driver/sqlserver"
+	"github.com/gzorm/gosqlx/builder"
+	"github.com/gzorm/gosqlx/query"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
-	"gorm.io/gorm/logger"
 )
 
 // ==================== 数据库核心结构 ====================
 
 // Database 数据库操作核心结构
 type Database struct {
-	db       *gorm.DB        // GORM数据库连接
-	sqlDB    *sql.DB         // 原生SQL数据库连接
-	dbType   DatabaseType    // 数据库类型
-	deadlock *Deadlock       // 死锁检测器
-	ctx      *Context        // 数据库上下文
-	adapter  adapter.Adapter // 添加适配器字段
+	db        *gorm.DB          // GORM数据库连接
+	sqlDB     *sql.DB           // 原生SQL数据库连接
+	dbType    DatabaseType      // 数据库类型
+	deadlock  *Deadlock         // 死锁检测器
+	ctx       *Context          // 数据库上下文
+	adapter   adapter.Adapter   // 添加适配器字段
+	validator Validator         // 写入前校验器
+	dryRun    *dryRunRecorder   // 试运行记录器
+	conns     *connTracker      // 连接占用跟踪器
+	release   func()            // 释放 Begin 开启的事务占用
+	comment   map[string]string // 每条语句附带的注释属性
+	tx        *txState          // 事务状态，不在事务中时为 nil
+	txOptions []*sql.TxOptions  // 开启事务的选项，只读模式下为只读事务
+	stmtRetry int               // 事务中单条语句的重试次数
+	binder    *valueBinder      // 参数格式转换，未配置 ValueFormat 时为 nil
+	refresh   bool              // 插入后读回数据库填充的列
+
+	stopKeepAlive func() // 停止空闲连接保活
 }
 
 // Deadlock 死锁检测器
@@ -48,6 +55,8 @@ type DatabaseManager struct {
 	configManager *ConfigManager
 	databases     map[string]*Database
 	mutex         sync.RWMutex
+	closing       bool         // 已开始关闭，不再创建新的数据库连接
+	lag           replicaState // 只读副本的延迟探测状态
 }
 
 // NewDatabaseManager 创建数据库管理器
@@ -59,13 +68,30 @@ func NewDatabaseManager(configManager *ConfigManager) *DatabaseManager {
 }
 
 // GetDatabase 获取数据库连接
+// ctx 中有同一数据库别名的环境事务（见 WithTx、FromContext）时返回该事务，读写模式均在事务中执行以读到未提交的写入
 func (m *DatabaseManager) GetDatabase(ctx *Context) (*Database, error) {
 	if ctx == nil {
 		return nil, errors.New("上下文不能为空")
 	}
+	if tx, ok := FromContext(ctx.parent()); ok && tx.ctx != nil && tx.ctx.Nick == ctx.Nick {
+		return tx.WithContext(ctx), nil
+	}
+
+	// 获取配置
+	env := "development" // 默认环境
+	dbName := ctx.Nick
 
+	// 如果是只读模式，路由到只读副本（ctx.MaxStaleness 大于 0 时跳过延迟超过该值的副本）
+	if ctx.IsReadOnly() {
+		dbName = m.routeReadOnly(ctx, env)
+	}
+	return m.open(ctx, env, dbName)
+}
+
+// open 获取或创建配置名为 dbName 的数据库连接
+func (m *DatabaseManager) open(ctx *Context, env, dbName string) (*Database, error) {
 	// 构建数据库键
-	dbKey := fmt.Sprintf("%s_%s", ctx.Nick, ctx.Mode)
+	dbKey := fmt.Sprintf("%s_%s", dbName, ctx.Mode)
 
 	// 尝试从缓存获取
 	m.mutex.RLock()
@@ -73,18 +99,10 @@ func (m *DatabaseManager) GetDatabase(ctx *Context) (*Database, error) {
 		m.mutex.RUnlock()
 		return db, nil
 	}
+	closing := m.closing
 	m.mutex.RUnlock()
-
-	// 获取配置
-	env := "development" // 默认环境
-	dbName := ctx.Nick
-
-	// 如果是只读模式，尝试获取只读数据库配置
-	if ctx.IsReadOnly() {
-		readOnlyDBName := fmt.Sprintf("%s_readonly", dbName)
-		if _, ok := m.configManager.GetConfig(env, readOnlyDBName); ok {
-			dbName = readOnlyDBName
-		}
+	if closing {
+		return nil, ErrShuttingDown
 	}
 
 	// 获取数据库配置
@@ -99,8 +117,18 @@ func (m *DatabaseManager) GetDatabase(ctx *Context) (*Database, error) {
 		return nil, err
 	}
 
-	// 缓存数据库连接
+	// 缓存数据库连接，并发创建时使用先缓存的连接
 	m.mutex.Lock()
+	if m.closing {
+		m.mutex.Unlock()
+		db.Close()
+		return nil, ErrShuttingDown
+	}
+	if cached, ok := m.databases[dbKey]; ok {
+		m.mutex.Unlock()
+		db.Close()
+		return cached, nil
+	}
 	m.databases[dbKey] = db
 	m.mutex.Unlock()
 
@@ -114,10 +142,8 @@ func (m *DatabaseManager) CloseAll() error {
 
 	var errs []string
 	for key, db := range m.databases {
-		if db.sqlDB != nil {
-			if err := db.sqlDB.Close(); err != nil {
-				errs = append(errs, fmt.Sprintf("关闭数据库(%s)失败: %v", key, err))
-			}
+		if err := db.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("关闭数据库(%s)失败: %v", key, err))
 		}
 	}
 
@@ -195,12 +221,9 @@ func NewDatabase(ctx *Context, config *Config) (*Database, error) {
 
 	// 创建GORM配置
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger: newLogger(config),
 	}
 
-	if config.Debug {
-		gormConfig.Logger = logger.Default.LogMode(logger.Info)
-	}
 	// MongoDB 需要特殊处理
 	if config.Type == MongoDB {
 		// 从连接字符串中解析数据库名称
@@ -244,38 +267,81 @@ func NewDatabase(ctx *Context, config *Config) (*Database, error) {
 		return database, nil
 	}
 	// 根据数据库类型创建方言
-	var dialector gorm.Dialector
-	switch config.Type {
-	case MySQL:
-		dialector = mysql.Open(config.Source)
-	case PostgresSQL:
-		dialector = postgres.Open(config.Source)
-	case SQLServer:
-		dialector = sqlserver.Open(config.Source)
-	case SQLite:
-		dialector = sqlite.Open(config.Source)
-	case Oracle:
-		dialector = oracle.Open(config.Source)
-	case TiDB:
-		// TiDB 使用 MySQL 驱动，但需要特殊处理
-		dialector = mysql.Open(config.Source)
-	case MariaDB:
-		// MariaDB 使用 MySQL 驱动
-		dialector = mysql.Open(config.Source)
-	case ClickHouse:
-		dialector = clickhouse.Open(config.Source)
-	case OceanBase:
-		// OceanBase 使用 MySQL 驱动
-		dialector = mysql.Open(config.Source)
-	default:
+	newDialector, ok := lookupDialect(config.Type)
+	if !ok {
 		return nil, fmt.Errorf("不支持的数据库类型: %s", config.Type)
 	}
+	source := config.Source
+	if ctx.IsReadOnly() && config.ReadOnlyIntent {
+		source = readOnlyDSN(config.Type, source)
+	}
+	// SQLite 连接时加上锁等待时间，DSN() 仍返回配置的连接字符串
+	openSource, busyTimeout := source, config.BusyTimeout
+	if config.Type == SQLite {
+		if busyTimeout == 0 {
+			busyTimeout = defaultBusyTimeout
+		}
+		openSource = sqliteSource(source, busyTimeout)
+		config = sqliteConfig(config, source)
+	}
+	dialector := newDialector(openSource)
+	// 按 ValueFormat 转换参数
+	var binder *valueBinder
+	if config.ValueFormat != nil {
+		var err error
+		if binder, err = newValueBinder(config.ValueFormat); err != nil {
+			return nil, err
+		}
+		dialector = valueDialector{Dialector: dialector, binder: binder}
+	}
 
 	// 创建GORM连接
 	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return nil, err
 	}
+	// 连接初始化语句
+	if len(config.ConnInit) > 0 {
+		if err := applyConnInit(ctx, db, openSource, config.ConnInit); err != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close()
+			}
+			return nil, err
+		}
+	}
+
+	// 注册语句超时回调
+	if err := registerTimeoutCallbacks(db); err != nil {
+		return nil, err
+	}
+	// 注册SQL注释回调
+	if err := registerCommentCallbacks(db, config.Comment); err != nil {
+		return nil, err
+	}
+	// 注册时区转换回调
+	if binder != nil {
+		if err := registerValueCallbacks(db, binder); err != nil {
+			return nil, err
+		}
+	}
+	// 注册语句策略回调
+	if err := registerPolicyCallbacks(db, ctx.Nick); err != nil {
+		return nil, err
+	}
+	// 注册限流回调
+	if err := registerThrottleCallbacks(db, ctx.Nick); err != nil {
+		return nil, err
+	}
+	// 注册强制过滤条件回调
+	if err := registerRowFilterCallbacks(db); err != nil {
+		return nil, err
+	}
+	// 只读模式注册拒绝写操作的回调
+	if ctx.IsReadOnly() {
+		if err := registerReadOnlyCallbacks(db); err != nil {
+			return nil, err
+		}
+	}
 
 	// 获取原生SQL连接
 	sqlDB, err := db.DB()
@@ -283,80 +349,66 @@ func NewDatabase(ctx *Context, config *Config) (*Database, error) {
 		return nil, err
 	}
 
+	// SQLite 不在事务中的语句遇到锁冲突时重试
+	if config.Type == SQLite && busyTimeout > 0 {
+		pool := &busyPool{db: sqlDB, timeout: busyTimeout}
+		db.ConnPool = pool
+		db.Statement.ConnPool = pool
+	}
+
 	// 设置连接池参数
 	sqlDB.SetMaxIdleConns(config.MaxIdle)
 	sqlDB.SetMaxOpenConns(config.MaxOpen)
 	sqlDB.SetConnMaxLifetime(config.MaxLifetime)
 
-	// 创建适配器实例
+	// 预热连接，超过 MaxIdle 的连接归还时会被关闭
+	if warm := min(config.WarmUp, config.MaxIdle); warm > 0 {
+		if config.MaxOpen > 0 {
+			warm = min(warm, config.MaxOpen)
+		}
+		if err := warmUp(ctx, sqlDB, warm); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	// 创建适配器实例，第三方数据库未注册适配器时为 nil
 	var adapterInstance adapter.Adapter
-	switch config.Type {
-	case MySQL:
-		adapterInstance = adapter.NewMySQL(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case PostgresSQL:
-		adapterInstance = adapter.NewPostgres(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case SQLServer:
-		adapterInstance = adapter.NewSQLServer(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case SQLite:
-		adapterInstance = adapter.NewSQLite(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case Oracle:
-		adapterInstance = adapter.NewOracle(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case TiDB:
-		adapterInstance = adapter.NewTiDB(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case MariaDB:
-		adapterInstance = adapter.NewMariaDB(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case ClickHouse:
-		adapterInstance = adapter.NewClickHouse(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	case OceanBase:
-		adapterInstance = adapter.NewOceanBase(config.Source).
-			WithMaxIdle(config.MaxIdle).
-			WithMaxOpen(config.MaxOpen).
-			WithMaxLifetime(config.MaxLifetime).
-			WithDebug(config.Debug)
-	default:
-		return nil, fmt.Errorf("不支持的数据库类型: %s", config.Type)
+	if newAdapter, ok := adapter.Lookup(string(config.Type)); ok {
+		adapterInstance = newAdapter(adapter.Options{
+			DSN:         source,
+			MaxIdle:     config.MaxIdle,
+			MaxOpen:     config.MaxOpen,
+			MaxLifetime: config.MaxLifetime,
+			Debug:       config.Debug,
+		})
+	}
+
+	// 特性随版本变化的适配器按服务端版本返回 Capabilities
+	if aware, ok := adapterInstance.(adapter.VersionAware); ok {
+		var version string
+		if err := sqlDB.QueryRowContext(ctx.parent(), aware.VersionQuery()).Scan(&version); err == nil {
+			aware.SetServerVersion(version)
+		}
 	}
 
 	// 创建数据库操作实例
 	database := &Database{
-		db:       db,
+		db:       db.WithContext(timeoutContext(ctx)),
 		sqlDB:    sqlDB,
 		dbType:   config.Type,
 		deadlock: NewDeadlock(ctx),
 		ctx:      ctx,
 		adapter:  adapterInstance,
+		conns:    newConnTracker(),
+		comment:  config.Comment,
+		binder:   binder,
+	}
+	if ctx.IsReadOnly() && config.ReadOnlyIntent {
+		database.txOptions = readOnlyTxOptions(config.Type)
+	}
+	if config.KeepAlive > 0 {
+		database.stopKeepAlive = startKeepAlive(sqlDB, config.KeepAlive)
 	}
 
 	return database, nil
@@ -383,6 +435,8 @@ func (d *Database) DSN() string {
 		return adapterInstance.DSN
 	case *adapter.MariaDB:
 		return adapterInstance.DSN
+	case *adapter.DuckDB:
+		return adapterInstance.DSN
 	default:
 		return ""
 	}
@@ -421,6 +475,18 @@ func (d *Database) Adapter() adapter.Adapter {
 	return d.adapter
 }
 
+// defaultCapabilities 没有适配器时假定的特性：支持事务，参数上限按 MySQL/Postgres
+var defaultCapabilities = adapter.Capabilities{Transactions: true, Savepoints: true, ReleaseSavepoint: true, MaxBindParams: 60000}
+
+// Capabilities 获取数据库支持的特性，没有适配器时返回 defaultCapabilities
+// 示例: if db.Capabilities().Returning { ... }
+func (d *Database) Capabilities() adapter.Capabilities {
+	if d.adapter == nil {
+		return defaultCapabilities
+	}
+	return d.adapter.Capabilities()
+}
+
 // Model 设置模型
 func (d *Database) Model(value interface{}) *gorm.DB {
 	return d.db.Model(value)
@@ -435,7 +501,11 @@ func (d *Database) Table(name string) *gorm.DB {
 
 // First 查询第一条记录
 func (d *Database) First(out interface{}, where ...interface{}) error {
-	return d.Model(out).First(out, where...).Error
+	relations, where := splitRelations(where)
+	if err := applyRelations(d.Model(out), relations).First(out, where...).Error; err != nil {
+		return err
+	}
+	return d.runHooks(afterFind, out)
 }
 
 // FirstOrInit 查询第一条记录，如果不存在则初始化
@@ -448,9 +518,20 @@ func (d *Database) FirstOrCreate(out interface{}, where ...interface{}) error {
 	return d.Model(out).FirstOrCreate(out, where...).Error
 }
 
-// Find 查询多条记录
+// Find 查询多条记录，条件参数中可传入 Preload/JoinPreload 加载关联
+// 条件参数中的切片超过方言 IN 上限时自动分批查询并合并结果（结果按批次顺序拼接）
 func (d *Database) Find(out interface{}, where ...interface{}) error {
-	return d.Model(out).Find(out, where...).Error
+	relations, where := splitRelations(where)
+	var err error
+	if index := d.oversizedIn(where); index >= 0 {
+		err = d.findChunked(out, where, index, relations)
+	} else {
+		err = applyRelations(d.Model(out), relations).Find(out, where...).Error
+	}
+	if err != nil {
+		return err
+	}
+	return d.runHooks(afterFind, out)
 }
 
 // FindInBatches 批量查询
@@ -479,17 +560,25 @@ func (d *Database) Exists(model interface{}, where ...interface{}) (bool, error)
 
 // Take 获取一条记录，不指定排序
 func (d *Database) Take(out interface{}, where ...interface{}) error {
-	return d.Model(out).Take(out, where...).Error
+	relations, where := splitRelations(where)
+	if err := applyRelations(d.Model(out), relations).Take(out, where...).Error; err != nil {
+		return err
+	}
+	return d.runHooks(afterFind, out)
 }
 
 // Last 获取最后一条记录
 func (d *Database) Last(out interface{}, where ...interface{}) error {
-	return d.Model(out).Last(out, where...).Error
+	relations, where := splitRelations(where)
+	if err := applyRelations(d.Model(out), relations).Last(out, where...).Error; err != nil {
+		return err
+	}
+	return d.runHooks(afterFind, out)
 }
 
 // Scan 将查询结果扫描到结构体
 func (d *Database) Scan(dest interface{}) error {
-	return d.db.Scan(dest).Error
+	return query.TimeoutError(nil, d.db.Scan(dest).Error)
 }
 
 // ScanRows 扫描行
@@ -499,19 +588,22 @@ func (d *Database) ScanRows(rows *sql.Rows, dest interface{}) error {
 
 // Query 执行查询并返回结果集(集合)
 func (d *Database) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	rows, err := d.db.WithContext(d.ctx).Raw(query, args...).Rows()
+	rows, err := d.db.Raw(query, args...).Rows()
 	return rows, err
 }
 
 // QueryRow 执行查询并返回单行结果
 func (d *Database) QueryRow(query string, args ...interface{}) *sql.Row {
-	row := d.db.WithContext(d.ctx).Raw(query, args...).Row()
+	row := d.db.Raw(query, args...).Row()
 	return row
 }
 
 // QueryRows 查询多条记录
 func (d *Database) QueryRows(out interface{}, sqlStr string, values ...interface{}) error {
-	return d.Raw(sqlStr, values...).Scan(out).Error
+	if err := query.TimeoutError(nil, d.Raw(sqlStr, values...).Scan(out).Error); err != nil {
+		return err
+	}
+	return d.runHooks(afterFind, out)
 }
 
 // Raw 执行原生SQL查询
@@ -521,7 +613,10 @@ func (d *Database) Raw(sql string, values ...interface{}) *gorm.DB {
 
 // ScanRaw 执行原生查询并扫描结果
 func (d *Database) ScanRaw(out interface{}, sql string, values ...interface{}) error {
-	return d.Raw(sql, values...).Scan(out).Error
+	if err := query.TimeoutError(nil, d.Raw(sql, values...).Scan(out).Error); err != nil {
+		return err
+	}
+	return d.runHooks(afterFind, out)
 }
 
 // Exec 执行原生SQL
@@ -531,12 +626,76 @@ func (d *Database) Exec(sql string, values ...interface{}) error {
 
 // ExecWithResult 执行原生SQL返回结果
 func (d *Database) ExecWithResult(sqlStr string, values ...interface{}) (sql.Result, error) {
+	if err := d.checkReadOnly(sqlStr); err != nil {
+		return nil, err
+	}
+	if err := d.checkPolicy(sqlStr); err != nil {
+		return nil, err
+	}
+	values, err := d.bindValues(values)
+	if err != nil {
+		return nil, err
+	}
+	if d.dryRun != nil {
+		return d.execDryRun(d.commented(d.db.Statement.Context, sqlStr), values), nil
+	}
 	// 使用原生SQL连接执行语句
-	return d.sqlDB.ExecContext(d.ctx, sqlStr, values...)
+	ctx, cancel := d.statementContext()
+	defer cancel()
+	release, err := d.throttle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	result, err := d.sqlDB.ExecContext(ctx, d.commented(ctx, d.Rebind(sqlStr)), values...)
+	return result, query.TimeoutError(ctx, err)
 }
 
-// QueryPage 分页查询
+// Rebind 将SQL中的 ? 占位符改写为当前数据库的位置参数格式，?? 表示字面量 ?
+// Raw/Exec 等经由 GORM 执行的语句会自动改写，直接使用 SqlDB() 执行时需手动调用
+func (d *Database) Rebind(sqlStr string) string {
+	return builder.Rebind(string(d.dbType), sqlStr)
+}
+
+// QueryPage 分页查询，返回总记录数；不在事务中时计数和数据查询在不同的连接上并发执行
+// dbOption 为 *gorm.DB 或 PageOptions，PageOptions.WithoutTotal 时不计数，通过 HasMore 判断是否有下一页
 func (d *Database) QueryPage(dbOption interface{}, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter ...interface{}) (int64, error) {
+	// 检查过滤条件中的未参数化字面量
+	if err := inspectFilter(filter); err != nil {
+		return 0, err
+	}
+
+	// 分页选项
+	var options *PageOptions
+	switch option := dbOption.(type) {
+	case PageOptions:
+		options = &option
+	case *PageOptions:
+		options = option
+	}
+	var pageOptions *adapter.PageOptions
+	if options != nil {
+		if !options.WithoutTotal {
+			total, handled, err := d.queryPageApproximate(*options, out, page, pageSize, tableName, orderBy, filter)
+			if handled {
+				return total, err
+			}
+		}
+		dbOption = options.DB
+		if dbOption == nil {
+			dbOption = d.db
+		}
+		if options.WithoutTotal || options.StrictOrder {
+			db, ok := dbOption.(*gorm.DB)
+			if !ok {
+				db = d.db
+			}
+			pageOptions = &adapter.PageOptions{DB: db, WithoutTotal: options.WithoutTotal, StrictOrder: options.StrictOrder}
+			dbOption = pageOptions
+			defer func() { options.HasMore = pageOptions.HasMore }()
+		}
+	}
+
 	// 使用适配器的分页查询
 	if d.adapter != nil {
 		if tableName == "" {
@@ -546,28 +705,23 @@ func (d *Database) QueryPage(dbOption interface{}, out interface{}, page, pageSi
 		return d.adapter.QueryPage(dbOption, out, page, pageSize, tableName, orderBy, filter)
 	}
 
-	// 计算总数
-	var total int64
-	err := d.Model(out).Where(filter).Count(&total).Error
-	if err != nil {
-		return 0, err
+	if page <= 0 {
+		page = 1
 	}
-
-	// 如果总数为0，直接返回
-	if total == 0 {
-		return 0, nil
+	if pageSize <= 0 {
+		pageSize = 10
 	}
-
 	// 计算偏移量
 	offset := (page - 1) * pageSize
 
-	// 查询数据
-	err = d.Model(out).Where(filter).Offset(offset).Limit(pageSize).Find(out).Error
-	if err != nil {
-		return 0, err
-	}
-
-	return total, nil
+	// 查询总记录数和分页数据，不在事务中时并发执行
+	return adapter.RunPage(d.db, pageOptions, out, pageSize, func(db *gorm.DB) (int64, error) {
+		var total int64
+		err := db.Model(out).Where(filter).Count(&total).Error
+		return total, err
+	}, func(db *gorm.DB, limit int) error {
+		return db.Model(out).Where(filter).Offset(offset).Limit(limit).Find(out).Error
+	})
 }
 
 // Lock 锁定记录
@@ -730,25 +884,56 @@ func (d *Database) LockMulti(out interface{}, where string, values ...interface{
 
 // Create 创建记录
 func (d *Database) Create(value interface{}) error {
-	return d.db.Create(value).Error
+	if err := d.validate(value); err != nil {
+		return err
+	}
+	if err := d.runHooks(beforeCreate, value); err != nil {
+		return err
+	}
+	err := d.create(value, func(db *gorm.DB) error {
+		return db.Create(value).Error
+	})
+	if err != nil {
+		return err
+	}
+	return d.runHooks(afterCreate, value)
 }
 
 // CreateInBatches 批量创建记录
 func (d *Database) CreateInBatches(value interface{}, batchSize int) error {
-	return d.db.CreateInBatches(value, batchSize).Error
+	if err := d.validate(value); err != nil {
+		return err
+	}
+	if err := d.runHooks(beforeCreate, value); err != nil {
+		return err
+	}
+	err := d.create(value, func(db *gorm.DB) error {
+		return db.CreateInBatches(value, batchSize).Error
+	})
+	if err != nil {
+		return err
+	}
+	return d.runHooks(afterCreate, value)
 }
 
 // Save 保存记录
 func (d *Database) Save(value interface{}) error {
-	return d.db.Save(value).Error
+	if err := d.validate(value); err != nil {
+		return err
+	}
+	creates, updates := d.saveStages(value)
+	if err := d.runSaveHooks(creates, updates, beforeCreate, beforeUpdate); err != nil {
+		return err
+	}
+	if err := d.db.Save(value).Error; err != nil {
+		return err
+	}
+	return d.runSaveHooks(creates, updates, afterCreate, afterUpdate)
 }
 
-// BatchInsert 批量插入
+// BatchInsert 批量插入，超过参数上限时自动分批，所有批次在同一事务中执行
 func (d *Database) BatchInsert(table string, columns []string, values [][]interface{}) error {
-	if d.adapter != nil {
-		return d.adapter.BatchInsert(d.db, table, columns, values)
-	}
-	return errors.New("数据库适配器不支持批量插入")
+	return d.BatchInsertWithOptions(table, columns, values, BatchInsertOptions{})
 }
 
 // MergeInto 合并插入（UPSERT）
@@ -763,12 +948,27 @@ func (d *Database) MergeInto(table string, columns []string, values [][]interfac
 
 // Update 更新记录
 func (d *Database) Update(model interface{}, column string, value interface{}) error {
-	return d.Model(model).Update(column, value).Error
+	if err := d.runHooks(beforeUpdate, model); err != nil {
+		return err
+	}
+	if err := d.Model(model).Update(column, value).Error; err != nil {
+		return err
+	}
+	return d.runHooks(afterUpdate, model)
 }
 
 // Updates 批量更新记录
 func (d *Database) Updates(model interface{}, values interface{}) error {
-	return d.Model(model).Updates(values).Error
+	if err := d.validatePartial(model, values); err != nil {
+		return err
+	}
+	if err := d.runHooks(beforeUpdate, model); err != nil {
+		return err
+	}
+	if err := d.Model(model).Updates(values).Error; err != nil {
+		return err
+	}
+	return d.runHooks(afterUpdate, model)
 }
 
 // UpdateColumn 更新列
@@ -785,7 +985,13 @@ func (d *Database) UpdateColumns(model interface{}, values interface{}) error {
 
 // Delete 删除记录
 func (d *Database) Delete(value interface{}, where ...interface{}) error {
-	return d.db.Delete(value, where...).Error
+	if err := d.runHooks(beforeDelete, value); err != nil {
+		return err
+	}
+	if err := d.db.Delete(value, where...).Error; err != nil {
+		return err
+	}
+	return d.runHooks(afterDelete, value)
 }
 
 // Unscoped 不使用软删除
@@ -795,43 +1001,71 @@ func (d *Database) Unscoped() *gorm.DB {
 
 // ==================== 事务操作 ====================
 
-// Transaction 执行事务
+// Transaction 执行事务，fc 返回错误或 panic 时回滚
+// 已在事务中时以保存点执行嵌套事务；上下文中有同一数据库的环境事务时加入该事务
 func (d *Database) Transaction(fc func(tx *Database) error) error {
-	return d.db.Transaction(func(tx *gorm.DB) error {
+	// 试运行模式不开启真实事务
+	if d.dryRun != nil {
+		return fc(d)
+	}
+	if ambient, ok := d.ambientTx(d.db.Statement.Context); ok {
+		return ambient.Transaction(fc)
+	}
+
+	state := &txState{}
+	committed := false
+	defer func() { d.endTx(state, committed) }()
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		defer d.track(HeldTransaction, "")()
 		// 创建事务数据库
-		txDB := &Database{
-			db:       tx,
-			sqlDB:    d.sqlDB,
-			dbType:   d.dbType,
-			deadlock: d.deadlock,
-			ctx:      d.ctx,
-			adapter:  d.adapter,
-		}
-		return fc(txDB)
-	})
+		return fc(d.withTx(tx, state))
+	}, d.txOptions...)
+	committed = err == nil
+	return err
 }
 
 // Begin 开始事务
 func (d *Database) Begin() *Database {
-	tx := d.db.Begin()
-	return &Database{
-		db:       tx,
-		sqlDB:    d.sqlDB,
-		dbType:   d.dbType,
-		deadlock: d.deadlock,
-		ctx:      d.ctx,
-		adapter:  d.adapter,
+	if d.dryRun != nil {
+		return d
+	}
+	db := d.db.Begin(d.txOptions...)
+	if db.Error != nil {
+		return d.session(db)
 	}
+	tx := d.withTx(db, &txState{})
+	tx.release = d.track(HeldTransaction, "")
+	return tx
 }
 
-// Commit 提交事务
+// Commit 提交事务，成功后执行 OnCommit 回调，失败时执行 OnRollback 回调
 func (d *Database) Commit() error {
-	return d.db.Commit().Error
+	if d.dryRun != nil {
+		return nil
+	}
+	if d.release != nil {
+		defer d.release()
+	}
+	err := d.db.Commit().Error
+	if d.tx != nil {
+		d.tx.finish(err == nil)
+	}
+	return err
 }
 
-// Rollback 回滚事务
+// Rollback 回滚事务，之后执行 OnRollback 回调
 func (d *Database) Rollback() error {
-	return d.db.Rollback().Error
+	if d.dryRun != nil {
+		return nil
+	}
+	if d.release != nil {
+		defer d.release()
+	}
+	err := d.db.Rollback().Error
+	if d.tx != nil {
+		d.tx.finish(false)
+	}
+	return err
 }
 
 // ==================== 辅助函数 ====================
@@ -843,6 +1077,20 @@ func formatWhere(where string) string {
 	return where
 }
 
+// inspectFilter 检查过滤条件中的SQL字符串（仅检查SQL部分，不检查参数值）
+func inspectFilter(filter []interface{}) error {
+	if len(filter) == 0 {
+		return nil
+	}
+	switch f := filter[0].(type) {
+	case string:
+		return builder.Inspect(f)
+	case []interface{}:
+		return inspectFilter(f)
+	}
+	return nil
+}
+
 // reflectTableName 反射获取表名
 func reflectTableName(value interface{}) string {
 	if value == nil {
@@ -913,6 +1161,9 @@ func reflectKeys(value interface{}) interface{} {
 
 // Close 关闭数据库连接
 func (d *Database) Close() error {
+	if d.stopKeepAlive != nil {
+		d.stopKeepAlive()
+	}
 	if d.sqlDB != nil {
 		return d.sqlDB.Close()
 	}
@@ -3,6 +3,7 @@ package dialect
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // Dialect 数据库方言接口
@@ -487,27 +488,38 @@ func (d *BaseDialect) UpsertSQL(table string, columns, uniqueColumns, updateColu
 	return ""
 }
 
+// 方言注册锁
+var dialectMu sync.RWMutex
+
 // 方言工厂映射
 var dialectMap = map[string]func() Dialect{
 	"mysql":      func() Dialect { return NewMySQLDialect() },
 	"postgres":   func() Dialect { return NewPostgresDialect() },
 	"postgresql": func() Dialect { return NewPostgresDialect() },
 	"sqlite":     func() Dialect { return NewSQLiteDialect() },
+	"sqlite3":    func() Dialect { return NewSQLiteDialect() },
 	"sqlserver":  func() Dialect { return NewSQLServerDialect() },
 	"mssql":      func() Dialect { return NewSQLServerDialect() },
 	"oracle":     func() Dialect { return NewOracleDialect() },
 	"clickhouse": func() Dialect { return NewClickHouseDialect() },
 	"mariadb":    func() Dialect { return NewMariaDBDialect() },
+	"tidb":       func() Dialect { return NewTiDBDialect() },
+	"oceanbase":  func() Dialect { return NewOceanBaseDialect() },
 }
 
 // 注册自定义方言
 func RegisterDialect(name string, factory func() Dialect) {
+	dialectMu.Lock()
+	defer dialectMu.Unlock()
 	dialectMap[strings.ToLower(name)] = factory
 }
 
 // 获取方言实例
 func GetDialect(name string) Dialect {
-	if factory, ok := dialectMap[strings.ToLower(name)]; ok {
+	dialectMu.RLock()
+	factory, ok := dialectMap[strings.ToLower(name)]
+	dialectMu.RUnlock()
+	if ok {
 		return factory()
 	}
 	return NewBaseDialect(name)
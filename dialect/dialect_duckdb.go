@@ -0,0 +1,55 @@
+package dialect
+
+import "fmt"
+
+// DuckDB方言，语法与 PostgreSQL 基本兼容
+type DuckDBDialect struct {
+	*PostgresDialect
+}
+
+// 创建DuckDB方言
+func NewDuckDBDialect() *DuckDBDialect {
+	return &DuckDBDialect{&PostgresDialect{NewBaseDialect("duckdb")}}
+}
+
+// 获取表列表
+func (d *DuckDBDialect) GetTablesSQL() string {
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() ORDER BY table_name"
+}
+
+// 获取表结构
+func (d *DuckDBDialect) GetTableSchemaSQL(table string) string {
+	return fmt.Sprintf("DESCRIBE %s", d.QuoteTable(table))
+}
+
+// 获取索引列表
+func (d *DuckDBDialect) GetIndexesSQL(table string) string {
+	return fmt.Sprintf("SELECT index_name, is_unique, sql FROM duckdb_indexes() WHERE table_name = %s", d.QuoteValue(table))
+}
+
+// 获取外键列表
+func (d *DuckDBDialect) GetForeignKeysSQL(table string) string {
+	return fmt.Sprintf("SELECT constraint_text FROM duckdb_constraints() WHERE table_name = %s AND constraint_type = 'FOREIGN KEY'", d.QuoteValue(table))
+}
+
+// 获取数据库版本
+func (d *DuckDBDialect) GetVersionSQL() string {
+	return "SELECT version()"
+}
+
+// 行锁，DuckDB 不支持
+func (d *DuckDBDialect) ForUpdateSQL() string {
+	return ""
+}
+
+// 共享锁，DuckDB 不支持
+func (d *DuckDBDialect) ForShareSQL() string {
+	return ""
+}
+
+// 初始化方言
+func init() {
+	RegisterDialect("duckdb", func() Dialect {
+		return NewDuckDBDialect()
+	})
+}
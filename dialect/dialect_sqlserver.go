@@ -34,6 +34,11 @@ func (d *SQLServerDialect) BuildLimit(query string, offset, limit int) string {
 	return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", query, offset, limit)
 }
 
+// 获取序列值
+func (d *SQLServerDialect) GetSequenceSQL(sequence string) string {
+	return fmt.Sprintf("SELECT NEXT VALUE FOR %s", sequence)
+}
+
 // 获取表列表
 func (d *SQLServerDialect) GetTablesSQL() string {
 	return "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE'"
@@ -0,0 +1,474 @@
+// Package docstore 在关系型数据库上存储JSON文档
+// 文档整体序列化后保存在 doc 列（Postgres 使用 JSONB，MySQL 系使用 JSON），Options.Indexes 声明的字段
+// 在写入时提取到独立列并建立索引，按这些字段查询可使用普通索引，其余字段通过数据库的 JSON 函数查询。
+// 文档表与其他表位于同一数据库，可通过 WithTx 与普通 gosqlx 操作在同一事务中读写
+//
+//	users, err := docstore.New[Profile](db, "profiles", docstore.Options{
+//		Indexes: []docstore.Index{{Field: "email", Unique: true}, {Field: "address.city"}},
+//	})
+//	err = users.Save("u1", profile)
+//	docs, err := users.Find(docstore.Filter{"address.city": "Berlin"})
+package docstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound 文档不存在
+var ErrNotFound = errors.New("文档不存在")
+
+// ErrInvalidField 字段路径不合法
+var ErrInvalidField = errors.New("不合法的文档字段")
+
+// fieldPattern 合法的字段路径，以点号分隔
+var fieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// Index 提取到独立列的文档字段
+type Index struct {
+	Field  string // 文档中的字段路径（JSON 键名），以点号分隔，如 email、address.city
+	Column string // 列名，默认将 Field 中的点号替换为下划线
+	Type   string // 列类型，默认 VARCHAR(255)
+	Unique bool   // 是否唯一索引
+}
+
+// Options 集合选项
+type Options struct {
+	Indexes []Index // 提取到独立列并建立索引的字段
+}
+
+// Filter 查询条件，键为字段路径，值为相等比较的值
+type Filter map[string]interface{}
+
+// Document 文档及其元数据
+type Document[T any] struct {
+	ID        string    // 文档ID
+	Value     T         // 文档内容
+	CreatedAt time.Time // 创建时间
+	UpdatedAt time.Time // 更新时间
+}
+
+// Collection 文档集合，可并发使用
+type Collection[T any] struct {
+	db      *gosqlx.Database
+	table   string
+	indexes []Index
+}
+
+// New 创建文档集合，表不存在时自动创建；新增的索引字段会自动添加列和索引，已有文档需调用 Reindex 回填
+func New[T any](db *gosqlx.Database, table string, options Options) (*Collection[T], error) {
+	c := &Collection[T]{db: db, table: table}
+	for _, index := range options.Indexes {
+		if !fieldPattern.MatchString(index.Field) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidField, index.Field)
+		}
+		if index.Column == "" {
+			index.Column = strings.ReplaceAll(index.Field, ".", "_")
+		}
+		if index.Type == "" {
+			index.Type = "VARCHAR(255)"
+		}
+		c.indexes = append(c.indexes, index)
+	}
+	if err := c.ensureTable(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WithTx 返回使用指定数据库（通常为事务）的集合副本
+func (c *Collection[T]) WithTx(tx *gosqlx.Database) *Collection[T] {
+	copied := *c
+	copied.db = tx
+	return &copied
+}
+
+// Insert 插入文档，ID 已存在时返回唯一约束错误
+func (c *Collection[T]) Insert(id string, value T) error {
+	data, fields, err := c.encode(value)
+	if err != nil {
+		return err
+	}
+	if err := c.insert(c.db.DB(), id, data, fields, time.Now()); err != nil {
+		return fmt.Errorf("插入文档失败: %w", err)
+	}
+	return nil
+}
+
+// Save 保存文档，ID 不存在时插入，存在时替换文档内容
+func (c *Collection[T]) Save(id string, value T) error {
+	data, fields, err := c.encode(value)
+	if err != nil {
+		return err
+	}
+	err = c.db.DB().Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := c.from(tx).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		if count == 0 {
+			return c.insert(tx, id, data, fields, now)
+		}
+		values := map[string]interface{}{"doc": data, "updated_at": now}
+		for i, index := range c.indexes {
+			values[index.Column] = fields[i]
+		}
+		return c.from(tx).Where("id = ?", id).Updates(values).Error
+	})
+	if err != nil {
+		return fmt.Errorf("保存文档失败: %w", err)
+	}
+	return nil
+}
+
+// Get 按ID获取文档，不存在时返回 ErrNotFound
+func (c *Collection[T]) Get(id string) (*Document[T], error) {
+	docs, err := c.scan(c.from(c.db.DB()).Where("id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return &docs[0], nil
+}
+
+// Delete 删除文档，不存在时返回 ErrNotFound
+func (c *Collection[T]) Delete(id string) error {
+	result := c.from(c.db.DB()).Where("id = ?", id).Delete(nil)
+	if result.Error != nil {
+		return fmt.Errorf("删除文档失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return nil
+}
+
+// Find 查询满足条件的全部文档，按ID排序
+func (c *Collection[T]) Find(filter Filter) ([]Document[T], error) {
+	tx, err := c.where(c.from(c.db.DB()), filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.scan(tx.Order("id"))
+}
+
+// FindPage 分页查询，page 从1开始，返回当页文档和总数
+func (c *Collection[T]) FindPage(filter Filter, page, pageSize int) ([]Document[T], int64, error) {
+	total, err := c.Count(filter)
+	if err != nil || total == 0 {
+		return nil, total, err
+	}
+	tx, err := c.where(c.from(c.db.DB()), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	docs, err := c.scan(tx.Order("id").Offset((max(page, 1) - 1) * pageSize).Limit(pageSize))
+	return docs, total, err
+}
+
+// Count 统计满足条件的文档数
+func (c *Collection[T]) Count(filter Filter) (int64, error) {
+	tx, err := c.where(c.from(c.db.DB()), filter)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计文档失败: %w", err)
+	}
+	return count, nil
+}
+
+// Reindex 按当前的索引声明重新提取全部文档的索引列，用于新增索引字段后回填
+func (c *Collection[T]) Reindex() error {
+	if len(c.indexes) == 0 {
+		return nil
+	}
+	// 按ID顺序分批处理
+	var err error
+	for lastID := ""; ; {
+		var rows []struct {
+			ID  string
+			Doc string
+		}
+		err = c.from(c.db.DB()).Select("id, doc").Where("id > ?", lastID).Order("id").Limit(500).Scan(&rows).Error
+		if err != nil || len(rows) == 0 {
+			break
+		}
+		for _, row := range rows {
+			if err = c.reindex(row.ID, row.Doc); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+		lastID = rows[len(rows)-1].ID
+	}
+	if err != nil {
+		return fmt.Errorf("重建索引列失败: %w", err)
+	}
+	return nil
+}
+
+// ==================== 内部实现 ====================
+
+// reindex 更新单个文档的索引列
+func (c *Collection[T]) reindex(id, data string) error {
+	fields, err := c.extract([]byte(data))
+	if err != nil {
+		return fmt.Errorf("解析文档 %s 失败: %w", id, err)
+	}
+	values := make(map[string]interface{}, len(fields))
+	for i, index := range c.indexes {
+		values[index.Column] = fields[i]
+	}
+	return c.from(c.db.DB()).Where("id = ?", id).Updates(values).Error
+}
+
+// from 指定文档表
+func (c *Collection[T]) from(db *gorm.DB) *gorm.DB {
+	return db.Table(c.table)
+}
+
+// insert 插入一行
+func (c *Collection[T]) insert(db *gorm.DB, id string, data string, fields []interface{}, now time.Time) error {
+	values := map[string]interface{}{"id": id, "doc": data, "created_at": now, "updated_at": now}
+	for i, index := range c.indexes {
+		values[index.Column] = fields[i]
+	}
+	return c.from(db).Create(values).Error
+}
+
+// scan 查询并解码文档
+func (c *Collection[T]) scan(tx *gorm.DB) ([]Document[T], error) {
+	var rows []struct {
+		ID        string
+		Doc       string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+	if err := tx.Select("id, doc, created_at, updated_at").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("查询文档失败: %w", err)
+	}
+	docs := make([]Document[T], len(rows))
+	for i, row := range rows {
+		docs[i] = Document[T]{ID: row.ID, CreatedAt: row.CreatedAt, UpdatedAt: row.UpdatedAt}
+		if err := json.Unmarshal([]byte(row.Doc), &docs[i].Value); err != nil {
+			return nil, fmt.Errorf("解析文档 %s 失败: %w", row.ID, err)
+		}
+	}
+	return docs, nil
+}
+
+// where 将查询条件转换为SQL，索引字段比较独立列，其余字段使用 JSON 函数
+func (c *Collection[T]) where(tx *gorm.DB, filter Filter) (*gorm.DB, error) {
+	fields := make([]string, 0, len(filter))
+	for field := range filter {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		value := filter[field]
+		if index, ok := c.index(field); ok {
+			tx = tx.Where(tx.Statement.Quote(index.Column)+" = ?", value)
+			continue
+		}
+		expr, err := c.jsonValue(field)
+		if err != nil {
+			return nil, err
+		}
+		// Postgres 的 ->> 返回文本，参数统一转为文本比较
+		if c.db.Type() == gosqlx.PostgresSQL {
+			value = textValue(value)
+		}
+		tx = tx.Where(expr+" = ?", value)
+	}
+	return tx, nil
+}
+
+// index 查找字段对应的索引列
+func (c *Collection[T]) index(field string) (Index, bool) {
+	for _, index := range c.indexes {
+		if index.Field == field {
+			return index, true
+		}
+	}
+	return Index{}, false
+}
+
+// jsonValue 返回读取文档字段的SQL表达式
+func (c *Collection[T]) jsonValue(field string) (string, error) {
+	if !fieldPattern.MatchString(field) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidField, field)
+	}
+	switch c.db.Type() {
+	case gosqlx.PostgresSQL:
+		return fmt.Sprintf("doc #>> '{%s}'", strings.ReplaceAll(field, ".", ",")), nil
+	case gosqlx.MySQL, gosqlx.MariaDB, gosqlx.TiDB, gosqlx.OceanBase:
+		return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(doc, '$.%s'))", field), nil
+	case gosqlx.SQLServer, gosqlx.Oracle:
+		return fmt.Sprintf("JSON_VALUE(doc, '$.%s')", field), nil
+	case gosqlx.SQLite:
+		return fmt.Sprintf("json_extract(doc, '$.%s')", field), nil
+	default:
+		return "", fmt.Errorf("%s 不支持查询非索引字段 %s", c.db.Type(), field)
+	}
+}
+
+// encode 序列化文档并提取索引字段
+func (c *Collection[T]) encode(value T) (string, []interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", nil, fmt.Errorf("序列化文档失败: %w", err)
+	}
+	fields, err := c.extract(data)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(data), fields, nil
+}
+
+// extract 按索引声明的顺序提取字段值，字段不存在时为 nil
+func (c *Collection[T]) extract(data []byte) ([]interface{}, error) {
+	if len(c.indexes) == 0 {
+		return nil, nil
+	}
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("解析文档失败: %w", err)
+	}
+
+	fields := make([]interface{}, len(c.indexes))
+	for i, index := range c.indexes {
+		value := doc
+		for _, key := range strings.Split(index.Field, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				value = nil
+				break
+			}
+			value = object[key]
+		}
+		fields[i] = columnValue(value)
+	}
+	return fields, nil
+}
+
+// columnValue 将JSON值转换为列值，对象和数组保存为JSON文本
+func columnValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return v
+	}
+}
+
+// textValue 将查询参数转换为与 JSON 文本表示一致的字符串
+func textValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return strings.Trim(string(data), `"`)
+	}
+}
+
+// ensureTable 创建文档表，补充缺少的索引列和索引
+func (c *Collection[T]) ensureTable() error {
+	db := c.db.DB()
+	migrator := db.Migrator()
+	quote := db.Statement.Quote
+
+	if !migrator.HasTable(c.table) {
+		columns := []string{
+			"id VARCHAR(64) NOT NULL PRIMARY KEY",
+			"doc " + c.docType() + " NOT NULL",
+			"created_at " + c.timeType() + " NOT NULL",
+			"updated_at " + c.timeType() + " NOT NULL",
+		}
+		for _, index := range c.indexes {
+			columns = append(columns, quote(index.Column)+" "+index.Type)
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quote(c.table), strings.Join(columns, ", "))).Error; err != nil {
+			return fmt.Errorf("创建文档表失败: %w", err)
+		}
+	}
+
+	for _, index := range c.indexes {
+		if !migrator.HasColumn(c.table, index.Column) {
+			if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD %s %s", quote(c.table), quote(index.Column), index.Type)).Error; err != nil {
+				return fmt.Errorf("添加索引列 %s 失败: %w", index.Column, err)
+			}
+		}
+		name := "idx_" + c.table + "_" + index.Column
+		if migrator.HasIndex(c.table, name) {
+			continue
+		}
+		create := "CREATE INDEX"
+		if index.Unique {
+			create = "CREATE UNIQUE INDEX"
+		}
+		if err := db.Exec(fmt.Sprintf("%s %s ON %s (%s)", create, quote(name), quote(c.table), quote(index.Column))).Error; err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// docType 文档列类型
+func (c *Collection[T]) docType() string {
+	switch c.db.Type() {
+	case gosqlx.PostgresSQL:
+		return "JSONB"
+	case gosqlx.MySQL, gosqlx.MariaDB, gosqlx.TiDB, gosqlx.OceanBase:
+		return "JSON"
+	case gosqlx.SQLServer:
+		return "NVARCHAR(MAX)"
+	case gosqlx.Oracle:
+		return "CLOB"
+	default:
+		return "TEXT"
+	}
+}
+
+// timeType 时间列类型
+func (c *Collection[T]) timeType() string {
+	switch c.db.Type() {
+	case gosqlx.SQLServer:
+		return "DATETIME2"
+	case gosqlx.MySQL, gosqlx.MariaDB, gosqlx.TiDB, gosqlx.OceanBase:
+		return "DATETIME(3)"
+	default:
+		return "TIMESTAMP"
+	}
+}
@@ -0,0 +1,124 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ==================== 试运行 ====================
+
+// Statement 生成的SQL语句
+type Statement struct {
+	SQL     string        // 带占位符的SQL
+	Args    []interface{} // 参数值
+	Preview string        // 参数内联后的SQL，仅用于日志和调试，不可直接执行
+}
+
+// String 返回内联参数后的预览SQL
+func (s Statement) String() string {
+	return s.Preview
+}
+
+// DryRun 返回试运行模式的数据库实例，所有操作只生成SQL而不访问数据库
+// 生成的语句通过 Statements 获取；事务方法直接在当前实例上执行，返回结果集的方法
+// （Query、QueryRow 等）记录语句后返回 gorm.ErrDryRunModeUnsupported
+//
+//	dry := db.DryRun()
+//	dry.Updates(&User{ID: 1}, map[string]interface{}{"age": 30})
+//	for _, stmt := range dry.Statements() {
+//		log.Println(stmt.Preview)
+//	}
+func (d *Database) DryRun() *Database {
+	recorder := &dryRunRecorder{Interface: logger.Discard}
+	dry := d.session(d.db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true, Logger: recorder}))
+	dry.dryRun = recorder
+	return dry
+}
+
+// IsDryRun 是否为试运行模式
+func (d *Database) IsDryRun() bool {
+	return d.dryRun != nil
+}
+
+// Statements 返回试运行模式下生成的语句，非试运行模式返回 nil
+func (d *Database) Statements() []Statement {
+	if d.dryRun == nil {
+		return nil
+	}
+	return d.dryRun.list()
+}
+
+// ToSQL 在试运行模式下执行 fn，返回其生成的全部语句
+//
+//	stmts, err := db.ToSQL(func(tx *gosqlx.Database) error {
+//		return tx.BatchUpdate("users", "id", rows)
+//	})
+func (d *Database) ToSQL(fn func(tx *Database) error) ([]Statement, error) {
+	dry := d.DryRun()
+	err := fn(dry)
+	return dry.Statements(), err
+}
+
+// session 以新的 GORM 会话复制数据库实例，保留校验器、试运行等设置
+func (d *Database) session(db *gorm.DB) *Database {
+	copied := *d
+	copied.db = db
+	return &copied
+}
+
+// execDryRun 记录绕过 GORM 直接执行的语句
+func (d *Database) execDryRun(sqlStr string, values []interface{}) sql.Result {
+	d.dryRun.add(Statement{SQL: sqlStr, Args: values, Preview: d.db.Dialector.Explain(sqlStr, values...)})
+	return driver.RowsAffected(0)
+}
+
+// dryRunRecorder 以 GORM 日志器的形式记录生成的语句
+// GORM 在 Trace 中先调用 ParamsFilter 传入原始SQL和参数，再由方言生成内联参数的SQL
+type dryRunRecorder struct {
+	logger.Interface
+	mutex      sync.Mutex
+	pending    Statement
+	statements []Statement
+}
+
+// LogMode 忽略日志级别
+func (r *dryRunRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+// ParamsFilter 获取原始SQL和参数
+func (r *dryRunRecorder) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	r.pending = Statement{SQL: sql, Args: params}
+	return sql, params
+}
+
+// Trace 记录一条语句
+func (r *dryRunRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	preview, _ := fc()
+	stmt := r.pending
+	stmt.Preview = preview
+	r.statements = append(r.statements, stmt)
+	r.pending = Statement{}
+}
+
+// add 记录一条语句
+func (r *dryRunRecorder) add(stmt Statement) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.statements = append(r.statements, stmt)
+}
+
+// list 返回已记录语句的副本
+func (r *dryRunRecorder) list() []Statement {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Statement(nil), r.statements...)
+}
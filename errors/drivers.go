@@ -0,0 +1,289 @@
+package errors
+
+import (
+	stderrors "errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	mssql "github.com/microsoft/go-mssqldb"
+	"github.com/sijms/go-ora/v2/network"
+)
+
+// parsers 各驱动的错误解析器，按顺序尝试
+var parsers = []func(err error) *Error{
+	parseMySQL,
+	parsePostgres,
+	parseSQLServer,
+	parseOracle,
+	parseSQLite,
+}
+
+// ==================== MySQL ====================
+
+var (
+	mysqlKeyPattern        = regexp.MustCompile("for key '([^']+)'")
+	mysqlConstraintPattern = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	mysqlColumnPattern     = regexp.MustCompile("Column '([^']+)'")
+	mysqlTablePattern      = regexp.MustCompile("`[^`]+`\\.`([^`]+)`")
+	mysqlCheckPattern      = regexp.MustCompile("constraint '([^']+)'")
+)
+
+// parseMySQL 解析 MySQL/MariaDB/TiDB/OceanBase 错误
+func parseMySQL(err error) *Error {
+	var mysqlErr *mysql.MySQLError
+	if !stderrors.As(err, &mysqlErr) {
+		if stderrors.Is(err, mysql.ErrInvalidConn) {
+			return &Error{Kind: ErrConnection, Driver: "mysql"}
+		}
+		return nil
+	}
+
+	dbErr := &Error{Driver: "mysql", code: strconv.Itoa(int(mysqlErr.Number))}
+	switch mysqlErr.Number {
+	case 1062, 1586:
+		dbErr.Kind = ErrDuplicateKey
+		dbErr.Constraint = submatch(mysqlKeyPattern, mysqlErr.Message)
+		// MySQL 8 的索引名带有表名前缀: users.idx_email
+		if table, key, ok := strings.Cut(dbErr.Constraint, "."); ok {
+			dbErr.Table, dbErr.Constraint = table, key
+		}
+	case 1451, 1452, 1216, 1217:
+		dbErr.Kind = ErrForeignKeyViolation
+		dbErr.Constraint = submatch(mysqlConstraintPattern, mysqlErr.Message)
+		dbErr.Table = submatch(mysqlTablePattern, mysqlErr.Message)
+	case 1048, 1364:
+		dbErr.Kind = ErrNotNullViolation
+		dbErr.Column = submatch(mysqlColumnPattern, mysqlErr.Message)
+	case 3819:
+		dbErr.Kind = ErrCheckViolation
+		dbErr.Constraint = submatch(mysqlCheckPattern, mysqlErr.Message)
+	case 1205:
+		dbErr.Kind = ErrLockTimeout
+	case 1213:
+		dbErr.Kind = ErrDeadlock
+	case 1040, 1053, 1129, 1152, 1153, 1158, 1159, 1160, 1161, 2002, 2003, 2006, 2013:
+		dbErr.Kind = ErrConnection
+	default:
+		return nil
+	}
+	return dbErr
+}
+
+// ==================== Postgres ====================
+
+// parsePostgres 解析 Postgres 错误（SQLSTATE）
+func parsePostgres(err error) *Error {
+	var pgErr *pgconn.PgError
+	if !stderrors.As(err, &pgErr) {
+		var connectErr *pgconn.ConnectError
+		if stderrors.As(err, &connectErr) {
+			return &Error{Kind: ErrConnection, Driver: "postgres"}
+		}
+		return nil
+	}
+
+	dbErr := &Error{
+		Driver:     "postgres",
+		Constraint: pgErr.ConstraintName,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		code:       pgErr.Code,
+	}
+	switch {
+	case pgErr.Code == "23505":
+		dbErr.Kind = ErrDuplicateKey
+	case pgErr.Code == "23503":
+		dbErr.Kind = ErrForeignKeyViolation
+	case pgErr.Code == "23502":
+		dbErr.Kind = ErrNotNullViolation
+	case pgErr.Code == "23514":
+		dbErr.Kind = ErrCheckViolation
+	case pgErr.Code == "55P03":
+		dbErr.Kind = ErrLockTimeout
+	case pgErr.Code == "40P01":
+		dbErr.Kind = ErrDeadlock
+	case pgErr.Code == "40001":
+		dbErr.Kind = ErrSerializationFailure
+	case strings.HasPrefix(pgErr.Code, "08"), pgErr.Code == "57P01", pgErr.Code == "57P02", pgErr.Code == "57P03":
+		dbErr.Kind = ErrConnection
+	default:
+		return nil
+	}
+	return dbErr
+}
+
+// ==================== SQLServer ====================
+
+var (
+	sqlserverConstraintPattern = regexp.MustCompile(`constraint "([^"]+)"|constraint '([^']+)'`)
+	sqlserverIndexPattern      = regexp.MustCompile(`unique index '([^']+)'`)
+	sqlserverObjectPattern     = regexp.MustCompile(`object '([^']+)'|table "([^"]+)"`)
+	sqlserverColumnPattern     = regexp.MustCompile(`column '([^']+)'`)
+)
+
+// parseSQLServer 解析 SQLServer 错误
+func parseSQLServer(err error) *Error {
+	var sqlErr mssql.Error
+	if !stderrors.As(err, &sqlErr) {
+		var sqlErrPtr *mssql.Error
+		if !stderrors.As(err, &sqlErrPtr) {
+			return nil
+		}
+		sqlErr = *sqlErrPtr
+	}
+
+	dbErr := &Error{Driver: "sqlserver", code: strconv.Itoa(int(sqlErr.Number))}
+	switch sqlErr.Number {
+	case 2627, 2601:
+		dbErr.Kind = ErrDuplicateKey
+		dbErr.Constraint = submatch(sqlserverConstraintPattern, sqlErr.Message)
+		if dbErr.Constraint == "" {
+			dbErr.Constraint = submatch(sqlserverIndexPattern, sqlErr.Message)
+		}
+		dbErr.Table = submatch(sqlserverObjectPattern, sqlErr.Message)
+	case 547:
+		// 547 同时用于外键和检查约束
+		if strings.Contains(sqlErr.Message, "CHECK") {
+			dbErr.Kind = ErrCheckViolation
+		} else {
+			dbErr.Kind = ErrForeignKeyViolation
+		}
+		dbErr.Constraint = submatch(sqlserverConstraintPattern, sqlErr.Message)
+		dbErr.Table = submatch(sqlserverObjectPattern, sqlErr.Message)
+	case 515:
+		dbErr.Kind = ErrNotNullViolation
+		dbErr.Column = submatch(sqlserverColumnPattern, sqlErr.Message)
+		dbErr.Table = submatch(sqlserverObjectPattern, sqlErr.Message)
+	case 1222:
+		dbErr.Kind = ErrLockTimeout
+	case 1205:
+		dbErr.Kind = ErrDeadlock
+	case 3960:
+		dbErr.Kind = ErrSerializationFailure
+	case 233, 10053, 10054, 10060, 10061, 18456, 4060:
+		dbErr.Kind = ErrConnection
+	default:
+		return nil
+	}
+	return dbErr
+}
+
+// ==================== Oracle ====================
+
+var (
+	oracleCodePattern       = regexp.MustCompile(`ORA-(\d{5})`)
+	oracleConstraintPattern = regexp.MustCompile(`constraint \(([^)]+)\)`)
+	oracleColumnPattern     = regexp.MustCompile(`into \("[^"]+"\."([^"]+)"\."([^"]+)"\)`)
+)
+
+// parseOracle 解析 Oracle 错误，驱动错误类型不可用时从 ORA-xxxxx 错误码识别
+func parseOracle(err error) *Error {
+	var code int
+	var oraErr *network.OracleError
+	if stderrors.As(err, &oraErr) {
+		code = oraErr.ErrCode
+	} else if match := oracleCodePattern.FindStringSubmatch(err.Error()); match != nil {
+		code, _ = strconv.Atoi(match[1])
+	} else {
+		return nil
+	}
+
+	message := err.Error()
+	dbErr := &Error{Driver: "oracle", code: "ORA-" + leftPad(strconv.Itoa(code), 5)}
+	switch code {
+	case 1:
+		dbErr.Kind = ErrDuplicateKey
+		dbErr.Constraint = submatch(oracleConstraintPattern, message)
+	case 2291, 2292:
+		dbErr.Kind = ErrForeignKeyViolation
+		dbErr.Constraint = submatch(oracleConstraintPattern, message)
+	case 1400:
+		dbErr.Kind = ErrNotNullViolation
+		if match := oracleColumnPattern.FindStringSubmatch(message); match != nil {
+			dbErr.Table, dbErr.Column = match[1], match[2]
+		}
+	case 2290:
+		dbErr.Kind = ErrCheckViolation
+		dbErr.Constraint = submatch(oracleConstraintPattern, message)
+	case 54, 30006:
+		dbErr.Kind = ErrLockTimeout
+	case 60:
+		dbErr.Kind = ErrDeadlock
+	case 8177:
+		dbErr.Kind = ErrSerializationFailure
+	case 3113, 3114, 3135, 12170, 12514, 12541, 12543, 28:
+		dbErr.Kind = ErrConnection
+	default:
+		return nil
+	}
+	return dbErr
+}
+
+// ==================== SQLite ====================
+
+var sqliteColumnPattern = regexp.MustCompile(`constraint failed: ([\w.]+)`)
+
+// parseSQLite 解析 SQLite 错误
+func parseSQLite(err error) *Error {
+	var sqliteErr sqlite3.Error
+	if !stderrors.As(err, &sqliteErr) {
+		return nil
+	}
+
+	dbErr := &Error{Driver: "sqlite", code: strconv.Itoa(int(sqliteErr.ExtendedCode))}
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		dbErr.Kind = ErrDuplicateKey
+	case sqlite3.ErrConstraintForeignKey:
+		dbErr.Kind = ErrForeignKeyViolation
+	case sqlite3.ErrConstraintNotNull:
+		dbErr.Kind = ErrNotNullViolation
+	case sqlite3.ErrConstraintCheck:
+		dbErr.Kind = ErrCheckViolation
+		dbErr.Constraint = submatch(sqliteColumnPattern, sqliteErr.Error())
+		return dbErr
+	default:
+		switch sqliteErr.Code {
+		case sqlite3.ErrBusy, sqlite3.ErrLocked:
+			dbErr.Kind = ErrLockTimeout
+			dbErr.code = strconv.Itoa(int(sqliteErr.Code))
+			return dbErr
+		case sqlite3.ErrCantOpen:
+			dbErr.Kind = ErrConnection
+			return dbErr
+		}
+		return nil
+	}
+
+	// UNIQUE constraint failed: users.email
+	if column := submatch(sqliteColumnPattern, sqliteErr.Error()); column != "" {
+		dbErr.Table, dbErr.Column, _ = strings.Cut(column, ".")
+		if dbErr.Column == "" {
+			dbErr.Table, dbErr.Column = "", column
+		}
+	}
+	return dbErr
+}
+
+// submatch 返回首个非空的捕获组
+func submatch(pattern *regexp.Regexp, s string) string {
+	match := pattern.FindStringSubmatch(s)
+	for _, group := range match[min(len(match), 1):] {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
+}
+
+// leftPad 左侧补零
+func leftPad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat("0", width-len(s)) + s
+}
@@ -0,0 +1,143 @@
+// Package errors 将各数据库驱动的错误归一化为统一的错误类型
+//
+//	if err := db.Create(&user); errors.Is(err, gosqlxerrors.ErrDuplicateKey) {
+//		dbErr, _ := gosqlxerrors.Parse(err)
+//		log.Printf("约束 %s 冲突（错误码 %s）", dbErr.Constraint, dbErr.Code())
+//	}
+package errors
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"gorm.io/gorm"
+)
+
+// 归一化的错误类型
+var (
+	ErrDuplicateKey         = stderrors.New("唯一约束冲突")
+	ErrForeignKeyViolation  = stderrors.New("外键约束冲突")
+	ErrNotNullViolation     = stderrors.New("非空约束冲突")
+	ErrCheckViolation       = stderrors.New("检查约束冲突")
+	ErrLockTimeout          = stderrors.New("锁等待超时")
+	ErrDeadlock             = stderrors.New("检测到死锁")
+	ErrConnection           = stderrors.New("数据库连接错误")
+	ErrSerializationFailure = stderrors.New("事务序列化失败")
+)
+
+// Error 归一化的数据库错误
+// 同时匹配归一化类型和原始驱动错误: errors.Is(err, ErrDuplicateKey)、errors.As(err, &mysqlErr)
+type Error struct {
+	Kind       error  // 归一化的错误类型（ErrDuplicateKey 等）
+	Driver     string // 数据库驱动（mysql/postgres/sqlserver/oracle/sqlite）
+	Constraint string // 约束或索引名（可获取时）
+	Table      string // 表名（可获取时）
+	Column     string // 列名（可获取时）
+	code       string // 驱动错误码
+	err        error  // 原始错误
+}
+
+// Error 返回原始错误信息
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Code 驱动错误码，如 MySQL 的 1062、Postgres 的 23505、Oracle 的 ORA-00001
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Unwrap 返回归一化类型和原始错误
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.err}
+}
+
+// Classify 将驱动错误转换为 *Error，无法识别的错误原样返回
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	if dbErr, ok := Parse(err); ok {
+		return dbErr
+	}
+	return err
+}
+
+// Parse 解析驱动错误，无法识别时返回false
+func Parse(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var dbErr *Error
+	if stderrors.As(err, &dbErr) {
+		return dbErr, true
+	}
+
+	for _, parse := range parsers {
+		if dbErr := parse(err); dbErr != nil {
+			dbErr.err = err
+			return dbErr, true
+		}
+	}
+
+	if kind := classifyGeneric(err); kind != nil {
+		return &Error{Kind: kind, err: err}, true
+	}
+	return nil, false
+}
+
+// Code 获取错误的驱动错误码，无法识别时返回空字符串
+func Code(err error) string {
+	if dbErr, ok := Parse(err); ok {
+		return dbErr.code
+	}
+	return ""
+}
+
+// IsRetryable 判断错误是否可通过重试事务解决（死锁、锁超时、序列化失败）
+func IsRetryable(err error) bool {
+	if dbErr, ok := Parse(err); ok {
+		switch dbErr.Kind {
+		case ErrDeadlock, ErrLockTimeout, ErrSerializationFailure:
+			return true
+		}
+	}
+	return false
+}
+
+// classifyGeneric 识别 GORM 转换后的错误和通用连接错误
+func classifyGeneric(err error) error {
+	switch {
+	case stderrors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateKey
+	case stderrors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrForeignKeyViolation
+	case stderrors.Is(err, gorm.ErrCheckConstraintViolated):
+		return ErrCheckViolation
+	case stderrors.Is(err, driver.ErrBadConn),
+		stderrors.Is(err, syscall.ECONNREFUSED),
+		stderrors.Is(err, syscall.ECONNRESET),
+		stderrors.Is(err, syscall.EPIPE):
+		return ErrConnection
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && !stderrors.Is(err, context.DeadlineExceeded) {
+		return ErrConnection
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "invalid connection"),
+		strings.Contains(message, "connection refused"),
+		strings.Contains(message, "broken pipe"),
+		strings.Contains(message, "bad connection"):
+		return ErrConnection
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	mssql "github.com/microsoft/go-mssqldb"
+	"github.com/sijms/go-ora/v2/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDriverErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		kind       error
+		code       string
+		constraint string
+		table      string
+		column     string
+	}{
+		{
+			name:       "mysql duplicate",
+			err:        &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.idx_email'"},
+			kind:       ErrDuplicateKey,
+			code:       "1062",
+			constraint: "idx_email",
+			table:      "users",
+		},
+		{
+			name:       "mysql foreign key",
+			err:        &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`shop`.`orders`, CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"},
+			kind:       ErrForeignKeyViolation,
+			code:       "1452",
+			constraint: "fk_orders_user",
+			table:      "orders",
+		},
+		{
+			name:   "mysql not null",
+			err:    &mysql.MySQLError{Number: 1048, Message: "Column 'email' cannot be null"},
+			kind:   ErrNotNullViolation,
+			code:   "1048",
+			column: "email",
+		},
+		{
+			name: "mysql deadlock",
+			err:  &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+			kind: ErrDeadlock,
+			code: "1213",
+		},
+		{
+			name:       "postgres duplicate",
+			err:        &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", TableName: "users"},
+			kind:       ErrDuplicateKey,
+			code:       "23505",
+			constraint: "users_email_key",
+			table:      "users",
+		},
+		{
+			name: "postgres lock timeout",
+			err:  &pgconn.PgError{Code: "55P03"},
+			kind: ErrLockTimeout,
+			code: "55P03",
+		},
+		{
+			name:       "sqlserver duplicate",
+			err:        mssql.Error{Number: 2627, Message: "Violation of UNIQUE KEY constraint 'UQ_users_email'. Cannot insert duplicate key in object 'dbo.users'."},
+			kind:       ErrDuplicateKey,
+			code:       "2627",
+			constraint: "UQ_users_email",
+			table:      "dbo.users",
+		},
+		{
+			name:       "sqlserver check",
+			err:        mssql.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "CK_users_age".`},
+			kind:       ErrCheckViolation,
+			code:       "547",
+			constraint: "CK_users_age",
+		},
+		{
+			name: "sqlserver deadlock",
+			err:  mssql.Error{Number: 1205, Message: "Transaction was deadlocked"},
+			kind: ErrDeadlock,
+			code: "1205",
+		},
+		{
+			name:       "oracle duplicate",
+			err:        &network.OracleError{ErrCode: 1, ErrMsg: "ORA-00001: unique constraint (APP.UK_USERS_EMAIL) violated"},
+			kind:       ErrDuplicateKey,
+			code:       "ORA-00001",
+			constraint: "APP.UK_USERS_EMAIL",
+		},
+		{
+			name:   "oracle not null from message",
+			err:    fmt.Errorf("插入失败: %w", stderrors.New(`ORA-01400: cannot insert NULL into ("APP"."USERS"."EMAIL")`)),
+			kind:   ErrNotNullViolation,
+			code:   "ORA-01400",
+			table:  "USERS",
+			column: "EMAIL",
+		},
+		{
+			name: "sqlite busy",
+			err:  sqlite3.Error{Code: sqlite3.ErrBusy},
+			kind: ErrLockTimeout,
+			code: "5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(fmt.Errorf("执行失败: %w", tt.err))
+			assert.ErrorIs(t, err, tt.kind)
+			assert.Contains(t, err.Error(), tt.err.Error())
+
+			dbErr, ok := Parse(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.code, dbErr.Code())
+			assert.Equal(t, tt.constraint, dbErr.Constraint)
+			assert.Equal(t, tt.table, dbErr.Table)
+			assert.Equal(t, tt.column, dbErr.Column)
+		})
+	}
+}
+
+func TestSQLiteConstraintErrors(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO users (email) VALUES ('a@example.com')`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (email) VALUES ('a@example.com')`)
+	assert.ErrorIs(t, Classify(err), ErrDuplicateKey)
+	dbErr, ok := Parse(err)
+	require.True(t, ok)
+	assert.Equal(t, "users", dbErr.Table)
+	assert.Equal(t, "email", dbErr.Column)
+	assert.Equal(t, "sqlite", dbErr.Driver)
+
+	_, err = db.Exec(`INSERT INTO users (email) VALUES (NULL)`)
+	assert.ErrorIs(t, Classify(err), ErrNotNullViolation)
+	assert.False(t, IsRetryable(err))
+}
+
+func TestClassifyUnknown(t *testing.T) {
+	plain := stderrors.New("something else")
+	assert.Equal(t, plain, Classify(plain))
+	assert.Nil(t, Classify(nil))
+	assert.Equal(t, "", Code(plain))
+	assert.True(t, IsRetryable(&pgconn.PgError{Code: "40001"}))
+}
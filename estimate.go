@@ -0,0 +1,89 @@
+package gosqlx
+
+import (
+	"fmt"
+
+	"github.com/gzorm/gosqlx/adapter"
+	"gorm.io/gorm"
+)
+
+// ==================== 行数估算 ====================
+
+// PageOptions 分页查询选项，作为 QueryPage 的 dbOption 参数传入，需要读取 HasMore 时传入指针
+// 示例: db.QueryPage(gosqlx.PageOptions{DB: db.DB(), ApproximateTotal: true}, &users, 1, 20, "users", nil)
+//
+//	options := &gosqlx.PageOptions{WithoutTotal: true}
+//	_, err := db.QueryPage(options, &users, 2, 20, "users", []interface{}{"id DESC"})
+//	if options.HasMore { ... }
+type PageOptions struct {
+	DB               interface{} // 数据库连接，为空时使用当前连接
+	ApproximateTotal bool        // 无过滤条件时使用估算行数代替 COUNT(*)
+	Threshold        int64       // 估算行数不小于该值时才使用估算，否则执行精确计数
+	WithoutTotal     bool        // 不查询总数，多取一行判断是否有下一页，QueryPage 返回的总数为 -1
+	HasMore          bool        // 查询后设置：WithoutTotal 时是否还有下一页
+	StrictOrder      bool        // SQLServer 分页缺少排序时返回 adapter.ErrPageOrderRequired，默认使用 ORDER BY (SELECT NULL)
+}
+
+// EstimateCount 快速估算表的行数
+// 使用适配器（adapter.RowEstimator）读取的统计信息（pg_class、information_schema.tables、sys.dm_db_partition_stats、system.parts 等），
+// 结果可能与实际行数存在偏差；适配器不支持或没有统计信息时回退到 COUNT(*)
+func (d *Database) EstimateCount(table string) (int64, error) {
+	estimator, ok := d.adapter.(adapter.RowEstimator)
+	if !ok {
+		return d.exactCount(table)
+	}
+	estimate, ok, err := estimator.EstimateRows(d.db, table)
+	if err != nil {
+		return 0, err
+	}
+	// 从未收集过统计信息时使用精确计数
+	if !ok {
+		return d.exactCount(table)
+	}
+	return estimate, nil
+}
+
+// exactCount 精确统计表行数
+func (d *Database) exactCount(table string) (int64, error) {
+	var total int64
+	if err := d.db.Table(table).Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("统计表 %s 行数失败: %w", table, err)
+	}
+	return total, nil
+}
+
+// queryPageApproximate 使用估算行数的分页查询，返回false表示不适用需走常规分页
+func (d *Database) queryPageApproximate(options PageOptions, out interface{}, page, pageSize int, tableName string, orderBy []interface{}, filter []interface{}) (int64, bool, error) {
+	if !options.ApproximateTotal || len(filter) > 0 {
+		return 0, false, nil
+	}
+	if tableName == "" {
+		tableName = reflectTableName(out)
+	}
+	total, err := d.EstimateCount(tableName)
+	if err != nil {
+		return 0, true, err
+	}
+	if total < options.Threshold {
+		return 0, false, nil
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	db := d.db
+	if gdb, ok := options.DB.(*gorm.DB); ok && gdb != nil {
+		db = gdb
+	}
+	query := db.Table(tableName)
+	for _, order := range orderBy {
+		query = query.Order(order)
+	}
+	if err := query.Offset((page - 1) * pageSize).Limit(pageSize).Find(out).Error; err != nil {
+		return 0, true, err
+	}
+	return total, true, nil
+}
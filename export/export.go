@@ -0,0 +1,304 @@
+// Package export 将查询结果流式导出为 CSV、JSON Lines 或 Parquet，并支持将 CSV、JSON Lines 导入到表
+package export
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/query"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	CSV       Format = "csv"     // 逗号分隔值
+	JSONLines Format = "jsonl"   // 每行一个 JSON 对象
+	Parquet   Format = "parquet" // Apache Parquet 列式存储（未压缩）
+)
+
+// Converter 列值转换函数
+type Converter func(value interface{}) (interface{}, error)
+
+// Options 导出选项
+type Options struct {
+	Context    context.Context      // 查询上下文，默认 context.Background()
+	BatchSize  int                  // 每批写出的行数，Parquet 为每个行组的行数，默认 1000
+	NoHeader   bool                 // CSV 不输出表头
+	Delimiter  rune                 // CSV 分隔符，默认逗号
+	TimeFormat string               // 时间格式，默认 RFC3339（Parquet 始终使用毫秒时间戳）
+	Converters map[string]Converter // 按列名转换值，返回值参与后续格式化
+}
+
+// ErrUnsupportedFormat 不支持的导出格式
+var ErrUnsupportedFormat = errors.New("不支持的导出格式")
+
+// rowWriter 各格式的写出器
+type rowWriter interface {
+	// WriteBatch 写出一批行
+	WriteBatch(rows [][]interface{}) error
+	// Close 完成写出
+	Close() error
+}
+
+// Export 执行查询并将结果写入 w，返回导出的行数
+func Export(q *query.Query, w io.Writer, format Format, opts Options) (int64, error) {
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+
+	rows, err := q.Rows(opts.Context)
+	if err != nil {
+		return 0, fmt.Errorf("执行导出查询失败: %w", err)
+	}
+	defer rows.Close()
+	return ExportRows(rows, w, format, opts)
+}
+
+// ExportRows 将已有结果集写入 w，返回导出的行数
+func ExportRows(rows *sql.Rows, w io.Writer, format Format, opts Options) (int64, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = time.RFC3339
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("获取列信息失败: %w", err)
+	}
+
+	types, _ := rows.ColumnTypes()
+	writer, err := newRowWriter(w, format, columns, types, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		total int64
+		batch = make([][]interface{}, 0, opts.BatchSize)
+	)
+	for rows.Next() {
+		row, err := scanRow(rows, columns, opts.Converters)
+		if err != nil {
+			return total, err
+		}
+		batch = append(batch, row)
+		if len(batch) == opts.BatchSize {
+			if err := writer.WriteBatch(batch); err != nil {
+				return total, fmt.Errorf("写出数据失败: %w", err)
+			}
+			total += int64(len(batch))
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return total, fmt.Errorf("读取数据失败: %w", err)
+	}
+	if len(batch) > 0 {
+		if err := writer.WriteBatch(batch); err != nil {
+			return total, fmt.Errorf("写出数据失败: %w", err)
+		}
+		total += int64(len(batch))
+	}
+	if err := writer.Close(); err != nil {
+		return total, fmt.Errorf("写出数据失败: %w", err)
+	}
+	return total, nil
+}
+
+// newRowWriter 创建指定格式的写出器，types 用于确定 Parquet 列类型，可以为nil
+func newRowWriter(w io.Writer, format Format, columns []string, types []*sql.ColumnType, opts Options) (rowWriter, error) {
+	switch Format(strings.ToLower(string(format))) {
+	case CSV:
+		return newCSVWriter(w, columns, opts)
+	case JSONLines:
+		return newJSONLinesWriter(w, columns, opts), nil
+	case Parquet:
+		return newParquetWriter(w, columns, types), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// Writer 分批写出已扫描的行，用于数据不是来自单个结果集的场景（如分批归档）
+// 每批写出后立即刷新；Parquet 每批为一个行组，Close 时写出文件尾
+type Writer struct {
+	writer     rowWriter
+	columns    []string
+	converters map[string]Converter
+}
+
+// NewWriter 创建写出器，CSV 会立即写出表头；Parquet 的列类型按第一批的非空值推断
+func NewWriter(w io.Writer, format Format, columns []string, opts Options) (*Writer, error) {
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = time.RFC3339
+	}
+	writer, err := newRowWriter(w, format, columns, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{writer: writer, columns: columns, converters: opts.Converters}, nil
+}
+
+// WriteBatch 写出一批行，每行的值与列一一对应
+func (w *Writer) WriteBatch(rows [][]interface{}) error {
+	for _, row := range rows {
+		if len(row) != len(w.columns) {
+			return fmt.Errorf("行的值数量 %d 与列数量 %d 不一致", len(row), len(w.columns))
+		}
+		if err := convertRow(row, w.columns, w.converters); err != nil {
+			return err
+		}
+	}
+	if err := w.writer.WriteBatch(rows); err != nil {
+		return fmt.Errorf("写出数据失败: %w", err)
+	}
+	return nil
+}
+
+// Close 完成写出
+func (w *Writer) Close() error {
+	if err := w.writer.Close(); err != nil {
+		return fmt.Errorf("写出数据失败: %w", err)
+	}
+	return nil
+}
+
+// scanRow 扫描一行并执行列转换
+func scanRow(rows *sql.Rows, columns []string, converters map[string]Converter) ([]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, fmt.Errorf("扫描数据失败: %w", err)
+	}
+
+	if err := convertRow(values, columns, converters); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// convertRow 将字节值转为字符串并执行列转换
+func convertRow(values []interface{}, columns []string, converters map[string]Converter) error {
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			values[i] = string(b)
+		}
+		if convert, ok := converters[columns[i]]; ok {
+			converted, err := convert(values[i])
+			if err != nil {
+				return fmt.Errorf("转换列 %s 失败: %w", columns[i], err)
+			}
+			values[i] = converted
+		}
+	}
+	return nil
+}
+
+// ==================== CSV ====================
+
+// csvWriter CSV 写出器
+type csvWriter struct {
+	writer     *csv.Writer
+	timeFormat string
+}
+
+// newCSVWriter 创建 CSV 写出器并写出表头
+func newCSVWriter(w io.Writer, columns []string, opts Options) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		writer.Comma = opts.Delimiter
+	}
+	if !opts.NoHeader {
+		if err := writer.Write(columns); err != nil {
+			return nil, fmt.Errorf("写出表头失败: %w", err)
+		}
+	}
+	return &csvWriter{writer: writer, timeFormat: opts.TimeFormat}, nil
+}
+
+// WriteBatch 写出一批行
+func (c *csvWriter) WriteBatch(rows [][]interface{}) error {
+	record := make([]string, 0)
+	for _, row := range rows {
+		record = record[:0]
+		for _, value := range row {
+			if t, ok := value.(time.Time); ok {
+				record = append(record, t.Format(c.timeFormat))
+			} else {
+				record = append(record, gosqlx.FormatValue(value))
+			}
+		}
+		if err := c.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// Close 完成写出
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// ==================== JSON Lines ====================
+
+// jsonLinesWriter JSON Lines 写出器，按查询列顺序输出字段
+type jsonLinesWriter struct {
+	writer     *bufio.Writer
+	keys       [][]byte
+	timeFormat string
+}
+
+// newJSONLinesWriter 创建 JSON Lines 写出器
+func newJSONLinesWriter(w io.Writer, columns []string, opts Options) *jsonLinesWriter {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		keys[i], _ = json.Marshal(column)
+	}
+	return &jsonLinesWriter{writer: bufio.NewWriter(w), keys: keys, timeFormat: opts.TimeFormat}
+}
+
+// WriteBatch 写出一批行
+func (j *jsonLinesWriter) WriteBatch(rows [][]interface{}) error {
+	for _, row := range rows {
+		j.writer.WriteByte('{')
+		for i, value := range row {
+			if i > 0 {
+				j.writer.WriteByte(',')
+			}
+			if t, ok := value.(time.Time); ok {
+				value = t.Format(j.timeFormat)
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("序列化列 %s 失败: %w", j.keys[i], err)
+			}
+			j.writer.Write(j.keys[i])
+			j.writer.WriteByte(':')
+			j.writer.Write(data)
+		}
+		j.writer.WriteString("}\n")
+	}
+	return j.writer.Flush()
+}
+
+// Close 完成写出
+func (j *jsonLinesWriter) Close() error {
+	return j.writer.Flush()
+}
@@ -0,0 +1,225 @@
+package export
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gzorm/gosqlx/query"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openTestDB 创建包含测试数据的内存数据库
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, score REAL, active BOOLEAN, created_at DATETIME);
+INSERT INTO users VALUES (1, 'alice', 9.5, 1, '2024-01-02 03:04:05'), (2, 'bob, jr', NULL, 0, NULL), (3, NULL, 7, 1, '2024-02-01 00:00:00');`)
+	if err != nil {
+		t.Fatalf("准备测试数据失败: %v", err)
+	}
+	return db
+}
+
+// 测试导出 CSV
+func TestExportCSV(t *testing.T) {
+	db := openTestDB(t)
+
+	var buf bytes.Buffer
+	q := query.NewQuery(db).Table("users").Select("id", "name", "score").OrderByAsc("id")
+	n, err := Export(q, &buf, CSV, Options{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+	want := "id,name,score\n1,alice,9.5\n2,\"bob, jr\",\n3,,7\n"
+	if n != 3 || buf.String() != want {
+		t.Errorf("期望导出 3 行\n%s实际为 %d 行\n%s", want, n, buf.String())
+	}
+}
+
+// 测试导出 JSON Lines
+func TestExportJSONLines(t *testing.T) {
+	db := openTestDB(t)
+
+	var buf bytes.Buffer
+	q := query.NewQuery(db).Table("users").Select("name", "id").OrderByAsc("id")
+	_, err := Export(q, &buf, JSONLines, Options{
+		Converters: map[string]Converter{
+			"name": func(value interface{}) (interface{}, error) {
+				if value == nil {
+					return "unknown", nil
+				}
+				return strings.ToUpper(value.(string)), nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+	want := `{"name":"ALICE","id":1}` + "\n" + `{"name":"BOB, JR","id":2}` + "\n" + `{"name":"unknown","id":3}` + "\n"
+	if buf.String() != want {
+		t.Errorf("期望\n%s实际为\n%s", want, buf.String())
+	}
+}
+
+// 测试导出 Parquet
+func TestExportParquet(t *testing.T) {
+	db := openTestDB(t)
+
+	var buf bytes.Buffer
+	q := query.NewQuery(db).Table("users").OrderByAsc("id")
+	if _, err := Export(q, &buf, Parquet, Options{BatchSize: 2}); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, parquetMagic) || !bytes.HasSuffix(data, parquetMagic) {
+		t.Fatal("Parquet 文件缺少 PAR1 标识")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-size : len(data)-8]
+
+	meta := (&thriftReader{data: footer}).readStruct()
+	if rows := meta[3]; rows != int64(3) {
+		t.Errorf("期望总行数为 3，实际为 %v", rows)
+	}
+	schema := meta[2].([]interface{})
+	if len(schema) != 6 {
+		t.Fatalf("期望 schema 包含 6 个节点，实际为 %d", len(schema))
+	}
+	types := map[string]interface{}{}
+	for _, element := range schema[1:] {
+		fields := element.(map[int16]interface{})
+		types[string(fields[4].([]byte))] = fields[1]
+	}
+	want := map[string]interface{}{"id": int64(parquetInt64), "name": int64(parquetByteArray), "score": int64(parquetDouble),
+		"active": int64(parquetBoolean), "created_at": int64(parquetInt64)}
+	for name, typ := range want {
+		if types[name] != typ {
+			t.Errorf("列 %s 期望类型 %v，实际为 %v", name, typ, types[name])
+		}
+	}
+	groups := meta[4].([]interface{})
+	if len(groups) != 2 {
+		t.Fatalf("期望 2 个行组，实际为 %d", len(groups))
+	}
+
+	// 解码第二个行组的 score 列，仅包含第 3 行的 7.0
+	chunk := groups[1].(map[int16]interface{})[1].([]interface{})[2].(map[int16]interface{})
+	reader := &thriftReader{data: data, pos: int(chunk[3].(map[int16]interface{})[9].(int64))}
+	header := reader.readStruct()
+	if pageHeader := header[5].(map[int16]interface{}); pageHeader[1] != int64(1) {
+		t.Errorf("期望数据页包含 1 个值，实际为 %v", pageHeader[1])
+	}
+	levelSize := int(binary.LittleEndian.Uint32(data[reader.pos:]))
+	values := data[reader.pos+4+levelSize:]
+	if math.Float64frombits(binary.LittleEndian.Uint64(values)) != 7 {
+		t.Errorf("score 列的值不符合预期")
+	}
+}
+
+// thriftReader 测试用的 Thrift Compact 解码器
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) readValue(typ byte) interface{} {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case thriftI32, thriftI64:
+		v := r.uvarint()
+		return int64(v>>1) ^ -int64(v&1)
+	case thriftBinary:
+		n := int(r.uvarint())
+		b := r.data[r.pos : r.pos+n]
+		r.pos += n
+		return b
+	case thriftList:
+		header := r.byte()
+		size, elem := int(header>>4), header&0x0F
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.readValue(elem)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	}
+	panic("未知的 Thrift 类型")
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			v := r.uvarint()
+			id = int16(int64(v>>1) ^ -int64(v&1))
+		}
+		fields[id] = r.readValue(header & 0x0F)
+		last = id
+	}
+}
+
+// 测试分批写出已扫描的行
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, JSONLines, []string{"id", "name"}, Options{
+		Converters: map[string]Converter{"name": func(v interface{}) (interface{}, error) { return strings.ToUpper(v.(string)), nil }},
+	})
+	if err != nil {
+		t.Fatalf("创建写出器失败: %v", err)
+	}
+	if err := w.WriteBatch([][]interface{}{{int64(1), []byte("alice")}}); err != nil {
+		t.Fatalf("写出失败: %v", err)
+	}
+	if err := w.WriteBatch([][]interface{}{{int64(2), "bob"}}); err != nil {
+		t.Fatalf("写出失败: %v", err)
+	}
+	if err := w.WriteBatch([][]interface{}{{int64(3)}}); err == nil {
+		t.Error("期望列数量不一致时报错")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+	want := "{\"id\":1,\"name\":\"ALICE\"}\n{\"id\":2,\"name\":\"BOB\"}\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	if _, err := NewWriter(&buf, "xml", []string{"id"}, Options{}); err == nil {
+		t.Error("期望不支持的格式报错")
+	}
+}
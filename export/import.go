@@ -0,0 +1,357 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gzorm/gosqlx"
+	"gorm.io/gorm"
+)
+
+// RowError 导入失败的行
+type RowError struct {
+	Line int    // 源文件中的行号（从1开始）
+	Raw  string // 原始内容
+	Err  error  // 失败原因
+}
+
+// Error 实现 error 接口
+func (e RowError) Error() string {
+	return fmt.Sprintf("第 %d 行: %v", e.Line, e.Err)
+}
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	BatchSize int               // 每批插入的行数，默认 1000，并受数据库参数上限约束
+	Delimiter rune              // CSV 分隔符，默认逗号
+	Header    []string          // CSV 列名，设置后文件首行视为数据
+	Columns   map[string]string // 文件列名到表列名的映射，映射为空字符串的列将被忽略
+	MaxErrors int               // 错误行超过该数量时中止导入，0 表示不限制
+	// Validate 校验并可修改转换后的行，返回错误时该行记为错误行
+	Validate func(line int, row map[string]interface{}) error
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Inserted int64      // 成功插入的行数
+	Errors   []RowError // 失败的行
+}
+
+// ErrTooManyErrors 错误行超过 MaxErrors
+var ErrTooManyErrors = errors.New("错误行过多，导入已中止")
+
+// sourceRow 从文件读取的一行
+type sourceRow struct {
+	line   int
+	raw    string
+	values map[string]interface{}
+}
+
+// Import 将 CSV 或 JSON Lines 数据导入表，按表头映射列并根据表结构转换类型
+// 通过适配器的 BatchInsert 批量写入，转换、校验或插入失败的行记入 ImportResult.Errors 而不中止导入
+func Import(db *gosqlx.Database, table string, r io.Reader, format Format, opts ImportOptions) (*ImportResult, error) {
+	columnTypes, err := db.DB().Migrator().ColumnTypes(table)
+	if err != nil {
+		return nil, fmt.Errorf("获取表 %s 结构失败: %w", table, err)
+	}
+	types := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, columnType := range columnTypes {
+		types[strings.ToLower(columnType.Name())] = columnType
+	}
+
+	var next func() (*sourceRow, error)
+	switch Format(strings.ToLower(string(format))) {
+	case CSV:
+		next, err = csvRows(r, opts)
+	case JSONLines:
+		next = jsonLinesRows(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{db: db, table: table, types: types, opts: opts, result: &ImportResult{}}
+	if imp.opts.BatchSize <= 0 {
+		imp.opts.BatchSize = 1000
+	}
+	for {
+		src, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imp.result, fmt.Errorf("读取数据失败: %w", err)
+		}
+		if err := imp.add(src); err != nil {
+			return imp.result, err
+		}
+	}
+	err = imp.flush()
+	// 插入失败的行在批次提交时才记录，按行号排序便于报告
+	sort.SliceStable(imp.result.Errors, func(i, j int) bool {
+		return imp.result.Errors[i].Line < imp.result.Errors[j].Line
+	})
+	return imp.result, err
+}
+
+// importer 导入过程状态
+type importer struct {
+	db     *gosqlx.Database
+	table  string
+	types  map[string]gorm.ColumnType
+	opts   ImportOptions
+	batch  []*sourceRow
+	result *ImportResult
+}
+
+// add 转换并校验一行，加入待插入批次
+func (imp *importer) add(src *sourceRow) error {
+	if src.values == nil {
+		return imp.reject(src, errors.New("格式错误"))
+	}
+	row := make(map[string]interface{}, len(src.values))
+	for name, value := range src.values {
+		column := name
+		if mapped, ok := imp.opts.Columns[name]; ok {
+			if mapped == "" {
+				continue
+			}
+			column = mapped
+		}
+		columnType, ok := imp.types[strings.ToLower(column)]
+		if !ok {
+			return imp.reject(src, fmt.Errorf("表 %s 不存在列 %s", imp.table, column))
+		}
+		converted, err := coerce(value, columnType)
+		if err != nil {
+			return imp.reject(src, fmt.Errorf("列 %s: %w", column, err))
+		}
+		row[columnType.Name()] = converted
+	}
+
+	if imp.opts.Validate != nil {
+		if err := imp.opts.Validate(src.line, row); err != nil {
+			return imp.reject(src, err)
+		}
+	}
+
+	src.values = row
+	imp.batch = append(imp.batch, src)
+	if len(imp.batch) >= imp.batchSize(len(imp.types)) {
+		return imp.flush()
+	}
+	return nil
+}
+
+// batchSize 根据列数（按表的全部列估算）和数据库参数上限计算批次大小
+func (imp *importer) batchSize(columns int) int {
+	return max(min(imp.opts.BatchSize, imp.db.ParamLimit()/max(columns, 1)), 1)
+}
+
+// flush 插入当前批次，批量插入失败时逐行重试以定位错误行
+func (imp *importer) flush() error {
+	if len(imp.batch) == 0 {
+		return nil
+	}
+	batch := imp.batch
+	imp.batch = nil
+
+	columns, values := batchValues(batch)
+	if err := imp.db.BatchInsert(imp.table, columns, values); err == nil {
+		imp.result.Inserted += int64(len(batch))
+		return nil
+	}
+
+	for _, src := range batch {
+		columns, values := batchValues([]*sourceRow{src})
+		if err := imp.db.BatchInsert(imp.table, columns, values); err != nil {
+			if err := imp.reject(src, err); err != nil {
+				return err
+			}
+			continue
+		}
+		imp.result.Inserted++
+	}
+	return nil
+}
+
+// reject 记录错误行，超过上限时返回 ErrTooManyErrors
+func (imp *importer) reject(src *sourceRow, err error) error {
+	imp.result.Errors = append(imp.result.Errors, RowError{Line: src.line, Raw: src.raw, Err: err})
+	if imp.opts.MaxErrors > 0 && len(imp.result.Errors) > imp.opts.MaxErrors {
+		return ErrTooManyErrors
+	}
+	return nil
+}
+
+// batchValues 将一批行转换为列名和值，缺失的列写入 NULL
+func batchValues(batch []*sourceRow) ([]string, [][]interface{}) {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, src := range batch {
+		for column := range src.values {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	values := make([][]interface{}, len(batch))
+	for i, src := range batch {
+		values[i] = make([]interface{}, len(columns))
+		for j, column := range columns {
+			values[i][j] = src.values[column]
+		}
+	}
+	return columns, values
+}
+
+// csvRows 逐行读取 CSV
+func csvRows(r io.Reader, opts ImportOptions) (func() (*sourceRow, error), error) {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+
+	header := opts.Header
+	if len(header) == 0 {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("读取表头失败: %w", err)
+		}
+		header = make([]string, len(record))
+		for i, name := range record {
+			header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		}
+	}
+
+	return func() (*sourceRow, error) {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		src := &sourceRow{line: line, raw: strings.Join(record, string(reader.Comma)), values: make(map[string]interface{}, len(header))}
+		if len(record) != len(header) {
+			src.values = nil
+			return src, nil
+		}
+		for i, name := range header {
+			src.values[name] = record[i]
+		}
+		return src, nil
+	}, nil
+}
+
+// jsonLinesRows 逐行读取 JSON Lines，跳过空行
+func jsonLinesRows(r io.Reader) func() (*sourceRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	return func() (*sourceRow, error) {
+		for scanner.Scan() {
+			line++
+			raw := strings.TrimSpace(scanner.Text())
+			if raw == "" {
+				continue
+			}
+			src := &sourceRow{line: line, raw: raw}
+			decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+			decoder.UseNumber()
+			if err := decoder.Decode(&src.values); err != nil {
+				src.values = nil
+			}
+			return src, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+}
+
+// coerce 根据列类型转换值，空字符串对非文本列视为 NULL，DECIMAL 等类型保留文本交由数据库转换
+func coerce(value interface{}, columnType gorm.ColumnType) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	case bool:
+		text = strconv.FormatBool(v)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return v, nil
+	}
+
+	kind := columnKindOf(columnType)
+	if kind != kindString && strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	switch kind {
+	case kindInt64:
+		text = strings.TrimSpace(text)
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			// 允许无小数部分的浮点数（如 JSON 中的 28.0）
+			if f, floatErr := strconv.ParseFloat(text, 64); floatErr == nil && f == math.Trunc(f) {
+				return int64(f), nil
+			}
+			// 布尔值常以整数存储（SQLite、MySQL TINYINT(1)）
+			if b, boolErr := strconv.ParseBool(text); boolErr == nil {
+				return toInt64(b)
+			}
+		}
+		return n, err
+	case kindDouble:
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case kindBoolean:
+		return toBool(strings.TrimSpace(text))
+	case kindTimestamp:
+		return toTime(strings.TrimSpace(text))
+	}
+	return text, nil
+}
+
+// columnKindOf 根据扫描类型或数据库类型名确定列类型
+func columnKindOf(columnType gorm.ColumnType) columnKind {
+	if kind := kindOfScanType(columnType.ScanType()); kind != kindUnknown {
+		return kind
+	}
+	name := strings.ToUpper(columnType.DatabaseTypeName())
+	switch {
+	case strings.Contains(name, "BOOL"), name == "BIT":
+		return kindBoolean
+	case strings.Contains(name, "INT"):
+		return kindInt64
+	case strings.Contains(name, "REAL"), strings.Contains(name, "FLOAT"), strings.Contains(name, "DOUBLE"):
+		return kindDouble
+	case strings.Contains(name, "DATE"), strings.Contains(name, "TIME"):
+		return kindTimestamp
+	}
+	return kindString
+}
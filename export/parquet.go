@@ -0,0 +1,521 @@
+package export
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// Parquet 物理类型（parquet.thrift Type）
+const (
+	parquetBoolean   int32 = 0
+	parquetInt64     int32 = 2
+	parquetDouble    int32 = 5
+	parquetByteArray int32 = 6
+)
+
+// Parquet 逻辑类型（parquet.thrift ConvertedType）
+const (
+	convertedUTF8            int32 = 0
+	convertedTimestampMillis int32 = 9
+)
+
+// Parquet 编码（parquet.thrift Encoding）
+const (
+	encodingPlain int32 = 0
+	encodingRLE   int32 = 3
+)
+
+var parquetMagic = []byte("PAR1")
+
+// columnKind 导出列的类型
+type columnKind int
+
+const (
+	kindUnknown columnKind = iota // 尚未确定，根据首个非空值推断
+	kindBoolean
+	kindInt64
+	kindDouble
+	kindString
+	kindTimestamp
+)
+
+// columnChunk 已写出的列块信息
+type columnChunk struct {
+	offset    int64 // 数据页起始位置
+	size      int64 // 页头与数据的总字节数
+	numValues int64 // 值数量（含 NULL）
+}
+
+// rowGroup 已写出的行组信息
+type rowGroup struct {
+	numRows int64
+	columns []columnChunk
+}
+
+// parquetWriter Parquet 写出器
+// 每批数据写为一个行组，所有列为 OPTIONAL，使用 PLAIN 编码且不压缩
+type parquetWriter struct {
+	writer  io.Writer
+	offset  int64
+	columns []string
+	kinds   []columnKind
+	groups  []rowGroup
+	started bool
+}
+
+// newParquetWriter 创建 Parquet 写出器，根据驱动报告的列类型确定 Parquet 类型
+func newParquetWriter(w io.Writer, columns []string, types []*sql.ColumnType) *parquetWriter {
+	kinds := make([]columnKind, len(columns))
+	for i := range kinds {
+		if i < len(types) && types[i] != nil {
+			kinds[i] = kindOfScanType(types[i].ScanType())
+		}
+	}
+	return &parquetWriter{writer: w, columns: columns, kinds: kinds}
+}
+
+// kindOfScanType 将扫描类型映射为列类型
+func kindOfScanType(t reflect.Type) columnKind {
+	if t == nil {
+		return kindUnknown
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(sql.NullTime{}):
+		return kindTimestamp
+	case reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}), reflect.TypeOf(sql.NullInt16{}), reflect.TypeOf(sql.NullByte{}):
+		return kindInt64
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return kindDouble
+	case reflect.TypeOf(sql.NullBool{}):
+		return kindBoolean
+	case reflect.TypeOf(sql.NullString{}), reflect.TypeOf(sql.RawBytes{}):
+		return kindString
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return kindBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindInt64
+	case reflect.Float32, reflect.Float64:
+		return kindDouble
+	case reflect.String:
+		return kindString
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindString
+		}
+	}
+	return kindUnknown
+}
+
+// kindOfValue 根据值推断列类型
+func kindOfValue(value interface{}) columnKind {
+	switch value.(type) {
+	case bool:
+		return kindBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return kindInt64
+	case float32, float64:
+		return kindDouble
+	case time.Time:
+		return kindTimestamp
+	default:
+		return kindString
+	}
+}
+
+// write 写出数据并记录偏移量
+func (p *parquetWriter) write(data []byte) error {
+	n, err := p.writer.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+// WriteBatch 将一批行写为一个行组
+func (p *parquetWriter) WriteBatch(rows [][]interface{}) error {
+	if !p.started {
+		if err := p.write(parquetMagic); err != nil {
+			return err
+		}
+		p.started = true
+	}
+
+	group := rowGroup{numRows: int64(len(rows)), columns: make([]columnChunk, len(p.columns))}
+	for i := range p.columns {
+		if p.kinds[i] == kindUnknown {
+			for _, row := range rows {
+				if row[i] != nil {
+					p.kinds[i] = kindOfValue(row[i])
+					break
+				}
+			}
+		}
+
+		page, err := p.encodeColumn(i, rows)
+		if err != nil {
+			return err
+		}
+		header := encodePageHeader(len(rows), len(page))
+
+		chunk := columnChunk{offset: p.offset, size: int64(len(header) + len(page)), numValues: int64(len(rows))}
+		if err := p.write(header); err != nil {
+			return err
+		}
+		if err := p.write(page); err != nil {
+			return err
+		}
+		group.columns[i] = chunk
+	}
+	p.groups = append(p.groups, group)
+	return nil
+}
+
+// encodeColumn 编码一列的数据页：定义级别（RLE）+ 非空值（PLAIN）
+func (p *parquetWriter) encodeColumn(index int, rows [][]interface{}) ([]byte, error) {
+	levels := make([]byte, len(rows))
+	var values bytes.Buffer
+	var bits []bool
+	for r, row := range rows {
+		value := row[index]
+		if value == nil {
+			continue
+		}
+		levels[r] = 1
+
+		var err error
+		switch p.kinds[index] {
+		case kindBoolean:
+			var b bool
+			b, err = toBool(value)
+			bits = append(bits, b)
+		case kindInt64:
+			var n int64
+			n, err = toInt64(value)
+			binary.Write(&values, binary.LittleEndian, n)
+		case kindDouble:
+			var f float64
+			f, err = toFloat64(value)
+			binary.Write(&values, binary.LittleEndian, math.Float64bits(f))
+		case kindTimestamp:
+			var t time.Time
+			t, err = toTime(value)
+			binary.Write(&values, binary.LittleEndian, t.UnixMilli())
+		default:
+			s := gosqlx.FormatValue(value)
+			binary.Write(&values, binary.LittleEndian, uint32(len(s)))
+			values.WriteString(s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("列 %s 第 %d 行: %w", p.columns[index], r+1, err)
+		}
+	}
+
+	// 布尔值按位打包，低位在前
+	if p.kinds[index] == kindBoolean {
+		packed := make([]byte, (len(bits)+7)/8)
+		for i, b := range bits {
+			if b {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		values.Write(packed)
+	}
+
+	encoded := encodeLevels(levels)
+	var page bytes.Buffer
+	binary.Write(&page, binary.LittleEndian, uint32(len(encoded)))
+	page.Write(encoded)
+	page.Write(values.Bytes())
+	return page.Bytes(), nil
+}
+
+// encodeLevels 使用 RLE 编码定义级别（位宽为1）
+func encodeLevels(levels []byte) []byte {
+	var buf []byte
+	for start := 0; start < len(levels); {
+		end := start + 1
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		buf = binary.AppendUvarint(buf, uint64(end-start)<<1)
+		buf = append(buf, levels[start])
+		start = end
+	}
+	return buf
+}
+
+// encodePageHeader 编码数据页页头
+func encodePageHeader(numValues, size int) []byte {
+	t := &thriftWriter{}
+	t.i32(1, 0) // DATA_PAGE
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.structBegin(5)
+	t.i32(1, int32(numValues))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.structEnd()
+	t.stop()
+	return t.buf.Bytes()
+}
+
+// Close 写出文件元数据
+func (p *parquetWriter) Close() error {
+	if !p.started {
+		if err := p.write(parquetMagic); err != nil {
+			return err
+		}
+	}
+
+	var numRows int64
+	for _, group := range p.groups {
+		numRows += group.numRows
+	}
+
+	t := &thriftWriter{}
+	t.i32(1, 1) // version
+
+	// schema: 根节点 + 各列
+	t.listBegin(2, thriftStruct, len(p.columns)+1)
+	t.elementBegin()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(p.columns)))
+	t.structEnd()
+	for i, column := range p.columns {
+		physical, converted := p.parquetType(i)
+		t.elementBegin()
+		t.i32(1, physical)
+		t.i32(3, 1) // OPTIONAL
+		t.binary(4, column)
+		if converted >= 0 {
+			t.i32(6, converted)
+		}
+		t.structEnd()
+	}
+
+	t.i64(3, numRows)
+
+	t.listBegin(4, thriftStruct, len(p.groups))
+	for _, group := range p.groups {
+		var total int64
+		t.elementBegin()
+		t.listBegin(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			physical, _ := p.parquetType(i)
+			total += chunk.size
+			t.elementBegin()
+			t.i64(2, chunk.offset)
+			t.structBegin(3)
+			t.i32(1, physical)
+			t.listBegin(2, thriftI32, 2)
+			t.varint(zigzag(int64(encodingPlain)))
+			t.varint(zigzag(int64(encodingRLE)))
+			t.listBegin(3, thriftBinary, 1)
+			t.varint(uint64(len(p.columns[i])))
+			t.buf.WriteString(p.columns[i])
+			t.i32(4, 0) // UNCOMPRESSED
+			t.i64(5, chunk.numValues)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64(2, total)
+		t.i64(3, group.numRows)
+		t.structEnd()
+	}
+	t.binary(6, "gosqlx")
+	t.stop()
+
+	footer := t.buf.Bytes()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	if err := p.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return p.write(parquetMagic)
+}
+
+// parquetType 获取列的物理类型和逻辑类型（无逻辑类型时为-1）
+func (p *parquetWriter) parquetType(index int) (int32, int32) {
+	switch p.kinds[index] {
+	case kindBoolean:
+		return parquetBoolean, -1
+	case kindInt64:
+		return parquetInt64, -1
+	case kindDouble:
+		return parquetDouble, -1
+	case kindTimestamp:
+		return parquetInt64, convertedTimestampMillis
+	default:
+		return parquetByteArray, convertedUTF8
+	}
+}
+
+// ==================== 类型转换 ====================
+
+// toBool 转换为布尔值
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+	n, err := toInt64(value)
+	return n != 0, err
+}
+
+// toInt64 转换为整数
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("无法将 %T 转换为整数", value)
+}
+
+// toFloat64 转换为浮点数
+func toFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	n, err := toInt64(value)
+	if err != nil {
+		return 0, fmt.Errorf("无法将 %T 转换为浮点数", value)
+	}
+	return float64(n), nil
+}
+
+// toTime 转换为时间
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法将 %v 转换为时间", value)
+}
+
+// ==================== Thrift Compact 编码 ====================
+
+// Thrift Compact 协议类型
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriter 最小化的 Thrift Compact 协议编码器，仅用于写出 Parquet 元数据
+type thriftWriter struct {
+	buf    bytes.Buffer
+	last   int16   // 当前结构体上一个字段编号
+	parent []int16 // 外层结构体的字段编号
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(zigzag(int64(id)))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftWriter) listBegin(id int16, elem byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xF0 | elem)
+		t.varint(uint64(size))
+	}
+}
+
+// structBegin 开始结构体字段
+func (t *thriftWriter) structBegin(id int16) {
+	t.field(id, thriftStruct)
+	t.elementBegin()
+}
+
+// elementBegin 开始列表中的结构体元素
+func (t *thriftWriter) elementBegin() {
+	t.parent = append(t.parent, t.last)
+	t.last = 0
+}
+
+// structEnd 结束结构体
+func (t *thriftWriter) structEnd() {
+	t.stop()
+	t.last = t.parent[len(t.parent)-1]
+	t.parent = t.parent[:len(t.parent)-1]
+}
+
+// stop 写出字段结束标记
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
@@ -0,0 +1,83 @@
+//go:build parquet
+
+package export
+
+// 使用 parquet-go 读回导出的文件，验证手写的写出器与独立实现兼容
+// 默认构建不包含该测试，拉取依赖后运行：
+//
+//	GOFLAGS=-mod=mod go test -tags parquet ./export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/gzorm/gosqlx/query"
+	"github.com/parquet-go/parquet-go"
+)
+
+// 测试 parquet-go 读回导出的 Parquet 文件
+func TestExportParquetReadBack(t *testing.T) {
+	db := openTestDB(t)
+
+	var buf bytes.Buffer
+	q := query.NewQuery(db).Table("users").OrderByAsc("id")
+	if _, err := Export(q, &buf, Parquet, Options{BatchSize: 2}); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("parquet-go 打开文件失败: %v", err)
+	}
+	if f.NumRows() != 3 || len(f.RowGroups()) != 2 {
+		t.Fatalf("期望 3 行 2 个行组，实际为 %d 行 %d 个行组", f.NumRows(), len(f.RowGroups()))
+	}
+	columns := map[string]int{}
+	for i, path := range f.Schema().Columns() {
+		columns[path[0]] = i
+	}
+
+	var rows []parquet.Row
+	for _, group := range f.RowGroups() {
+		reader := group.Rows()
+		batch := make([]parquet.Row, group.NumRows())
+		n, err := reader.ReadRows(batch)
+		reader.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			t.Fatalf("读取行组失败: %v", err)
+		}
+		rows = append(rows, batch[:n]...)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("期望读回 3 行，实际为 %d", len(rows))
+	}
+
+	value := func(row int, column string) parquet.Value {
+		for _, v := range rows[row] {
+			if v.Column() == columns[column] {
+				return v
+			}
+		}
+		t.Fatalf("第 %d 行缺少列 %s", row+1, column)
+		return parquet.Value{}
+	}
+	for i, id := range []int64{1, 2, 3} {
+		if got := value(i, "id").Int64(); got != id {
+			t.Errorf("第 %d 行 id = %d", i+1, got)
+		}
+	}
+	if got := string(value(1, "name").ByteArray()); got != "bob, jr" {
+		t.Errorf("第 2 行 name = %q", got)
+	}
+	if !value(2, "name").IsNull() || !value(1, "score").IsNull() || !value(1, "created_at").IsNull() {
+		t.Error("NULL 值读回后应为空")
+	}
+	if value(0, "score").Double() != 9.5 || value(2, "score").Double() != 7 {
+		t.Errorf("score 列读回的值不符合预期")
+	}
+	if !value(0, "active").Boolean() || value(1, "active").Boolean() {
+		t.Errorf("active 列读回的值不符合预期")
+	}
+}
@@ -0,0 +1,316 @@
+// Package expr 构建 SQL 表达式片段：标识符按方言加引号，值一律作为参数绑定，
+// 用于查询列、条件和排序，替代手工拼接的 SelectRaw/WhereRaw 字符串
+//
+//	total := expr.Col("price").Mul(expr.Col("qty")).As("total")
+//	sql, args, err := total.Build("mysql") // (`price` * `qty`) AS `total`
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gzorm/gosqlx/builder"
+	"github.com/gzorm/gosqlx/dialect"
+)
+
+// ErrInvalidExpr 表达式中的标识符不合法或表达式为空
+var ErrInvalidExpr = errors.New("表达式不合法")
+
+// identRegex 允许的标识符：列名、表名、函数名和别名
+var identRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// Expr SQL 表达式，值类型不可变，方法返回新的表达式
+type Expr struct {
+	write func(w *writer)
+	alias string // 查询列别名，仅在最外层生效
+	order string // 排序方向，仅在最外层生效
+}
+
+// writer 生成 SQL 片段的状态
+type writer struct {
+	dialect dialect.Dialect
+	sql     strings.Builder
+	args    []interface{}
+	err     error
+}
+
+// fail 记录首个错误
+func (w *writer) fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// expr 写入子表达式，忽略其别名和排序方向
+func (w *writer) expr(e Expr) {
+	if e.write == nil {
+		w.fail(fmt.Errorf("%w: 空表达式", ErrInvalidExpr))
+		return
+	}
+	e.write(w)
+}
+
+// ident 写入加引号的标识符，表名.列名 按段分别加引号
+func (w *writer) ident(name string, star bool) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if i > 0 {
+			w.sql.WriteString(".")
+		}
+		if star && part == "*" && i == len(parts)-1 {
+			w.sql.WriteString("*")
+			continue
+		}
+		if !identRegex.MatchString(part) {
+			w.fail(fmt.Errorf("%w: 标识符 %q", ErrInvalidExpr, name))
+			return
+		}
+		w.sql.WriteString(w.dialect.Quote(part))
+	}
+}
+
+// Build 按方言生成 SQL 片段和参数，dialect 为 mysql、postgres、sqlite3、sqlserver、oracle 等
+func (e Expr) Build(dialectName string) (string, []interface{}, error) {
+	w := &writer{dialect: dialect.GetDialect(dialectName)}
+	w.expr(e)
+	if e.alias != "" {
+		w.sql.WriteString(" AS ")
+		w.ident(e.alias, false)
+	}
+	if e.order != "" {
+		w.sql.WriteString(" ")
+		w.sql.WriteString(e.order)
+	}
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	return w.sql.String(), w.args, nil
+}
+
+// Col 列引用，支持 表名.列名 和 *（如 users.*）
+// 示例: Col("o.price")
+func Col(name string) Expr {
+	return Expr{write: func(w *writer) { w.ident(name, true) }}
+}
+
+// Val 作为参数绑定的值
+// 示例: Val(100)
+func Val(value interface{}) Expr {
+	if e, ok := value.(Expr); ok {
+		return e
+	}
+	return Expr{write: func(w *writer) {
+		w.sql.WriteString("?")
+		w.args = append(w.args, value)
+	}}
+}
+
+// Raw 原样写入的 SQL 片段，受 builder 防护模式检查，仅用于表达式无法覆盖的语法
+// 示例: Raw("INTERVAL 1 DAY")
+func Raw(sql string, args ...interface{}) Expr {
+	return Expr{write: func(w *writer) {
+		if err := builder.Inspect(sql); err != nil {
+			w.fail(err)
+			return
+		}
+		w.sql.WriteString(sql)
+		w.args = append(w.args, args...)
+	}}
+}
+
+// Fn 函数调用，参数不是 Expr 时作为值绑定
+// 示例: Fn("COALESCE", Col("nickname"), Col("name"), "匿名")
+func Fn(name string, args ...interface{}) Expr {
+	return Expr{write: func(w *writer) {
+		// 函数名只校验不加引号，加引号后部分数据库按大小写敏感查找函数
+		for _, part := range strings.Split(name, ".") {
+			if !identRegex.MatchString(part) {
+				w.fail(fmt.Errorf("%w: 函数名 %q", ErrInvalidExpr, name))
+				return
+			}
+		}
+		w.sql.WriteString(name)
+		w.sql.WriteString("(")
+		for i, arg := range args {
+			if i > 0 {
+				w.sql.WriteString(", ")
+			}
+			w.expr(Val(arg))
+		}
+		w.sql.WriteString(")")
+	}}
+}
+
+// binary 二元运算，整体加括号避免优先级问题
+func binary(left Expr, op string, right interface{}) Expr {
+	return Expr{write: func(w *writer) {
+		w.sql.WriteString("(")
+		w.expr(left)
+		w.sql.WriteString(" " + op + " ")
+		w.expr(Val(right))
+		w.sql.WriteString(")")
+	}}
+}
+
+// postfix 后缀运算，如 IS NULL
+func postfix(e Expr, op string) Expr {
+	return Expr{write: func(w *writer) {
+		w.sql.WriteString("(")
+		w.expr(e)
+		w.sql.WriteString(" " + op + ")")
+	}}
+}
+
+// Add 加法
+func (e Expr) Add(other interface{}) Expr { return binary(e, "+", other) }
+
+// Sub 减法
+func (e Expr) Sub(other interface{}) Expr { return binary(e, "-", other) }
+
+// Mul 乘法
+func (e Expr) Mul(other interface{}) Expr { return binary(e, "*", other) }
+
+// Div 除法
+func (e Expr) Div(other interface{}) Expr { return binary(e, "/", other) }
+
+// Eq 等于，值为 nil 时生成 IS NULL
+func (e Expr) Eq(other interface{}) Expr {
+	if other == nil {
+		return e.IsNull()
+	}
+	return binary(e, "=", other)
+}
+
+// Ne 不等于，值为 nil 时生成 IS NOT NULL
+func (e Expr) Ne(other interface{}) Expr {
+	if other == nil {
+		return e.IsNotNull()
+	}
+	return binary(e, "<>", other)
+}
+
+// Gt 大于
+func (e Expr) Gt(other interface{}) Expr { return binary(e, ">", other) }
+
+// Gte 大于等于
+func (e Expr) Gte(other interface{}) Expr { return binary(e, ">=", other) }
+
+// Lt 小于
+func (e Expr) Lt(other interface{}) Expr { return binary(e, "<", other) }
+
+// Lte 小于等于
+func (e Expr) Lte(other interface{}) Expr { return binary(e, "<=", other) }
+
+// Like 模糊匹配
+func (e Expr) Like(pattern interface{}) Expr { return binary(e, "LIKE", pattern) }
+
+// NotLike 模糊不匹配
+func (e Expr) NotLike(pattern interface{}) Expr { return binary(e, "NOT LIKE", pattern) }
+
+// IsNull 为空
+func (e Expr) IsNull() Expr { return postfix(e, "IS NULL") }
+
+// IsNotNull 不为空
+func (e Expr) IsNotNull() Expr { return postfix(e, "IS NOT NULL") }
+
+// Between 范围匹配
+func (e Expr) Between(low, high interface{}) Expr {
+	return Expr{write: func(w *writer) {
+		w.sql.WriteString("(")
+		w.expr(e)
+		w.sql.WriteString(" BETWEEN ")
+		w.expr(Val(low))
+		w.sql.WriteString(" AND ")
+		w.expr(Val(high))
+		w.sql.WriteString(")")
+	}}
+}
+
+// In 列表匹配，只传一个切片时展开为列表，列表为空时不匹配任何行
+// 示例: Col("status").In([]int{1, 2})
+func (e Expr) In(values ...interface{}) Expr { return e.in("IN", "(1 = 0)", values) }
+
+// NotIn 列表不匹配，列表为空时匹配所有行
+func (e Expr) NotIn(values ...interface{}) Expr { return e.in("NOT IN", "(1 = 1)", values) }
+
+// in 生成 IN/NOT IN 表达式
+func (e Expr) in(op, empty string, values []interface{}) Expr {
+	if len(values) == 1 {
+		if rv := reflect.ValueOf(values[0]); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			values = make([]interface{}, rv.Len())
+			for i := range values {
+				values[i] = rv.Index(i).Interface()
+			}
+		}
+	}
+	return Expr{write: func(w *writer) {
+		if len(values) == 0 {
+			w.sql.WriteString(empty)
+			return
+		}
+		w.sql.WriteString("(")
+		w.expr(e)
+		w.sql.WriteString(" " + op + " (")
+		for i, value := range values {
+			if i > 0 {
+				w.sql.WriteString(", ")
+			}
+			w.expr(Val(value))
+		}
+		w.sql.WriteString("))")
+	}}
+}
+
+// As 设置查询列别名
+func (e Expr) As(alias string) Expr {
+	e.alias = alias
+	return e
+}
+
+// Asc 升序排序
+func (e Expr) Asc() Expr {
+	e.order = "ASC"
+	return e
+}
+
+// Desc 降序排序
+func (e Expr) Desc() Expr {
+	e.order = "DESC"
+	return e
+}
+
+// And 逻辑与，没有条件时恒为真
+func And(conditions ...Expr) Expr { return join(" AND ", "(1 = 1)", conditions) }
+
+// Or 逻辑或，没有条件时恒为假
+func Or(conditions ...Expr) Expr { return join(" OR ", "(1 = 0)", conditions) }
+
+// Not 逻辑非
+func Not(condition Expr) Expr {
+	return Expr{write: func(w *writer) {
+		w.sql.WriteString("(NOT ")
+		w.expr(condition)
+		w.sql.WriteString(")")
+	}}
+}
+
+// join 用逻辑运算符连接条件
+func join(op, empty string, conditions []Expr) Expr {
+	return Expr{write: func(w *writer) {
+		if len(conditions) == 0 {
+			w.sql.WriteString(empty)
+			return
+		}
+		w.sql.WriteString("(")
+		for i, condition := range conditions {
+			if i > 0 {
+				w.sql.WriteString(op)
+			}
+			w.expr(condition)
+		}
+		w.sql.WriteString(")")
+	}}
+}
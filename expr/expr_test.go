@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gzorm/gosqlx/builder"
+)
+
+// 测试按方言生成表达式
+func TestBuild(t *testing.T) {
+	total := Col("price").Mul(Col("qty")).As("total")
+	cases := []struct {
+		dialect string
+		sql     string
+	}{
+		{"mysql", "(`price` * `qty`) AS `total`"},
+		{"postgres", `("price" * "qty") AS "total"`},
+		{"sqlserver", "([price] * [qty]) AS [total]"},
+	}
+	for _, c := range cases {
+		sql, args, err := total.Build(c.dialect)
+		if err != nil || sql != c.sql || len(args) != 0 {
+			t.Errorf("Build(%s) = %s, %v, %v", c.dialect, sql, args, err)
+		}
+	}
+
+	sql, args, err := Fn("COALESCE", Col("u.nickname"), Col("u.name"), "匿名").As("display").Build("mysql")
+	if err != nil || sql != "COALESCE(`u`.`nickname`, `u`.`name`, ?) AS `display`" || !reflect.DeepEqual(args, []interface{}{"匿名"}) {
+		t.Errorf("Fn = %s, %v, %v", sql, args, err)
+	}
+	if sql, _, _ := Fn("COUNT", Col("o.*")).Build("mysql"); sql != "COUNT(`o`.*)" {
+		t.Errorf("COUNT = %s", sql)
+	}
+}
+
+// 测试条件表达式
+func TestConditions(t *testing.T) {
+	condition := And(
+		Col("price").Mul(1.1).Gt(100),
+		Or(Col("status").In([]int{1, 2}), Col("vip").Eq(true)),
+		Col("deleted_at").Eq(nil),
+		Not(Col("name").Like("test%")),
+		Col("age").Between(18, 30),
+		Col("role").NotIn(),
+	)
+	sql, args, err := condition.Build("sqlite3")
+	expected := `((("price" * ?) > ?) AND (("status" IN (?, ?)) OR ("vip" = ?)) AND ("deleted_at" IS NULL) AND (NOT ("name" LIKE ?)) AND ("age" BETWEEN ? AND ?) AND (1 = 1))`
+	if err != nil || sql != expected {
+		t.Errorf("sql = %s, err = %v", sql, err)
+	}
+	expectedArgs := []interface{}{1.1, 100, 1, 2, true, "test%", 18, 30}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("args = %v", args)
+	}
+	if sql, _, _ := Col("id").In().Build("mysql"); sql != "(1 = 0)" {
+		t.Errorf("空 IN = %s", sql)
+	}
+	if sql, _, _ := Col("created_at").Desc().Build("mysql"); sql != "`created_at` DESC" {
+		t.Errorf("Desc = %s", sql)
+	}
+}
+
+// 测试不合法的表达式
+func TestInvalid(t *testing.T) {
+	for _, e := range []Expr{
+		Col("name`; DROP TABLE users; --"),
+		Col("price").As("total amount"),
+		Fn("SLEEP(1)--"),
+		Col("a").Add(Expr{}),
+	} {
+		if _, _, err := e.Build("mysql"); !errors.Is(err, ErrInvalidExpr) {
+			t.Errorf("Build() error = %v", err)
+		}
+	}
+
+	builder.SetGuardMode(builder.GuardStrict)
+	defer builder.SetGuardMode(builder.GuardOff)
+	if _, _, err := Raw("name = 'admin'").Build("mysql"); !errors.Is(err, builder.ErrUnsafeSQL) {
+		t.Errorf("Raw 字面量 error = %v", err)
+	}
+	if sql, args, err := Col("created_at").Gt(Raw("NOW() - INTERVAL ? DAY", 7)).Build("mysql"); err != nil || sql != "(`created_at` > NOW() - INTERVAL ? DAY)" || len(args) != 1 {
+		t.Errorf("Raw = %s, %v, %v", sql, args, err)
+	}
+}
@@ -0,0 +1,236 @@
+// Package comment 将 Go 模型中的注释同步到数据库的表注释和列注释，使 gen/doc 生成的文档与代码保持一致
+//
+// 注释来自模型字段的 gorm 标签 comment、字段的文档注释或行尾注释，以及类型的文档注释；
+// 只为与数据库现有注释不同的表和列生成语句，模型中没有注释的列保持数据库原样:
+//
+//	tables, err := comment.Parse("./model/poes")
+//	statements, err := comment.Sync(db, "mysql", tables, comment.Options{DryRun: true})
+//
+// MySQL 系通过 ALTER TABLE ... MODIFY COLUMN 修改列注释，列定义取自 SHOW CREATE TABLE，类型、字符集、
+// 默认值等保持不变；PostgreSQL、Oracle 和 DuckDB 使用 COMMENT ON，SQL Server 使用 MS_Description 扩展属性，
+// ClickHouse 使用 ALTER TABLE ... COMMENT COLUMN。SQLite 不支持注释
+package comment
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gzorm/gosqlx/introspect"
+)
+
+// ErrUnsupported 数据库不支持表和列注释
+var ErrUnsupported = errors.New("数据库不支持表和列注释")
+
+// Options 同步选项
+type Options struct {
+	DryRun bool // 仅生成语句，不执行
+}
+
+// state 数据库中表的现有注释
+type state struct {
+	schema      string            // 表所属的库或 schema
+	comment     string            // 表注释
+	columns     map[string]string // 列名 -> 注释
+	definitions map[string]string // MySQL 系的列定义，不含 COMMENT
+}
+
+// Statements 比较模型注释与数据库现有注释，返回需要执行的语句；数据库中不存在的表和列忽略
+func Statements(db *sql.DB, dialect string, tables []Table) ([]string, error) {
+	in := introspect.New(db, dialect)
+	dialect = in.Dialect()
+	if !supported(dialect) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, dialect)
+	}
+	dbTables, err := in.Tables()
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]introspect.Table, len(dbTables))
+	for _, dbTable := range dbTables {
+		existing[dbTable.Name] = dbTable
+	}
+
+	var statements []string
+	for _, table := range tables {
+		dbTable, ok := existing[table.Name]
+		if !ok {
+			continue
+		}
+		current := state{schema: dbTable.Schema, comment: dbTable.Comment, columns: make(map[string]string)}
+		columns, err := in.Columns(table.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range columns {
+			current.columns[column.Name] = column.Comment
+		}
+		if mysqlFamily(dialect) && needsColumnChange(table, current) {
+			var name, ddl string
+			if err := db.QueryRow("SHOW CREATE TABLE "+quote(dialect, table.Name)).Scan(&name, &ddl); err != nil {
+				return nil, fmt.Errorf("获取表 %s 的定义失败: %w", table.Name, err)
+			}
+			current.definitions = mysqlColumnDefinitions(ddl)
+		}
+		statements = append(statements, tableStatements(dialect, table, current)...)
+	}
+	return statements, nil
+}
+
+// Sync 生成并依次执行同步注释的语句，返回已执行（DryRun 时为待执行）的语句
+func Sync(db *sql.DB, dialect string, tables []Table, opts Options) ([]string, error) {
+	statements, err := Statements(db, dialect, tables)
+	if err != nil || opts.DryRun {
+		return statements, err
+	}
+	for i, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return statements[:i], fmt.Errorf("执行 %s 失败: %w", statement, err)
+		}
+	}
+	return statements, nil
+}
+
+// supported 方言是否支持注释
+func supported(dialect string) bool {
+	switch dialect {
+	case "sqlite", "sqlite3", "":
+		return false
+	}
+	return introspect.Supported(dialect)
+}
+
+// mysqlFamily MySQL 协议兼容的方言
+func mysqlFamily(dialect string) bool {
+	switch dialect {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		return true
+	}
+	return false
+}
+
+// needsColumnChange 是否有列注释需要修改
+func needsColumnChange(table Table, current state) bool {
+	for _, column := range table.Columns {
+		if existing, ok := current.columns[column.Name]; ok && existing != column.Comment {
+			return true
+		}
+	}
+	return false
+}
+
+// tableStatements 生成单个表的注释语句，表注释在前
+func tableStatements(dialect string, table Table, current state) []string {
+	var statements []string
+	if table.Comment != "" && table.Comment != current.comment {
+		statements = append(statements, tableCommentSQL(dialect, table.Name, table.Comment, current))
+	}
+	for _, column := range table.Columns {
+		existing, ok := current.columns[column.Name]
+		if !ok || existing == column.Comment {
+			continue
+		}
+		if statement := columnCommentSQL(dialect, table.Name, column.Name, column.Comment, current); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+// tableCommentSQL 修改表注释的语句
+func tableCommentSQL(dialect, table, comment string, current state) string {
+	switch {
+	case mysqlFamily(dialect):
+		return fmt.Sprintf("ALTER TABLE %s COMMENT = %s", quote(dialect, table), literal(dialect, comment))
+	case dialect == "clickhouse":
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COMMENT %s", quote(dialect, table), literal(dialect, comment))
+	case dialect == "sqlserver" || dialect == "mssql":
+		return sqlserverDescription(current, comment, current.comment != "", table, "")
+	default:
+		return fmt.Sprintf("COMMENT ON TABLE %s IS %s", quote(dialect, table), literal(dialect, comment))
+	}
+}
+
+// columnCommentSQL 修改列注释的语句，MySQL 系找不到列定义时返回空字符串
+func columnCommentSQL(dialect, table, column, comment string, current state) string {
+	switch {
+	case mysqlFamily(dialect):
+		definition, ok := current.definitions[column]
+		if !ok {
+			return ""
+		}
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s COMMENT %s", quote(dialect, table), definition, literal(dialect, comment))
+	case dialect == "clickhouse":
+		return fmt.Sprintf("ALTER TABLE %s COMMENT COLUMN %s %s", quote(dialect, table), quote(dialect, column), literal(dialect, comment))
+	case dialect == "sqlserver" || dialect == "mssql":
+		return sqlserverDescription(current, comment, current.columns[column] != "", table, column)
+	default:
+		return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", quote(dialect, table), quote(dialect, column), literal(dialect, comment))
+	}
+}
+
+// sqlserverDescription 添加或更新 MS_Description 扩展属性，column 为空时为表注释
+func sqlserverDescription(current state, comment string, exists bool, table, column string) string {
+	procedure := "sp_addextendedproperty"
+	if exists {
+		procedure = "sp_updateextendedproperty"
+	}
+	schemaName := current.schema
+	if schemaName == "" {
+		schemaName = "dbo"
+	}
+	statement := fmt.Sprintf("EXEC %s @name = N'MS_Description', @value = %s, @level0type = N'SCHEMA', @level0name = %s, @level1type = N'TABLE', @level1name = %s",
+		procedure, literal("sqlserver", comment), literal("sqlserver", schemaName), literal("sqlserver", table))
+	if column != "" {
+		statement += fmt.Sprintf(", @level2type = N'COLUMN', @level2name = %s", literal("sqlserver", column))
+	}
+	return statement
+}
+
+// quote 按方言引用标识符；Oracle 不加引号，按数据库规则转为大写
+func quote(dialect, name string) string {
+	switch {
+	case mysqlFamily(dialect), dialect == "clickhouse":
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case dialect == "sqlserver" || dialect == "mssql":
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	case dialect == "oracle":
+		return name
+	default:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+}
+
+// literal 按方言生成字符串字面量：MySQL 系和 ClickHouse 同时转义反斜杠，SQL Server 使用 N 前缀
+func literal(dialect, s string) string {
+	if mysqlFamily(dialect) || dialect == "clickhouse" {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	s = "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	if dialect == "sqlserver" || dialect == "mssql" {
+		s = "N" + s
+	}
+	return s
+}
+
+// mysqlCommentClause 列定义中的 COMMENT 子句
+var mysqlCommentClause = regexp.MustCompile(` COMMENT '(?:[^'\\]|\\.|'')*'`)
+
+// mysqlColumnDefinitions 从 SHOW CREATE TABLE 的结果中取出各列的定义，去掉原有的 COMMENT 子句
+func mysqlColumnDefinitions(ddl string) map[string]string {
+	definitions := make(map[string]string)
+	for _, line := range strings.Split(ddl, "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if !strings.HasPrefix(line, "`") {
+			continue
+		}
+		end := strings.Index(line[1:], "`")
+		if end < 0 {
+			continue
+		}
+		definitions[line[1:end+1]] = mysqlCommentClause.ReplaceAllString(line, "")
+	}
+	return definitions
+}
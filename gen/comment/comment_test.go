@@ -0,0 +1,130 @@
+package comment
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// models 生成的模型和手写的模型
+const models = `package poes
+
+// Users 用户表
+type Users struct {
+	ID   int64  ` + "`gorm:\"column:id;primaryKey\"`" + ` // 主键
+	// Name 用户名
+	Name string ` + "`gorm:\"column:user_name\"`" + `
+	Age  int    ` + "`gorm:\"comment:年龄\"`" + ` // 被标签覆盖
+	Temp string ` + "`gorm:\"-\"`" + ` // 不是列
+	Orders []Orders // 关联
+	Audit
+}
+
+// TableName 表名
+func (m *Users) TableName() string {
+	return "users"
+}
+
+// Audit 审计字段
+type Audit struct {
+	CreatedBy string // 创建人
+}
+
+// OrderItem 订单明细
+type OrderItem struct {
+	Qty int ` + "`gorm:\"not null\"`" + ` // 数量
+}
+
+type Orders struct {
+	ID int64 ` + "`gorm:\"primaryKey\"`" + `
+}
+`
+
+// 测试从 Go 源码解析表和列注释
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "poes.go"), []byte(models), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tables, err := Parse(dir)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	want := []Table{
+		{Model: "Users", Name: "users", Comment: "用户表", Columns: []Column{
+			{Field: "ID", Name: "id", Comment: "主键"},
+			{Field: "Name", Name: "user_name", Comment: "用户名"},
+			{Field: "Age", Name: "age", Comment: "年龄"},
+			{Field: "CreatedBy", Name: "created_by", Comment: "创建人"},
+		}},
+		{Model: "OrderItem", Name: "order_items", Comment: "订单明细", Columns: []Column{{Field: "Qty", Name: "qty", Comment: "数量"}}},
+		{Model: "Orders", Name: "orders"},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("Parse =\n%+v\n期望\n%+v", tables, want)
+	}
+}
+
+// 测试各方言生成的注释语句
+func TestTableStatements(t *testing.T) {
+	table := Table{Name: "users", Comment: "用户表", Columns: []Column{
+		{Name: "id", Comment: "主键"},
+		{Name: "name", Comment: "用户's 名称"},
+		{Name: "missing", Comment: "不存在的列"},
+	}}
+	current := state{comment: "旧注释", columns: map[string]string{"id": "主键", "name": ""}}
+
+	ddl := "CREATE TABLE `users` (\n  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '主键',\n  `name` varchar(64) COLLATE utf8mb4_bin NOT NULL DEFAULT '' COMMENT 'it''s',\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+	current.definitions = mysqlColumnDefinitions(ddl)
+	if got := current.definitions["name"]; got != "`name` varchar(64) COLLATE utf8mb4_bin NOT NULL DEFAULT ''" {
+		t.Errorf("列定义 = %q", got)
+	}
+
+	cases := map[string][]string{
+		"mysql": {
+			"ALTER TABLE `users` COMMENT = '用户表'",
+			"ALTER TABLE `users` MODIFY COLUMN `name` varchar(64) COLLATE utf8mb4_bin NOT NULL DEFAULT '' COMMENT '用户''s 名称'",
+		},
+		"postgres": {
+			`COMMENT ON TABLE "users" IS '用户表'`,
+			`COMMENT ON COLUMN "users"."name" IS '用户''s 名称'`,
+		},
+		"oracle": {
+			"COMMENT ON TABLE users IS '用户表'",
+			"COMMENT ON COLUMN users.name IS '用户''s 名称'",
+		},
+		"clickhouse": {
+			"ALTER TABLE `users` MODIFY COMMENT '用户表'",
+			"ALTER TABLE `users` COMMENT COLUMN `name` '用户''s 名称'",
+		},
+		"sqlserver": {
+			"EXEC sp_updateextendedproperty @name = N'MS_Description', @value = N'用户表', @level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'TABLE', @level1name = N'users'",
+			"EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'用户''s 名称', @level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'TABLE', @level1name = N'users', @level2type = N'COLUMN', @level2name = N'name'",
+		},
+	}
+	for dialect, want := range cases {
+		if got := tableStatements(dialect, table, current); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s =\n%q\n期望\n%q", dialect, got, want)
+		}
+	}
+	if got := literal("mysql", `a\b`); got != `'a\\b'` {
+		t.Errorf("MySQL 字面量 = %s", got)
+	}
+}
+
+// 测试 SQLite 不支持注释
+func TestSQLiteUnsupported(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := Sync(db, "sqlite", []Table{{Name: "users", Comment: "用户表"}}, Options{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("期望 ErrUnsupported，实际为 %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package comment
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// Table 模型对应的表及其注释
+type Table struct {
+	Model   string   // 模型类型名
+	Name    string   // 表名，取自 TableName 方法，没有时按 GORM 默认命名
+	Comment string   // 表注释，取自类型的文档注释
+	Columns []Column // 有注释的列，按字段顺序
+}
+
+// Column 字段对应的列及其注释
+type Column struct {
+	Field   string // 字段名
+	Name    string // 列名，取自 gorm 标签的 column，没有时按 GORM 默认命名
+	Comment string // 列注释
+}
+
+// relationKeys 表示关联字段的 gorm 标签，关联字段不对应列
+var relationKeys = []string{"FOREIGNKEY", "REFERENCES", "MANY2MANY", "POLYMORPHIC", "JOINFOREIGNKEY"}
+
+// Parse 解析目录中的 Go 模型（不含 _test.go），返回带注释的表
+// 包含 TableName 方法或带 gorm 标签字段的结构体视为模型。列注释依次取 gorm 标签的 comment、
+// 字段的文档注释和行尾注释；表注释取类型的文档注释，去掉开头的类型名，如 // Users 用户表 => 用户表。
+// 值嵌入的同包结构体按 GORM 的规则展开为列，关联字段和 gorm:"-" 字段忽略
+func Parse(dir string) ([]Table, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	p := &parsed{structs: make(map[string]*ast.TypeSpec), docs: make(map[string]*ast.CommentGroup), tableNames: make(map[string]string)}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %w", file, err)
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", file, err)
+		}
+		p.collect(f)
+	}
+
+	var tables []Table
+	for _, name := range p.order {
+		spec := p.structs[name]
+		columns := p.columns(spec.Type.(*ast.StructType), map[string]bool{name: true})
+		tableName, hasTableName := p.tableNames[name]
+		if !hasTableName && !p.hasGormTags(spec.Type.(*ast.StructType)) {
+			continue
+		}
+		if !hasTableName {
+			tableName = schema.NamingStrategy{}.TableName(name)
+		}
+		var withComment []Column
+		for _, column := range columns {
+			if column.Comment != "" {
+				withComment = append(withComment, column)
+			}
+		}
+		tables = append(tables, Table{Model: name, Name: tableName, Comment: docText(p.docs[name], name), Columns: withComment})
+	}
+	return tables, nil
+}
+
+// parsed 目录中的结构体类型和 TableName 方法
+type parsed struct {
+	order      []string                     // 结构体按出现顺序
+	structs    map[string]*ast.TypeSpec     // 类型名 -> 结构体定义
+	docs       map[string]*ast.CommentGroup // 类型名 -> 文档注释
+	tableNames map[string]string            // 类型名 -> TableName 返回的表名
+}
+
+// collect 收集文件中的结构体和返回字符串字面量的 TableName 方法
+func (p *parsed) collect(f *ast.File) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if _, ok := ts.Type.(*ast.StructType); !ok || !ts.Name.IsExported() {
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil && len(d.Specs) == 1 {
+					doc = d.Doc
+				}
+				p.order = append(p.order, ts.Name.Name)
+				p.structs[ts.Name.Name] = ts
+				p.docs[ts.Name.Name] = doc
+			}
+		case *ast.FuncDecl:
+			if d.Name.Name != "TableName" || d.Recv == nil || len(d.Recv.List) != 1 || d.Body == nil || len(d.Body.List) != 1 {
+				continue
+			}
+			ret, ok := d.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			lit, ok := ret.Results[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				p.tableNames[receiverName(d.Recv.List[0].Type)] = name
+			}
+		}
+	}
+}
+
+// columns 返回结构体字段对应的列，seen 防止嵌入循环
+func (p *parsed) columns(st *ast.StructType, seen map[string]bool) []Column {
+	var columns []Column
+	for _, field := range st.Fields.List {
+		settings := gormSettings(field)
+		if ignored(settings) {
+			continue
+		}
+		if len(field.Names) == 0 {
+			// 值嵌入的同包结构体展开为列
+			if ident, ok := field.Type.(*ast.Ident); ok && p.structs[ident.Name] != nil && !seen[ident.Name] {
+				seen[ident.Name] = true
+				columns = append(columns, p.columns(p.structs[ident.Name].Type.(*ast.StructType), seen)...)
+			}
+			continue
+		}
+		if p.isRelation(field.Type) {
+			continue
+		}
+		comment := settings["COMMENT"]
+		for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+			if comment == "" {
+				comment = docText(group, field.Names[0].Name)
+			}
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			column := settings["COLUMN"]
+			if column == "" {
+				column = schema.NamingStrategy{}.ColumnName("", name.Name)
+			}
+			columns = append(columns, Column{Field: name.Name, Name: column, Comment: comment})
+		}
+	}
+	return columns
+}
+
+// isRelation 字段类型为同包模型、模型指针或模型切片时视为关联
+func (p *parsed) isRelation(expr ast.Expr) bool {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ArrayType:
+			expr = t.Elt
+		case *ast.Ident:
+			return p.structs[t.Name] != nil
+		default:
+			return false
+		}
+	}
+}
+
+// hasGormTags 结构体是否有带 gorm 标签的字段
+func (p *parsed) hasGormTags(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if field.Tag != nil {
+			if _, ok := structTag(field).Lookup("gorm"); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structTag 字段的结构体标签
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+// gormSettings 解析字段的 gorm 标签，键为大写
+func gormSettings(field *ast.Field) map[string]string {
+	return schema.ParseTagSetting(structTag(field).Get("gorm"), ";")
+}
+
+// ignored 字段是否不对应列：gorm:"-"、只读或关联字段
+func ignored(settings map[string]string) bool {
+	if value, ok := settings["-"]; ok && (value == "-" || strings.EqualFold(value, "all") || strings.EqualFold(value, "migration")) {
+		return true
+	}
+	for _, key := range relationKeys {
+		if _, ok := settings[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// receiverName 方法接收者的类型名
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// docText 注释文本合并为一行，去掉开头的标识符名，如 // Name 用户名 => 用户名
+func docText(group *ast.CommentGroup, name string) string {
+	if group == nil {
+		return ""
+	}
+	text := strings.Join(strings.Fields(group.Text()), " ")
+	if rest, ok := strings.CutPrefix(text, name); ok && (rest == "" || rest[0] == ' ') {
+		text = strings.TrimSpace(rest)
+	}
+	return text
+}
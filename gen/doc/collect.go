@@ -0,0 +1,99 @@
+package doc
+
+import (
+	"database/sql"
+
+	"github.com/gzorm/gosqlx/gen/internal/walk"
+	"github.com/gzorm/gosqlx/introspect"
+)
+
+// Progress 生成进度，每获取完一个表回调一次
+type Progress = walk.Progress
+
+// collectTables 通过 introspect 获取所有表的文档信息，按配置过滤并并发获取
+func collectTables(db *sql.DB, config *Config) ([]TableDoc, error) {
+	in := introspect.New(db, string(config.DBType))
+	dbTables, err := in.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(dbTables))
+	byName := make(map[string]introspect.Table, len(dbTables))
+	for i, dbTable := range dbTables {
+		names[i] = dbTable.Name
+		byName[dbTable.Name] = dbTable
+	}
+	return walk.Run(names, walk.Options{
+		Concurrency: config.Concurrency,
+		Include:     config.IncludeTables,
+		Exclude:     config.ExcludeTables,
+		ResumeFile:  config.ResumeFile,
+		Progress:    config.Progress,
+	}, func(name string) (TableDoc, error) {
+		return collectTable(in, byName[name])
+	})
+}
+
+// collectTable 获取单个表的列、主键和索引
+func collectTable(in *introspect.Inspector, dbTable introspect.Table) (TableDoc, error) {
+	table := TableDoc{
+		TableName:    dbTable.Name,
+		TableComment: dbTable.Comment,
+	}
+
+	dbColumns, err := in.Columns(dbTable.Name)
+	if err != nil {
+		return TableDoc{}, err
+	}
+	for _, dbColumn := range dbColumns {
+		table.Columns = append(table.Columns, newColumnDoc(dbColumn))
+	}
+
+	if table.PrimaryKeys, err = in.PrimaryKeys(dbTable.Name); err != nil {
+		return TableDoc{}, err
+	}
+
+	dbIndexes, err := in.Indexes(dbTable.Name)
+	if err != nil {
+		return TableDoc{}, err
+	}
+	for _, dbIndex := range dbIndexes {
+		// 主键已单独列出
+		if dbIndex.Primary {
+			continue
+		}
+		table.Indexes = append(table.Indexes, IndexDoc{
+			IndexName: dbIndex.Name,
+			Columns:   dbIndex.Columns,
+			IndexType: dbIndex.Type,
+			IsUnique:  dbIndex.Unique,
+		})
+	}
+	return table, nil
+}
+
+// newColumnDoc 将 introspect 列信息转换为文档列信息
+func newColumnDoc(dbColumn introspect.Column) ColumnDoc {
+	col := ColumnDoc{
+		ColumnName:    dbColumn.Name,
+		DataType:      dbColumn.ColumnType,
+		IsNullable:    "NO",
+		ColumnDefault: "NULL",
+		ColumnComment: dbColumn.Comment,
+		Extra:         dbColumn.Extra,
+	}
+	if dbColumn.Nullable {
+		col.IsNullable = "YES"
+	}
+	if dbColumn.Default.Valid {
+		col.ColumnDefault = dbColumn.Default.String
+	}
+	if dbColumn.PrimaryKey {
+		col.ColumnKey = "PRI"
+	}
+	if col.Extra == "" && dbColumn.AutoIncrement {
+		col.Extra = "auto_increment"
+	}
+	return col
+}
@@ -0,0 +1,60 @@
+package doc
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/gzorm/gosqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// 测试通过 introspect 收集表文档信息
+func TestCollectTables(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(64) NOT NULL DEFAULT 'guest')`,
+		`CREATE UNIQUE INDEX idx_users_name ON users (name)`,
+		`CREATE TABLE logs (id INTEGER PRIMARY KEY)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+
+	tables, err := collectTables(db, &Config{DBType: gosqlx.SQLite, ExcludeTables: []string{"logs"}})
+	if err != nil {
+		t.Fatalf("collectTables() error = %v", err)
+	}
+	if len(tables) != 1 || tables[0].TableName != "users" {
+		t.Fatalf("collectTables() = %+v", tables)
+	}
+	users := tables[0]
+	want := []ColumnDoc{
+		{ColumnName: "id", DataType: "INTEGER", IsNullable: "NO", ColumnDefault: "NULL", ColumnKey: "PRI", Extra: "auto_increment"},
+		{ColumnName: "name", DataType: "VARCHAR(64)", IsNullable: "NO", ColumnDefault: "'guest'"},
+	}
+	if !reflect.DeepEqual(users.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", users.Columns, want)
+	}
+	if !reflect.DeepEqual(users.PrimaryKeys, []string{"id"}) {
+		t.Errorf("PrimaryKeys = %v", users.PrimaryKeys)
+	}
+	if len(users.Indexes) != 1 || !users.Indexes[0].IsUnique {
+		t.Errorf("Indexes = %+v", users.Indexes)
+	}
+}
+
+func TestGenerateDBDocUnsupported(t *testing.T) {
+	if err := GenerateDBDoc(&Config{DBType: gosqlx.MongoDB}); err == nil {
+		t.Error("MongoDB 应返回不支持的数据库类型")
+	}
+	if err := GeneratePostgresDoc(&Config{DBType: gosqlx.MySQL}); err == nil {
+		t.Error("数据库类型不匹配时应返回错误")
+	}
+}
@@ -0,0 +1,213 @@
+package doc
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// overviewSheet 概览工作表名称
+	overviewSheet = "概览"
+	// maxSheetName Excel 工作表名称的最大长度
+	maxSheetName = 31
+)
+
+// excelColumnHeaders 表工作表的列信息表头
+var excelColumnHeaders = []interface{}{"列名", "数据类型", "允许空值", "默认值", "键类型", "额外信息", "注释"}
+
+// sheetNames 为每个表生成合法且不重复的工作表名称
+// 去掉 Excel 不允许的字符，超过 31 个字符时截断，重名（不区分大小写）时追加 ~2、~3 等后缀
+func sheetNames(tables []TableDoc) []string {
+	used := map[string]bool{strings.ToLower(overviewSheet): true}
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		base := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`:\/?*[]`, r) {
+				return '_'
+			}
+			return r
+		}, table.TableName)
+		// 名称不能以单引号开头或结尾
+		base = strings.Trim(base, "'")
+		if base == "" {
+			base = "Sheet"
+		}
+
+		name := truncate(base, maxSheetName)
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf("~%d", n)
+			name = truncate(base, maxSheetName-len(suffix)) + suffix
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// truncate 按字符数截断
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// sheetLink 工作表内部超链接地址
+func sheetLink(sheet string) string {
+	return fmt.Sprintf("'%s'!A1", strings.ReplaceAll(sheet, "'", "''"))
+}
+
+// cellName 行列号转单元格名称，行列号均从 1 开始
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
+
+// writeExcelDoc 生成Excel文档：概览工作表带有到各表工作表的超链接，表头冻结并启用筛选
+func writeExcelDoc(tables []TableDoc, config *Config) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#DDEBF7"}, Pattern: 1},
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	linkStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "#0563C1", Underline: "single"},
+	})
+	if err != nil {
+		return err
+	}
+
+	// 创建概览工作表
+	if err := f.SetSheetName("Sheet1", overviewSheet); err != nil {
+		return err
+	}
+	overviewHeaders := []interface{}{"表名", "注释", "列数", "索引数"}
+	if err := writeHeader(f, overviewSheet, overviewHeaders, headerStyle); err != nil {
+		return err
+	}
+
+	names := sheetNames(tables)
+	for i, table := range tables {
+		sheet := names[i]
+		row := i + 2
+		if err := f.SetSheetRow(overviewSheet, cellName(1, row), &[]interface{}{
+			table.TableName, table.TableComment, len(table.Columns), len(table.Indexes),
+		}); err != nil {
+			return err
+		}
+		if err := f.SetCellHyperLink(overviewSheet, cellName(1, row), sheetLink(sheet), "Location"); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(overviewSheet, cellName(1, row), cellName(1, row), linkStyle); err != nil {
+			return err
+		}
+
+		if err := writeTableSheet(f, sheet, table, headerStyle, linkStyle); err != nil {
+			return fmt.Errorf("生成表 %s 的工作表失败: %w", table.TableName, err)
+		}
+	}
+	if err := finishSheet(f, overviewSheet, len(overviewHeaders), len(tables)+1); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(overviewSheet, "A", "B", 30); err != nil {
+		return err
+	}
+
+	return f.SaveAs(config.OutputPath)
+}
+
+// writeTableSheet 生成单个表的工作表：列信息、返回概览的链接和索引信息
+func writeTableSheet(f *excelize.File, sheet string, table TableDoc, headerStyle, linkStyle int) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return err
+	}
+	if err := writeHeader(f, sheet, excelColumnHeaders, headerStyle); err != nil {
+		return err
+	}
+	for j, col := range table.Columns {
+		if err := f.SetSheetRow(sheet, cellName(1, j+2), &[]interface{}{
+			col.ColumnName, col.DataType, col.IsNullable, col.ColumnDefault, col.ColumnKey, col.Extra, col.ColumnComment,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 表头右侧放置返回概览的链接
+	back := cellName(len(excelColumnHeaders)+2, 1)
+	if err := f.SetCellValue(sheet, back, "返回概览"); err != nil {
+		return err
+	}
+	if err := f.SetCellHyperLink(sheet, back, sheetLink(overviewSheet), "Location"); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, back, back, linkStyle); err != nil {
+		return err
+	}
+
+	// 添加索引信息
+	if len(table.Indexes) > 0 {
+		indexRow := len(table.Columns) + 3
+		indexHeaders := []interface{}{"索引名", "类型", "索引方法", "列"}
+		if err := f.SetSheetRow(sheet, cellName(1, indexRow), &indexHeaders); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, cellName(1, indexRow), cellName(len(indexHeaders), indexRow), headerStyle); err != nil {
+			return err
+		}
+		for j, idx := range table.Indexes {
+			idxType := "普通索引"
+			if idx.IsUnique {
+				idxType = "唯一索引"
+			}
+			if err := f.SetSheetRow(sheet, cellName(1, indexRow+j+1), &[]interface{}{
+				idx.IndexName, idxType, idx.IndexType, strings.Join(idx.Columns, ", "),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return finishSheet(f, sheet, len(excelColumnHeaders), len(table.Columns)+1)
+}
+
+// writeHeader 写入第一行表头并设置样式
+func writeHeader(f *excelize.File, sheet string, headers []interface{}, style int) error {
+	if err := f.SetSheetRow(sheet, "A1", &headers); err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, "A1", cellName(len(headers), 1), style)
+}
+
+// finishSheet 冻结表头、对表头到最后一行启用筛选并设置列宽
+func finishSheet(f *excelize.File, sheet string, cols, rows int) error {
+	if err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+	if err := f.AutoFilter(sheet, "A1:"+cellName(cols, rows), nil); err != nil {
+		return err
+	}
+	last, err := excelize.ColumnNumberToName(cols)
+	if err != nil {
+		return err
+	}
+	return f.SetColWidth(sheet, "A", last, 15)
+}
@@ -0,0 +1,71 @@
+package doc
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSheetNames(t *testing.T) {
+	long := strings.Repeat("a", 40)
+	tables := []TableDoc{
+		{TableName: "users"},
+		{TableName: "USERS"},
+		{TableName: long},
+		{TableName: long + "_b"},
+		{TableName: "logs[2024]/a:b"},
+		{TableName: "概览"},
+		{TableName: "'quoted'"},
+	}
+	want := []string{
+		"users",
+		"USERS~2",
+		strings.Repeat("a", 31),
+		strings.Repeat("a", 29) + "~2",
+		"logs_2024__a_b",
+		"概览~2",
+		"quoted",
+	}
+	if got := sheetNames(tables); !reflect.DeepEqual(got, want) {
+		t.Errorf("sheetNames() = %v, want %v", got, want)
+	}
+}
+
+// 测试Excel文档的工作表、超链接和筛选
+func TestWriteExcelDoc(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "db.xlsx")
+	tables := []TableDoc{
+		{TableName: "users", Columns: []ColumnDoc{{ColumnName: "id"}, {ColumnName: "name"}},
+			Indexes: []IndexDoc{{IndexName: "idx_name", Columns: []string{"name"}, IsUnique: true}}},
+		{TableName: "Users"},
+	}
+	if err := writeExcelDoc(tables, &Config{OutputPath: output}); err != nil {
+		t.Fatalf("writeExcelDoc() error = %v", err)
+	}
+
+	f, err := excelize.OpenFile(output)
+	if err != nil {
+		t.Fatalf("打开Excel失败: %v", err)
+	}
+	defer f.Close()
+
+	if got, want := f.GetSheetList(), []string{"概览", "users", "Users~2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSheetList() = %v, want %v", got, want)
+	}
+	if ok, link, _ := f.GetCellHyperLink("概览", "A3"); !ok || link != "'Users~2'!A1" {
+		t.Errorf("概览 A3 超链接 = %v %q", ok, link)
+	}
+	if ok, link, _ := f.GetCellHyperLink("users", "I1"); !ok || link != "'概览'!A1" {
+		t.Errorf("返回概览超链接 = %v %q", ok, link)
+	}
+	if value, _ := f.GetCellValue("users", "A6"); value != "idx_name" {
+		t.Errorf("索引信息 = %q", value)
+	}
+	panes, err := f.GetPanes("users")
+	if err != nil || !panes.Freeze || panes.TopLeftCell != "A2" {
+		t.Errorf("GetPanes() = %+v, %v", panes, err)
+	}
+}
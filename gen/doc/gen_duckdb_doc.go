@@ -0,0 +1,53 @@
+package doc
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// createDuckDBDBConnection 创建DuckDB数据库连接，需在程序中导入 DuckDB 驱动
+func createDuckDBDBConnection(config *Config) (*sql.DB, error) {
+	dbConfig := &gosqlx.Config{
+		Type:        config.DBType,
+		Source:      config.Source,
+		MaxIdle:     1,
+		MaxOpen:     1,
+		MaxLifetime: time.Hour,
+	}
+	ctx := &gosqlx.Context{
+		Context: nil,
+		Nick:    "duckdb_doc_generator",
+		Mode:    "ro",
+		DBType:  config.DBType,
+		Timeout: time.Second * 30,
+	}
+	database, err := gosqlx.NewDatabase(ctx, dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	return database.SqlDB(), nil
+}
+
+// GenerateDuckDBDoc 生成DuckDB数据库文档
+func GenerateDuckDBDoc(config *Config) error {
+	db, err := createDuckDBDBConnection(config)
+	if err != nil {
+		return fmt.Errorf("连接DuckDB数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	tables, err := collectTables(db, config)
+	if err != nil {
+		return fmt.Errorf("获取DuckDB表信息失败: %v", err)
+	}
+
+	// 生成Word文档
+	err = generateWordDoc(tables, config)
+	if err != nil {
+		return fmt.Errorf("生成Word文档失败: %v", err)
+	}
+	return nil
+}
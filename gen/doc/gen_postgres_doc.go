@@ -0,0 +1,16 @@
+package doc
+
+import (
+	"fmt"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// GeneratePostgresDoc 生成PostgreSQL数据库文档
+// 读取当前 schema 的表结构，表和列注释来自 pg_catalog（COMMENT ON）
+func GeneratePostgresDoc(config *Config) error {
+	if config.DBType != gosqlx.PostgresSQL {
+		return fmt.Errorf("不支持的数据库类型: %s", config.DBType)
+	}
+	return GenerateDBDoc(config)
+}
@@ -0,0 +1,16 @@
+package doc
+
+import (
+	"fmt"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// GenerateSQLServerDoc 生成SQL Server数据库文档
+// 读取 sys.* 目录视图，表和列注释来自扩展属性 MS_Description
+func GenerateSQLServerDoc(config *Config) error {
+	if config.DBType != gosqlx.SQLServer {
+		return fmt.Errorf("不支持的数据库类型: %s", config.DBType)
+	}
+	return GenerateDBDoc(config)
+}
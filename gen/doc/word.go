@@ -0,0 +1,276 @@
+package doc
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nguyenthenguyen/docx"
+)
+
+// defaultTemplate 内置的空白模板，正文只有一个 {{content}} 段落
+//
+//go:embed blank.docx
+var defaultTemplate []byte
+
+// contentPlaceholder 模板中正文的占位符，所在段落会被替换为生成的表格
+const contentPlaceholder = "{{content}}"
+
+// WordColumn Word 文档中列信息表格的列
+type WordColumn string
+
+// 列信息表格可选的列
+const (
+	WordColumnName     WordColumn = "name"     // 列名
+	WordColumnType     WordColumn = "type"     // 数据类型
+	WordColumnNullable WordColumn = "nullable" // 允许空值
+	WordColumnDefault  WordColumn = "default"  // 默认值
+	WordColumnKey      WordColumn = "key"      // 键类型
+	WordColumnExtra    WordColumn = "extra"    // 额外信息
+	WordColumnComment  WordColumn = "comment"  // 注释
+)
+
+// DefaultWordColumns 默认输出的列及顺序
+var DefaultWordColumns = []WordColumn{
+	WordColumnName, WordColumnType, WordColumnNullable, WordColumnDefault,
+	WordColumnKey, WordColumnExtra, WordColumnComment,
+}
+
+// wordColumnTitles 列标题
+var wordColumnTitles = map[WordColumn]string{
+	WordColumnName:     "列名",
+	WordColumnType:     "数据类型",
+	WordColumnNullable: "允许空值",
+	WordColumnDefault:  "默认值",
+	WordColumnKey:      "键类型",
+	WordColumnExtra:    "额外信息",
+	WordColumnComment:  "注释",
+}
+
+// WordLayout Word 文档布局
+type WordLayout struct {
+	Columns     []WordColumn // 列信息表格的列及顺序，默认 DefaultWordColumns
+	TableStyle  string       // 模板中定义的表格样式ID，为空时使用单线边框
+	HeaderColor string       // 表头底色（十六进制RGB），默认 DDEBF7
+	PageBreak   bool         // 每个表从新的一页开始
+	HideIndexes bool         // 不输出索引表格
+}
+
+// value 获取列在表格中显示的值
+func (c WordColumn) value(col ColumnDoc) string {
+	switch c {
+	case WordColumnName:
+		return col.ColumnName
+	case WordColumnType:
+		return col.DataType
+	case WordColumnNullable:
+		return col.IsNullable
+	case WordColumnDefault:
+		return col.ColumnDefault
+	case WordColumnKey:
+		return col.ColumnKey
+	case WordColumnExtra:
+		return col.Extra
+	case WordColumnComment:
+		return col.ColumnComment
+	}
+	return ""
+}
+
+// generateWordDoc 基于模板生成Word文档，每个数据库表生成一个Word表格
+// 模板正文中需要有一个只包含 {{content}} 的段落，另外支持 {{title}}、{{author}}、{{company}}、{{dbname}}、{{date}} 占位符
+func generateWordDoc(tables []TableDoc, config *Config) error {
+	var (
+		r   *docx.ReplaceDocx
+		err error
+	)
+	if config.TemplatePath != "" {
+		r, err = docx.ReadDocxFile(config.TemplatePath)
+	} else {
+		r, err = docx.ReadDocxFromMemory(bytes.NewReader(defaultTemplate), int64(len(defaultTemplate)))
+	}
+	if err != nil {
+		return fmt.Errorf("无法打开Word模板: %w", err)
+	}
+	defer r.Close()
+	doc := r.Editable()
+
+	layout := config.Layout
+	if layout == nil {
+		layout = &WordLayout{}
+	}
+
+	// 用生成的正文替换占位符所在的整个段落
+	content := doc.GetContent()
+	start, end, ok := placeholderParagraph(content)
+	if !ok {
+		return fmt.Errorf("Word模板中缺少 %s 占位符", contentPlaceholder)
+	}
+	body := renderWordBody(tables, config, layout)
+	doc.SetContent(content[:start] + body + content[end:])
+
+	replacements := map[string]string{
+		"{{title}}":   config.Title,
+		"{{author}}":  config.Author,
+		"{{company}}": config.Company,
+		"{{dbname}}":  config.DBName,
+		"{{date}}":    time.Now().Format("2006-01-02"),
+	}
+	for placeholder, value := range replacements {
+		if err := doc.Replace(placeholder, value, -1); err != nil {
+			return fmt.Errorf("替换模板占位符失败: %w", err)
+		}
+	}
+
+	return doc.WriteToFile(config.OutputPath)
+}
+
+// placeholderParagraph 查找包含正文占位符的段落位置
+func placeholderParagraph(content string) (start, end int, ok bool) {
+	pos := strings.Index(content, contentPlaceholder)
+	if pos < 0 {
+		return 0, 0, false
+	}
+	start = max(strings.LastIndex(content[:pos], "<w:p>"), strings.LastIndex(content[:pos], "<w:p "))
+	closing := strings.Index(content[pos:], "</w:p>")
+	if start < 0 || closing < 0 {
+		return 0, 0, false
+	}
+	return start, pos + closing + len("</w:p>"), true
+}
+
+// renderWordBody 生成正文：文档信息、每个表的标题、列信息表格、主键和索引表格
+func renderWordBody(tables []TableDoc, config *Config, layout *WordLayout) string {
+	columns := layout.Columns
+	if len(columns) == 0 {
+		columns = DefaultWordColumns
+	}
+
+	var b strings.Builder
+	if config.Title != "" {
+		writeParagraph(&b, config.Title, true, 36, false)
+	}
+	writeParagraph(&b, fmt.Sprintf("作者: %s   公司: %s   生成时间: %s", config.Author, config.Company,
+		time.Now().Format("2006-01-02 15:04:05")), false, 0, false)
+	writeParagraph(&b, "数据库名称: "+config.DBName, false, 0, false)
+
+	for i, table := range tables {
+		heading := table.TableName
+		if table.TableComment != "" {
+			heading += "（" + table.TableComment + "）"
+		}
+		writeParagraph(&b, heading, true, 28, layout.PageBreak && i > 0)
+
+		header := make([]string, len(columns))
+		for n, column := range columns {
+			header[n] = wordColumnTitles[column]
+		}
+		rows := make([][]string, 0, len(table.Columns))
+		for _, col := range table.Columns {
+			row := make([]string, len(columns))
+			for n, column := range columns {
+				row[n] = column.value(col)
+			}
+			rows = append(rows, row)
+		}
+		writeTable(&b, layout, header, rows)
+
+		if len(table.PrimaryKeys) > 0 {
+			writeParagraph(&b, "主键: "+strings.Join(table.PrimaryKeys, ", "), false, 0, false)
+		}
+		if len(table.Indexes) > 0 && !layout.HideIndexes {
+			writeParagraph(&b, "索引", true, 0, false)
+			var indexRows [][]string
+			for _, idx := range table.Indexes {
+				idxType := "普通索引"
+				if idx.IsUnique {
+					idxType = "唯一索引"
+				}
+				indexRows = append(indexRows, []string{idx.IndexName, idxType, idx.IndexType, strings.Join(idx.Columns, ", ")})
+			}
+			writeTable(&b, layout, []string{"索引名", "类型", "索引方法", "列"}, indexRows)
+		}
+	}
+	return b.String()
+}
+
+// writeParagraph 写入段落，size 为字号（半磅），0 表示使用模板默认字号
+func writeParagraph(b *strings.Builder, text string, bold bool, size int, pageBreak bool) {
+	b.WriteString("<w:p>")
+	if pageBreak {
+		b.WriteString(`<w:pPr><w:pageBreakBefore/></w:pPr>`)
+	}
+	writeRun(b, text, bold, size)
+	b.WriteString("</w:p>")
+}
+
+// writeRun 写入文本片段
+func writeRun(b *strings.Builder, text string, bold bool, size int) {
+	b.WriteString("<w:r>")
+	if bold || size > 0 {
+		b.WriteString("<w:rPr>")
+		if bold {
+			b.WriteString("<w:b/>")
+		}
+		if size > 0 {
+			fmt.Fprintf(b, `<w:sz w:val="%d"/>`, size)
+		}
+		b.WriteString("</w:rPr>")
+	}
+	b.WriteString(`<w:t xml:space="preserve">`)
+	_ = xml.EscapeText(b, []byte(text))
+	b.WriteString("</w:t></w:r>")
+}
+
+// writeTable 写入表格，第一行为表头，表头在跨页时重复
+func writeTable(b *strings.Builder, layout *WordLayout, header []string, rows [][]string) {
+	b.WriteString("<w:tbl><w:tblPr>")
+	if layout.TableStyle != "" {
+		b.WriteString(`<w:tblStyle w:val="`)
+		_ = xml.EscapeText(b, []byte(layout.TableStyle))
+		b.WriteString(`"/>`)
+	}
+	b.WriteString(`<w:tblW w:w="5000" w:type="pct"/>`)
+	if layout.TableStyle == "" {
+		b.WriteString("<w:tblBorders>")
+		for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+			fmt.Fprintf(b, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="000000"/>`, side)
+		}
+		b.WriteString("</w:tblBorders>")
+	}
+	b.WriteString("</w:tblPr><w:tblGrid>")
+	for range header {
+		b.WriteString("<w:gridCol/>")
+	}
+	b.WriteString("</w:tblGrid>")
+
+	headerColor := layout.HeaderColor
+	if headerColor == "" {
+		headerColor = "DDEBF7"
+	}
+	b.WriteString("<w:tr><w:trPr><w:tblHeader/></w:trPr>")
+	for _, cell := range header {
+		b.WriteString("<w:tc><w:tcPr>")
+		fmt.Fprintf(b, `<w:shd w:val="clear" w:color="auto" w:fill="%s"/>`, strings.TrimPrefix(headerColor, "#"))
+		b.WriteString("</w:tcPr><w:p>")
+		writeRun(b, cell, true, 0)
+		b.WriteString("</w:p></w:tc>")
+	}
+	b.WriteString("</w:tr>")
+
+	for _, row := range rows {
+		b.WriteString("<w:tr>")
+		for _, cell := range row {
+			b.WriteString("<w:tc><w:p>")
+			writeRun(b, cell, false, 0)
+			b.WriteString("</w:p></w:tc>")
+		}
+		b.WriteString("</w:tr>")
+	}
+	b.WriteString("</w:tbl>")
+	// Word 要求表格后紧跟段落，否则相邻表格会被合并
+	b.WriteString("<w:p/>")
+}
@@ -0,0 +1,65 @@
+package doc
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nguyenthenguyen/docx"
+)
+
+// 测试使用内置模板生成Word表格
+func TestGenerateWordDoc(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "db.docx")
+	tables := []TableDoc{{
+		TableName:    "users",
+		TableComment: "用户<表>",
+		Columns: []ColumnDoc{
+			{ColumnName: "id", DataType: "INTEGER", IsNullable: "NO", ColumnDefault: "NULL", ColumnKey: "PRI"},
+			{ColumnName: "name", DataType: "VARCHAR(64)", IsNullable: "YES", ColumnDefault: "'guest'", ColumnComment: "姓名"},
+		},
+		PrimaryKeys: []string{"id"},
+		Indexes:     []IndexDoc{{IndexName: "idx_users_name", Columns: []string{"name"}, IndexType: "BTREE"}},
+	}}
+	config := &Config{
+		DBName:     "testdb",
+		OutputPath: output,
+		Title:      "数据库文档",
+		Layout:     &WordLayout{Columns: []WordColumn{WordColumnName, WordColumnType, WordColumnComment}},
+	}
+	if err := generateWordDoc(tables, config); err != nil {
+		t.Fatalf("generateWordDoc() error = %v", err)
+	}
+
+	r, err := docx.ReadDocxFile(output)
+	if err != nil {
+		t.Fatalf("读取Word文档失败: %v", err)
+	}
+	defer r.Close()
+	content := r.Editable().GetContent()
+
+	if err := xml.Unmarshal([]byte(content), new(struct{})); err != nil {
+		t.Errorf("生成的文档不是合法的XML: %v", err)
+	}
+	if strings.Contains(content, contentPlaceholder) {
+		t.Error("占位符未被替换")
+	}
+	if n := strings.Count(content, "<w:tbl>"); n != 2 {
+		t.Errorf("应生成列信息和索引两个表格，实际 %d 个", n)
+	}
+	for _, want := range []string{"users（用户&lt;表&gt;）", "VARCHAR(64)", "姓名", "idx_users_name", "主键: id"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("文档缺少 %s", want)
+		}
+	}
+	// 未选择的列不输出
+	if strings.Contains(content, "允许空值") || strings.Contains(content, "&#39;guest&#39;") {
+		t.Error("布局未选择的列不应输出")
+	}
+
+	config.TemplatePath = filepath.Join(t.TempDir(), "missing.docx")
+	if err := generateWordDoc(tables, config); err == nil {
+		t.Error("模板不存在时应返回错误")
+	}
+}
@@ -0,0 +1,249 @@
+// Package walk 为文档和模型生成器提供表过滤、并发获取表结构和断点续跑
+package walk
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+)
+
+// DefaultConcurrency 默认并发获取表结构的数量
+const DefaultConcurrency = 4
+
+// Options 遍历选项
+type Options struct {
+	Concurrency int            // 并发数量，<=0 时使用 DefaultConcurrency
+	Include     []string       // 只处理匹配的表，支持 path.Match 通配符，为空表示全部
+	Exclude     []string       // 排除匹配的表
+	ResumeFile  string         // 断点文件，为空表示不记录
+	Progress    func(Progress) // 进度回调，串行调用
+}
+
+// Progress 进度信息
+type Progress struct {
+	Table   string // 表名
+	Done    int    // 已完成的表数量
+	Total   int    // 需要处理的表数量
+	Resumed bool   // 是否从断点文件恢复
+}
+
+// Filter 按 Include/Exclude 模式过滤表名，保持原有顺序
+func Filter(names, include, exclude []string) ([]string, error) {
+	var result []string
+	for _, name := range names {
+		if len(include) > 0 {
+			ok, err := match(include, name)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		ok, err := match(exclude, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// match 判断名称是否匹配任一模式
+func match(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("无效的表名模式 %q: %w", pattern, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// entry 断点文件中的一行
+type entry[T any] struct {
+	Table string `json:"table"`
+	Data  T      `json:"data"`
+}
+
+// Run 过滤表名后并发调用 fetch 获取每个表的信息，结果顺序与过滤后的表名一致
+// 设置了 ResumeFile 时每完成一个表追加一行记录，重跑时跳过已完成的表，全部完成后删除断点文件
+// 任一表失败时停止分发新的表并返回第一个错误，已完成的表保留在断点文件中
+func Run[T any](names []string, opts Options, fetch func(name string) (T, error)) ([]T, error) {
+	names, err := Filter(names, opts.Include, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	finished, err := load[T](opts.ResumeFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint *os.File
+	if opts.ResumeFile != "" {
+		checkpoint, err = os.OpenFile(opts.ResumeFile, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("打开断点文件失败: %w", err)
+		}
+		defer checkpoint.Close()
+		if err := terminate(checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+		results  = make([]T, len(names))
+		pending  []int
+	)
+	report := func(name string, resumed bool) {
+		done++
+		if opts.Progress != nil {
+			opts.Progress(Progress{Table: name, Done: done, Total: len(names), Resumed: resumed})
+		}
+	}
+	for i, name := range names {
+		if data, ok := finished[name]; ok {
+			results[i] = data
+			report(name, true)
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > len(pending) {
+		concurrency = len(pending)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				mu.Lock()
+				stopped := firstErr != nil
+				mu.Unlock()
+				if stopped {
+					continue
+				}
+				data, err := fetch(names[i])
+
+				mu.Lock()
+				if err == nil && checkpoint != nil {
+					err = appendEntry(checkpoint, entry[T]{Table: names[i], Data: data})
+				}
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("获取表 %s 信息失败: %w", names[i], err)
+					}
+				} else {
+					results[i] = data
+					report(names[i], false)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, i := range pending {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if checkpoint != nil {
+		_ = checkpoint.Close()
+		if err := os.Remove(opts.ResumeFile); err != nil {
+			return nil, fmt.Errorf("删除断点文件失败: %w", err)
+		}
+	}
+	return results, nil
+}
+
+// appendEntry 追加一行断点记录
+func appendEntry[T any](file *os.File, e entry[T]) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("序列化断点记录失败: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入断点文件失败: %w", err)
+	}
+	return nil
+}
+
+// terminate 上次中断时最后一行可能不完整，补一个换行避免与新记录粘连
+func terminate(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("读取断点文件失败: %w", err)
+	}
+	if last[0] != '\n' {
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("写入断点文件失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// load 读取断点文件，文件不存在时返回空结果
+// 进程中断时最后一行可能不完整，无法解析的行会被忽略
+func load[T any](file string) (map[string]T, error) {
+	finished := make(map[string]T)
+	if file == "" {
+		return finished, nil
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return finished, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取断点文件失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var e entry[T]
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		finished[e.Table] = e.Data
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取断点文件失败: %w", err)
+	}
+	return finished, nil
+}
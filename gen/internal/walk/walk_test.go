@@ -0,0 +1,101 @@
+package walk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	names := []string{"users", "user_roles", "orders", "tmp_orders", "logs_2024"}
+
+	got, err := Filter(names, []string{"user*", "*orders"}, []string{"tmp_*"})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if want := []string{"users", "user_roles", "orders"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+
+	if _, err := Filter(names, []string{"["}, nil); err == nil {
+		t.Error("无效的模式应返回错误")
+	}
+}
+
+func TestRunKeepsOrder(t *testing.T) {
+	var names []string
+	for i := 0; i < 50; i++ {
+		names = append(names, strings.Repeat("t", i+1))
+	}
+
+	var progress []Progress
+	results, err := Run(names, Options{Concurrency: 8, Progress: func(p Progress) {
+		progress = append(progress, p)
+	}}, func(name string) (int, error) {
+		return len(name), nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for i, n := range results {
+		if n != i+1 {
+			t.Fatalf("results[%d] = %d", i, n)
+		}
+	}
+	if len(progress) != 50 || progress[49].Done != 50 || progress[49].Total != 50 {
+		t.Errorf("进度回调不正确: %+v", progress[len(progress)-1])
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	resume := filepath.Join(t.TempDir(), "resume.jsonl")
+	names := []string{"a", "b", "c", "d"}
+	failed := errors.New("connection reset")
+
+	// 第一次运行在 c 处失败
+	_, err := Run(names, Options{Concurrency: 1, ResumeFile: resume}, func(name string) (string, error) {
+		if name == "c" {
+			return "", failed
+		}
+		return strings.ToUpper(name), nil
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Run() error = %v, want %v", err, failed)
+	}
+
+	// 模拟中断时写了半行
+	f, err := os.OpenFile(resume, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("打开断点文件失败: %v", err)
+	}
+	_, _ = f.WriteString(`{"table":"d","da`)
+	_ = f.Close()
+
+	// 重跑时跳过已完成的表
+	var fetched int32
+	var resumed []string
+	results, err := Run(names, Options{ResumeFile: resume, Progress: func(p Progress) {
+		if p.Resumed {
+			resumed = append(resumed, p.Table)
+		}
+	}}, func(name string) (string, error) {
+		atomic.AddInt32(&fetched, 1)
+		return strings.ToUpper(name), nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"A", "B", "C", "D"}; !reflect.DeepEqual(results, want) {
+		t.Errorf("Run() = %v, want %v", results, want)
+	}
+	if fetched != 2 || !reflect.DeepEqual(resumed, []string{"a", "b"}) {
+		t.Errorf("fetched = %d, resumed = %v", fetched, resumed)
+	}
+	if _, err := os.Stat(resume); !os.IsNotExist(err) {
+		t.Errorf("完成后应删除断点文件: %v", err)
+	}
+}
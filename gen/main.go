@@ -1,3 +1,4 @@
+// 生成器使用示例；日常使用推荐 cmd/gosqlx 命令行工具，连接信息通过参数或配置文件传入
 package main
 
 import (
@@ -5,6 +6,9 @@ import (
 	"log"
 
 	"github.com/gzorm/gosqlx/gen/model"
+	"github.com/gzorm/gosqlx/gen/seed"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -105,6 +109,19 @@ func gen_SQLITE_POES() {
 		log.Fatalf("生成模型失败: %v", err)
 	}
 }
+func gen_DuckDB_POES() {
+	// 需导入 DuckDB 驱动: import _ "github.com/duckdb/duckdb-go/v2"
+	config := &model.Config{
+		DBType:       "duckdb",
+		DatabaseName: "./analytics.duckdb", // DuckDB 数据库文件路径
+		OutputDir:    "./gen/model",        // 会自动创建 model/poes 目录
+		PackageName:  "poes",               // 生成的包名
+	}
+
+	if err := model.GenerateModels(config); err != nil {
+		log.Fatalf("生成模型失败: %v", err)
+	}
+}
 func gen_MongoDb_POES() {
 	config := &model.Config{
 		DBType:       "mongodb",
@@ -154,3 +171,25 @@ func gen_ClickHouse_POES() {
 		log.Fatalf("生成模型失败: %v", err)
 	}
 }
+
+// gen_MySql_Seed 为生成的模型写入压测数据
+// 将 models 替换为生成的模型，例如 &models.User{}, &models.Article{}
+func gen_MySql_Seed(models ...interface{}) {
+	db, err := gorm.Open(mysql.Open("root:root@tcp(localhost:3306)/testdb?charset=utf8mb4&parseTime=True&loc=Local"), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("连接数据库失败: %v", err)
+	}
+
+	seeder := seed.NewSeeder(db, &seed.Config{
+		Rows:      10000, // 每张表写入行数
+		BatchSize: 1000,  // 批量写入大小
+	})
+	// 声明未在模型关联中体现的外键
+	seeder.Reference("articles.user_id", "users.id")
+
+	if err := seeder.Seed(models...); err != nil {
+		log.Fatalf("填充数据失败: %v", err)
+	}
+
+	fmt.Println("数据填充完成！")
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"text/template"
+	"time"
+)
+
+// columnsTemplate 表名和列名常量，供查询构建器的 From、SelectCols、WhereCol 等方法使用
+const columnsTemplate = `// 代码由 gosqlx 自动生成，请勿手动修改
+// 生成时间: {{.GenerateTime}}
+package {{.PackageName}}
+
+import "github.com/gzorm/gosqlx/builder"
+{{range .TableInfos}}
+// {{.ModelName}}Table {{.TableName}} 的表名
+const {{.ModelName}}Table builder.Table = {{printf "%q" .TableName}}
+
+// {{.ModelName}}Cols {{.TableName}} 的列名
+var {{.ModelName}}Cols = struct {
+{{- range .Columns}}
+	{{.FieldName}} builder.Column
+{{- end}}
+}{
+{{- range .Columns}}
+	{{.FieldName}}: {{printf "%q" .ColumnName}},
+{{- end}}
+}
+{{end}}`
+
+// writeColumnFiles 开启 ColumnConstants 时生成表名和列名常量：
+// SplitFiles 为 false 时写入 columns.go，为 true 时每个表写入 <表名>_columns.go
+func writeColumnFiles(config *Config, outputDir string, tables []*TableInfo) error {
+	if !config.ColumnConstants {
+		return nil
+	}
+	t, err := template.New("columns").Parse(columnsTemplate)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+	if !config.SplitFiles {
+		return renderColumnFile(filepath.Join(outputDir, "columns.go"), t, config.PackageName, tables)
+	}
+	for _, table := range tables {
+		filePath := filepath.Join(outputDir, fileBaseName(table.TableName)+"_columns.go")
+		if err := renderColumnFile(filePath, t, config.PackageName, []*TableInfo{table}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderColumnFile 执行列名模板并格式化后写入文件
+func renderColumnFile(filePath string, t *template.Template, packageName string, tables []*TableInfo) error {
+	var buf bytes.Buffer
+	err := t.Execute(&buf, struct {
+		PackageName  string
+		TableInfos   []*TableInfo
+		GenerateTime string
+	}{
+		PackageName:  packageName,
+		TableInfos:   tables,
+		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("格式化 %s 失败: %v", filePath, err)
+	}
+	if err := writeGenerated(filePath, src); err != nil {
+		return err
+	}
+	fmt.Printf("生成列名文件: %s\n", filePath)
+	return nil
+}
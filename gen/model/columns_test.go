@@ -0,0 +1,55 @@
+package model
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试生成表名和列名常量
+func TestSQLiteColumnConstants(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE user_accounts (id INTEGER PRIMARY KEY, user_name TEXT, created_at DATETIME)`); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	for _, split := range []bool{false, true} {
+		out := filepath.Join(dir, map[bool]string{false: "single", true: "split"}[split])
+		config := &Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: out, PackageName: "poes", SplitFiles: split, ColumnConstants: true}
+		if err := GenerateModels(config); err != nil {
+			t.Fatalf("生成模型失败: %v", err)
+		}
+		file := filepath.Join(out, "poes", "columns.go")
+		if split {
+			file = filepath.Join(out, "poes", "user_accounts_columns.go")
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("读取列名文件失败: %v", err)
+		}
+		code := string(data)
+		for _, want := range []string{
+			`const UserAccountsTable builder.Table = "user_accounts"`,
+			"var UserAccountsCols = struct {",
+			`UserName:  "user_name",`,
+			`CreatedAt: "created_at",`,
+		} {
+			if !strings.Contains(code, want) {
+				t.Errorf("split=%v: 列名文件缺少 %q:\n%s", split, want, code)
+			}
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), file, data, 0); err != nil {
+			t.Errorf("split=%v: 生成的代码无法解析: %v", split, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// EnumInfo 枚举列生成的字符串类型
+type EnumInfo struct {
+	TypeName string      // 类型名，如 OrdersStatus，PostgreSQL 枚举类型按类型名命名，如 OrderStatus
+	Source   string      // 来源，如 orders.status 或 PostgreSQL 的类型名
+	Values   []EnumValue // 取值，按数据库中的顺序
+}
+
+// EnumValue 枚举取值和对应的常量名
+type EnumValue struct {
+	ConstName string
+	Value     string
+}
+
+// enumsTemplate 枚举类型、常量和取值校验
+const enumsTemplate = `// 代码由 gosqlx 自动生成，请勿手动修改
+// 生成时间: {{.GenerateTime}}
+package {{.PackageName}}
+{{range .Enums}}
+// {{.TypeName}} {{.Source}} 的取值
+type {{.TypeName}} string
+
+const (
+{{- $type := .TypeName}}
+{{- range .Values}}
+	{{.ConstName}} {{$type}} = {{printf "%q" .Value}}
+{{- end}}
+)
+
+// {{.TypeName}}Values {{.Source}} 的全部取值
+var {{.TypeName}}Values = []{{.TypeName}}{ {{- range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.ConstName}}{{end -}} }
+
+// IsValid 取值是否在允许的集合内，实现 gosqlx.Enum，TagValidator 写入前据此校验
+func (v {{.TypeName}}) IsValid() bool {
+	switch v {
+	case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.ConstName}}{{end}}:
+		return true
+	}
+	return false
+}
+{{end}}`
+
+// applyEnums 开启 EnumTypes 时为枚举列确定类型名，将模型字段改为枚举类型，需要生成的类型记录在首个使用它的表中
+// PostgreSQL 的同一枚举类型在多个表中只生成一次；与模型或其他类型重名时追加 Enum 后缀
+func applyEnums(config *Config, tables []*TableInfo) {
+	if !config.EnumTypes {
+		return
+	}
+	used := make(map[string]bool)
+	for _, table := range tables {
+		used[table.ModelName] = true
+	}
+	named := make(map[string]string) // PostgreSQL 枚举类型名 -> Go 类型名
+	for _, table := range tables {
+		for n := range table.Columns {
+			column := &table.Columns[n]
+			if len(column.EnumValues) == 0 {
+				continue
+			}
+			typeName, ok := named[column.EnumType]
+			if !ok || column.EnumType == "" {
+				source := table.TableName + "." + column.ColumnName
+				typeName = table.ModelName + column.FieldName
+				if column.EnumType != "" {
+					source, typeName = column.EnumType, enumIdent(column.EnumType)
+				}
+				for used[typeName] {
+					typeName += "Enum"
+				}
+				used[typeName] = true
+				if column.EnumType != "" {
+					named[column.EnumType] = typeName
+				}
+				table.Enums = append(table.Enums, EnumInfo{TypeName: typeName, Source: source, Values: enumConsts(typeName, column.EnumValues)})
+			}
+			column.GoType = typeName
+			if column.IsNullable == "YES" {
+				column.GoType = "*" + typeName
+			}
+		}
+	}
+}
+
+// enumConsts 为取值生成常量名，如 OrdersStatusPaid；取值为空或以数字开头时加 V 前缀，重名时追加序号
+func enumConsts(typeName string, values []string) []EnumValue {
+	seen := make(map[string]bool, len(values))
+	result := make([]EnumValue, len(values))
+	for i, value := range values {
+		suffix := enumIdent(value)
+		if suffix == "" || unicode.IsDigit(rune(suffix[0])) {
+			suffix = "V" + suffix
+		}
+		name := typeName + suffix
+		for j := 2; seen[name]; j++ {
+			name = fmt.Sprintf("%s%s%d", typeName, suffix, j)
+		}
+		seen[name] = true
+		result[i] = EnumValue{ConstName: name, Value: value}
+	}
+	return result
+}
+
+// enumIdent 将取值转为导出标识符的驼峰形式，如 in_progress => InProgress，非字母数字的字符作为分隔
+func enumIdent(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) || r > unicode.MaxASCII {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// writeEnumFiles 开启 EnumTypes 时生成 applyEnums 记录的枚举类型：
+// SplitFiles 为 false 时写入 enums.go，为 true 时每个表写入 <表名>_enums.go；没有枚举列时不生成文件
+func writeEnumFiles(config *Config, outputDir string, tables []*TableInfo) error {
+	var all []EnumInfo
+	for _, table := range tables {
+		all = append(all, table.Enums...)
+	}
+	if !config.EnumTypes || len(all) == 0 {
+		return nil
+	}
+	t, err := template.New("enums").Parse(enumsTemplate)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+	if !config.SplitFiles {
+		return renderEnumFile(filepath.Join(outputDir, "enums.go"), t, config.PackageName, all)
+	}
+	for _, table := range tables {
+		if len(table.Enums) == 0 {
+			continue
+		}
+		filePath := filepath.Join(outputDir, fileBaseName(table.TableName)+"_enums.go")
+		if err := renderEnumFile(filePath, t, config.PackageName, table.Enums); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderEnumFile 执行枚举模板并格式化后写入文件
+func renderEnumFile(filePath string, t *template.Template, packageName string, enums []EnumInfo) error {
+	var buf bytes.Buffer
+	err := t.Execute(&buf, struct {
+		PackageName  string
+		Enums        []EnumInfo
+		GenerateTime string
+	}{
+		PackageName:  packageName,
+		Enums:        enums,
+		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("格式化 %s 失败: %v", filePath, err)
+	}
+	if err := writeGenerated(filePath, src); err != nil {
+		return err
+	}
+	fmt.Printf("生成枚举文件: %s\n", filePath)
+	return nil
+}
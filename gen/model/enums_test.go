@@ -0,0 +1,64 @@
+package model
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试由 CHECK (col IN (...)) 列生成枚举类型
+func TestSQLiteEnumTypes(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT NOT NULL CHECK (status IN ('pending', 'in_progress', 'done')), kind TEXT CHECK (kind IN ('a', '1')))`); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	out := filepath.Join(dir, "out")
+	config := &Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: out, PackageName: "poes", EnumTypes: true}
+	if err := GenerateModels(config); err != nil {
+		t.Fatalf("生成模型失败: %v", err)
+	}
+	file := filepath.Join(out, "poes", "enums.go")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("读取枚举文件失败: %v", err)
+	}
+	code := string(data)
+	for _, want := range []string{
+		"type OrdersStatus string",
+		`OrdersStatusInProgress OrdersStatus = "in_progress"`,
+		"var OrdersStatusValues = []OrdersStatus{OrdersStatusPending, OrdersStatusInProgress, OrdersStatusDone}",
+		`OrdersKindV1 OrdersKind = "1"`,
+		"func (v OrdersKind) IsValid() bool {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("枚举文件缺少 %q:\n%s", want, code)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), file, data, 0); err != nil {
+		t.Errorf("生成的代码无法解析: %v", err)
+	}
+
+	model, err := os.ReadFile(filepath.Join(out, "poes", "poes.go"))
+	if err != nil {
+		t.Fatalf("读取模型文件失败: %v", err)
+	}
+	if !strings.Contains(string(model), "OrdersStatus") || !strings.Contains(string(model), "*OrdersKind") {
+		t.Errorf("模型字段未使用枚举类型:\n%s", model)
+	}
+
+	consts := enumConsts("Role", []string{"a-b", "a_b", ""})
+	if consts[0].ConstName != "RoleAB" || consts[1].ConstName != "RoleAB2" || consts[2].ConstName != "RoleV" {
+		t.Errorf("常量名 = %+v", consts)
+	}
+}
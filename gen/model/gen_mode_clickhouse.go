@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "gorm.io/driver/clickhouse"
 )
 
@@ -71,13 +73,22 @@ func (g *ClickHouseGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -90,39 +101,15 @@ func (g *ClickHouseGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *ClickHouseGenerator) GetAllTables() ([]string, error) {
-	query := "SHOW TABLES"
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
 func (g *ClickHouseGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
-	// 获取表引擎和注释
-	var engine, tableComment string
-	query := `
-		SELECT 
-			engine,
-			comment
-		FROM system.tables
-		WHERE database = currentDatabase() AND name = ?
-	`
-	err := g.DB.QueryRow(query, tableName).Scan(&engine, &tableComment)
+	// 获取表注释
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
 	if err != nil {
-		return nil, fmt.Errorf("获取表信息失败: %v", err)
+		return nil, err
 	}
 
 	// 获取列信息
@@ -158,55 +145,15 @@ func (g *ClickHouseGenerator) GetTableInfo(tableName string) (*TableInfo, error)
 
 // GetColumnInfo 获取列信息
 func (g *ClickHouseGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	query := `
-		SELECT 
-			name, type, default_expression, 
-			comment, is_in_primary_key
-		FROM system.columns
-		WHERE database = currentDatabase() AND table = ?
-		ORDER BY position
-	`
-
-	rows, err := g.DB.Query(query, tableName)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询列信息失败: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var col ColumnInfo
-		var defaultExpr, comment sql.NullString
-		var isPrimaryKey bool
-
-		if err := rows.Scan(
-			&col.ColumnName, &col.DataType, &defaultExpr,
-			&comment, &isPrimaryKey,
-		); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
-
-		// 设置列类型和是否可为空
-		col.ColumnType = col.DataType
-		col.IsNullable = "YES" // ClickHouse中Nullable类型会在类型中显示
-		if !strings.Contains(col.DataType, "Nullable") {
-			col.IsNullable = "NO"
-		}
-
-		// 设置列注释
-		if comment.Valid {
-			col.ColumnComment = comment.String
-		}
-
-		// 设置列键
-		if isPrimaryKey {
-			col.ColumnKey = "PRI"
-		}
-
-		// 设置默认值
-		if defaultExpr.Valid {
-			col.Extra = fmt.Sprintf("DEFAULT %s", defaultExpr.String)
-		}
+	for _, dbColumn := range dbColumns {
+		// ClickHouse中Nullable类型会在类型中显示
+		col := newColumnInfo(dbColumn)
 
 		// 设置Go相关字段
 		col.FieldName = g.ToCamelCase(col.ColumnName)
@@ -230,13 +177,13 @@ func (g *ClickHouseGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, err
 		}
 
 		// 添加默认值
-		if defaultExpr.Valid {
-			gormTag += fmt.Sprintf("default:%s;", defaultExpr.String)
+		if dbColumn.Default.Valid {
+			gormTag += fmt.Sprintf("default:%s;", dbColumn.Default.String)
 		}
 
 		// 添加注释
-		if comment.Valid && comment.String != "" {
-			gormTag += fmt.Sprintf("comment:'%s';", strings.Replace(comment.String, "'", "\\'", -1))
+		if col.ColumnComment != "" {
+			gormTag += fmt.Sprintf("comment:'%s';", strings.Replace(col.ColumnComment, "'", "\\'", -1))
 		}
 
 		col.GormTag = gormTag
@@ -249,86 +196,12 @@ func (g *ClickHouseGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, err
 
 // GetPrimaryKeys 获取主键
 func (g *ClickHouseGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
-	query := `
-		SELECT name
-		FROM system.columns
-		WHERE database = currentDatabase() AND table = ? AND is_in_primary_key = 1
-		ORDER BY position
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询主键失败: %v", err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-		primaryKeys = append(primaryKeys, columnName)
-	}
-
-	return primaryKeys, nil
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
 }
 
 // GetIndexes 获取索引
 func (g *ClickHouseGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	// ClickHouse的索引查询
-	query := `
-		SELECT 
-			name as index_name,
-			column as column_name,
-			type as index_type,
-			0 as is_unique
-		FROM 
-			system.data_skipping_indices
-		WHERE 
-			database = currentDatabase() AND table = ?
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询索引失败: %v", err)
-	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-
-		}
-	}(rows)
-
-	indexMap := make(map[string]*IndexInfo)
-	for rows.Next() {
-		var indexName, columnName, indexType string
-		var isUnique int
-
-		if err := rows.Scan(&indexName, &columnName, &indexType, &isUnique); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 如果索引不存在，创建新索引
-		if _, ok := indexMap[indexName]; !ok {
-			indexMap[indexName] = &IndexInfo{
-				IndexName: indexName,
-				IndexType: indexType,
-				IsUnique:  isUnique == 1,
-			}
-		}
-
-		// 添加索引列
-		indexMap[indexName].ColumnNames = append(indexMap[indexName].ColumnNames, columnName)
-	}
-
-	// 转换为切片
-	var indexes []IndexInfo
-	for _, idx := range indexMap {
-		indexes = append(indexes, *idx)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // GenerateModelFile 生成模型文件
@@ -346,7 +219,7 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-    {{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
 {{- end}}
 }
 
@@ -358,42 +231,41 @@ func (m *{{.ModelName}}) TableName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName  string
-		TableInfos   []*TableInfo
-		GenerateTime string
-	}{
-		PackageName:  g.Config.PackageName,
-		TableInfos:   tableInfos,
-		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer func(file *os.File) {
-		_ = file.Close()
-	}(file)
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
 
 // MapClickHouseTypeToGo 将ClickHouse类型映射到Go类型
@@ -523,7 +395,7 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-    {{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
 {{- end}}
 }
 
@@ -573,23 +445,10 @@ func (m *{{.ModelName}}) TableName() string {
 		fileName := fmt.Sprintf("%s.go", strings.ToLower(tableInfo.ModelName))
 		filePath := filepath.Join(outputDir, fileName)
 
-		// 创建文件
-		file, err := os.Create(filePath)
-		if err != nil {
-			return fmt.Errorf("创建文件失败: %v", err)
-		}
-
-		// 执行模板
-		if err := t.Execute(file, data); err != nil {
-			_ = file.Close()
-			return fmt.Errorf("执行模板失败: %v", err)
-		}
-
-		err = file.Close()
-		if err != nil {
+		// 写入文件，保留 gosqlx:keep 区域
+		if err := renderModelFile(filePath, t, data); err != nil {
 			return err
 		}
-		fmt.Printf("生成模型文件: %s\n", filePath)
 	}
 
 	return nil
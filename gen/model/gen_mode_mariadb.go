@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -71,17 +73,29 @@ func (g *MariaDBGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 根据配置选择生成单个文件还是多个文件
-	if g.Config.SingleFile {
+	if g.Config.SingleFile || g.Config.SplitFiles {
 		// 生成单个模型文件
 		if err := g.GenerateModelFile(tableInfos, outputDir); err != nil {
 			return err
@@ -98,41 +112,15 @@ func (g *MariaDBGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *MariaDBGenerator) GetAllTables() ([]string, error) {
-	query := "SHOW TABLES"
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("遍历表名时发生错误: %v", err)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
 func (g *MariaDBGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	// 获取表注释
-	var tableComment string
-	query := `
-		SELECT table_comment 
-		FROM information_schema.tables 
-		WHERE table_schema = ? AND table_name = ?
-	`
-	err := g.DB.QueryRow(query, g.Config.DatabaseName, tableName).Scan(&tableComment)
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
 	if err != nil {
-		return nil, fmt.Errorf("获取表 %s 注释失败: %v", tableName, err)
+		return nil, err
 	}
 
 	// 获取列信息
@@ -153,6 +141,12 @@ func (g *MariaDBGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		return nil, err
 	}
 
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成模型名称（表名转为驼峰命名）
 	modelName := g.ToCamelCase(tableName)
 
@@ -162,36 +156,21 @@ func (g *MariaDBGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		Columns:      columns,
 		PrimaryKeys:  primaryKeys,
 		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
 		ModelName:    modelName,
 	}, nil
 }
 
 // GetColumnInfo 获取列信息
 func (g *MariaDBGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	query := `
-		SELECT 
-			column_name, data_type, column_type, 
-			is_nullable, column_key, column_comment, extra
-		FROM information_schema.columns
-		WHERE table_schema = ? AND table_name = ?
-		ORDER BY ordinal_position
-	`
-
-	rows, err := g.DB.Query(query, g.Config.DatabaseName, tableName)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询表 %s 列信息失败: %v", tableName, err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var col ColumnInfo
-		if err := rows.Scan(
-			&col.ColumnName, &col.DataType, &col.ColumnType,
-			&col.IsNullable, &col.ColumnKey, &col.ColumnComment, &col.Extra,
-		); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
 
 		// 设置Go相关字段
 		col.FieldName = g.ToCamelCase(col.ColumnName)
@@ -237,99 +216,22 @@ func (g *MariaDBGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error)
 		columns = append(columns, col)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("遍历列信息时发生错误: %v", err)
-	}
-
 	return columns, nil
 }
 
 // GetPrimaryKeys 获取主键
 func (g *MariaDBGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
-	query := `
-		SELECT column_name
-		FROM information_schema.key_column_usage
-		WHERE table_schema = ? AND table_name = ? AND constraint_name = 'PRIMARY'
-		ORDER BY ordinal_position
-	`
-
-	rows, err := g.DB.Query(query, g.Config.DatabaseName, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询表 %s 主键失败: %v", tableName, err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-		primaryKeys = append(primaryKeys, columnName)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("遍历主键时发生错误: %v", err)
-	}
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
 
-	return primaryKeys, nil
+// GetForeignKeys 获取外键
+func (g *MariaDBGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
 }
 
 // GetIndexes 获取索引
 func (g *MariaDBGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	query := `
-		SELECT 
-			index_name, non_unique, index_type, column_name
-		FROM information_schema.statistics
-		WHERE table_schema = ? AND table_name = ?
-		ORDER BY index_name, seq_in_index
-	`
-
-	rows, err := g.DB.Query(query, g.Config.DatabaseName, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询表 %s 索引失败: %v", tableName, err)
-	}
-	defer rows.Close()
-
-	indexMap := make(map[string]*IndexInfo)
-	for rows.Next() {
-		var indexName string
-		var nonUnique int
-		var indexType, columnName string
-
-		if err := rows.Scan(&indexName, &nonUnique, &indexType, &columnName); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 跳过主键索引，因为已经单独处理
-		if indexName == "PRIMARY" {
-			continue
-		}
-
-		// 如果索引不存在，创建新索引
-		if _, ok := indexMap[indexName]; !ok {
-			indexMap[indexName] = &IndexInfo{
-				IndexName: indexName,
-				IndexType: indexType,
-				IsUnique:  nonUnique == 0,
-			}
-		}
-
-		// 添加索引列
-		indexMap[indexName].ColumnNames = append(indexMap[indexName].ColumnNames, columnName)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("遍历索引时发生错误: %v", err)
-	}
-
-	// 转换为切片
-	var indexes []IndexInfo
-	for _, idx := range indexMap {
-		indexes = append(indexes, *idx)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // GenerateModelFile 生成模型文件
@@ -348,7 +250,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-    {{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -374,42 +279,43 @@ func (m *{{.ModelName}}) TableName() string {
 		}
 	}
 
-	// 准备模板数据
-	data := struct {
-		PackageName    string
-		TableInfos     []*TableInfo
-		GenerateTime   string
-		NeedJsonImport bool
-	}{
-		PackageName:    g.Config.PackageName,
-		TableInfos:     tableInfos,
-		GenerateTime:   time.Now().Format("2006-01-02 15:04:05"),
-		NeedJsonImport: needJsonImport,
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName    string
+				TableInfos     []*TableInfo
+				GenerateTime   string
+				NeedJsonImport bool
+			}{
+				PackageName:    g.Config.PackageName,
+				TableInfos:     items,
+				GenerateTime:   time.Now().Format("2006-01-02 15:04:05"),
+				NeedJsonImport: needJsonImport,
+			}
+		})
 }
 
 // MapMariaDBTypeToGo 将MariaDB类型映射到Go类型
@@ -522,7 +428,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-    {{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -556,6 +465,7 @@ func (m *{{.ModelName}}) TableName() string {
 			TableName      string
 			TableComment   string
 			Columns        []ColumnInfo
+			Relations      []RelationInfo
 			GenerateTime   string
 			NeedJsonImport bool
 		}{
@@ -564,6 +474,7 @@ func (m *{{.ModelName}}) TableName() string {
 			TableName:      tableInfo.TableName,
 			TableComment:   tableInfo.TableComment,
 			Columns:        tableInfo.Columns,
+			Relations:      tableInfo.Relations,
 			GenerateTime:   time.Now().Format("2006-01-02 15:04:05"),
 			NeedJsonImport: needJsonImport,
 		}
@@ -572,20 +483,10 @@ func (m *{{.ModelName}}) TableName() string {
 		fileName := fmt.Sprintf("%s.go", strings.ToLower(tableInfo.ModelName))
 		filePath := filepath.Join(outputDir, fileName)
 
-		// 创建文件
-		file, err := os.Create(filePath)
-		if err != nil {
-			return fmt.Errorf("创建文件失败: %v", err)
-		}
-
-		// 执行模板
-		if err := t.Execute(file, data); err != nil {
-			_ = file.Close()
-			return fmt.Errorf("执行模板失败: %v", err)
+		// 写入文件，保留 gosqlx:keep 区域
+		if err := renderModelFile(filePath, t, data); err != nil {
+			return err
 		}
-
-		_ = file.Close()
-		fmt.Printf("生成模型文件: %s\n", filePath)
 	}
 
 	return nil
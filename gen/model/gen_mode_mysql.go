@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -20,23 +22,30 @@ type TableInfo struct {
 	PrimaryKeys  []string     // 主键
 	Indexes      []IndexInfo  // 索引
 	ModelName    string       // 模型名称（驼峰命名）
+
+	ForeignKeys []ForeignKeyInfo // 外键
+	Relations   []RelationInfo   // 由外键推断的关联
+	Enums       []EnumInfo       // 开启 EnumTypes 时由该表的枚举列生成的类型
 }
 
 // ColumnInfo 列信息
 type ColumnInfo struct {
-	ColumnName    string // 列名
-	DataType      string // 数据类型
-	ColumnType    string // 列类型（包含长度等信息）
-	IsNullable    string // 是否可为空
-	ColumnKey     string // 键类型（PRI/UNI/MUL）
-	ColumnComment string // 列注释
-	Extra         string // 额外信息（如auto_increment）
+	ColumnName    string   // 列名
+	DataType      string   // 数据类型
+	ColumnType    string   // 列类型（包含长度等信息）
+	IsNullable    string   // 是否可为空
+	ColumnKey     string   // 键类型（PRI/UNI/MUL）
+	ColumnComment string   // 列注释
+	Extra         string   // 额外信息（如auto_increment）
+	EnumValues    []string // 枚举列的取值
+	EnumType      string   // 枚举列的数据库类型名（PostgreSQL），其他数据库为空
 
 	// 生成Go结构体时使用
 	FieldName string // 字段名（驼峰命名）
 	GoType    string // Go类型
 	JsonTag   string // JSON标签
 	GormTag   string // GORM标签
+	Tags      string // 按 TagStyles 生成的完整结构体标签
 }
 
 // IndexInfo 索引信息
@@ -60,6 +69,31 @@ type Config struct {
 	// 添加这个字段
 	FirstLetterUpper bool // 是否将首字母大写
 	SingleFile       bool //
+	// 每个表生成 <表名>_gen.go，并创建不会被覆盖的 <表名>.go 用于手写代码；
+	// 无论是否拆分，生成文件中 // gosqlx:keep 名称 与 // gosqlx:keep end 之间的代码在重新生成时都会保留
+	SplitFiles bool
+	// 同时生成表名和列名常量（columns.go，拆分文件时为 <表名>_columns.go），如 UsersTable、UsersCols.Username，
+	// 供查询构建器的 From、SelectCols、WhereCol 使用，列被删除或改名后引用处编译报错；MongoDB 不生成
+	ColumnConstants bool
+	// 为枚举列（MySQL 系的 ENUM、PostgreSQL 枚举类型和 SQLite 的 CHECK (列 IN (...)) 约束）生成字符串类型和常量
+	// （enums.go，拆分文件时为 <表名>_enums.go），模型字段使用该类型，配合 gosqlx.TagValidator 在写入前校验取值
+	EnumTypes bool
+	// 可为 NULL 的列的字段类型：pointer（默认，如 *string）、sql（sql.NullString 等）或 generic（gosqlx.Null[string]）
+	NullStyle string
+	// 为匹配的表（支持 * ? [] 通配符）生成 CRUD HTTP 处理器（handlers.go，拆分文件时为 <表名>_handler.go），
+	// 列表接口的分页、过滤和排序由查询构建器完成；只支持单列主键的表，MongoDB 不生成
+	HTTPHandlers []string
+
+	// 结构体标签，格式为 标签[:命名策略]，支持 db/gorm/json/xml/bson/protobuf，
+	// 命名策略为 snake/camel/preserve，默认 json 和 gorm
+	TagStyles []string
+
+	// 大库生成选项
+	Concurrency   int            // 并发获取表结构的数量，默认 4
+	IncludeTables []string       // 只生成匹配的表，支持 * ? [] 通配符
+	ExcludeTables []string       // 排除匹配的表
+	ResumeFile    string         // 断点文件，中断后重跑会跳过已获取的表，全部完成后自动删除
+	Progress      func(Progress) // 进度回调
 }
 
 // MySQLGenerator MySQL表结构生成器
@@ -117,13 +151,25 @@ func (g *MySQLGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -136,37 +182,15 @@ func (g *MySQLGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *MySQLGenerator) GetAllTables() ([]string, error) {
-	query := "SHOW TABLES"
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
 func (g *MySQLGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	// 获取表注释
-	var tableComment string
-	query := `
-		SELECT table_comment 
-		FROM information_schema.tables 
-		WHERE table_schema = ? AND table_name = ?
-	`
-	err := g.DB.QueryRow(query, g.Config.DatabaseName, tableName).Scan(&tableComment)
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
 	if err != nil {
-		return nil, fmt.Errorf("获取表注释失败: %v", err)
+		return nil, err
 	}
 
 	// 获取列信息
@@ -187,6 +211,12 @@ func (g *MySQLGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		return nil, err
 	}
 
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成模型名称（表名转为驼峰命名）
 	modelName := g.ToCamelCase(tableName)
 
@@ -196,36 +226,21 @@ func (g *MySQLGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		Columns:      columns,
 		PrimaryKeys:  primaryKeys,
 		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
 		ModelName:    modelName,
 	}, nil
 }
 
 // GetColumnInfo 获取列信息
 func (g *MySQLGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	query := `
-		SELECT 
-			column_name, data_type, column_type, 
-			is_nullable, column_key, column_comment, extra
-		FROM information_schema.columns
-		WHERE table_schema = ? AND table_name = ?
-		ORDER BY ordinal_position
-	`
-
-	rows, err := g.DB.Query(query, g.Config.DatabaseName, tableName)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询列信息失败: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var col ColumnInfo
-		if err := rows.Scan(
-			&col.ColumnName, &col.DataType, &col.ColumnType,
-			&col.IsNullable, &col.ColumnKey, &col.ColumnComment, &col.Extra,
-		); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
 
 		// 设置Go相关字段
 		col.FieldName = g.ToCamelCase(col.ColumnName)
@@ -297,82 +312,17 @@ func (g *MySQLGenerator) ExtractDefaultValue(extra string) string {
 
 // GetPrimaryKeys 获取主键
 func (g *MySQLGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
-	query := `
-		SELECT column_name
-		FROM information_schema.key_column_usage
-		WHERE table_schema = ? AND table_name = ? AND constraint_name = 'PRIMARY'
-		ORDER BY ordinal_position
-	`
-
-	rows, err := g.DB.Query(query, g.Config.DatabaseName, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询主键失败: %v", err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-		primaryKeys = append(primaryKeys, columnName)
-	}
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
 
-	return primaryKeys, nil
+// GetForeignKeys 获取外键
+func (g *MySQLGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
 }
 
 // GetIndexes 获取索引
 func (g *MySQLGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	query := `
-		SELECT 
-			index_name, non_unique, index_type, column_name
-		FROM information_schema.statistics
-		WHERE table_schema = ? AND table_name = ?
-		ORDER BY index_name, seq_in_index
-	`
-
-	rows, err := g.DB.Query(query, g.Config.DatabaseName, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询索引失败: %v", err)
-	}
-	defer rows.Close()
-
-	indexMap := make(map[string]*IndexInfo)
-	for rows.Next() {
-		var indexName string
-		var nonUnique int
-		var indexType, columnName string
-
-		if err := rows.Scan(&indexName, &nonUnique, &indexType, &columnName); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 跳过主键索引，因为已经单独处理
-		if indexName == "PRIMARY" {
-			continue
-		}
-
-		// 如果索引不存在，创建新索引
-		if _, ok := indexMap[indexName]; !ok {
-			indexMap[indexName] = &IndexInfo{
-				IndexName: indexName,
-				IndexType: indexType,
-				IsUnique:  nonUnique == 0,
-			}
-		}
-
-		// 添加索引列
-		indexMap[indexName].ColumnNames = append(indexMap[indexName].ColumnNames, columnName)
-	}
-
-	// 转换为切片
-	var indexes []IndexInfo
-	for _, idx := range indexMap {
-		indexes = append(indexes, *idx)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // MapMySQLTypeToGo 将MySQL类型映射到Go类型
@@ -448,7 +398,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-    {{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+    {{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -460,38 +413,39 @@ func (m *{{.ModelName}}) TableName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName  string
-		TableInfos   []*TableInfo
-		GenerateTime string
-	}{
-		PackageName:  g.Config.PackageName,
-		TableInfos:   tableInfos,
-		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
@@ -1,6 +1,14 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gzorm/gosqlx/gen/internal/walk"
+	"github.com/gzorm/gosqlx/introspect"
+)
 
 // Generator 表结构生成器接口
 type Generator interface {
@@ -34,6 +42,8 @@ func GenerateModels(config *Config) error {
 		generator, err = NewClickHouseGenerator(config)
 	case "oceanbase":
 		generator, err = NewOceanBaseGenerator(config)
+	case "duckdb":
+		generator, err = NewDuckDBGenerator(config)
 	default:
 		return fmt.Errorf("不支持的数据库类型: %s", config.DBType)
 	}
@@ -45,3 +55,250 @@ func GenerateModels(config *Config) error {
 
 	return generator.Generate()
 }
+
+// Progress 生成进度，每获取完一个表回调一次
+type Progress = walk.Progress
+
+// collectTables 按配置过滤表名，并发获取表信息，结果顺序与表名一致
+func collectTables[T any](config *Config, names []string, fetch func(string) (T, error)) ([]T, error) {
+	return walk.Run(names, walk.Options{
+		Concurrency: config.Concurrency,
+		Include:     config.IncludeTables,
+		Exclude:     config.ExcludeTables,
+		ResumeFile:  config.ResumeFile,
+		Progress:    config.Progress,
+	}, fetch)
+}
+
+// ForeignKeyInfo 外键信息（每列一条，复合外键按约束名分组）
+type ForeignKeyInfo struct {
+	ConstraintName string // 约束名
+	ColumnName     string // 外键列
+	RefTable       string // 引用表
+	RefColumn      string // 引用列
+}
+
+// RelationInfo 由外键推断的模型关联
+type RelationInfo struct {
+	Kind      string // 关联类型（belongs_to/has_many）
+	FieldName string // 字段名
+	GoType    string // Go类型，belongs_to 为 *Model，has_many 为 []Model
+	JsonTag   string // JSON标签
+	GormTag   string // GORM标签，声明 foreignKey 和 references
+	Tags      string // 按 TagStyles 生成的完整结构体标签
+}
+
+// 关联类型
+const (
+	RelationBelongsTo = "belongs_to"
+	RelationHasMany   = "has_many"
+)
+
+// getTableComment 获取表注释
+func getTableComment(in *introspect.Inspector, tableName string) (string, error) {
+	table, err := in.Table(tableName)
+	if err != nil {
+		return "", err
+	}
+	return table.Comment, nil
+}
+
+// tableNames 获取所有表名
+func tableNames(in *introspect.Inspector) ([]string, error) {
+	tables, err := in.Tables()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = table.Name
+	}
+	return names, nil
+}
+
+// newColumnInfo 由 introspect 的列信息填充 ColumnInfo 的数据库字段
+// Extra 优先使用数据库原始附加信息，否则为 DEFAULT 默认值或 auto_increment
+func newColumnInfo(column introspect.Column) ColumnInfo {
+	col := ColumnInfo{
+		ColumnName:    column.Name,
+		DataType:      column.DataType,
+		ColumnType:    column.ColumnType,
+		IsNullable:    "NO",
+		ColumnComment: column.Comment,
+		Extra:         column.Extra,
+		EnumValues:    column.EnumValues,
+	}
+	if column.DataType == "USER-DEFINED" && len(column.EnumValues) > 0 {
+		col.EnumType = column.UDTName
+	}
+	if column.Nullable {
+		col.IsNullable = "YES"
+	}
+	if column.PrimaryKey {
+		col.ColumnKey = "PRI"
+	}
+	if col.Extra == "" {
+		if column.Default.Valid {
+			col.Extra = "DEFAULT " + column.Default.String
+		} else if column.AutoIncrement {
+			col.Extra = "auto_increment"
+		}
+	}
+	return col
+}
+
+// indexInfos 转换索引信息，主键索引单独处理，不包含在内
+func indexInfos(indexes []introspect.Index, err error) ([]IndexInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	var infos []IndexInfo
+	for _, index := range indexes {
+		if index.Primary {
+			continue
+		}
+		infos = append(infos, IndexInfo{
+			IndexName:   index.Name,
+			IndexType:   index.Type,
+			IsUnique:    index.Unique,
+			ColumnNames: index.Columns,
+		})
+	}
+	return infos, nil
+}
+
+// foreignKeyInfos 将外键展开为每列一条
+func foreignKeyInfos(foreignKeys []introspect.ForeignKey, err error) ([]ForeignKeyInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	var infos []ForeignKeyInfo
+	for _, fk := range foreignKeys {
+		for i, column := range fk.Columns {
+			infos = append(infos, ForeignKeyInfo{
+				ConstraintName: fk.Name,
+				ColumnName:     column,
+				RefTable:       fk.RefTable,
+				RefColumn:      fk.RefColumns[i],
+			})
+		}
+	}
+	return infos, nil
+}
+
+// inferRelations 根据外键为模型生成关联字段: 外键所在表生成 belongs_to，被引用表生成 has_many
+// 复合外键和引用表不在本次生成范围内的外键会被忽略，生成的字段可直接用于 Preload
+func inferRelations(tables []*TableInfo) {
+	byName := make(map[string]*TableInfo, len(tables))
+	used := make(map[*TableInfo]map[string]bool, len(tables))
+	for _, table := range tables {
+		byName[strings.ToLower(table.TableName)] = table
+		used[table] = make(map[string]bool)
+		for _, column := range table.Columns {
+			used[table][column.FieldName] = true
+		}
+	}
+	// uniqueName 同一模型中字段重名时追加后缀
+	uniqueName := func(table *TableInfo, name, suffix string) string {
+		if used[table][name] || name == "" {
+			name += suffix
+		}
+		for i := 2; used[table][name]; i++ {
+			name = fmt.Sprintf("%s%s%d", name, suffix, i)
+		}
+		used[table][name] = true
+		return name
+	}
+
+	for _, table := range tables {
+		constraints := make(map[string][]ForeignKeyInfo)
+		var order []string
+		for _, fk := range table.ForeignKeys {
+			if _, ok := constraints[fk.ConstraintName]; !ok {
+				order = append(order, fk.ConstraintName)
+			}
+			constraints[fk.ConstraintName] = append(constraints[fk.ConstraintName], fk)
+		}
+
+		// 按外键列在表中的顺序生成，结果不受数据库返回顺序影响
+		sort.SliceStable(order, func(i, j int) bool {
+			return columnIndex(table, constraints[order[i]][0].ColumnName) < columnIndex(table, constraints[order[j]][0].ColumnName)
+		})
+
+		for _, name := range order {
+			fks := constraints[name]
+			ref, ok := byName[strings.ToLower(fks[0].RefTable)]
+			if len(fks) != 1 || !ok {
+				continue
+			}
+			// 未声明引用列时引用主键（SQLite）
+			refName := fks[0].RefColumn
+			if refName == "" && len(ref.PrimaryKeys) == 1 {
+				refName = ref.PrimaryKeys[0]
+			}
+			column, refColumn := findColumn(table, fks[0].ColumnName), findColumn(ref, refName)
+			if column == nil || refColumn == nil {
+				continue
+			}
+			tag := fmt.Sprintf("foreignKey:%s;references:%s", column.FieldName, refColumn.FieldName)
+
+			// user_id => User，不以 _id 结尾时使用引用模型名
+			belongsTo := ref.ModelName
+			if base := strings.TrimSuffix(strings.ToLower(column.ColumnName), "_id"); base != strings.ToLower(column.ColumnName) && base != "" {
+				belongsTo = strings.TrimSuffix(column.FieldName, "Id")
+			}
+			belongsTo = uniqueName(table, belongsTo, "Ref")
+			table.Relations = append(table.Relations, RelationInfo{
+				Kind:      RelationBelongsTo,
+				FieldName: belongsTo,
+				GoType:    "*" + ref.ModelName,
+				JsonTag:   toSnake(belongsTo),
+				GormTag:   tag,
+			})
+
+			hasMany := uniqueName(ref, table.ModelName, "By"+belongsTo)
+			ref.Relations = append(ref.Relations, RelationInfo{
+				Kind:      RelationHasMany,
+				FieldName: hasMany,
+				GoType:    "[]" + table.ModelName,
+				JsonTag:   toSnake(hasMany),
+				GormTag:   tag,
+			})
+		}
+	}
+}
+
+// findColumn 按列名查找（忽略大小写）
+func findColumn(table *TableInfo, name string) *ColumnInfo {
+	if i := columnIndex(table, name); i >= 0 {
+		return &table.Columns[i]
+	}
+	return nil
+}
+
+// columnIndex 返回列的位置，不存在时返回-1
+func columnIndex(table *TableInfo, name string) int {
+	for i := range table.Columns {
+		if strings.EqualFold(table.Columns[i].ColumnName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// toSnake 驼峰转下划线，连续大写视为一个单词，如 UserID => user_id
+func toSnake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' && (!unicode.IsUpper(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,329 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gzorm/gosqlx/introspect"
+)
+
+// DuckDBGenerator DuckDB表结构生成器
+// gosqlx 不直接依赖 DuckDB 驱动（需要 CGO），使用前需导入驱动: import _ "github.com/duckdb/duckdb-go/v2"
+type DuckDBGenerator struct {
+	Config *Config
+	DB     *sql.DB
+}
+
+// NewDuckDBGenerator 创建DuckDB表结构生成器，DatabaseName 为数据库文件路径
+func NewDuckDBGenerator(config *Config) (*DuckDBGenerator, error) {
+	if config.DBType != "duckdb" {
+		return nil, fmt.Errorf("不支持的数据库类型: %s", config.DBType)
+	}
+
+	db, err := sql.Open("duckdb", config.DatabaseName)
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %v", err)
+	}
+
+	// 测试连接
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("测试数据库连接失败: %v", err)
+	}
+
+	return &DuckDBGenerator{
+		Config: config,
+		DB:     db,
+	}, nil
+}
+
+// Close 关闭数据库连接
+func (g *DuckDBGenerator) Close() error {
+	if g.DB != nil {
+		return g.DB.Close()
+	}
+	return nil
+}
+
+// Generate 生成所有表的模型
+func (g *DuckDBGenerator) Generate() error {
+	// 获取所有表名
+	tables, err := g.GetAllTables()
+	if err != nil {
+		return err
+	}
+
+	// 确保输出目录存在
+	outputDir := filepath.Join(g.Config.OutputDir, "poes")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %v", err)
+	}
+
+	// 收集所有表信息
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	return g.GenerateModelFile(tableInfos, outputDir)
+}
+
+// GetAllTables 获取所有表名
+func (g *DuckDBGenerator) GetAllTables() ([]string, error) {
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
+}
+
+// GetTableInfo 获取表信息
+func (g *DuckDBGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取列信息
+	columns, err := g.GetColumnInfo(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取主键
+	primaryKeys, err := g.GetPrimaryKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取索引
+	indexes, err := g.GetIndexes(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TableInfo{
+		TableName:    tableName,
+		TableComment: tableComment,
+		Columns:      columns,
+		PrimaryKeys:  primaryKeys,
+		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
+		ModelName:    g.ToCamelCase(tableName),
+	}, nil
+}
+
+// GetColumnInfo 获取列信息
+func (g *DuckDBGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []ColumnInfo
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
+		col.FieldName = g.ToCamelCase(col.ColumnName)
+		col.GoType = g.MapDuckDBTypeToGo(col.DataType, dbColumn.Nullable)
+		col.JsonTag = col.ColumnName
+
+		// 生成GORM标签
+		gormTag := fmt.Sprintf("column:%s;type:%s;", col.ColumnName, col.DataType)
+		if !dbColumn.Nullable {
+			gormTag += "not null;"
+		}
+		if dbColumn.PrimaryKey {
+			gormTag += "primaryKey;"
+		}
+		// DuckDB 使用序列实现自增
+		if dbColumn.AutoIncrement {
+			gormTag += "autoIncrement;"
+		} else if dbColumn.Default.Valid {
+			gormTag += fmt.Sprintf("default:%s;", dbColumn.Default.String)
+		}
+		if col.ColumnComment != "" {
+			gormTag += fmt.Sprintf("comment:'%s';", strings.ReplaceAll(col.ColumnComment, "'", "\\'"))
+		}
+		col.GormTag = gormTag
+
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// GetPrimaryKeys 获取主键
+func (g *DuckDBGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
+
+// GetForeignKeys 获取外键
+func (g *DuckDBGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
+}
+
+// GetIndexes 获取索引
+func (g *DuckDBGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
+}
+
+// MapDuckDBTypeToGo 将DuckDB类型映射到Go类型
+func (g *DuckDBGenerator) MapDuckDBTypeToGo(duckdbType string, isNullable bool) string {
+	duckdbType = strings.ToUpper(duckdbType)
+
+	// 列表、结构体、映射等嵌套类型
+	if strings.HasSuffix(duckdbType, "[]") || strings.HasPrefix(duckdbType, "STRUCT") || strings.HasPrefix(duckdbType, "MAP") {
+		return "json.RawMessage"
+	}
+
+	// 提取基本类型（去除精度等信息）
+	baseType := duckdbType
+	if idx := strings.Index(baseType, "("); idx > 0 {
+		baseType = baseType[:idx]
+	}
+
+	goType := ""
+	switch baseType {
+	case "TINYINT":
+		goType = "int8"
+	case "SMALLINT":
+		goType = "int16"
+	case "INTEGER":
+		goType = "int32"
+	case "BIGINT":
+		goType = "int64"
+	case "UTINYINT":
+		goType = "uint8"
+	case "USMALLINT":
+		goType = "uint16"
+	case "UINTEGER":
+		goType = "uint32"
+	case "UBIGINT":
+		goType = "uint64"
+	case "HUGEINT", "UHUGEINT", "DECIMAL":
+		goType = "string" // 超出 int64/float64 精度
+	case "FLOAT":
+		goType = "float32"
+	case "DOUBLE":
+		goType = "float64"
+	case "BOOLEAN":
+		goType = "bool"
+	case "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP_S", "TIMESTAMP_MS", "TIMESTAMP_NS":
+		goType = "time.Time"
+	case "BLOB":
+		return "[]byte"
+	case "JSON":
+		return "json.RawMessage"
+	default:
+		// VARCHAR、UUID、TIME、INTERVAL 等使用字符串
+		goType = "string"
+	}
+	if isNullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+// ToCamelCase 转换为驼峰命名
+func (g *DuckDBGenerator) ToCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := range parts {
+		if len(parts[i]) > 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// GenerateModelFile 生成模型文件
+func (g *DuckDBGenerator) GenerateModelFile(tableInfos []*TableInfo, outputDir string) error {
+	// 模板定义
+	tmpl := `// 代码由 gosqlx 自动生成，请勿手动修改
+// 生成时间: {{.GenerateTime}}
+package {{.PackageName}}
+
+import (
+	"encoding/json"
+	"time"
+)
+
+var _ = json.RawMessage{}
+var _ = time.Time{}
+
+{{range .TableInfos}}
+// {{.ModelName}} {{.TableComment}}
+type {{.ModelName}} struct {
+{{- range .Columns}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
+{{- end}}
+}
+
+// TableName 表名
+func (m *{{.ModelName}}) TableName() string {
+	return "{{.TableName}}"
+}
+
+{{end}}
+`
+
+	// 解析模板
+	t, err := template.New("model").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
+
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
+
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
+
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
+}
@@ -28,6 +28,7 @@ type MongoDBField struct {
 	JsonTag   string // JSON标签
 	GormTag   string // GORM标签
 	OmitEmpty bool   // 是否忽略空值
+	Tags      string // 按 TagStyles 生成的完整结构体标签
 }
 
 // MongoDBCollectionInfo MongoDB集合信息
@@ -107,13 +108,14 @@ func (g *MongoDBGenerator) Generate() error {
 	}
 
 	// 收集所有集合信息
-	var collectionInfos []*MongoDBCollectionInfo
-	for _, collectionName := range collections {
-		collectionInfo, err := g.GetCollectionInfo(collectionName)
-		if err != nil {
-			return err
-		}
-		collectionInfos = append(collectionInfos, collectionInfo)
+	collectionInfos, err := collectTables(g.Config, collections, g.GetCollectionInfo)
+	if err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := g.applyTags(collectionInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -381,6 +383,32 @@ func (g *MongoDBGenerator) ToCamelCase(s string) string {
 	return strings.Join(parts, "")
 }
 
+// applyTags 按配置生成字段标签，未配置 TagStyles 时输出 bson、json 和 gorm 标签
+func (g *MongoDBGenerator) applyTags(collectionInfos []*MongoDBCollectionInfo) error {
+	tagStyles := g.Config.TagStyles
+	if len(tagStyles) == 0 {
+		tagStyles = []string{"bson", "json", "gorm"}
+	}
+	styles, err := parseTagStyles(tagStyles)
+	if err != nil {
+		return err
+	}
+	for _, info := range collectionInfos {
+		for i := range info.Fields {
+			field := &info.Fields[i]
+			field.Tags = renderTags(styles, tagField{
+				Name:      field.BsonTag,
+				Preserve:  map[string]string{"json": field.JsonTag},
+				GoType:    field.GoType,
+				Number:    i + 1,
+				GormTag:   field.GormTag,
+				OmitEmpty: field.OmitEmpty,
+			})
+		}
+	}
+	return nil
+}
+
 // GenerateModelFile 生成模型文件
 func (g *MongoDBGenerator) GenerateModelFile(collectionInfos []*MongoDBCollectionInfo, outputDir string) error {
 	// 模板定义
@@ -397,7 +425,7 @@ import (
 // {{.ModelName}} {{.CollectionName}}集合模型
 type {{.ModelName}} struct {
 {{- range .Fields}}
-	{{.FieldName}} {{.GoType}} ` + "`bson:\"{{.BsonTag}},omitempty\" json:\"{{.JsonTag}},omitempty\" gorm:\"{{.GormTag}}\"`" + `
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -409,38 +437,24 @@ func (m *{{.ModelName}}) CollectionName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName     string
-		CollectionInfos []*MongoDBCollectionInfo
-		GenerateTime    string
-	}{
-		PackageName:     g.Config.PackageName,
-		CollectionInfos: collectionInfos,
-		GenerateTime:    time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
-
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
-	}
-	defer file.Close()
-
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
-	}
-
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, collectionInfos,
+		func(collection *MongoDBCollectionInfo) string { return collection.CollectionName },
+		func(items []*MongoDBCollectionInfo) interface{} {
+			return struct {
+				PackageName     string
+				CollectionInfos []*MongoDBCollectionInfo
+				GenerateTime    string
+			}{
+				PackageName:     g.Config.PackageName,
+				CollectionInfos: items,
+				GenerateTime:    time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "github.com/seelly/gorm-oracle"
 )
 
@@ -68,13 +70,25 @@ func (g *OracleGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -87,47 +101,15 @@ func (g *OracleGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *OracleGenerator) GetAllTables() ([]string, error) {
-	// 查询用户表（排除系统表）
-	query := `
-		SELECT TABLE_NAME 
-		FROM USER_TABLES 
-		ORDER BY TABLE_NAME
-	`
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
 func (g *OracleGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	// 获取表注释
-	var tableComment string
-	query := `
-		SELECT COMMENTS 
-		FROM USER_TAB_COMMENTS 
-		WHERE TABLE_NAME = :1
-	`
-	err := g.DB.QueryRow(query, tableName).Scan(&tableComment)
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
 	if err != nil {
-		// 如果没有注释，不返回错误，而是使用空字符串
-		if err == sql.ErrNoRows {
-			tableComment = ""
-		} else {
-			return nil, fmt.Errorf("获取表注释失败: %v", err)
-		}
+		return nil, err
 	}
 
 	// 获取列信息
@@ -148,6 +130,12 @@ func (g *OracleGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		return nil, err
 	}
 
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成模型名称（表名转为驼峰命名）
 	modelName := g.ToCamelCase(strings.ToLower(tableName))
 
@@ -157,78 +145,21 @@ func (g *OracleGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		Columns:      columns,
 		PrimaryKeys:  primaryKeys,
 		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
 		ModelName:    modelName,
 	}, nil
 }
 
 // GetColumnInfo 获取列信息
 func (g *OracleGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	// 查询列信息
-	query := `
-		SELECT 
-			c.COLUMN_NAME,
-			c.DATA_TYPE,
-			CASE 
-				WHEN c.DATA_TYPE LIKE '%CHAR%' THEN c.DATA_TYPE || '(' || c.CHAR_LENGTH || ')'
-				WHEN c.DATA_TYPE = 'NUMBER' AND c.DATA_PRECISION IS NOT NULL AND c.DATA_SCALE IS NOT NULL THEN 
-					c.DATA_TYPE || '(' || c.DATA_PRECISION || ',' || c.DATA_SCALE || ')'
-				WHEN c.DATA_TYPE = 'NUMBER' AND c.DATA_PRECISION IS NOT NULL THEN 
-					c.DATA_TYPE || '(' || c.DATA_PRECISION || ')'
-				ELSE c.DATA_TYPE
-			END AS COLUMN_TYPE,
-			c.NULLABLE,
-			CASE WHEN p.COLUMN_NAME IS NOT NULL THEN 'PRI' ELSE '' END AS COLUMN_KEY,
-			NVL(cc.COMMENTS, '') AS COLUMN_COMMENT,
-			CASE WHEN c.DATA_DEFAULT IS NOT NULL THEN 'DEFAULT ' || c.DATA_DEFAULT ELSE '' END AS EXTRA
-		FROM 
-			USER_TAB_COLUMNS c
-		LEFT JOIN (
-			SELECT 
-				cols.COLUMN_NAME
-			FROM 
-				USER_CONSTRAINTS cons, 
-				USER_CONS_COLUMNS cols
-			WHERE 
-				cons.CONSTRAINT_TYPE = 'P'
-				AND cons.CONSTRAINT_NAME = cols.CONSTRAINT_NAME
-				AND cons.TABLE_NAME = :1
-		) p ON c.COLUMN_NAME = p.COLUMN_NAME
-		LEFT JOIN 
-			USER_COL_COMMENTS cc ON c.TABLE_NAME = cc.TABLE_NAME AND c.COLUMN_NAME = cc.COLUMN_NAME
-		WHERE 
-			c.TABLE_NAME = :1
-		ORDER BY 
-			c.COLUMN_ID
-	`
-
-	rows, err := g.DB.Query(query, tableName)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询列信息失败: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var col ColumnInfo
-		var nullable string
-
-		if err := rows.Scan(
-			&col.ColumnName,
-			&col.DataType,
-			&col.ColumnType,
-			&nullable,
-			&col.ColumnKey,
-			&col.ColumnComment,
-			&col.Extra,
-		); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
-
-		// 设置是否可为空
-		col.IsNullable = "NO"
-		if nullable == "Y" {
-			col.IsNullable = "YES"
-		}
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
 
 		// 设置Go相关字段
 		col.FieldName = g.ToCamelCase(strings.ToLower(col.ColumnName))
@@ -300,95 +231,17 @@ func (g *OracleGenerator) ExtractDefaultValue(extra string) string {
 
 // GetPrimaryKeys 获取主键
 func (g *OracleGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
-	query := `
-		SELECT 
-			cols.COLUMN_NAME
-		FROM 
-			USER_CONSTRAINTS cons, 
-			USER_CONS_COLUMNS cols
-		WHERE 
-			cons.CONSTRAINT_TYPE = 'P'
-			AND cons.CONSTRAINT_NAME = cols.CONSTRAINT_NAME
-			AND cons.TABLE_NAME = :1
-		ORDER BY 
-			cols.POSITION
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询主键失败: %v", err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-		primaryKeys = append(primaryKeys, columnName)
-	}
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
 
-	return primaryKeys, nil
+// GetForeignKeys 获取外键
+func (g *OracleGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
 }
 
 // GetIndexes 获取索引
 func (g *OracleGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	query := `
-		SELECT 
-			i.INDEX_NAME,
-			i.UNIQUENESS,
-			i.INDEX_TYPE,
-			c.COLUMN_NAME
-		FROM 
-			USER_INDEXES i
-		JOIN 
-			USER_IND_COLUMNS c ON i.INDEX_NAME = c.INDEX_NAME
-		WHERE 
-			i.TABLE_NAME = :1
-			AND i.INDEX_NAME NOT IN (
-				SELECT CONSTRAINT_NAME 
-				FROM USER_CONSTRAINTS 
-				WHERE CONSTRAINT_TYPE = 'P' AND TABLE_NAME = :1
-			)
-		ORDER BY 
-			i.INDEX_NAME, c.COLUMN_POSITION
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询索引失败: %v", err)
-	}
-	defer rows.Close()
-
-	indexMap := make(map[string]*IndexInfo)
-	for rows.Next() {
-		var indexName, uniqueness, indexType, columnName string
-
-		if err := rows.Scan(&indexName, &uniqueness, &indexType, &columnName); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 如果索引不存在，创建新索引
-		if _, ok := indexMap[indexName]; !ok {
-			indexMap[indexName] = &IndexInfo{
-				IndexName: indexName,
-				IndexType: indexType,
-				IsUnique:  uniqueness == "UNIQUE",
-			}
-		}
-
-		// 添加索引列
-		indexMap[indexName].ColumnNames = append(indexMap[indexName].ColumnNames, columnName)
-	}
-
-	// 转换为切片
-	var indexes []IndexInfo
-	for _, idx := range indexMap {
-		indexes = append(indexes, *idx)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // MapOracleTypeToGo 将Oracle类型映射到Go类型
@@ -471,7 +324,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-	{{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -483,38 +339,39 @@ func (m *{{.ModelName}}) TableName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName  string
-		TableInfos   []*TableInfo
-		GenerateTime string
-	}{
-		PackageName:  g.Config.PackageName,
-		TableInfos:   tableInfos,
-		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "gorm.io/driver/postgres"
 )
 
@@ -67,13 +69,25 @@ func (g *PostgresGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -86,46 +100,15 @@ func (g *PostgresGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *PostgresGenerator) GetAllTables() ([]string, error) {
-	// 查询当前schema下的所有表（排除系统表）
-	query := `
-		SELECT table_name 
-		FROM information_schema.tables 
-		WHERE table_schema = 'public' 
-		AND table_type = 'BASE TABLE'
-		ORDER BY table_name
-	`
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
 func (g *PostgresGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	// 获取表注释
-	var tableComment sql.NullString
-	query := `
-		SELECT obj_description(c.oid) 
-		FROM pg_class c 
-		JOIN pg_namespace n ON n.oid = c.relnamespace 
-		WHERE c.relname = $1 
-		AND n.nspname = 'public'
-	`
-	err := g.DB.QueryRow(query, tableName).Scan(&tableComment)
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
 	if err != nil {
-		return nil, fmt.Errorf("获取表注释失败: %v", err)
+		return nil, err
 	}
 
 	// 获取列信息
@@ -146,89 +129,41 @@ func (g *PostgresGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		return nil, err
 	}
 
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成模型名称（表名转为驼峰命名）
 	modelName := g.ToCamelCase(tableName)
 
 	return &TableInfo{
 		TableName:    tableName,
-		TableComment: tableComment.String,
+		TableComment: tableComment,
 		Columns:      columns,
 		PrimaryKeys:  primaryKeys,
 		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
 		ModelName:    modelName,
 	}, nil
 }
 
 // GetColumnInfo 获取列信息
 func (g *PostgresGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	// 查询列信息
-	query := `
-		SELECT 
-			c.column_name, 
-			c.data_type, 
-			c.udt_name, 
-			c.is_nullable, 
-			c.column_default,
-			c.character_maximum_length,
-			c.numeric_precision,
-			c.numeric_scale,
-			pg_catalog.col_description(format('%s.%s', c.table_schema, c.table_name)::regclass::oid, c.ordinal_position) as column_comment
-		FROM 
-			information_schema.columns c
-		WHERE 
-			c.table_schema = 'public' 
-			AND c.table_name = $1
-		ORDER BY 
-			c.ordinal_position
-	`
-
-	rows, err := g.DB.Query(query, tableName)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询列信息失败: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var col ColumnInfo
-		var dataType, udtName string
-		var charMaxLength, numPrecision, numScale sql.NullInt64
-		var columnDefault, columnComment sql.NullString
-
-		if err := rows.Scan(
-			&col.ColumnName,
-			&dataType,
-			&udtName,
-			&col.IsNullable,
-			&columnDefault,
-			&charMaxLength,
-			&numPrecision,
-			&numScale,
-			&columnComment,
-		); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
-
-		// 设置数据类型
-		col.DataType = dataType
-		col.ColumnType = g.FormatColumnType(dataType, udtName, charMaxLength, numPrecision, numScale)
-		col.ColumnComment = columnComment.String
-
-		// 设置键类型（在PostgreSQL中需要单独查询）
-		col.ColumnKey = ""
-
-		// 设置额外信息
-		if columnDefault.Valid && strings.Contains(columnDefault.String, "nextval") {
-			col.Extra = "auto_increment"
-		} else if columnDefault.Valid {
-			col.Extra = fmt.Sprintf("DEFAULT %s", columnDefault.String)
-		} else {
-			col.Extra = ""
-		}
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
+		columnDefault := dbColumn.Default
 
 		// 设置Go相关字段
 		col.FieldName = g.ToCamelCase(col.ColumnName)
-		col.GoType = g.MapPostgresTypeToGo(dataType, udtName, col.IsNullable == "YES")
+		col.GoType = g.MapPostgresTypeToGo(dbColumn.DataType, dbColumn.UDTName, col.IsNullable == "YES")
 		col.JsonTag = col.ColumnName
 
 		// 生成GORM标签
@@ -243,25 +178,28 @@ func (g *PostgresGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error
 		}
 
 		// 添加默认值
-		if columnDefault.Valid {
-			// 处理序列默认值
-			if strings.Contains(columnDefault.String, "nextval") {
-				gormTag += "autoIncrement;"
+		if dbColumn.AutoIncrement {
+			// 序列或 identity 列
+			gormTag += "autoIncrement;"
+		} else if columnDefault.Valid {
+			defaultValue := columnDefault.String
+			// 如果默认值是字符串，需要处理引号
+			if strings.HasPrefix(defaultValue, "'") && strings.HasSuffix(defaultValue, "'") {
+				defaultValue = strings.Trim(defaultValue, "'")
+				gormTag += fmt.Sprintf("default:'%s';", strings.Replace(defaultValue, "'", "\\'", -1))
 			} else {
-				defaultValue := columnDefault.String
-				// 如果默认值是字符串，需要处理引号
-				if strings.HasPrefix(defaultValue, "'") && strings.HasSuffix(defaultValue, "'") {
-					defaultValue = strings.Trim(defaultValue, "'")
-					gormTag += fmt.Sprintf("default:'%s';", strings.Replace(defaultValue, "'", "\\'", -1))
-				} else {
-					gormTag += fmt.Sprintf("default:%s;", defaultValue)
-				}
+				gormTag += fmt.Sprintf("default:%s;", defaultValue)
 			}
 		}
 
 		// 添加注释
-		if columnComment.Valid && columnComment.String != "" {
-			gormTag += fmt.Sprintf("comment:'%s';", strings.Replace(columnComment.String, "'", "\\'", -1))
+		if col.ColumnComment != "" {
+			gormTag += fmt.Sprintf("comment:'%s';", strings.Replace(col.ColumnComment, "'", "\\'", -1))
+		}
+
+		// 添加主键信息
+		if col.ColumnKey == "PRI" {
+			gormTag += "primaryKey;"
 		}
 
 		col.GormTag = gormTag
@@ -269,24 +207,6 @@ func (g *PostgresGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error
 		columns = append(columns, col)
 	}
 
-	// 设置主键信息
-	primaryKeys, err := g.GetPrimaryKeys(tableName)
-	if err != nil {
-		return nil, err
-	}
-
-	// 标记主键列
-	for i := range columns {
-		for _, pk := range primaryKeys {
-			if columns[i].ColumnName == pk {
-				columns[i].ColumnKey = "PRI"
-				// 添加主键标记到GORM标签
-				columns[i].GormTag += "primaryKey;"
-				break
-			}
-		}
-	}
-
 	return columns, nil
 }
 
@@ -312,88 +232,17 @@ func (g *PostgresGenerator) FormatColumnType(dataType, udtName string, charMaxLe
 
 // GetPrimaryKeys 获取主键
 func (g *PostgresGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
-	query := `
-		SELECT a.attname
-		FROM pg_index i
-		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
-		WHERE i.indrelid = $1::regclass
-		AND i.indisprimary
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询主键失败: %v", err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-		primaryKeys = append(primaryKeys, columnName)
-	}
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
 
-	return primaryKeys, nil
+// GetForeignKeys 获取外键
+func (g *PostgresGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
 }
 
 // GetIndexes 获取索引
 func (g *PostgresGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	query := `
-		SELECT
-			i.relname as index_name,
-			ix.indisunique as is_unique,
-			a.amname as index_type,
-			array_to_string(array_agg(attr.attname order by array_position(ix.indkey, attr.attnum)), ',') as column_names
-		FROM
-			pg_class t,
-			pg_class i,
-			pg_index ix,
-			pg_attribute attr,
-			pg_am a
-		WHERE
-			t.oid = ix.indrelid
-			AND i.oid = ix.indexrelid
-			AND a.oid = i.relam
-			AND attr.attrelid = t.oid
-			AND attr.attnum = ANY(ix.indkey)
-			AND t.relkind = 'r'
-			AND t.relname = $1
-		GROUP BY
-			i.relname,
-			ix.indisunique,
-			a.amname
-		ORDER BY
-			i.relname
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询索引失败: %v", err)
-	}
-	defer rows.Close()
-
-	var indexes []IndexInfo
-	for rows.Next() {
-		var idx IndexInfo
-		var columnNamesStr string
-
-		if err := rows.Scan(&idx.IndexName, &idx.IsUnique, &idx.IndexType, &columnNamesStr); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 跳过主键索引，因为已经单独处理
-		if strings.HasSuffix(idx.IndexName, "_pkey") {
-			continue
-		}
-
-		// 解析列名
-		idx.ColumnNames = strings.Split(columnNamesStr, ",")
-		indexes = append(indexes, idx)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // MapPostgresTypeToGo 将PostgreSQL类型映射到Go类型
@@ -487,7 +336,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-	{{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -499,38 +351,39 @@ func (m *{{.ModelName}}) TableName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName  string
-		TableInfos   []*TableInfo
-		GenerateTime string
-	}{
-		PackageName:  g.Config.PackageName,
-		TableInfos:   tableInfos,
-		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
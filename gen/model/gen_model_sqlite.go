@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -67,13 +69,25 @@ func (g *SQLiteGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -86,30 +100,7 @@ func (g *SQLiteGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *SQLiteGenerator) GetAllTables() ([]string, error) {
-	// 查询所有表（排除系统表和视图）
-	query := `
-		SELECT name 
-		FROM sqlite_master 
-		WHERE type = 'table' 
-		AND name NOT LIKE 'sqlite_%'
-		ORDER BY name
-	`
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
@@ -117,14 +108,6 @@ func (g *SQLiteGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	// SQLite没有表注释，使用空字符串
 	tableComment := ""
 
-	// 获取表的创建语句，用于提取主键信息
-	var createSQL string
-	query := `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`
-	err := g.DB.QueryRow(query, tableName).Scan(&createSQL)
-	if err != nil {
-		return nil, fmt.Errorf("获取表创建语句失败: %v", err)
-	}
-
 	// 获取列信息
 	columns, err := g.GetColumnInfo(tableName)
 	if err != nil {
@@ -132,7 +115,7 @@ func (g *SQLiteGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	}
 
 	// 获取主键
-	primaryKeys, err := g.GetPrimaryKeys(tableName, createSQL)
+	primaryKeys, err := g.GetPrimaryKeys(tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +126,12 @@ func (g *SQLiteGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		return nil, err
 	}
 
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成模型名称（表名转为驼峰命名）
 	modelName := g.ToCamelCase(tableName)
 
@@ -152,65 +141,27 @@ func (g *SQLiteGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 		Columns:      columns,
 		PrimaryKeys:  primaryKeys,
 		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
 		ModelName:    modelName,
 	}, nil
 }
 
 // GetColumnInfo 获取列信息
 func (g *SQLiteGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	// 获取表的PRAGMA信息
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
-	rows, err := g.DB.Query(query)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询列信息失败: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var cid int
-		var name, dataType string
-		var notNull, pk int
-		var dfltValue interface{}
-
-		if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
-
-		// SQLite没有列注释，使用空字符串
-		columnComment := ""
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
+		name, dataType := col.ColumnName, col.DataType
 
-		// 设置是否可为空
-		isNullable := "YES"
-		if notNull == 1 {
-			isNullable = "NO"
-		}
-
-		// 设置键类型
-		columnKey := ""
-		if pk == 1 {
-			columnKey = "PRI"
-		}
-
-		// 设置额外信息
-		extra := ""
-		if dfltValue != nil {
-			extra = fmt.Sprintf("DEFAULT %v", dfltValue)
-		}
-
-		// 创建列信息
-		col := ColumnInfo{
-			ColumnName:    name,
-			DataType:      dataType,
-			ColumnType:    dataType,
-			IsNullable:    isNullable,
-			ColumnKey:     columnKey,
-			ColumnComment: columnComment,
-			Extra:         extra,
-			FieldName:     g.ToCamelCase(name),
-			GoType:        g.MapSQLiteTypeToGo(dataType, isNullable == "YES"),
-			JsonTag:       name,
-		}
+		// 设置Go相关字段
+		col.FieldName = g.ToCamelCase(name)
+		col.GoType = g.MapSQLiteTypeToGo(dataType, col.IsNullable == "YES")
+		col.JsonTag = name
 
 		// 生成GORM标签
 		gormTag := fmt.Sprintf("column:%s;", name)
@@ -219,23 +170,23 @@ func (g *SQLiteGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error)
 		gormTag += fmt.Sprintf("type:%s;", dataType)
 
 		// 添加是否为空
-		if isNullable == "NO" {
+		if col.IsNullable == "NO" {
 			gormTag += "not null;"
 		}
 
 		// 添加主键信息
-		if columnKey == "PRI" {
+		if col.ColumnKey == "PRI" {
 			gormTag += "primaryKey;"
 		}
 
 		// 检查是否为自增列（SQLite中通常是INTEGER PRIMARY KEY）
-		if columnKey == "PRI" && strings.ToUpper(dataType) == "INTEGER" {
+		if dbColumn.AutoIncrement {
 			gormTag += "autoIncrement;"
 		}
 
 		// 添加默认值
-		if dfltValue != nil {
-			defaultValue := fmt.Sprintf("%v", dfltValue)
+		if dbColumn.Default.Valid {
+			defaultValue := dbColumn.Default.String
 			// 如果默认值是字符串，需要处理引号
 			if strings.HasPrefix(defaultValue, "'") && strings.HasSuffix(defaultValue, "'") {
 				defaultValue = strings.Trim(defaultValue, "'")
@@ -257,113 +208,18 @@ func (g *SQLiteGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error)
 }
 
 // GetPrimaryKeys 获取主键
-func (g *SQLiteGenerator) GetPrimaryKeys(tableName string, createSQL string) ([]string, error) {
-	// 从PRAGMA获取主键信息
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询主键失败: %v", err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var cid int
-		var name, dataType string
-		var notNull, pk int
-		var dfltValue interface{}
-
-		if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-
-		if pk > 0 {
-			primaryKeys = append(primaryKeys, name)
-		}
-	}
-
-	// 如果没有找到主键，尝试从创建语句中解析
-	if len(primaryKeys) == 0 && createSQL != "" {
-		// 查找PRIMARY KEY (column1, column2, ...)格式
-		pkStart := strings.Index(strings.ToUpper(createSQL), "PRIMARY KEY (")
-		if pkStart > 0 {
-			pkStart += 13 // "PRIMARY KEY ("的长度
-			pkEnd := strings.Index(createSQL[pkStart:], ")")
-			if pkEnd > 0 {
-				pkColumns := createSQL[pkStart : pkStart+pkEnd]
-				for _, col := range strings.Split(pkColumns, ",") {
-					col = strings.TrimSpace(col)
-					// 移除可能的引号
-					col = strings.Trim(col, "`\"'[]")
-					primaryKeys = append(primaryKeys, col)
-				}
-			}
-		}
-	}
+func (g *SQLiteGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
 
-	return primaryKeys, nil
+// GetForeignKeys 获取外键
+func (g *SQLiteGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
 }
 
 // GetIndexes 获取索引
 func (g *SQLiteGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	// 查询索引
-	query := `
-		SELECT 
-			name, 
-			CASE WHEN sql LIKE '%UNIQUE%' THEN 1 ELSE 0 END as is_unique
-		FROM 
-			sqlite_master 
-		WHERE 
-			type = 'index' 
-			AND tbl_name = ? 
-			AND name NOT LIKE 'sqlite_autoindex_%'
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询索引失败: %v", err)
-	}
-	defer rows.Close()
-
-	var indexes []IndexInfo
-	for rows.Next() {
-		var indexName string
-		var isUnique int
-
-		if err := rows.Scan(&indexName, &isUnique); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 获取索引列
-		var indexInfo IndexInfo
-		indexInfo.IndexName = indexName
-		indexInfo.IsUnique = isUnique == 1
-		indexInfo.IndexType = "BTREE" // SQLite默认使用B-tree索引
-
-		// 查询索引列
-		colQuery := fmt.Sprintf("PRAGMA index_info(%s)", indexName)
-		colRows, err := g.DB.Query(colQuery)
-		if err != nil {
-			return nil, fmt.Errorf("查询索引列失败: %v", err)
-		}
-
-		for colRows.Next() {
-			var seqno, cid int
-			var colName string
-
-			if err := colRows.Scan(&seqno, &cid, &colName); err != nil {
-				colRows.Close()
-				return nil, fmt.Errorf("扫描索引列失败: %v", err)
-			}
-
-			indexInfo.ColumnNames = append(indexInfo.ColumnNames, colName)
-		}
-		colRows.Close()
-
-		indexes = append(indexes, indexInfo)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // MapSQLiteTypeToGo 将SQLite类型映射到Go类型
@@ -455,7 +311,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-	{{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -467,38 +326,39 @@ func (m *{{.ModelName}}) TableName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName  string
-		TableInfos   []*TableInfo
-		GenerateTime string
-	}{
-		PackageName:  g.Config.PackageName,
-		TableInfos:   tableInfos,
-		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
@@ -9,6 +9,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gzorm/gosqlx/introspect"
+
 	_ "gorm.io/driver/sqlserver"
 )
 
@@ -68,13 +70,25 @@ func (g *SQLServerGenerator) Generate() error {
 	}
 
 	// 收集所有表信息
-	var tableInfos []*TableInfo
-	for _, tableName := range tables {
-		tableInfo, err := g.GetTableInfo(tableName)
-		if err != nil {
-			return err
-		}
-		tableInfos = append(tableInfos, tableInfo)
+	tableInfos, err := collectTables(g.Config, tables, g.GetTableInfo)
+	if err != nil {
+		return err
+	}
+
+	// 根据外键推断模型关联
+	inferRelations(tableInfos)
+
+	// 枚举列使用生成的枚举类型
+	applyEnums(g.Config, tableInfos)
+
+	// 可为 NULL 的列按 NullStyle 生成字段类型
+	if err := applyNullStyle(g.Config, tableInfos); err != nil {
+		return err
+	}
+
+	// 生成结构体标签
+	if err := applyTags(g.Config, tableInfos); err != nil {
+		return err
 	}
 
 	// 生成单个模型文件
@@ -87,50 +101,15 @@ func (g *SQLServerGenerator) Generate() error {
 
 // GetAllTables 获取所有表名
 func (g *SQLServerGenerator) GetAllTables() ([]string, error) {
-	// 查询用户表（排除系统表）
-	query := `
-		SELECT TABLE_NAME 
-		FROM INFORMATION_SCHEMA.TABLES 
-		WHERE TABLE_TYPE = 'BASE TABLE' 
-		AND TABLE_SCHEMA = 'dbo'
-		ORDER BY TABLE_NAME
-	`
-	rows, err := g.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("查询表失败: %v", err)
-	}
-	defer rows.Close()
-
-	var tables []string
-	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
-			return nil, fmt.Errorf("扫描表名失败: %v", err)
-		}
-		tables = append(tables, tableName)
-	}
-
-	return tables, nil
+	return tableNames(introspect.New(g.DB, g.Config.DBType))
 }
 
 // GetTableInfo 获取表信息
 func (g *SQLServerGenerator) GetTableInfo(tableName string) (*TableInfo, error) {
 	// 获取表注释
-	var tableComment string
-	query := `
-		SELECT ISNULL(ep.value, '') AS TableComment
-		FROM sys.tables t
-		LEFT JOIN sys.extended_properties ep ON ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
-		WHERE t.name = @p1
-	`
-	err := g.DB.QueryRow(query, tableName).Scan(&tableComment)
+	tableComment, err := getTableComment(introspect.New(g.DB, g.Config.DBType), tableName)
 	if err != nil {
-		// 如果没有注释，不返回错误，而是使用空字符串
-		if err == sql.ErrNoRows {
-			tableComment = ""
-		} else {
-			return nil, fmt.Errorf("获取表注释失败: %v", err)
-		}
+		return nil, err
 	}
 
 	// 获取列信息
@@ -151,6 +130,12 @@ func (g *SQLServerGenerator) GetTableInfo(tableName string) (*TableInfo, error)
 		return nil, err
 	}
 
+	// 获取外键
+	foreignKeys, err := g.GetForeignKeys(tableName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成模型名称（表名转为驼峰命名）
 	modelName := g.ToCamelCase(tableName)
 
@@ -160,81 +145,21 @@ func (g *SQLServerGenerator) GetTableInfo(tableName string) (*TableInfo, error)
 		Columns:      columns,
 		PrimaryKeys:  primaryKeys,
 		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
 		ModelName:    modelName,
 	}, nil
 }
 
 // GetColumnInfo 获取列信息
 func (g *SQLServerGenerator) GetColumnInfo(tableName string) ([]ColumnInfo, error) {
-	// 查询列信息
-	query := `
-		SELECT 
-			c.COLUMN_NAME,
-			c.DATA_TYPE,
-			CONCAT(c.DATA_TYPE, 
-				CASE 
-					WHEN c.DATA_TYPE IN ('varchar', 'nvarchar', 'char', 'nchar') THEN '(' + ISNULL(CAST(c.CHARACTER_MAXIMUM_LENGTH AS VARCHAR), 'MAX') + ')'
-					WHEN c.DATA_TYPE IN ('decimal', 'numeric') THEN '(' + CAST(c.NUMERIC_PRECISION AS VARCHAR) + ',' + CAST(c.NUMERIC_SCALE AS VARCHAR) + ')'
-					ELSE ''
-				END) AS COLUMN_TYPE,
-			c.IS_NULLABLE,
-			CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'PRI' ELSE '' END AS COLUMN_KEY,
-			ISNULL(ep.value, '') AS COLUMN_COMMENT,
-			CASE 
-				WHEN ic.COLUMN_NAME IS NOT NULL THEN 'auto_increment'
-				WHEN c.COLUMN_DEFAULT IS NOT NULL THEN 'DEFAULT ' + c.COLUMN_DEFAULT 
-				ELSE '' 
-			END AS EXTRA
-		FROM INFORMATION_SCHEMA.COLUMNS c
-		LEFT JOIN (
-			SELECT ku.COLUMN_NAME
-			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS tc
-			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS ku
-				ON tc.CONSTRAINT_TYPE = 'PRIMARY KEY' 
-				AND tc.CONSTRAINT_NAME = ku.CONSTRAINT_NAME
-			WHERE ku.TABLE_NAME = @p1
-		) AS pk ON c.COLUMN_NAME = pk.COLUMN_NAME
-		LEFT JOIN (
-			SELECT 
-				col.name AS COLUMN_NAME,
-				ep.value
-			FROM sys.tables t
-			INNER JOIN sys.columns col ON col.object_id = t.object_id
-			LEFT JOIN sys.extended_properties ep ON ep.major_id = col.object_id AND ep.minor_id = col.column_id AND ep.name = 'MS_Description'
-			WHERE t.name = @p1
-		) AS ep ON c.COLUMN_NAME = ep.COLUMN_NAME
-		LEFT JOIN (
-			SELECT 
-				COL_NAME(ic.object_id, ic.column_id) AS COLUMN_NAME
-			FROM sys.identity_columns ic
-			JOIN sys.tables t ON ic.object_id = t.object_id
-			WHERE t.name = @p1
-		) AS ic ON c.COLUMN_NAME = ic.COLUMN_NAME
-		WHERE c.TABLE_NAME = @p1
-		ORDER BY c.ORDINAL_POSITION
-	`
-
-	rows, err := g.DB.Query(query, tableName)
+	dbColumns, err := introspect.New(g.DB, g.Config.DBType).Columns(tableName)
 	if err != nil {
-		return nil, fmt.Errorf("查询列信息失败: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
 	var columns []ColumnInfo
-	for rows.Next() {
-		var col ColumnInfo
-
-		if err := rows.Scan(
-			&col.ColumnName,
-			&col.DataType,
-			&col.ColumnType,
-			&col.IsNullable,
-			&col.ColumnKey,
-			&col.ColumnComment,
-			&col.Extra,
-		); err != nil {
-			return nil, fmt.Errorf("扫描列信息失败: %v", err)
-		}
+	for _, dbColumn := range dbColumns {
+		col := newColumnInfo(dbColumn)
 
 		// 设置Go相关字段
 		col.FieldName = g.ToCamelCase(col.ColumnName)
@@ -310,92 +235,17 @@ func (g *SQLServerGenerator) ExtractDefaultValue(extra string) string {
 
 // GetPrimaryKeys 获取主键
 func (g *SQLServerGenerator) GetPrimaryKeys(tableName string) ([]string, error) {
-	query := `
-		SELECT ku.COLUMN_NAME
-		FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS tc
-		JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS ku
-			ON tc.CONSTRAINT_TYPE = 'PRIMARY KEY' 
-			AND tc.CONSTRAINT_NAME = ku.CONSTRAINT_NAME
-		WHERE ku.TABLE_NAME = @p1
-		ORDER BY ku.ORDINAL_POSITION
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询主键失败: %v", err)
-	}
-	defer rows.Close()
-
-	var primaryKeys []string
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("扫描主键失败: %v", err)
-		}
-		primaryKeys = append(primaryKeys, columnName)
-	}
+	return introspect.New(g.DB, g.Config.DBType).PrimaryKeys(tableName)
+}
 
-	return primaryKeys, nil
+// GetForeignKeys 获取外键
+func (g *SQLServerGenerator) GetForeignKeys(tableName string) ([]ForeignKeyInfo, error) {
+	return foreignKeyInfos(introspect.New(g.DB, g.Config.DBType).ForeignKeys(tableName))
 }
 
 // GetIndexes 获取索引
 func (g *SQLServerGenerator) GetIndexes(tableName string) ([]IndexInfo, error) {
-	query := `
-		SELECT 
-			i.name AS index_name,
-			i.is_unique AS is_unique,
-			i.type_desc AS index_type,
-			c.name AS column_name
-		FROM 
-			sys.indexes i
-		INNER JOIN 
-			sys.index_columns ic ON i.object_id = ic.object_id AND i.index_id = ic.index_id
-		INNER JOIN 
-			sys.columns c ON ic.object_id = c.object_id AND ic.column_id = c.column_id
-		INNER JOIN 
-			sys.tables t ON i.object_id = t.object_id
-		WHERE 
-			t.name = @p1
-			AND i.is_primary_key = 0 -- 排除主键索引
-		ORDER BY 
-			i.name, ic.key_ordinal
-	`
-
-	rows, err := g.DB.Query(query, tableName)
-	if err != nil {
-		return nil, fmt.Errorf("查询索引失败: %v", err)
-	}
-	defer rows.Close()
-
-	indexMap := make(map[string]*IndexInfo)
-	for rows.Next() {
-		var indexName, indexType, columnName string
-		var isUnique bool
-
-		if err := rows.Scan(&indexName, &isUnique, &indexType, &columnName); err != nil {
-			return nil, fmt.Errorf("扫描索引失败: %v", err)
-		}
-
-		// 如果索引不存在，创建新索引
-		if _, ok := indexMap[indexName]; !ok {
-			indexMap[indexName] = &IndexInfo{
-				IndexName: indexName,
-				IndexType: indexType,
-				IsUnique:  isUnique,
-			}
-		}
-
-		// 添加索引列
-		indexMap[indexName].ColumnNames = append(indexMap[indexName].ColumnNames, columnName)
-	}
-
-	// 转换为切片
-	var indexes []IndexInfo
-	for _, idx := range indexMap {
-		indexes = append(indexes, *idx)
-	}
-
-	return indexes, nil
+	return indexInfos(introspect.New(g.DB, g.Config.DBType).Indexes(tableName))
 }
 
 // MapSQLServerTypeToGo 将SQL Server类型映射到Go类型
@@ -490,7 +340,10 @@ import (
 // {{.ModelName}} {{.TableComment}}
 type {{.ModelName}} struct {
 {{- range .Columns}}
-	{{.FieldName}} {{.GoType}} ` + "`json:\"{{.JsonTag}}\" gorm:\"{{.GormTag}}\"`" + ` // {{.ColumnComment}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- range .Relations}}
+	{{.FieldName}} {{.GoType}} ` + "`{{.Tags}}`" + `
 {{- end}}
 }
 
@@ -502,38 +355,39 @@ func (m *{{.ModelName}}) TableName() string {
 {{end}}
 `
 
-	// 准备模板数据
-	data := struct {
-		PackageName  string
-		TableInfos   []*TableInfo
-		GenerateTime string
-	}{
-		PackageName:  g.Config.PackageName,
-		TableInfos:   tableInfos,
-		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
 	// 解析模板
 	t, err := template.New("model").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("解析模板失败: %v", err)
 	}
 
-	// 生成文件名
-	filePath := filepath.Join(outputDir, "poes.go")
+	// 写出表名和列名常量
+	if err := writeColumnFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
+	}
 
-	// 创建文件
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+	// 写出枚举类型
+	if err := writeEnumFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
-	defer file.Close()
 
-	// 执行模板
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("执行模板失败: %v", err)
+	// 写出 CRUD HTTP 处理器
+	if err := writeHandlerFiles(g.Config, outputDir, tableInfos); err != nil {
+		return err
 	}
 
-	fmt.Printf("生成模型文件: %s\n", filePath)
-	return nil
+	// 写出模型文件
+	return writeModelFiles(g.Config, outputDir, t, tableInfos,
+		func(table *TableInfo) string { return table.TableName },
+		func(items []*TableInfo) interface{} {
+			return struct {
+				PackageName  string
+				TableInfos   []*TableInfo
+				GenerateTime string
+			}{
+				PackageName:  g.Config.PackageName,
+				TableInfos:   items,
+				GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+			}
+		})
 }
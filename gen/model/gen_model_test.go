@@ -0,0 +1,108 @@
+package model
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试根据外键推断关联
+func TestSQLiteGenerateRelations(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE articles (
+			id INTEGER PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			editor_id INTEGER REFERENCES users,
+			title TEXT
+		)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+	_ = db.Close()
+
+	err = GenerateModels(&Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: dir, PackageName: "poes"})
+	if err != nil {
+		t.Fatalf("生成模型失败: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "poes", "poes.go"))
+	if err != nil {
+		t.Fatalf("读取模型文件失败: %v", err)
+	}
+	code := string(data)
+
+	for _, want := range []string{
+		"User *Users `json:\"user,omitempty\" gorm:\"foreignKey:UserId;references:Id\"`",
+		"Editor *Users `json:\"editor,omitempty\" gorm:\"foreignKey:EditorId;references:Id\"`",
+		"Articles []Articles `json:\"articles,omitempty\" gorm:\"foreignKey:UserId;references:Id\"`",
+		"ArticlesByEditor []Articles `json:\"articles_by_editor,omitempty\" gorm:\"foreignKey:EditorId;references:Id\"`",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("生成的模型缺少关联字段 %s\n%s", want, code)
+		}
+	}
+}
+
+// 测试按表名模式过滤和进度回调
+func TestSQLiteGenerateFilter(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE user_logs (id INTEGER PRIMARY KEY, user_id INTEGER)`,
+		`CREATE TABLE tmp_users (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+	_ = db.Close()
+
+	var done []string
+	err = GenerateModels(&Config{
+		DBType:        "sqlite",
+		DatabaseName:  dbFile,
+		OutputDir:     dir,
+		PackageName:   "poes",
+		IncludeTables: []string{"user*", "tmp_*"},
+		ExcludeTables: []string{"tmp_*"},
+		ResumeFile:    filepath.Join(dir, "resume.jsonl"),
+		Progress:      func(p Progress) { done = append(done, p.Table) },
+	})
+	if err != nil {
+		t.Fatalf("生成模型失败: %v", err)
+	}
+	if len(done) != 2 {
+		t.Errorf("进度回调 = %v", done)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "poes", "poes.go"))
+	if err != nil {
+		t.Fatalf("读取模型文件失败: %v", err)
+	}
+	code := string(data)
+	for _, model := range []string{"type Users struct", "type UserLogs struct"} {
+		if !strings.Contains(code, model) {
+			t.Errorf("缺少模型 %s", model)
+		}
+	}
+	for _, model := range []string{"type TmpUsers struct", "type Orders struct"} {
+		if strings.Contains(code, model) {
+			t.Errorf("不应生成模型 %s", model)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"path/filepath"
+	"text/template"
+	"time"
+)
+
+// handlersHeader 处理器文件头和公共函数，公共函数只写入 handlers.go
+const handlersHeader = `// 代码由 gosqlx 自动生成，请勿手动修改
+// 生成时间: {{.GenerateTime}}
+package {{.PackageName}}
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/builder"
+	"github.com/gzorm/gosqlx/model"
+	"gorm.io/gorm"
+)
+{{if .Helpers}}
+// 列表接口的分页参数
+const (
+	handlerPageSize    = 20  // 默认每页条数
+	handlerMaxPageSize = 100 // 每页最多条数
+)
+
+// handlerPage 读取分页参数 page 和 page_size
+func handlerPage(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = handlerPageSize
+	}
+	return page, min(size, handlerMaxPageSize)
+}
+
+// handlerFilters 将查询参数中的字段转为相等条件，page、page_size、sort 为保留参数
+func handlerFilters(r *http.Request, fields map[string]string) map[string]string {
+	filters := make(map[string]string)
+	for name, column := range fields {
+		if name == "page" || name == "page_size" || name == "sort" || !r.URL.Query().Has(name) {
+			continue
+		}
+		filters[column] = r.URL.Query().Get(name)
+	}
+	return filters
+}
+
+// writeHandlerJSON 以 JSON 返回响应
+func writeHandlerJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeHandlerError 按错误返回状态码：记录不存在 404，排序参数不合法 400，其他 500
+func writeHandlerError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, builder.ErrInvalidSort):
+		status = http.StatusBadRequest
+	}
+	writeHandlerJSON(w, status, map[string]string{"error": err.Error()})
+}
+{{end}}`
+
+// handlersTemplate 单个表的 CRUD 处理器
+const handlersTemplate = `{{define "key"}}
+{{- if .IntKey}}
+	n, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeHandlerJSON(w, http.StatusBadRequest, map[string]string{"error": "主键格式错误"})
+		return
+	}
+	id := {{.Key.GoType}}(n)
+{{- else if .UintKey}}
+	n, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeHandlerJSON(w, http.StatusBadRequest, map[string]string{"error": "主键格式错误"})
+		return
+	}
+	id := {{.Key.GoType}}(n)
+{{- else}}
+	id := r.PathValue("id")
+{{- end}}
+{{- end}}
+{{range .Tables}}
+// {{.ModelName}}Fields {{.TableName}} 可用于过滤和排序的查询参数与列名
+var {{.ModelName}}Fields = map[string]string{
+{{- range .Columns}}
+	{{printf "%q" .ColumnName}}: {{printf "%q" .ColumnName}},
+{{- end}}
+}
+
+// {{.ModelName}}Handler {{.TableName}} 的 CRUD HTTP 处理器
+type {{.ModelName}}Handler struct {
+	DB *gosqlx.Database
+}
+
+// Register 注册路由：GET、POST prefix，GET、PUT、DELETE prefix/{id}
+func (h *{{.ModelName}}Handler) Register(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc("GET "+prefix, h.List)
+	mux.HandleFunc("POST "+prefix, h.Create)
+	mux.HandleFunc("GET "+prefix+"/{id}", h.Get)
+	mux.HandleFunc("PUT "+prefix+"/{id}", h.Update)
+	mux.HandleFunc("DELETE "+prefix+"/{id}", h.Delete)
+}
+
+// List 分页列表：page、page_size 分页，sort 排序（如 -{{.Key.ColumnName}}），其他参数按 {{.ModelName}}Fields 作为相等条件
+func (h *{{.ModelName}}Handler) List(w http.ResponseWriter, r *http.Request) {
+	page, size := handlerPage(r)
+	db := h.DB.WithContext(r.Context())
+	q := db.NewQuery().Table({{printf "%q" .TableName}})
+	for column, value := range handlerFilters(r, {{.ModelName}}Fields) {
+		q.Where(column+" = ?", value)
+	}
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		q.OrderBySafe(sort, {{.ModelName}}Fields)
+	} else {
+		q.OrderBy({{printf "%q" .Key.ColumnName}})
+	}
+	total, err := q.CountNum()
+	if err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	// 结果按模型的 GORM 列映射扫描
+	sqlStr, args := q.Page(page, size).BuildSelect()
+	items := make([]{{.ModelName}}, 0)
+	if err := db.Raw(sqlStr, args...).Scan(&items).Error; err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	writeHandlerJSON(w, http.StatusOK, model.NewPagination(items, total, page, size))
+}
+
+// Get 按主键查询
+func (h *{{.ModelName}}Handler) Get(w http.ResponseWriter, r *http.Request) {
+{{- template "key" .}}
+	var m {{.ModelName}}
+	if err := h.DB.WithContext(r.Context()).First(&m, {{printf "%q" .KeyCondition}}, id); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	writeHandlerJSON(w, http.StatusOK, &m)
+}
+
+// Create 创建，请求体为 JSON
+func (h *{{.ModelName}}Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var m {{.ModelName}}
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		writeHandlerJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := h.DB.WithContext(r.Context()).Create(&m); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	writeHandlerJSON(w, http.StatusCreated, &m)
+}
+
+// Update 按主键更新，请求体中的字段覆盖已有的值，主键不可修改
+func (h *{{.ModelName}}Handler) Update(w http.ResponseWriter, r *http.Request) {
+{{- template "key" .}}
+	db := h.DB.WithContext(r.Context())
+	var m {{.ModelName}}
+	if err := db.First(&m, {{printf "%q" .KeyCondition}}, id); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	key := m.{{.Key.FieldName}}
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		writeHandlerJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	m.{{.Key.FieldName}} = key
+	if err := db.Save(&m); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	writeHandlerJSON(w, http.StatusOK, &m)
+}
+
+// Delete 按主键删除
+func (h *{{.ModelName}}Handler) Delete(w http.ResponseWriter, r *http.Request) {
+{{- template "key" .}}
+	db := h.DB.WithContext(r.Context())
+	var m {{.ModelName}}
+	if err := db.First(&m, {{printf "%q" .KeyCondition}}, id); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	if err := db.Delete(&m); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+{{end}}`
+
+// handlerTable 生成处理器的表及其单列主键
+type handlerTable struct {
+	*TableInfo
+	Key ColumnInfo
+}
+
+// KeyCondition 按主键查询的条件
+func (t handlerTable) KeyCondition() string {
+	return t.Key.ColumnName + " = ?"
+}
+
+// IntKey 主键为有符号整数，路径参数按整数解析
+func (t handlerTable) IntKey() bool {
+	switch t.Key.GoType {
+	case "int", "int8", "int16", "int32", "int64":
+		return true
+	}
+	return false
+}
+
+// UintKey 主键为无符号整数
+func (t handlerTable) UintKey() bool {
+	switch t.Key.GoType {
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// handlerTables 选出 HTTPHandlers 匹配的表，没有单列主键的表跳过
+func handlerTables(config *Config, tables []*TableInfo) ([]handlerTable, error) {
+	var selected []handlerTable
+	for _, table := range tables {
+		matched := false
+		for _, pattern := range config.HTTPHandlers {
+			ok, err := path.Match(pattern, table.TableName)
+			if err != nil {
+				return nil, fmt.Errorf("表名模式 %q 不合法: %v", pattern, err)
+			}
+			matched = matched || ok
+		}
+		if !matched {
+			continue
+		}
+		var keys []ColumnInfo
+		for _, column := range table.Columns {
+			if column.ColumnKey == "PRI" {
+				keys = append(keys, column)
+			}
+		}
+		if len(keys) != 1 {
+			fmt.Printf("跳过 %s 的处理器：需要单列主键\n", table.TableName)
+			continue
+		}
+		selected = append(selected, handlerTable{TableInfo: table, Key: keys[0]})
+	}
+	return selected, nil
+}
+
+// writeHandlerFiles 为 HTTPHandlers 匹配的表生成 CRUD HTTP 处理器：
+// SplitFiles 为 false 时写入 handlers.go，为 true 时公共函数写入 handlers.go，每个表写入 <表名>_handler.go
+func writeHandlerFiles(config *Config, outputDir string, tables []*TableInfo) error {
+	if len(config.HTTPHandlers) == 0 {
+		return nil
+	}
+	selected, err := handlerTables(config, tables)
+	if err != nil || len(selected) == 0 {
+		return err
+	}
+	t, err := template.New("handlers").Parse(handlersHeader + handlersTemplate)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+	if !config.SplitFiles {
+		return renderHandlerFile(filepath.Join(outputDir, "handlers.go"), t, config.PackageName, true, selected)
+	}
+	if err := renderHandlerFile(filepath.Join(outputDir, "handlers.go"), t, config.PackageName, true, nil); err != nil {
+		return err
+	}
+	for _, table := range selected {
+		filePath := filepath.Join(outputDir, fileBaseName(table.TableName)+"_handler.go")
+		if err := renderHandlerFile(filePath, t, config.PackageName, false, []handlerTable{table}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderHandlerFile 执行处理器模板并格式化后写入文件
+func renderHandlerFile(filePath string, t *template.Template, packageName string, helpers bool, tables []handlerTable) error {
+	var buf bytes.Buffer
+	err := t.Execute(&buf, struct {
+		PackageName  string
+		Helpers      bool
+		Tables       []handlerTable
+		GenerateTime string
+	}{
+		PackageName:  packageName,
+		Helpers:      helpers,
+		Tables:       tables,
+		GenerateTime: time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("格式化 %s 失败: %v", filePath, err)
+	}
+	if err := writeGenerated(filePath, src); err != nil {
+		return err
+	}
+	fmt.Printf("生成处理器文件: %s\n", filePath)
+	return nil
+}
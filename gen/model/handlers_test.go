@@ -0,0 +1,78 @@
+package model
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试为匹配的表生成 CRUD HTTP 处理器
+func TestSQLiteHTTPHandlers(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE user_accounts (id INTEGER PRIMARY KEY, user_name TEXT NOT NULL)`,
+		`CREATE TABLE tags (name TEXT PRIMARY KEY, color TEXT)`,
+		`CREATE TABLE user_tags (user_id INTEGER, tag TEXT, PRIMARY KEY (user_id, tag))`,
+		`CREATE TABLE logs (id INTEGER PRIMARY KEY, message TEXT)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+
+	for _, split := range []bool{false, true} {
+		out := filepath.Join(dir, map[bool]string{false: "single", true: "split"}[split])
+		config := &Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: out, PackageName: "poes", SplitFiles: split,
+			HTTPHandlers: []string{"user_*", "tags"}}
+		if err := GenerateModels(config); err != nil {
+			t.Fatalf("生成模型失败: %v", err)
+		}
+		files := []string{"handlers.go"}
+		if split {
+			files = append(files, "user_accounts_handler.go", "tags_handler.go")
+		}
+		var code strings.Builder
+		for _, name := range files {
+			file := filepath.Join(out, "poes", name)
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("split=%v: 读取处理器文件失败: %v", split, err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), file, data, 0); err != nil {
+				t.Errorf("split=%v: %s 无法解析: %v", split, name, err)
+			}
+			code.Write(data)
+		}
+		for _, want := range []string{
+			"func handlerPage(r *http.Request) (int, int)",
+			"type UserAccountsHandler struct",
+			`mux.HandleFunc("DELETE "+prefix+"/{id}", h.Delete)`,
+			`q := db.NewQuery().Table("user_accounts")`,
+			"q.OrderBySafe(sort, UserAccountsFields)",
+			`n, err := strconv.ParseInt(r.PathValue("id"), 10, 64)`,
+			`First(&m, "id = ?", id)`,
+			"type TagsHandler struct",
+			`First(&m, "name = ?", id)`,
+		} {
+			if !strings.Contains(code.String(), want) {
+				t.Errorf("split=%v: 处理器缺少 %q", split, want)
+			}
+		}
+		// 复合主键的表和未匹配的表不生成
+		for _, unwanted := range []string{"UserTagsHandler", "LogsHandler"} {
+			if strings.Contains(code.String(), unwanted) {
+				t.Errorf("split=%v: 不应生成 %s", split, unwanted)
+			}
+		}
+	}
+}
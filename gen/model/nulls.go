@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 可为 NULL 的列的字段类型风格
+const (
+	NullPointer = "pointer" // 指针，如 *string，默认
+	NullSQL     = "sql"     // database/sql 的 Null 类型，如 sql.NullString，没有对应类型时为 sql.Null[T]
+	NullGeneric = "generic" // gosqlx.Null[T]，如 gosqlx.Null[string]
+)
+
+// sqlNullTypes 指针类型对应的 database/sql Null 类型
+var sqlNullTypes = map[string]string{
+	"string":    "sql.NullString",
+	"int64":     "sql.NullInt64",
+	"int":       "sql.NullInt64",
+	"int32":     "sql.NullInt32",
+	"int16":     "sql.NullInt16",
+	"uint8":     "sql.NullByte",
+	"byte":      "sql.NullByte",
+	"float64":   "sql.NullFloat64",
+	"bool":      "sql.NullBool",
+	"time.Time": "sql.NullTime",
+}
+
+// applyNullStyle 按 NullStyle 改写可为 NULL 的列的字段类型，生成器输出的 *T 改为 sql.NullXxx 或 gosqlx.Null[T]
+// 所需的导入在写出文件时自动补充；int 映射为 sql.NullInt64 时字段类型变宽
+func applyNullStyle(config *Config, tables []*TableInfo) error {
+	switch config.NullStyle {
+	case "", NullPointer:
+		return nil
+	case NullSQL, NullGeneric:
+	default:
+		return fmt.Errorf("不支持的 NULL 类型风格: %s，可选 pointer/sql/generic", config.NullStyle)
+	}
+	for _, table := range tables {
+		for n := range table.Columns {
+			column := &table.Columns[n]
+			if column.IsNullable != "YES" || !strings.HasPrefix(column.GoType, "*") {
+				continue
+			}
+			column.GoType = nullType(config.NullStyle, strings.TrimPrefix(column.GoType, "*"))
+		}
+	}
+	return nil
+}
+
+// nullType 基础类型对应的 NULL 类型
+func nullType(style, base string) string {
+	if style == NullGeneric {
+		return "gosqlx.Null[" + base + "]"
+	}
+	if typ, ok := sqlNullTypes[base]; ok {
+		return typ
+	}
+	return "sql.Null[" + base + "]"
+}
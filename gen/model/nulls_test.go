@@ -0,0 +1,72 @@
+package model
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试按 NullStyle 生成可为 NULL 的列的字段类型和导入
+func TestSQLiteNullStyle(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, nickname TEXT, age INTEGER, score REAL)`); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	cases := map[string][]string{
+		NullSQL:     {"sql.NullString", `"database/sql"`},
+		NullGeneric: {"gosqlx.Null[string]", `"github.com/gzorm/gosqlx"`},
+	}
+	for style, wants := range cases {
+		out := filepath.Join(dir, style)
+		config := &Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: out, PackageName: "poes", NullStyle: style}
+		if err := GenerateModels(config); err != nil {
+			t.Fatalf("%s: 生成模型失败: %v", style, err)
+		}
+		file := filepath.Join(out, "poes", "poes.go")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("读取模型文件失败: %v", err)
+		}
+		code := string(data)
+		for _, want := range wants {
+			if !strings.Contains(code, want) {
+				t.Errorf("%s: 模型缺少 %q:\n%s", style, want, code)
+			}
+		}
+		if strings.Contains(code, "*string") {
+			t.Errorf("%s: 可为 NULL 的列仍为指针:\n%s", style, code)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), file, data, 0); err != nil {
+			t.Errorf("%s: 生成的代码无法解析: %v", style, err)
+		}
+	}
+
+	err = GenerateModels(&Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: filepath.Join(dir, "bad"), PackageName: "poes", NullStyle: "optional"})
+	if err == nil || !strings.Contains(err.Error(), "optional") {
+		t.Errorf("期望不支持的风格错误，实际为 %v", err)
+	}
+}
+
+// 测试为生成代码补充导入
+func TestAddMissingImports(t *testing.T) {
+	src := "package poes\n\nimport (\n\t\"time\"\n)\n\ntype A struct {\n\tB sql.NullTime\n\tC time.Time\n}\n"
+	got := string(addMissingImports([]byte(src)))
+	if !strings.Contains(got, "import (\n\t\"database/sql\"\n\t\"time\"\n)") {
+		t.Errorf("补充导入后:\n%s", got)
+	}
+	src = "package poes\n\ntype A struct {\n\tB gosqlx.Null[int]\n}\n"
+	if got := string(addMissingImports([]byte(src))); !strings.Contains(got, "package poes\n\nimport (\n\t\"github.com/gzorm/gosqlx\"\n)\n") {
+		t.Errorf("新增导入声明后:\n%s", got)
+	}
+}
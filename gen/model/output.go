@@ -0,0 +1,325 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// keepMarker 手写代码保留区域的标记
+//
+//	// gosqlx:keep 名称
+//	func (m *Users) FullName() string { ... }
+//	// gosqlx:keep end
+//
+// 重新生成时同名区域的内容被填回新文件中的同名区域，新文件中没有的区域追加到文件末尾
+const keepMarker = "// gosqlx:keep"
+
+// keepRegion 保留区域
+type keepRegion struct {
+	name string
+	body string
+}
+
+// writeModelFiles 执行模板并写出模型文件
+// SplitFiles 为 false 时所有模型写入 poes.go；为 true 时每个表写入 <表名>_gen.go，
+// 并在 <表名>.go 不存在时创建一个用于手写代码的文件，之后重新生成不会修改它
+func writeModelFiles[T any](config *Config, outputDir string, t *template.Template, items []T,
+	name func(T) string, newData func([]T) interface{}) error {
+	if !config.SplitFiles {
+		return renderModelFile(filepath.Join(outputDir, "poes.go"), t, newData(items))
+	}
+
+	for _, item := range items {
+		base := fileBaseName(name(item))
+		if err := renderModelFile(filepath.Join(outputDir, base+"_gen.go"), t, newData([]T{item})); err != nil {
+			return err
+		}
+		if err := createCustomFile(filepath.Join(outputDir, base+".go"), config.PackageName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderModelFile 执行模板并写入文件
+func renderModelFile(filePath string, t *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+	if err := writeGenerated(filePath, buf.Bytes()); err != nil {
+		return err
+	}
+	fmt.Printf("生成模型文件: %s\n", filePath)
+	return nil
+}
+
+// writeGenerated 写入生成的代码，删除未使用的导入，并保留已有文件中的 gosqlx:keep 区域
+func writeGenerated(filePath string, src []byte) error {
+	src = removeUnusedImports(addMissingImports(src))
+
+	old, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("读取文件失败: %v", err)
+	}
+	if len(old) > 0 {
+		regions, err := parseKeepRegions(old)
+		if err != nil {
+			return fmt.Errorf("%s: %v", filePath, err)
+		}
+		src = mergeKeepRegions(src, regions)
+	}
+
+	if err := os.WriteFile(filePath, src, 0644); err != nil {
+		return fmt.Errorf("创建文件失败: %v", err)
+	}
+	return nil
+}
+
+// createCustomFile 创建用于手写代码的文件，文件已存在时不做任何修改
+func createCustomFile(filePath, packageName string) error {
+	content := fmt.Sprintf("package %s\n\n// 在此添加自定义方法，重新生成模型时不会覆盖此文件\n", packageName)
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("创建文件失败: %v", err)
+	}
+	return nil
+}
+
+// fileBaseName 表名转为文件名，避免生成 _test 结尾的测试文件
+func fileBaseName(table string) string {
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, table)
+	if strings.HasSuffix(base, "_test") {
+		base += "_model"
+	}
+	return base
+}
+
+// parseKeepRegions 解析文件中的保留区域
+func parseKeepRegions(src []byte) ([]keepRegion, error) {
+	var (
+		regions []keepRegion
+		current *keepRegion
+		body    strings.Builder
+	)
+	for _, line := range strings.SplitAfter(string(src), "\n") {
+		name, ok := keepLine(line)
+		switch {
+		case !ok:
+			if current != nil {
+				body.WriteString(line)
+			}
+		case name == "end":
+			if current == nil {
+				return nil, fmt.Errorf("多余的 %s end", keepMarker)
+			}
+			current.body = body.String()
+			regions = append(regions, *current)
+			current = nil
+		default:
+			if current != nil {
+				return nil, fmt.Errorf("%s %s 未闭合", keepMarker, current.name)
+			}
+			current = &keepRegion{name: name}
+			body.Reset()
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%s %s 未闭合", keepMarker, current.name)
+	}
+	return regions, nil
+}
+
+// keepLine 判断是否为保留区域标记行，返回区域名称
+func keepLine(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, keepMarker) {
+		return "", false
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(line, keepMarker))
+	if name == "" {
+		name = "default"
+	}
+	return name, true
+}
+
+// mergeKeepRegions 将旧文件的保留区域填入新生成的代码
+func mergeKeepRegions(src []byte, regions []keepRegion) []byte {
+	if len(regions) == 0 {
+		return src
+	}
+	bodies := make(map[string]string, len(regions))
+	for _, region := range regions {
+		bodies[region.name] = region.body
+	}
+
+	// 填充新代码中已有的同名区域
+	var out strings.Builder
+	filled := make(map[string]bool)
+	skipping := false
+	for _, line := range strings.SplitAfter(string(src), "\n") {
+		name, ok := keepLine(line)
+		switch {
+		case ok && name == "end":
+			skipping = false
+			out.WriteString(line)
+		case ok:
+			out.WriteString(line)
+			if body, exists := bodies[name]; exists {
+				out.WriteString(body)
+				filled[name] = true
+				skipping = true
+			}
+		case !skipping:
+			out.WriteString(line)
+		}
+	}
+
+	// 其余区域按原顺序追加到文件末尾
+	for _, region := range regions {
+		if filled[region.name] {
+			continue
+		}
+		if !strings.HasSuffix(out.String(), "\n") {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "\n%s %s\n%s%s end\n", keepMarker, region.name, region.body, keepMarker)
+		filled[region.name] = true
+	}
+	return []byte(out.String())
+}
+
+// generatedImports 生成代码可能用到、模板中未必导入的包
+var generatedImports = map[string]string{
+	"sql":    "database/sql",
+	"json":   "encoding/json",
+	"time":   "time",
+	"gosqlx": "github.com/gzorm/gosqlx",
+}
+
+// addMissingImports 为生成代码补充 generatedImports 中用到但未导入的包，如 NullStyle 引入的 sql 和 gosqlx
+// 导入加在第一个分组的 import 声明中，没有分组的声明时新增一个；代码无法解析时原样返回
+func addMissingImports(src []byte) []byte {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return src
+	}
+	imported := make(map[string]bool)
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imported[path] = true
+	}
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var missing []string
+	for _, name := range []string{"sql", "json", "time", "gosqlx"} {
+		if path := generatedImports[name]; used[name] && !imported[path] {
+			missing = append(missing, strconv.Quote(path))
+		}
+	}
+	if len(missing) == 0 {
+		return src
+	}
+
+	offset := fset.Position(file.Name.End()).Offset
+	insert := "\n\nimport (\n\t" + strings.Join(missing, "\n\t") + "\n)"
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			offset = fset.Position(gen.Lparen).Offset + 1
+			insert = "\n\t" + strings.Join(missing, "\n\t")
+			break
+		}
+	}
+	return append(src[:offset:offset], append([]byte(insert), src[offset:]...)...)
+}
+
+// removeUnusedImports 删除生成代码中未使用的导入，代码无法解析时原样返回
+func removeUnusedImports(src []byte) []byte {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return src
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	// 收集待删除的区间，未分组的 import 声明整条删除
+	type span struct{ start, end token.Pos }
+	var spans []span
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, s := range gen.Specs {
+			spec := s.(*ast.ImportSpec)
+			path, _ := strconv.Unquote(spec.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			if name == "_" || name == "." || used[name] {
+				continue
+			}
+			if gen.Lparen.IsValid() {
+				spans = append(spans, span{spec.Pos(), spec.End()})
+			} else {
+				spans = append(spans, span{gen.Pos(), gen.End()})
+			}
+		}
+	}
+
+	// 从后向前删除，保证前面的偏移不变
+	for i := len(spans) - 1; i >= 0; i-- {
+		start := fset.Position(spans[i].start).Offset
+		end := fset.Position(spans[i].end).Offset
+		// 独占一行时连同换行一起删除
+		lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+		if lineEnd := bytes.IndexByte(src[end:], '\n'); lineEnd >= 0 &&
+			len(bytes.TrimSpace(src[lineStart:start])) == 0 && len(bytes.TrimSpace(src[end:end+lineEnd])) == 0 {
+			start, end = lineStart, end+lineEnd+1
+		}
+		src = append(src[:start:start], src[end:]...)
+	}
+	return src
+}
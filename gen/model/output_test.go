@@ -0,0 +1,95 @@
+package model
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoveUnusedImports(t *testing.T) {
+	src := "package poes\n\nimport (\n    \"time\"    \n\t\"encoding/json\"\n)\n\nimport \"strings\"\n\ntype A struct {\n\tB json.RawMessage\n}\n"
+	want := "package poes\n\nimport (\n\t\"encoding/json\"\n)\n\n\ntype A struct {\n\tB json.RawMessage\n}\n"
+	if got := string(removeUnusedImports([]byte(src))); got != want {
+		t.Errorf("removeUnusedImports() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeKeepRegions(t *testing.T) {
+	old := "package poes\n\ntype A struct{}\n\n// gosqlx:keep helpers\nfunc (a A) Name() string { return \"a\" }\n// gosqlx:keep end\n" +
+		"  // gosqlx:keep slot\n\tvar x = 1\n  // gosqlx:keep end\n"
+	regions, err := parseKeepRegions([]byte(old))
+	if err != nil {
+		t.Fatalf("parseKeepRegions() error = %v", err)
+	}
+
+	src := "package poes\n\ntype A struct{ ID int }\n// gosqlx:keep slot\n// gosqlx:keep end\n"
+	want := "package poes\n\ntype A struct{ ID int }\n// gosqlx:keep slot\n\tvar x = 1\n// gosqlx:keep end\n" +
+		"\n// gosqlx:keep helpers\nfunc (a A) Name() string { return \"a\" }\n// gosqlx:keep end\n"
+	if got := string(mergeKeepRegions([]byte(src), regions)); got != want {
+		t.Errorf("mergeKeepRegions() = %q, want %q", got, want)
+	}
+
+	for _, invalid := range []string{
+		"// gosqlx:keep a\n",
+		"// gosqlx:keep end\n",
+		"// gosqlx:keep a\n// gosqlx:keep b\n// gosqlx:keep end\n",
+	} {
+		if _, err := parseKeepRegions([]byte(invalid)); err == nil {
+			t.Errorf("parseKeepRegions(%q) 应返回错误", invalid)
+		}
+	}
+}
+
+// 测试重新生成时保留手写代码
+func TestSQLiteRegenerateKeepsCustomCode(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "gen.db")
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	config := &Config{DBType: "sqlite", DatabaseName: dbFile, OutputDir: dir, PackageName: "poes", SplitFiles: true}
+	if err := GenerateModels(config); err != nil {
+		t.Fatalf("生成模型失败: %v", err)
+	}
+	genFile := filepath.Join(dir, "poes", "users_gen.go")
+	customFile := filepath.Join(dir, "poes", "users.go")
+
+	custom := "package poes\n\nfunc (m *Users) DisplayName() string { return *m.Name }\n"
+	keep := "\n// gosqlx:keep scopes\nconst usersTable = \"users\"\n// gosqlx:keep end\n"
+	if err := os.WriteFile(customFile, []byte(custom), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	data, _ := os.ReadFile(genFile)
+	if err := os.WriteFile(genFile, append(data, keep...), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	// 表结构变化后重新生成
+	if _, err := db.Exec(`ALTER TABLE users ADD COLUMN email TEXT`); err != nil {
+		t.Fatalf("修改表失败: %v", err)
+	}
+	if err := GenerateModels(config); err != nil {
+		t.Fatalf("重新生成模型失败: %v", err)
+	}
+
+	data, _ = os.ReadFile(genFile)
+	if code := string(data); !strings.Contains(code, "Email") || !strings.Contains(code, keep) {
+		t.Errorf("重新生成的文件不正确:\n%s", code)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), genFile, data, 0); err != nil {
+		t.Errorf("生成的代码无法解析: %v", err)
+	}
+	if data, _ := os.ReadFile(customFile); string(data) != custom {
+		t.Errorf("手写文件被修改:\n%s", data)
+	}
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 标签名称的命名策略
+const (
+	NamingPreserve = "preserve" // 保留数据库列名
+	NamingSnake    = "snake"    // user_name
+	NamingCamel    = "camel"    // userName
+)
+
+// defaultTagStyles 未配置 TagStyles 时生成的标签
+var defaultTagStyles = []string{"json", "gorm"}
+
+// supportedTags 支持的标签及未指定命名策略时的默认策略
+var supportedTags = map[string]string{
+	"db":       NamingPreserve,
+	"gorm":     NamingPreserve,
+	"json":     NamingPreserve,
+	"xml":      NamingPreserve,
+	"bson":     NamingPreserve,
+	"protobuf": NamingSnake,
+}
+
+// tagStyle 一种结构体标签及其命名策略
+type tagStyle struct {
+	key    string
+	naming string
+}
+
+// parseTagStyles 解析 TagStyles 配置，格式为 标签[:命名策略]，如 json:camel
+func parseTagStyles(styles []string) ([]tagStyle, error) {
+	if len(styles) == 0 {
+		styles = defaultTagStyles
+	}
+	var result []tagStyle
+	seen := make(map[string]bool)
+	for _, style := range styles {
+		key, naming, _ := strings.Cut(strings.TrimSpace(style), ":")
+		key = strings.ToLower(key)
+		defaultNaming, ok := supportedTags[key]
+		if !ok {
+			return nil, fmt.Errorf("不支持的标签样式: %s", style)
+		}
+		switch naming = strings.ToLower(naming); naming {
+		case "":
+			naming = defaultNaming
+		case NamingPreserve, NamingSnake, NamingCamel:
+		default:
+			return nil, fmt.Errorf("不支持的命名策略: %s", style)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("重复的标签样式: %s", key)
+		}
+		seen[key] = true
+		result = append(result, tagStyle{key: key, naming: naming})
+	}
+	return result, nil
+}
+
+// tagField 生成结构体标签所需的字段信息
+type tagField struct {
+	Name      string            // 原始名称（列名或字段路径）
+	Preserve  map[string]string // preserve 策略下各标签使用的名称，未设置时使用 Name
+	GoType    string            // Go类型，用于推断 protobuf 编码类型
+	Number    int               // protobuf 字段编号，0 表示不输出 protobuf 标签
+	GormTag   string            // GORM标签内容
+	OmitEmpty bool              // json/xml/bson 标签是否追加 omitempty
+	Virtual   bool              // 不是数据库列（如关联字段），db 标签输出 "-"
+}
+
+// renderTags 按标签样式生成结构体标签内容（不含反引号）
+func renderTags(styles []tagStyle, field tagField) string {
+	var tags []string
+	for _, style := range styles {
+		name := field.Name
+		switch style.naming {
+		case NamingSnake:
+			name = toSnake(field.Name)
+		case NamingCamel:
+			name = toLowerCamel(field.Name)
+		default:
+			if preserved, ok := field.Preserve[style.key]; ok {
+				name = preserved
+			}
+		}
+
+		switch style.key {
+		case "gorm":
+			if field.GormTag != "" {
+				tags = append(tags, fmt.Sprintf(`gorm:"%s"`, field.GormTag))
+			}
+		case "db":
+			if field.Virtual {
+				name = "-"
+			}
+			tags = append(tags, fmt.Sprintf(`db:"%s"`, name))
+		case "protobuf":
+			if field.Number > 0 {
+				tags = append(tags, fmt.Sprintf(`protobuf:"%s,%d,opt,name=%s,proto3"`, protobufWireType(field.GoType), field.Number, name))
+			}
+		default:
+			if field.OmitEmpty {
+				name += ",omitempty"
+			}
+			tags = append(tags, fmt.Sprintf(`%s:"%s"`, style.key, name))
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// protobufWireType 根据Go类型推断 protobuf 编码类型
+func protobufWireType(goType string) string {
+	switch strings.TrimPrefix(goType, "*") {
+	case "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "varint"
+	case "float64":
+		return "fixed64"
+	case "float32":
+		return "fixed32"
+	}
+	return "bytes"
+}
+
+// applyTags 按配置为所有列和关联字段生成结构体标签
+func applyTags(config *Config, tables []*TableInfo) error {
+	styles, err := parseTagStyles(config.TagStyles)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		for i := range table.Columns {
+			col := &table.Columns[i]
+			col.Tags = renderTags(styles, tagField{
+				Name:     col.ColumnName,
+				Preserve: map[string]string{"json": col.JsonTag},
+				GoType:   col.GoType,
+				Number:   i + 1,
+				GormTag:  col.GormTag,
+			})
+		}
+		for i := range table.Relations {
+			rel := &table.Relations[i]
+			rel.Tags = renderTags(styles, tagField{
+				Name:      rel.JsonTag,
+				GoType:    rel.GoType,
+				GormTag:   rel.GormTag,
+				OmitEmpty: true,
+				Virtual:   true,
+			})
+		}
+	}
+	return nil
+}
+
+// toLowerCamel 下划线或驼峰名称转为首字母小写的驼峰，开头的连续大写视为一个单词，如 HTTPServer => httpServer
+func toLowerCamel(s string) string {
+	// 全大写的名称（如 Oracle 列名）先转小写
+	if strings.ToUpper(s) == s {
+		s = strings.ToLower(s)
+	}
+	runes := []rune(s)
+	lead := 0
+	for lead < len(runes) && unicode.IsUpper(runes[lead]) {
+		lead++
+	}
+	if lead > 1 && lead < len(runes) && unicode.IsLower(runes[lead]) {
+		lead--
+	}
+	for i := 0; i < lead; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	var b strings.Builder
+	upper := false
+	for _, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			upper = b.Len() > 0
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestRenderTags(t *testing.T) {
+	styles, err := parseTagStyles([]string{"db", "gorm", "json:camel", "xml:snake", "protobuf"})
+	if err != nil {
+		t.Fatalf("parseTagStyles() error = %v", err)
+	}
+
+	got := renderTags(styles, tagField{Name: "UserID", GoType: "int64", Number: 3, GormTag: "column:UserID;"})
+	want := `db:"UserID" gorm:"column:UserID;" json:"userID" xml:"user_id" protobuf:"varint,3,opt,name=user_id,proto3"`
+	if got != want {
+		t.Errorf("renderTags() = %s, want %s", got, want)
+	}
+
+	// 关联字段不是数据库列，不输出 protobuf 标签
+	got = renderTags(styles, tagField{Name: "order_items", GoType: "[]OrderItems", GormTag: "foreignKey:OrderId", OmitEmpty: true, Virtual: true})
+	want = `db:"-" gorm:"foreignKey:OrderId" json:"orderItems,omitempty" xml:"order_items,omitempty"`
+	if got != want {
+		t.Errorf("renderTags() = %s, want %s", got, want)
+	}
+
+	// 默认与原有输出一致
+	styles, _ = parseTagStyles(nil)
+	got = renderTags(styles, tagField{Name: "USER_NAME", Preserve: map[string]string{"json": "user_name"}, GormTag: "column:USER_NAME;"})
+	if want := `json:"user_name" gorm:"column:USER_NAME;"`; got != want {
+		t.Errorf("renderTags() = %s, want %s", got, want)
+	}
+
+	for _, invalid := range [][]string{{"yaml"}, {"json:kebab"}, {"json", "JSON:camel"}} {
+		if _, err := parseTagStyles(invalid); err == nil {
+			t.Errorf("parseTagStyles(%v) 应返回错误", invalid)
+		}
+	}
+}
+
+func TestNaming(t *testing.T) {
+	for _, c := range []struct{ in, snake, camel string }{
+		{"user_name", "user_name", "userName"},
+		{"USER_NAME", "user_name", "userName"},
+		{"UserID", "user_id", "userID"},
+		{"ID", "id", "id"},
+		{"HTTPServer", "http_server", "httpServer"},
+		{"createdAt", "created_at", "createdAt"},
+	} {
+		if got := toSnake(c.in); got != c.snake {
+			t.Errorf("toSnake(%q) = %q, want %q", c.in, got, c.snake)
+		}
+		if got := toLowerCamel(c.in); got != c.camel {
+			t.Errorf("toLowerCamel(%q) = %q, want %q", c.in, got, c.camel)
+		}
+	}
+}
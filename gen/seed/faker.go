@@ -0,0 +1,139 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 随机数据词库
+var (
+	firstNames = []string{"James", "Mary", "John", "Linda", "Robert", "Emma", "Wei", "Fang", "Lei", "Min", "Hiro", "Sofia"}
+	lastNames  = []string{"Smith", "Johnson", "Brown", "Wang", "Li", "Zhang", "Chen", "Garcia", "Miller", "Tanaka"}
+	words      = []string{"alpha", "beta", "gamma", "delta", "data", "cloud", "report", "order", "market", "service",
+		"system", "user", "value", "query", "table", "index", "stream", "event", "record", "status"}
+	domains = []string{"example.com", "example.org", "test.com", "mail.test"}
+	cities  = []string{"Beijing", "Shanghai", "Shenzhen", "London", "Paris", "Tokyo", "Berlin", "New York"}
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fakeValue 根据列名和Go类型生成随机值，size为字符串最大长度（0表示不限制）
+// 返回无效值表示不支持的类型
+func fakeValue(r *rand.Rand, column string, typ reflect.Type, size int) reflect.Value {
+	name := strings.ToLower(column)
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		elem := fakeValue(r, column, typ.Elem(), size)
+		if !elem.IsValid() {
+			return elem
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr
+	case reflect.String:
+		return reflect.ValueOf(truncate(fakeString(r, name), size)).Convert(typ)
+	case reflect.Bool:
+		return reflect.ValueOf(r.Intn(2) == 1).Convert(typ)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := fakeInt(r, name)
+		if max := int64(1)<<(typ.Bits()-1) - 1; v > max {
+			v %= max
+		}
+		return reflect.ValueOf(v).Convert(typ)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := uint64(fakeInt(r, name))
+		if typ.Bits() < 64 {
+			v %= uint64(1) << typ.Bits()
+		}
+		return reflect.ValueOf(v).Convert(typ)
+	case reflect.Float32, reflect.Float64:
+		v := math.Round(r.Float64()*100000) / 100
+		return reflect.ValueOf(v).Convert(typ)
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, 16)
+			r.Read(b)
+			return reflect.ValueOf(b).Convert(typ)
+		}
+	case reflect.Struct:
+		if typ == timeType || typ.ConvertibleTo(timeType) && timeType.ConvertibleTo(typ) {
+			t := time.Now().Add(-time.Duration(r.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+			return reflect.ValueOf(t).Convert(typ)
+		}
+	}
+	return reflect.Value{}
+}
+
+// fakeString 根据列名生成字符串
+func fakeString(r *rand.Rand, name string) string {
+	pick := func(list []string) string { return list[r.Intn(len(list))] }
+
+	switch {
+	case strings.Contains(name, "email"):
+		return fmt.Sprintf("%s.%s%d@%s", strings.ToLower(pick(firstNames)), strings.ToLower(pick(lastNames)), r.Intn(10000), pick(domains))
+	case strings.Contains(name, "phone") || strings.Contains(name, "mobile"):
+		return fmt.Sprintf("1%d%09d", 3+r.Intn(7), r.Intn(1000000000))
+	case strings.Contains(name, "url") || strings.Contains(name, "link") || strings.Contains(name, "avatar"):
+		return fmt.Sprintf("https://%s/%s/%d", pick(domains), pick(words), r.Intn(100000))
+	case name == "ip" || strings.HasSuffix(name, "_ip"):
+		return fmt.Sprintf("%d.%d.%d.%d", 1+r.Intn(223), r.Intn(256), r.Intn(256), 1+r.Intn(254))
+	case strings.Contains(name, "uuid") || strings.Contains(name, "guid"):
+		return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", r.Uint32(), r.Intn(1<<16), r.Intn(1<<16), r.Intn(1<<16), r.Int63n(1<<48))
+	case strings.Contains(name, "city") || strings.Contains(name, "address"):
+		return fmt.Sprintf("%d %s Road, %s", 1+r.Intn(999), pick(lastNames), pick(cities))
+	case strings.Contains(name, "username") || strings.Contains(name, "nick") || strings.Contains(name, "account"):
+		return fmt.Sprintf("%s%d", strings.ToLower(pick(firstNames)), r.Intn(100000))
+	case strings.Contains(name, "name"):
+		return pick(firstNames) + " " + pick(lastNames)
+	case strings.Contains(name, "title") || strings.Contains(name, "subject"):
+		return sentence(r, 3+r.Intn(4))
+	case strings.Contains(name, "content") || strings.Contains(name, "desc") || strings.Contains(name, "remark") ||
+		strings.Contains(name, "comment") || strings.Contains(name, "note"):
+		return sentence(r, 10+r.Intn(20))
+	case strings.Contains(name, "code") || strings.Contains(name, "no"):
+		return fmt.Sprintf("%s%08d", strings.ToUpper(pick(words)[:2]), r.Intn(100000000))
+	}
+	return fmt.Sprintf("%s_%d", pick(words), r.Intn(100000))
+}
+
+// fakeInt 根据列名生成整数
+func fakeInt(r *rand.Rand, name string) int64 {
+	switch {
+	case strings.Contains(name, "status") || strings.Contains(name, "type") || strings.Contains(name, "state"):
+		return int64(r.Intn(4))
+	case name == "age" || strings.HasSuffix(name, "_age"):
+		return int64(18 + r.Intn(63))
+	case strings.HasPrefix(name, "is_") || strings.HasSuffix(name, "_flag"):
+		return int64(r.Intn(2))
+	case strings.Contains(name, "year"):
+		return int64(2000 + r.Intn(30))
+	}
+	return 1 + r.Int63n(100000)
+}
+
+// sentence 生成由随机单词组成的句子
+func sentence(r *rand.Rand, n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = words[r.Intn(len(words))]
+	}
+	parts[0] = strings.ToUpper(parts[0][:1]) + parts[0][1:]
+	return strings.Join(parts, " ")
+}
+
+// truncate 按字符数截断字符串
+func truncate(s string, size int) string {
+	if size <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) > size {
+		return string(runes[:size])
+	}
+	return s
+}
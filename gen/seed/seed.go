@@ -0,0 +1,250 @@
+// Package seed 根据生成的模型批量写入随机测试数据，用于压测环境准备
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ValueFunc 自定义列值生成函数，row为当前行序号（从0开始）
+type ValueFunc func(r *rand.Rand, row int) interface{}
+
+// Config 数据填充配置
+type Config struct {
+	Rows      int            // 每张表默认写入行数
+	TableRows map[string]int // 指定表的写入行数
+	BatchSize int            // 批量写入大小
+	Seed      int64          // 随机种子，0时使用当前时间
+	NullRate  float64        // 可空列生成NULL的概率
+}
+
+// Seeder 数据填充器
+type Seeder struct {
+	db        *gorm.DB
+	config    *Config
+	rand      *rand.Rand
+	overrides map[string]ValueFunc       // 列值覆盖，键为 "表.列" 或 "列"
+	refs      map[string]string          // 外键引用，键为 "表.列"，值为 "表.列"
+	pool      map[string][]reflect.Value // 已写入的引用列值
+}
+
+// NewSeeder 创建数据填充器
+func NewSeeder(db *gorm.DB, config *Config) *Seeder {
+	if config == nil {
+		config = &Config{}
+	}
+	if config.Rows <= 0 {
+		config.Rows = 100
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Seeder{
+		db:        db,
+		config:    config,
+		rand:      rand.New(rand.NewSource(seed)),
+		overrides: make(map[string]ValueFunc),
+		refs:      make(map[string]string),
+		pool:      make(map[string][]reflect.Value),
+	}
+}
+
+// Override 设置列值生成函数
+// 示例: seeder.Override("users.status", func(r *rand.Rand, row int) interface{} { return 1 })
+func (s *Seeder) Override(column string, fn ValueFunc) *Seeder {
+	s.overrides[strings.ToLower(column)] = fn
+	return s
+}
+
+// Reference 声明外键引用，引用列的值从已写入的目标列中随机选取
+// 模型中声明的 belongs to 关联会自动识别
+// 示例: seeder.Reference("articles.user_id", "users.id")
+func (s *Seeder) Reference(column, target string) *Seeder {
+	s.refs[strings.ToLower(column)] = strings.ToLower(target)
+	return s
+}
+
+// Seed 按顺序为模型写入随机数据，被引用的模型需排在前面
+func (s *Seeder) Seed(models ...interface{}) error {
+	schemas := make([]*schema.Schema, 0, len(models))
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: s.db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("解析模型失败: %w", err)
+		}
+		schemas = append(schemas, stmt.Schema)
+
+		// 识别模型关联中的外键
+		for _, rel := range stmt.Schema.Relationships.BelongsTo {
+			for _, ref := range rel.References {
+				if ref.ForeignKey == nil || ref.PrimaryKey == nil {
+					continue
+				}
+				key := strings.ToLower(stmt.Schema.Table + "." + ref.ForeignKey.DBName)
+				if _, ok := s.refs[key]; !ok {
+					s.refs[key] = strings.ToLower(rel.FieldSchema.Table + "." + ref.PrimaryKey.DBName)
+				}
+			}
+		}
+	}
+
+	for _, sch := range schemas {
+		if err := s.seedTable(sch); err != nil {
+			return fmt.Errorf("填充表 %s 失败: %w", sch.Table, err)
+		}
+	}
+	return nil
+}
+
+// seedTable 为单表生成并写入数据
+func (s *Seeder) seedTable(sch *schema.Schema) error {
+	ctx := context.Background()
+	rows := s.config.Rows
+	if n, ok := s.config.TableRows[sch.Table]; ok {
+		rows = n
+	}
+	if rows <= 0 {
+		return nil
+	}
+
+	uniques := s.uniqueColumns(sch)
+	seen := make(map[string]map[string]bool, len(uniques))
+	for column := range uniques {
+		seen[column] = make(map[string]bool)
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(reflect.PointerTo(sch.ModelType)), 0, rows)
+	for row := 0; row < rows; row++ {
+		item := reflect.New(sch.ModelType)
+		for _, field := range sch.Fields {
+			if field.DBName == "" || !field.Creatable || field.AutoIncrement ||
+				field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+				continue
+			}
+			if err := s.fill(ctx, sch, field, item, row, seen[field.DBName]); err != nil {
+				return err
+			}
+		}
+		slice = reflect.Append(slice, item)
+	}
+
+	if err := s.db.Table(sch.Table).CreateInBatches(slice.Interface(), s.config.BatchSize).Error; err != nil {
+		return err
+	}
+
+	// 记录被引用列的值，供后续表的外键使用
+	targets := make(map[string]bool, len(s.refs))
+	for _, target := range s.refs {
+		targets[target] = true
+	}
+	for _, field := range sch.Fields {
+		key := strings.ToLower(sch.Table + "." + field.DBName)
+		if field.DBName == "" || !targets[key] {
+			continue
+		}
+		for i := 0; i < slice.Len(); i++ {
+			s.pool[key] = append(s.pool[key], field.ReflectValueOf(ctx, slice.Index(i)))
+		}
+	}
+	return nil
+}
+
+// fill 为单个字段赋值
+func (s *Seeder) fill(ctx context.Context, sch *schema.Schema, field *schema.Field, item reflect.Value, row int, seen map[string]bool) error {
+	key := strings.ToLower(sch.Table + "." + field.DBName)
+
+	// 自定义生成函数
+	fn, ok := s.overrides[key]
+	if !ok {
+		fn, ok = s.overrides[strings.ToLower(field.DBName)]
+	}
+	if ok {
+		return field.Set(ctx, item, fn(s.rand, row))
+	}
+
+	// 外键引用
+	if target, ok := s.refs[key]; ok {
+		values := s.pool[target]
+		if len(values) == 0 {
+			return fmt.Errorf("列 %s 引用的 %s 没有可用数据，请先填充被引用的表", key, target)
+		}
+		return field.Set(ctx, item, values[s.rand.Intn(len(values))].Interface())
+	}
+
+	// 可空列按比例置空
+	if !field.NotNull && !field.PrimaryKey && seen == nil && s.config.NullRate > 0 &&
+		field.FieldType.Kind() == reflect.Ptr && s.rand.Float64() < s.config.NullRate {
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		value := fakeValue(s.rand, field.DBName, field.FieldType, field.Size)
+		if !value.IsValid() {
+			return nil // 不支持的类型保持零值
+		}
+		if seen != nil {
+			if attempt >= 10 {
+				value = uniqueFallback(value, row)
+			}
+			text := fmt.Sprint(reflect.Indirect(value).Interface())
+			if seen[text] {
+				if attempt < 20 {
+					continue
+				}
+				return fmt.Errorf("列 %s 无法生成唯一值", key)
+			}
+			seen[text] = true
+		}
+		field.ReflectValueOf(ctx, item).Set(value)
+		return nil
+	}
+}
+
+// uniqueColumns 获取需要保证唯一的列
+func (s *Seeder) uniqueColumns(sch *schema.Schema) map[string]bool {
+	columns := make(map[string]bool)
+	for _, field := range sch.Fields {
+		if field.DBName != "" && (field.Unique || field.PrimaryKey && !field.AutoIncrement) {
+			columns[field.DBName] = true
+		}
+	}
+	for _, idx := range sch.ParseIndexes() {
+		if idx.Class == "UNIQUE" && len(idx.Fields) == 1 {
+			columns[idx.Fields[0].DBName] = true
+		}
+	}
+	return columns
+}
+
+// uniqueFallback 随机值多次重复时，结合行序号生成唯一值
+func uniqueFallback(value reflect.Value, row int) reflect.Value {
+	if value.Kind() == reflect.Ptr {
+		elem := uniqueFallback(value.Elem(), row)
+		ptr := reflect.New(elem.Type())
+		ptr.Elem().Set(elem)
+		return ptr
+	}
+
+	v := reflect.New(value.Type()).Elem()
+	v.Set(value)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprintf("%s_%d", v.String(), row))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(row) + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(row) + 1)
+	}
+	return v
+}
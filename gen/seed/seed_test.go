@@ -0,0 +1,88 @@
+package seed
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type seedUser struct {
+	ID        uint      `gorm:"column:id;primaryKey;autoIncrement"`
+	Email     string    `gorm:"column:email;size:64;unique;not null"`
+	Name      string    `gorm:"column:name;size:10"`
+	Age       int       `gorm:"column:age"`
+	Status    int8      `gorm:"column:status"`
+	Nickname  *string   `gorm:"column:nickname"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (seedUser) TableName() string { return "users" }
+
+type seedArticle struct {
+	ID     uint     `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID uint     `gorm:"column:user_id"`
+	Title  string   `gorm:"column:title;size:32"`
+	User   seedUser `gorm:"foreignKey:UserID"`
+}
+
+func (seedArticle) TableName() string { return "articles" }
+
+type seedComment struct {
+	ID        uint   `gorm:"column:id;primaryKey;autoIncrement"`
+	ArticleID uint   `gorm:"column:article_id"`
+	Content   string `gorm:"column:content"`
+}
+
+func (seedComment) TableName() string { return "comments" }
+
+// 测试随机数据填充
+func TestSeed(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&seedUser{}, &seedArticle{}, &seedComment{}); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	seeder := NewSeeder(db, &Config{Rows: 50, TableRows: map[string]int{"comments": 80}, BatchSize: 20, Seed: 1, NullRate: 0.5}).
+		Override("users.status", func(r *rand.Rand, row int) interface{} { return 1 }).
+		Reference("comments.article_id", "articles.id")
+	if err := seeder.Seed(&seedUser{}, &seedArticle{}, &seedComment{}); err != nil {
+		t.Fatalf("填充数据失败: %v", err)
+	}
+
+	var count int64
+	db.Table("users").Count(&count)
+	if count != 50 {
+		t.Errorf("期望用户数为 50，实际为 %d", count)
+	}
+	db.Table("users").Where("status <> 1 OR LENGTH(name) > 10").Count(&count)
+	if count != 0 {
+		t.Errorf("覆盖值或长度限制未生效，异常行数为 %d", count)
+	}
+	db.Table("users").Distinct("email").Count(&count)
+	if count != 50 {
+		t.Errorf("期望邮箱唯一，实际不同值数量为 %d", count)
+	}
+
+	// 外键必须指向已存在的数据
+	db.Table("articles").Where("user_id NOT IN (SELECT id FROM users)").Count(&count)
+	if count != 0 {
+		t.Errorf("文章存在无效的用户引用: %d", count)
+	}
+	db.Table("comments").Count(&count)
+	if count != 80 {
+		t.Errorf("期望评论数为 80，实际为 %d", count)
+	}
+	db.Table("comments").Where("article_id NOT IN (SELECT id FROM articles)").Count(&count)
+	if count != 0 {
+		t.Errorf("评论存在无效的文章引用: %d", count)
+	}
+}
@@ -0,0 +1,237 @@
+// Package geo 空间数据类型，支持 MySQL 空间类型、PostGIS 和 SQLServer geography
+//
+// Point、LineString、Polygon 可直接作为模型字段使用：写入时根据方言生成 ST_GeomFromText 等构造表达式，
+// 读取时自动识别 WKT/EWKT、WKB/EWKB（含 PostGIS 返回的十六进制文本）和 MySQL 内部存储格式。
+// 坐标约定 X 为经度、Y 为纬度
+//
+//	type Store struct {
+//		ID       int64
+//		Location geo.Point
+//	}
+//	db.Create(&Store{Location: geo.NewPoint(116.397, 39.908)})
+package geo
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultSRID 未指定 SRID 时写入使用的空间参考（WGS 84）
+var DefaultSRID = 4326
+
+// ErrUnsupportedGeometry 不支持的空间数据
+var ErrUnsupportedGeometry = errors.New("不支持的空间数据")
+
+// Geometry 空间对象
+type Geometry interface {
+	// WKT 返回 WKT 文本，如 POINT(116.397 39.908)
+	WKT() string
+	// WKB 返回小端序 WKB
+	WKB() []byte
+	// GetSRID 返回空间参考，0 表示未指定
+	GetSRID() int
+}
+
+// Point 点，X 为经度，Y 为纬度
+type Point struct {
+	X, Y float64
+	SRID int
+}
+
+// NewPoint 创建 WGS 84 坐标点
+func NewPoint(lng, lat float64) Point {
+	return Point{X: lng, Y: lat, SRID: DefaultSRID}
+}
+
+// Lng 经度
+func (p Point) Lng() float64 { return p.X }
+
+// Lat 纬度
+func (p Point) Lat() float64 { return p.Y }
+
+// LineString 折线
+type LineString struct {
+	Points []Point
+	SRID   int
+}
+
+// Polygon 多边形，第一个环为外环，其余为内环（洞），每个环首尾坐标相同
+type Polygon struct {
+	Rings [][]Point
+	SRID  int
+}
+
+// ==================== WKT ====================
+
+// WKT 返回 WKT 文本
+func (p Point) WKT() string {
+	return "POINT(" + coords([]Point{p}) + ")"
+}
+
+// WKT 返回 WKT 文本
+func (l LineString) WKT() string {
+	return "LINESTRING(" + coords(l.Points) + ")"
+}
+
+// WKT 返回 WKT 文本
+func (p Polygon) WKT() string {
+	rings := make([]string, len(p.Rings))
+	for i, ring := range p.Rings {
+		rings[i] = "(" + coords(ring) + ")"
+	}
+	return "POLYGON(" + strings.Join(rings, ",") + ")"
+}
+
+// GetSRID 返回空间参考
+func (p Point) GetSRID() int { return p.SRID }
+
+// GetSRID 返回空间参考
+func (l LineString) GetSRID() int { return l.SRID }
+
+// GetSRID 返回空间参考
+func (p Polygon) GetSRID() int { return p.SRID }
+
+// String 返回 WKT 文本
+func (p Point) String() string { return p.WKT() }
+
+// String 返回 WKT 文本
+func (l LineString) String() string { return l.WKT() }
+
+// String 返回 WKT 文本
+func (p Polygon) String() string { return p.WKT() }
+
+// ==================== database/sql ====================
+
+// Scan 实现 sql.Scanner
+func (p *Point) Scan(src interface{}) error {
+	return scanInto(src, p)
+}
+
+// Scan 实现 sql.Scanner
+func (l *LineString) Scan(src interface{}) error {
+	return scanInto(src, l)
+}
+
+// Scan 实现 sql.Scanner
+func (p *Polygon) Scan(src interface{}) error {
+	return scanInto(src, p)
+}
+
+// Value 实现 driver.Valuer，返回 WKT 文本；原生SQL中需配合 ST_GeomFromText(?, srid) 使用
+func (p Point) Value() (driver.Value, error) { return p.WKT(), nil }
+
+// Value 实现 driver.Valuer，返回 WKT 文本
+func (l LineString) Value() (driver.Value, error) { return l.WKT(), nil }
+
+// Value 实现 driver.Valuer，返回 WKT 文本
+func (p Polygon) Value() (driver.Value, error) { return p.WKT(), nil }
+
+// scanInto 解析数据库返回的空间数据并赋值给 dest
+func scanInto(src interface{}, dest Geometry) error {
+	if src == nil {
+		return nil
+	}
+	g, err := Parse(src)
+	if err != nil {
+		return err
+	}
+	switch d := dest.(type) {
+	case *Point:
+		if v, ok := g.(Point); ok {
+			*d = v
+			return nil
+		}
+	case *LineString:
+		if v, ok := g.(LineString); ok {
+			*d = v
+			return nil
+		}
+	case *Polygon:
+		if v, ok := g.(Polygon); ok {
+			*d = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 无法将 %s 扫描到 %T", ErrUnsupportedGeometry, g.WKT(), dest)
+}
+
+// ==================== GORM ====================
+
+// GormDataType 通用数据类型
+func (Point) GormDataType() string { return "geometry" }
+
+// GormDataType 通用数据类型
+func (LineString) GormDataType() string { return "geometry" }
+
+// GormDataType 通用数据类型
+func (Polygon) GormDataType() string { return "geometry" }
+
+// GormDBDataType 各数据库的列类型
+func (Point) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return columnType(db, "POINT", "Point")
+}
+
+// GormDBDataType 各数据库的列类型
+func (LineString) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return columnType(db, "LINESTRING", "LineString")
+}
+
+// GormDBDataType 各数据库的列类型
+func (Polygon) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return columnType(db, "POLYGON", "Polygon")
+}
+
+// GormValue 根据方言生成写入表达式
+func (p Point) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return valueExpr(db, p)
+}
+
+// GormValue 根据方言生成写入表达式
+func (l LineString) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return valueExpr(db, l)
+}
+
+// GormValue 根据方言生成写入表达式
+func (p Polygon) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return valueExpr(db, p)
+}
+
+// columnType 返回列类型
+func columnType(db *gorm.DB, mysqlType, postgisType string) string {
+	switch db.Dialector.Name() {
+	case "mysql", "mariadb", "oceanbase":
+		return mysqlType
+	case "postgres":
+		return fmt.Sprintf("geometry(%s,%d)", postgisType, DefaultSRID)
+	case "sqlserver":
+		return "geography"
+	default:
+		return "TEXT"
+	}
+}
+
+// valueExpr 返回写入表达式
+func valueExpr(db *gorm.DB, g Geometry) clause.Expr {
+	srid := g.GetSRID()
+	if srid == 0 {
+		srid = DefaultSRID
+	}
+	switch db.Dialector.Name() {
+	case "mysql":
+		// MySQL 8 的地理坐标系默认纬度在前，显式声明经度在前
+		return clause.Expr{SQL: "ST_GeomFromText(?, ?, 'axis-order=long-lat')", Vars: []interface{}{g.WKT(), srid}}
+	case "mariadb", "oceanbase", "postgres":
+		return clause.Expr{SQL: "ST_GeomFromText(?, ?)", Vars: []interface{}{g.WKT(), srid}}
+	case "sqlserver":
+		return clause.Expr{SQL: "geography::STGeomFromText(?, ?)", Vars: []interface{}{g.WKT(), srid}}
+	default:
+		return clause.Expr{SQL: "?", Vars: []interface{}{g.WKT()}}
+	}
+}
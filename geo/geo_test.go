@@ -0,0 +1,83 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// 测试 WKT 输出与解析
+func TestWKT(t *testing.T) {
+	polygon := Polygon{Rings: [][]Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 0}}}}
+	tests := []struct {
+		geom Geometry
+		wkt  string
+	}{
+		{Point{X: 116.397, Y: 39.908}, "POINT(116.397 39.908)"},
+		{LineString{Points: []Point{{X: 1, Y: 2}, {X: 3.5, Y: -4}}}, "LINESTRING(1 2,3.5 -4)"},
+		{polygon, "POLYGON((0 0,10 0,10 10,0 0))"},
+	}
+	for _, tt := range tests {
+		if got := tt.geom.WKT(); got != tt.wkt {
+			t.Errorf("WKT() = %q, 期望 %q", got, tt.wkt)
+		}
+		g, err := ParseWKT(tt.wkt)
+		if err != nil || !reflect.DeepEqual(g, tt.geom) {
+			t.Errorf("ParseWKT(%q) = %v, %v", tt.wkt, g, err)
+		}
+	}
+
+	g, err := ParseWKT("SRID=4326;POINT(1 2)")
+	if err != nil || g != (Point{X: 1, Y: 2, SRID: 4326}) {
+		t.Errorf("解析 EWKT 失败: %v, %v", g, err)
+	}
+	if _, err := ParseWKT("CIRCLE(1 2)"); !errors.Is(err, ErrUnsupportedGeometry) {
+		t.Errorf("期望 ErrUnsupportedGeometry, 得到 %v", err)
+	}
+}
+
+// 测试 WKB、EWKB 与 MySQL 内部格式解析
+func TestParseWKB(t *testing.T) {
+	line := LineString{Points: []Point{{X: 1, Y: 2}, {X: 3, Y: 4}}}
+	g, err := Parse(line.WKB())
+	if err != nil || !reflect.DeepEqual(g, line) {
+		t.Errorf("解析 WKB 失败: %v, %v", g, err)
+	}
+
+	// PostGIS 返回的十六进制 EWKB: SRID=4326;POINT(1 2)
+	g, err = Parse("0101000020E6100000000000000000F03F0000000000000040")
+	if err != nil || g != (Point{X: 1, Y: 2, SRID: 4326}) {
+		t.Errorf("解析十六进制 EWKB 失败: %v, %v", g, err)
+	}
+
+	// MySQL 内部格式: SRID + WKB
+	point := Point{X: 116.397, Y: 39.908}
+	data := append(binary.LittleEndian.AppendUint32(nil, 4326), point.WKB()...)
+	g, err = Parse(data)
+	if err != nil || g != (Point{X: 116.397, Y: 39.908, SRID: 4326}) {
+		t.Errorf("解析 MySQL 格式失败: %v, %v", g, err)
+	}
+
+	// 截断的数据
+	raw, _ := hex.DecodeString("0101000000000000000000F03F")
+	if _, err := Parse(raw); !errors.Is(err, ErrUnsupportedGeometry) {
+		t.Errorf("期望 ErrUnsupportedGeometry, 得到 %v", err)
+	}
+}
+
+// 测试 Scan
+func TestScan(t *testing.T) {
+	var p Point
+	if err := p.Scan([]byte("POINT(1 2)")); err != nil || p != (Point{X: 1, Y: 2}) {
+		t.Errorf("Scan 失败: %v, %v", p, err)
+	}
+	var l LineString
+	if err := l.Scan("POINT(1 2)"); !errors.Is(err, ErrUnsupportedGeometry) {
+		t.Errorf("类型不匹配应返回错误, 得到 %v", err)
+	}
+	if err := p.Scan(nil); err != nil {
+		t.Errorf("Scan(nil) 失败: %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WKB 几何类型编号
+const (
+	wkbPoint      = 1
+	wkbLineString = 2
+	wkbPolygon    = 3
+
+	ewkbSRIDFlag = 0x20000000 // EWKB 携带 SRID
+	ewkbZMFlags  = 0xC0000000 // EWKB 携带 Z/M 坐标
+)
+
+// Parse 解析数据库返回的空间数据，自动识别 WKT/EWKT、WKB/EWKB、十六进制 EWKB 和 MySQL 内部格式
+func Parse(src interface{}) (Geometry, error) {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("%w: 无法解析 %T", ErrUnsupportedGeometry, src)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: 空数据", ErrUnsupportedGeometry)
+	}
+
+	// 文本格式: WKT、EWKT 或十六进制 EWKB
+	if text := strings.TrimSpace(string(data)); isText(data) {
+		if isHex(text) {
+			raw, err := hex.DecodeString(text)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrUnsupportedGeometry, err)
+			}
+			return ParseWKB(raw)
+		}
+		return ParseWKT(text)
+	}
+
+	// MySQL 内部格式: 4 字节小端 SRID + WKB
+	if len(data) > 4 && data[4] <= 1 {
+		if g, n, err := readWKB(data[4:]); err == nil && n == len(data)-4 {
+			return withSRID(g, int(binary.LittleEndian.Uint32(data[:4]))), nil
+		}
+	}
+	return ParseWKB(data)
+}
+
+// isText 判断是否为可打印文本
+func isText(data []byte) bool {
+	for _, c := range data {
+		if c < 0x20 && c != '\n' && c != '\r' && c != '\t' || c >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// isHex 判断是否为十六进制文本
+func isHex(text string) bool {
+	if len(text)%2 != 0 || len(text) < 10 {
+		return false
+	}
+	for _, c := range text {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================== WKT ====================
+
+// ParseWKT 解析 WKT 或 EWKT（SRID=4326;POINT(...)）
+func ParseWKT(text string) (Geometry, error) {
+	text = strings.TrimSpace(text)
+	srid := 0
+	if len(text) > 5 && strings.EqualFold(text[:5], "SRID=") {
+		head, body, found := strings.Cut(text, ";")
+		if !found {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+		}
+		value, err := strconv.Atoi(head[5:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+		}
+		srid, text = value, strings.TrimSpace(body)
+	}
+
+	open := strings.IndexByte(text, '(')
+	if open < 0 || !strings.HasSuffix(text, ")") {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+	}
+	kind := strings.ToUpper(strings.TrimSpace(text[:open]))
+	body := text[open+1 : len(text)-1]
+
+	switch kind {
+	case "POINT":
+		points, err := parseCoords(body)
+		if err != nil || len(points) != 1 {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+		}
+		points[0].SRID = srid
+		return points[0], nil
+	case "LINESTRING":
+		points, err := parseCoords(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+		}
+		return LineString{Points: points, SRID: srid}, nil
+	case "POLYGON":
+		var rings [][]Point
+		for _, part := range strings.Split(body, ")") {
+			part = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), ","))
+			if part == "" {
+				continue
+			}
+			points, err := parseCoords(strings.TrimPrefix(part, "("))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+			}
+			rings = append(rings, points)
+		}
+		return Polygon{Rings: rings, SRID: srid}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, text)
+}
+
+// parseCoords 解析 "x y, x y" 形式的坐标列表
+func parseCoords(body string) ([]Point, error) {
+	var points []Point
+	for _, pair := range strings.Split(body, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("坐标格式错误: %q", pair)
+		}
+		x, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		y, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, Point{X: x, Y: y})
+	}
+	return points, nil
+}
+
+// coords 格式化坐标列表
+func coords(points []Point) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = strconv.FormatFloat(p.X, 'f', -1, 64) + " " + strconv.FormatFloat(p.Y, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ==================== WKB ====================
+
+// ParseWKB 解析 WKB 或 EWKB
+func ParseWKB(data []byte) (Geometry, error) {
+	g, n, err := readWKB(data)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(data) {
+		return nil, fmt.Errorf("%w: WKB 存在多余数据", ErrUnsupportedGeometry)
+	}
+	return g, nil
+}
+
+// readWKB 读取一个几何对象，返回读取的字节数
+func readWKB(data []byte) (Geometry, int, error) {
+	r := &wkbReader{data: data}
+	if len(data) < 5 || data[0] > 1 {
+		return nil, 0, fmt.Errorf("%w: WKB 格式错误", ErrUnsupportedGeometry)
+	}
+	if data[0] == 0 {
+		r.order = binary.BigEndian
+	} else {
+		r.order = binary.LittleEndian
+	}
+	r.pos = 1
+
+	kind := r.uint32()
+	srid := 0
+	if kind&ewkbSRIDFlag != 0 {
+		srid = int(r.uint32())
+	}
+	if kind&ewkbZMFlags != 0 || kind&0x0FFFFFFF > 1000 {
+		return nil, 0, fmt.Errorf("%w: 不支持 Z/M 坐标", ErrUnsupportedGeometry)
+	}
+
+	var g Geometry
+	switch kind & 0x0FFFFFFF {
+	case wkbPoint:
+		p := r.point()
+		p.SRID = srid
+		g = p
+	case wkbLineString:
+		g = LineString{Points: r.points(), SRID: srid}
+	case wkbPolygon:
+		rings := make([][]Point, r.count())
+		for i := range rings {
+			rings[i] = r.points()
+		}
+		g = Polygon{Rings: rings, SRID: srid}
+	default:
+		return nil, 0, fmt.Errorf("%w: 几何类型 %d", ErrUnsupportedGeometry, kind&0x0FFFFFFF)
+	}
+	if r.err {
+		return nil, 0, fmt.Errorf("%w: WKB 数据不完整", ErrUnsupportedGeometry)
+	}
+	return g, r.pos, nil
+}
+
+// wkbReader WKB 读取器
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+	err   bool
+}
+
+func (r *wkbReader) uint32() uint32 {
+	if r.err || r.pos+4 > len(r.data) {
+		r.err = true
+		return 0
+	}
+	v := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v
+}
+
+func (r *wkbReader) float64() float64 {
+	if r.err || r.pos+8 > len(r.data) {
+		r.err = true
+		return 0
+	}
+	v := math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	r.pos += 8
+	return v
+}
+
+// count 读取元素数量，超过剩余数据可容纳的数量时视为错误
+func (r *wkbReader) count() int {
+	n := int(r.uint32())
+	if n > (len(r.data)-r.pos)/4 {
+		r.err = true
+		return 0
+	}
+	return n
+}
+
+func (r *wkbReader) point() Point {
+	return Point{X: r.float64(), Y: r.float64()}
+}
+
+func (r *wkbReader) points() []Point {
+	points := make([]Point, r.count())
+	for i := range points {
+		points[i] = r.point()
+	}
+	return points
+}
+
+// WKB 返回小端序 WKB
+func (p Point) WKB() []byte {
+	return newWKB(wkbPoint).point(p).bytes()
+}
+
+// WKB 返回小端序 WKB
+func (l LineString) WKB() []byte {
+	return newWKB(wkbLineString).points(l.Points).bytes()
+}
+
+// WKB 返回小端序 WKB
+func (p Polygon) WKB() []byte {
+	w := newWKB(wkbPolygon).uint32(uint32(len(p.Rings)))
+	for _, ring := range p.Rings {
+		w.points(ring)
+	}
+	return w.bytes()
+}
+
+// wkbWriter WKB 写入器
+type wkbWriter struct {
+	buf bytes.Buffer
+}
+
+func newWKB(kind uint32) *wkbWriter {
+	w := &wkbWriter{}
+	w.buf.WriteByte(1)
+	return w.uint32(kind)
+}
+
+func (w *wkbWriter) uint32(v uint32) *wkbWriter {
+	w.buf.Write(binary.LittleEndian.AppendUint32(nil, v))
+	return w
+}
+
+func (w *wkbWriter) point(p Point) *wkbWriter {
+	w.buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(p.X)))
+	w.buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(p.Y)))
+	return w
+}
+
+func (w *wkbWriter) points(points []Point) *wkbWriter {
+	w.uint32(uint32(len(points)))
+	for _, p := range points {
+		w.point(p)
+	}
+	return w
+}
+
+func (w *wkbWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// withSRID 设置几何对象的 SRID
+func withSRID(g Geometry, srid int) Geometry {
+	switch v := g.(type) {
+	case Point:
+		v.SRID = srid
+		return v
+	case LineString:
+		v.SRID = srid
+		return v
+	case Polygon:
+		v.SRID = srid
+		return v
+	}
+	return g
+}
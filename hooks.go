@@ -0,0 +1,206 @@
+package gosqlx
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 生命周期钩子 ====================
+//
+// 模型实现以下接口后，由 Database 的 Create/CreateInBatches/Save/Update/Updates/Delete 及
+// First/Take/Last/Find/QueryRows/ScanRaw 调用，与走 GORM 还是原生SQL无关，业务约束只需写在模型上。
+// 钩子以指针接收者实现，ctx 为当前语句的上下文；方法签名与 GORM 的钩子不同，GORM 不会重复调用。
+// Before 钩子返回错误时不执行写入；After 钩子返回错误时写入不会回滚，需要原子性时请在 Transaction 中调用。
+// UpdateColumn/UpdateColumns 与 GORM 一致，不触发钩子
+//
+//	func (u *User) BeforeCreate(ctx context.Context) error {
+//		u.CreatedBy = auth.UserFrom(ctx)
+//		return nil
+//	}
+
+// BeforeCreateHook 创建前钩子
+type BeforeCreateHook interface {
+	BeforeCreate(ctx context.Context) error
+}
+
+// AfterCreateHook 创建后钩子
+type AfterCreateHook interface {
+	AfterCreate(ctx context.Context) error
+}
+
+// BeforeUpdateHook 更新前钩子
+type BeforeUpdateHook interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdateHook 更新后钩子
+type AfterUpdateHook interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// BeforeDeleteHook 删除前钩子
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterDeleteHook 删除后钩子
+type AfterDeleteHook interface {
+	AfterDelete(ctx context.Context) error
+}
+
+// AfterFindHook 查询后钩子，对每条扫描出的记录调用
+type AfterFindHook interface {
+	AfterFind(ctx context.Context) error
+}
+
+// hookStage 钩子阶段
+type hookStage int
+
+const (
+	beforeCreate hookStage = iota
+	afterCreate
+	beforeUpdate
+	afterUpdate
+	beforeDelete
+	afterDelete
+	afterFind
+)
+
+// callHook 调用单个模型的钩子
+func callHook(ctx context.Context, stage hookStage, model interface{}) error {
+	switch stage {
+	case beforeCreate:
+		if h, ok := model.(BeforeCreateHook); ok {
+			return h.BeforeCreate(ctx)
+		}
+	case afterCreate:
+		if h, ok := model.(AfterCreateHook); ok {
+			return h.AfterCreate(ctx)
+		}
+	case beforeUpdate:
+		if h, ok := model.(BeforeUpdateHook); ok {
+			return h.BeforeUpdate(ctx)
+		}
+	case afterUpdate:
+		if h, ok := model.(AfterUpdateHook); ok {
+			return h.AfterUpdate(ctx)
+		}
+	case beforeDelete:
+		if h, ok := model.(BeforeDeleteHook); ok {
+			return h.BeforeDelete(ctx)
+		}
+	case afterDelete:
+		if h, ok := model.(AfterDeleteHook); ok {
+			return h.AfterDelete(ctx)
+		}
+	case afterFind:
+		if h, ok := model.(AfterFindHook); ok {
+			return h.AfterFind(ctx)
+		}
+	}
+	return nil
+}
+
+// hookInterfaces 各阶段钩子的接口类型
+var hookInterfaces = [...]reflect.Type{
+	beforeCreate: reflect.TypeOf((*BeforeCreateHook)(nil)).Elem(),
+	afterCreate:  reflect.TypeOf((*AfterCreateHook)(nil)).Elem(),
+	beforeUpdate: reflect.TypeOf((*BeforeUpdateHook)(nil)).Elem(),
+	afterUpdate:  reflect.TypeOf((*AfterUpdateHook)(nil)).Elem(),
+	beforeDelete: reflect.TypeOf((*BeforeDeleteHook)(nil)).Elem(),
+	afterDelete:  reflect.TypeOf((*AfterDeleteHook)(nil)).Elem(),
+	afterFind:    reflect.TypeOf((*AfterFindHook)(nil)).Elem(),
+}
+
+// hookTypeKey 钩子判断结果的缓存键
+type hookTypeKey struct {
+	typ   reflect.Type
+	stage hookStage
+}
+
+// hookTypes 模型类型和阶段 -> 是否实现钩子，每种类型只判断一次
+var hookTypes sync.Map
+
+// hasHook 判断 typ（模型、模型指针或切片）的元素是否实现 stage 的钩子，
+// 没有钩子时 runHooks 不再逐行反射；元素为接口时无法预先判断，返回 true
+func hasHook(typ reflect.Type, stage hookStage) bool {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return typ.Kind() == reflect.Interface
+	}
+	key := hookTypeKey{typ: typ, stage: stage}
+	if ok, found := hookTypes.Load(key); found {
+		return ok.(bool)
+	}
+	ok := reflect.PointerTo(typ).Implements(hookInterfaces[stage])
+	hookTypes.Store(key, ok)
+	return ok
+}
+
+// runHooks 对 value 中的每个模型（结构体指针、切片或切片指针）依次调用钩子
+func (d *Database) runHooks(stage hookStage, value interface{}) error {
+	if value == nil || !hasHook(reflect.TypeOf(value), stage) {
+		return nil
+	}
+	ctx := d.db.Statement.Context
+	return eachModel(reflect.ValueOf(value), func(model interface{}) error {
+		return callHook(ctx, stage, model)
+	})
+}
+
+// eachModel 遍历可寻址的结构体元素
+func eachModel(v reflect.Value, fn func(model interface{}) error) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+			return fn(v.Interface())
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Struct && elem.CanAddr() {
+				elem = elem.Addr()
+			}
+			if err := eachModel(elem, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveStages 按主键是否为零值将 Save 的记录拆分为创建和更新两组
+func (d *Database) saveStages(value interface{}) (creates, updates []interface{}) {
+	stmt := &gorm.Statement{DB: d.db}
+	parsed := stmt.Parse(value) == nil && stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil
+	ctx := d.db.Statement.Context
+	_ = eachModel(reflect.ValueOf(value), func(model interface{}) error {
+		if parsed {
+			if _, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(ctx, reflect.ValueOf(model).Elem()); zero {
+				creates = append(creates, model)
+				return nil
+			}
+		}
+		updates = append(updates, model)
+		return nil
+	})
+	return creates, updates
+}
+
+// runSaveHooks 按记录是创建还是更新调用对应的钩子
+func (d *Database) runSaveHooks(creates, updates []interface{}, createStage, updateStage hookStage) error {
+	if err := d.runHooks(createStage, creates); err != nil {
+		return err
+	}
+	return d.runHooks(updateStage, updates)
+}
@@ -0,0 +1,169 @@
+// Package idgen 全局ID生成：雪花算法、数据库序列和号段（ticket表）生成器
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/dialect"
+	"gorm.io/gorm"
+)
+
+// IDGenerator ID生成器接口
+type IDGenerator interface {
+	// Next 生成下一个ID
+	Next() (int64, error)
+}
+
+// 确保各生成器实现 IDGenerator 接口
+var (
+	_ IDGenerator = (*Snowflake)(nil)
+	_ IDGenerator = (*Sequence)(nil)
+	_ IDGenerator = (*Ticket)(nil)
+)
+
+// TicketTableName 号段表名
+const TicketTableName = "gosqlx_id_tickets"
+
+// ==================== 数据库序列 ====================
+
+// Sequence 基于数据库序列的ID生成器（Postgres/Oracle/SQLServer）
+type Sequence struct {
+	db  *gosqlx.Database
+	sql string
+}
+
+// NewSequence 创建序列ID生成器，序列需预先创建
+func NewSequence(db *gosqlx.Database, sequence string) (*Sequence, error) {
+	switch db.Type() {
+	case gosqlx.PostgresSQL, gosqlx.Oracle, gosqlx.SQLServer:
+	default:
+		return nil, fmt.Errorf("%s 不支持序列，请使用 NewTicket", db.Type())
+	}
+	return &Sequence{
+		db:  db,
+		sql: dialect.GetDialect(string(db.Type())).GetSequenceSQL(sequence),
+	}, nil
+}
+
+// Next 获取序列的下一个值
+func (s *Sequence) Next() (int64, error) {
+	var id int64
+	if err := s.db.DB().Raw(s.sql).Row().Scan(&id); err != nil {
+		return 0, fmt.Errorf("获取序列值失败: %w", err)
+	}
+	return id, nil
+}
+
+// ==================== 号段表 ====================
+
+// ticketRecord 号段表记录
+type ticketRecord struct {
+	Name  string `gorm:"column:name;primaryKey;size:64"`
+	Value int64  `gorm:"column:value;not null"`
+}
+
+// TableName 号段表名
+func (ticketRecord) TableName() string {
+	return TicketTableName
+}
+
+// Ticket 基于号段表的ID生成器，每次从数据库申请 step 个ID在内存中分配
+// MySQL 系使用 LAST_INSERT_ID 原子递增，其他数据库使用事务更新
+type Ticket struct {
+	db    *gosqlx.Database
+	name  string
+	step  int64
+	mutex sync.Mutex
+	next  int64 // 下一个可分配的ID
+	max   int64 // 当前号段的最大ID
+}
+
+// NewTicket 创建号段ID生成器，号段表不存在时自动创建
+func NewTicket(db *gosqlx.Database, name string, step int64) (*Ticket, error) {
+	if step <= 0 {
+		step = 1
+	}
+	migrator := db.DB().Migrator()
+	if !migrator.HasTable(&ticketRecord{}) {
+		if err := migrator.CreateTable(&ticketRecord{}); err != nil {
+			return nil, fmt.Errorf("创建号段表失败: %w", err)
+		}
+	}
+	return &Ticket{db: db, name: name, step: step, next: 1, max: 0}, nil
+}
+
+// Next 生成下一个ID
+func (t *Ticket) Next() (int64, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.next > t.max {
+		max, err := t.allocate()
+		if err != nil {
+			return 0, err
+		}
+		t.next, t.max = max-t.step+1, max
+	}
+	id := t.next
+	t.next++
+	return id, nil
+}
+
+// allocate 申请新号段，返回号段的最大ID
+func (t *Ticket) allocate() (int64, error) {
+	switch t.db.Type() {
+	case gosqlx.MySQL, gosqlx.MariaDB, gosqlx.TiDB, gosqlx.OceanBase:
+		return t.allocateMySQL()
+	}
+
+	var max int64
+	err := t.db.DB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&ticketRecord{}).Where("name = ?", t.name).
+			Update("value", gorm.Expr("value + ?", t.step))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.Create(&ticketRecord{Name: t.name, Value: t.step}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&ticketRecord{}).Where("name = ?", t.name).Pluck("value", &max).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("申请号段失败: %w", err)
+	}
+	return max, nil
+}
+
+// allocateMySQL 使用 LAST_INSERT_ID 在同一连接上原子获取新值
+func (t *Ticket) allocateMySQL() (int64, error) {
+	ctx := context.Background()
+	if c := t.db.Context(); c != nil && c.Context != nil {
+		ctx = c.Context
+	}
+	conn, err := t.db.SqlDB().Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "INSERT INTO "+TicketTableName+" (name, value) VALUES (?, LAST_INSERT_ID(?)) "+
+		"ON DUPLICATE KEY UPDATE value = LAST_INSERT_ID(value + ?)", t.name, t.step, t.step)
+	if err != nil {
+		return 0, fmt.Errorf("申请号段失败: %w", err)
+	}
+
+	var max int64
+	if err := conn.QueryRowContext(ctx, "SELECT LAST_INSERT_ID()").Scan(&max); err != nil {
+		return 0, fmt.Errorf("申请号段失败: %w", err)
+	}
+	if max <= 0 {
+		return 0, errors.New("申请号段失败: 未获取到号段值")
+	}
+	return max, nil
+}
@@ -0,0 +1,87 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 雪花ID位数分配：41位毫秒时间戳 + 10位节点 + 12位序列
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNode      = -1 ^ (-1 << nodeBits)
+	maxSequence  = -1 ^ (-1 << sequenceBits)
+)
+
+// DefaultEpoch 默认起始时间 2020-01-01 UTC
+var DefaultEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrClockBackwards 系统时钟回拨超过容忍范围
+var ErrClockBackwards = errors.New("系统时钟回拨")
+
+// maxBackwards 可等待的时钟回拨时长
+const maxBackwards = 5 * time.Millisecond
+
+// Snowflake 雪花ID生成器，同一节点内单调递增
+type Snowflake struct {
+	mutex    sync.Mutex
+	epoch    int64 // 起始时间（毫秒）
+	node     int64 // 节点ID
+	lastTime int64 // 上次生成时间（毫秒，相对起始时间）
+	sequence int64 // 毫秒内序列
+}
+
+// NewSnowflake 创建雪花ID生成器，节点ID范围为 0-1023，epoch 为零值时使用 DefaultEpoch
+func NewSnowflake(node int64, epoch time.Time) (*Snowflake, error) {
+	if node < 0 || node > maxNode {
+		return nil, fmt.Errorf("节点ID必须在 0-%d 之间", maxNode)
+	}
+	if epoch.IsZero() {
+		epoch = DefaultEpoch
+	}
+	if epoch.After(time.Now()) {
+		return nil, errors.New("起始时间不能晚于当前时间")
+	}
+	return &Snowflake{epoch: epoch.UnixMilli(), node: node, lastTime: -1}, nil
+}
+
+// Next 生成下一个ID
+func (s *Snowflake) Next() (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now().UnixMilli() - s.epoch
+	if now < s.lastTime {
+		// 短暂回拨时等待时钟追上
+		if time.Duration(s.lastTime-now)*time.Millisecond > maxBackwards {
+			return 0, fmt.Errorf("%w: %dms", ErrClockBackwards, s.lastTime-now)
+		}
+		for now < s.lastTime {
+			time.Sleep(time.Duration(s.lastTime-now) * time.Millisecond)
+			now = time.Now().UnixMilli() - s.epoch
+		}
+	}
+
+	if now == s.lastTime {
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			// 当前毫秒序列用尽，等待下一毫秒
+			for now <= s.lastTime {
+				now = time.Now().UnixMilli() - s.epoch
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastTime = now
+
+	return now<<(nodeBits+sequenceBits) | s.node<<sequenceBits | s.sequence, nil
+}
+
+// Parse 解析ID中的生成时间、节点ID和序列
+func (s *Snowflake) Parse(id int64) (t time.Time, node int64, sequence int64) {
+	ms := id>>(nodeBits+sequenceBits) + s.epoch
+	return time.UnixMilli(ms), id >> sequenceBits & maxNode, id & maxSequence
+}
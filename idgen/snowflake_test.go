@@ -0,0 +1,65 @@
+package idgen
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试雪花ID唯一且递增
+func TestSnowflake(t *testing.T) {
+	s, err := NewSnowflake(7, time.Time{})
+	if err != nil {
+		t.Fatalf("创建生成器失败: %v", err)
+	}
+
+	var last int64
+	for i := 0; i < 10000; i++ {
+		id, err := s.Next()
+		if err != nil {
+			t.Fatalf("生成ID失败: %v", err)
+		}
+		if id <= last {
+			t.Fatalf("ID未递增: %d <= %d", id, last)
+		}
+		last = id
+	}
+
+	ts, node, _ := s.Parse(last)
+	if node != 7 {
+		t.Errorf("期望节点为 7，实际为 %d", node)
+	}
+	if d := time.Since(ts); d < 0 || d > time.Minute {
+		t.Errorf("解析出的时间不符合预期: %v", ts)
+	}
+
+	if _, err := NewSnowflake(1024, time.Time{}); err == nil {
+		t.Error("节点ID超出范围时应返回错误")
+	}
+}
+
+// 测试并发生成不重复
+func TestSnowflakeConcurrent(t *testing.T) {
+	s, _ := NewSnowflake(1, time.Time{})
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		seen  = make(map[int64]bool)
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				id, _ := s.Next()
+				mutex.Lock()
+				if seen[id] {
+					t.Errorf("ID重复: %d", id)
+				}
+				seen[id] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,95 @@
+package introspect
+
+import (
+	"strings"
+)
+
+// clickhouseBackend ClickHouse，读取当前库的 system 表
+type clickhouseBackend struct{}
+
+func (clickhouseBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("name", name)
+	return scanTables(i.query(`
+		SELECT database, name, comment
+		FROM system.tables
+		WHERE database = currentDatabase() AND NOT is_temporary AND engine NOT LIKE '%View'`+filter+`
+		ORDER BY name
+	`, args...))
+}
+
+func (clickhouseBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT database, name, as_select, comment
+		FROM system.tables
+		WHERE database = currentDatabase() AND engine LIKE '%View'
+		ORDER BY name
+	`))
+}
+
+func (clickhouseBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`
+		SELECT name, position, type, default_kind, default_expression, comment, is_in_primary_key
+		FROM system.columns
+		WHERE database = currentDatabase() AND table = ?
+		ORDER BY position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var defaultKind, defaultExpr string
+		var primaryKey uint8
+		if err := rows.Scan(&col.Name, &col.Position, &col.DataType, &defaultKind, &defaultExpr, &col.Comment, &primaryKey); err != nil {
+			return nil, err
+		}
+		col.ColumnType = col.DataType
+		// 可空性体现在类型中，如 Nullable(String)、LowCardinality(Nullable(String))
+		col.Nullable = strings.Contains(col.DataType, "Nullable(")
+		col.PrimaryKey = primaryKey == 1
+		switch defaultKind {
+		case "":
+		case "DEFAULT":
+			col.Default.String, col.Default.Valid = defaultExpr, true
+		default:
+			// MATERIALIZED/ALIAS/EPHEMERAL 列的值由表达式计算
+			col.Extra = defaultKind + " " + defaultExpr
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (clickhouseBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	// ClickHouse 只有跳数索引，索引对象是表达式
+	rows, err := i.query(`
+		SELECT name, type, expr
+		FROM system.data_skipping_indices
+		WHERE database = currentDatabase() AND table = ?
+		ORDER BY name
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []Index
+	for rows.Next() {
+		var index Index
+		var expr string
+		if err := rows.Scan(&index.Name, &index.Type, &expr); err != nil {
+			return nil, err
+		}
+		index.Columns = []string{expr}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+func (clickhouseBackend) foreignKeys(*Inspector, string) ([]ForeignKey, error) {
+	// ClickHouse 不支持外键
+	return nil, nil
+}
@@ -0,0 +1,217 @@
+package introspect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind 结构差异类型
+type ChangeKind string
+
+const (
+	TableAdded    ChangeKind = "table_added"    // 目标库多出的表
+	TableDropped  ChangeKind = "table_dropped"  // 目标库缺少的表
+	ColumnAdded   ChangeKind = "column_added"   // 目标库多出的列
+	ColumnDropped ChangeKind = "column_dropped" // 目标库缺少的列
+	ColumnChanged ChangeKind = "column_changed" // 类型、可空、默认值或主键不同的列
+	IndexAdded    ChangeKind = "index_added"    // 目标库多出的索引
+	IndexDropped  ChangeKind = "index_dropped"  // 目标库缺少的索引
+	IndexChanged  ChangeKind = "index_changed"  // 唯一性或索引列不同的索引
+)
+
+// Change 两个库之间的一处结构差异，描述将源库变为目标库需要的变更
+type Change struct {
+	Kind  ChangeKind // 差异类型
+	Table string     // 表名
+	Name  string     // 列名或索引名，表级差异为空
+	From  string     // 源库中的定义，新增时为空
+	To    string     // 目标库中的定义，删除时为空
+}
+
+// String 返回差异的文本描述，+ 表示新增，- 表示删除，~ 表示修改
+func (c Change) String() string {
+	object := "table " + c.Table
+	switch c.Kind {
+	case ColumnAdded, ColumnDropped, ColumnChanged:
+		object = fmt.Sprintf("column %s.%s", c.Table, c.Name)
+	case IndexAdded, IndexDropped, IndexChanged:
+		object = fmt.Sprintf("index %s.%s", c.Table, c.Name)
+	}
+	switch c.Kind {
+	case TableAdded, ColumnAdded, IndexAdded:
+		return strings.TrimSpace("+ " + object + " " + c.To)
+	case TableDropped, ColumnDropped, IndexDropped:
+		return strings.TrimSpace("- " + object + " " + c.From)
+	}
+	return fmt.Sprintf("~ %s: %s => %s", object, c.From, c.To)
+}
+
+// Diff 比较两个库的表、列和索引（不含主键索引），返回将 from 变为 to 需要的变更
+// 表名、列名和索引名不区分大小写；类型按数据库报告的完整类型比较，跨数据库比较时类型名的差异也会被报告
+func Diff(from, to *Inspector) ([]Change, error) {
+	fromTables, err := tableMap(from)
+	if err != nil {
+		return nil, err
+	}
+	toTables, err := tableMap(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, key := range unionKeys(fromTables, toTables) {
+		fromTable, inFrom := fromTables[key]
+		toTable, inTo := toTables[key]
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Kind: TableAdded, Table: toTable.Name})
+		case !inTo:
+			changes = append(changes, Change{Kind: TableDropped, Table: fromTable.Name})
+		default:
+			tableChanges, err := diffTable(from, to, fromTable.Name, toTable.Name)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, tableChanges...)
+		}
+	}
+	return changes, nil
+}
+
+// diffTable 比较同一个表的列和索引
+func diffTable(from, to *Inspector, fromName, toName string) ([]Change, error) {
+	fromColumns, err := columnMap(from, fromName)
+	if err != nil {
+		return nil, err
+	}
+	toColumns, err := columnMap(to, toName)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, key := range unionKeys(fromColumns, toColumns) {
+		fromColumn, inFrom := fromColumns[key]
+		toColumn, inTo := toColumns[key]
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Kind: ColumnAdded, Table: toName, Name: toColumn.Name, To: columnDefinition(toColumn)})
+		case !inTo:
+			changes = append(changes, Change{Kind: ColumnDropped, Table: toName, Name: fromColumn.Name, From: columnDefinition(fromColumn)})
+		default:
+			fromDef, toDef := columnDefinition(fromColumn), columnDefinition(toColumn)
+			if !strings.EqualFold(fromDef, toDef) {
+				changes = append(changes, Change{Kind: ColumnChanged, Table: toName, Name: toColumn.Name, From: fromDef, To: toDef})
+			}
+		}
+	}
+
+	fromIndexes, err := indexMap(from, fromName)
+	if err != nil {
+		return nil, err
+	}
+	toIndexes, err := indexMap(to, toName)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range unionKeys(fromIndexes, toIndexes) {
+		fromIndex, inFrom := fromIndexes[key]
+		toIndex, inTo := toIndexes[key]
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Kind: IndexAdded, Table: toName, Name: toIndex.Name, To: indexDefinition(toIndex)})
+		case !inTo:
+			changes = append(changes, Change{Kind: IndexDropped, Table: toName, Name: fromIndex.Name, From: indexDefinition(fromIndex)})
+		default:
+			fromDef, toDef := indexDefinition(fromIndex), indexDefinition(toIndex)
+			if !strings.EqualFold(fromDef, toDef) {
+				changes = append(changes, Change{Kind: IndexChanged, Table: toName, Name: toIndex.Name, From: fromDef, To: toDef})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// columnDefinition 列定义的文本形式，如 varchar(64) NOT NULL DEFAULT 'guest'
+func columnDefinition(column Column) string {
+	def := column.ColumnType
+	if def == "" {
+		def = column.DataType
+	}
+	if !column.Nullable {
+		def += " NOT NULL"
+	}
+	if column.Default.Valid {
+		def += " DEFAULT " + strings.TrimSpace(column.Default.String)
+	}
+	if column.PrimaryKey {
+		def += " PRIMARY KEY"
+	}
+	return def
+}
+
+// indexDefinition 索引定义的文本形式，如 UNIQUE (email)
+func indexDefinition(index Index) string {
+	def := "(" + strings.Join(index.Columns, ", ") + ")"
+	if index.Unique {
+		def = "UNIQUE " + def
+	}
+	return def
+}
+
+// tableMap 以小写表名为键的表
+func tableMap(i *Inspector) (map[string]Table, error) {
+	tables, err := i.Tables()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]Table, len(tables))
+	for _, table := range tables {
+		result[strings.ToLower(table.Name)] = table
+	}
+	return result, nil
+}
+
+// columnMap 以小写列名为键的列
+func columnMap(i *Inspector, table string) (map[string]Column, error) {
+	columns, err := i.Columns(table)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]Column, len(columns))
+	for _, column := range columns {
+		result[strings.ToLower(column.Name)] = column
+	}
+	return result, nil
+}
+
+// indexMap 以小写索引名为键的非主键索引
+func indexMap(i *Inspector, table string) (map[string]Index, error) {
+	indexes, err := i.Indexes(table)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]Index, len(indexes))
+	for _, index := range indexes {
+		if !index.Primary {
+			result[strings.ToLower(index.Name)] = index
+		}
+	}
+	return result, nil
+}
+
+// unionKeys 返回两个映射的键的并集，已排序
+func unionKeys[T any](a, b map[string]T) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package introspect
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestSQLiteDiff(t *testing.T) {
+	from := openSQLite(t)
+	to, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	to.SetMaxOpenConns(1)
+	defer to.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE USERS (id INTEGER PRIMARY KEY, name VARCHAR(128) NOT NULL DEFAULT 'guest', age INTEGER)`,
+		`CREATE INDEX idx_users_email ON users (name)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY)`,
+	} {
+		if _, err := to.Exec(stmt); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+
+	changes, err := Diff(New(from, ""), New(to, ""))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	want := []string{
+		"+ table orders",
+		"- table user_roles",
+		"+ column USERS.age INTEGER",
+		"- column USERS.email TEXT",
+		"~ column USERS.name: VARCHAR(64) NOT NULL DEFAULT 'guest' => VARCHAR(128) NOT NULL DEFAULT 'guest'",
+		"~ index USERS.idx_users_email: UNIQUE (email) => (name)",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() = %v", changes)
+	}
+	for i, change := range changes {
+		if change.String() != want[i] {
+			t.Errorf("changes[%d] = %q, want %q", i, change.String(), want[i])
+		}
+	}
+
+	if changes, _ := Diff(New(from, ""), New(from, "")); len(changes) != 0 {
+		t.Errorf("相同的库 Diff() = %v", changes)
+	}
+}
@@ -0,0 +1,133 @@
+package introspect
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// duckdbBackend DuckDB，读取当前 schema 的 duckdb_* 元数据函数和 information_schema
+type duckdbBackend struct{}
+
+func (duckdbBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("table_name", name)
+	return scanTables(i.query(`
+		SELECT schema_name, table_name, comment
+		FROM duckdb_tables()
+		WHERE schema_name = current_schema() AND NOT internal AND NOT temporary`+filter+`
+		ORDER BY table_name
+	`, args...))
+}
+
+func (duckdbBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT schema_name, view_name, sql, comment
+		FROM duckdb_views()
+		WHERE schema_name = current_schema() AND NOT internal AND NOT temporary
+		ORDER BY view_name
+	`))
+}
+
+func (duckdbBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`
+		SELECT c.column_name, c.column_index, c.data_type, c.is_nullable, c.column_default, c.comment,
+			EXISTS (
+				SELECT 1 FROM duckdb_constraints() k
+				WHERE k.constraint_type = 'PRIMARY KEY' AND k.schema_name = c.schema_name
+					AND k.table_name = c.table_name AND list_contains(k.constraint_column_names, c.column_name)
+			)
+		FROM duckdb_columns() c
+		WHERE c.schema_name = current_schema() AND c.table_name = ?
+		ORDER BY c.column_index
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var comment sql.NullString
+		if err := rows.Scan(&col.Name, &col.Position, &col.DataType, &col.Nullable, &col.Default, &comment, &col.PrimaryKey); err != nil {
+			return nil, err
+		}
+		col.Comment = comment.String
+		col.ColumnType = col.DataType
+		// DuckDB 使用序列实现自增
+		col.AutoIncrement = strings.HasPrefix(col.Default.String, "nextval(")
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (duckdbBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	// duckdb_indexes() 不含主键和唯一约束自动创建的索引，列名从索引定义中解析
+	rows, err := i.query(`
+		SELECT index_name, is_unique, sql
+		FROM duckdb_indexes()
+		WHERE schema_name = current_schema() AND table_name = ?
+		ORDER BY index_name
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []Index
+	for rows.Next() {
+		var index Index
+		var definition sql.NullString
+		if err := rows.Scan(&index.Name, &index.Unique, &definition); err != nil {
+			return nil, err
+		}
+		// DuckDB 使用 ART 索引
+		index.Type = "ART"
+		index.Columns = indexColumns(definition.String)
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+// indexColumns 从 CREATE INDEX 语句中解析列名
+func indexColumns(definition string) []string {
+	open := strings.LastIndex(definition, "(")
+	end := strings.LastIndex(definition, ")")
+	if open < 0 || end <= open {
+		return nil
+	}
+	var columns []string
+	for _, col := range strings.Split(definition[open+1:end], ",") {
+		if col = strings.Trim(strings.TrimSpace(col), `"`); col != "" {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+func (duckdbBackend) foreignKeys(i *Inspector, table string) ([]ForeignKey, error) {
+	rows, err := i.query(`
+		SELECT rc.constraint_name, kcu.column_name, ref.table_name, ref.column_name, rc.update_rule, rc.delete_rule
+		FROM information_schema.referential_constraints rc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_name = rc.constraint_name
+		JOIN information_schema.key_column_usage ref
+			ON ref.constraint_name = rc.unique_constraint_name AND ref.ordinal_position = kcu.ordinal_position
+		WHERE kcu.table_schema = current_schema() AND kcu.table_name = ?
+		ORDER BY rc.constraint_name, kcu.ordinal_position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group foreignKeyGroup
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &column, &fk.RefTable, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		group.add(fk, column, refColumn)
+	}
+	return group.foreignKeys, rows.Err()
+}
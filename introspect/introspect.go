@@ -0,0 +1,474 @@
+// Package introspect 读取数据库的表、列、索引、外键和视图结构，屏蔽各数据库系统表的差异
+//
+// 支持 MySQL/MariaDB/TiDB/OceanBase、PostgreSQL、SQL Server、Oracle、SQLite、ClickHouse 和 DuckDB，
+// 均读取当前连接的默认库（schema），方言可显式指定，也可由驱动自动识别:
+//
+//	tables, err := introspect.Tables(db)
+//	columns, err := introspect.New(db, "mysql").Columns("users")
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gzorm/gosqlx/builder"
+)
+
+// Table 表信息
+type Table struct {
+	Schema  string // 所属库或 schema
+	Name    string // 表名
+	Comment string // 表注释
+}
+
+// View 视图信息
+type View struct {
+	Schema     string // 所属库或 schema
+	Name       string // 视图名
+	Definition string // 视图定义（SELECT 语句或完整的 CREATE VIEW）
+	Comment    string // 视图注释
+}
+
+// Column 列信息
+type Column struct {
+	Name          string         // 列名
+	Position      int            // 列序号，从1开始
+	DataType      string         // 数据库报告的类型名，如 varchar、NUMBER、Nullable(String)
+	ColumnType    string         // 完整类型，包含长度和精度，如 varchar(255)
+	UDTName       string         // PostgreSQL 的底层类型名（udt_name），其他数据库为空
+	Nullable      bool           // 是否可为空
+	Default       sql.NullString // 默认值表达式
+	Comment       string         // 列注释
+	PrimaryKey    bool           // 是否为主键列
+	AutoIncrement bool           // 是否自增（自增属性、identity 或序列默认值）
+	Extra         string         // 数据库原始附加信息（MySQL 的 extra，ClickHouse 的 MATERIALIZED/ALIAS）
+	EnumValues    []string       // 枚举列的取值：MySQL 系的 ENUM、PostgreSQL 枚举类型和 SQLite 的 CHECK (列 IN (...)) 约束
+}
+
+// Index 索引信息
+type Index struct {
+	Name    string   // 索引名
+	Type    string   // 索引类型，如 BTREE、HASH、NONCLUSTERED
+	Unique  bool     // 是否唯一
+	Primary bool     // 是否为主键索引
+	Columns []string // 索引列，按索引中的顺序
+}
+
+// ForeignKey 外键信息，复合外键的 Columns 与 RefColumns 按位置一一对应
+type ForeignKey struct {
+	Name       string   // 约束名，SQLite 外键没有名称，以 fk_表名_编号 表示
+	Columns    []string // 外键列
+	RefTable   string   // 引用表
+	RefColumns []string // 引用列，SQLite 未声明引用列时为空字符串，表示引用主键
+	OnUpdate   string   // 更新规则，如 CASCADE、NO ACTION
+	OnDelete   string   // 删除规则
+}
+
+// backend 各数据库的结构查询实现
+type backend interface {
+	tables(i *Inspector, name string) ([]Table, error)
+	views(i *Inspector) ([]View, error)
+	columns(i *Inspector, table string) ([]Column, error)
+	indexes(i *Inspector, table string) ([]Index, error)
+	foreignKeys(i *Inspector, table string) ([]ForeignKey, error)
+}
+
+// backends 方言名到实现的映射，同一数据库的别名共用实现
+var backends = map[string]backend{
+	"mysql":      mysqlBackend{},
+	"mariadb":    mysqlBackend{},
+	"tidb":       mysqlBackend{},
+	"oceanbase":  mysqlBackend{},
+	"postgres":   postgresBackend{},
+	"postgresql": postgresBackend{},
+	"sqlserver":  sqlserverBackend{},
+	"mssql":      sqlserverBackend{},
+	"oracle":     oracleBackend{},
+	"sqlite":     sqliteBackend{},
+	"sqlite3":    sqliteBackend{},
+	"clickhouse": clickhouseBackend{},
+	"duckdb":     duckdbBackend{},
+}
+
+// bindDialects 方言别名对应的参数占位符风格
+var bindDialects = map[string]string{
+	"postgresql": "postgres",
+	"mssql":      "sqlserver",
+}
+
+// Inspector 结构读取器
+type Inspector struct {
+	db      *sql.DB
+	dialect string
+	backend backend
+	ctx     context.Context
+}
+
+// New 创建结构读取器，dialect 为空时根据驱动类型识别
+func New(db *sql.DB, dialect string) *Inspector {
+	if dialect == "" && db != nil {
+		dialect = DetectDialect(db)
+	}
+	dialect = strings.ToLower(dialect)
+	return &Inspector{
+		db:      db,
+		dialect: dialect,
+		backend: backends[dialect],
+		ctx:     context.Background(),
+	}
+}
+
+// WithContext 设置查询使用的上下文
+func (i *Inspector) WithContext(ctx context.Context) *Inspector {
+	clone := *i
+	clone.ctx = ctx
+	return &clone
+}
+
+// Dialect 返回方言名
+func (i *Inspector) Dialect() string {
+	return i.dialect
+}
+
+// Tables 返回当前库的所有表（不含视图），按表名排序
+func (i *Inspector) Tables() ([]Table, error) {
+	if err := i.check(); err != nil {
+		return nil, err
+	}
+	tables, err := i.backend.tables(i, "")
+	if err != nil {
+		return nil, fmt.Errorf("查询表失败: %w", err)
+	}
+	return tables, nil
+}
+
+// Table 返回指定表，表不存在时返回 sql.ErrNoRows
+func (i *Inspector) Table(name string) (Table, error) {
+	if err := i.check(); err != nil {
+		return Table{}, err
+	}
+	tables, err := i.backend.tables(i, name)
+	if err != nil {
+		return Table{}, fmt.Errorf("查询表 %s 失败: %w", name, err)
+	}
+	if len(tables) == 0 {
+		return Table{}, fmt.Errorf("表 %s 不存在: %w", name, sql.ErrNoRows)
+	}
+	return tables[0], nil
+}
+
+// Views 返回当前库的所有视图，按视图名排序
+func (i *Inspector) Views() ([]View, error) {
+	if err := i.check(); err != nil {
+		return nil, err
+	}
+	views, err := i.backend.views(i)
+	if err != nil {
+		return nil, fmt.Errorf("查询视图失败: %w", err)
+	}
+	return views, nil
+}
+
+// Columns 返回表的所有列，按列序号排序
+func (i *Inspector) Columns(table string) ([]Column, error) {
+	if err := i.check(); err != nil {
+		return nil, err
+	}
+	columns, err := i.backend.columns(i, table)
+	if err != nil {
+		return nil, fmt.Errorf("查询表 %s 列信息失败: %w", table, err)
+	}
+	return columns, nil
+}
+
+// Indexes 返回表的所有索引（包含主键索引），按索引名排序
+func (i *Inspector) Indexes(table string) ([]Index, error) {
+	if err := i.check(); err != nil {
+		return nil, err
+	}
+	indexes, err := i.backend.indexes(i, table)
+	if err != nil {
+		return nil, fmt.Errorf("查询表 %s 索引失败: %w", table, err)
+	}
+	return indexes, nil
+}
+
+// ForeignKeys 返回表的所有外键，按约束名排序
+func (i *Inspector) ForeignKeys(table string) ([]ForeignKey, error) {
+	if err := i.check(); err != nil {
+		return nil, err
+	}
+	foreignKeys, err := i.backend.foreignKeys(i, table)
+	if err != nil {
+		return nil, fmt.Errorf("查询表 %s 外键失败: %w", table, err)
+	}
+	return foreignKeys, nil
+}
+
+// PrimaryKeys 返回表的主键列，优先使用主键索引中的顺序
+func (i *Inspector) PrimaryKeys(table string) ([]string, error) {
+	indexes, err := i.Indexes(table)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if index.Primary {
+			return index.Columns, nil
+		}
+	}
+
+	// SQLite 的 INTEGER PRIMARY KEY、ClickHouse 排序键等没有主键索引
+	columns, err := i.Columns(table)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, column := range columns {
+		if column.PrimaryKey {
+			keys = append(keys, column.Name)
+		}
+	}
+	return keys, nil
+}
+
+// check 检查连接和方言
+func (i *Inspector) check() error {
+	if i.db == nil {
+		return fmt.Errorf("数据库连接不能为空")
+	}
+	if i.backend == nil {
+		return fmt.Errorf("不支持读取 %q 的表结构", i.dialect)
+	}
+	return nil
+}
+
+// query 按方言改写占位符后执行查询
+func (i *Inspector) query(query string, args ...interface{}) (*sql.Rows, error) {
+	dialect := i.dialect
+	if alias, ok := bindDialects[dialect]; ok {
+		dialect = alias
+	}
+	return i.db.QueryContext(i.ctx, builder.Rebind(dialect, query), args...)
+}
+
+// Tables 返回当前库的所有表
+func Tables(db *sql.DB) ([]Table, error) {
+	return New(db, "").Tables()
+}
+
+// Views 返回当前库的所有视图
+func Views(db *sql.DB) ([]View, error) {
+	return New(db, "").Views()
+}
+
+// Columns 返回表的所有列
+func Columns(db *sql.DB, table string) ([]Column, error) {
+	return New(db, "").Columns(table)
+}
+
+// Indexes 返回表的所有索引
+func Indexes(db *sql.DB, table string) ([]Index, error) {
+	return New(db, "").Indexes(table)
+}
+
+// ForeignKeys 返回表的所有外键
+func ForeignKeys(db *sql.DB, table string) ([]ForeignKey, error) {
+	return New(db, "").ForeignKeys(table)
+}
+
+// PrimaryKeys 返回表的主键列
+func PrimaryKeys(db *sql.DB, table string) ([]string, error) {
+	return New(db, "").PrimaryKeys(table)
+}
+
+// Supported 判断方言是否支持
+func Supported(dialect string) bool {
+	_, ok := backends[strings.ToLower(dialect)]
+	return ok
+}
+
+// DetectDialect 根据驱动类型识别方言，无法识别时返回空字符串
+// MySQL 协议兼容的数据库（MariaDB/TiDB/OceanBase）均识别为 mysql
+func DetectDialect(db *sql.DB) string {
+	driverName := strings.ToLower(reflect.TypeOf(db.Driver()).String())
+	switch {
+	case strings.Contains(driverName, "mysql"):
+		return "mysql"
+	case strings.Contains(driverName, "stdlib"), strings.Contains(driverName, "pq."):
+		return "postgres"
+	case strings.Contains(driverName, "mssql"):
+		return "sqlserver"
+	case strings.Contains(driverName, "duckdb"):
+		return "duckdb"
+	case strings.Contains(driverName, "sqlite"):
+		return "sqlite"
+	case strings.Contains(driverName, "ora"):
+		return "oracle"
+	case strings.Contains(driverName, "clickhouse"):
+		return "clickhouse"
+	}
+	return ""
+}
+
+// indexGroup 将按索引名和列序排列的行合并为索引
+type indexGroup struct {
+	indexes []Index
+	pos     map[string]int
+}
+
+// add 添加一行，同名索引追加列
+func (g *indexGroup) add(index Index, column string) {
+	if g.pos == nil {
+		g.pos = make(map[string]int)
+	}
+	n, ok := g.pos[index.Name]
+	if !ok {
+		n = len(g.indexes)
+		g.pos[index.Name] = n
+		g.indexes = append(g.indexes, index)
+	}
+	if column != "" {
+		g.indexes[n].Columns = append(g.indexes[n].Columns, column)
+	}
+}
+
+// foreignKeyGroup 将按约束名和列序排列的行合并为外键
+type foreignKeyGroup struct {
+	foreignKeys []ForeignKey
+	pos         map[string]int
+}
+
+// add 添加一行，同名约束追加列
+func (g *foreignKeyGroup) add(fk ForeignKey, column, refColumn string) {
+	if g.pos == nil {
+		g.pos = make(map[string]int)
+	}
+	n, ok := g.pos[fk.Name]
+	if !ok {
+		n = len(g.foreignKeys)
+		g.pos[fk.Name] = n
+		g.foreignKeys = append(g.foreignKeys, fk)
+	}
+	g.foreignKeys[n].Columns = append(g.foreignKeys[n].Columns, column)
+	g.foreignKeys[n].RefColumns = append(g.foreignKeys[n].RefColumns, refColumn)
+}
+
+// scanTables 扫描 schema、表名、注释三列
+func scanTables(rows *sql.Rows, err error) ([]Table, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var table Table
+		var schema, comment sql.NullString
+		if err := rows.Scan(&schema, &table.Name, &comment); err != nil {
+			return nil, err
+		}
+		table.Schema, table.Comment = schema.String, comment.String
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// scanViews 扫描 schema、视图名、定义、注释四列
+func scanViews(rows *sql.Rows, err error) ([]View, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []View
+	for rows.Next() {
+		var view View
+		var schema, definition, comment sql.NullString
+		if err := rows.Scan(&schema, &view.Name, &definition, &comment); err != nil {
+			return nil, err
+		}
+		view.Schema, view.Definition, view.Comment = schema.String, strings.TrimSpace(definition.String), comment.String
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// tableFilter 按表名过滤的条件，name 为空时不过滤
+func tableFilter(column, name string) (string, []interface{}) {
+	if name == "" {
+		return "", nil
+	}
+	return " AND " + column + " = ?", []interface{}{name}
+}
+
+// sizedType 为类型追加长度或精度
+func sizedType(dataType string, length, precision, scale sql.NullInt64) string {
+	switch {
+	case length.Valid && length.Int64 > 0:
+		return fmt.Sprintf("%s(%d)", dataType, length.Int64)
+	case precision.Valid && scale.Valid:
+		return fmt.Sprintf("%s(%d,%d)", dataType, precision.Int64, scale.Int64)
+	case precision.Valid:
+		return fmt.Sprintf("%s(%d)", dataType, precision.Int64)
+	}
+	return dataType
+}
+
+// enumValues 解析 MySQL 系 ENUM 列的类型，如 enum('draft','paid')
+func enumValues(columnType string) []string {
+	open, end := strings.IndexByte(columnType, '('), strings.LastIndexByte(columnType, ')')
+	if open < 0 || end < open {
+		return nil
+	}
+	return quotedList(columnType[open+1 : end])
+}
+
+// checkEnumValues 从建表语句中查找 CHECK (列 IN ('a', 'b')) 约束，返回列的取值
+func checkEnumValues(ddl, column string) []string {
+	pattern := `(?i)CHECK\s*\(\s*["` + "`" + `\[]?` + regexp.QuoteMeta(column) + `["` + "`" + `\]]?\s+IN\s*\(([^)]*)\)\s*\)`
+	match := regexp.MustCompile(pattern).FindStringSubmatch(ddl)
+	if match == nil {
+		return nil
+	}
+	return quotedList(match[1])
+}
+
+// quotedList 解析以逗号分隔的单引号字符串列表，连续两个单引号表示一个单引号；含有非字符串项时返回 nil
+func quotedList(list string) []string {
+	var values []string
+	for rest := strings.TrimSpace(list); rest != ""; {
+		if rest[0] != '\'' {
+			return nil
+		}
+		var value strings.Builder
+		i := 1
+		for ; i < len(rest); i++ {
+			if rest[i] == '\'' {
+				if i+1 < len(rest) && rest[i+1] == '\'' {
+					value.WriteByte('\'')
+					i++
+					continue
+				}
+				break
+			}
+			value.WriteByte(rest[i])
+		}
+		if i >= len(rest) {
+			return nil
+		}
+		values = append(values, value.String())
+		rest = strings.TrimSpace(rest[i+1:])
+		if rest == "" {
+			break
+		}
+		if rest[0] != ',' {
+			return nil
+		}
+		rest = strings.TrimSpace(rest[1:])
+	}
+	return values
+}
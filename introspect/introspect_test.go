@@ -0,0 +1,157 @@
+package introspect
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openSQLite 创建测试用的内存数据库
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(64) NOT NULL DEFAULT 'guest', email TEXT)`,
+		`CREATE UNIQUE INDEX idx_users_email ON users (email)`,
+		`CREATE TABLE user_roles (
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role TEXT NOT NULL CHECK (role IN ('admin', 'member', 'o''wner')),
+			PRIMARY KEY (role, user_id)
+		)`,
+		`CREATE VIEW active_users AS SELECT id, name FROM users`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+	return db
+}
+
+func TestDetectDialect(t *testing.T) {
+	db := openSQLite(t)
+	if got := DetectDialect(db); got != "sqlite" {
+		t.Errorf("DetectDialect() = %q, want sqlite", got)
+	}
+	if !Supported("PostgreSQL") || Supported("mongodb") {
+		t.Error("Supported() 结果不正确")
+	}
+	if _, err := New(db, "db2").Tables(); err == nil {
+		t.Error("不支持的方言应返回错误")
+	}
+}
+
+func TestSQLiteTablesAndViews(t *testing.T) {
+	db := openSQLite(t)
+
+	tables, err := Tables(db)
+	if err != nil {
+		t.Fatalf("Tables() error = %v", err)
+	}
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	if want := []string{"user_roles", "users"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Tables() = %v, want %v", names, want)
+	}
+
+	if _, err := New(db, "").Table("missing"); err == nil {
+		t.Error("不存在的表应返回错误")
+	}
+
+	views, err := Views(db)
+	if err != nil {
+		t.Fatalf("Views() error = %v", err)
+	}
+	if len(views) != 1 || views[0].Name != "active_users" || views[0].Definition == "" {
+		t.Errorf("Views() = %+v", views)
+	}
+}
+
+func TestSQLiteColumns(t *testing.T) {
+	db := openSQLite(t)
+
+	columns, err := Columns(db, "users")
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	if len(columns) != 3 {
+		t.Fatalf("Columns() 返回 %d 列", len(columns))
+	}
+	id, name, email := columns[0], columns[1], columns[2]
+	if !id.PrimaryKey || !id.AutoIncrement || id.Position != 1 {
+		t.Errorf("id = %+v", id)
+	}
+	if name.Nullable || name.ColumnType != "VARCHAR(64)" || name.Default.String != "'guest'" {
+		t.Errorf("name = %+v", name)
+	}
+	if !email.Nullable || email.Default.Valid {
+		t.Errorf("email = %+v", email)
+	}
+
+	// 复合主键的列不是 rowid 别名
+	columns, err = Columns(db, "user_roles")
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	for _, column := range columns {
+		if !column.PrimaryKey || column.AutoIncrement {
+			t.Errorf("%s = %+v", column.Name, column)
+		}
+	}
+	// CHECK (列 IN (...)) 约束的列为枚举列
+	if got := columns[1].EnumValues; !reflect.DeepEqual(got, []string{"admin", "member", "o'wner"}) || columns[0].EnumValues != nil {
+		t.Errorf("EnumValues = %q, %q", got, columns[0].EnumValues)
+	}
+	if got := enumValues("enum('draft','paid','it''s')"); !reflect.DeepEqual(got, []string{"draft", "paid", "it's"}) {
+		t.Errorf("enumValues() = %q", got)
+	}
+}
+
+func TestSQLiteIndexesAndKeys(t *testing.T) {
+	db := openSQLite(t)
+
+	indexes, err := Indexes(db, "users")
+	if err != nil {
+		t.Fatalf("Indexes() error = %v", err)
+	}
+	if len(indexes) != 1 || indexes[0].Name != "idx_users_email" || !indexes[0].Unique ||
+		!reflect.DeepEqual(indexes[0].Columns, []string{"email"}) {
+		t.Errorf("Indexes() = %+v", indexes)
+	}
+
+	// INTEGER PRIMARY KEY 没有主键索引，从列信息获取
+	keys, err := PrimaryKeys(db, "users")
+	if err != nil || !reflect.DeepEqual(keys, []string{"id"}) {
+		t.Errorf("PrimaryKeys(users) = %v, %v", keys, err)
+	}
+	// 复合主键按声明顺序返回
+	keys, err = PrimaryKeys(db, "user_roles")
+	if err != nil || !reflect.DeepEqual(keys, []string{"role", "user_id"}) {
+		t.Errorf("PrimaryKeys(user_roles) = %v, %v", keys, err)
+	}
+
+	foreignKeys, err := ForeignKeys(db, "user_roles")
+	if err != nil {
+		t.Fatalf("ForeignKeys() error = %v", err)
+	}
+	want := []ForeignKey{{
+		Name:       "fk_user_roles_0",
+		Columns:    []string{"user_id"},
+		RefTable:   "users",
+		RefColumns: []string{"id"},
+		OnUpdate:   "NO ACTION",
+		OnDelete:   "CASCADE",
+	}}
+	if !reflect.DeepEqual(foreignKeys, want) {
+		t.Errorf("ForeignKeys() = %+v, want %+v", foreignKeys, want)
+	}
+}
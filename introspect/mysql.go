@@ -0,0 +1,115 @@
+package introspect
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// mysqlBackend MySQL 及兼容数据库（MariaDB/TiDB/OceanBase），读取 information_schema
+type mysqlBackend struct{}
+
+func (mysqlBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("table_name", name)
+	return scanTables(i.query(`
+		SELECT table_schema, table_name, table_comment
+		FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'`+filter+`
+		ORDER BY table_name
+	`, args...))
+}
+
+func (mysqlBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT table_schema, table_name, view_definition, ''
+		FROM information_schema.views
+		WHERE table_schema = DATABASE()
+		ORDER BY table_name
+	`))
+}
+
+func (mysqlBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`
+		SELECT column_name, ordinal_position, data_type, column_type,
+			is_nullable, column_default, column_comment, column_key, extra
+		FROM information_schema.columns
+		WHERE table_schema = DATABASE() AND table_name = ?
+		ORDER BY ordinal_position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var nullable, key string
+		if err := rows.Scan(&col.Name, &col.Position, &col.DataType, &col.ColumnType,
+			&nullable, &col.Default, &col.Comment, &key, &col.Extra); err != nil {
+			return nil, err
+		}
+		col.Nullable = nullable == "YES"
+		col.PrimaryKey = key == "PRI"
+		col.AutoIncrement = strings.Contains(strings.ToLower(col.Extra), "auto_increment")
+		if strings.EqualFold(col.DataType, "enum") {
+			col.EnumValues = enumValues(col.ColumnType)
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (mysqlBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	rows, err := i.query(`
+		SELECT index_name, non_unique, index_type, column_name
+		FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = ?
+		ORDER BY index_name, seq_in_index
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group indexGroup
+	for rows.Next() {
+		var index Index
+		var nonUnique int
+		// 函数索引（MySQL 8）的 column_name 为 NULL
+		var column sql.NullString
+		if err := rows.Scan(&index.Name, &nonUnique, &index.Type, &column); err != nil {
+			return nil, err
+		}
+		index.Unique = nonUnique == 0
+		index.Primary = index.Name == "PRIMARY"
+		group.add(index, column.String)
+	}
+	return group.indexes, rows.Err()
+}
+
+func (mysqlBackend) foreignKeys(i *Inspector, table string) ([]ForeignKey, error) {
+	rows, err := i.query(`
+		SELECT k.constraint_name, k.column_name, k.referenced_table_name, k.referenced_column_name,
+			r.update_rule, r.delete_rule
+		FROM information_schema.key_column_usage k
+		JOIN information_schema.referential_constraints r
+			ON r.constraint_schema = k.constraint_schema AND r.constraint_name = k.constraint_name
+		WHERE k.table_schema = DATABASE() AND k.table_name = ? AND k.referenced_table_name IS NOT NULL
+		ORDER BY k.constraint_name, k.ordinal_position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group foreignKeyGroup
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &column, &fk.RefTable, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		group.add(fk, column, refColumn)
+	}
+	return group.foreignKeys, rows.Err()
+}
@@ -0,0 +1,148 @@
+package introspect
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// oracleBackend Oracle，读取当前用户的 USER_* 数据字典视图
+type oracleBackend struct{}
+
+func (oracleBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("t.TABLE_NAME", name)
+	return scanTables(i.query(`
+		SELECT USER, t.TABLE_NAME, c.COMMENTS
+		FROM USER_TABLES t
+		LEFT JOIN USER_TAB_COMMENTS c ON c.TABLE_NAME = t.TABLE_NAME
+		WHERE t.NESTED = 'NO' AND t.SECONDARY = 'N'`+filter+`
+		ORDER BY t.TABLE_NAME
+	`, args...))
+}
+
+func (oracleBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT USER, v.VIEW_NAME, v.TEXT, c.COMMENTS
+		FROM USER_VIEWS v
+		LEFT JOIN USER_TAB_COMMENTS c ON c.TABLE_NAME = v.VIEW_NAME
+		ORDER BY v.VIEW_NAME
+	`))
+}
+
+func (oracleBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`
+		SELECT c.COLUMN_NAME, c.COLUMN_ID, c.DATA_TYPE, c.CHAR_LENGTH, c.DATA_PRECISION, c.DATA_SCALE,
+			c.NULLABLE, c.DATA_DEFAULT, cc.COMMENTS,
+			CASE WHEN p.COLUMN_NAME IS NULL THEN 0 ELSE 1 END
+		FROM USER_TAB_COLUMNS c
+		LEFT JOIN USER_COL_COMMENTS cc ON cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME
+		LEFT JOIN (
+			SELECT cols.COLUMN_NAME
+			FROM USER_CONSTRAINTS cons
+			JOIN USER_CONS_COLUMNS cols ON cols.CONSTRAINT_NAME = cons.CONSTRAINT_NAME
+			WHERE cons.CONSTRAINT_TYPE = 'P' AND cons.TABLE_NAME = ?
+		) p ON p.COLUMN_NAME = c.COLUMN_NAME
+		WHERE c.TABLE_NAME = ?
+		ORDER BY c.COLUMN_ID
+	`, table, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var length, precision, scale sql.NullInt64
+		var nullable string
+		var comment sql.NullString
+		var primaryKey int
+		if err := rows.Scan(&col.Name, &col.Position, &col.DataType, &length, &precision, &scale,
+			&nullable, &col.Default, &comment, &primaryKey); err != nil {
+			return nil, err
+		}
+		col.Nullable = nullable == "Y"
+		col.Comment = comment.String
+		col.PrimaryKey = primaryKey == 1
+		// DATA_DEFAULT 保留了建表语句中的原文，末尾常带换行
+		if col.Default.Valid {
+			col.Default.String = strings.TrimSpace(col.Default.String)
+		}
+		// 序列和 12c 的 identity 列默认值均为 序列名.nextval
+		col.AutoIncrement = strings.Contains(strings.ToUpper(col.Default.String), "NEXTVAL")
+
+		switch {
+		case strings.Contains(col.DataType, "CHAR"):
+			col.ColumnType = sizedType(col.DataType, length, sql.NullInt64{}, sql.NullInt64{})
+		case col.DataType == "NUMBER":
+			if scale.Valid && scale.Int64 == 0 {
+				scale = sql.NullInt64{}
+			}
+			col.ColumnType = sizedType(col.DataType, sql.NullInt64{}, precision, scale)
+		default:
+			col.ColumnType = col.DataType
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (oracleBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	rows, err := i.query(`
+		SELECT i.INDEX_NAME, i.UNIQUENESS, i.INDEX_TYPE, c.COLUMN_NAME,
+			CASE WHEN EXISTS (
+				SELECT 1 FROM USER_CONSTRAINTS k
+				WHERE k.CONSTRAINT_TYPE = 'P' AND k.TABLE_NAME = i.TABLE_NAME AND k.INDEX_NAME = i.INDEX_NAME
+			) THEN 1 ELSE 0 END
+		FROM USER_INDEXES i
+		JOIN USER_IND_COLUMNS c ON c.INDEX_NAME = i.INDEX_NAME
+		WHERE i.TABLE_NAME = ?
+		ORDER BY i.INDEX_NAME, c.COLUMN_POSITION
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group indexGroup
+	for rows.Next() {
+		var index Index
+		var uniqueness, column string
+		var primary int
+		if err := rows.Scan(&index.Name, &uniqueness, &index.Type, &column, &primary); err != nil {
+			return nil, err
+		}
+		index.Unique = uniqueness == "UNIQUE"
+		index.Primary = primary == 1
+		group.add(index, column)
+	}
+	return group.indexes, rows.Err()
+}
+
+func (oracleBackend) foreignKeys(i *Inspector, table string) ([]ForeignKey, error) {
+	rows, err := i.query(`
+		SELECT cons.CONSTRAINT_NAME, cols.COLUMN_NAME, rcons.TABLE_NAME, rcols.COLUMN_NAME, cons.DELETE_RULE
+		FROM USER_CONSTRAINTS cons
+		JOIN USER_CONS_COLUMNS cols ON cols.CONSTRAINT_NAME = cons.CONSTRAINT_NAME
+		JOIN USER_CONSTRAINTS rcons ON rcons.CONSTRAINT_NAME = cons.R_CONSTRAINT_NAME
+		JOIN USER_CONS_COLUMNS rcols ON rcols.CONSTRAINT_NAME = rcons.CONSTRAINT_NAME AND rcols.POSITION = cols.POSITION
+		WHERE cons.CONSTRAINT_TYPE = 'R' AND cons.TABLE_NAME = ?
+		ORDER BY cons.CONSTRAINT_NAME, cols.POSITION
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group foreignKeyGroup
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &column, &fk.RefTable, &refColumn, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		// Oracle 不支持 ON UPDATE
+		fk.OnUpdate = "NO ACTION"
+		group.add(fk, column, refColumn)
+	}
+	return group.foreignKeys, rows.Err()
+}
@@ -0,0 +1,157 @@
+package introspect
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+)
+
+// postgresBackend PostgreSQL，读取当前 schema 的 pg_catalog 和 information_schema
+type postgresBackend struct{}
+
+func (postgresBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("c.relname", name)
+	return scanTables(i.query(`
+		SELECT n.nspname, c.relname, obj_description(c.oid, 'pg_class')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p') AND NOT c.relispartition`+filter+`
+		ORDER BY c.relname
+	`, args...))
+}
+
+func (postgresBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT n.nspname, c.relname, pg_get_viewdef(c.oid, true), obj_description(c.oid, 'pg_class')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('v', 'm')
+		ORDER BY c.relname
+	`))
+}
+
+func (postgresBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`
+		SELECT a.attname, c.ordinal_position, c.data_type, c.udt_name, c.is_nullable, c.column_default,
+			c.character_maximum_length, c.numeric_precision, c.numeric_scale, c.is_identity,
+			col_description(a.attrelid, a.attnum),
+			(SELECT json_agg(e.enumlabel ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = a.atttypid),
+			EXISTS (
+				SELECT 1 FROM pg_index i
+				WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+			)
+		FROM information_schema.columns c
+		JOIN pg_namespace n ON n.nspname = c.table_schema
+		JOIN pg_class t ON t.relnamespace = n.oid AND t.relname = c.table_name
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attname = c.column_name
+		WHERE c.table_schema = current_schema() AND c.table_name = ?
+		ORDER BY c.ordinal_position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var nullable, identity string
+		var length, precision, scale sql.NullInt64
+		var comment, labels sql.NullString
+		if err := rows.Scan(&col.Name, &col.Position, &col.DataType, &col.UDTName, &nullable, &col.Default,
+			&length, &precision, &scale, &identity, &comment, &labels, &col.PrimaryKey); err != nil {
+			return nil, err
+		}
+		if labels.Valid {
+			if err := json.Unmarshal([]byte(labels.String), &col.EnumValues); err != nil {
+				return nil, err
+			}
+		}
+		col.Nullable = nullable == "YES"
+		col.Comment = comment.String
+		col.AutoIncrement = identity == "YES" || strings.HasPrefix(col.Default.String, "nextval(")
+
+		switch col.DataType {
+		case "character varying", "character", "varchar", "char":
+			col.ColumnType = sizedType(col.DataType, length, sql.NullInt64{}, sql.NullInt64{})
+		case "numeric", "decimal":
+			col.ColumnType = sizedType(col.DataType, sql.NullInt64{}, precision, scale)
+		case "USER-DEFINED", "ARRAY":
+			col.ColumnType = col.UDTName
+		default:
+			col.ColumnType = col.DataType
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (postgresBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	rows, err := i.query(`
+		SELECT ic.relname, ix.indisunique, ix.indisprimary, am.amname, a.attname
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class ic ON ic.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = ic.relam
+		JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = current_schema() AND t.relname = ?
+		ORDER BY ic.relname, k.ord
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group indexGroup
+	for rows.Next() {
+		var index Index
+		var column string
+		if err := rows.Scan(&index.Name, &index.Unique, &index.Primary, &index.Type, &column); err != nil {
+			return nil, err
+		}
+		group.add(index, column)
+	}
+	return group.indexes, rows.Err()
+}
+
+// postgresActions pg_constraint 中外键动作代码对应的规则
+var postgresActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+func (postgresBackend) foreignKeys(i *Inspector, table string) ([]ForeignKey, error) {
+	rows, err := i.query(`
+		SELECT con.conname, a.attname, ref.relname, ra.attname, con.confupdtype::text, con.confdeltype::text
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class ref ON ref.oid = con.confrelid
+		JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum
+		WHERE con.contype = 'f' AND n.nspname = current_schema() AND t.relname = ?
+		ORDER BY con.conname, k.ord
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group foreignKeyGroup
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&fk.Name, &column, &fk.RefTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return nil, err
+		}
+		fk.OnUpdate, fk.OnDelete = postgresActions[onUpdate], postgresActions[onDelete]
+		group.add(fk, column, refColumn)
+	}
+	return group.foreignKeys, rows.Err()
+}
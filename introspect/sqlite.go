@@ -0,0 +1,168 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sqliteBackend SQLite，读取 sqlite_master 和 pragma 表值函数（需要 SQLite 3.16+）
+type sqliteBackend struct{}
+
+func (sqliteBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("name", name)
+	// SQLite 没有表注释
+	return scanTables(i.query(`
+		SELECT 'main', name, ''
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`+filter+`
+		ORDER BY name
+	`, args...))
+}
+
+func (sqliteBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT 'main', name, sql, ''
+		FROM sqlite_master
+		WHERE type = 'view'
+		ORDER BY name
+	`))
+}
+
+func (sqliteBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`SELECT cid, name, type, "notnull", dflt_value, pk FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		columns []Column
+		pkCount int
+	)
+	for rows.Next() {
+		var col Column
+		var notNull, pk int
+		if err := rows.Scan(&col.Position, &col.Name, &col.DataType, &notNull, &col.Default, &pk); err != nil {
+			return nil, err
+		}
+		col.Position++
+		col.ColumnType = col.DataType
+		col.Nullable = notNull == 0
+		col.PrimaryKey = pk > 0
+		if col.PrimaryKey {
+			pkCount++
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 单列 INTEGER PRIMARY KEY 是 rowid 的别名，插入时自动分配，不能为 NULL
+	if pkCount == 1 {
+		for n := range columns {
+			if columns[n].PrimaryKey && strings.EqualFold(columns[n].DataType, "INTEGER") {
+				columns[n].AutoIncrement = true
+				columns[n].Nullable = false
+			}
+		}
+	}
+
+	// CHECK (列 IN ('a', 'b')) 约束的列视为枚举列
+	ddlRows, err := i.query(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer ddlRows.Close()
+	var ddl sql.NullString
+	if ddlRows.Next() {
+		if err := ddlRows.Scan(&ddl); err != nil {
+			return nil, err
+		}
+	}
+	for n := range columns {
+		columns[n].EnumValues = checkEnumValues(ddl.String, columns[n].Name)
+	}
+	return columns, nil
+}
+
+func (sqliteBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	rows, err := i.query(`SELECT name, "unique", origin FROM pragma_index_list(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []Index
+	for rows.Next() {
+		var index Index
+		var origin string
+		if err := rows.Scan(&index.Name, &index.Unique, &origin); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index.Type = "BTREE"
+		index.Primary = origin == "pk"
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for n := range indexes {
+		if indexes[n].Columns, err = sqliteIndexColumns(i, indexes[n].Name); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(indexes, func(a, b int) bool { return indexes[a].Name < indexes[b].Name })
+	return indexes, nil
+}
+
+// sqliteIndexColumns 查询索引列，表达式索引的列名为空
+func sqliteIndexColumns(i *Inspector, index string) ([]string, error) {
+	rows, err := i.query(`SELECT name FROM pragma_index_info(?) ORDER BY seqno`, index)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column sql.NullString
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		if column.Valid {
+			columns = append(columns, column.String)
+		}
+	}
+	return columns, rows.Err()
+}
+
+func (sqliteBackend) foreignKeys(i *Inspector, table string) ([]ForeignKey, error) {
+	rows, err := i.query(`
+		SELECT id, "table", "from", "to", on_update, on_delete
+		FROM pragma_foreign_key_list(?)
+		ORDER BY id, seq
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group foreignKeyGroup
+	for rows.Next() {
+		var fk ForeignKey
+		var id int
+		var column string
+		// 未声明引用列时 to 为 NULL，表示引用主键
+		var refColumn sql.NullString
+		if err := rows.Scan(&id, &fk.RefTable, &column, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		fk.Name = fmt.Sprintf("fk_%s_%d", table, id)
+		group.add(fk, column, refColumn.String)
+	}
+	return group.foreignKeys, rows.Err()
+}
@@ -0,0 +1,179 @@
+package introspect
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// sqlserverBackend SQL Server，读取当前用户默认 schema 的 sys 目录视图
+type sqlserverBackend struct{}
+
+// sqlserverObject 当前 schema 下表的 object_id 表达式，参数为表名
+const sqlserverObject = `OBJECT_ID(QUOTENAME(SCHEMA_NAME()) + '.' + QUOTENAME(?))`
+
+func (sqlserverBackend) tables(i *Inspector, name string) ([]Table, error) {
+	filter, args := tableFilter("t.name", name)
+	return scanTables(i.query(`
+		SELECT s.name, t.name, CAST(ep.value AS NVARCHAR(MAX))
+		FROM sys.tables t
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		LEFT JOIN sys.extended_properties ep
+			ON ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE s.name = SCHEMA_NAME()`+filter+`
+		ORDER BY t.name
+	`, args...))
+}
+
+func (sqlserverBackend) views(i *Inspector) ([]View, error) {
+	return scanViews(i.query(`
+		SELECT s.name, v.name, OBJECT_DEFINITION(v.object_id), CAST(ep.value AS NVARCHAR(MAX))
+		FROM sys.views v
+		JOIN sys.schemas s ON s.schema_id = v.schema_id
+		LEFT JOIN sys.extended_properties ep
+			ON ep.major_id = v.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE s.name = SCHEMA_NAME()
+		ORDER BY v.name
+	`))
+}
+
+func (sqlserverBackend) columns(i *Inspector, table string) ([]Column, error) {
+	rows, err := i.query(`
+		SELECT c.name, c.column_id, ty.name, c.max_length, c.precision, c.scale, c.is_nullable,
+			OBJECT_DEFINITION(c.default_object_id), CAST(ep.value AS NVARCHAR(MAX)), c.is_identity,
+			CASE WHEN EXISTS (
+				SELECT 1 FROM sys.indexes i
+				JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+				WHERE i.object_id = c.object_id AND i.is_primary_key = 1 AND ic.column_id = c.column_id
+			) THEN 1 ELSE 0 END
+		FROM sys.columns c
+		JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+		LEFT JOIN sys.extended_properties ep
+			ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+		WHERE c.object_id = `+sqlserverObject+`
+		ORDER BY c.column_id
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		var maxLength, precision, scale int64
+		var comment sql.NullString
+		var primaryKey int
+		if err := rows.Scan(&col.Name, &col.Position, &col.DataType, &maxLength, &precision, &scale,
+			&col.Nullable, &col.Default, &comment, &col.AutoIncrement, &primaryKey); err != nil {
+			return nil, err
+		}
+		col.Comment = comment.String
+		col.PrimaryKey = primaryKey == 1
+		col.ColumnType = sqlserverColumnType(col.DataType, maxLength, precision, scale)
+		// 默认值约束定义带有括号，如 ((0))、('abc')
+		if col.Default.Valid {
+			col.Default.String = trimParens(col.Default.String)
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// sqlserverColumnType 根据 sys.columns 的长度和精度拼接完整类型，max_length 为 -1 表示 MAX
+func sqlserverColumnType(dataType string, maxLength, precision, scale int64) string {
+	switch dataType {
+	case "varchar", "char", "varbinary", "binary":
+		if maxLength < 0 {
+			return dataType + "(MAX)"
+		}
+		return sizedType(dataType, sql.NullInt64{Int64: maxLength, Valid: true}, sql.NullInt64{}, sql.NullInt64{})
+	case "nvarchar", "nchar":
+		// max_length 为字节数，Unicode 类型每个字符占两个字节
+		if maxLength < 0 {
+			return dataType + "(MAX)"
+		}
+		return sizedType(dataType, sql.NullInt64{Int64: maxLength / 2, Valid: true}, sql.NullInt64{}, sql.NullInt64{})
+	case "decimal", "numeric":
+		return sizedType(dataType, sql.NullInt64{}, sql.NullInt64{Int64: precision, Valid: true}, sql.NullInt64{Int64: scale, Valid: true})
+	}
+	return dataType
+}
+
+// trimParens 去掉包裹整个表达式的括号
+func trimParens(s string) string {
+	for len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		depth := 0
+		for n := 0; n < len(s)-1; n++ {
+			switch s[n] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			// 首个括号在末尾之前闭合，如 (a)+(b)
+			if depth == 0 {
+				return s
+			}
+		}
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+func (sqlserverBackend) indexes(i *Inspector, table string) ([]Index, error) {
+	rows, err := i.query(`
+		SELECT i.name, i.is_unique, i.is_primary_key, i.type_desc, c.name
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = `+sqlserverObject+` AND ic.is_included_column = 0
+		ORDER BY i.name, ic.key_ordinal
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group indexGroup
+	for rows.Next() {
+		var index Index
+		var column string
+		if err := rows.Scan(&index.Name, &index.Unique, &index.Primary, &index.Type, &column); err != nil {
+			return nil, err
+		}
+		group.add(index, column)
+	}
+	return group.indexes, rows.Err()
+}
+
+func (sqlserverBackend) foreignKeys(i *Inspector, table string) ([]ForeignKey, error) {
+	rows, err := i.query(`
+		SELECT fk.name, pc.name, rt.name, rc.name,
+			fk.update_referential_action_desc, fk.delete_referential_action_desc
+		FROM sys.foreign_keys fk
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+		JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+		JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE fk.parent_object_id = `+sqlserverObject+`
+		ORDER BY fk.name, fkc.constraint_column_id
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var group foreignKeyGroup
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &column, &fk.RefTable, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		// NO_ACTION => NO ACTION
+		fk.OnUpdate = strings.ReplaceAll(fk.OnUpdate, "_", " ")
+		fk.OnDelete = strings.ReplaceAll(fk.OnDelete, "_", " ")
+		group.add(fk, column, refColumn)
+	}
+	return group.foreignKeys, rows.Err()
+}
@@ -0,0 +1,278 @@
+package gosqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ==================== 连接占用跟踪 ====================
+
+// 连接占用类型常量
+const (
+	HeldTransaction = "transaction" // Begin/Transaction 开启的事务
+	HeldSession     = "session"     // 会话级锁等独占的连接
+)
+
+// HeldConn 被占用的连接
+type HeldConn struct {
+	ID     int64     // 占用编号
+	Nick   string    // 数据库别名
+	Mode   string    // 读写模式
+	Kind   string    // 占用类型
+	Label  string    // 占用说明，如锁名
+	Since  time.Time // 获取时间
+	Caller string    // 获取连接的调用位置
+	Stack  string    // 获取连接时的调用栈，仅在开启泄漏检测后记录
+}
+
+// Age 已占用时长
+func (h HeldConn) Age() time.Duration {
+	return time.Since(h.Since)
+}
+
+// String 返回可读的占用描述
+func (h HeldConn) String() string {
+	desc := fmt.Sprintf("[%s/%s] %s", h.Nick, h.Mode, h.Kind)
+	if h.Label != "" {
+		desc += "(" + h.Label + ")"
+	}
+	return fmt.Sprintf("%s 已占用 %s，获取位置 %s", desc, h.Age().Round(time.Millisecond), h.Caller)
+}
+
+// PoolStats 连接池统计
+type PoolStats struct {
+	sql.DBStats
+	Nick string       // 数据库别名
+	Mode string       // 读写模式
+	Type DatabaseType // 数据库类型
+	// Held 通过事务、会话锁等方式占用的连接，按占用时长降序
+	Held []HeldConn
+	// Untracked 使用中但未被跟踪的连接数，通常为未关闭的结果集（Query 返回的 *sql.Rows）
+	Untracked int
+}
+
+// LongHeld 返回占用时长超过 threshold 的连接
+func (s PoolStats) LongHeld(threshold time.Duration) []HeldConn {
+	var held []HeldConn
+	for _, conn := range s.Held {
+		if conn.Age() >= threshold {
+			held = append(held, conn)
+		}
+	}
+	return held
+}
+
+// connTracker 连接占用跟踪器，由同一连接池的所有 Database 副本共享
+type connTracker struct {
+	mutex        sync.Mutex
+	nextID       int64
+	held         map[int64]*HeldConn
+	reported     map[int64]bool
+	captureStack atomic.Bool
+}
+
+// newConnTracker 创建连接占用跟踪器
+func newConnTracker() *connTracker {
+	return &connTracker{held: make(map[int64]*HeldConn), reported: make(map[int64]bool)}
+}
+
+// track 记录一次连接占用，返回释放函数（可重复调用）
+func (d *Database) track(kind, label string) func() {
+	t := d.conns
+	if t == nil {
+		return func() {}
+	}
+
+	held := &HeldConn{Kind: kind, Label: label, Since: time.Now(), Caller: callerOutside()}
+	if d.ctx != nil {
+		held.Nick, held.Mode = d.ctx.Nick, d.ctx.Mode
+	}
+	if t.captureStack.Load() {
+		buf := make([]byte, 16*1024)
+		held.Stack = string(buf[:runtime.Stack(buf, false)])
+	}
+
+	t.mutex.Lock()
+	t.nextID++
+	held.ID = t.nextID
+	t.held[held.ID] = held
+	t.mutex.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mutex.Lock()
+			delete(t.held, held.ID)
+			delete(t.reported, held.ID)
+			t.mutex.Unlock()
+		})
+	}
+}
+
+// list 返回当前占用的连接，按占用时长降序
+func (t *connTracker) list() []HeldConn {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	held := make([]HeldConn, 0, len(t.held))
+	for _, conn := range t.held {
+		held = append(held, *conn)
+	}
+	sort.Slice(held, func(i, j int) bool { return held[i].Since.Before(held[j].Since) })
+	return held
+}
+
+// unreported 返回超过阈值且尚未报告的连接，并标记为已报告
+func (t *connTracker) unreported(threshold time.Duration) []HeldConn {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var leaks []HeldConn
+	for id, conn := range t.held {
+		if !t.reported[id] && conn.Age() >= threshold {
+			t.reported[id] = true
+			leaks = append(leaks, *conn)
+		}
+	}
+	sort.Slice(leaks, func(i, j int) bool { return leaks[i].Since.Before(leaks[j].Since) })
+	return leaks
+}
+
+// callerOutside 返回 gosqlx 包和 GORM 之外的首个调用位置
+func callerOutside() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/gzorm/gosqlx.") && !strings.HasPrefix(frame.Function, "gorm.io/") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// Stats 返回连接池统计和被占用的连接
+func (d *Database) Stats() PoolStats {
+	stats := PoolStats{Type: d.dbType}
+	if d.ctx != nil {
+		stats.Nick, stats.Mode = d.ctx.Nick, d.ctx.Mode
+	}
+	if d.sqlDB != nil {
+		stats.DBStats = d.sqlDB.Stats()
+	}
+	if d.conns != nil {
+		stats.Held = d.conns.list()
+	}
+	stats.Untracked = max(stats.InUse-len(stats.Held), 0)
+	return stats
+}
+
+// Stats 返回所有已创建数据库的连接池统计，按别名排序
+func (m *DatabaseManager) Stats() []PoolStats {
+	m.mutex.RLock()
+	stats := make([]PoolStats, 0, len(m.databases))
+	for _, db := range m.databases {
+		stats = append(stats, db.Stats())
+	}
+	m.mutex.RUnlock()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Nick != stats[j].Nick {
+			return stats[i].Nick < stats[j].Nick
+		}
+		return stats[i].Mode < stats[j].Mode
+	})
+	return stats
+}
+
+// ==================== 泄漏检测 ====================
+
+// LeakOptions 泄漏检测选项
+type LeakOptions struct {
+	Threshold time.Duration                            // 占用超过该时长视为疑似泄漏，默认 1 分钟
+	Interval  time.Duration                            // 检查间隔，默认为 Threshold 的一半
+	Logger    func(format string, args ...interface{}) // 日志输出，默认 log.Printf
+}
+
+// withDefaults 填充默认值
+func (o LeakOptions) withDefaults() LeakOptions {
+	if o.Threshold <= 0 {
+		o.Threshold = time.Minute
+	}
+	if o.Interval <= 0 {
+		o.Interval = o.Threshold / 2
+	}
+	if o.Logger == nil {
+		o.Logger = log.Printf
+	}
+	return o
+}
+
+// DetectLeaks 开启泄漏检测，定期输出占用超过阈值的连接及其获取时的调用栈，每个连接只报告一次
+// 开启后获取连接时会记录调用栈；返回的函数用于停止检测
+func (d *Database) DetectLeaks(opts LeakOptions) (stop func()) {
+	opts = opts.withDefaults()
+	return runLeakDetector(opts, func() []*Database { return []*Database{d} })
+}
+
+// DetectLeaks 对管理器中的所有数据库（包括之后创建的）开启泄漏检测
+func (m *DatabaseManager) DetectLeaks(opts LeakOptions) (stop func()) {
+	opts = opts.withDefaults()
+	return runLeakDetector(opts, func() []*Database {
+		m.mutex.RLock()
+		defer m.mutex.RUnlock()
+		databases := make([]*Database, 0, len(m.databases))
+		for _, db := range m.databases {
+			databases = append(databases, db)
+		}
+		return databases
+	})
+}
+
+// runLeakDetector 启动检测协程
+func runLeakDetector(opts LeakOptions, databases func() []*Database) func() {
+	enable := func(on bool) {
+		for _, db := range databases() {
+			if db.conns != nil {
+				db.conns.captureStack.Store(on)
+			}
+		}
+	}
+	enable(true)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				enable(true)
+				for _, db := range databases() {
+					if db.conns == nil {
+						continue
+					}
+					for _, leak := range db.conns.unreported(opts.Threshold) {
+						opts.Logger("gosqlx: 疑似连接泄漏 %s\n%s", leak, leak.Stack)
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			enable(false)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package gosqlx
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// ==================== 分布式锁 ====================
+
+// ErrLockNotHeld 释放未持有的锁时返回的错误
+var ErrLockNotHeld = errors.New("未持有该锁")
+
+// LockTableName 通用实现使用的锁表名
+const LockTableName = "gosqlx_locks"
+
+// lockRecord 锁表记录
+type lockRecord struct {
+	Name      string `gorm:"column:name;primaryKey;size:191"`
+	Owner     string `gorm:"column:owner;size:64;not null"`
+	ExpiresAt int64  `gorm:"column:expires_at;not null"`
+}
+
+// TableName 锁表名
+func (lockRecord) TableName() string {
+	return LockTableName
+}
+
+// heldLock 已持有的锁
+type heldLock struct {
+	conn    *sql.Conn   // 会话级锁占用的连接
+	timer   *time.Timer // 到期自动释放定时器
+	release func()      // 释放连接占用记录
+}
+
+// Locker 基于数据库的分布式锁
+// Postgres 使用 advisory lock，MySQL 系使用 GET_LOCK，SQLServer 使用 sp_getapplock，
+// 其他数据库使用锁表实现。会话级锁在持有期间独占一个连接，到达 ttl 后自动释放
+type Locker struct {
+	db        *Database
+	owner     string
+	mutex     sync.Mutex
+	held      map[string]*heldLock
+	tableOnce sync.Once
+	tableErr  error
+}
+
+// NewLocker 创建分布式锁
+func NewLocker(db *Database) *Locker {
+	owner := make([]byte, 16)
+	_, _ = rand.Read(owner)
+	return &Locker{
+		db:    db,
+		owner: hex.EncodeToString(owner),
+		held:  make(map[string]*heldLock),
+	}
+}
+
+// AcquireLock 尝试获取锁（不等待），获取成功返回true
+// ttl 为锁的最长持有时间，到期后自动释放；重复获取已持有的锁会刷新到期时间
+func (l *Locker) AcquireLock(name string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, errors.New("锁的有效期必须大于0")
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if lock, ok := l.held[name]; ok && lock.conn != nil {
+		lock.timer.Reset(ttl)
+		return true, nil
+	}
+
+	switch l.db.Type() {
+	case PostgresSQL, MySQL, MariaDB, TiDB, OceanBase, SQLServer:
+		return l.acquireSession(name, ttl)
+	default:
+		return l.acquireTable(name, ttl)
+	}
+}
+
+// ReleaseLock 释放锁
+func (l *Locker) ReleaseLock(name string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.release(name)
+}
+
+// WithLock 获取锁后执行函数并释放锁，未获取到锁时返回false且不执行
+func (l *Locker) WithLock(name string, ttl time.Duration, fn func() error) (bool, error) {
+	ok, err := l.AcquireLock(name, ttl)
+	if err != nil || !ok {
+		return ok, err
+	}
+	defer l.ReleaseLock(name)
+	return true, fn()
+}
+
+// release 释放锁（调用方持有互斥锁）
+func (l *Locker) release(name string) error {
+	lock, ok := l.held[name]
+	if !ok {
+		return ErrLockNotHeld
+	}
+	delete(l.held, name)
+	if lock.timer != nil {
+		lock.timer.Stop()
+	}
+
+	if lock.conn == nil {
+		result := l.db.db.Where("name = ? AND owner = ?", name, l.owner).Delete(&lockRecord{})
+		if result.Error != nil {
+			return fmt.Errorf("释放锁失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrLockNotHeld // 已过期并被其他实例获取
+		}
+		return nil
+	}
+
+	defer lock.release()
+	defer lock.conn.Close()
+	ctx := l.context()
+	var err error
+	switch l.db.Type() {
+	case PostgresSQL:
+		_, err = lock.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey(name))
+	case SQLServer:
+		_, err = lock.conn.ExecContext(ctx, "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", name)
+	default:
+		_, err = lock.conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockName(name))
+	}
+	if err != nil {
+		return fmt.Errorf("释放锁失败: %w", err)
+	}
+	return nil
+}
+
+// acquireSession 使用数据库会话级锁
+func (l *Locker) acquireSession(name string, ttl time.Duration) (bool, error) {
+	ctx := l.context()
+	conn, err := l.db.sqlDB.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("获取连接失败: %w", err)
+	}
+
+	var acquired bool
+	switch l.db.Type() {
+	case PostgresSQL:
+		err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey(name)).Scan(&acquired)
+	case SQLServer:
+		var code int
+		err = conn.QueryRowContext(ctx, `DECLARE @result INT;
+EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 0;
+SELECT @result`, name).Scan(&code)
+		acquired = code >= 0
+	default:
+		var result sql.NullInt64
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", lockName(name)).Scan(&result)
+		acquired = result.Valid && result.Int64 == 1
+	}
+	if err != nil || !acquired {
+		conn.Close()
+		if err != nil {
+			return false, fmt.Errorf("获取锁失败: %w", err)
+		}
+		return false, nil
+	}
+
+	lock := &heldLock{conn: conn, release: l.db.track(HeldSession, "lock:"+name)}
+	lock.timer = time.AfterFunc(ttl, func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		// 锁可能已被释放并重新获取，只释放本次获取的锁
+		if l.held[name] == lock {
+			_ = l.release(name)
+		}
+	})
+	l.held[name] = lock
+	return true, nil
+}
+
+// acquireTable 使用锁表实现
+func (l *Locker) acquireTable(name string, ttl time.Duration) (bool, error) {
+	l.tableOnce.Do(func() {
+		if !l.db.db.Migrator().HasTable(&lockRecord{}) {
+			l.tableErr = l.db.db.Migrator().CreateTable(&lockRecord{})
+		}
+	})
+	if l.tableErr != nil {
+		return false, fmt.Errorf("创建锁表失败: %w", l.tableErr)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl).UnixMilli()
+
+	// 续期自己持有的锁
+	result := l.db.db.Model(&lockRecord{}).
+		Where("name = ? AND owner = ?", name, l.owner).
+		Update("expires_at", expiresAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("获取锁失败: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		l.held[name] = &heldLock{}
+		return true, nil
+	}
+
+	// 清理过期锁后尝试插入
+	if err := l.db.db.Where("name = ? AND expires_at < ?", name, now.UnixMilli()).Delete(&lockRecord{}).Error; err != nil {
+		return false, fmt.Errorf("清理过期锁失败: %w", err)
+	}
+	if err := l.db.db.Create(&lockRecord{Name: name, Owner: l.owner, ExpiresAt: expiresAt}).Error; err != nil {
+		var count int64
+		if l.db.db.Model(&lockRecord{}).Where("name = ?", name).Count(&count).Error == nil && count > 0 {
+			return false, nil // 已被其他实例持有
+		}
+		return false, fmt.Errorf("获取锁失败: %w", err)
+	}
+	l.held[name] = &heldLock{}
+	return true, nil
+}
+
+// context 获取锁操作使用的上下文
+func (l *Locker) context() context.Context {
+	if l.db.ctx != nil {
+		return l.db.ctx.parent()
+	}
+	return context.Background()
+}
+
+// lockKey 将锁名称转换为 advisory lock 使用的整数键
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// lockName MySQL 锁名最长64个字符，超长时使用哈希
+func lockName(name string) string {
+	if len(name) <= 64 {
+		return name
+	}
+	return fmt.Sprintf("gosqlx_%x", uint64(lockKey(name)))
+}
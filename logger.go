@@ -0,0 +1,191 @@
+package gosqlx
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gorm.io/gorm/logger"
+)
+
+// ==================== 日志脱敏 ====================
+
+// newLogger 根据配置创建 GORM 日志
+// Debug 输出全部语句，SlowThreshold 大于 0 时输出慢查询；LogParameterValues 为 false 时隐藏参数值和字面量
+func newLogger(config *Config) logger.Interface {
+	level := logger.Silent
+	if config.SlowThreshold > 0 {
+		level = logger.Warn
+	}
+	if config.Debug {
+		level = logger.Info
+	}
+
+	var l logger.Interface
+	if config.SlowThreshold > 0 {
+		l = logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold: config.SlowThreshold,
+			LogLevel:      level,
+			Colorful:      true,
+		})
+	} else {
+		l = logger.Default.LogMode(level)
+	}
+
+	if level == logger.Silent || config.LogParameterValues {
+		return l
+	}
+	return NewSanitizedLogger(l, config.LogParameterHash)
+}
+
+// sanitizedLogger 在输出语句前隐藏参数值和字面量
+type sanitizedLogger struct {
+	logger.Interface
+	hash bool
+}
+
+// NewSanitizedLogger 包装 GORM 日志，输出的语句中参数值和字面量替换为 ?
+// hash 为 true 时替换为取值的哈希，相同取值得到相同的占位符，便于关联而不泄露内容
+func NewSanitizedLogger(l logger.Interface, hash bool) logger.Interface {
+	return sanitizedLogger{Interface: l, hash: hash}
+}
+
+// LogMode 设置日志级别
+func (l sanitizedLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return sanitizedLogger{Interface: l.Interface.LogMode(level), hash: l.hash}
+}
+
+// ParamsFilter 在 GORM 展开参数前隐藏参数值和SQL中的字面量
+func (l sanitizedLogger) ParamsFilter(_ context.Context, sqlStr string, params ...interface{}) (string, []interface{}) {
+	sqlStr = SanitizeSQL(sqlStr, l.hash)
+	if !l.hash {
+		return sqlStr, nil
+	}
+	hashed := make([]interface{}, len(params))
+	for i, param := range params {
+		if value, ok := param.(driver.Valuer); ok {
+			param, _ = value.Value()
+		}
+		switch v := param.(type) {
+		case nil:
+			hashed[i] = nil
+		case []byte:
+			hashed[i] = hashLiteral(string(v))
+		default:
+			hashed[i] = hashLiteral(fmt.Sprint(v))
+		}
+	}
+	return sqlStr, hashed
+}
+
+// SanitizeSQL 将SQL中的字符串和数字字面量替换为 ?，hash 为 true 时替换为 '#哈希'
+// 标识符（"name"、`name`、[name]）、注释和占位符（?、$1、:name、@p1）保持不变
+func SanitizeSQL(sqlStr string, hash bool) string {
+	var b strings.Builder
+	b.Grow(len(sqlStr))
+	placeholder := func(literal string) {
+		if hash {
+			b.WriteString("'" + hashLiteral(literal) + "'")
+		} else {
+			b.WriteByte('?')
+		}
+	}
+
+	for i := 0; i < len(sqlStr); {
+		c := sqlStr[i]
+		switch {
+		case c == '\'':
+			// 字符串字面量，'' 和 \' 为转义
+			var value strings.Builder
+			j := i + 1
+			for j < len(sqlStr) {
+				if sqlStr[j] == '\\' && j+1 < len(sqlStr) {
+					value.WriteByte(sqlStr[j+1])
+					j += 2
+					continue
+				}
+				if sqlStr[j] == '\'' {
+					if j+1 < len(sqlStr) && sqlStr[j+1] == '\'' {
+						value.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				value.WriteByte(sqlStr[j])
+				j++
+			}
+			placeholder(value.String())
+			i = j + 1
+		case c == '"' || c == '`' || c == '[':
+			end := byte(c)
+			if c == '[' {
+				end = ']'
+			}
+			j := strings.IndexByte(sqlStr[i+1:], end)
+			if j < 0 {
+				b.WriteString(sqlStr[i:])
+				return b.String()
+			}
+			b.WriteString(sqlStr[i : i+j+2])
+			i += j + 2
+		case c == '-' && strings.HasPrefix(sqlStr[i:], "--"):
+			j := strings.IndexByte(sqlStr[i:], '\n')
+			if j < 0 {
+				b.WriteString(sqlStr[i:])
+				return b.String()
+			}
+			b.WriteString(sqlStr[i : i+j])
+			i += j
+		case c == '/' && strings.HasPrefix(sqlStr[i:], "/*"):
+			j := strings.Index(sqlStr[i+2:], "*/")
+			if j < 0 {
+				b.WriteString(sqlStr[i:])
+				return b.String()
+			}
+			b.WriteString(sqlStr[i : i+j+4])
+			i += j + 4
+		case isIdentByte(c) && !isDigit(c), c == '$', c == ':', c == '@':
+			// 标识符和命名占位符整体跳过，其中的数字不是字面量
+			j := i + 1
+			for j < len(sqlStr) && isIdentByte(sqlStr[j]) {
+				j++
+			}
+			b.WriteString(sqlStr[i:j])
+			i = j
+		case isDigit(c) || (c == '.' && i+1 < len(sqlStr) && isDigit(sqlStr[i+1])):
+			j := i + 1
+			for j < len(sqlStr) && (isIdentByte(sqlStr[j]) || sqlStr[j] == '.' ||
+				((sqlStr[j] == '+' || sqlStr[j] == '-') && (sqlStr[j-1] == 'e' || sqlStr[j-1] == 'E'))) {
+				j++
+			}
+			placeholder(sqlStr[i:j])
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// hashLiteral 返回取值的短哈希
+func hashLiteral(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "#" + hex.EncodeToString(sum[:4])
+}
+
+// isIdentByte 判断是否为标识符字符
+func isIdentByte(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// isDigit 判断是否为数字
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
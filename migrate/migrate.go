@@ -0,0 +1,361 @@
+// Package migrate 基于SQL文件的数据库迁移
+//
+// 迁移文件放在同一目录下，命名为 <版本号>_<名称>.up.sql 和 <版本号>_<名称>.down.sql，
+// 版本号为正整数（如 001 或 20240101120000），按版本号从小到大执行，已执行的版本记录在 gosqlx_migrations 表中:
+//
+//	migrations, err := migrate.Load("./migrations")
+//	applied, err := migrate.New(db, "mysql").Up(migrations, 0)
+//
+// 每个迁移在一个事务中执行，文件中的多条语句以分号分隔；
+// MySQL、Oracle 等数据库的 DDL 会隐式提交，迁移失败时需手动清理已执行的语句
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx/builder"
+	"github.com/gzorm/gosqlx/introspect"
+)
+
+// DefaultTable 默认的迁移记录表
+const DefaultTable = "gosqlx_migrations"
+
+// fileRe 迁移文件名格式
+var fileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration 迁移
+type Migration struct {
+	Version int64  // 版本号
+	Name    string // 名称
+	Up      string // 升级SQL
+	Down    string // 回滚SQL，没有 .down.sql 文件时为空
+}
+
+// Status 迁移状态
+type Status struct {
+	Migration
+	Applied   bool      // 是否已执行
+	AppliedAt time.Time // 执行时间
+}
+
+// Load 读取目录中的迁移文件，按版本号排序
+func Load(dir string) ([]Migration, error) {
+	return LoadFS(os.DirFS(dir), ".")
+}
+
+// LoadFS 读取文件系统中的迁移文件，可配合 embed.FS 将迁移打包进程序
+func LoadFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("迁移文件 %s 的版本号无效: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件 %s 失败: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("版本 %d 存在多个迁移: %s 和 %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少 .up.sql 文件", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Create 在目录中创建一对空的迁移文件，返回文件路径
+func Create(dir string, version int64, name string) (string, string, error) {
+	name = strings.Trim(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '.' || r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(name)), "_")
+	if name == "" {
+		return "", "", errors.New("迁移名称不能为空")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("创建迁移目录失败: %w", err)
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%d_%s", version, name))
+	up, down := base+".up.sql", base+".down.sql"
+	for _, file := range []string{up, down} {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("创建迁移文件失败: %w", err)
+		}
+		_ = f.Close()
+	}
+	return up, down, nil
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db      *sql.DB
+	dialect string
+	table   string
+	ctx     context.Context
+}
+
+// New 创建迁移执行器，dialect 为空时根据驱动类型识别
+func New(db *sql.DB, dialect string) *Migrator {
+	if dialect == "" && db != nil {
+		dialect = introspect.DetectDialect(db)
+	}
+	dialect = strings.ToLower(dialect)
+	// 统一别名，保证占位符改写正确
+	switch dialect {
+	case "postgresql":
+		dialect = "postgres"
+	case "mssql":
+		dialect = "sqlserver"
+	}
+	return &Migrator{
+		db:      db,
+		dialect: dialect,
+		table:   DefaultTable,
+		ctx:     context.Background(),
+	}
+}
+
+// WithTable 设置迁移记录表
+func (m *Migrator) WithTable(table string) *Migrator {
+	clone := *m
+	clone.table = table
+	return &clone
+}
+
+// WithContext 设置执行使用的上下文
+func (m *Migrator) WithContext(ctx context.Context) *Migrator {
+	clone := *m
+	clone.ctx = ctx
+	return &clone
+}
+
+// Status 返回所有迁移的执行状态，按版本号排序
+func (m *Migrator) Status(migrations []Migration) ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Status, len(migrations))
+	for i, migration := range migrations {
+		at, ok := applied[migration.Version]
+		result[i] = Status{Migration: migration, Applied: ok, AppliedAt: at}
+	}
+	return result, nil
+}
+
+// Up 按版本号顺序执行未执行的迁移，steps <= 0 时执行全部，返回本次执行的迁移
+func (m *Migrator) Up(migrations []Migration, steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		insert := fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (?, ?, ?)", m.table)
+		if err := m.run(migration.Up, insert, migration.Version, migration.Name, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return done, fmt.Errorf("执行迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down 按版本号倒序回滚已执行的迁移，steps <= 0 时回滚全部，返回本次回滚的迁移
+func (m *Migrator) Down(migrations []Migration, steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			return done, fmt.Errorf("迁移 %d_%s 没有回滚SQL", migration.Version, migration.Name)
+		}
+		remove := fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.table)
+		if err := m.run(migration.Down, remove, migration.Version); err != nil {
+			return done, fmt.Errorf("回滚迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// run 在事务中执行迁移脚本并更新迁移记录
+func (m *Migrator) run(script, record string, args ...interface{}) error {
+	tx, err := m.db.BeginTx(m.ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range SplitStatements(script) {
+		if _, err := tx.ExecContext(m.ctx, stmt); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%w\n%s", err, stmt)
+		}
+	}
+	if _, err := tx.ExecContext(m.ctx, builder.Rebind(m.dialect, record), args...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("更新迁移记录失败: %w", err)
+	}
+	return tx.Commit()
+}
+
+// applied 返回已执行的版本及执行时间，迁移记录表不存在时先创建
+func (m *Migrator) applied() (map[int64]time.Time, error) {
+	if m.db == nil {
+		return nil, errors.New("数据库连接不能为空")
+	}
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(m.ctx, fmt.Sprintf("SELECT version, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			at      string
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("读取迁移记录失败: %w", err)
+		}
+		applied[version], _ = time.Parse(time.RFC3339, at)
+	}
+	return applied, rows.Err()
+}
+
+// ensureTable 创建迁移记录表，执行时间以 RFC3339 字符串保存，避免各数据库时间类型的差异
+func (m *Migrator) ensureTable() error {
+	if introspect.Supported(m.dialect) {
+		_, err := introspect.New(m.db, m.dialect).WithContext(m.ctx).Table(m.table)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	var ddl string
+	switch m.dialect {
+	case "oracle":
+		ddl = "CREATE TABLE %s (version NUMBER(19) PRIMARY KEY, name VARCHAR2(255) NOT NULL, applied_at VARCHAR2(32) NOT NULL)"
+	case "clickhouse":
+		ddl = "CREATE TABLE IF NOT EXISTS %s (version Int64, name String, applied_at String) ENGINE = MergeTree ORDER BY version"
+	case "sqlserver":
+		ddl = "CREATE TABLE %s (version BIGINT PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at VARCHAR(32) NOT NULL)"
+	default:
+		ddl = "CREATE TABLE IF NOT EXISTS %s (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at VARCHAR(32) NOT NULL)"
+	}
+	if _, err := m.db.ExecContext(m.ctx, fmt.Sprintf(ddl, m.table)); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+	return nil
+}
+
+// SplitStatements 按分号拆分SQL脚本，忽略引号和注释中的分号，以及空语句和只有注释的语句
+func SplitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		hasCode    bool
+	)
+	flush := func() {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		end, code := i+1, c != ' ' && c != '\t' && c != '\r' && c != '\n'
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end = closing(script, i+1, string(c))
+		case strings.HasPrefix(script[i:], "--"):
+			end, code = closing(script, i, "\n"), false
+		case strings.HasPrefix(script[i:], "/*"):
+			end, code = closing(script, i+2, "*/"), false
+		case c == ';':
+			flush()
+			continue
+		}
+		current.WriteString(script[i:end])
+		hasCode = hasCode || code
+		i = end - 1
+	}
+	flush()
+	return statements
+}
+
+// closing 返回从 start 开始第一个 token 结束后的位置，找不到时返回脚本长度
+func closing(script string, start int, token string) int {
+	if n := strings.Index(script[start:], token); n >= 0 {
+		return start + n + len(token)
+	}
+	return len(script)
+}
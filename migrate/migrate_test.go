@@ -0,0 +1,116 @@
+package migrate
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := "-- 用户表\nCREATE TABLE a (v TEXT DEFAULT ';');\n/* ; */\nINSERT INTO a VALUES ('x;y'); -- 结尾;\n  ;\n-- 只有注释\n"
+	want := []string{
+		"-- 用户表\nCREATE TABLE a (v TEXT DEFAULT ';')",
+		"/* ; */\nINSERT INTO a VALUES ('x;y')",
+	}
+	if got := SplitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitStatements() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"db/002_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD COLUMN email TEXT")},
+		"db/001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY)")},
+		"db/001_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+		"db/README.md":                 {Data: []byte("说明")},
+	}
+	migrations, err := LoadFS(fsys, "db")
+	if err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[0].Down != "DROP TABLE users" ||
+		migrations[1].Name != "add_email" || migrations[1].Down != "" {
+		t.Errorf("LoadFS() = %+v", migrations)
+	}
+
+	fsys["db/003_only_down.down.sql"] = &fstest.MapFile{Data: []byte("SELECT 1")}
+	if _, err := LoadFS(fsys, "db"); err == nil {
+		t.Error("缺少 .up.sql 时应返回错误")
+	}
+}
+
+// 测试在 SQLite 上执行、查看和回滚迁移
+func TestSQLiteMigrate(t *testing.T) {
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "migrate.db"))
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	migrationsDir := filepath.Join(dir, "migrations")
+	up, down, err := Create(migrationsDir, 1, "Create Users")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if filepath.Base(up) != "1_create_users.up.sql" {
+		t.Errorf("Create() = %s", up)
+	}
+	files := map[string]string{
+		up:   "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);\nINSERT INTO users (name) VALUES ('admin');",
+		down: "DROP TABLE users;",
+		filepath.Join(migrationsDir, "2_add_email.up.sql"):   "ALTER TABLE users ADD COLUMN email TEXT;",
+		filepath.Join(migrationsDir, "2_add_email.down.sql"): "ALTER TABLE users DROP COLUMN email;",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("写入迁移文件失败: %v", err)
+		}
+	}
+	migrations, err := Load(migrationsDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	m := New(db, "")
+	applied, err := m.Up(migrations, 1)
+	if err != nil || len(applied) != 1 || applied[0].Version != 1 {
+		t.Fatalf("Up(1) = %v, %v", applied, err)
+	}
+	if applied, err = m.Up(migrations, 0); err != nil || len(applied) != 1 || applied[0].Version != 2 {
+		t.Fatalf("Up(0) = %v, %v", applied, err)
+	}
+	if applied, _ = m.Up(migrations, 0); len(applied) != 0 {
+		t.Errorf("重复执行 Up() = %v", applied)
+	}
+	if _, err := db.Exec(`SELECT email FROM users`); err != nil {
+		t.Errorf("迁移未生效: %v", err)
+	}
+
+	status, err := m.Status(migrations)
+	if err != nil || len(status) != 2 || !status[0].Applied || !status[1].Applied || status[1].AppliedAt.IsZero() {
+		t.Fatalf("Status() = %+v, %v", status, err)
+	}
+
+	rolledBack, err := m.Down(migrations, 1)
+	if err != nil || len(rolledBack) != 1 || rolledBack[0].Version != 2 {
+		t.Fatalf("Down(1) = %v, %v", rolledBack, err)
+	}
+	if status, _ = m.Status(migrations); !status[0].Applied || status[1].Applied {
+		t.Errorf("回滚后 Status() = %+v", status)
+	}
+
+	// 失败的迁移不记录版本
+	bad := append(migrations, Migration{Version: 3, Name: "bad", Up: "CREATE TABLE t (id INTEGER); INSERT INTO missing VALUES (1);"})
+	if _, err := m.Up(bad, 0); err == nil {
+		t.Fatal("错误的迁移应返回错误")
+	}
+	if status, _ = m.Status(bad); !status[1].Applied || status[2].Applied {
+		t.Errorf("失败后 Status() = %+v", status)
+	}
+}
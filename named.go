@@ -0,0 +1,344 @@
+package gosqlx
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 命名语句注册表 ====================
+
+// ErrQueryNotRegistered 命名语句未注册
+var ErrQueryNotRegistered = errors.New("命名语句未注册")
+
+// NamedQuery 已注册的命名语句
+type NamedQuery struct {
+	Name    string // 语句名，如 user.byEmail
+	Version int    // 版本号，从1开始
+	SQL     string // SQL文本
+}
+
+// QueryStat 命名语句的执行统计
+type QueryStat struct {
+	Name      string        // 语句名
+	Calls     int64         // 执行次数
+	Errors    int64         // 失败次数
+	TotalTime time.Duration // 累计耗时
+	MaxTime   time.Duration // 最大耗时
+}
+
+// AvgTime 平均耗时
+func (s QueryStat) AvgTime() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalTime / time.Duration(s.Calls)
+}
+
+// namedQueries 全局命名语句注册表
+var namedQueries = struct {
+	mutex    sync.RWMutex
+	queries  map[string][]NamedQuery // 按版本升序
+	stats    map[string]*QueryStat
+	observer func(name string, elapsed time.Duration, err error)
+}{
+	queries: make(map[string][]NamedQuery),
+	stats:   make(map[string]*QueryStat),
+}
+
+// RegisterQuery 注册命名语句，同名语句再次注册且SQL不同时生成新版本，执行时默认使用最新版本
+func RegisterQuery(name, sql string) NamedQuery {
+	namedQueries.mutex.Lock()
+	defer namedQueries.mutex.Unlock()
+
+	versions := namedQueries.queries[name]
+	if len(versions) > 0 && versions[len(versions)-1].SQL == sql {
+		return versions[len(versions)-1]
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[len(versions)-1].Version + 1
+	}
+	query := NamedQuery{Name: name, Version: version, SQL: sql}
+	namedQueries.queries[name] = append(versions, query)
+	return query
+}
+
+// RegisterQueryVersion 注册指定版本的命名语句，同一版本已注册不同SQL时返回错误
+func RegisterQueryVersion(name string, version int, sql string) error {
+	if version <= 0 {
+		return fmt.Errorf("语句 %s 的版本号必须大于0", name)
+	}
+	namedQueries.mutex.Lock()
+	defer namedQueries.mutex.Unlock()
+
+	versions := namedQueries.queries[name]
+	for _, query := range versions {
+		if query.Version == version {
+			if query.SQL != sql {
+				return fmt.Errorf("语句 %s 的版本 %d 已注册", name, version)
+			}
+			return nil
+		}
+	}
+	versions = append(versions, NamedQuery{Name: name, Version: version, SQL: sql})
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	namedQueries.queries[name] = versions
+	return nil
+}
+
+// LookupQuery 查找命名语句，name@version 形式指定版本，否则返回最新版本
+func LookupQuery(name string) (NamedQuery, bool) {
+	base, version := name, 0
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		if v, err := strconv.Atoi(name[i+1:]); err == nil {
+			base, version = name[:i], v
+		}
+	}
+
+	namedQueries.mutex.RLock()
+	defer namedQueries.mutex.RUnlock()
+	versions := namedQueries.queries[base]
+	if len(versions) == 0 {
+		return NamedQuery{}, false
+	}
+	if version == 0 {
+		return versions[len(versions)-1], true
+	}
+	for _, query := range versions {
+		if query.Version == version {
+			return query, true
+		}
+	}
+	return NamedQuery{}, false
+}
+
+// RegisteredQueries 返回全部已注册语句（含历史版本），按名称和版本排序，便于集中审查
+func RegisteredQueries() []NamedQuery {
+	namedQueries.mutex.RLock()
+	defer namedQueries.mutex.RUnlock()
+	var queries []NamedQuery
+	for _, versions := range namedQueries.queries {
+		queries = append(queries, versions...)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].Name != queries[j].Name {
+			return queries[i].Name < queries[j].Name
+		}
+		return queries[i].Version < queries[j].Version
+	})
+	return queries
+}
+
+// LoadQueries 从文件系统（如 embed.FS）加载命名语句，文件中以注释声明语句名和可选的版本号:
+//
+//	-- name: user.byEmail
+//	-- version: 2
+//	SELECT * FROM users WHERE email = ?
+//
+// 未声明语句名的文件以路径（去掉扩展名）作为语句名
+func LoadQueries(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return fmt.Errorf("匹配语句文件失败: %w", err)
+		}
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return fmt.Errorf("读取语句文件 %s 失败: %w", file, err)
+			}
+			if err := loadQueryFile(strings.TrimSuffix(file, path.Ext(file)), string(data)); err != nil {
+				return fmt.Errorf("加载语句文件 %s 失败: %w", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadQueryFile 解析语句文件
+func loadQueryFile(defaultName, content string) error {
+	var (
+		name    string
+		version int
+		body    strings.Builder
+	)
+	flush := func() error {
+		sqlStr := strings.TrimSpace(body.String())
+		body.Reset()
+		if sqlStr == "" {
+			return nil
+		}
+		if name == "" {
+			name = defaultName
+		}
+		if version > 0 {
+			return RegisterQueryVersion(name, version, sqlStr)
+		}
+		RegisterQuery(name, sqlStr)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if comment, ok := strings.CutPrefix(strings.TrimSpace(line), "--"); ok {
+			key, value, _ := strings.Cut(strings.TrimSpace(comment), ":")
+			switch strings.TrimSpace(key) {
+			case "name":
+				if err := flush(); err != nil {
+					return err
+				}
+				name, version = strings.TrimSpace(value), 0
+				continue
+			case "version":
+				v, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil {
+					return fmt.Errorf("语句 %s 的版本号无效: %w", name, err)
+				}
+				version = v
+				continue
+			}
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// SetQueryObserver 设置命名语句执行回调，用于按语句名上报指标
+func SetQueryObserver(observer func(name string, elapsed time.Duration, err error)) {
+	namedQueries.mutex.Lock()
+	defer namedQueries.mutex.Unlock()
+	namedQueries.observer = observer
+}
+
+// QueryStats 返回各命名语句的执行统计，按语句名排序
+func QueryStats() []QueryStat {
+	namedQueries.mutex.RLock()
+	defer namedQueries.mutex.RUnlock()
+	stats := make([]QueryStat, 0, len(namedQueries.stats))
+	for _, stat := range namedQueries.stats {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// ResetQueryStats 清空执行统计
+func ResetQueryStats() {
+	namedQueries.mutex.Lock()
+	defer namedQueries.mutex.Unlock()
+	namedQueries.stats = make(map[string]*QueryStat)
+}
+
+// observeQuery 记录一次执行
+func observeQuery(name string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	namedQueries.mutex.Lock()
+	stat, ok := namedQueries.stats[name]
+	if !ok {
+		stat = &QueryStat{Name: name}
+		namedQueries.stats[name] = stat
+	}
+	stat.Calls++
+	if err != nil {
+		stat.Errors++
+	}
+	stat.TotalTime += elapsed
+	stat.MaxTime = max(stat.MaxTime, elapsed)
+	observer := namedQueries.observer
+	namedQueries.mutex.Unlock()
+
+	if observer != nil {
+		observer(name, elapsed, err)
+	}
+}
+
+// ==================== 执行命名语句 ====================
+
+// NamedStatement 待执行的命名语句
+type NamedStatement struct {
+	db    *Database
+	query NamedQuery
+	args  []interface{}
+	err   error
+}
+
+// Named 获取命名语句，name@version 形式指定版本；语句使用预编译缓存执行
+//
+//	gosqlx.RegisterQuery("user.byEmail", "SELECT * FROM users WHERE email = ?")
+//	err := db.Named("user.byEmail", email).Scan(&user)
+func (d *Database) Named(name string, args ...interface{}) *NamedStatement {
+	query, ok := LookupQuery(name)
+	if !ok {
+		return &NamedStatement{db: d, query: NamedQuery{Name: name}, err: fmt.Errorf("%w: %s", ErrQueryNotRegistered, name)}
+	}
+	return &NamedStatement{db: d, query: query, args: args}
+}
+
+// Query 返回命名语句的定义
+func (s *NamedStatement) Query() NamedQuery {
+	return s.query
+}
+
+// Scan 执行查询并扫描到 out
+func (s *NamedStatement) Scan(out interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	start := time.Now()
+	err := s.session().Raw(s.query.SQL, s.args...).Scan(out).Error
+	observeQuery(s.query.Name, start, err)
+	return err
+}
+
+// Rows 执行查询并返回结果集，调用方负责关闭；耗时统计不含结果集读取
+func (s *NamedStatement) Rows() (*sql.Rows, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	start := time.Now()
+	rows, err := s.session().Raw(s.query.SQL, s.args...).Rows()
+	observeQuery(s.query.Name, start, err)
+	return rows, err
+}
+
+// Maps 执行查询，每行转换为 列名->值 的map
+func (s *NamedStatement) Maps() ([]map[string]interface{}, error) {
+	rows, err := s.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanMaps(rows)
+}
+
+// Exec 执行语句，返回影响的行数
+func (s *NamedStatement) Exec() (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	start := time.Now()
+	result := s.session().Exec(s.query.SQL, s.args...)
+	observeQuery(s.query.Name, start, result.Error)
+	return result.RowsAffected, result.Error
+}
+
+// session 启用预编译语句缓存的会话，缓存由同一连接的所有会话共享
+func (s *NamedStatement) session() *gorm.DB {
+	return s.db.db.Session(&gorm.Session{PrepareStmt: true})
+}
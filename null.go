@@ -0,0 +1,104 @@
+package gosqlx
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// ==================== 可为 NULL 的值 ====================
+
+// Null 可为 NULL 的值，Valid 为 false 时表示 NULL
+// 实现 sql.Scanner 和 driver.Valuer，可用于模型字段、查询构建器的扫描目标和语句参数；
+// JSON 编码时 NULL 为 null。T 为 int32、枚举等非驱动类型时写入前转换为驱动支持的类型
+//
+//	type User struct {
+//		ID       int64
+//		Nickname gosqlx.Null[string]
+//		Deleted  gosqlx.Null[time.Time]
+//	}
+//	user.Nickname = gosqlx.NullOf("neo")
+//	name := user.Nickname.Or("anonymous")
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NullOf 返回有效的值
+func NullOf[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// NullFrom 从指针创建，nil 为 NULL
+func NullFrom[T any](p *T) Null[T] {
+	if p == nil {
+		return Null[T]{}
+	}
+	return NullOf(*p)
+}
+
+// Ptr 转为指针，NULL 为 nil
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.V
+	return &v
+}
+
+// Or 返回值，NULL 时返回 def
+func (n Null[T]) Or(def T) T {
+	if !n.Valid {
+		return def
+	}
+	return n.V
+}
+
+// Scan 实现 sql.Scanner，转换规则与 database/sql 扫描到 T 相同
+func (n *Null[T]) Scan(value interface{}) error {
+	return (*sql.Null[T])(n).Scan(value)
+}
+
+// Value 实现 driver.Valuer，T 实现 driver.Valuer 时使用其结果，否则按驱动默认规则转换
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	var v interface{} = n.V
+	if valuer, ok := v.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+// MarshalJSON NULL 编码为 null
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON null 解码为 NULL
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// nullValue 返回内部值，供参数转换按 ValueFormat 处理 Null[time.Time] 等值
+func (n Null[T]) nullValue() (interface{}, bool) {
+	return n.V, n.Valid
+}
+
+// nullable Null 的非泛型视图
+type nullable interface {
+	nullValue() (interface{}, bool)
+}
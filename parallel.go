@@ -0,0 +1,99 @@
+package gosqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gzorm/gosqlx/adapter"
+)
+
+// ==================== 并行查询 ====================
+
+// parallelWorkers Parallel 默认同时执行的函数数
+const parallelWorkers = 4
+
+// ParallelOptions 并行查询选项
+type ParallelOptions struct {
+	Workers    int  // 同时执行的函数数，默认 4，不应超过连接池的 MaxOpen
+	CollectAll bool // 等待所有函数执行完并返回全部错误；默认第一个错误时取消其余函数并只返回该错误
+}
+
+// Parallel 在不同的池连接上并发执行相互独立的只读查询，每个函数收到在派生上下文下执行的数据库实例
+// 第一个错误时取消其余函数，返回该错误；在事务中（包括 ctx 携带的环境事务）时按顺序在事务连接上执行
+//
+//	var users, orders int64
+//	err := db.Parallel(ctx,
+//		func(db *gosqlx.Database) error { return db.Model(&User{}).Count(&users).Error },
+//		func(db *gosqlx.Database) error { return db.Model(&Order{}).Count(&orders).Error },
+//	)
+func (d *Database) Parallel(ctx context.Context, fns ...func(db *Database) error) error {
+	return d.ParallelWithOptions(ctx, ParallelOptions{}, fns...)
+}
+
+// ParallelWithOptions 按选项并发执行，CollectAll 时返回以 errors.Join 合并的所有错误，每个错误标明函数序号
+func (d *Database) ParallelWithOptions(ctx context.Context, opts ParallelOptions, fns ...func(db *Database) error) error {
+	if ctx == nil {
+		ctx = d.db.Statement.Context
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = parallelWorkers
+	}
+	errs := make([]error, len(fns))
+
+	if db := d.WithContext(ctx); adapter.Sequential(db.db) || workers == 1 || len(fns) < 2 {
+		for i, fn := range fns {
+			if errs[i] = fn(db); errs[i] != nil && !opts.CollectAll {
+				return errs[i]
+			}
+		}
+		return parallelError(errs)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		slots    = make(chan struct{}, workers)
+		first    error
+		firstErr sync.Once
+	)
+	for i, fn := range fns {
+		if !opts.CollectAll && ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if ctx.Err() != nil && !opts.CollectAll {
+				return
+			}
+			if errs[i] = fn(d.WithContext(ctx)); errs[i] != nil && !opts.CollectAll {
+				firstErr.Do(func() {
+					first = errs[i]
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if !opts.CollectAll {
+		return first
+	}
+	return parallelError(errs)
+}
+
+// parallelError 合并各函数的错误，标明函数序号
+func parallelError(errs []error) error {
+	var joined []error
+	for i, err := range errs {
+		if err != nil {
+			joined = append(joined, fmt.Errorf("第 %d 个查询失败: %w", i+1, err))
+		}
+	}
+	return errors.Join(joined...)
+}
@@ -0,0 +1,490 @@
+// Package partition 按时间范围分区的管理，用于定时任务提前创建分区、删除过期分区和查看分区信息
+//
+// 支持 MySQL（含 MariaDB、TiDB、OceanBase）的 RANGE 分区、PostgreSQL 的声明式分区和 Oracle 的范围分区:
+//
+//	m, err := partition.New(db, "events", partition.Options{Interval: partition.Monthly})
+//	created, err := m.Ensure(3)                                 // 创建当前及之后 3 个月的分区
+//	dropped, err := m.DropBefore(time.Now().AddDate(0, -12, 0)) // 删除 12 个月前的分区
+//
+// MySQL 的分区表达式需为 RANGE COLUMNS(列)、RANGE(TO_DAYS(列)) 或 RANGE(UNIX_TIMESTAMP(列))；
+// 存在 MAXVALUE 分区时，MySQL 通过 REORGANIZE、Oracle 通过 SPLIT 拆分出新分区
+package partition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// Interval 分区的时间跨度
+type Interval string
+
+// 分区跨度
+const (
+	Daily   Interval = "daily"   // 按天
+	Monthly Interval = "monthly" // 按月
+)
+
+// Partition 分区信息
+type Partition struct {
+	Name  string    // 分区名，PostgreSQL 为子表名
+	From  time.Time // 下界（含），第一个分区或 MINVALUE 时为零值
+	To    time.Time // 上界（不含），Max 为 true 时为零值
+	Max   bool      // MAXVALUE 或 DEFAULT 分区
+	Rows  int64     // 统计信息中的行数
+	Bound string    // 数据库中的原始边界表达式
+}
+
+// Options 分区选项
+type Options struct {
+	Interval Interval       // 分区跨度，默认按月
+	Prefix   string         // 分区名前缀，默认 p，分区名如 p202401、p20240131；PostgreSQL 子表名为 <表名>_<前缀><日期>
+	Location *time.Location // 计算分区边界使用的时区，默认 time.Local
+}
+
+// Manager 分区管理器
+type Manager struct {
+	db      *gosqlx.Database
+	dialect string
+	table   string
+	options Options
+	ctx     context.Context
+}
+
+// New 创建表的分区管理器，仅支持 MySQL、MariaDB、TiDB、OceanBase、PostgreSQL 和 Oracle
+func New(db *gosqlx.Database, table string, options Options) (*Manager, error) {
+	if table == "" {
+		return nil, errors.New("表名不能为空")
+	}
+	var dialect string
+	switch db.Type() {
+	case gosqlx.MySQL, gosqlx.MariaDB, gosqlx.TiDB, gosqlx.OceanBase:
+		dialect = "mysql"
+	case gosqlx.PostgresSQL:
+		dialect = "postgres"
+	case gosqlx.Oracle:
+		dialect = "oracle"
+	default:
+		return nil, fmt.Errorf("%s 不支持分区管理", db.Type())
+	}
+	if options.Interval == "" {
+		options.Interval = Monthly
+	}
+	if options.Interval != Daily && options.Interval != Monthly {
+		return nil, fmt.Errorf("不支持的分区跨度: %s", options.Interval)
+	}
+	if options.Prefix == "" {
+		options.Prefix = "p"
+	}
+	if options.Location == nil {
+		options.Location = time.Local
+	}
+	return &Manager{db: db, dialect: dialect, table: table, options: options, ctx: context.Background()}, nil
+}
+
+// WithContext 设置执行使用的上下文
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	clone := *m
+	clone.ctx = ctx
+	return &clone
+}
+
+// List 返回表的分区，按上界排序，MAXVALUE/DEFAULT 分区在最后
+func (m *Manager) List() ([]Partition, error) {
+	var (
+		partitions []Partition
+		err        error
+	)
+	switch m.dialect {
+	case "mysql":
+		partitions, err = m.listMySQL()
+	case "postgres":
+		partitions, err = m.listPostgres()
+	case "oracle":
+		partitions, err = m.listOracle()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询表 %s 的分区失败: %w", m.table, err)
+	}
+	sort.SliceStable(partitions, func(i, j int) bool {
+		if partitions[i].Max != partitions[j].Max {
+			return !partitions[i].Max
+		}
+		return partitions[i].To.Before(partitions[j].To)
+	})
+	return partitions, nil
+}
+
+// Ensure 创建覆盖当前周期及之后 ahead 个周期的分区，已覆盖的周期跳过，返回新建的分区名
+// 新分区从已有分区的最大上界开始，保证范围连续
+func (m *Manager) Ensure(ahead int) ([]string, error) {
+	return m.EnsureAt(time.Now(), ahead)
+}
+
+// EnsureAt 以 now 为当前时间创建分区，见 Ensure
+func (m *Manager) EnsureAt(now time.Time, ahead int) ([]string, error) {
+	partitions, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		upper   time.Time
+		maxPart *Partition
+	)
+	for i := range partitions {
+		if partitions[i].Max {
+			maxPart = &partitions[i]
+		} else if partitions[i].To.After(upper) {
+			upper = partitions[i].To
+		}
+	}
+
+	var created []string
+	start := m.truncate(now)
+	for i := 0; i <= ahead; i++ {
+		end := m.next(start)
+		if end.After(upper) {
+			from := start
+			if upper.After(from) {
+				from = upper
+			}
+			name := m.name(start)
+			if err := m.create(partitions, maxPart, name, from, end); err != nil {
+				return created, fmt.Errorf("创建分区 %s 失败: %w", name, err)
+			}
+			created = append(created, name)
+			upper = end
+		}
+		start = end
+	}
+	return created, nil
+}
+
+// DropBefore 删除上界不晚于 t 的分区（分区内的数据全部早于 t），返回删除的分区名
+func (m *Manager) DropBefore(t time.Time) ([]string, error) {
+	partitions, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, p := range partitions {
+		if !p.Max && !p.To.IsZero() && !p.To.After(t) {
+			names = append(names, p.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if err := m.drop(names); err != nil {
+		return nil, fmt.Errorf("删除分区失败: %w", err)
+	}
+	return names, nil
+}
+
+// Attach 将已有的表挂载为 from 所在周期的分区，仅支持 PostgreSQL
+func (m *Manager) Attach(table string, from time.Time) error {
+	if m.dialect != "postgres" {
+		return fmt.Errorf("%s 不支持挂载分区", m.db.Type())
+	}
+	start := m.truncate(from)
+	return m.exec(fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)",
+		m.table, table, m.literal(start), m.literal(m.next(start))))
+}
+
+// Detach 将分区从表中卸载为普通表，仅支持 PostgreSQL
+func (m *Manager) Detach(name string) error {
+	if m.dialect != "postgres" {
+		return fmt.Errorf("%s 不支持卸载分区", m.db.Type())
+	}
+	return m.exec(fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", m.table, name))
+}
+
+// create 创建 [from, to) 的分区
+func (m *Manager) create(partitions []Partition, maxPart *Partition, name string, from, to time.Time) error {
+	switch m.dialect {
+	case "mysql":
+		if len(partitions) == 0 {
+			return errors.New("表未分区")
+		}
+		bound, err := mysqlBound(partitions[0].Bound, to, m.options.Location)
+		if err != nil {
+			return err
+		}
+		if maxPart != nil {
+			return m.exec(fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (PARTITION %s VALUES LESS THAN (%s), PARTITION %s VALUES LESS THAN (MAXVALUE))",
+				m.table, maxPart.Name, name, bound, maxPart.Name))
+		}
+		return m.exec(fmt.Sprintf("ALTER TABLE %s ADD PARTITION (PARTITION %s VALUES LESS THAN (%s))", m.table, name, bound))
+	case "postgres":
+		return m.exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%s) TO (%s)",
+			name, m.table, m.literal(from), m.literal(to)))
+	default:
+		bound := fmt.Sprintf("TO_DATE('%s', 'YYYY-MM-DD HH24:MI:SS')", to.In(m.options.Location).Format(time.DateTime))
+		if maxPart != nil {
+			return m.exec(fmt.Sprintf("ALTER TABLE %s SPLIT PARTITION %s AT (%s) INTO (PARTITION %s, PARTITION %s)",
+				m.table, maxPart.Name, bound, name, maxPart.Name))
+		}
+		return m.exec(fmt.Sprintf("ALTER TABLE %s ADD PARTITION %s VALUES LESS THAN (%s)", m.table, name, bound))
+	}
+}
+
+// drop 删除分区
+func (m *Manager) drop(names []string) error {
+	switch m.dialect {
+	case "mysql":
+		return m.exec(fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s", m.table, strings.Join(names, ", ")))
+	case "postgres":
+		for _, name := range names {
+			if err := m.exec("DROP TABLE " + name); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, name := range names {
+			if err := m.exec(fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s UPDATE GLOBAL INDEXES", m.table, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// listMySQL 查询 information_schema.PARTITIONS，Bound 为 "方法|表达式|边界"
+func (m *Manager) listMySQL() ([]Partition, error) {
+	var rows []struct {
+		Name        string
+		Method      string
+		Expression  string
+		Description string
+		Rows        int64
+	}
+	err := m.db.WithContext(m.ctx).Raw(`SELECT PARTITION_NAME AS name, PARTITION_METHOD AS method, PARTITION_EXPRESSION AS expression,
+		PARTITION_DESCRIPTION AS description, COALESCE(TABLE_ROWS, 0) AS `+"`rows`"+`
+		FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL
+		ORDER BY PARTITION_ORDINAL_POSITION`, m.table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	partitions := make([]Partition, 0, len(rows))
+	var from time.Time
+	for _, row := range rows {
+		bound := row.Method + "|" + row.Expression + "|" + row.Description
+		to, max, err := parseMySQLBound(bound, m.options.Location)
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, Partition{Name: row.Name, From: from, To: to, Max: max, Rows: row.Rows, Bound: bound})
+		from = to
+	}
+	return partitions, nil
+}
+
+// listPostgres 查询 pg_inherits 中的子表及其分区边界
+func (m *Manager) listPostgres() ([]Partition, error) {
+	var rows []struct {
+		Name  string
+		Bound string
+		Rows  int64
+	}
+	err := m.db.WithContext(m.ctx).Raw(`SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound,
+		GREATEST(c.reltuples, 0)::bigint AS rows
+		FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass(?)`, m.table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	partitions := make([]Partition, 0, len(rows))
+	for _, row := range rows {
+		from, to, max, err := parsePostgresBound(row.Bound, m.options.Location)
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, Partition{Name: row.Name, From: from, To: to, Max: max, Rows: row.Rows, Bound: row.Bound})
+	}
+	return partitions, nil
+}
+
+// listOracle 查询 USER_TAB_PARTITIONS
+func (m *Manager) listOracle() ([]Partition, error) {
+	var rows []struct {
+		Name  string
+		Bound string
+		Rows  int64
+	}
+	err := m.db.WithContext(m.ctx).Raw(`SELECT partition_name AS name, high_value AS bound, NVL(num_rows, 0) AS "rows"
+		FROM user_tab_partitions WHERE table_name = UPPER(?) ORDER BY partition_position`, m.table).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	partitions := make([]Partition, 0, len(rows))
+	var from time.Time
+	for _, row := range rows {
+		to, max, err := parseOracleBound(row.Bound, m.options.Location)
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, Partition{Name: row.Name, From: from, To: to, Max: max, Rows: row.Rows, Bound: row.Bound})
+		from = to
+	}
+	return partitions, nil
+}
+
+// exec 执行DDL
+func (m *Manager) exec(sqlStr string) error {
+	return m.db.WithContext(m.ctx).Exec(sqlStr)
+}
+
+// truncate 返回 t 所在周期的开始时间
+func (m *Manager) truncate(t time.Time) time.Time {
+	t = t.In(m.options.Location)
+	if m.options.Interval == Daily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, m.options.Location)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, m.options.Location)
+}
+
+// next 返回下一个周期的开始时间
+func (m *Manager) next(start time.Time) time.Time {
+	if m.options.Interval == Daily {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// name 周期的分区名
+func (m *Manager) name(start time.Time) string {
+	layout := "200601"
+	if m.options.Interval == Daily {
+		layout = "20060102"
+	}
+	name := m.options.Prefix + start.In(m.options.Location).Format(layout)
+	if m.dialect == "postgres" {
+		// 带模式的表名如 s.events，子表建在同一模式下: s.events_p202401
+		return m.table + "_" + name
+	}
+	return name
+}
+
+// literal PostgreSQL 分区边界字面量
+func (m *Manager) literal(t time.Time) string {
+	return "'" + t.In(m.options.Location).Format(time.DateTime) + "'"
+}
+
+// toDaysEpoch MySQL TO_DAYS('1970-01-01') 的值
+const toDaysEpoch = 719528
+
+// mysqlBound 按分区表达式生成上界为 t 的 VALUES LESS THAN 取值，bound 为已有分区的 "方法|表达式|边界"
+func mysqlBound(bound string, t time.Time, loc *time.Location) (string, error) {
+	method, expression, _ := splitMySQLBound(bound)
+	value := t.In(loc).Format(time.DateTime)
+	switch {
+	case strings.Contains(method, "COLUMNS"):
+		return "'" + value + "'", nil
+	case strings.Contains(expression, "to_days("):
+		return "TO_DAYS('" + value + "')", nil
+	case strings.Contains(expression, "unix_timestamp("):
+		return "UNIX_TIMESTAMP('" + value + "')", nil
+	}
+	return "", fmt.Errorf("不支持的分区表达式: %s %s", method, expression)
+}
+
+// parseMySQLBound 解析 MySQL 分区的上界
+func parseMySQLBound(bound string, loc *time.Location) (time.Time, bool, error) {
+	method, expression, description := splitMySQLBound(bound)
+	if strings.EqualFold(description, "MAXVALUE") {
+		return time.Time{}, true, nil
+	}
+	switch {
+	case strings.Contains(method, "COLUMNS"):
+		t, err := parseTime(strings.Trim(description, "'"), loc)
+		return t, false, err
+	case strings.Contains(expression, "to_days("):
+		days, err := strconv.ParseInt(description, 10, 64)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("无法解析分区边界 %q: %w", description, err)
+		}
+		return time.Date(1970, 1, 1, 0, 0, 0, 0, loc).AddDate(0, 0, int(days-toDaysEpoch)), false, nil
+	case strings.Contains(expression, "unix_timestamp("):
+		seconds, err := strconv.ParseInt(description, 10, 64)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("无法解析分区边界 %q: %w", description, err)
+		}
+		return time.Unix(seconds, 0).In(loc), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("不支持的分区表达式: %s %s", method, expression)
+}
+
+// splitMySQLBound 拆分 "方法|表达式|边界"，方法转为大写，表达式转为小写并去掉反引号
+func splitMySQLBound(bound string) (method, expression, description string) {
+	parts := strings.SplitN(bound, "|", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	return strings.ToUpper(parts[0]), strings.ReplaceAll(strings.ToLower(parts[1]), "`", ""), strings.TrimSpace(parts[2])
+}
+
+// postgresBoundRe 解析 FOR VALUES FROM (...) TO (...)
+var postgresBoundRe = regexp.MustCompile(`(?i)FROM \((.+?)\) TO \((.+?)\)`)
+
+// parsePostgresBound 解析 PostgreSQL 分区边界，DEFAULT 分区返回 max 为 true
+func parsePostgresBound(bound string, loc *time.Location) (from, to time.Time, max bool, err error) {
+	if strings.EqualFold(strings.TrimSpace(bound), "DEFAULT") {
+		return time.Time{}, time.Time{}, true, nil
+	}
+	match := postgresBoundRe.FindStringSubmatch(bound)
+	if match == nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("不支持的分区边界: %s", bound)
+	}
+	if !strings.EqualFold(match[1], "MINVALUE") {
+		if from, err = parseTime(strings.Trim(match[1], "'"), loc); err != nil {
+			return
+		}
+	}
+	if strings.EqualFold(match[2], "MAXVALUE") {
+		return from, time.Time{}, true, nil
+	}
+	to, err = parseTime(strings.Trim(match[2], "'"), loc)
+	return
+}
+
+// oracleDateRe 匹配 HIGH_VALUE 中的日期
+var oracleDateRe = regexp.MustCompile(`'\s*(\d{4}-\d{2}-\d{2}(?: \d{2}:\d{2}:\d{2})?)`)
+
+// parseOracleBound 解析 Oracle 分区的 HIGH_VALUE，如 TO_DATE(' 2024-02-01 00:00:00', 'SYYYY-MM-DD HH24:MI:SS', ...)
+func parseOracleBound(bound string, loc *time.Location) (time.Time, bool, error) {
+	if strings.EqualFold(strings.TrimSpace(bound), "MAXVALUE") {
+		return time.Time{}, true, nil
+	}
+	match := oracleDateRe.FindStringSubmatch(bound)
+	if match == nil {
+		return time.Time{}, false, fmt.Errorf("不支持的分区边界: %s", bound)
+	}
+	t, err := parseTime(match[1], loc)
+	return t, false, err
+}
+
+// timeLayouts 分区边界中可能出现的时间格式
+var timeLayouts = []string{
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05-07",
+	time.DateTime,
+	time.DateOnly,
+}
+
+// parseTime 解析分区边界中的时间
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析分区边界 %q", value)
+}
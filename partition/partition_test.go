@@ -0,0 +1,141 @@
+package partition
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMySQLBound(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		bound string
+		want  time.Time
+		max   bool
+	}{
+		{"RANGE|to_days(`created_at`)|739282", time.Date(2024, 2, 1, 0, 0, 0, 0, loc), false},
+		{"RANGE|unix_timestamp(`created_at`)|1706745600", time.Date(2024, 2, 1, 0, 0, 0, 0, loc), false},
+		{"RANGE COLUMNS|`created_at`|'2024-02-01 00:00:00'", time.Date(2024, 2, 1, 0, 0, 0, 0, loc), false},
+		{"RANGE COLUMNS|`created_at`|'2024-02-01'", time.Date(2024, 2, 1, 0, 0, 0, 0, loc), false},
+		{"RANGE|to_days(`created_at`)|MAXVALUE", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, max, err := parseMySQLBound(tt.bound, loc)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.bound, err)
+		}
+		if !got.Equal(tt.want) || max != tt.max {
+			t.Errorf("%s: got %v %v, want %v %v", tt.bound, got, max, tt.want, tt.max)
+		}
+	}
+
+	if _, _, err := parseMySQLBound("RANGE|year(`created_at`)|2024", loc); err == nil {
+		t.Error("expected error for unsupported expression")
+	}
+}
+
+func TestMySQLBound(t *testing.T) {
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"RANGE|to_days(`created_at`)|739283":          "TO_DAYS('2024-03-01 00:00:00')",
+		"RANGE|unix_timestamp(`created_at`)|1":        "UNIX_TIMESTAMP('2024-03-01 00:00:00')",
+		"RANGE COLUMNS|`created_at`|'2024-02-01'":     "'2024-03-01 00:00:00'",
+		"range columns|`created_at`|MAXVALUE":         "'2024-03-01 00:00:00'",
+		"RANGE|TO_DAYS(`created_at`)|MAXVALUE":        "TO_DAYS('2024-03-01 00:00:00')",
+		"RANGE|unix_timestamp(`created_at`)|MAXVALUE": "UNIX_TIMESTAMP('2024-03-01 00:00:00')",
+	}
+	for bound, want := range tests {
+		got, err := mysqlBound(bound, to, time.UTC)
+		if err != nil {
+			t.Fatalf("%s: %v", bound, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %s, want %s", bound, got, want)
+		}
+	}
+}
+
+func TestParsePostgresBound(t *testing.T) {
+	loc := time.UTC
+	from, to, max, err := parsePostgresBound("FOR VALUES FROM ('2024-01-01 00:00:00') TO ('2024-02-01 00:00:00')", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !from.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, loc)) || !to.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, loc)) || max {
+		t.Errorf("got %v %v %v", from, to, max)
+	}
+
+	_, to, _, err = parsePostgresBound("FOR VALUES FROM ('2024-01-01 00:00:00+08') TO ('2024-02-01 00:00:00+08')", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !to.Equal(time.Date(2024, 1, 31, 16, 0, 0, 0, loc)) {
+		t.Errorf("got %v", to)
+	}
+
+	from, _, _, err = parsePostgresBound("FOR VALUES FROM (MINVALUE) TO ('2024-01-01')", loc)
+	if err != nil || !from.IsZero() {
+		t.Errorf("got %v %v", from, err)
+	}
+
+	if _, _, max, err = parsePostgresBound("DEFAULT", loc); err != nil || !max {
+		t.Errorf("DEFAULT: got %v %v", max, err)
+	}
+	if _, _, max, err = parsePostgresBound("FOR VALUES FROM ('2024-01-01') TO (MAXVALUE)", loc); err != nil || !max {
+		t.Errorf("MAXVALUE: got %v %v", max, err)
+	}
+	if _, _, _, err = parsePostgresBound("FOR VALUES IN (1, 2)", loc); err == nil {
+		t.Error("expected error for list partition")
+	}
+}
+
+func TestParseOracleBound(t *testing.T) {
+	loc := time.UTC
+	tests := []string{
+		"TO_DATE(' 2024-02-01 00:00:00', 'SYYYY-MM-DD HH24:MI:SS', 'NLS_CALENDAR=GREGORIAN')",
+		"TIMESTAMP' 2024-02-01 00:00:00'",
+	}
+	for _, bound := range tests {
+		got, max, err := parseOracleBound(bound, loc)
+		if err != nil {
+			t.Fatalf("%s: %v", bound, err)
+		}
+		if !got.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, loc)) || max {
+			t.Errorf("%s: got %v %v", bound, got, max)
+		}
+	}
+	if _, max, err := parseOracleBound("MAXVALUE", loc); err != nil || !max {
+		t.Errorf("MAXVALUE: got %v %v", max, err)
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2024, 1, 31, 20, 0, 0, 0, time.UTC) // 2024-02-01 04:00 CST
+
+	monthly := &Manager{dialect: "mysql", table: "events", options: Options{Interval: Monthly, Prefix: "p", Location: loc}}
+	start := monthly.truncate(now)
+	if !start.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("monthly truncate: got %v", start)
+	}
+	if next := monthly.next(start); !next.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("monthly next: got %v", next)
+	}
+	if name := monthly.name(start); name != "p202402" {
+		t.Errorf("monthly name: got %s", name)
+	}
+
+	daily := &Manager{dialect: "postgres", table: "public.events", options: Options{Interval: Daily, Prefix: "p", Location: loc}}
+	start = daily.truncate(now)
+	if !start.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("daily truncate: got %v", start)
+	}
+	if next := daily.next(start); !next.Equal(time.Date(2024, 2, 2, 0, 0, 0, 0, loc)) {
+		t.Errorf("daily next: got %v", next)
+	}
+	if name := daily.name(start); name != "public.events_p20240201" {
+		t.Errorf("daily name: got %s", name)
+	}
+	if literal := daily.literal(start); literal != "'2024-02-01 00:00:00'" {
+		t.Errorf("literal: got %s", literal)
+	}
+}
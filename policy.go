@@ -0,0 +1,276 @@
+package gosqlx
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 语句策略 ====================
+
+// ErrPolicyViolation 语句违反了数据库别名上配置的语句策略
+var ErrPolicyViolation = errors.New("SQL语句违反策略")
+
+// 策略规则名，用于 PolicyViolation.Rule
+const (
+	RuleDDL                = "ddl"                  // DDL 语句
+	RuleDeleteWithoutWhere = "delete_without_where" // 不带 WHERE 的 DELETE
+	RuleUpdateWithoutWhere = "update_without_where" // 不带 WHERE 的 UPDATE
+	RuleSelectStar         = "select_star"          // SELECT *
+	RuleStatement          = "statement"            // 禁止的语句类型
+)
+
+// StatementPolicy 语句策略，按数据库别名（Context.Nick）配置，在 GORM 回调链中执行语句前检查
+// 覆盖 Database 的 ORM 方法和 Exec/Raw/Query 等原生语句；query 包构建器直接执行的语句不在检查范围内
+type StatementPolicy struct {
+	DenyDDL                bool     // 禁止 CREATE、ALTER、DROP、TRUNCATE、RENAME
+	DenyDeleteWithoutWhere bool     // 禁止不带 WHERE 的 DELETE
+	DenyUpdateWithoutWhere bool     // 禁止不带 WHERE 的 UPDATE
+	DenySelectStar         []string // 禁止 SELECT * 的表，"*" 表示所有表
+	DenyStatements         []string // 禁止的语句类型（首个关键字），如 "GRANT"、"CALL"
+	AuditOnly              bool     // 只记录违规不拒绝，用于启用前观察
+}
+
+// PolicyViolation 违规记录
+type PolicyViolation struct {
+	Nick   string // 数据库别名
+	Rule   string // 违反的规则
+	Detail string // 违规说明
+	SQL    string // 语句，ORM 方法生成的语句在检查时尚未构建，为表名
+	Denied bool   // 是否已拒绝执行
+}
+
+// policies 按数据库别名保存的策略，"*" 为默认策略
+var policies = struct {
+	mutex   sync.RWMutex
+	byNick  map[string]*StatementPolicy
+	auditor func(v PolicyViolation)
+}{
+	byNick:  make(map[string]*StatementPolicy),
+	auditor: logViolation,
+}
+
+// logViolation 默认的违规记录输出
+func logViolation(v PolicyViolation) {
+	log.Printf("[gosqlx] 语句策略违规 nick=%s rule=%s denied=%v %s: %s", v.Nick, v.Rule, v.Denied, v.Detail, v.SQL)
+}
+
+// SetPolicy 设置数据库别名的语句策略，nick 为 "*" 时作为未单独配置的别名的默认策略，policy 为nil时删除
+//
+//	gosqlx.SetPolicy("orders_prod", &gosqlx.StatementPolicy{DenyDDL: true, DenyDeleteWithoutWhere: true})
+func SetPolicy(nick string, policy *StatementPolicy) {
+	policies.mutex.Lock()
+	defer policies.mutex.Unlock()
+	if policy == nil {
+		delete(policies.byNick, nick)
+		return
+	}
+	copied := *policy
+	policies.byNick[nick] = &copied
+}
+
+// SetPolicyAuditor 设置违规记录的输出函数，传入nil时恢复为标准日志
+func SetPolicyAuditor(auditor func(v PolicyViolation)) {
+	policies.mutex.Lock()
+	defer policies.mutex.Unlock()
+	if auditor == nil {
+		auditor = logViolation
+	}
+	policies.auditor = auditor
+}
+
+// CheckPolicy 按数据库别名的策略检查SQL，违规时记录并在非 AuditOnly 时返回 ErrPolicyViolation
+func CheckPolicy(nick, sqlStr string) error {
+	policy := policyFor(nick)
+	if policy == nil {
+		return nil
+	}
+	rule, detail := policy.inspect(sqlStr)
+	return reportViolation(nick, policy, rule, detail, sqlStr)
+}
+
+// policyFor 获取别名的策略
+func policyFor(nick string) *StatementPolicy {
+	policies.mutex.RLock()
+	defer policies.mutex.RUnlock()
+	if policy, ok := policies.byNick[nick]; ok {
+		return policy
+	}
+	return policies.byNick["*"]
+}
+
+// reportViolation 记录违规，rule 为空表示未违规
+func reportViolation(nick string, policy *StatementPolicy, rule, detail, sqlStr string) error {
+	if rule == "" {
+		return nil
+	}
+	policies.mutex.RLock()
+	auditor := policies.auditor
+	policies.mutex.RUnlock()
+	auditor(PolicyViolation{Nick: nick, Rule: rule, Detail: detail, SQL: sqlStr, Denied: !policy.AuditOnly})
+	if policy.AuditOnly {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrPolicyViolation, detail)
+}
+
+// ddlStatements DDL 语句关键字
+var ddlStatements = map[string]bool{"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true}
+
+// whereRe 匹配 WHERE 关键字
+var whereRe = regexp.MustCompile(`(?i)\bWHERE\b`)
+
+// selectStarRe 匹配 SELECT * FROM 表名，表名可带模式和引号
+var selectStarRe = regexp.MustCompile("(?is)\\bSELECT\\s+(?:DISTINCT\\s+)?\\*\\s+FROM\\s+([\\w.`\"\\[\\]]+)")
+
+// inspect 检查SQL文本，返回违反的规则和说明
+func (p *StatementPolicy) inspect(sqlStr string) (string, string) {
+	keyword, rest := leadingKeyword(sqlStr)
+	if keyword == "" {
+		return "", ""
+	}
+	for _, denied := range p.DenyStatements {
+		if strings.EqualFold(denied, keyword) {
+			return RuleStatement, "禁止执行 " + keyword + " 语句"
+		}
+	}
+	if p.DenyDDL && ddlStatements[keyword] {
+		return RuleDDL, "禁止执行 DDL 语句 " + keyword
+	}
+	body := stripLiterals(rest)
+	if p.DenyDeleteWithoutWhere && keyword == "DELETE" && !whereRe.MatchString(body) {
+		return RuleDeleteWithoutWhere, "禁止不带 WHERE 的 DELETE"
+	}
+	if p.DenyUpdateWithoutWhere && keyword == "UPDATE" && !whereRe.MatchString(body) {
+		return RuleUpdateWithoutWhere, "禁止不带 WHERE 的 UPDATE"
+	}
+	if len(p.DenySelectStar) > 0 {
+		for _, match := range selectStarRe.FindAllStringSubmatch(keyword+body, -1) {
+			if table := strings.Trim(match[1], "`\"[]"); p.denySelectStar(table) {
+				return RuleSelectStar, "禁止对表 " + table + " 使用 SELECT *"
+			}
+		}
+	}
+	return "", ""
+}
+
+// denySelectStar 判断表是否禁止 SELECT *，表名带模式时也按不带模式的名称匹配
+func (p *StatementPolicy) denySelectStar(table string) bool {
+	name := table
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = strings.Trim(name[i+1:], "`\"[]")
+	}
+	for _, denied := range p.DenySelectStar {
+		if denied == "*" || strings.EqualFold(denied, table) || strings.EqualFold(denied, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerPolicyCallbacks 注册语句策略回调
+// ORM 方法在构建SQL之前按语句信息检查，原生语句按已生成的SQL检查
+func registerPolicyCallbacks(db *gorm.DB, nick string) error {
+	callback := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callback.Create().Before("*").Register("gosqlx:policy", policyCallback(nick, "INSERT"))
+		},
+		func() error {
+			return callback.Query().Before("*").Register("gosqlx:policy", policyCallback(nick, "SELECT"))
+		},
+		func() error {
+			return callback.Update().Before("*").Register("gosqlx:policy", policyCallback(nick, "UPDATE"))
+		},
+		func() error {
+			return callback.Delete().Before("*").Register("gosqlx:policy", policyCallback(nick, "DELETE"))
+		},
+		func() error { return callback.Raw().Before("*").Register("gosqlx:policy", policyCallback(nick, "")) },
+		func() error { return callback.Row().Before("*").Register("gosqlx:policy", policyCallback(nick, "")) },
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return fmt.Errorf("注册语句策略回调失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// policyCallback 检查语句策略，statement 为 ORM 方法对应的语句类型，为空时检查原生SQL
+func policyCallback(nick, statement string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		policy := policyFor(nick)
+		if policy == nil {
+			return
+		}
+		sqlStr := db.Statement.SQL.String()
+		var rule, detail string
+		if sqlStr != "" || statement == "" {
+			rule, detail = policy.inspect(sqlStr)
+		} else {
+			sqlStr = db.Statement.Table
+			rule, detail = policy.inspectStatement(db.Statement, statement)
+		}
+		if err := reportViolation(nick, policy, rule, detail, sqlStr); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// inspectStatement 按 ORM 语句的信息检查：是否有 WHERE 条件、是否未指定查询列
+func (p *StatementPolicy) inspectStatement(stmt *gorm.Statement, statement string) (string, string) {
+	for _, denied := range p.DenyStatements {
+		if strings.EqualFold(denied, statement) {
+			return RuleStatement, "禁止执行 " + statement + " 语句"
+		}
+	}
+	_, hasWhere := stmt.Clauses["WHERE"]
+	// 没有条件且未开启 AllowGlobalUpdate 时 GORM 会按主键补充条件或拒绝执行
+	global := !hasWhere && stmt.DB.AllowGlobalUpdate
+	switch statement {
+	case "DELETE":
+		if p.DenyDeleteWithoutWhere && global {
+			return RuleDeleteWithoutWhere, "禁止不带 WHERE 的 DELETE"
+		}
+	case "UPDATE":
+		if p.DenyUpdateWithoutWhere && global {
+			return RuleUpdateWithoutWhere, "禁止不带 WHERE 的 UPDATE"
+		}
+	case "SELECT":
+		if len(p.DenySelectStar) > 0 && selectsAll(stmt) && p.denySelectStar(stmt.Table) {
+			return RuleSelectStar, "禁止对表 " + stmt.Table + " 使用 SELECT *"
+		}
+	}
+	return "", ""
+}
+
+// selectsAll 判断查询是否会生成 SELECT *
+func selectsAll(stmt *gorm.Statement) bool {
+	if len(stmt.Selects) > 0 || stmt.DB.QueryFields {
+		return false
+	}
+	// Count 等方法设置的 SELECT 子句表达式不是 clause.Select
+	if c, ok := stmt.Clauses["SELECT"]; ok && c.Expression != nil {
+		if sel, ok := c.Expression.(clause.Select); !ok || len(sel.Columns) > 0 || sel.Expression != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPolicy 检查不经过 GORM 执行的语句
+func (d *Database) checkPolicy(sqlStr string) error {
+	if d.ctx == nil {
+		return nil
+	}
+	return CheckPolicy(d.ctx.Nick, sqlStr)
+}
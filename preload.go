@@ -0,0 +1,62 @@
+package gosqlx
+
+import "gorm.io/gorm"
+
+// ==================== 关联预加载 ====================
+
+// Relation 待加载的关联，作为 Find/First/Take/Last 的条件参数传入
+type Relation struct {
+	name       string
+	join       bool
+	conditions []interface{}
+}
+
+// Preload 预加载关联: 主查询完成后以一条 IN 查询批量加载全部关联记录，避免 N+1 查询
+// 嵌套关联以点号分隔，如 "Articles.Comments"；conditions 为关联记录的过滤条件
+//
+//	err := db.Find(&users, gosqlx.Preload("Articles", "status = ?", 1), "age > ?", 18)
+func Preload(name string, conditions ...interface{}) Relation {
+	return Relation{name: name, conditions: conditions}
+}
+
+// JoinPreload 通过 LEFT JOIN 在主查询中一并加载关联，仅适用于 belongs_to/has_one 关联
+func JoinPreload(name string, conditions ...interface{}) Relation {
+	return Relation{name: name, join: true, conditions: conditions}
+}
+
+// splitRelations 从条件参数中分离出关联，不修改调用方的切片
+func splitRelations(where []interface{}) ([]Relation, []interface{}) {
+	var (
+		relations []Relation
+		rest      []interface{}
+	)
+	for i, arg := range where {
+		relation, ok := arg.(Relation)
+		if !ok {
+			if relations != nil {
+				rest = append(rest, arg)
+			}
+			continue
+		}
+		if relations == nil {
+			rest = append(make([]interface{}, 0, len(where)), where[:i]...)
+		}
+		relations = append(relations, relation)
+	}
+	if relations == nil {
+		return nil, where
+	}
+	return relations, rest
+}
+
+// applyRelations 将关联加载应用到查询
+func applyRelations(tx *gorm.DB, relations []Relation) *gorm.DB {
+	for _, relation := range relations {
+		if relation.join {
+			tx = tx.Joins(relation.name, relation.conditions...)
+		} else {
+			tx = tx.Preload(relation.name, relation.conditions...)
+		}
+	}
+	return tx
+}
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -9,31 +10,47 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gzorm/gosqlx/adapter"
 	"github.com/gzorm/gosqlx/builder"
 )
 
 // Query 查询构建器
 type Query struct {
-	db        interface{}    // 数据库连接
-	table     string         // 表名
-	alias     string         // 表别名
-	columns   []string       // 查询列
-	joins     []string       // 连接语句
-	where     *builder.Where // 条件构建器
-	group     string         // 分组语句
-	having    string         // 过滤语句
-	order     *builder.Order // 排序构建器
-	limit     int            // 限制数
-	offset    int            // 偏移量
-	forUpdate bool           // 行锁
-	forShare  bool           // 共享锁
-	distinct  bool           // 去重
-	count     string         // 计数字段
-	sum       string         // 求和字段
-	avg       string         // 平均值字段
-	max       string         // 最大值字段
-	min       string         // 最小值字段
-	args      []interface{}  // 参数值
+	db          interface{}       // 数据库连接
+	table       string            // 表名
+	alias       string            // 表别名
+	columns     []string          // 查询列
+	selectArgs  []interface{}     // 查询列参数
+	joins       []string          // 连接语句
+	where       *builder.Where    // 条件构建器
+	group       string            // 分组语句
+	having      string            // 过滤语句
+	order       *builder.Order    // 排序构建器
+	limit       int               // 限制数
+	offset      int               // 偏移量
+	forUpdate   bool              // 行锁
+	forShare    bool              // 共享锁
+	skipLocked  bool              // 跳过已锁定的行
+	noWait      bool              // 不等待锁
+	adapter     adapter.Adapter   // 锁语法适配器
+	distinct    bool              // 去重
+	distinctOn  []string          // DISTINCT ON 列（PostgreSQL）
+	count       string            // 计数字段
+	sum         string            // 求和字段
+	avg         string            // 平均值字段
+	max         string            // 最大值字段
+	min         string            // 最小值字段
+	args        []interface{}     // 参数值
+	err         error             // 构建错误
+	dialect     string            // 数据库方言
+	ctes        []cte             // 公用表表达式
+	ctx         context.Context   // 执行上下文
+	timeout     time.Duration     // 单次查询超时
+	comment     map[string]string // 每条语句附带的注释属性
+	readOnly    bool              // 只读，拒绝写语句
+	mongo       mongoOptions      // MongoDB 聚合管道的附加阶段
+	projection  map[string]string // SelectFields 选择的列（小写）到 JSON 名的映射
+	jsonColumns map[string]bool   // SelectJSONAgg、SelectJSONObject 生成的 JSON 列别名
 }
 
 // NewQuery 创建查询构建器
@@ -63,6 +80,8 @@ func (q *Query) Alias(alias string) *Query {
 func (q *Query) Select(columns ...string) *Query {
 	if len(columns) > 0 {
 		q.columns = columns
+		q.selectArgs = nil
+		q.jsonColumns = nil
 	}
 	return q
 }
@@ -70,7 +89,8 @@ func (q *Query) Select(columns ...string) *Query {
 // SelectRaw 设置原始查询列
 func (q *Query) SelectRaw(query string, args ...interface{}) *Query {
 	q.columns = []string{query}
-	q.args = append(q.args, args...)
+	q.selectArgs = args
+	q.jsonColumns = nil
 	return q
 }
 
@@ -122,15 +142,22 @@ func (q *Query) WhereIf(condition bool, query string, args ...interface{}) *Quer
 	return q
 }
 
+// WhereMap 添加 map 形式的过滤条件，键为 "字段 操作符"，allowed 不为空时只允许其中的字段，见 builder.Where.WhereMap
+func (q *Query) WhereMap(filters map[string]interface{}, allowed ...string) *Query {
+	q.where.Dialect(q.detectDialect()).WhereMap(filters, allowed...)
+	return q
+}
+
 // WhereIn 添加IN条件
+// 元素过多时按方言自动拆分，见 builder.SetInLimit
 func (q *Query) WhereIn(field string, values interface{}) *Query {
-	q.where.WhereIn(field, values)
+	q.where.Dialect(q.detectDialect()).WhereIn(field, values)
 	return q
 }
 
 // WhereNotIn 添加NOT IN条件
 func (q *Query) WhereNotIn(field string, values interface{}) *Query {
-	q.where.WhereNotIn(field, values)
+	q.where.Dialect(q.detectDialect()).WhereNotIn(field, values)
 	return q
 }
 
@@ -178,6 +205,9 @@ func (q *Query) Group(group string) *Query {
 
 // Having 添加过滤
 func (q *Query) Having(having string, args ...interface{}) *Query {
+	if err := builder.Inspect(having); err != nil && q.err == nil {
+		q.err = err
+	}
 	q.having = having
 	q.args = append(q.args, args...)
 	return q
@@ -201,6 +231,20 @@ func (q *Query) OrderByDesc(field string) *Query {
 	return q
 }
 
+// OrderBySafe 按白名单校验外部传入的排序参数（如接口的 sort 参数）后追加排序，字段不在白名单内时查询返回 builder.ErrInvalidSort
+// 排序项格式见 builder.SafeOrder，allowed 将外部字段名映射为列名，映射值为空时使用字段名本身
+//
+//	q.OrderBySafe(r.URL.Query().Get("sort"), map[string]string{"created": "created_at", "name": ""})
+func (q *Query) OrderBySafe(input string, allowed map[string]string) *Query {
+	order, err := builder.SafeOrder(input, allowed)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.order.AppendOrderBy(order)
+	return q
+}
+
 // Limit 设置限制数
 func (q *Query) Limit(limit int) *Query {
 	q.limit = limit
@@ -240,10 +284,11 @@ func (q *Query) ForShare() *Query {
 	return q
 }
 
-// Distinct 设置去重
-func (q *Query) Distinct() *Query {
+// Distinct 设置去重，传入列时同时设置查询列
+// 示例: Distinct("city", "country")
+func (q *Query) Distinct(columns ...string) *Query {
 	q.distinct = true
-	return q
+	return q.Select(columns...)
 }
 
 // Count 设置计数
@@ -295,7 +340,7 @@ func (q *Query) First(out interface{}) error {
 
 // Value 获取单个值
 func (q *Query) Value(column string) (interface{}, error) {
-	q.columns = []string{column}
+	defer q.replaceColumns(column)()
 	q.limit = 1
 	sqlStr, args := q.BuildSelect()
 
@@ -306,14 +351,14 @@ func (q *Query) Value(column string) (interface{}, error) {
 
 // Pluck 获取单列值
 func (q *Query) Pluck(column string, out interface{}) error {
-	q.columns = []string{column}
+	defer q.replaceColumns(column)()
 	sqlSelect, args := q.BuildSelect()
 	return q.execQuery(sqlSelect, args, out)
 }
 
 // Exists 判断是否存在
 func (q *Query) Exists() (bool, error) {
-	q.columns = []string{"1"}
+	defer q.replaceColumns("1")()
 	q.limit = 1
 	sqlSelect, args := q.BuildSelect()
 
@@ -330,12 +375,11 @@ func (q *Query) Exists() (bool, error) {
 
 // CountNum 获取记录数
 func (q *Query) CountNum() (int64, error) {
-	oldColumns := q.columns
 	oldLimit := q.limit
 	oldOffset := q.offset
 	oldOrder := q.order
 
-	q.columns = []string{"COUNT(*) as count"}
+	restoreColumns := q.replaceColumns("COUNT(*) as count")
 	q.limit = 0
 	q.offset = 0
 	q.order = builder.NewOrder()
@@ -345,7 +389,7 @@ func (q *Query) CountNum() (int64, error) {
 	var count int64
 	err := q.execQueryRow(sqlStr, args, &count)
 
-	q.columns = oldColumns
+	restoreColumns()
 	q.limit = oldLimit
 	q.offset = oldOffset
 	q.order = oldOrder
@@ -355,11 +399,10 @@ func (q *Query) CountNum() (int64, error) {
 
 // SumNum 获取求和
 func (q *Query) SumNum(field string) (float64, error) {
-	oldColumns := q.columns
 	oldLimit := q.limit
 	oldOffset := q.offset
 
-	q.columns = []string{fmt.Sprintf("SUM(%s) as sum", field)}
+	restoreColumns := q.replaceColumns(fmt.Sprintf("SUM(%s) as sum", field))
 	q.limit = 0
 	q.offset = 0
 
@@ -368,7 +411,7 @@ func (q *Query) SumNum(field string) (float64, error) {
 	var sum float64
 	err := q.execQueryRow(sqlStr, args, &sum)
 
-	q.columns = oldColumns
+	restoreColumns()
 	q.limit = oldLimit
 	q.offset = oldOffset
 
@@ -377,11 +420,10 @@ func (q *Query) SumNum(field string) (float64, error) {
 
 // AvgNum 获取平均值
 func (q *Query) AvgNum(field string) (float64, error) {
-	oldColumns := q.columns
 	oldLimit := q.limit
 	oldOffset := q.offset
 
-	q.columns = []string{fmt.Sprintf("AVG(%s) as avg", field)}
+	restoreColumns := q.replaceColumns(fmt.Sprintf("AVG(%s) as avg", field))
 	q.limit = 0
 	q.offset = 0
 
@@ -390,7 +432,7 @@ func (q *Query) AvgNum(field string) (float64, error) {
 	var avg float64
 	err := q.execQueryRow(sqlStr, args, &avg)
 
-	q.columns = oldColumns
+	restoreColumns()
 	q.limit = oldLimit
 	q.offset = oldOffset
 
@@ -399,11 +441,10 @@ func (q *Query) AvgNum(field string) (float64, error) {
 
 // MaxNum 获取最大值
 func (q *Query) MaxNum(field string) (interface{}, error) {
-	oldColumns := q.columns
 	oldLimit := q.limit
 	oldOffset := q.offset
 
-	q.columns = []string{fmt.Sprintf("MAX(%s) as max", field)}
+	restoreColumns := q.replaceColumns(fmt.Sprintf("MAX(%s) as max", field))
 	q.limit = 0
 	q.offset = 0
 
@@ -412,7 +453,7 @@ func (q *Query) MaxNum(field string) (interface{}, error) {
 	var maxValue interface{}
 	err := q.execQueryRow(sqlBulder, args, &maxValue)
 
-	q.columns = oldColumns
+	restoreColumns()
 	q.limit = oldLimit
 	q.offset = oldOffset
 
@@ -421,11 +462,10 @@ func (q *Query) MaxNum(field string) (interface{}, error) {
 
 // MinNum 获取最小值
 func (q *Query) MinNum(field string) (interface{}, error) {
-	oldColumns := q.columns
 	oldLimit := q.limit
 	oldOffset := q.offset
 
-	q.columns = []string{fmt.Sprintf("MIN(%s) as min", field)}
+	restoreColumns := q.replaceColumns(fmt.Sprintf("MIN(%s) as min", field))
 	q.limit = 0
 	q.offset = 0
 
@@ -434,13 +474,22 @@ func (q *Query) MinNum(field string) (interface{}, error) {
 	var minValue interface{}
 	err := q.execQueryRow(sqlBuilder, args, &minValue)
 
-	q.columns = oldColumns
+	restoreColumns()
 	q.limit = oldLimit
 	q.offset = oldOffset
 
 	return minValue, err
 }
 
+// replaceColumns 临时替换查询列（同时清空查询列参数），返回恢复函数
+func (q *Query) replaceColumns(columns ...string) func() {
+	oldColumns, oldArgs := q.columns, q.selectArgs
+	q.columns, q.selectArgs = columns, nil
+	return func() {
+		q.columns, q.selectArgs = oldColumns, oldArgs
+	}
+}
+
 // BuildSelect 构建SELECT语句
 func (q *Query) BuildSelect() (string, []interface{}) {
 	var query strings.Builder
@@ -448,7 +497,11 @@ func (q *Query) BuildSelect() (string, []interface{}) {
 
 	// SELECT
 	query.WriteString("SELECT ")
-	if q.distinct {
+	if len(q.distinctOn) > 0 {
+		query.WriteString("DISTINCT ON (")
+		query.WriteString(strings.Join(q.distinctOn, ", "))
+		query.WriteString(") ")
+	} else if q.distinct {
 		query.WriteString("DISTINCT ")
 	}
 
@@ -465,8 +518,11 @@ func (q *Query) BuildSelect() (string, []interface{}) {
 		query.WriteString(fmt.Sprintf("MIN(%s)", q.min))
 	} else {
 		query.WriteString(strings.Join(q.columns, ", "))
+		args = append(args, q.selectArgs...)
 	}
 
+	lockHint, lockSuffix := q.lockClause()
+
 	// FROM
 	query.WriteString(" FROM ")
 	query.WriteString(q.table)
@@ -474,6 +530,10 @@ func (q *Query) BuildSelect() (string, []interface{}) {
 		query.WriteString(" AS ")
 		query.WriteString(q.alias)
 	}
+	if lockHint != "" {
+		query.WriteString(" ")
+		query.WriteString(lockHint)
+	}
 
 	// JOIN
 	if len(q.joins) > 0 {
@@ -481,8 +541,8 @@ func (q *Query) BuildSelect() (string, []interface{}) {
 		query.WriteString(strings.Join(q.joins, " "))
 	}
 
-	// WHERE
-	whereStr, whereArgs := q.where.Build()
+	// WHERE，含主表的强制过滤条件
+	whereStr, whereArgs := q.filteredWhere()
 	if whereStr != "" {
 		query.WriteString(" WHERE ")
 		query.WriteString(whereStr)
@@ -517,29 +577,31 @@ func (q *Query) BuildSelect() (string, []interface{}) {
 		}
 	}
 
-	// FOR UPDATE / FOR SHARE
-	// 检查是否为 ClickHouse 数据库，如果是则跳过锁定语句
-	isClickHouse := false
-	if db, ok := q.db.(*sql.DB); ok {
-		if driver := reflect.TypeOf(db.Driver()).String(); strings.Contains(strings.ToLower(driver), "clickhouse") {
-			isClickHouse = true
-		}
-	}
-
-	if !isClickHouse {
-		if q.forUpdate {
-			query.WriteString(" FOR UPDATE")
-		} else if q.forShare {
-			query.WriteString(" FOR SHARE")
-		}
+	// FOR UPDATE / FOR SHARE，由适配器决定具体语法
+	if lockSuffix != "" {
+		query.WriteString(" ")
+		query.WriteString(lockSuffix)
 	}
 
 	// 合并参数
 	args = append(args, q.args...)
 
+	// WITH 子句位于最前，参数也最先绑定
+	if withSQL, withArgs := q.buildWith(); withSQL != "" {
+		return withSQL + query.String(), append(withArgs, args...)
+	}
+
 	return query.String(), args
 }
 
+// Err 获取构建过程中产生的错误
+func (q *Query) Err() error {
+	if q.err != nil {
+		return q.err
+	}
+	return q.where.Err()
+}
+
 // execQuery 执行查询
 func (q *Query) execQuery(sqlStr string, args []interface{}, out interface{}) error {
 	// 检查输出参数
@@ -547,27 +609,36 @@ func (q *Query) execQuery(sqlStr string, args []interface{}, out interface{}) er
 		return errors.New("输出参数不能为空")
 	}
 
+	// 检查构建错误
+	if err := q.Err(); err != nil {
+		return err
+	}
+
 	// 检查数据库连接
 	if q.db == nil {
 		return errors.New("数据库连接不能为空")
 	}
 
+	ctx, cancel := q.context()
+	defer cancel()
+	sqlStr = q.commented(ctx, builder.Rebind(q.detectDialect(), sqlStr))
+
 	// 根据数据库连接类型执行查询
 	switch db := q.db.(type) {
 	case *sql.DB:
-		rows, err := db.Query(sqlStr, args...)
+		rows, err := db.QueryContext(ctx, sqlStr, args...)
 		if err != nil {
-			return err
+			return TimeoutError(ctx, err)
 		}
 		defer rows.Close()
-		return scanRows(rows, out)
+		return TimeoutError(ctx, scanRows(rows, out))
 	case *sql.Tx:
-		rows, err := db.Query(sqlStr, args...)
+		rows, err := db.QueryContext(ctx, sqlStr, args...)
 		if err != nil {
-			return err
+			return TimeoutError(ctx, err)
 		}
 		defer rows.Close()
-		return scanRows(rows, out)
+		return TimeoutError(ctx, scanRows(rows, out))
 	default:
 		return fmt.Errorf("不支持的数据库连接类型: %T", q.db)
 	}
@@ -580,17 +651,26 @@ func (q *Query) execQueryRow(sqlStr string, args []interface{}, out interface{})
 		return errors.New("输出参数不能为空")
 	}
 
+	// 检查构建错误
+	if err := q.Err(); err != nil {
+		return err
+	}
+
 	// 检查数据库连接
 	if q.db == nil {
 		return errors.New("数据库连接不能为空")
 	}
 
+	ctx, cancel := q.context()
+	defer cancel()
+	sqlStr = q.commented(ctx, builder.Rebind(q.detectDialect(), sqlStr))
+
 	// 根据数据库连接类型执行查询
 	switch db := q.db.(type) {
 	case *sql.DB:
-		return db.QueryRow(sqlStr, args...).Scan(out)
+		return TimeoutError(ctx, db.QueryRowContext(ctx, sqlStr, args...).Scan(out))
 	case *sql.Tx:
-		return db.QueryRow(sqlStr, args...).Scan(out)
+		return TimeoutError(ctx, db.QueryRowContext(ctx, sqlStr, args...).Scan(out))
 	default:
 		return fmt.Errorf("不支持的数据库连接类型: %T", q.db)
 	}
@@ -615,29 +695,21 @@ func scanRows(rows *sql.Rows, out interface{}) error {
 
 	// 处理切片类型
 	if outValue.Kind() == reflect.Slice {
-		// 获取切片元素类型
+		// 直接扫描到切片末尾的新元素，扫描器在各行之间复用
 		elemType := outValue.Type().Elem()
-
-		// 创建切片
+		scanner := newRowScanner(columns, elemType)
 		slice := reflect.MakeSlice(outValue.Type(), 0, 0)
-
-		// 遍历结果集
+		zero := reflect.Zero(elemType)
 		for rows.Next() {
-			// 创建元素
-			elem := reflect.New(elemType).Elem()
-
-			// 扫描行数据
-			if err := scanRow(rows, columns, elem); err != nil {
+			slice = reflect.Append(slice, zero)
+			if err := scanner.scan(rows, slice.Index(slice.Len()-1)); err != nil {
 				return err
 			}
-
-			// 添加到切片
-			slice = reflect.Append(slice, elem)
 		}
 
-		// 设置输出值
+		// 设置输出值，遍历中断（如超时）时返回错误
 		outValue.Set(slice)
-		return nil
+		return rows.Err()
 	}
 
 	// 处理结构体类型
@@ -646,13 +718,7 @@ func scanRows(rows *sql.Rows, out interface{}) error {
 		if !rows.Next() {
 			return sql.ErrNoRows
 		}
-
-		// 扫描行数据
-		if err := scanRow(rows, columns, outValue); err != nil {
-			return err
-		}
-
-		return nil
+		return newRowScanner(columns, outValue.Type()).scan(rows, outValue)
 	}
 
 	// 处理基本类型
@@ -664,74 +730,13 @@ func scanRows(rows *sql.Rows, out interface{}) error {
 	return rows.Scan(out)
 }
 
-// scanRow 扫描单行数据
-func scanRow(rows *sql.Rows, columns []string, outValue reflect.Value) error {
-	// 创建扫描目标
-	targets := make([]interface{}, len(columns))
-	for i := range columns {
-		targets[i] = new(interface{})
-	}
-
-	// 扫描行数据
-	if err := rows.Scan(targets...); err != nil {
-		return err
-	}
-
-	// 设置结构体字段值
-	if outValue.Kind() == reflect.Struct {
-		for i, column := range columns {
-			// 查找字段
-			field := findField(outValue, column)
-			if !field.IsValid() {
-				continue
-			}
-
-			// 获取扫描值
-			scanValue := reflect.ValueOf(targets[i]).Elem().Interface()
-			if scanValue == nil {
-				continue
-			}
-
-			// 设置字段值
-			if err := setFieldValue(field, scanValue); err != nil {
-				return err
-			}
-		}
-		return nil
-	}
-
-	// 设置基本类型值
-	scanValue := reflect.ValueOf(targets[0]).Elem().Interface()
-	if scanValue == nil {
-		return nil
-	}
-
-	// 设置值
-	return setFieldValue(outValue, scanValue)
-}
-
-// findField 查找结构体字段
+// findField 查找结构体字段，规则见 fieldIndex
 func findField(outValue reflect.Value, column string) reflect.Value {
-	// 获取结构体类型
-	outType := outValue.Type()
-
-	// 遍历字段
-	for i := 0; i < outType.NumField(); i++ {
-		field := outType.Field(i)
-
-		// 检查标签
-		tag := field.Tag.Get("db")
-		if tag == column {
-			return outValue.Field(i)
-		}
-
-		// 检查字段名
-		if strings.EqualFold(field.Name, column) {
-			return outValue.Field(i)
-		}
+	index := fieldIndex(outValue.Type(), column)
+	if index == nil {
+		return reflect.Value{}
 	}
-
-	return reflect.Value{}
+	return outValue.FieldByIndex(index)
 }
 
 // setFieldValue 设置字段值
@@ -741,6 +746,13 @@ func setFieldValue(field reflect.Value, value interface{}) error {
 		return nil
 	}
 
+	// 实现 sql.Scanner 的字段（sql.NullString、gosqlx.Null[T] 等）由其自行转换
+	if field.CanAddr() && field.CanInterface() {
+		if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(value)
+		}
+	}
+
 	// 若 field 是指针，初始化并取其 Elem
 	if field.Kind() == reflect.Ptr {
 		elemType := field.Type().Elem()
@@ -884,8 +896,16 @@ func setFieldValue(field reflect.Value, value interface{}) error {
 				return fmt.Errorf("无法将 %T 转换为 time.Time", value)
 			}
 		} else {
-			return fmt.Errorf("不支持的 struct 类型: %s", field.Type().Name())
+			// 结构体从 JSON 文本解码，如 SelectJSONObject 的结果
+			return setJSONValue(field, value)
+		}
+	case reflect.Slice, reflect.Map:
+		if b, ok := value.([]byte); ok && field.Type() == reflect.TypeOf(b) {
+			field.SetBytes(append([]byte(nil), b...))
+			return nil
 		}
+		// 切片和 map 从 JSON 文本解码，如 SelectJSONAgg 聚合的子记录
+		return setJSONValue(field, value)
 	case reflect.Interface:
 		field.Set(valueValue)
 	default:
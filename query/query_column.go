@@ -0,0 +1,39 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/gzorm/gosqlx/builder"
+)
+
+// From 按生成的表名常量设置表名
+// 示例: NewQuery(db).From(poes.UserTable)
+func (q *Query) From(table builder.Table) *Query {
+	return q.Table(string(table))
+}
+
+// SelectCols 按生成的列名常量设置查询列
+// 示例: SelectCols(poes.UserCols.ID, poes.UserCols.Username)
+func (q *Query) SelectCols(columns ...builder.Column) *Query {
+	return q.Select(builder.ColumnNames(columns...)...)
+}
+
+// WhereCol 按列名常量添加条件，op 与 WhereMap 的操作符相同（= != > >= < <= like in between 等），值作为参数绑定
+// 示例: WhereCol(poes.UserCols.Age, ">=", 18).WhereCol(poes.UserCols.Status, "in", []int{1, 2})
+func (q *Query) WhereCol(column builder.Column, op string, value interface{}) *Query {
+	q.where.Dialect(q.detectDialect()).WhereMap(map[string]interface{}{string(column) + " " + op: value})
+	return q
+}
+
+// GroupByCols 按列名常量分组
+func (q *Query) GroupByCols(columns ...builder.Column) *Query {
+	return q.Group(strings.Join(builder.ColumnNames(columns...), ", "))
+}
+
+// OrderByCol 按列名常量排序，desc 为 true 时降序
+func (q *Query) OrderByCol(column builder.Column, desc bool) *Query {
+	if desc {
+		return q.OrderByDesc(string(column))
+	}
+	return q.OrderByAsc(string(column))
+}
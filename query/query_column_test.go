@@ -0,0 +1,33 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gzorm/gosqlx/builder"
+)
+
+// 测试使用生成的表名和列名常量构建查询
+func TestColumnConstants(t *testing.T) {
+	const usersTable builder.Table = "users"
+	var usersCols = struct {
+		ID, Name, Age, Status builder.Column
+	}{ID: "id", Name: "name", Age: "age", Status: "status"}
+
+	sqlStr, args := NewQuery(nil).From(usersTable).Alias("u").
+		SelectCols(usersCols.ID.Of("u"), usersCols.Name).
+		WhereCol(usersCols.Age, ">=", 18).
+		WhereCol(usersCols.Status, "in", []int{1, 2}).
+		GroupByCols(usersCols.ID, usersCols.Name).
+		OrderByCol(usersCols.Name, true).
+		BuildSelect()
+	want := "SELECT u.id, name FROM users AS u WHERE age >= ? AND status IN (?, ?) GROUP BY id, name ORDER BY name DESC"
+	if sqlStr != want || !reflect.DeepEqual(args, []interface{}{18, 1, 2}) {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, args)
+	}
+
+	q := NewQuery(nil).From(usersTable).WhereCol(usersCols.Age, "~", 1)
+	if q.Err() == nil {
+		t.Error("不支持的操作符应返回错误")
+	}
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// commentKey SQL注释属性在上下文中的键
+type commentKey struct{}
+
+// WithComment 返回携带SQL注释属性的上下文，kv 为成对的键和值，同名属性覆盖外层上下文中的值
+// 经由该上下文执行的语句以 /* app=orders svc=checkout trace=abc123 */ 开头，便于在 processlist 和慢日志中定位来源:
+//
+//	ctx = query.WithComment(ctx, "trace", traceID)
+//	q.WithContext(ctx).Table("orders").Find(&orders)
+func WithComment(ctx context.Context, kv ...string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parent, _ := ctx.Value(commentKey{}).(map[string]string)
+	attrs := make(map[string]string, len(parent)+len(kv)/2)
+	for key, value := range parent {
+		attrs[key] = value
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		attrs[kv[i]] = kv[i+1]
+	}
+	return context.WithValue(ctx, commentKey{}, attrs)
+}
+
+// CommentAttrs 返回上下文中的SQL注释属性，没有时返回 nil
+func CommentAttrs(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(commentKey{}).(map[string]string)
+	return attrs
+}
+
+// Comment 设置每条语句都附带的注释属性，与上下文中的属性合并，上下文中的同名属性优先
+func (q *Query) Comment(attrs map[string]string) *Query {
+	q.comment = attrs
+	return q
+}
+
+// FormatComment 合并固定属性和上下文中的属性生成注释，没有属性时返回空字符串
+// 属性按键排序；键和值中除字母、数字和 - _ . : / 以外的字符替换为 _，避免提前结束注释
+func FormatComment(attrs map[string]string, ctx context.Context) string {
+	ctxAttrs := CommentAttrs(ctx)
+	if len(attrs) == 0 && len(ctxAttrs) == 0 {
+		return ""
+	}
+
+	merged := make(map[string]string, len(attrs)+len(ctxAttrs))
+	for key, value := range attrs {
+		merged[key] = value
+	}
+	for key, value := range ctxAttrs {
+		merged[key] = value
+	}
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("/*")
+	for _, key := range keys {
+		b.WriteByte(' ')
+		b.WriteString(sanitizeComment(key))
+		b.WriteByte('=')
+		b.WriteString(sanitizeComment(merged[key]))
+	}
+	b.WriteString(" */")
+	return b.String()
+}
+
+// PrependComment 在SQL前加上注释，注释为空或SQL已以该注释开头时原样返回
+func PrependComment(comment, sqlStr string) string {
+	if comment == "" || strings.HasPrefix(sqlStr, comment) {
+		return sqlStr
+	}
+	return comment + " " + sqlStr
+}
+
+// sanitizeComment 替换注释属性中的不安全字符
+func sanitizeComment(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.:/", r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// commented 为即将执行的语句加上注释
+func (q *Query) commented(ctx context.Context, sqlStr string) string {
+	return PrependComment(FormatComment(q.comment, ctx), sqlStr)
+}
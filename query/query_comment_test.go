@@ -0,0 +1,30 @@
+package query
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFormatComment(t *testing.T) {
+	if got := FormatComment(nil, context.Background()); got != "" {
+		t.Errorf("没有属性时 FormatComment() = %q", got)
+	}
+
+	ctx := WithComment(context.Background(), "trace", "abc123", "svc", "old")
+	ctx = WithComment(ctx, "svc", "checkout", "odd")
+	got := FormatComment(map[string]string{"app": "orders", "svc": "static"}, ctx)
+	if want := "/* app=orders svc=checkout trace=abc123 */"; got != want {
+		t.Errorf("FormatComment() = %q, want %q", got, want)
+	}
+
+	// 不安全字符被替换，无法提前结束注释
+	got = FormatComment(map[string]string{"user name": "x */ DROP TABLE t; --"}, nil)
+	if want := "/* user_name=x__/_DROP_TABLE_t__-- */"; got != want {
+		t.Errorf("FormatComment() = %q, want %q", got, want)
+	}
+
+	sqlStr := PrependComment("/* a=1 */", "SELECT 1")
+	if sqlStr != "/* a=1 */ SELECT 1" || PrependComment("/* a=1 */", sqlStr) != sqlStr {
+		t.Errorf("PrependComment() = %q", sqlStr)
+	}
+}
@@ -0,0 +1,103 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// cte 公用表表达式
+type cte struct {
+	name      string   // 名称
+	columns   []string // 列名
+	anchor    *Query   // 查询（递归时为初始查询）
+	recursive *Query   // 递归查询
+}
+
+// With 添加公用表表达式（CTE）
+// 示例: q.With("active_users", NewQuery(db).Table("users").Where("active = ?", 1)).Table("active_users")
+func (q *Query) With(name string, sub *Query, columns ...string) *Query {
+	if sub == nil {
+		q.setErr(errors.New("CTE 子查询不能为空"))
+		return q
+	}
+	switch q.detectDialect() {
+	case "mongodb":
+		q.setErr(fmt.Errorf("%s 不支持公用表表达式", q.detectDialect()))
+	}
+	q.ctes = append(q.ctes, cte{name: name, columns: columns, anchor: sub})
+	return q
+}
+
+// WithRecursive 添加递归公用表表达式，生成 name(columns) AS (anchor UNION ALL recursive)
+// 示例: q.WithRecursive("tree", NewQuery(db).Table("menus").Select("id", "parent_id").Where("id = ?", 1),
+// NewQuery(db).Table("menus m").Select("m.id", "m.parent_id").Join("tree t", "m.parent_id = t.id"), "id", "parent_id")
+func (q *Query) WithRecursive(name string, anchor, recursive *Query, columns ...string) *Query {
+	if anchor == nil || recursive == nil {
+		q.setErr(errors.New("递归 CTE 的初始查询和递归查询不能为空"))
+		return q
+	}
+	switch dialect := q.detectDialect(); dialect {
+	case "mongodb", "clickhouse":
+		q.setErr(fmt.Errorf("%s 不支持递归公用表表达式", dialect))
+	case "oracle":
+		if len(columns) == 0 {
+			q.setErr(errors.New("Oracle 递归 CTE 必须指定列名"))
+		}
+	}
+	q.ctes = append(q.ctes, cte{name: name, columns: columns, anchor: anchor, recursive: recursive})
+	return q
+}
+
+// setErr 记录首个构建错误
+func (q *Query) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// buildWith 构建 WITH 子句
+func (q *Query) buildWith() (string, []interface{}) {
+	if len(q.ctes) == 0 {
+		return "", nil
+	}
+
+	var (
+		parts     []string
+		args      []interface{}
+		recursive bool
+	)
+	for _, c := range q.ctes {
+		if err := c.anchor.Err(); err != nil {
+			q.setErr(err)
+		}
+		name := c.name
+		if len(c.columns) > 0 {
+			name += "(" + strings.Join(c.columns, ", ") + ")"
+		}
+
+		body, bodyArgs := c.anchor.BuildSelect()
+		args = append(args, bodyArgs...)
+		if c.recursive != nil {
+			if err := c.recursive.Err(); err != nil {
+				q.setErr(err)
+			}
+			recursiveSQL, recursiveArgs := c.recursive.BuildSelect()
+			body += " UNION ALL " + recursiveSQL
+			args = append(args, recursiveArgs...)
+			recursive = true
+		}
+		parts = append(parts, fmt.Sprintf("%s AS (%s)", name, body))
+	}
+
+	keyword := "WITH "
+	// SQLServer 和 Oracle 的递归 CTE 不使用 RECURSIVE 关键字
+	if recursive {
+		switch q.detectDialect() {
+		case "sqlserver", "oracle":
+		default:
+			keyword = "WITH RECURSIVE "
+		}
+	}
+	return keyword + strings.Join(parts, ", ") + " ", args
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 测试公用表表达式
+func TestWith(t *testing.T) {
+	active := NewQuery(nil).Table("users").Select("id", "name").Where("active = ?", 1)
+	sqlStr, args := NewQuery(nil).Dialect("postgres").
+		With("active_users", active).
+		Table("active_users").Where("id > ?", 10).BuildSelect()
+	want := "WITH active_users AS (SELECT id, name FROM users WHERE active = ?) SELECT * FROM active_users WHERE id > ?"
+	if sqlStr != want || !reflect.DeepEqual(args, []interface{}{1, 10}) {
+		t.Errorf("期望 %q %v，实际为 %q %v", want, []interface{}{1, 10}, sqlStr, args)
+	}
+}
+
+// 测试递归公用表表达式
+func TestWithRecursive(t *testing.T) {
+	build := func(dialect string) *Query {
+		anchor := NewQuery(nil).Table("menus").Select("id", "parent_id").Where("id = ?", 1)
+		recursive := NewQuery(nil).Table("menus m").Select("m.id", "m.parent_id").InnerJoin("tree t", "m.parent_id = t.id")
+		return NewQuery(nil).Dialect(dialect).WithRecursive("tree", anchor, recursive, "id", "parent_id").Table("tree")
+	}
+
+	sqlStr, _ := build("postgres").BuildSelect()
+	want := "WITH RECURSIVE tree(id, parent_id) AS (SELECT id, parent_id FROM menus WHERE id = ? UNION ALL " +
+		"SELECT m.id, m.parent_id FROM menus m INNER JOIN tree t ON m.parent_id = t.id) SELECT * FROM tree"
+	if sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q", want, sqlStr)
+	}
+
+	// SQLServer 不使用 RECURSIVE 关键字
+	if sqlStr, _ = build("sqlserver").BuildSelect(); sqlStr[:10] != "WITH tree(" {
+		t.Errorf("SQLServer 递归 CTE 不符合预期: %s", sqlStr)
+	}
+
+	if err := build("clickhouse").Err(); err == nil {
+		t.Error("ClickHouse 应返回不支持递归 CTE 的错误")
+	}
+}
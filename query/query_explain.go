@@ -0,0 +1,544 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx/builder"
+	"gorm.io/gorm"
+)
+
+// ExplainNode 执行计划节点
+type ExplainNode struct {
+	ID        string                 // 节点编号
+	Table     string                 // 访问的表
+	Operation string                 // 访问方式（ALL/ref/Seq Scan/Index Scan 等）
+	Index     string                 // 使用的索引
+	Rows      float64                // 预估（或实际）行数
+	Cost      float64                // 预估代价
+	Extra     string                 // 额外信息
+	Raw       map[string]interface{} // 原始行数据
+}
+
+// ExplainPlan 执行计划
+type ExplainPlan struct {
+	Dialect string        // 数据库方言
+	SQL     string        // 被分析的SQL
+	Args    []interface{} // SQL参数
+	Analyze bool          // 是否实际执行
+	Nodes   []ExplainNode // 计划节点
+	Text    string        // 文本格式的计划（部分数据库仅提供文本）
+}
+
+// TotalRows 计划中预估行数之和
+func (p *ExplainPlan) TotalRows() float64 {
+	var total float64
+	for _, node := range p.Nodes {
+		total += node.Rows
+	}
+	return total
+}
+
+// TotalCost 计划的最大代价（根节点代价）
+func (p *ExplainPlan) TotalCost() float64 {
+	var cost float64
+	for _, node := range p.Nodes {
+		if node.Cost > cost {
+			cost = node.Cost
+		}
+	}
+	return cost
+}
+
+// Indexes 计划中使用的索引
+func (p *ExplainPlan) Indexes() []string {
+	var indexes []string
+	seen := make(map[string]bool)
+	for _, node := range p.Nodes {
+		if node.Index != "" && !seen[node.Index] {
+			seen[node.Index] = true
+			indexes = append(indexes, node.Index)
+		}
+	}
+	return indexes
+}
+
+// HasFullScan 计划中是否包含全表扫描
+func (p *ExplainPlan) HasFullScan() bool {
+	for _, node := range p.Nodes {
+		if node.FullScan() {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToUpper(p.Text), "FULL SCAN")
+}
+
+// FullScan 节点是否为全表扫描
+func (n ExplainNode) FullScan() bool {
+	if n.Table == "" {
+		return false
+	}
+	op := strings.ToUpper(n.Operation)
+	switch {
+	case op == "ALL", op == "SEQ SCAN", op == "TABLE SCAN", op == "TABLE ACCESS FULL",
+		strings.HasPrefix(op, "SCAN"), op == "CLUSTERED INDEX SCAN":
+		return true
+	}
+	return false
+}
+
+// Dialect 显式指定数据库方言（mysql/postgres/sqlserver/sqlite3/oracle/clickhouse等）
+// 未指定时将根据数据库连接自动识别
+func (q *Query) Dialect(name string) *Query {
+	q.dialect = strings.ToLower(name)
+	return q
+}
+
+// Explain 获取当前查询的执行计划
+func (q *Query) Explain() (*ExplainPlan, error) {
+	return q.explain(false)
+}
+
+// ExplainAnalyze 实际执行当前查询并获取执行计划
+// 仅 MySQL 8.0.18+/MariaDB/TiDB/PostgreSQL/ClickHouse 支持
+func (q *Query) ExplainAnalyze() (*ExplainPlan, error) {
+	return q.explain(true)
+}
+
+// explain 生成并解析执行计划
+func (q *Query) explain(analyze bool) (*ExplainPlan, error) {
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	sqlStr, args := q.BuildSelect()
+	sqlStr = builder.Rebind(q.detectDialect(), sqlStr)
+	plan := &ExplainPlan{
+		Dialect: q.detectDialect(),
+		SQL:     sqlStr,
+		Args:    args,
+		Analyze: analyze,
+	}
+
+	ctx := context.Background()
+	runner, release, err := q.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	switch plan.Dialect {
+	case "mysql", "tidb", "oceanbase":
+		if analyze {
+			return plan, explainText(ctx, runner, plan, "EXPLAIN ANALYZE "+sqlStr, args)
+		}
+		return plan, explainMySQL(ctx, runner, plan, "EXPLAIN "+sqlStr, args)
+	case "mariadb":
+		if analyze {
+			return plan, explainMySQL(ctx, runner, plan, "ANALYZE "+sqlStr, args)
+		}
+		return plan, explainMySQL(ctx, runner, plan, "EXPLAIN "+sqlStr, args)
+	case "postgres":
+		prefix := "EXPLAIN (FORMAT JSON) "
+		if analyze {
+			prefix = "EXPLAIN (ANALYZE, FORMAT JSON) "
+		}
+		return plan, explainPostgres(ctx, runner, plan, prefix+sqlStr, args)
+	case "sqlite3", "sqlite":
+		if analyze {
+			return nil, fmt.Errorf("SQLite 不支持 EXPLAIN ANALYZE")
+		}
+		return plan, explainSQLite(ctx, runner, plan, "EXPLAIN QUERY PLAN "+sqlStr, args)
+	case "sqlserver":
+		if analyze {
+			return nil, fmt.Errorf("SQLServer 不支持 EXPLAIN ANALYZE，请使用 SET STATISTICS PROFILE")
+		}
+		return plan, explainSQLServer(ctx, runner, plan, sqlStr, args)
+	case "oracle":
+		if analyze {
+			return nil, fmt.Errorf("oracle 不支持 EXPLAIN ANALYZE")
+		}
+		return plan, explainOracle(ctx, runner, plan, sqlStr, args)
+	case "clickhouse":
+		if analyze {
+			return plan, explainText(ctx, runner, plan, "EXPLAIN PIPELINE "+sqlStr, args)
+		}
+		return plan, explainText(ctx, runner, plan, "EXPLAIN indexes = 1 "+sqlStr, args)
+	case "duckdb":
+		if analyze {
+			return plan, explainText(ctx, runner, plan, "EXPLAIN ANALYZE "+sqlStr, args)
+		}
+		return plan, explainText(ctx, runner, plan, "EXPLAIN "+sqlStr, args)
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", plan.Dialect)
+	}
+}
+
+// sqlRunner 可执行SQL的连接（*sql.DB/*sql.Tx/*sql.Conn）
+type sqlRunner interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// conn 获取单个会话连接，保证会话级设置（如 SHOWPLAN）在同一连接上生效
+func (q *Query) conn(ctx context.Context) (sqlRunner, func(), error) {
+	noop := func() {}
+
+	switch db := q.db.(type) {
+	case *sql.DB:
+		c, err := db.Conn(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, func() { _ = c.Close() }, nil
+	case *sql.Tx:
+		return db, noop, nil
+	case *sql.Conn:
+		return db, noop, nil
+	case *gorm.DB:
+		if tx, ok := db.Statement.ConnPool.(*sql.Tx); ok {
+			return tx, noop, nil
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, nil, err
+		}
+		c, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, func() { _ = c.Close() }, nil
+	case nil:
+		return nil, nil, fmt.Errorf("数据库连接不能为空")
+	default:
+		return nil, nil, fmt.Errorf("不支持的数据库连接类型: %T", q.db)
+	}
+}
+
+// detectDialect 识别数据库方言
+func (q *Query) detectDialect() string {
+	if q.dialect != "" {
+		return q.dialect
+	}
+
+	var driverName string
+	switch db := q.db.(type) {
+	case *gorm.DB:
+		if db.Dialector != nil {
+			return db.Dialector.Name()
+		}
+	case *sql.DB:
+		driverName = strings.ToLower(reflect.TypeOf(db.Driver()).String())
+	}
+
+	switch {
+	case strings.Contains(driverName, "mysql"):
+		return "mysql"
+	case strings.Contains(driverName, "stdlib"), strings.Contains(driverName, "pq."):
+		return "postgres"
+	case strings.Contains(driverName, "mssql"):
+		return "sqlserver"
+	case strings.Contains(driverName, "duckdb"):
+		return "duckdb"
+	case strings.Contains(driverName, "sqlite"):
+		return "sqlite3"
+	case strings.Contains(driverName, "ora"):
+		return "oracle"
+	case strings.Contains(driverName, "clickhouse"):
+		return "clickhouse"
+	}
+	return ""
+}
+
+// fetchMaps 执行查询并将每一行转换为map
+func fetchMaps(ctx context.Context, runner sqlRunner, sqlStr string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := runner.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// explainMySQL 解析 MySQL 表格格式的执行计划
+func explainMySQL(ctx context.Context, runner sqlRunner, plan *ExplainPlan, sqlStr string, args []interface{}) error {
+	rows, err := fetchMaps(ctx, runner, sqlStr, args)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		node := ExplainNode{
+			ID:        toString(row["id"]),
+			Table:     toString(row["table"]),
+			Operation: toString(row["type"]),
+			Index:     toString(row["key"]),
+			Rows:      toFloat(row["rows"]),
+			Extra:     toString(row["Extra"]),
+			Raw:       row,
+		}
+		// TiDB 的计划列与 MySQL 不同
+		if node.Table == "" {
+			node.ID = toString(row["id"])
+			node.Table = toString(row["access object"])
+			node.Operation = toString(row["task"])
+			node.Rows = toFloat(row["estRows"])
+			node.Extra = toString(row["operator info"])
+		}
+		if rowsValue, ok := row["r_rows"]; ok {
+			node.Rows = toFloat(rowsValue)
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+	return nil
+}
+
+// explainText 解析仅返回文本的执行计划
+func explainText(ctx context.Context, runner sqlRunner, plan *ExplainPlan, sqlStr string, args []interface{}) error {
+	rows, err := fetchMaps(ctx, runner, sqlStr, args)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, row := range rows {
+		for _, value := range row {
+			lines = append(lines, toString(value))
+		}
+	}
+	plan.Text = strings.Join(lines, "\n")
+	return nil
+}
+
+// explainPostgres 解析 PostgreSQL JSON 格式的执行计划
+func explainPostgres(ctx context.Context, runner sqlRunner, plan *ExplainPlan, sqlStr string, args []interface{}) error {
+	rows, err := runner.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var raw []byte
+	for rows.Next() {
+		if err := rows.Scan(&raw); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	plan.Text = string(raw)
+
+	var documents []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &documents); err != nil {
+		return fmt.Errorf("解析执行计划失败: %w", err)
+	}
+
+	var walk func(node map[string]interface{}, id string)
+	walk = func(node map[string]interface{}, id string) {
+		rowsKey := "Plan Rows"
+		if plan.Analyze {
+			rowsKey = "Actual Rows"
+		}
+		plan.Nodes = append(plan.Nodes, ExplainNode{
+			ID:        id,
+			Table:     toString(node["Relation Name"]),
+			Operation: toString(node["Node Type"]),
+			Index:     toString(node["Index Name"]),
+			Rows:      toFloat(node[rowsKey]),
+			Cost:      toFloat(node["Total Cost"]),
+			Extra:     toString(node["Filter"]),
+			Raw:       node,
+		})
+		children, _ := node["Plans"].([]interface{})
+		for i, child := range children {
+			if childNode, ok := child.(map[string]interface{}); ok {
+				walk(childNode, fmt.Sprintf("%s.%d", id, i+1))
+			}
+		}
+	}
+	for i, document := range documents {
+		walk(document.Plan, strconv.Itoa(i+1))
+	}
+	return nil
+}
+
+// explainSQLite 解析 SQLite EXPLAIN QUERY PLAN 的结果
+func explainSQLite(ctx context.Context, runner sqlRunner, plan *ExplainPlan, sqlStr string, args []interface{}) error {
+	rows, err := fetchMaps(ctx, runner, sqlStr, args)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, row := range rows {
+		detail := toString(row["detail"])
+		lines = append(lines, detail)
+
+		// 格式如: SEARCH users USING INDEX idx_users_email (email=?) 或 SCAN users
+		node := ExplainNode{ID: toString(row["id"]), Extra: detail, Raw: row}
+		fields := strings.Fields(detail)
+		if len(fields) >= 2 {
+			node.Operation = fields[0]
+			node.Table = fields[1]
+			if node.Operation == "SCAN" && node.Table == "TABLE" && len(fields) >= 3 {
+				node.Table = fields[2]
+			}
+		}
+		for i, field := range fields {
+			if field == "INDEX" && i+1 < len(fields) {
+				node.Index = fields[i+1]
+				break
+			}
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+	plan.Text = strings.Join(lines, "\n")
+	return nil
+}
+
+// explainSQLServer 通过 SHOWPLAN_ALL 获取 SQLServer 执行计划
+func explainSQLServer(ctx context.Context, runner sqlRunner, plan *ExplainPlan, sqlStr string, args []interface{}) error {
+	if _, err := runner.ExecContext(ctx, "SET SHOWPLAN_ALL ON"); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = runner.ExecContext(ctx, "SET SHOWPLAN_ALL OFF")
+	}()
+
+	rows, err := fetchMaps(ctx, runner, sqlStr, args)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		node := ExplainNode{
+			ID:        toString(row["NodeId"]),
+			Operation: toString(row["PhysicalOp"]),
+			Rows:      toFloat(row["EstimateRows"]),
+			Cost:      toFloat(row["TotalSubtreeCost"]),
+			Extra:     toString(row["Argument"]),
+			Raw:       row,
+		}
+		// Argument 格式如: OBJECT:([db].[dbo].[users].[IX_users_email]), SEEK:(...)
+		if argument := node.Extra; strings.HasPrefix(argument, "OBJECT:(") {
+			end := strings.Index(argument, ")")
+			if end > 0 {
+				parts := strings.Split(strings.NewReplacer("[", "", "]", "").Replace(argument[len("OBJECT:("):end]), ".")
+				if len(parts) >= 3 {
+					node.Table = parts[2]
+				}
+				if len(parts) >= 4 {
+					node.Index = parts[3]
+				}
+			}
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+	return nil
+}
+
+// explainOracle 通过 EXPLAIN PLAN 与 PLAN_TABLE 获取 Oracle 执行计划
+func explainOracle(ctx context.Context, runner sqlRunner, plan *ExplainPlan, sqlStr string, _ []interface{}) error {
+	statementID := fmt.Sprintf("gosqlx_%d", time.Now().UnixNano())
+	explainSQL := fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%s' FOR %s", statementID, sqlStr)
+	// EXPLAIN PLAN 仅解析语句，绑定变量无需传值
+	if _, err := runner.ExecContext(ctx, explainSQL); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = runner.ExecContext(ctx, "DELETE FROM PLAN_TABLE WHERE STATEMENT_ID = :1", statementID)
+	}()
+
+	rows, err := fetchMaps(ctx, runner,
+		"SELECT ID, OPERATION, OPTIONS, OBJECT_NAME, OBJECT_TYPE, CARDINALITY, COST FROM PLAN_TABLE WHERE STATEMENT_ID = :1 ORDER BY ID",
+		[]interface{}{statementID})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		node := ExplainNode{
+			ID:        toString(row["ID"]),
+			Operation: strings.TrimSpace(toString(row["OPERATION"]) + " " + toString(row["OPTIONS"])),
+			Rows:      toFloat(row["CARDINALITY"]),
+			Cost:      toFloat(row["COST"]),
+			Raw:       row,
+		}
+		if strings.HasPrefix(toString(row["OBJECT_TYPE"]), "INDEX") {
+			node.Index = toString(row["OBJECT_NAME"])
+		} else {
+			node.Table = toString(row["OBJECT_NAME"])
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+	return nil
+}
+
+// toString 将扫描值转换为字符串
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// toFloat 将扫描值转换为浮点数
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(toString(v)), 64)
+		return f
+	}
+}
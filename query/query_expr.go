@@ -0,0 +1,59 @@
+package query
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gzorm/gosqlx/expr"
+)
+
+// SelectExpr 追加表达式作为查询列，标识符按当前方言加引号
+//
+//	q.Table("order_items").Select("order_id").
+//		SelectExpr(expr.Col("price").Mul(expr.Col("qty")).As("total"))
+func (q *Query) SelectExpr(exprs ...expr.Expr) *Query {
+	for _, e := range exprs {
+		sql, args, err := e.Build(q.detectDialect())
+		if err != nil {
+			q.setErr(err)
+			return q
+		}
+		q.columns = append(q.columns, sql)
+		q.selectArgs = append(q.selectArgs, args...)
+	}
+	return q
+}
+
+// WhereExpr 添加表达式条件
+//
+//	q.WhereExpr(expr.Or(expr.Col("status").In(1, 2), expr.Col("vip").Eq(true)))
+func (q *Query) WhereExpr(condition expr.Expr) *Query {
+	sql, args, err := condition.Build(q.detectDialect())
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.where.Where(sql, args...)
+	return q
+}
+
+// OrderByExpr 追加表达式排序，排序表达式不支持绑定参数
+//
+//	q.OrderByExpr(expr.Fn("COALESCE", expr.Col("updated_at"), expr.Col("created_at")).Desc())
+func (q *Query) OrderByExpr(exprs ...expr.Expr) *Query {
+	orders := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		sql, args, err := e.Build(q.detectDialect())
+		if err != nil {
+			q.setErr(err)
+			return q
+		}
+		if len(args) > 0 {
+			q.setErr(errors.New("排序表达式不支持绑定参数"))
+			return q
+		}
+		orders = append(orders, sql)
+	}
+	q.order.AppendOrderBy(strings.Join(orders, ", "))
+	return q
+}
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gzorm/gosqlx/expr"
+)
+
+// 测试表达式查询列、条件和排序
+func TestQueryExpr(t *testing.T) {
+	sqlStr, args, err := NewQuery(nil).Dialect("mysql").Table("order_items").
+		Select("order_id").
+		SelectExpr(expr.Col("price").Mul(expr.Col("qty")).As("total"), expr.Fn("COALESCE", expr.Col("note"), "").As("note")).
+		Where("order_id > ?", 10).
+		WhereExpr(expr.Or(expr.Col("status").In(1, 2), expr.Col("qty").Gte(5))).
+		OrderByExpr(expr.Col("total").Desc(), expr.Col("order_id").Asc()).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	want := "SELECT order_id, (`price` * `qty`) AS `total`, COALESCE(`note`, ?) AS `note` FROM order_items WHERE order_id > ? AND ((`status` IN (?, ?)) OR (`qty` >= ?)) ORDER BY `total` DESC, `order_id` ASC"
+	if sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q", want, sqlStr)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"", 10, 1, 2, 5}) {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, _, err := NewQuery(nil).Dialect("mysql").Table("t").SelectExpr(expr.Col("a b")).ToSQL(); !errors.Is(err, expr.ErrInvalidExpr) {
+		t.Errorf("不合法的列名 error = %v", err)
+	}
+	if _, _, err := NewQuery(nil).Dialect("mysql").Table("t").OrderByExpr(expr.Col("a").Eq(1)).ToSQL(); err == nil {
+		t.Error("带参数的排序表达式应返回错误")
+	}
+}
@@ -0,0 +1,24 @@
+package query
+
+import "github.com/gzorm/gosqlx/builder"
+
+// WhereWithinRadius 添加距离条件: 空间列与坐标 (lat, lng) 的球面距离不超过 meters 米
+func (q *Query) WhereWithinRadius(column string, lat, lng, meters float64) *Query {
+	q.where.Dialect(q.detectDialect()).WhereWithinRadius(column, lat, lng, meters)
+	return q
+}
+
+// SelectDistance 追加空间列到坐标 (lat, lng) 的球面距离（米）作为查询列，可配合 OrderByAsc(alias) 由近及远排序
+//
+//	q.Table("stores").Select("id", "name").SelectDistance("location", lat, lng, "distance").
+//		WhereWithinRadius("location", lat, lng, 3000).OrderByAsc("distance")
+func (q *Query) SelectDistance(column string, lat, lng float64, alias string) *Query {
+	expr, args, err := builder.DistanceSQL(q.detectDialect(), column, lat, lng)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.columns = append(q.columns, expr+" AS "+alias)
+	q.selectArgs = append(q.selectArgs, args...)
+	return q
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ==================== 按列索引的结果 ====================
+
+// GetMap 执行查询，以 keyColumn 列的值为键将结果写入 dest，规则见 ScanIndexed
+//
+//	users := make(map[int64]User)
+//	err := query.NewQuery(db).Table("users").Where("status = ?", 1).GetMap(users, "id")
+//
+//	var byDept map[string][]User
+//	err = query.NewQuery(db).Table("users").GetMap(&byDept, "dept")
+func (q *Query) GetMap(dest interface{}, keyColumn string) error {
+	if err := q.Err(); err != nil {
+		return err
+	}
+	ctx, cancel := q.context()
+	defer cancel()
+	sqlStr, args := q.BuildSelect()
+	rows, err := q.queryContext(ctx, sqlStr, args)
+	if err != nil {
+		return TimeoutError(ctx, err)
+	}
+	defer rows.Close()
+	return TimeoutError(ctx, ScanIndexed(rows, dest, keyColumn))
+}
+
+// ScanIndexed 以 keyColumn 列（不区分大小写）的值为键，将结果集的所有行写入 dest
+// dest 为 map[K]T、map[K]*T 或分组的 map[K][]T，也可以是这些 map 的指针，指向的 map 为 nil 时创建；
+// T 为结构体时按列名扫描，键列同时写入对应字段，T 为基本类型时结果集须只有键列和值列两列。
+// 非分组的 map 中键重复时保留最后一行，分组的 map 按结果集顺序追加
+func ScanIndexed(rows *sql.Rows, dest interface{}, keyColumn string) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() == reflect.Ptr && destValue.Elem().Kind() == reflect.Map {
+		destValue = destValue.Elem()
+		if destValue.IsNil() {
+			destValue.Set(reflect.MakeMap(destValue.Type()))
+		}
+	}
+	if destValue.Kind() != reflect.Map {
+		return errors.New("结果参数必须是 map 或 map 指针")
+	}
+	if destValue.IsNil() {
+		return errors.New("结果 map 不能为 nil，请传入 map 指针")
+	}
+
+	mapType := destValue.Type()
+	elemType := mapType.Elem()
+	grouped := elemType.Kind() == reflect.Slice && elemType.Elem().Kind() != reflect.Uint8
+	valueType := elemType
+	if grouped {
+		valueType = elemType.Elem()
+	}
+	rowType := valueType
+	if rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	keyIndex, valueIndex := -1, -1
+	for i, column := range columns {
+		if strings.EqualFold(column, keyColumn) {
+			keyIndex = i
+		} else {
+			valueIndex = i
+		}
+	}
+	if keyIndex < 0 {
+		return fmt.Errorf("结果集中没有键列 %s", keyColumn)
+	}
+	structRow := rowType.Kind() == reflect.Struct && rowType != timeType
+	if !structRow && len(columns) != 2 {
+		return fmt.Errorf("值类型 %s 不是结构体，结果集须只有键列和值列，实际有 %d 列", rowType, len(columns))
+	}
+
+	scanner := newRowScanner(columns, rowType)
+	for rows.Next() {
+		value := reflect.New(valueType).Elem()
+		target := value
+		if valueType.Kind() == reflect.Ptr {
+			value.Set(reflect.New(rowType))
+			target = value.Elem()
+		}
+		if structRow {
+			err = scanner.scan(rows, target)
+		} else if err = rows.Scan(scanner.targets...); err == nil {
+			err = setFieldValue(target, scanner.values[valueIndex])
+		}
+		if err != nil {
+			return err
+		}
+
+		key := reflect.New(mapType.Key()).Elem()
+		if err := setFieldValue(key, scanner.values[keyIndex]); err != nil {
+			return fmt.Errorf("转换键列 %s 失败: %w", keyColumn, err)
+		}
+		if grouped {
+			group := destValue.MapIndex(key)
+			if !group.IsValid() {
+				group = reflect.MakeSlice(elemType, 0, 1)
+			}
+			value = reflect.Append(group, value)
+		}
+		destValue.SetMapIndex(key, value)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"testing"
+)
+
+// 测试按键列将结果写入 map
+func TestGetMap(t *testing.T) {
+	db := openScanDB(t, 5)
+
+	users := make(map[int64]scanUser)
+	if err := NewQuery(db).Table("users").Where("id <= ?", 3).GetMap(users, "ID"); err != nil {
+		t.Fatalf("GetMap 失败: %v", err)
+	}
+	if len(users) != 3 || users[2].Name != "user2" || users[2].ID != 2 || users[2].Note == nil {
+		t.Errorf("users = %+v", users)
+	}
+
+	// 分组的指针 map，nil map 自动创建
+	var byLevel map[int][]*scanUser
+	if err := NewQuery(db).Table("users").OrderByAsc("id").GetMap(&byLevel, "level"); err != nil {
+		t.Fatalf("分组 GetMap 失败: %v", err)
+	}
+	if len(byLevel) != 5 || len(byLevel[1]) != 1 || byLevel[1][0].Name != "user1" {
+		t.Errorf("byLevel = %+v", byLevel)
+	}
+	var byActive map[bool][]scanUser
+	if err := NewQuery(db).Table("users").OrderByAsc("id").GetMap(&byActive, "active"); err != nil {
+		t.Fatalf("分组 GetMap 失败: %v", err)
+	}
+	if len(byActive[true]) != 3 || byActive[false][1].ID != 4 {
+		t.Errorf("byActive = %+v", byActive)
+	}
+
+	// 基本类型的值取另一列
+	var names map[string]float64
+	if err := NewQuery(db).Table("users").Select("user_name", "score").GetMap(&names, "user_name"); err != nil {
+		t.Fatalf("基本类型 GetMap 失败: %v", err)
+	}
+	if len(names) != 5 || names["user4"] != 4.5 {
+		t.Errorf("names = %v", names)
+	}
+
+	if err := NewQuery(db).Table("users").GetMap(&names, "missing"); err == nil {
+		t.Error("缺少键列时应返回错误")
+	}
+	if err := NewQuery(db).Table("users").GetMap(&names, "user_name"); err == nil {
+		t.Error("基本类型的值多于两列时应返回错误")
+	}
+	var nilMap map[int64]scanUser
+	if err := NewQuery(db).Table("users").GetMap(nilMap, "id"); err == nil {
+		t.Error("nil map 应返回错误")
+	}
+}
@@ -0,0 +1,285 @@
+package query
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gzorm/gosqlx/builder"
+)
+
+// ToJSON 执行查询并由数据库将结果序列化为 JSON 数组，每行一个对象，键为结果列名；没有结果时返回 []
+// PostgreSQL 使用 row_to_json，SQLServer 使用 FOR JSON PATH，MySQL 系和 SQLite 使用 JSON_OBJECT（先查询一次结果列名）；
+// 适合接口直接输出查询结果、省去扫描和序列化的场景
+//
+//	data, err := q.Table("users").Select("id", "name").Where("status = ?", 1).ToJSON()
+func (q *Query) ToJSON() ([]byte, error) {
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := q.context()
+	defer cancel()
+
+	inner, args := q.BuildSelect()
+	var (
+		sqlStr   string
+		fragment bool // 每行是完整 JSON 的一段（FOR JSON 的结果按长度拆成多行）
+	)
+	switch dialect := q.detectDialect(); dialect {
+	case "postgres", "postgresql":
+		sqlStr = "SELECT row_to_json(t) FROM (" + inner + ") AS t"
+	case "sqlserver", "mssql":
+		sqlStr, fragment = inner+" FOR JSON PATH, INCLUDE_NULL_VALUES", true
+	case "mysql", "mariadb", "tidb", "oceanbase", "sqlite", "sqlite3":
+		columns, err := q.resultColumns(inner, args)
+		if err != nil {
+			return nil, err
+		}
+		quote := func(name string) string { return "`" + strings.ReplaceAll(name, "`", "``") + "`" }
+		if strings.HasPrefix(dialect, "sqlite") {
+			quote = func(name string) string { return `"` + strings.ReplaceAll(name, `"`, `""`) + `"` }
+		}
+		pairs := make([]string, len(columns))
+		keys := make([]interface{}, len(columns))
+		for i, column := range columns {
+			pairs[i] = "?, t." + quote(column)
+			keys[i] = column
+		}
+		sqlStr = "SELECT JSON_OBJECT(" + strings.Join(pairs, ", ") + ") FROM (" + inner + ") AS t"
+		args = append(keys, args...)
+	default:
+		return nil, fmt.Errorf("%s 不支持 ToJSON", dialect)
+	}
+
+	rows, err := q.queryContext(ctx, sqlStr, args)
+	if err != nil {
+		return nil, TimeoutError(ctx, err)
+	}
+	defer rows.Close()
+
+	var buf bytes.Buffer
+	if !fragment {
+		buf.WriteByte('[')
+	}
+	for n := 0; rows.Next(); n++ {
+		var value sql.NullString
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		if !fragment && n > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(value.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, TimeoutError(ctx, err)
+	}
+	if !fragment {
+		buf.WriteByte(']')
+	} else if buf.Len() == 0 {
+		buf.WriteString("[]")
+	}
+	return buf.Bytes(), nil
+}
+
+// resultColumns 查询语句的结果列名，不读取数据
+func (q *Query) resultColumns(inner string, args []interface{}) ([]string, error) {
+	ctx, cancel := q.context()
+	defer cancel()
+	rows, err := q.queryContext(ctx, "SELECT * FROM ("+inner+") AS t WHERE 1 = 0", args)
+	if err != nil {
+		return nil, TimeoutError(ctx, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("查询没有结果列")
+	}
+	return columns, nil
+}
+
+// ==================== JSON 聚合 ====================
+
+// SelectJSONAgg 追加子查询 sub 的所有行聚合成的 JSON 对象数组作为查询列 alias，没有行时为 []；
+// sub 通常是引用外层表别名的关联子查询，一条查询即可返回父记录及其子记录，避免 N+1 查询。
+// 结果可扫描到切片或结构体字段，对象的键按 db 标签、字段名或 json 标签匹配字段；
+// MySQL 系和 SQLite 需要 sub 显式列出结果列（列名或 AS 别名），子查询中的 ORDER BY 决定数组的顺序
+//
+//	items := query.NewQuery(db).Table("order_items AS i").Select("i.sku", "i.qty").Where("i.order_id = o.id").OrderBy("i.id")
+//	var orders []struct {
+//		ID    int64      `db:"id"`
+//		Items []LineItem `db:"items"`
+//	}
+//	err := query.NewQuery(db).Table("orders").Alias("o").Select("o.id").SelectJSONAgg(items, "items").Get(&orders)
+func (q *Query) SelectJSONAgg(sub *Query, alias string) *Query {
+	return q.selectJSON(sub, alias, builder.JSONAggSQL)
+}
+
+// SelectJSONObject 追加子查询 sub 的第一行转换成的 JSON 对象作为查询列 alias，没有行时为 NULL，
+// 用于多对一或一对一的关联，结果可扫描到结构体或结构体指针字段；规则同 SelectJSONAgg
+func (q *Query) SelectJSONObject(sub *Query, alias string) *Query {
+	return q.selectJSON(sub, alias, builder.JSONObjectSQL)
+}
+
+// selectJSON 构建子查询并按方言生成 JSON 表达式
+func (q *Query) selectJSON(sub *Query, alias string, build func(dialect, sub string, columns []builder.JSONColumn) (string, error)) *Query {
+	if err := sub.Err(); err != nil {
+		q.setErr(err)
+		return q
+	}
+	subSQL, args := sub.BuildSelect()
+	expr, err := build(q.detectDialect(), subSQL, sub.jsonResultColumns())
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.columns = append(q.columns, expr+" AS "+alias)
+	q.selectArgs = append(q.selectArgs, args...)
+	if q.jsonColumns == nil {
+		q.jsonColumns = make(map[string]bool)
+	}
+	q.jsonColumns[alias] = true
+	return q
+}
+
+// jsonResultColumns 由查询列推断结果列名，有 * 或没有别名的表达式时返回 nil
+func (q *Query) jsonResultColumns() []builder.JSONColumn {
+	columns := make([]builder.JSONColumn, 0, len(q.columns))
+	for _, column := range q.columns {
+		column = strings.TrimSpace(column)
+		if i := strings.LastIndex(strings.ToUpper(column), " AS "); i >= 0 {
+			column = strings.TrimSpace(column[i+4:])
+		} else if strings.ContainsAny(column, "*() +-,") {
+			return nil
+		} else if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+		key := strings.Trim(column, "`\"[]")
+		columns = append(columns, builder.JSONColumn{Key: key, JSON: q.jsonColumns[key]})
+	}
+	return columns
+}
+
+// setJSONValue 将 JSON 文本（如 SelectJSONAgg 的结果）解码到切片、map 或结构体字段
+// 对象的键按 db 标签、字段名或 json 标签匹配字段，取值按 setFieldValue 的规则转换，
+// 因此数据库输出的各种时间文本、0/1 布尔值都可以写入对应类型的字段
+func setJSONValue(field reflect.Value, value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("无法将 %T 转换为 %s", value, field.Type())
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return fmt.Errorf("解析 %s 的 JSON 失败: %w", field.Type(), err)
+	}
+	return assignJSON(field, decoded)
+}
+
+// assignJSON 将解码后的 JSON 值写入字段
+func assignJSON(field reflect.Value, value interface{}) error {
+	if !field.CanSet() {
+		return nil
+	}
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			value = i
+		} else if value, err = n.Float64(); err != nil {
+			return err
+		}
+	}
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			return scanner.Scan(data)
+		}
+		return scanner.Scan(value)
+	}
+
+	switch field.Kind() {
+	case reflect.Ptr:
+		field.Set(reflect.New(field.Type().Elem()))
+		return assignJSON(field.Elem(), value)
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := assignJSON(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok || field.Type().Key().Kind() != reflect.String {
+			break
+		}
+		m := reflect.MakeMapWithSize(field.Type(), len(object))
+		for key, item := range object {
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := assignJSON(elem, item); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(field.Type().Key()), elem)
+		}
+		field.Set(m)
+		return nil
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok || field.Type() == timeType {
+			break
+		}
+		for key, item := range object {
+			index := jsonFieldIndex(field.Type(), key)
+			if index == nil {
+				continue
+			}
+			if err := assignJSON(field.FieldByIndex(index), item); err != nil {
+				return fmt.Errorf("转换字段 %s 失败: %w", key, err)
+			}
+		}
+		return nil
+	case reflect.Interface:
+		field.Set(reflect.ValueOf(value))
+		return nil
+	}
+	return setFieldValue(field, value)
+}
+
+// jsonFieldIndex 按 db 标签、字段名或 json 标签查找 JSON 对象的键对应的字段
+func jsonFieldIndex(typ reflect.Type, key string) []int {
+	if index := fieldIndex(typ, key); index != nil {
+		return index
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ","); name == key {
+			return []int{i}
+		}
+	}
+	return nil
+}
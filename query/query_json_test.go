@@ -0,0 +1,118 @@
+package query
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jsonOrder 订单及聚合的明细
+type jsonOrder struct {
+	ID     int64          `db:"id"`
+	Amount float64        `db:"amount"`
+	Paid   bool           `db:"paid"`
+	At     time.Time      `db:"created_at"`
+	Note   sql.NullString `db:"note"`
+	Items  []jsonItem     `db:"items"`
+}
+
+// jsonItem 订单明细，键按 json 标签匹配
+type jsonItem struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+// 测试各方言的 JSON 聚合语句
+func TestSelectJSONAggSQL(t *testing.T) {
+	sub := func() *Query {
+		return NewQuery(nil).Table("orders").Select("orders.id", "amount AS total").Where("orders.user_id = u.id").OrderBy("orders.id")
+	}
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{"postgres", "COALESCE((SELECT json_agg(t) FROM (SELECT orders.id, amount AS total FROM orders WHERE orders.user_id = u.id AND orders.status = ? ORDER BY orders.id) AS t), '[]'::json) AS orders"},
+		{"mysql", "(SELECT COALESCE(JSON_ARRAYAGG(JSON_OBJECT('id', t.`id`, 'total', t.`total`)), JSON_ARRAY()) FROM (SELECT orders.id, amount AS total FROM orders WHERE orders.user_id = u.id AND orders.status = ? ORDER BY orders.id) AS t) AS orders"},
+		{"sqlserver", "JSON_QUERY(COALESCE((SELECT orders.id, amount AS total FROM orders WHERE orders.user_id = u.id AND orders.status = ? ORDER BY orders.id FOR JSON PATH, INCLUDE_NULL_VALUES), '[]')) AS orders"},
+	}
+	for _, tt := range tests {
+		q := NewQuery(nil).Dialect(tt.dialect).Table("users").Alias("u").Select("u.id").SelectJSONAgg(sub().Where("orders.status = ?", 1), "orders")
+		sqlStr, args, err := q.ToSQL()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dialect, err)
+		}
+		if want := "SELECT u.id, " + tt.want + " FROM users AS u"; sqlStr != want {
+			t.Errorf("%s:\n得到 %s\n期望 %s", tt.dialect, sqlStr, want)
+		}
+		if len(args) != 1 || args[0] != 1 {
+			t.Errorf("%s: 参数 = %v", tt.dialect, args)
+		}
+	}
+
+	q := NewQuery(nil).Dialect("mysql").Table("users").SelectJSONAgg(NewQuery(nil).Table("orders"), "orders")
+	if q.Err() == nil {
+		t.Error("MySQL 子查询为 * 时期望返回错误")
+	}
+	q = NewQuery(nil).Dialect("oracle").Table("users").SelectJSONObject(sub(), "latest")
+	if q.Err() == nil {
+		t.Error("不支持的方言期望返回错误")
+	}
+}
+
+// 测试在 SQLite 上一次查询父记录和嵌套聚合的子记录并扫描到嵌套结构体
+func TestSelectJSONAgg(t *testing.T) {
+	db := openScanDB(t, 3)
+	_, err := db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER, amount REAL, paid BOOLEAN, created_at DATETIME, note TEXT);
+		CREATE TABLE items (id INTEGER PRIMARY KEY, order_id INTEGER, sku TEXT, qty INTEGER);
+		INSERT INTO orders VALUES (1, 1, 9.5, 1, '2024-05-01 08:30:00', 'gift'), (2, 1, 20, 0, '2024-05-02 09:00:00', NULL), (3, 2, 5, 1, '2024-05-03 10:00:00', NULL);
+		INSERT INTO items VALUES (1, 1, 'A-1', 2), (2, 1, 'B-2', 1), (3, 3, 'C-3', 5);`)
+	if err != nil {
+		t.Fatalf("准备测试数据失败: %v", err)
+	}
+
+	items := NewQuery(db).Table("items").Alias("i").Select("i.sku", "i.qty").Where("i.order_id = o.id").OrderByDesc("i.id")
+	orders := NewQuery(db).Table("orders").Alias("o").
+		Select("o.id", "o.amount", "o.paid", "o.created_at", "o.note").
+		SelectJSONAgg(items, "items").
+		Where("o.user_id = u.id").OrderByAsc("o.id")
+	var users []struct {
+		ID       int64       `db:"id"`
+		UserName string      `db:"user_name"`
+		Orders   []jsonOrder `db:"orders"`
+		Latest   *jsonOrder  `db:"latest"`
+	}
+	latest := NewQuery(db).Table("orders").Alias("o").Select("o.id", "o.amount").Where("o.user_id = u.id AND o.amount > ?", 1).OrderByDesc("o.id")
+	err = NewQuery(db).Table("users").Alias("u").Select("u.id", "u.user_name").
+		SelectJSONAgg(orders, "orders").
+		SelectJSONObject(latest, "latest").
+		OrderByAsc("u.id").Get(&users)
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("用户 = %d 个，期望 3", len(users))
+	}
+
+	first := users[0]
+	if len(first.Orders) != 2 || first.Latest == nil || first.Latest.ID != 2 {
+		t.Fatalf("用户 1 = %+v", first)
+	}
+	order := first.Orders[0]
+	if order.ID != 1 || order.Amount != 9.5 || !order.Paid || order.Note.String != "gift" ||
+		!order.At.Equal(time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("订单 1 = %+v", order)
+	}
+	if len(order.Items) != 2 || order.Items[0].SKU != "B-2" || order.Items[1].Qty != 2 {
+		t.Errorf("订单 1 的明细 = %+v，期望按 id 倒序", order.Items)
+	}
+	if first.Orders[1].Note.Valid || first.Orders[1].Items == nil || len(first.Orders[1].Items) != 0 {
+		t.Errorf("订单 2 = %+v，期望空明细和 NULL 备注", first.Orders[1])
+	}
+	if last := users[2]; last.Orders == nil || len(last.Orders) != 0 || last.Latest != nil {
+		t.Errorf("没有订单的用户 = %+v，期望空数组和 nil", last)
+	}
+	if !strings.HasPrefix(users[1].UserName, "user") || len(users[1].Orders[0].Items) != 1 {
+		t.Errorf("用户 2 = %+v", users[1])
+	}
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/gzorm/gosqlx/adapter"
+)
+
+// SkipLocked 跳过已被其他事务锁定的行，未设置锁类型时默认使用行锁
+// 适用于队列式消费（Postgres 9.5+、MySQL 8.0+、Oracle、SQLServer READPAST）
+func (q *Query) SkipLocked() *Query {
+	q.skipLocked = true
+	q.noWait = false
+	if !q.forShare {
+		q.forUpdate = true
+	}
+	return q
+}
+
+// NoWait 行已被锁定时立即返回错误而不等待，未设置锁类型时默认使用行锁
+func (q *Query) NoWait() *Query {
+	q.noWait = true
+	q.skipLocked = false
+	if !q.forShare {
+		q.forUpdate = true
+	}
+	return q
+}
+
+// Adapter 设置生成锁语句使用的适配器，未设置时根据数据库方言选择
+func (q *Query) Adapter(a adapter.Adapter) *Query {
+	q.adapter = a
+	return q
+}
+
+// lockingAdapter 获取提供锁语法的适配器
+func (q *Query) lockingAdapter() adapter.Adapter {
+	if q.adapter != nil {
+		return q.adapter
+	}
+	switch q.detectDialect() {
+	case "mysql":
+		return &adapter.MySQL{}
+	case "mariadb":
+		return &adapter.MariaDB{}
+	case "tidb":
+		return &adapter.TiDB{}
+	case "oceanbase":
+		return &adapter.OceanBase{}
+	case "postgres":
+		return &adapter.Postgres{}
+	case "sqlserver":
+		return &adapter.SQLServer{}
+	case "oracle":
+		return &adapter.Oracle{}
+	case "sqlite", "sqlite3":
+		return &adapter.SQLite{}
+	case "clickhouse":
+		return &adapter.ClickHouse{}
+	case "duckdb":
+		return &adapter.DuckDB{}
+	}
+	return nil
+}
+
+// lockClause 生成锁语句，返回表提示（SQLServer，位于表名之后）和语句末尾的锁子句
+func (q *Query) lockClause() (hint string, suffix string) {
+	if !q.forUpdate && !q.forShare {
+		return "", ""
+	}
+
+	var lock string
+	if a := q.lockingAdapter(); a != nil {
+		if q.forUpdate {
+			lock = a.ForUpdate()
+		} else {
+			lock = a.ForShare()
+		}
+	} else if q.forUpdate {
+		lock = "FOR UPDATE"
+	} else {
+		lock = "FOR SHARE"
+	}
+	// SQLite/ClickHouse 等不支持行锁
+	if lock == "" {
+		return "", ""
+	}
+
+	// SQLServer 使用表提示
+	if strings.HasPrefix(lock, "WITH (") {
+		switch {
+		case q.skipLocked:
+			lock = strings.TrimSuffix(lock, ")") + ", READPAST)"
+		case q.noWait:
+			lock = strings.TrimSuffix(lock, ")") + ", NOWAIT)"
+		}
+		return lock, ""
+	}
+
+	modifier := ""
+	switch {
+	case q.skipLocked:
+		modifier = "SKIP LOCKED"
+	case q.noWait:
+		modifier = "NOWAIT"
+	}
+	if modifier == "" {
+		return "", lock
+	}
+	// MySQL 的 LOCK IN SHARE MODE 不支持修饰符，改用 8.0 的 FOR SHARE
+	if lock == "LOCK IN SHARE MODE" {
+		lock = "FOR SHARE"
+	}
+	// Oracle 共享锁语法已带 NOWAIT
+	lock = strings.TrimSuffix(lock, " NOWAIT")
+	return "", lock + " " + modifier
+}
@@ -0,0 +1,30 @@
+package query
+
+import "testing"
+
+// 测试各数据库的锁语句
+func TestLockClause(t *testing.T) {
+	cases := []struct {
+		dialect string
+		build   func(q *Query) *Query
+		want    string
+	}{
+		{"postgres", func(q *Query) *Query { return q.ForUpdate() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1 FOR UPDATE"},
+		{"postgres", func(q *Query) *Query { return q.SkipLocked() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1 FOR UPDATE SKIP LOCKED"},
+		{"postgres", func(q *Query) *Query { return q.ForShare().NoWait() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1 FOR SHARE NOWAIT"},
+		{"mysql", func(q *Query) *Query { return q.ForShare() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1 LOCK IN SHARE MODE"},
+		{"mysql", func(q *Query) *Query { return q.ForShare().SkipLocked() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1 FOR SHARE SKIP LOCKED"},
+		{"oracle", func(q *Query) *Query { return q.ForShare().SkipLocked() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1 FOR UPDATE SKIP LOCKED"},
+		{"sqlserver", func(q *Query) *Query { return q.SkipLocked() }, "SELECT * FROM jobs WITH (UPDLOCK, ROWLOCK, READPAST) WHERE status = ? LIMIT 1"},
+		{"sqlite", func(q *Query) *Query { return q.ForUpdate().NoWait() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1"},
+		{"clickhouse", func(q *Query) *Query { return q.ForUpdate() }, "SELECT * FROM jobs WHERE status = ? LIMIT 1"},
+	}
+
+	for _, c := range cases {
+		q := NewQuery(nil).Dialect(c.dialect).Table("jobs").Where("status = ?", "ready").Limit(1)
+		sqlStr, _ := c.build(q).BuildSelect()
+		if sqlStr != c.want {
+			t.Errorf("%s: 期望 %q，实际为 %q", c.dialect, c.want, sqlStr)
+		}
+	}
+}
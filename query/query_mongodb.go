@@ -1,8 +1,11 @@
 package query
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -218,14 +221,18 @@ func (q *Query) Skip(n int) *Query {
 // Facet 实现 MongoDB 的 $facet 操作（多管道处理）
 func (q *Query) Facet(facets map[string][]string) *Query {
 	// 构建 facet 对象
+	names := make([]string, 0, len(facets))
+	for k := range facets {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
 	facetObj := "{"
-	i := 0
-	for k, v := range facets {
+	for i, k := range names {
 		if i > 0 {
 			facetObj += ", "
 		}
-		facetObj += fmt.Sprintf(`"%s": [%s]`, k, join(v, ", "))
-		i++
+		facetObj += fmt.Sprintf(`"%s": [%s]`, k, join(facets[k], ", "))
 	}
 	facetObj += "}"
 
@@ -252,6 +259,11 @@ func (q *Query) BuildAggregate() (string, []interface{}) {
 	var pipeline []string
 	var args []interface{}
 
+	// $geoNear 和 $text 匹配必须位于第一个阶段
+	if q.mongo.first != "" {
+		pipeline = append(pipeline, q.mongo.first)
+	}
+
 	// 添加 $match 阶段（如果有 where 条件）
 	if q.where != nil {
 		whereStr, whereArgs := q.where.Build()
@@ -300,31 +312,41 @@ func (q *Query) BuildAggregate() (string, []interface{}) {
 		}
 	}
 
-	// 添加 $skip 阶段（如果有偏移量）
+	// 添加 $skip 和 $limit 阶段（如果有偏移量和限制数），$facet 分页时放在 $facet 中
+	var page []string
 	if q.offset > 0 {
-		skipStage := fmt.Sprintf(`{"$skip": %d}`, q.offset)
-		pipeline = append(pipeline, skipStage)
+		page = append(page, fmt.Sprintf(`{"$skip": %d}`, q.offset))
 	}
-
-	// 添加 $limit 阶段（如果有限制数）
 	if q.limit > 0 {
-		limitStage := fmt.Sprintf(`{"$limit": %d}`, q.limit)
-		pipeline = append(pipeline, limitStage)
+		page = append(page, fmt.Sprintf(`{"$limit": %d}`, q.limit))
+	}
+	if !q.mongo.facetPage {
+		pipeline = append(pipeline, page...)
 	}
 
-	// 添加 $project 阶段（如果有指定列）
+	// 添加 $project 阶段（如果有指定列），只有投影操作符时使用 $addFields 保留其余字段
+	projections := make([]string, 0, len(q.mongo.projections))
+	projected := make(map[string]bool, len(q.mongo.projections))
+	for _, projection := range q.mongo.projections {
+		projections = append(projections, projection.expr)
+		projected[projection.field] = true
+	}
 	if len(q.columns) > 0 && q.columns[0] != "*" {
-		projectObj := "{"
-		for i, column := range q.columns {
-			if i > 0 {
-				projectObj += ", "
+		var fields []string
+		for _, column := range q.columns {
+			if !projected[column] {
+				fields = append(fields, fmt.Sprintf(`"%s": 1`, column))
 			}
-			projectObj += fmt.Sprintf(`"%s": 1`, column)
 		}
-		projectObj += "}"
-
-		projectStage := fmt.Sprintf(`{"$project": %s}`, projectObj)
+		projectStage := fmt.Sprintf(`{"$project": {%s}}`, join(append(fields, projections...), ", "))
 		pipeline = append(pipeline, projectStage)
+	} else if len(projections) > 0 {
+		pipeline = append(pipeline, fmt.Sprintf(`{"$addFields": {%s}}`, join(projections, ", ")))
+	}
+
+	// 添加 $facet 分页阶段
+	if q.mongo.facetPage {
+		pipeline = append(pipeline, fmt.Sprintf(`{"$facet": {"data": [%s], "total": [{"$count": "count"}]}}`, join(page, ", ")))
 	}
 
 	// 构建最终的聚合管道查询
@@ -332,3 +354,125 @@ func (q *Query) BuildAggregate() (string, []interface{}) {
 
 	return query, args
 }
+
+// mongoOptions MongoDB 聚合管道的附加阶段
+type mongoOptions struct {
+	first       string            // 必须位于管道第一个阶段的 $geoNear 或 $text 匹配
+	projections []mongoProjection // 投影操作符
+	facetPage   bool              // 分页数据和总数在同一个 $facet 阶段返回
+}
+
+// mongoProjection 字段的投影操作符
+type mongoProjection struct {
+	field string
+	expr  string // 形如 "字段": 表达式
+}
+
+// mongoJSON 将值编码为 JSON，失败时记录错误
+func (q *Query) mongoJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		q.setErr(fmt.Errorf("编码 MongoDB 管道参数失败: %w", err))
+		return "null"
+	}
+	return string(data)
+}
+
+// setFirstStage 设置管道的第一个阶段，$text 和 $geoNear 都要求位于第一个阶段，不能同时使用
+func (q *Query) setFirstStage(stage string) {
+	if q.mongo.first != "" {
+		q.setErr(errors.New("$text 和 $geoNear 都必须是聚合管道的第一个阶段，不能同时使用"))
+		return
+	}
+	q.mongo.first = stage
+}
+
+// TextSearch 实现 MongoDB 的 $text 全文搜索，集合需要有文本索引
+// search: 搜索词，支持 "短语" 和 -排除词 语法
+func (q *Query) TextSearch(search string) *Query {
+	q.setFirstStage(fmt.Sprintf(`{"$match": {"$text": {"$search": %s}}}`, q.mongoJSON(search)))
+	return q
+}
+
+// SortByTextScore 按全文搜索的相关度降序排序，并将相关度写入 field 字段，需配合 TextSearch 使用
+func (q *Query) SortByTextScore(field string) *Query {
+	name := q.mongoJSON(field)
+	q.joins = append(q.joins,
+		fmt.Sprintf(`{"$addFields": {%s: {"$meta": "textScore"}}}`, name),
+		fmt.Sprintf(`{"$sort": {%s: {"$meta": "textScore"}}}`, name))
+	return q
+}
+
+// GeoNear 实现 MongoDB 的 $geoNear 操作，按到坐标 (lat, lng) 的球面距离由近及远返回文档
+// field: 带 2dsphere 索引的 GeoJSON 字段
+// maxMeters: 最大距离（米），小于等于 0 时不限制
+// distanceField: 距离（米）写入的字段
+func (q *Query) GeoNear(field string, lat, lng, maxMeters float64, distanceField string) *Query {
+	stage := fmt.Sprintf(`{"$geoNear": {"near": {"type": "Point", "coordinates": [%s, %s]}, "key": %s, "distanceField": %s, "spherical": true`,
+		q.mongoJSON(lng), q.mongoJSON(lat), q.mongoJSON(field), q.mongoJSON(distanceField))
+	if maxMeters > 0 {
+		stage += fmt.Sprintf(`, "maxDistance": %s`, q.mongoJSON(maxMeters))
+	}
+	q.setFirstStage(stage + "}}")
+	return q
+}
+
+// ProjectSlice 数组字段只返回部分元素，对应投影操作符 $slice
+// ProjectSlice("comments", 5) 返回前 5 个，ProjectSlice("comments", -5) 返回后 5 个，ProjectSlice("comments", 10, 5) 跳过 10 个后返回 5 个
+func (q *Query) ProjectSlice(field string, n int, limit ...int) *Query {
+	args := []interface{}{"$" + field, n}
+	if len(limit) > 0 {
+		args = append(args, limit[0])
+	}
+	q.mongo.projections = append(q.mongo.projections, mongoProjection{field, fmt.Sprintf(`%s: {"$slice": %s}`, q.mongoJSON(field), q.mongoJSON(args))})
+	return q
+}
+
+// ProjectElemMatch 数组字段只返回第一个满足条件的元素，对应投影操作符 $elemMatch
+// 聚合管道的 $project 不支持 $elemMatch，转换为 $filter 后取第一个元素；条件的值可以是比较操作符
+// 示例: ProjectElemMatch("items", map[string]interface{}{"sku": "A1", "qty": map[string]interface{}{"$gte": 2}})
+func (q *Query) ProjectElemMatch(field string, cond map[string]interface{}) *Query {
+	keys := make([]string, 0, len(cond))
+	for key := range cond {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var conditions []interface{}
+	for _, key := range keys {
+		path := "$$item." + key
+		operators, ok := cond[key].(map[string]interface{})
+		if !ok {
+			conditions = append(conditions, map[string]interface{}{"$eq": []interface{}{path, cond[key]}})
+			continue
+		}
+		names := make([]string, 0, len(operators))
+		for name := range operators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			switch name {
+			case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte", "$in":
+				conditions = append(conditions, map[string]interface{}{name: []interface{}{path, operators[name]}})
+			default:
+				q.setErr(fmt.Errorf("ProjectElemMatch 不支持操作符 %s", name))
+				return q
+			}
+		}
+	}
+
+	filter := map[string]interface{}{
+		"$filter": map[string]interface{}{"input": "$" + field, "as": "item", "cond": map[string]interface{}{"$and": conditions}},
+	}
+	q.mongo.projections = append(q.mongo.projections, mongoProjection{field, fmt.Sprintf(`%s: {"$slice": [%s, 1]}`, q.mongoJSON(field), q.mongoJSON(filter))})
+	return q
+}
+
+// FacetPage 分页查询，使用 $facet 在一次聚合中同时返回当前页数据和总数
+// 结果为单个文档：{"data": [...], "total": [{"count": N}]}，没有匹配文档时 total 为空数组
+func (q *Query) FacetPage(page, pageSize int) *Query {
+	q.Page(page, pageSize)
+	q.mongo.facetPage = true
+	return q
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+// 测试全文搜索、投影操作符和 $facet 分页
+func TestBuildAggregateStages(t *testing.T) {
+	q := NewQuery(nil).Table("posts").
+		Select("title", "comments").
+		TextSearch(`go "query builder"`).
+		SortByTextScore("score").
+		ProjectSlice("comments", 10, 5).
+		ProjectElemMatch("tags", map[string]interface{}{"name": "go", "weight": map[string]interface{}{"$gte": 2}}).
+		FacetPage(3, 20)
+	if err := q.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	pipeline, _ := q.BuildAggregate()
+	want := `db.posts.aggregate([` +
+		`{"$match": {"$text": {"$search": "go \"query builder\""}}}, ` +
+		`{"$addFields": {"score": {"$meta": "textScore"}}}, ` +
+		`{"$sort": {"score": {"$meta": "textScore"}}}, ` +
+		`{"$project": {"title": 1, "comments": {"$slice": ["$comments",10,5]}, ` +
+		`"tags": {"$slice": [{"$filter":{"as":"item","cond":{"$and":[{"$eq":["$$item.name","go"]},{"$gte":["$$item.weight",2]}]},"input":"$tags"}}, 1]}}}, ` +
+		`{"$facet": {"data": [{"$skip": 40}, {"$limit": 20}], "total": [{"$count": "count"}]}}])`
+	if pipeline != want {
+		t.Errorf("pipeline =\n%s\nwant\n%s", pipeline, want)
+	}
+}
+
+// 测试 $geoNear 和未指定列时的投影操作符
+func TestBuildAggregateGeoNear(t *testing.T) {
+	q := NewQuery(nil).Table("stores").GeoNear("location", 31.23, 121.47, 3000, "distance").ProjectSlice("photos", 1).Limit(10)
+	pipeline, _ := q.BuildAggregate()
+	want := `db.stores.aggregate([` +
+		`{"$geoNear": {"near": {"type": "Point", "coordinates": [121.47, 31.23]}, "key": "location", "distanceField": "distance", "spherical": true, "maxDistance": 3000}}, ` +
+		`{"$limit": 10}, ` +
+		`{"$addFields": {"photos": {"$slice": ["$photos",1]}}}])`
+	if pipeline != want {
+		t.Errorf("pipeline =\n%s\nwant\n%s", pipeline, want)
+	}
+
+	if err := NewQuery(nil).Table("stores").GeoNear("location", 0, 0, 0, "d").TextSearch("x").Err(); err == nil || !strings.Contains(err.Error(), "$geoNear") {
+		t.Errorf("同时使用 $text 和 $geoNear 应返回错误，得到 %v", err)
+	}
+	if err := NewQuery(nil).ProjectElemMatch("tags", map[string]interface{}{"n": map[string]interface{}{"$regex": "x"}}).Err(); err == nil {
+		t.Error("不支持的操作符应返回错误")
+	}
+}
@@ -0,0 +1,199 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ==================== 按请求字段投影 ====================
+
+// ErrUnknownField 请求的字段不在模型中或不可查询
+var ErrUnknownField = errors.New("请求的字段不存在")
+
+// projectFieldsCache 结构体类型到可投影字段的缓存
+var projectFieldsCache sync.Map
+
+// projectField 可按请求查询的字段
+type projectField struct {
+	name   string // Go 字段名
+	column string // db 标签的列名
+	json   string // JSON 名，没有 json 标签时为列名
+}
+
+// SelectFields 只查询调用方请求的字段（如接口的 fields 参数），减少宽表的查询和传输量
+// model 同 SelectStruct，可请求的字段为带 db 标签、未带 omit 选项且 json 标签不是 "-" 的字段；
+// 字段可以用 JSON 名、列名或 Go 字段名（不区分大小写）指定，单个元素中可用逗号分隔多个字段；
+// 有不存在的字段时查询返回 ErrUnknownField，没有请求字段时查询模型的所有字段。
+// 结果扫描到结构体时未请求的字段保持零值，GetFields 返回只含请求字段、以 JSON 名为键的 map
+//
+//	q.Table("users").SelectFields(&User{}, r.URL.Query().Get("fields")).Get(&users)
+//	rows, err := q.Table("users").SelectFields(&User{}, "id,name", "email").GetFields()
+func (q *Query) SelectFields(model interface{}, fields ...string) *Query {
+	return q.selectFields(model, fields, nil)
+}
+
+// SelectFieldsWith 同 SelectFields，always 中的字段（如主键）无论是否请求都查询
+//
+//	q.Table("users").SelectFieldsWith(&User{}, []string{r.URL.Query().Get("fields")}, "id").Get(&users)
+func (q *Query) SelectFieldsWith(model interface{}, fields []string, always ...string) *Query {
+	return q.selectFields(model, fields, always)
+}
+
+// selectFields 校验请求字段并设置查询列，记录列到 JSON 名的映射供 GetFields 使用
+func (q *Query) selectFields(model interface{}, fields, always []string) *Query {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		q.setErr(errors.New("SelectFields 的参数必须是结构体、结构体指针或结构体切片"))
+		return q
+	}
+	available := projectFields(t)
+	if len(available) == 0 {
+		q.setErr(errors.New("结构体没有可查询的 db 标签字段"))
+		return q
+	}
+
+	requested := splitFields(fields)
+	if len(requested) == 0 {
+		for _, field := range available {
+			requested = append(requested, field.column)
+		}
+	}
+	requested = append(splitFields(always), requested...)
+
+	var selected []projectField
+	for _, name := range requested {
+		field, ok := lookupProjectField(available, name)
+		if !ok {
+			q.setErr(fmt.Errorf("%w: %q", ErrUnknownField, name))
+			return q
+		}
+		duplicate := false
+		for _, s := range selected {
+			duplicate = duplicate || s.column == field.column
+		}
+		if !duplicate {
+			selected = append(selected, field)
+		}
+	}
+
+	columns := make([]string, len(selected))
+	q.projection = make(map[string]string, len(selected))
+	for i, field := range selected {
+		columns[i] = field.column
+		if q.alias != "" {
+			columns[i] = q.alias + "." + field.column
+		}
+		q.projection[strings.ToLower(field.column)] = field.json
+	}
+	return q.Select(columns...)
+}
+
+// GetFields 执行查询，每行转换为 JSON 名->值 的 map，只包含 SelectFields 请求的字段；[]byte 转换为字符串
+// 没有调用 SelectFields 时以列名为键
+func (q *Query) GetFields() ([]map[string]interface{}, error) {
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := q.context()
+	defer cancel()
+	sqlStr, args := q.BuildSelect()
+	rows, err := q.queryContext(ctx, sqlStr, args)
+	if err != nil {
+		return nil, TimeoutError(ctx, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column
+		if name, ok := q.projection[strings.ToLower(column)]; ok {
+			keys[i] = name
+		}
+	}
+	result := make([]map[string]interface{}, 0)
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, key := range keys {
+			if b, ok := values[i].([]byte); ok {
+				row[key] = string(b)
+			} else {
+				row[key] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, TimeoutError(ctx, rows.Err())
+}
+
+// projectFields 返回结构体类型中可按请求查询的字段，嵌入的结构体展开其字段
+func projectFields(t reflect.Type) []projectField {
+	if cached, ok := projectFieldsCache.Load(t); ok {
+		return cached.([]projectField)
+	}
+	var fields []projectField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column, omit, ok := dbTag(field)
+		if !ok {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if field.Anonymous && fieldType.Kind() == reflect.Struct {
+				fields = append(fields, projectFields(fieldType)...)
+			}
+			continue
+		}
+		jsonName := strings.TrimSpace(strings.Split(field.Tag.Get("json"), ",")[0])
+		if omit || !field.IsExported() || jsonName == "-" {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = column
+		}
+		fields = append(fields, projectField{name: field.Name, column: column, json: jsonName})
+	}
+	projectFieldsCache.Store(t, fields)
+	return fields
+}
+
+// lookupProjectField 按 JSON 名、列名或 Go 字段名查找字段
+func lookupProjectField(fields []projectField, name string) (projectField, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.json, name) || strings.EqualFold(field.column, name) || strings.EqualFold(field.name, name) {
+			return field, true
+		}
+	}
+	return projectField{}, false
+}
+
+// splitFields 拆分逗号分隔的字段，去掉空白和空项
+func splitFields(fields []string) []string {
+	var names []string
+	for _, item := range fields {
+		for _, name := range strings.Split(item, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+// projectUser 带 db 和 json 标签的用户
+type projectUser struct {
+	ID     int64   `db:"id" json:"id"`
+	Name   string  `db:"user_name" json:"name"`
+	Score  float64 `db:"score" json:"score"`
+	Note   *string `db:"note"`
+	Secret string  `db:"secret" json:"-"`
+}
+
+// 测试按请求字段投影
+func TestSelectFields(t *testing.T) {
+	db := openScanDB(t, 3)
+
+	var users []projectUser
+	q := NewQuery(db).Table("users").SelectFields(&users, "name, Score").OrderByAsc("id")
+	if sqlStr, _ := q.BuildSelect(); sqlStr != "SELECT user_name, score FROM users ORDER BY id ASC" {
+		t.Errorf("SQL = %s", sqlStr)
+	}
+	if err := q.Get(&users); err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(users) != 3 || users[1] != (projectUser{Name: "user2", Score: 2.5}) {
+		t.Errorf("users = %+v", users)
+	}
+
+	rows, err := NewQuery(db).Table("users").Alias("u").SelectFieldsWith(&projectUser{}, []string{"note,user_name"}, "id").
+		Where("u.id = ?", 2).GetFields()
+	if err != nil {
+		t.Fatalf("GetFields 失败: %v", err)
+	}
+	if len(rows) != 1 || len(rows[0]) != 3 || rows[0]["id"] != int64(2) || rows[0]["name"] != "user2" || rows[0]["note"] != "note2" {
+		t.Errorf("rows = %v", rows)
+	}
+
+	// 没有请求字段时查询所有可请求的字段，json:"-" 的字段不查询
+	q = NewQuery(db).Table("users").SelectFields(projectUser{})
+	if sqlStr, _ := q.BuildSelect(); sqlStr != "SELECT id, user_name, score, note FROM users" {
+		t.Errorf("SQL = %s", sqlStr)
+	}
+
+	for _, fields := range []string{"secret", "name,missing"} {
+		_, err := NewQuery(db).Table("users").SelectFields(&projectUser{}, fields).GetFields()
+		if !errors.Is(err, ErrUnknownField) {
+			t.Errorf("%s: err = %v", fields, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RowFilter 表的强制过滤条件，按语句的上下文生成，如 "tenant_id = ?" 和当前租户ID；
+// condition 为空表示该语句不过滤，返回错误时拒绝执行语句（如上下文中缺少租户ID）
+type RowFilter func(ctx context.Context) (condition string, args []interface{}, err error)
+
+// rowFilters 按表名保存的强制过滤条件，"*" 对所有表生效
+var rowFilters = struct {
+	mutex   sync.RWMutex
+	byTable map[string][]RowFilter
+}{
+	byTable: make(map[string][]RowFilter),
+}
+
+// rowFilterSkipKey 跳过强制过滤条件的表在上下文中的键，值为nil表示跳过所有表
+type rowFilterSkipKey struct{}
+
+// RegisterRowFilter 为表注册强制过滤条件，同一张表的多个条件以 AND 连接，table 为 "*" 时对所有表生效
+// 查询构建器按主表追加条件，GORM 的查询、更新和删除由 gosqlx 的回调追加条件:
+//
+//	query.RegisterRowFilter("orders", func(ctx context.Context) (string, []interface{}, error) {
+//		tenantID, ok := ctx.Value(tenantKey{}).(int64)
+//		if !ok {
+//			return "", nil, errors.New("缺少租户ID")
+//		}
+//		return "tenant_id = ?", []interface{}{tenantID}, nil
+//	})
+func RegisterRowFilter(table string, filter RowFilter) {
+	if filter == nil {
+		return
+	}
+	rowFilters.mutex.Lock()
+	defer rowFilters.mutex.Unlock()
+	key := strings.ToLower(table)
+	rowFilters.byTable[key] = append(rowFilters.byTable[key], filter)
+}
+
+// ClearRowFilters 删除表的所有强制过滤条件，不传表名时删除全部
+func ClearRowFilters(tables ...string) {
+	rowFilters.mutex.Lock()
+	defer rowFilters.mutex.Unlock()
+	if len(tables) == 0 {
+		rowFilters.byTable = make(map[string][]RowFilter)
+		return
+	}
+	for _, table := range tables {
+		delete(rowFilters.byTable, strings.ToLower(table))
+	}
+}
+
+// WithoutRowFilters 返回跳过强制过滤条件的上下文，不传表名时跳过所有表，用于后台任务和跨租户的管理操作
+func WithoutRowFilters(ctx context.Context, tables ...string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var skip map[string]bool
+	if len(tables) > 0 {
+		parent, ok := ctx.Value(rowFilterSkipKey{}).(map[string]bool)
+		if ok && parent == nil {
+			return ctx
+		}
+		skip = make(map[string]bool, len(parent)+len(tables))
+		for table := range parent {
+			skip[table] = true
+		}
+		for _, table := range tables {
+			skip[strings.ToLower(table)] = true
+		}
+	}
+	return context.WithValue(ctx, rowFilterSkipKey{}, skip)
+}
+
+// RowFilterCondition 生成表在上下文中的强制过滤条件，多个条件以 AND 连接，没有条件时返回空字符串
+// 表名可带模式、引号和别名，按不带模式的名称匹配
+func RowFilterCondition(ctx context.Context, table string) (string, []interface{}, error) {
+	name := rowFilterTable(table)
+	if name == "" {
+		return "", nil, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if skip, ok := ctx.Value(rowFilterSkipKey{}).(map[string]bool); ok && (skip == nil || skip[name]) {
+		return "", nil, nil
+	}
+
+	rowFilters.mutex.RLock()
+	filters := append(append([]RowFilter(nil), rowFilters.byTable["*"]...), rowFilters.byTable[name]...)
+	rowFilters.mutex.RUnlock()
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	for _, filter := range filters {
+		condition, filterArgs, err := filter(ctx)
+		if err != nil {
+			return "", nil, fmt.Errorf("表 %s 的强制过滤条件: %w", name, err)
+		}
+		if condition == "" {
+			continue
+		}
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, filterArgs...)
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// rowFilterTable 取表名：去掉别名、模式和引号并转为小写
+func rowFilterTable(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := fields[0]
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.Trim(name, "`\"[]"))
+}
+
+// filteredWhere 构建 WHERE 条件并追加主表的强制过滤条件
+func (q *Query) filteredWhere() (string, []interface{}) {
+	whereStr, whereArgs := q.where.Build()
+	condition, args, err := RowFilterCondition(q.ctx, q.table)
+	if err != nil {
+		q.setErr(err)
+		return whereStr, whereArgs
+	}
+	if condition == "" {
+		return whereStr, whereArgs
+	}
+	if whereStr == "" {
+		return condition, args
+	}
+	return "(" + whereStr + ") AND " + condition, append(append([]interface{}(nil), whereArgs...), args...)
+}
@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestRowFilter(t *testing.T) {
+	RegisterRowFilter("rf_orders", func(ctx context.Context) (string, []interface{}, error) {
+		tenantID, ok := ctx.Value(tenantKey{}).(int)
+		if !ok {
+			return "", nil, errors.New("缺少租户ID")
+		}
+		return "tenant_id = ?", []interface{}{tenantID}, nil
+	})
+	defer ClearRowFilters("rf_orders")
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, 7)
+	sqlStr, args := NewQuery(nil).WithContext(ctx).Table("rf_orders AS o").WhereRaw("a = ? OR b = ?", 1, 2).BuildSelect()
+	if want := "SELECT * FROM rf_orders AS o WHERE (a = ? OR b = ?) AND (tenant_id = ?)"; sqlStr != want {
+		t.Errorf("BuildSelect() = %q, want %q", sqlStr, want)
+	}
+	if len(args) != 3 || args[2] != 7 {
+		t.Errorf("args = %v", args)
+	}
+
+	// 缺少租户时构建失败
+	q := NewQuery(nil).Table("rf_orders")
+	q.BuildSelect()
+	if q.Err() == nil {
+		t.Error("缺少租户ID时应返回错误")
+	}
+
+	// 跳过过滤条件
+	sqlStr, _ = NewQuery(nil).WithContext(WithoutRowFilters(ctx, "RF_ORDERS")).Table("`rf_orders`").BuildSelect()
+	if want := "SELECT * FROM `rf_orders`"; sqlStr != want {
+		t.Errorf("WithoutRowFilters BuildSelect() = %q, want %q", sqlStr, want)
+	}
+	if condition, _, _ := RowFilterCondition(WithoutRowFilters(ctx), "rf_orders"); condition != "" {
+		t.Errorf("WithoutRowFilters() condition = %q", condition)
+	}
+}
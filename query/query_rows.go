@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gzorm/gosqlx/builder"
+	"gorm.io/gorm"
+)
+
+// Rows 执行当前查询并返回结果集，调用方负责关闭
+// 适用于导出等需要流式读取大量数据的场景；ctx 为 nil 时使用 WithContext 设置的上下文，
+// 结果集不受 Timeout 限制
+func (q *Query) Rows(ctx context.Context) (*sql.Rows, error) {
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = q.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	sqlStr, args := q.BuildSelect()
+	return q.queryContext(ctx, sqlStr, args)
+}
+
+// queryContext 在当前连接上执行查询语句，返回结果集
+func (q *Query) queryContext(ctx context.Context, sqlStr string, args []interface{}) (*sql.Rows, error) {
+	sqlStr = q.commented(ctx, builder.Rebind(q.detectDialect(), sqlStr))
+	switch db := q.db.(type) {
+	case *sql.DB:
+		return db.QueryContext(ctx, sqlStr, args...)
+	case *sql.Tx:
+		return db.QueryContext(ctx, sqlStr, args...)
+	case *sql.Conn:
+		return db.QueryContext(ctx, sqlStr, args...)
+	case *gorm.DB:
+		if tx, ok := db.Statement.ConnPool.(*sql.Tx); ok {
+			return tx.QueryContext(ctx, sqlStr, args...)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		return sqlDB.QueryContext(ctx, sqlStr, args...)
+	case nil:
+		return nil, fmt.Errorf("数据库连接不能为空")
+	default:
+		return nil, fmt.Errorf("不支持的数据库连接类型: %T", q.db)
+	}
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== 扫描计划缓存 ====================
+
+// scanPlanKey 扫描计划的缓存键：结构体类型和结果集的列
+type scanPlanKey struct {
+	typ     reflect.Type
+	columns string
+}
+
+// scanField 结果集的一列对应的结构体字段
+type scanField struct {
+	index    []int        // 字段的索引路径，列没有对应字段时为 nil
+	offset   uintptr      // 字段相对结构体起始地址的偏移，只经过值嵌入的结构体
+	kind     reflect.Kind // 字段类型
+	time     bool         // 字段为 time.Time
+	settable bool         // 字段是否导出
+}
+
+// scanPlans 结构体类型和列 -> []scanField，每种组合只解析一次
+var scanPlans sync.Map
+
+// timeType time.Time 的类型
+var timeType = reflect.TypeOf(time.Time{})
+
+// scanPlanFor 获取按 columns 扫描到结构体 typ 的计划，结果按列的顺序排列
+func scanPlanFor(typ reflect.Type, columns []string) []scanField {
+	key := scanPlanKey{typ: typ, columns: strings.Join(columns, "\x00")}
+	if plan, ok := scanPlans.Load(key); ok {
+		return plan.([]scanField)
+	}
+
+	plan := make([]scanField, len(columns))
+	for i, column := range columns {
+		index := fieldIndex(typ, column)
+		if index == nil {
+			continue
+		}
+		var offset uintptr
+		t := typ
+		for _, j := range index {
+			field := t.Field(j)
+			offset += field.Offset
+			t = field.Type
+		}
+		field := typ.FieldByIndex(index)
+		plan[i] = scanField{index: index, offset: offset, kind: t.Kind(), time: t == timeType, settable: field.IsExported()}
+	}
+	actual, _ := scanPlans.LoadOrStore(key, plan)
+	return actual.([]scanField)
+}
+
+// fieldIndex 查找列对应的结构体字段，返回索引路径，不存在时返回 nil
+// 先按 db 标签或字段名（不区分大小写）匹配本层字段，再查找值嵌入的结构体
+func fieldIndex(typ reflect.Type, column string) []int {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if name, _, ok := dbTag(field); ok && name == column {
+			return []int{i}
+		}
+		if strings.EqualFold(field.Name, column) {
+			return []int{i}
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if index := fieldIndex(field.Type, column); index != nil {
+				return append([]int{i}, index...)
+			}
+		}
+	}
+	return nil
+}
+
+// rowScanner 在同一结果集的各行之间复用扫描目标，结构体目标使用缓存的扫描计划
+type rowScanner struct {
+	values  []interface{}
+	targets []interface{}
+	plan    []scanField
+}
+
+// newRowScanner 创建按 columns 扫描到 typ 的行扫描器
+func newRowScanner(columns []string, typ reflect.Type) *rowScanner {
+	s := &rowScanner{values: make([]interface{}, len(columns)), targets: make([]interface{}, len(columns))}
+	for i := range s.values {
+		s.targets[i] = &s.values[i]
+	}
+	if typ.Kind() == reflect.Struct && typ != timeType {
+		s.plan = scanPlanFor(typ, columns)
+	}
+	return s
+}
+
+// scan 扫描当前行到 outValue（可寻址的结构体或基本类型），NULL 保持零值
+func (s *rowScanner) scan(rows *sql.Rows, outValue reflect.Value) error {
+	if err := rows.Scan(s.targets...); err != nil {
+		return err
+	}
+	if s.plan == nil {
+		return setFieldValue(outValue, s.values[0])
+	}
+	for i, field := range s.plan {
+		value := s.values[i]
+		if field.index == nil || value == nil || setField(outValue, field, value) {
+			continue
+		}
+		if err := setFieldValue(outValue.FieldByIndex(field.index), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
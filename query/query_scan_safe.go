@@ -0,0 +1,11 @@
+//go:build !gosqlx_unsafe
+
+package query
+
+import "reflect"
+
+// setField 按扫描计划直接写入字段，返回 false 时由 setFieldValue 通过反射转换写入
+// 默认构建不直接写内存，使用 -tags gosqlx_unsafe 启用
+func setField(outValue reflect.Value, field scanField, value interface{}) bool {
+	return false
+}
@@ -0,0 +1,103 @@
+package query
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type scanBase struct {
+	ID        int64
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type scanUser struct {
+	scanBase
+	Name   string `db:"user_name"`
+	Score  float64
+	Active bool
+	Note   *string
+	Level  int
+	secret string
+}
+
+// openScanDB 创建包含 n 行用户数据的内存数据库
+func openScanDB(t testing.TB, n int) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	var b strings.Builder
+	b.WriteString("CREATE TABLE users (id INTEGER PRIMARY KEY, user_name TEXT, score REAL, active BOOLEAN, note TEXT, level INTEGER, secret TEXT, created_at DATETIME);")
+	for i := 1; i <= n; i++ {
+		note := "NULL"
+		if i%2 == 0 {
+			note = fmt.Sprintf("'note%d'", i)
+		}
+		fmt.Fprintf(&b, "INSERT INTO users VALUES (%d, 'user%d', %d.5, %d, %s, %d, 'x', '2024-01-02 03:04:05');", i, i, i, i%2, note, i%5)
+	}
+	if _, err := db.Exec(b.String()); err != nil {
+		t.Fatalf("准备测试数据失败: %v", err)
+	}
+	return db
+}
+
+// 测试按缓存的扫描计划扫描结构体
+func TestScanPlan(t *testing.T) {
+	db := openScanDB(t, 3)
+
+	var users []scanUser
+	if err := NewQuery(db).Table("users").OrderByAsc("id").Get(&users); err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("期望 3 行，实际为 %d", len(users))
+	}
+	u := users[1]
+	if u.ID != 2 || u.Name != "user2" || u.Score != 2.5 || u.Active || u.Note == nil || *u.Note != "note2" || u.Level != 2 {
+		t.Errorf("第 2 行 = %+v", u)
+	}
+	if users[0].Note != nil || !users[0].Active || users[0].CreatedAt.Year() != 2024 || users[0].secret != "" {
+		t.Errorf("第 1 行 = %+v", users[0])
+	}
+
+	var one scanUser
+	if err := NewQuery(db).Table("users").Where("id = ?", 3).First(&one); err != nil || one.Name != "user3" {
+		t.Errorf("First = %+v, %v", one, err)
+	}
+	var names []string
+	if err := NewQuery(db).Table("users").OrderByDesc("id").Pluck("user_name", &names); err != nil || !reflect.DeepEqual(names, []string{"user3", "user2", "user1"}) {
+		t.Errorf("Pluck = %v, %v", names, err)
+	}
+
+	// 同一类型和列只解析一次
+	columns := []string{"id", "user_name", "missing"}
+	plan := scanPlanFor(reflect.TypeOf(scanUser{}), columns)
+	if again := scanPlanFor(reflect.TypeOf(scanUser{}), columns); &again[0] != &plan[0] {
+		t.Error("扫描计划未缓存")
+	}
+	if !reflect.DeepEqual(plan[0].index, []int{0, 0}) || !reflect.DeepEqual(plan[1].index, []int{1}) || plan[2].index != nil {
+		t.Errorf("扫描计划 = %+v", plan)
+	}
+}
+
+// 基准测试：按结构体切片扫描 1000 行
+func BenchmarkScanStructs(b *testing.B) {
+	db := openScanDB(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var users []scanUser
+		if err := NewQuery(db).Table("users").Get(&users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build gosqlx_unsafe
+
+package query
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// setField 按扫描计划的字段偏移直接写入常见类型，省去每列的反射取值和赋值
+// 仅处理驱动返回类型与字段类型一致的情况，其余返回 false 由 setFieldValue 转换
+func setField(outValue reflect.Value, field scanField, value interface{}) bool {
+	if !field.settable || !outValue.CanAddr() {
+		return false
+	}
+	ptr := unsafe.Add(outValue.Addr().UnsafePointer(), field.offset)
+	switch v := value.(type) {
+	case int64:
+		switch field.kind {
+		case reflect.Int64:
+			*(*int64)(ptr) = v
+		case reflect.Int:
+			*(*int)(ptr) = int(v)
+		default:
+			return false
+		}
+	case float64:
+		if field.kind != reflect.Float64 {
+			return false
+		}
+		*(*float64)(ptr) = v
+	case bool:
+		if field.kind != reflect.Bool {
+			return false
+		}
+		*(*bool)(ptr) = v
+	case string:
+		if field.kind != reflect.String {
+			return false
+		}
+		*(*string)(ptr) = v
+	case []byte:
+		if field.kind != reflect.String {
+			return false
+		}
+		*(*string)(ptr) = string(v)
+	case time.Time:
+		if !field.time {
+			return false
+		}
+		*(*time.Time)(ptr) = v
+	default:
+		return false
+	}
+	return true
+}
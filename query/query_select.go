@@ -0,0 +1,112 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structColumnsCache 结构体类型到查询列的缓存
+var structColumnsCache sync.Map
+
+// SelectStruct 按结构体字段的 db 标签生成查询列，避免 SELECT *，模型增删字段时查询列自动同步
+// model 可以是结构体、结构体指针、切片或切片指针；没有 db 标签的字段、db:"-" 和带 omit 选项的字段
+// （如 db:"password,omit"）不查询，嵌入的结构体展开其字段，omit 为本次查询额外排除的列；设置了别名时列名加上别名前缀
+//
+//	var users []User
+//	q.Table("users").SelectStruct(&users, "avatar").Find(&users)
+func (q *Query) SelectStruct(model interface{}, omit ...string) *Query {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		q.setErr(errors.New("SelectStruct 的参数必须是结构体、结构体指针或结构体切片"))
+		return q
+	}
+
+	var columns []string
+	for _, column := range structColumns(t) {
+		if containsFold(omit, column) {
+			continue
+		}
+		if q.alias != "" {
+			column = q.alias + "." + column
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		q.setErr(errors.New("结构体没有可查询的 db 标签字段"))
+		return q
+	}
+	return q.Select(columns...)
+}
+
+// DistinctOn 每组 columns 只保留排序后的第一行（PostgreSQL、DuckDB），ORDER BY 需以 columns 开头
+//
+//	q.Table("prices").DistinctOn("product_id").OrderBy("product_id, created_at DESC").Get(&latest)
+func (q *Query) DistinctOn(columns ...string) *Query {
+	switch q.detectDialect() {
+	case "postgres", "postgresql", "duckdb":
+	default:
+		q.setErr(fmt.Errorf("%s 不支持 DISTINCT ON", q.detectDialect()))
+		return q
+	}
+	q.distinctOn = columns
+	return q
+}
+
+// structColumns 返回结构体类型中带 db 标签的列名
+func structColumns(t reflect.Type) []string {
+	if cached, ok := structColumnsCache.Load(t); ok {
+		return cached.([]string)
+	}
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omit, ok := dbTag(field)
+		if !ok {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if field.Anonymous && fieldType.Kind() == reflect.Struct {
+				columns = append(columns, structColumns(fieldType)...)
+			}
+			continue
+		}
+		if !omit && field.IsExported() {
+			columns = append(columns, name)
+		}
+	}
+	structColumnsCache.Store(t, columns)
+	return columns
+}
+
+// dbTag 解析字段的 db 标签，返回列名和是否带 omit 选项；没有标签或为 "-" 时 ok 为 false
+func dbTag(field reflect.StructField) (name string, omit bool, ok bool) {
+	tag, exists := field.Tag.Lookup("db")
+	if !exists || tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == "omit" {
+			omit = true
+		}
+	}
+	name = strings.TrimSpace(parts[0])
+	return name, omit, name != ""
+}
+
+// containsFold 判断列表中是否包含指定字符串（忽略大小写）
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
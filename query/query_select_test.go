@@ -0,0 +1,80 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gzorm/gosqlx/builder"
+)
+
+type selectBase struct {
+	ID int64 `db:"id"`
+}
+
+type selectUser struct {
+	selectBase
+	Name     string `db:"name"`
+	Email    string `db:"email"`
+	Password string `db:"password,omit"`
+	Note     string `db:"-"`
+	Temp     string
+}
+
+// 测试按结构体标签生成查询列
+func TestSelectStruct(t *testing.T) {
+	var users []selectUser
+	sqlStr, _, _ := NewQuery(nil).Dialect("mysql").Table("users").SelectStruct(&users, "EMAIL").ToSQL()
+	if want := "SELECT id, name FROM users"; sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q", want, sqlStr)
+	}
+
+	sqlStr, _, _ = NewQuery(nil).Dialect("mysql").Table("users").Alias("u").SelectStruct(selectUser{}).ToSQL()
+	if want := "SELECT u.id, u.name, u.email FROM users AS u"; sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q", want, sqlStr)
+	}
+
+	if _, _, err := NewQuery(nil).Table("users").SelectStruct(1).ToSQL(); err == nil {
+		t.Error("非结构体参数应返回错误")
+	}
+
+	// 扫描时识别带选项的标签和嵌入结构体的字段
+	var user selectUser
+	value := reflect.ValueOf(&user).Elem()
+	findField(value, "id").SetInt(7)
+	findField(value, "password").SetString("x")
+	if user.ID != 7 || user.Password != "x" {
+		t.Errorf("findField 结果 = %+v", user)
+	}
+}
+
+// 测试按白名单追加排序
+func TestOrderBySafe(t *testing.T) {
+	allowed := map[string]string{"created": "created_at", "name": ""}
+	sqlStr, _, err := NewQuery(nil).Dialect("mysql").Table("users").OrderBySafe("-created,name", allowed).ToSQL()
+	if want := "SELECT * FROM users ORDER BY created_at DESC, name ASC"; err != nil || sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q, %v", want, sqlStr, err)
+	}
+
+	if _, _, err := NewQuery(nil).Table("users").OrderBySafe("id desc; DROP TABLE users", allowed).ToSQL(); !errors.Is(err, builder.ErrInvalidSort) {
+		t.Errorf("不在白名单内的字段 error = %v", err)
+	}
+}
+
+// 测试 DISTINCT 和 DISTINCT ON
+func TestDistinct(t *testing.T) {
+	sqlStr, _, _ := NewQuery(nil).Dialect("mysql").Table("users").Distinct("city", "country").ToSQL()
+	if want := "SELECT DISTINCT city, country FROM users"; sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q", want, sqlStr)
+	}
+
+	sqlStr, _, err := NewQuery(nil).Dialect("postgres").Table("prices").Select("product_id", "price").
+		DistinctOn("product_id").OrderBy("product_id, created_at DESC").ToSQL()
+	if want := "SELECT DISTINCT ON (product_id) product_id, price FROM prices ORDER BY product_id, created_at DESC"; err != nil || sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q, %v", want, sqlStr, err)
+	}
+
+	if _, _, err := NewQuery(nil).Dialect("mysql").Table("prices").DistinctOn("product_id").ToSQL(); err == nil {
+		t.Error("MySQL 使用 DISTINCT ON 应返回错误")
+	}
+}
@@ -0,0 +1,24 @@
+package query
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ToSQL 返回最终的SQL和参数，不访问数据库
+func (q *Query) ToSQL() (string, []interface{}, error) {
+	if err := q.Err(); err != nil {
+		return "", nil, err
+	}
+	sqlStr, args := q.BuildSelect()
+	return sqlStr, args, nil
+}
+
+// Preview 返回参数内联后的SQL，仅用于日志和调试，不可直接执行
+func (q *Query) Preview() string {
+	sqlStr, args := q.BuildSelect()
+	if db, ok := q.db.(*gorm.DB); ok && db != nil && db.Dialector != nil {
+		return db.Dialector.Explain(sqlStr, args...)
+	}
+	return logger.ExplainSQL(sqlStr, nil, `'`, args...)
+}
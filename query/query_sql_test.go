@@ -0,0 +1,31 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+// 测试生成SQL和参数内联预览
+func TestToSQL(t *testing.T) {
+	q := NewQuery(nil).Table("users").Where("name = ?", "O'Brien").WhereIn("id", []int{1, 2}).Limit(10)
+
+	sqlStr, args, err := q.ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM users WHERE name = ? AND id IN (?, ?) LIMIT 10"; sqlStr != want {
+		t.Errorf("期望 %q，实际为 %q", want, sqlStr)
+	}
+	if len(args) != 3 {
+		t.Errorf("期望3个参数，实际为 %v", args)
+	}
+	if want := "SELECT * FROM users WHERE name = 'O''Brien' AND id IN (1, 2) LIMIT 10"; q.Preview() != want {
+		t.Errorf("期望 %q，实际为 %q", want, q.Preview())
+	}
+
+	q = NewQuery(nil).Table("users")
+	q.setErr(errors.New("构建失败"))
+	if _, _, err := q.ToSQL(); err == nil {
+		t.Error("期望返回构建错误")
+	}
+}
@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrQueryTimeout 语句执行超时，同时可通过 errors.Is(err, context.DeadlineExceeded) 判断
+var ErrQueryTimeout = errors.New("语句执行超时")
+
+// WithContext 设置执行查询使用的上下文，上下文取消时查询随之取消
+func (q *Query) WithContext(ctx context.Context) *Query {
+	q.ctx = ctx
+	return q
+}
+
+// Timeout 设置单次查询的超时时间，timeout<=0 表示不限制
+func (q *Query) Timeout(timeout time.Duration) *Query {
+	q.timeout = timeout
+	return q
+}
+
+// context 返回执行查询使用的上下文
+func (q *Query) context() (context.Context, context.CancelFunc) {
+	ctx := q.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if q.timeout > 0 {
+		return context.WithTimeout(ctx, q.timeout)
+	}
+	return ctx, func() {}
+}
+
+// TimeoutError 语句因超时失败时，将错误包装为 ErrQueryTimeout，ctx 为执行语句的上下文（可为 nil）
+func TimeoutError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, ErrQueryTimeout) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || (ctx != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+		return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// 测试超时错误转换
+func TestTimeoutError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	err := TimeoutError(ctx, errors.New("interrupted"))
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("截止时间已过的语句错误应转换为 ErrQueryTimeout，实际为 %v", err)
+	}
+	if again := TimeoutError(ctx, err); again != err {
+		t.Error("已转换的错误不应重复包装")
+	}
+	if err := TimeoutError(nil, context.DeadlineExceeded); !errors.Is(err, ErrQueryTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DeadlineExceeded 应转换为 ErrQueryTimeout，实际为 %v", err)
+	}
+	if err := TimeoutError(context.Background(), context.Canceled); errors.Is(err, ErrQueryTimeout) {
+		t.Error("取消不应视为超时")
+	}
+	if TimeoutError(ctx, nil) != nil {
+		t.Error("nil 错误应原样返回")
+	}
+}
@@ -0,0 +1,288 @@
+package query
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gzorm/gosqlx/builder"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReadOnly 只读的查询构建器拒绝执行写语句
+var ErrReadOnly = errors.New("只读连接不允许写操作")
+
+// ReadOnly 将查询构建器设为只读，InsertFromSelect、UpdateWithJoin 等写操作返回 ErrReadOnly
+func (q *Query) ReadOnly() *Query {
+	q.readOnly = true
+	return q
+}
+
+// RawExpr 原始SQL表达式，用于 UpdateWithJoin、InsertOnDuplicate 中引用其他列
+// 与 gorm.Expr 为同一类型，适配器的 BatchInsertOnDuplicate 同样接受
+type RawExpr = clause.Expr
+
+// Raw 创建原始SQL表达式
+// 示例: Raw("u.level + ?", 1)
+func Raw(sql string, args ...interface{}) RawExpr {
+	return RawExpr{SQL: sql, Vars: args}
+}
+
+// InsertFromSelect 将子查询结果写入 table，返回影响行数
+// 示例: NewQuery(db).InsertFromSelect("users_archive", []string{"id", "name"},
+// NewQuery(db).Table("users").Select("id", "name").Where("deleted = ?", 1))
+func (q *Query) InsertFromSelect(table string, columns []string, sub *Query) (int64, error) {
+	sqlStr, args := q.BuildInsertFromSelect(table, columns, sub)
+	return q.execStatement(sqlStr, args)
+}
+
+// BuildInsertFromSelect 构建 INSERT INTO ... SELECT 语句
+func (q *Query) BuildInsertFromSelect(table string, columns []string, sub *Query) (string, []interface{}) {
+	if table == "" || sub == nil {
+		q.setErr(errors.New("INSERT ... SELECT 的目标表和子查询不能为空"))
+		return "", nil
+	}
+	if err := sub.Err(); err != nil {
+		q.setErr(err)
+	}
+	if q.detectDialect() == "mongodb" {
+		q.setErr(errors.New("mongodb 不支持 INSERT ... SELECT"))
+	}
+
+	var query strings.Builder
+	query.WriteString("INSERT INTO ")
+	query.WriteString(table)
+	if len(columns) > 0 {
+		query.WriteString(" (")
+		query.WriteString(strings.Join(columns, ", "))
+		query.WriteString(")")
+	}
+
+	// SQLServer 的 WITH 子句必须位于 INSERT 之前
+	ctes := sub.ctes
+	if len(ctes) > 0 && q.detectDialect() == "sqlserver" {
+		withSQL, withArgs := sub.buildWith()
+		sub.ctes = nil
+		defer func() { sub.ctes = ctes }()
+		selectSQL, selectArgs := sub.BuildSelect()
+		return withSQL + query.String() + " " + selectSQL, append(withArgs, selectArgs...)
+	}
+
+	selectSQL, args := sub.BuildSelect()
+	query.WriteString(" ")
+	query.WriteString(selectSQL)
+	return query.String(), args
+}
+
+// InsertIgnore 向当前表插入多行，唯一键冲突的行忽略，返回实际插入的行数
+// 示例: NewQuery(db).Table("tags").InsertIgnore([]string{"name"}, [][]interface{}{{"go"}, {"sql"}})
+func (q *Query) InsertIgnore(columns []string, values [][]interface{}) (int64, error) {
+	sqlStr, args := q.BuildInsertIgnore(columns, values)
+	return q.execStatement(sqlStr, args)
+}
+
+// BuildInsertIgnore 构建忽略冲突的多行 INSERT 语句
+// MySQL 系使用 INSERT IGNORE，PostgreSQL/SQLite/DuckDB 使用 ON CONFLICT DO NOTHING
+func (q *Query) BuildInsertIgnore(columns []string, values [][]interface{}) (string, []interface{}) {
+	valuesSQL, args := q.insertValues(columns, values)
+	if valuesSQL == "" {
+		return "", nil
+	}
+	switch dialect := q.detectDialect(); dialect {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		return "INSERT IGNORE INTO " + valuesSQL, args
+	case "postgres", "sqlite3", "duckdb":
+		return "INSERT INTO " + valuesSQL + " ON CONFLICT DO NOTHING", args
+	default:
+		q.setErr(fmt.Errorf("%s 不支持 InsertIgnore", dialect))
+		return "", nil
+	}
+}
+
+// InsertOnDuplicate 向当前表插入多行，唯一键冲突时按 updates 更新（ON DUPLICATE KEY UPDATE），返回影响行数
+// updates 的值为参数或 Raw 表达式，按列名排序生成
+// 示例: NewQuery(db).Table("counters").InsertOnDuplicate([]string{"name", "count"}, [][]interface{}{{"visits", 1}},
+// map[string]interface{}{"count": Raw("count + VALUES(count)"), "updated_at": Raw("NOW()")})
+func (q *Query) InsertOnDuplicate(columns []string, values [][]interface{}, updates map[string]interface{}) (int64, error) {
+	sqlStr, args := q.BuildInsertOnDuplicate(columns, values, updates)
+	return q.execStatement(sqlStr, args)
+}
+
+// BuildInsertOnDuplicate 构建 INSERT ... ON DUPLICATE KEY UPDATE 语句，仅支持 MySQL 系
+func (q *Query) BuildInsertOnDuplicate(columns []string, values [][]interface{}, updates map[string]interface{}) (string, []interface{}) {
+	switch dialect := q.detectDialect(); dialect {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+	default:
+		q.setErr(fmt.Errorf("%s 不支持 ON DUPLICATE KEY UPDATE", dialect))
+		return "", nil
+	}
+	if len(updates) == 0 {
+		q.setErr(errors.New("ON DUPLICATE KEY UPDATE 的更新列不能为空"))
+		return "", nil
+	}
+	valuesSQL, args := q.insertValues(columns, values)
+	if valuesSQL == "" {
+		return "", nil
+	}
+
+	updateColumns := make([]string, 0, len(updates))
+	for column := range updates {
+		updateColumns = append(updateColumns, column)
+	}
+	sort.Strings(updateColumns)
+	assigns := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		switch v := updates[column].(type) {
+		case RawExpr:
+			assigns = append(assigns, column+" = "+v.SQL)
+			args = append(args, v.Vars...)
+		default:
+			assigns = append(assigns, column+" = ?")
+			args = append(args, v)
+		}
+	}
+	return "INSERT INTO " + valuesSQL + " ON DUPLICATE KEY UPDATE " + strings.Join(assigns, ", "), args
+}
+
+// insertValues 构建 "表 (列) VALUES (...), (...)" 部分，每行的值个数必须与列数相同
+func (q *Query) insertValues(columns []string, values [][]interface{}) (string, []interface{}) {
+	if q.table == "" || len(columns) == 0 || len(values) == 0 {
+		q.setErr(errors.New("插入的表名、列和值不能为空"))
+		return "", nil
+	}
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	rows := make([]string, len(values))
+	args := make([]interface{}, 0, len(values)*len(columns))
+	for i, row := range values {
+		if len(row) != len(columns) {
+			q.setErr(fmt.Errorf("第 %d 行的值个数 %d 与列数 %d 不一致", i+1, len(row), len(columns)))
+			return "", nil
+		}
+		rows[i] = placeholder
+		args = append(args, row...)
+	}
+	return fmt.Sprintf("%s (%s) VALUES %s", q.table, strings.Join(columns, ", "), strings.Join(rows, ", ")), args
+}
+
+// UpdateWithJoin 按连接表更新当前表，条件取自 Where，返回影响行数
+// set 的值为参数或 Raw 表达式，按列名排序生成；joinTable 可带别名，如 "users u"
+// 示例: NewQuery(db).Table("orders").Alias("o").Where("o.status = ?", 0).
+// UpdateWithJoin("users u", "o.user_id = u.id", map[string]interface{}{"o.level": Raw("u.level")})
+func (q *Query) UpdateWithJoin(joinTable, on string, set map[string]interface{}) (int64, error) {
+	sqlStr, args := q.BuildUpdateWithJoin(joinTable, on, set)
+	return q.execStatement(sqlStr, args)
+}
+
+// BuildUpdateWithJoin 构建带连接的 UPDATE 语句
+// MySQL 系使用 UPDATE ... JOIN ... SET，PostgreSQL/SQLite/DuckDB 使用 UPDATE ... SET ... FROM，
+// SQLServer 使用 UPDATE alias SET ... FROM ... JOIN
+func (q *Query) BuildUpdateWithJoin(joinTable, on string, set map[string]interface{}) (string, []interface{}) {
+	if q.table == "" || joinTable == "" || on == "" || len(set) == 0 {
+		q.setErr(errors.New("UpdateWithJoin 的表名、连接表、连接条件和更新列不能为空"))
+		return "", nil
+	}
+
+	dialect := q.detectDialect()
+	target := q.table
+	if q.alias != "" {
+		target += " AS " + q.alias
+	}
+	ref := q.alias
+	if ref == "" {
+		ref = q.table
+	}
+
+	columns := make([]string, 0, len(set))
+	for column := range set {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var (
+		assigns []string
+		args    []interface{}
+	)
+	for _, key := range columns {
+		column := key
+		switch dialect {
+		case "postgres", "sqlite3", "duckdb":
+			// SET 左侧不允许带表别名
+			if i := strings.LastIndexByte(column, '.'); i >= 0 {
+				column = column[i+1:]
+			}
+		default:
+			// 多表更新时未限定的列归属于目标表
+			if !strings.Contains(column, ".") {
+				column = ref + "." + column
+			}
+		}
+		switch v := set[key].(type) {
+		case RawExpr:
+			assigns = append(assigns, column+" = "+v.SQL)
+			args = append(args, v.Vars...)
+		default:
+			assigns = append(assigns, column+" = ?")
+			args = append(args, v)
+		}
+	}
+
+	whereStr, whereArgs := q.filteredWhere()
+	var query strings.Builder
+	switch dialect {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		query.WriteString(fmt.Sprintf("UPDATE %s INNER JOIN %s ON %s SET %s", target, joinTable, on, strings.Join(assigns, ", ")))
+		if whereStr != "" {
+			query.WriteString(" WHERE " + whereStr)
+		}
+	case "postgres", "sqlite3", "duckdb":
+		query.WriteString(fmt.Sprintf("UPDATE %s SET %s FROM %s WHERE %s", target, strings.Join(assigns, ", "), joinTable, on))
+		if whereStr != "" {
+			query.WriteString(" AND " + whereStr)
+		}
+	case "sqlserver":
+		query.WriteString(fmt.Sprintf("UPDATE %s SET %s FROM %s INNER JOIN %s ON %s", ref, strings.Join(assigns, ", "), target, joinTable, on))
+		if whereStr != "" {
+			query.WriteString(" WHERE " + whereStr)
+		}
+	default:
+		q.setErr(fmt.Errorf("%s 不支持 UPDATE ... JOIN", dialect))
+		return "", nil
+	}
+	return query.String(), append(args, whereArgs...)
+}
+
+// execStatement 执行非查询语句，返回影响行数
+func (q *Query) execStatement(sqlStr string, args []interface{}) (int64, error) {
+	if err := q.Err(); err != nil {
+		return 0, err
+	}
+	if q.db == nil {
+		return 0, errors.New("数据库连接不能为空")
+	}
+	if q.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	ctx, cancel := q.context()
+	defer cancel()
+	sqlStr = q.commented(ctx, builder.Rebind(q.detectDialect(), sqlStr))
+
+	var (
+		result sql.Result
+		err    error
+	)
+	switch db := q.db.(type) {
+	case *sql.DB:
+		result, err = db.ExecContext(ctx, sqlStr, args...)
+	case *sql.Tx:
+		result, err = db.ExecContext(ctx, sqlStr, args...)
+	default:
+		return 0, fmt.Errorf("不支持的数据库连接类型: %T", q.db)
+	}
+	if err != nil {
+		return 0, TimeoutError(ctx, err)
+	}
+	return result.RowsAffected()
+}
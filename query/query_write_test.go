@@ -0,0 +1,119 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// 测试 INSERT ... SELECT
+func TestInsertFromSelect(t *testing.T) {
+	sub := NewQuery(nil).Table("users").Select("id", "name").Where("deleted = ?", 1)
+	sqlStr, args := NewQuery(nil).Dialect("mysql").BuildInsertFromSelect("users_archive", []string{"id", "name"}, sub)
+	want := "INSERT INTO users_archive (id, name) SELECT id, name FROM users WHERE deleted = ?"
+	if sqlStr != want || !reflect.DeepEqual(args, []interface{}{1}) {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, args)
+	}
+
+	// SQLServer 的 WITH 子句前置
+	recent := NewQuery(nil).Table("orders").Where("created_at > ?", "2024-01-01")
+	sub = NewQuery(nil).With("recent", recent).Table("recent").Select("id")
+	sqlStr, args = NewQuery(nil).Dialect("sqlserver").BuildInsertFromSelect("order_ids", []string{"id"}, sub)
+	want = "WITH recent AS (SELECT * FROM orders WHERE created_at > ?) INSERT INTO order_ids (id) SELECT id FROM recent"
+	if sqlStr != want || len(args) != 1 {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, args)
+	}
+	if len(sub.ctes) != 1 {
+		t.Error("构建后应恢复子查询的 CTE")
+	}
+}
+
+// 测试 UPDATE ... JOIN
+func TestUpdateWithJoin(t *testing.T) {
+	set := map[string]interface{}{"o.level": Raw("u.level + ?", 1), "remark": "sync"}
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{"mysql", "UPDATE orders AS o INNER JOIN users u ON o.user_id = u.id SET o.level = u.level + ?, o.remark = ? WHERE o.status = ?"},
+		{"postgres", "UPDATE orders AS o SET level = u.level + ?, remark = ? FROM users u WHERE o.user_id = u.id AND o.status = ?"},
+		{"sqlserver", "UPDATE o SET o.level = u.level + ?, o.remark = ? FROM orders AS o INNER JOIN users u ON o.user_id = u.id WHERE o.status = ?"},
+	}
+	for _, tt := range tests {
+		sqlStr, args := NewQuery(nil).Dialect(tt.dialect).Table("orders").Alias("o").Where("o.status = ?", 0).
+			BuildUpdateWithJoin("users u", "o.user_id = u.id", set)
+		if sqlStr != tt.want || !reflect.DeepEqual(args, []interface{}{1, "sync", 0}) {
+			t.Errorf("%s: 期望 %q，实际为 %q %v", tt.dialect, tt.want, sqlStr, args)
+		}
+	}
+
+	q := NewQuery(nil).Dialect("oracle").Table("orders")
+	q.BuildUpdateWithJoin("users u", "orders.user_id = u.id", set)
+	if q.Err() == nil {
+		t.Error("Oracle 应返回不支持错误")
+	}
+}
+
+// 测试 INSERT IGNORE 与 ON CONFLICT DO NOTHING
+func TestInsertIgnore(t *testing.T) {
+	values := [][]interface{}{{"go", 1}, {"sql", 2}}
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{"mysql", "INSERT IGNORE INTO tags (name, weight) VALUES (?, ?), (?, ?)"},
+		{"sqlite3", "INSERT INTO tags (name, weight) VALUES (?, ?), (?, ?) ON CONFLICT DO NOTHING"},
+	}
+	for _, tt := range tests {
+		sqlStr, args := NewQuery(nil).Dialect(tt.dialect).Table("tags").BuildInsertIgnore([]string{"name", "weight"}, values)
+		if sqlStr != tt.want || !reflect.DeepEqual(args, []interface{}{"go", 1, "sql", 2}) {
+			t.Errorf("%s: 期望 %q，实际为 %q %v", tt.dialect, tt.want, sqlStr, args)
+		}
+	}
+
+	q := NewQuery(nil).Dialect("mysql").Table("tags")
+	q.BuildInsertIgnore([]string{"name", "weight"}, [][]interface{}{{"go"}})
+	if q.Err() == nil {
+		t.Error("值个数与列数不一致时应返回错误")
+	}
+	q = NewQuery(nil).Dialect("sqlserver").Table("tags")
+	q.BuildInsertIgnore([]string{"name"}, [][]interface{}{{"go"}})
+	if q.Err() == nil {
+		t.Error("SQLServer 应返回不支持错误")
+	}
+}
+
+// 测试 ON DUPLICATE KEY UPDATE 表达式
+func TestInsertOnDuplicate(t *testing.T) {
+	updates := map[string]interface{}{
+		"count":      Raw("count + VALUES(count)"),
+		"updated_at": Raw("NOW()"),
+		"source":     "import",
+		"weight":     Raw("GREATEST(weight, ?)", 5),
+	}
+	sqlStr, args := NewQuery(nil).Dialect("mysql").Table("counters").
+		BuildInsertOnDuplicate([]string{"name", "count"}, [][]interface{}{{"visits", 1}}, updates)
+	want := "INSERT INTO counters (name, count) VALUES (?, ?) ON DUPLICATE KEY UPDATE count = count + VALUES(count), source = ?, updated_at = NOW(), weight = GREATEST(weight, ?)"
+	if sqlStr != want || !reflect.DeepEqual(args, []interface{}{"visits", 1, "import", 5}) {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, args)
+	}
+
+	q := NewQuery(nil).Dialect("postgres").Table("counters")
+	q.BuildInsertOnDuplicate([]string{"name"}, [][]interface{}{{"visits"}}, updates)
+	if q.Err() == nil {
+		t.Error("PostgreSQL 应返回不支持错误")
+	}
+	q = NewQuery(nil).Dialect("mysql").Table("counters")
+	q.BuildInsertOnDuplicate([]string{"name"}, [][]interface{}{{"visits"}}, nil)
+	if q.Err() == nil {
+		t.Error("更新列为空时应返回错误")
+	}
+
+	// gorm.Expr 与 Raw 为同一类型
+	sqlStr, args = NewQuery(nil).Dialect("tidb").Table("counters").
+		BuildInsertOnDuplicate([]string{"name"}, [][]interface{}{{"visits"}}, map[string]interface{}{"count": gorm.Expr("count + ?", 2)})
+	if want := "INSERT INTO counters (name) VALUES (?) ON DUPLICATE KEY UPDATE count = count + ?"; sqlStr != want || !reflect.DeepEqual(args, []interface{}{"visits", 2}) {
+		t.Errorf("期望 %q，实际为 %q %v", want, sqlStr, args)
+	}
+}
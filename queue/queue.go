@@ -0,0 +1,350 @@
+// Package queue 基于数据库表的轻量任务队列
+// 出队使用 FOR UPDATE SKIP LOCKED（Postgres/MySQL 8/Oracle）或 READPAST（SQLServer）避免争用，
+// 其他数据库使用条件更新的乐观方式领取任务。支持可见性超时、失败重试和死信
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 任务状态常量
+const (
+	StatusPending = "pending" // 等待执行
+	StatusRunning = "running" // 执行中
+	StatusDone    = "done"    // 已完成
+	StatusDead    = "dead"    // 超过重试次数
+)
+
+// DefaultTable 默认任务表名
+const DefaultTable = "gosqlx_jobs"
+
+// ErrJobLost 任务已超时被重新投递，当前执行者不再持有该任务
+var ErrJobLost = errors.New("任务已被重新投递")
+
+// Job 任务
+type Job struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement"`
+	Queue       string     `gorm:"column:queue;size:64;not null"`
+	Payload     string     `gorm:"column:payload;type:text"`
+	Status      string     `gorm:"column:status;size:16;not null"`
+	Attempts    int        `gorm:"column:attempts;not null"`
+	MaxAttempts int        `gorm:"column:max_attempts;not null"`
+	RunAt       time.Time  `gorm:"column:run_at;not null"`
+	LockedUntil *time.Time `gorm:"column:locked_until"`
+	LastError   string     `gorm:"column:last_error;size:1024"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at"`
+}
+
+// Decode 将任务内容解析为JSON对象
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(j.Payload), v)
+}
+
+// Options 队列选项
+type Options struct {
+	Table             string                          // 任务表名，默认 gosqlx_jobs
+	VisibilityTimeout time.Duration                   // 可见性超时，超时未确认的任务会重新投递
+	MaxAttempts       int                             // 最大执行次数，超过后进入死信
+	RetryDelay        func(attempt int) time.Duration // 失败重试延迟
+}
+
+// Queue 任务队列
+type Queue struct {
+	db      *gosqlx.Database
+	name    string
+	options Options
+}
+
+// New 创建任务队列，任务表不存在时自动创建
+func New(db *gosqlx.Database, name string, options Options) (*Queue, error) {
+	if options.Table == "" {
+		options.Table = DefaultTable
+	}
+	if options.VisibilityTimeout <= 0 {
+		options.VisibilityTimeout = 30 * time.Second
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 5
+	}
+	if options.RetryDelay == nil {
+		options.RetryDelay = func(attempt int) time.Duration {
+			return time.Duration(attempt*attempt) * time.Second
+		}
+	}
+
+	q := &Queue{db: db, name: name, options: options}
+	if err := q.ensureTable(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Enqueue 添加任务，payload 为字符串或字节时原样保存，其他类型保存为JSON
+func (q *Queue) Enqueue(payload interface{}) (*Job, error) {
+	return q.EnqueueAt(payload, time.Now())
+}
+
+// EnqueueAt 添加延迟任务
+func (q *Queue) EnqueueAt(payload interface{}, runAt time.Time) (*Job, error) {
+	var content string
+	switch v := payload.(type) {
+	case string:
+		content = v
+	case []byte:
+		content = string(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("序列化任务内容失败: %w", err)
+		}
+		content = string(data)
+	}
+
+	job := &Job{
+		Queue:       q.name,
+		Payload:     content,
+		Status:      StatusPending,
+		MaxAttempts: q.options.MaxAttempts,
+		RunAt:       runAt,
+	}
+	if err := q.table(q.db.DB()).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("添加任务失败: %w", err)
+	}
+	return job, nil
+}
+
+// Dequeue 领取一个可执行的任务，没有任务时返回nil
+// 领取后任务在可见性超时内对其他消费者不可见，需调用 Ack 或 Fail
+func (q *Queue) Dequeue() (*Job, error) {
+	for {
+		var job *Job
+		var retry bool
+		err := q.db.DB().Transaction(func(tx *gorm.DB) error {
+			now := time.Now()
+			id, err := q.lockNext(tx, now)
+			if err != nil || id == 0 {
+				return err
+			}
+
+			var candidate Job
+			if err := q.table(tx).Where("id = ?", id).Take(&candidate).Error; err != nil {
+				return err
+			}
+
+			// 上次执行超时且已达到最大次数，进入死信
+			if candidate.Attempts >= candidate.MaxAttempts {
+				retry = true
+				return q.table(tx).Where("id = ? AND attempts = ?", id, candidate.Attempts).
+					Updates(map[string]interface{}{"status": StatusDead, "locked_until": nil, "last_error": "执行超时"}).Error
+			}
+
+			lockedUntil := now.Add(q.options.VisibilityTimeout)
+			result := q.table(tx).
+				Where("id = ? AND attempts = ?", id, candidate.Attempts).
+				Where(q.readyCondition(), StatusPending, now, StatusRunning, now).
+				Updates(map[string]interface{}{
+					"status":       StatusRunning,
+					"attempts":     candidate.Attempts + 1,
+					"locked_until": lockedUntil,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				retry = true // 已被其他消费者领取
+				return nil
+			}
+
+			candidate.Status = StatusRunning
+			candidate.Attempts++
+			candidate.LockedUntil = &lockedUntil
+			job = &candidate
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("领取任务失败: %w", err)
+		}
+		if !retry {
+			return job, nil
+		}
+	}
+}
+
+// Ack 确认任务执行成功
+func (q *Queue) Ack(job *Job) error {
+	return q.finish(job, map[string]interface{}{"status": StatusDone, "locked_until": nil})
+}
+
+// Fail 标记任务执行失败，未超过最大次数时按重试延迟重新排队，否则进入死信
+func (q *Queue) Fail(job *Job, cause error) error {
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+		if len(message) > 1024 {
+			message = message[:1024]
+		}
+	}
+
+	values := map[string]interface{}{"locked_until": nil, "last_error": message}
+	if job.Attempts >= job.MaxAttempts {
+		values["status"] = StatusDead
+	} else {
+		values["status"] = StatusPending
+		values["run_at"] = time.Now().Add(q.options.RetryDelay(job.Attempts))
+	}
+	return q.finish(job, values)
+}
+
+// Extend 延长任务的可见性超时，用于长时间执行的任务
+func (q *Queue) Extend(job *Job, d time.Duration) error {
+	lockedUntil := time.Now().Add(d)
+	if err := q.finish(job, map[string]interface{}{"locked_until": lockedUntil}); err != nil {
+		return err
+	}
+	job.LockedUntil = &lockedUntil
+	return nil
+}
+
+// DeadJobs 查询死信任务
+func (q *Queue) DeadJobs(limit int) ([]Job, error) {
+	var jobs []Job
+	err := q.table(q.db.DB()).Where("queue = ? AND status = ?", q.name, StatusDead).
+		Order("id").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// Requeue 将死信任务重新排队并清空执行次数
+func (q *Queue) Requeue(id int64) error {
+	result := q.table(q.db.DB()).Where("id = ? AND queue = ? AND status = ?", id, q.name, StatusDead).
+		Updates(map[string]interface{}{"status": StatusPending, "attempts": 0, "run_at": time.Now(), "last_error": ""})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("死信任务 %d 不存在", id)
+	}
+	return nil
+}
+
+// Process 循环领取并处理任务，直到 ctx 取消
+// handler 返回nil时确认任务，返回错误时按重试策略处理；无任务时等待 pollInterval
+func (q *Queue) Process(ctx context.Context, pollInterval time.Duration, handler func(ctx context.Context, job *Job) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		job, err := q.Dequeue()
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		if handleErr := handler(ctx, job); handleErr != nil {
+			err = q.Fail(job, handleErr)
+		} else {
+			err = q.Ack(job)
+		}
+		if err != nil && !errors.Is(err, ErrJobLost) {
+			return err
+		}
+	}
+}
+
+// finish 更新当前执行者持有的任务
+func (q *Queue) finish(job *Job, values map[string]interface{}) error {
+	result := q.table(q.db.DB()).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, StatusRunning, job.Attempts).
+		Updates(values)
+	if result.Error != nil {
+		return fmt.Errorf("更新任务失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLost
+	}
+	if status, ok := values["status"].(string); ok {
+		job.Status = status
+	}
+	return nil
+}
+
+// readyCondition 可领取任务的条件：等待中且已到执行时间，或执行中但已超时
+func (q *Queue) readyCondition() string {
+	return "((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?))"
+}
+
+// lockNext 锁定下一个可领取的任务，返回任务ID（0表示没有任务）
+func (q *Queue) lockNext(tx *gorm.DB, now time.Time) (int64, error) {
+	args := []interface{}{q.name, StatusPending, now, StatusRunning, now}
+	where := "queue = ? AND " + q.readyCondition()
+
+	var ids []int64
+	var err error
+	switch q.db.Type() {
+	case gosqlx.PostgresSQL, gosqlx.MySQL, gosqlx.MariaDB, gosqlx.TiDB, gosqlx.OceanBase:
+		err = q.table(tx).Select("id").Where(where, args...).Order("run_at, id").Limit(1).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Scan(&ids).Error
+	case gosqlx.SQLServer:
+		err = tx.Raw(fmt.Sprintf("SELECT TOP 1 id FROM %s WITH (UPDLOCK, READPAST, ROWLOCK) WHERE %s ORDER BY run_at, id",
+			tx.Statement.Quote(q.options.Table), where), args...).Scan(&ids).Error
+	case gosqlx.Oracle:
+		// Oracle 的 FOR UPDATE 不能与分页子句同时使用，先取候选再逐个加锁
+		var candidates []int64
+		if err = q.table(tx).Select("id").Where(where, args...).Order("run_at, id").Limit(10).Scan(&candidates).Error; err != nil {
+			break
+		}
+		for _, id := range candidates {
+			err = q.table(tx).Select("id").Where("id = ? AND "+where, append([]interface{}{id}, args...)...).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Scan(&ids).Error
+			if err != nil || len(ids) > 0 {
+				break
+			}
+		}
+	default:
+		// 不支持行锁的数据库依赖后续的条件更新保证只有一个消费者领取成功
+		err = q.table(tx).Select("id").Where(where, args...).Order("run_at, id").Limit(1).Scan(&ids).Error
+	}
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// ensureTable 创建任务表和索引
+func (q *Queue) ensureTable() error {
+	migrator := q.table(q.db.DB()).Migrator()
+	if migrator.HasTable(q.options.Table) {
+		return nil
+	}
+	if err := migrator.CreateTable(&Job{}); err != nil {
+		return fmt.Errorf("创建任务表失败: %w", err)
+	}
+	db := q.db.DB()
+	index := fmt.Sprintf("CREATE INDEX %s ON %s (queue, status, run_at)",
+		db.Statement.Quote("idx_"+q.options.Table+"_ready"), db.Statement.Quote(q.options.Table))
+	if err := db.Exec(index).Error; err != nil {
+		return fmt.Errorf("创建任务表索引失败: %w", err)
+	}
+	return nil
+}
+
+// table 指定任务表
+func (q *Queue) table(db *gorm.DB) *gorm.DB {
+	return db.Table(q.options.Table)
+}
@@ -0,0 +1,199 @@
+package gosqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gzorm/gosqlx/query"
+	"gorm.io/gorm"
+)
+
+// ==================== 只读模式 ====================
+
+// ErrReadOnly 只读模式（Context.Mode 为 ModeReadOnly）的数据库实例拒绝写操作，
+// 包括 Create/Save/Update/Delete、写语句的 Exec/Raw 和 DDL
+var ErrReadOnly = query.ErrReadOnly
+
+// readStatements 只读模式下允许执行的语句关键字，其余语句均视为写操作
+var readStatements = map[string]bool{
+	"SELECT": true, "WITH": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true,
+	"VALUES": true, "TABLE": true, "PRAGMA": true, "SET": true, "USE": true,
+	"BEGIN": true, "START": true, "COMMIT": true, "ROLLBACK": true, "SAVEPOINT": true, "RELEASE": true,
+}
+
+// writeKeywordRe 匹配 WITH/EXPLAIN 语句中嵌套的写操作，如 PostgreSQL 的可写 CTE、EXPLAIN ANALYZE DELETE
+var writeKeywordRe = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE|UPSERT|REPLACE|TRUNCATE)\b`)
+
+// IsWriteStatement 判断SQL是否为写操作（DML、DDL、存储过程调用等），用于只读模式的检查
+// 按首个关键字判断，开头的注释和括号会被跳过；PRAGMA 带赋值时视为写操作
+func IsWriteStatement(sqlStr string) bool {
+	keyword, rest := leadingKeyword(sqlStr)
+	if keyword == "" {
+		return false
+	}
+	if !readStatements[keyword] {
+		return true
+	}
+	switch keyword {
+	case "WITH", "EXPLAIN":
+		return writeKeywordRe.MatchString(stripLiterals(rest))
+	case "PRAGMA":
+		return strings.Contains(rest, "=")
+	case "SELECT":
+		// SELECT ... INTO 新表（SQLServer、PostgreSQL）
+		return selectIntoRe.MatchString(stripLiterals(rest))
+	}
+	return false
+}
+
+// selectIntoRe 匹配 SELECT ... INTO 表名 FROM，不含 INTO @变量
+var selectIntoRe = regexp.MustCompile(`(?is)\bINTO\s+[^@:\s]\S*\s+FROM\b`)
+
+// leadingKeyword 跳过开头的空白、注释和括号，返回大写的首个关键字及其后的内容
+func leadingKeyword(sqlStr string) (string, string) {
+	s := sqlStr
+	for {
+		s = strings.TrimLeft(s, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(s, "/*"):
+			end := strings.Index(s, "*/")
+			if end < 0 {
+				return "", ""
+			}
+			s = s[end+2:]
+		case strings.HasPrefix(s, "--"), strings.HasPrefix(s, "#"):
+			end := strings.IndexByte(s, '\n')
+			if end < 0 {
+				return "", ""
+			}
+			s = s[end+1:]
+		default:
+			end := 0
+			for end < len(s) && isIdentByte(s[end]) {
+				end++
+			}
+			return strings.ToUpper(s[:end]), s[end:]
+		}
+	}
+}
+
+// stripLiterals 去掉字符串字面量、带引号的标识符和注释，避免其中的文本被当作关键字
+func stripLiterals(sqlStr string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(sqlStr); i++ {
+		c := sqlStr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(' ')
+		case c == '-' && strings.HasPrefix(sqlStr[i:], "--"):
+			end := strings.IndexByte(sqlStr[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end
+			b.WriteByte(' ')
+		case c == '/' && strings.HasPrefix(sqlStr[i:], "/*"):
+			end := strings.Index(sqlStr[i:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 1
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// registerReadOnlyCallbacks 注册只读回调：创建、更新、删除直接拒绝，原生语句按 IsWriteStatement 判断
+func registerReadOnlyCallbacks(db *gorm.DB) error {
+	callback := db.Callback()
+	registers := []func() error{
+		func() error { return callback.Create().Before("*").Register("gosqlx:readonly", rejectWrite("INSERT")) },
+		func() error { return callback.Update().Before("*").Register("gosqlx:readonly", rejectWrite("UPDATE")) },
+		func() error { return callback.Delete().Before("*").Register("gosqlx:readonly", rejectWrite("DELETE")) },
+		func() error { return callback.Raw().Before("*").Register("gosqlx:readonly", rejectWrite("")) },
+		func() error { return callback.Row().Before("*").Register("gosqlx:readonly", rejectWrite("")) },
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return fmt.Errorf("注册只读回调失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// rejectWrite 拒绝写操作，statement 为空时检查已生成的原生SQL
+func rejectWrite(statement string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		keyword := statement
+		if keyword == "" {
+			sqlStr := db.Statement.SQL.String()
+			if !IsWriteStatement(sqlStr) {
+				return
+			}
+			keyword, _ = leadingKeyword(sqlStr)
+		}
+		db.AddError(fmt.Errorf("%w: %s", ErrReadOnly, keyword))
+	}
+}
+
+// checkReadOnly 只读模式下拒绝不经过 GORM 执行的写语句
+func (d *Database) checkReadOnly(sqlStr string) error {
+	if d.ctx == nil || !d.ctx.IsReadOnly() || !IsWriteStatement(sqlStr) {
+		return nil
+	}
+	keyword, _ := leadingKeyword(sqlStr)
+	return fmt.Errorf("%w: %s", ErrReadOnly, keyword)
+}
+
+// readOnlyTxOptions 只读模式下开启事务使用的选项，仅 MySQL 系和 PostgreSQL 的驱动支持只读事务
+func readOnlyTxOptions(dbType DatabaseType) []*sql.TxOptions {
+	switch dbType {
+	case MySQL, MariaDB, TiDB, OceanBase, PostgresSQL:
+		return []*sql.TxOptions{{ReadOnly: true}}
+	}
+	return nil
+}
+
+// readOnlyDSN 为连接字符串加上服务端只读参数：PostgreSQL 设置 default_transaction_read_only，
+// SQLServer 设置 ApplicationIntent=ReadOnly（可路由到 AlwaysOn 只读副本）；已设置或其他数据库时原样返回
+func readOnlyDSN(dbType DatabaseType, dsn string) string {
+	var key, value string
+	switch dbType {
+	case PostgresSQL:
+		key, value = "default_transaction_read_only", "on"
+	case SQLServer:
+		key, value = "ApplicationIntent", "ReadOnly"
+	default:
+		return dsn
+	}
+	if strings.Contains(strings.ToLower(dsn), strings.ToLower(key)) {
+		return dsn
+	}
+
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		q := u.Query()
+		q.Set(key, value)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	if dbType == SQLServer {
+		return strings.TrimRight(dsn, "; ") + ";" + key + "=" + value
+	}
+	return strings.TrimSpace(dsn) + " " + key + "=" + value
+}
@@ -0,0 +1,68 @@
+package gosqlx
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gzorm/gosqlx/adapter"
+	oracle "github.com/seelly/gorm-oracle"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+// DialectFactory 根据连接字符串创建 GORM 方言
+type DialectFactory func(dsn string) gorm.Dialector
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[DatabaseType]DialectFactory{
+		MySQL:       mysql.Open,
+		PostgresSQL: postgres.Open,
+		SQLServer:   sqlserver.Open,
+		SQLite:      sqlite.Open,
+		Oracle:      oracle.Open,
+		// TiDB、MariaDB、OceanBase 使用 MySQL 驱动
+		TiDB:       mysql.Open,
+		MariaDB:    mysql.Open,
+		ClickHouse: clickhouse.Open,
+		OceanBase:  mysql.Open,
+		// DuckDB 需在程序中导入驱动，见 adapter.DuckDBDriverName
+		DuckDB: adapter.OpenDuckDB,
+	}
+)
+
+// RegisterDialect 注册数据库方言，用于接入 DB2、Snowflake、Doris 等内置类型以外的数据库，同名时覆盖
+// 注册后即可通过 Config.Type 使用该类型；如需 BatchInsert/MergeInto/QueryPage，再通过 adapter.Register 注册同名适配器
+//
+//	gosqlx.RegisterDialect("doris", mysql.Open)
+//	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: "doris", Source: dsn})
+func RegisterDialect(name DatabaseType, factory DialectFactory) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[DatabaseType(strings.ToLower(string(name)))] = factory
+}
+
+// lookupDialect 获取已注册的方言工厂
+func lookupDialect(name DatabaseType) (DialectFactory, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	factory, ok := dialects[DatabaseType(strings.ToLower(string(name)))]
+	return factory, ok
+}
+
+// RegisteredDialects 返回已注册的数据库类型（已排序，不含 MongoDB）
+func RegisteredDialects() []DatabaseType {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	names := make([]DatabaseType, 0, len(dialects))
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
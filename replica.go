@@ -0,0 +1,232 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ==================== 只读副本 ====================
+
+// ErrLagUnsupported 数据库类型不支持探测复制延迟，可通过 ReplicaOptions.Probe 自定义探测方式（如心跳表）
+var ErrLagUnsupported = errors.New("不支持探测复制延迟")
+
+// ErrLagUnknown 副本的复制延迟未知，通常是复制线程已停止
+var ErrLagUnknown = errors.New("复制延迟未知")
+
+// LagProbe 复制延迟探测函数，db 为副本的数据库连接
+type LagProbe func(ctx context.Context, db *Database) (time.Duration, error)
+
+// ReplicaOptions 只读副本的延迟探测选项
+type ReplicaOptions struct {
+	Probe    LagProbe      // 延迟探测函数，默认 ProbeReplicaLag
+	Interval time.Duration // 路由时延迟结果的缓存时长，默认 1 秒
+	Timeout  time.Duration // 单次探测的超时时间，默认 1 秒
+}
+
+// withDefaults 填充默认值
+func (o ReplicaOptions) withDefaults() ReplicaOptions {
+	if o.Probe == nil {
+		o.Probe = ProbeReplicaLag
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = time.Second
+	}
+	return o
+}
+
+// ReplicaStatus 副本的复制延迟
+type ReplicaStatus struct {
+	Name      string        // 副本的配置名
+	Lag       time.Duration // 复制延迟
+	CheckedAt time.Time     // 探测时间
+	Err       error         // 探测错误，有错误的副本不参与按延迟路由
+}
+
+// replicaState 管理器中的副本探测状态
+type replicaState struct {
+	mutex   sync.Mutex
+	options ReplicaOptions
+	lags    map[string]ReplicaStatus
+}
+
+// SetReplicaOptions 设置只读副本的延迟探测选项
+func (m *DatabaseManager) SetReplicaOptions(opts ReplicaOptions) {
+	m.lag.mutex.Lock()
+	defer m.lag.mutex.Unlock()
+	m.lag.options = opts.withDefaults()
+	m.lag.lags = nil
+}
+
+// ReplicaLag 探测数据库别名所有只读副本的复制延迟，副本按配置名 <nick>_readonly、<nick>_readonly_1、<nick>_readonly_2... 查找
+func (m *DatabaseManager) ReplicaLag(nick string) ([]ReplicaStatus, error) {
+	env := "development"
+	names := m.replicas(env, nick)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("数据库 %s 没有配置只读副本", nick)
+	}
+	ctx := NewContext(context.Background(), nick, ModeReadOnly)
+	statuses := make([]ReplicaStatus, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, m.probe(ctx, env, name))
+	}
+	return statuses, nil
+}
+
+// replicas 返回数据库别名的只读副本配置名
+func (m *DatabaseManager) replicas(env, nick string) []string {
+	var names []string
+	if _, ok := m.configManager.GetConfig(env, nick+"_readonly"); ok {
+		names = append(names, nick+"_readonly")
+	}
+	for i := 1; ; i++ {
+		name := nick + "_readonly_" + strconv.Itoa(i)
+		if _, ok := m.configManager.GetConfig(env, name); !ok {
+			break
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// routeReadOnly 选择只读模式使用的配置名：未设置 MaxStaleness 时使用第一个副本，
+// 否则使用第一个延迟不超过 MaxStaleness 的副本，没有副本或都超过时使用主库
+func (m *DatabaseManager) routeReadOnly(ctx *Context, env string) string {
+	names := m.replicas(env, ctx.Nick)
+	if len(names) == 0 {
+		return ctx.Nick
+	}
+	if ctx.MaxStaleness <= 0 {
+		return names[0]
+	}
+	for _, name := range names {
+		if status := m.cachedLag(ctx, env, name); status.Err == nil && status.Lag <= ctx.MaxStaleness {
+			return name
+		}
+	}
+	return ctx.Nick
+}
+
+// cachedLag 返回缓存的副本延迟，超过缓存时长时重新探测
+func (m *DatabaseManager) cachedLag(ctx *Context, env, name string) ReplicaStatus {
+	m.lag.mutex.Lock()
+	status, ok := m.lag.lags[name]
+	interval := m.lag.options.withDefaults().Interval
+	m.lag.mutex.Unlock()
+	if ok && time.Since(status.CheckedAt) < interval {
+		return status
+	}
+	return m.probe(ctx, env, name)
+}
+
+// probe 探测副本延迟并更新缓存
+func (m *DatabaseManager) probe(ctx *Context, env, name string) ReplicaStatus {
+	m.lag.mutex.Lock()
+	options := m.lag.options.withDefaults()
+	m.lag.mutex.Unlock()
+
+	status := ReplicaStatus{Name: name}
+	db, err := m.open(ctx, env, name)
+	if err == nil {
+		probeCtx, cancel := context.WithTimeout(ctx.parent(), options.Timeout)
+		status.Lag, err = options.Probe(probeCtx, db)
+		cancel()
+	}
+	status.CheckedAt = time.Now()
+	if err != nil {
+		status.Err = fmt.Errorf("探测副本 %s 的复制延迟失败: %w", name, err)
+	}
+
+	m.lag.mutex.Lock()
+	if m.lag.lags == nil {
+		m.lag.lags = make(map[string]ReplicaStatus)
+	}
+	m.lag.lags[name] = status
+	m.lag.mutex.Unlock()
+	return status
+}
+
+// ProbeReplicaLag 默认的复制延迟探测：MySQL/MariaDB 读取 SHOW REPLICA STATUS（旧版本为 SHOW SLAVE STATUS）的 Seconds_Behind_Source，
+// PostgreSQL 在回放位置落后于接收位置时按最后回放事务的时间计算；不是副本时延迟为 0
+func ProbeReplicaLag(ctx context.Context, db *Database) (time.Duration, error) {
+	switch db.Type() {
+	case MySQL, MariaDB:
+		return mysqlReplicaLag(ctx, db.sqlDB)
+	case PostgresSQL:
+		return postgresReplicaLag(ctx, db.sqlDB)
+	}
+	return 0, fmt.Errorf("%w: %s", ErrLagUnsupported, db.Type())
+}
+
+// mysqlReplicaLag 读取 MySQL 复制状态，多源复制时取最大延迟
+func mysqlReplicaLag(ctx context.Context, sqlDB *sql.DB) (time.Duration, error) {
+	rows, err := sqlDB.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		// MySQL 8.0.22、MariaDB 10.5.1 之前的版本
+		if rows, err = sqlDB.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	lagColumn := -1
+	for i, column := range columns {
+		if column == "Seconds_Behind_Source" || column == "Seconds_Behind_Master" {
+			lagColumn = i
+		}
+	}
+	if lagColumn < 0 {
+		return 0, errors.New("复制状态中没有 Seconds_Behind_Source 列")
+	}
+
+	var lag time.Duration
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		if !values[lagColumn].Valid {
+			return 0, ErrLagUnknown
+		}
+		seconds, err := strconv.ParseInt(values[lagColumn].String, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("解析复制延迟失败: %w", err)
+		}
+		lag = max(lag, time.Duration(seconds)*time.Second)
+	}
+	return lag, rows.Err()
+}
+
+// postgresLagSQL PostgreSQL 副本的复制延迟（秒），主库返回 0，尚未回放任何事务时为 NULL
+const postgresLagSQL = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+END`
+
+// postgresReplicaLag 读取 PostgreSQL 副本的复制延迟
+func postgresReplicaLag(ctx context.Context, sqlDB *sql.DB) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	if err := sqlDB.QueryRowContext(ctx, postgresLagSQL).Scan(&seconds); err != nil {
+		return 0, err
+	}
+	if !seconds.Valid {
+		return 0, ErrLagUnknown
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
@@ -0,0 +1,231 @@
+// Package retention 数据保留策略，按时间列分批删除（或归档后删除）过期的行
+// 每批先按主键查出过期行再按主键删除，单条语句影响的行数可控，批次间可休眠，避免长事务、主从延迟和过大的 binlog
+//
+//	runner := retention.New(db, retention.Options{BatchSize: 1000, Sleep: 200 * time.Millisecond})
+//	runner.Register(retention.Policy{Table: "login_logs", Column: "created_at", Retain: retention.Days(90)})
+//	results, err := runner.Run(ctx)
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Days 返回 n 天的时长
+func Days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// Policy 表的保留策略：Column 早于 当前时间-Retain 的行过期
+type Policy struct {
+	Table  string        // 表名
+	Column string        // 时间列
+	Retain time.Duration // 保留时长
+	Key    string        // 主键列，用于分批删除，默认 id
+	Unix   bool          // 时间列为 Unix 秒时间戳
+	Where  string        // 额外条件，如 "status = ?"
+	Args   []interface{} // 额外条件的参数
+
+	// Archive 删除前归档过期行，与删除在同一事务中执行，返回错误时该批不删除
+	Archive func(ctx context.Context, tx *gorm.DB, rows []map[string]interface{}) error
+}
+
+// Options 执行选项
+type Options struct {
+	BatchSize  int           // 每批删除的行数，默认 1000
+	Sleep      time.Duration // 批次间的休眠时间
+	MaxBatches int           // 每次运行每个策略最多执行的批数，0 表示不限制
+	DryRun     bool          // 只统计过期行数，不删除
+}
+
+// Result 单个策略的执行结果
+type Result struct {
+	Table   string    // 表名
+	Cutoff  time.Time // 过期时间点
+	Deleted int64     // 删除的行数，DryRun 时为过期的行数
+	Batches int       // 执行的批数
+	Err     error     // 执行错误
+}
+
+// Runner 保留策略执行器
+type Runner struct {
+	db       *gosqlx.Database
+	options  Options
+	mutex    sync.Mutex
+	policies []Policy
+}
+
+// New 创建保留策略执行器
+func New(db *gosqlx.Database, options Options) *Runner {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	return &Runner{db: db, options: options}
+}
+
+// Register 注册表的保留策略
+func (r *Runner) Register(policy Policy) error {
+	if r.db.Type() == gosqlx.MongoDB {
+		return errors.New("MongoDB 请使用 TTL 索引")
+	}
+	if policy.Table == "" || policy.Column == "" {
+		return errors.New("保留策略缺少表名或时间列")
+	}
+	if policy.Retain <= 0 {
+		return fmt.Errorf("表 %s 的保留时长必须大于0", policy.Table)
+	}
+	if policy.Key == "" {
+		policy.Key = "id"
+	}
+	r.mutex.Lock()
+	r.policies = append(r.policies, policy)
+	r.mutex.Unlock()
+	return nil
+}
+
+// Policies 返回已注册的策略
+func (r *Runner) Policies() []Policy {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Policy(nil), r.policies...)
+}
+
+// Run 依次执行所有策略，单个策略失败不影响其他策略，返回的错误合并了各策略的错误
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+	now := time.Now()
+	var (
+		results []Result
+		errs    []error
+	)
+	for _, policy := range r.Policies() {
+		result := r.apply(ctx, policy, now)
+		results = append(results, result)
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Start 每隔 interval 执行一次 Run，直到 ctx 取消；onResult 不为nil时接收每次的执行结果
+func (r *Runner) Start(ctx context.Context, interval time.Duration, onResult func([]Result, error)) error {
+	for {
+		results, err := r.Run(ctx)
+		if onResult != nil {
+			onResult(results, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// apply 分批执行单个策略
+func (r *Runner) apply(ctx context.Context, policy Policy, now time.Time) Result {
+	result := Result{Table: policy.Table, Cutoff: now.Add(-policy.Retain)}
+	if r.options.DryRun {
+		result.Deleted, result.Err = r.count(ctx, policy, result.Cutoff)
+		return result
+	}
+
+	for r.options.MaxBatches <= 0 || result.Batches < r.options.MaxBatches {
+		if err := ctx.Err(); err != nil {
+			result.Err = err
+			break
+		}
+		selected, deleted, err := r.batch(ctx, policy, result.Cutoff)
+		if err != nil {
+			result.Err = fmt.Errorf("清理表 %s 失败: %w", policy.Table, err)
+			break
+		}
+		result.Batches++
+		result.Deleted += deleted
+		if selected < r.options.BatchSize {
+			break
+		}
+		if r.options.Sleep > 0 {
+			select {
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return result
+			case <-time.After(r.options.Sleep):
+			}
+		}
+	}
+	return result
+}
+
+// batch 删除一批过期行，返回查出的过期行数和删除的行数
+func (r *Runner) batch(ctx context.Context, policy Policy, cutoff time.Time) (int, int64, error) {
+	var (
+		selected int
+		deleted  int64
+	)
+	err := r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var keys []interface{}
+		if err := r.expired(tx, policy, cutoff).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: policy.Key}}).
+			Limit(r.options.BatchSize).
+			Pluck(policy.Key, &keys).Error; err != nil {
+			return fmt.Errorf("查询过期数据失败: %w", err)
+		}
+		selected = len(keys)
+		if selected == 0 {
+			return nil
+		}
+
+		if policy.Archive != nil {
+			var rows []map[string]interface{}
+			if err := tx.Table(policy.Table).Where(clause.IN{Column: clause.Column{Name: policy.Key}, Values: keys}).
+				Find(&rows).Error; err != nil {
+				return fmt.Errorf("查询归档数据失败: %w", err)
+			}
+			if err := policy.Archive(ctx, tx, rows); err != nil {
+				return fmt.Errorf("归档失败: %w", err)
+			}
+		}
+
+		result := tx.Exec("DELETE FROM ? WHERE ?", clause.Table{Name: policy.Table},
+			clause.IN{Column: clause.Column{Name: policy.Key}, Values: keys})
+		if result.Error != nil {
+			return fmt.Errorf("删除过期数据失败: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	return selected, deleted, err
+}
+
+// count 统计过期行数
+func (r *Runner) count(ctx context.Context, policy Policy, cutoff time.Time) (int64, error) {
+	var total int64
+	if err := r.expired(r.db.DB().WithContext(ctx), policy, cutoff).Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("统计表 %s 的过期数据失败: %w", policy.Table, err)
+	}
+	return total, nil
+}
+
+// expired 过期行的查询条件
+func (r *Runner) expired(db *gorm.DB, policy Policy, cutoff time.Time) *gorm.DB {
+	var value interface{} = cutoff
+	if policy.Unix {
+		value = cutoff.Unix()
+	}
+	query := db.Table(policy.Table).Where(clause.Lt{Column: clause.Column{Name: policy.Column}, Value: value})
+	if policy.Where != "" {
+		query = query.Where(policy.Where, policy.Args...)
+	}
+	return query
+}
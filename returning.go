@@ -0,0 +1,253 @@
+package gosqlx
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gzorm/gosqlx/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// ==================== 插入并返回 ====================
+
+// 插入语句返回结果行的方式
+const (
+	returningNone   = iota // 不支持，使用驱动返回的 LastInsertId
+	returningClause        // INSERT ... RETURNING
+	returningOutput        // INSERT ... OUTPUT INSERTED.*
+)
+
+// InsertReturningID 插入一行并返回 idColumn（自增主键）的值
+// PostgreSQL、DuckDB、MariaDB 10.5+、SQLite 3.35+ 使用 RETURNING，SQLServer 使用 OUTPUT INSERTED，
+// 其他数据库（MySQL、TiDB、OceanBase、较早的 MariaDB 和 SQLite 等）使用驱动返回的 LastInsertId
+//
+//	id, err := db.InsertReturningID("users", []string{"name", "age"}, []interface{}{"Tom", 18}, "id")
+func (d *Database) InsertReturningID(table string, columns []string, values []interface{}, idColumn string) (int64, error) {
+	if err := checkInsertRow(table, columns, values); err != nil {
+		return 0, err
+	}
+	if idColumn == "" {
+		return 0, errors.New("主键列不能为空")
+	}
+	var id int64
+	switch style := d.returningStyle(); style {
+	case returningNone:
+		result, err := d.execInsert(d.db, table, columns, values)
+		if err != nil {
+			return 0, err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return 0, fmt.Errorf("获取插入的主键失败: %w", err)
+		}
+		return id, nil
+	default:
+		sqlStr := insertReturningSQL(d.db, style, table, columns, []string{idColumn})
+		if err := d.db.Raw(sqlStr, values...).Scan(&id).Error; err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+}
+
+// InsertReturning 插入一行并将 returning 列（为空时为所有列）读取到 out（结构体指针或 map[string]interface{}）
+// 支持 RETURNING/OUTPUT 的数据库单条语句完成，其他数据库在事务中插入后按 LastInsertId 查询 idColumn 对应的行，
+// 主键不是自增列时使用 values 中 idColumn 的值，可以读取到默认值、触发器和生成列的结果
+//
+//	var user User
+//	err := db.InsertReturning(&user, "users", []string{"name"}, []interface{}{"Tom"}, "id", "id", "name", "created_at")
+func (d *Database) InsertReturning(out interface{}, table string, columns []string, values []interface{}, idColumn string, returning ...string) error {
+	if err := checkInsertRow(table, columns, values); err != nil {
+		return err
+	}
+	if style := d.returningStyle(); style != returningNone {
+		return d.db.Raw(insertReturningSQL(d.db, style, table, columns, returning), values...).Scan(out).Error
+	}
+
+	if idColumn == "" {
+		return errors.New("主键列不能为空")
+	}
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		result, err := d.execInsert(tx, table, columns, values)
+		if err != nil {
+			return err
+		}
+		var id interface{}
+		if lastID, err := result.LastInsertId(); err == nil && lastID != 0 {
+			id = lastID
+		}
+		for i, column := range columns {
+			if id == nil && strings.EqualFold(column, idColumn) {
+				id = values[i]
+			}
+		}
+		if id == nil {
+			return fmt.Errorf("无法获取插入行的 %s", idColumn)
+		}
+		selected := "*"
+		if len(returning) > 0 {
+			selected = strings.Join(quoteColumns(tx, returning), ", ")
+		}
+		sqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", selected, tx.Statement.Quote(table), tx.Statement.Quote(idColumn))
+		return tx.Raw(sqlStr, id).Scan(out).Error
+	})
+}
+
+// returningStyle 当前数据库插入语句返回结果行的方式，取自 Capabilities 的 Returning 和 OutputClause
+func (d *Database) returningStyle() int {
+	switch caps := d.Capabilities(); {
+	case !caps.Returning:
+		return returningNone
+	case caps.OutputClause:
+		return returningOutput
+	}
+	return returningClause
+}
+
+// insertReturningSQL 生成返回 returning 列的单行 INSERT 语句
+func insertReturningSQL(tx *gorm.DB, style int, table string, columns, returning []string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	target := fmt.Sprintf("%s (%s)", tx.Statement.Quote(table), strings.Join(quoteColumns(tx, columns), ", "))
+	if style == returningOutput {
+		output := "INSERTED.*"
+		if len(returning) > 0 {
+			prefixed := make([]string, len(returning))
+			for i, column := range quoteColumns(tx, returning) {
+				prefixed[i] = "INSERTED." + column
+			}
+			output = strings.Join(prefixed, ", ")
+		}
+		return fmt.Sprintf("INSERT INTO %s OUTPUT %s VALUES (%s)", target, output, placeholders)
+	}
+	selected := "*"
+	if len(returning) > 0 {
+		selected = strings.Join(quoteColumns(tx, returning), ", ")
+	}
+	return fmt.Sprintf("INSERT INTO %s VALUES (%s) RETURNING %s", target, placeholders, selected)
+}
+
+// execInsert 在 tx 的连接（事务中为事务连接）上执行单行 INSERT，返回驱动的执行结果
+func (d *Database) execInsert(tx *gorm.DB, table string, columns []string, values []interface{}) (sql.Result, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	sqlStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tx.Statement.Quote(table), strings.Join(quoteColumns(tx, columns), ", "), placeholders)
+	if err := d.checkReadOnly(sqlStr); err != nil {
+		return nil, err
+	}
+	if err := d.checkPolicy(sqlStr); err != nil {
+		return nil, err
+	}
+	values, err := d.bindValues(values)
+	if err != nil {
+		return nil, err
+	}
+	if d.dryRun != nil {
+		return d.execDryRun(d.commented(tx.Statement.Context, sqlStr), values), nil
+	}
+	ctx, cancel := d.statementContext()
+	defer cancel()
+	release, err := d.throttle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	result, err := tx.Statement.ConnPool.ExecContext(ctx, d.commented(ctx, d.Rebind(sqlStr)), values...)
+	return result, query.TimeoutError(ctx, err)
+}
+
+// checkInsertRow 检查单行插入的表名、列和值
+func checkInsertRow(table string, columns []string, values []interface{}) error {
+	if table == "" || len(columns) == 0 {
+		return errors.New("插入的表名和列不能为空")
+	}
+	if len(values) != len(columns) {
+		return fmt.Errorf("值个数 %d 与列数 %d 不一致", len(values), len(columns))
+	}
+	return nil
+}
+
+// ==================== 插入后刷新 ====================
+
+// WithRefresh 返回插入后刷新数据库生成值的数据库实例：Create、CreateInBatches 之后将模型所有列的实际值读回结构体，
+// 包括默认值、触发器、生成列和 CURRENT_TIMESTAMP 等由数据库填充的列
+// 支持 RETURNING 的数据库（PostgreSQL、SQLite 3.35+、MariaDB 10.5+）在插入语句中返回所有列，
+// 其他数据库（SQLServer 的 OUTPUT 只返回带 default 标签的列）插入后按主键重新查询；
+// GORM 会写入可写字段的零值，由数据库默认值、生成列填充的字段应加上 -> 只读标签（或带 default 标签）
+//
+//	err := db.WithRefresh().Create(&order) // order.CreatedAt、order.Status 为数据库中的值
+func (d *Database) WithRefresh() *Database {
+	s := d.session(d.db.WithContext(d.db.Statement.Context))
+	s.refresh = true
+	return s
+}
+
+// create 执行 GORM 插入，开启 WithRefresh 时读回数据库填充的列
+func (d *Database) create(value interface{}, insert func(db *gorm.DB) error) error {
+	if !d.refresh {
+		return insert(d.db)
+	}
+	stmt := &gorm.Statement{DB: d.db}
+	if err := stmt.Parse(value); err != nil {
+		return fmt.Errorf("解析模型失败: %w", err)
+	}
+	if utils.Contains(d.db.Callback().Create().Clauses, "RETURNING") {
+		columns := make([]clause.Column, len(stmt.Schema.DBNames))
+		for i, name := range stmt.Schema.DBNames {
+			columns[i] = clause.Column{Name: name}
+		}
+		return insert(d.db.Clauses(clause.Returning{Columns: columns}))
+	}
+
+	if err := insert(d.db); err != nil {
+		return err
+	}
+	if d.dryRun != nil {
+		return nil
+	}
+	return d.reload(stmt.Schema, value)
+}
+
+// reload 按主键重新查询插入的每一行，覆盖结构体中的值
+func (d *Database) reload(sch *schema.Schema, value interface{}) error {
+	if len(sch.PrimaryFields) == 0 {
+		return fmt.Errorf("模型 %s 没有主键，无法刷新插入的行", sch.Name)
+	}
+	ctx := d.db.Statement.Context
+	for _, row := range insertedRows(reflect.ValueOf(value)) {
+		for _, field := range sch.PrimaryFields {
+			if _, zero := field.ValueOf(ctx, row.Elem()); zero {
+				return fmt.Errorf("插入的行没有 %s 的值，无法刷新", field.Name)
+			}
+		}
+		if err := d.db.Session(&gorm.Session{NewDB: true}).Take(row.Interface()).Error; err != nil {
+			return fmt.Errorf("刷新插入的行失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertedRows 返回插入的结构体、结构体切片或数组中每一行的指针
+func insertedRows(value reflect.Value) []reflect.Value {
+	for value.Kind() == reflect.Ptr && value.Elem().Kind() != reflect.Struct {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		return []reflect.Value{value}
+	case reflect.Slice, reflect.Array:
+		rows := make([]reflect.Value, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			rows = append(rows, insertedRows(value.Index(i))...)
+		}
+		return rows
+	case reflect.Struct:
+		if value.CanAddr() {
+			return []reflect.Value{value.Addr()}
+		}
+	}
+	return nil
+}
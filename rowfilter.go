@@ -0,0 +1,117 @@
+package gosqlx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gzorm/gosqlx/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 强制过滤条件 ====================
+
+// RowFilter 表的强制过滤条件，按语句的上下文生成，condition 为空表示不过滤，返回错误时拒绝执行语句
+type RowFilter = query.RowFilter
+
+// RegisterRowFilter 为表注册强制过滤条件（如租户隔离、软删除），table 为 "*" 时对所有表生效
+// GORM 的查询、更新、删除（含 Count、Preload）和查询构建器的 SELECT 自动追加条件；
+// 条件中的列不带表名，联表时有歧义的列需在条件中写明表名；Raw/Exec 等原生SQL和 INSERT 不受影响:
+//
+//	gosqlx.RegisterRowFilter("orders", func(ctx context.Context) (string, []interface{}, error) {
+//		tenantID, ok := ctx.Value(tenantKey{}).(int64)
+//		if !ok {
+//			return "", nil, errors.New("缺少租户ID")
+//		}
+//		return "tenant_id = ?", []interface{}{tenantID}, nil
+//	})
+func RegisterRowFilter(table string, filter RowFilter) {
+	query.RegisterRowFilter(table, filter)
+}
+
+// ClearRowFilters 删除表的所有强制过滤条件，不传表名时删除全部
+func ClearRowFilters(tables ...string) {
+	query.ClearRowFilters(tables...)
+}
+
+// WithoutRowFilters 返回跳过强制过滤条件的上下文，不传表名时跳过所有表
+func WithoutRowFilters(ctx context.Context, tables ...string) context.Context {
+	return query.WithoutRowFilters(ctx, tables...)
+}
+
+// WithoutRowFilters 返回跳过强制过滤条件的数据库实例，不传表名时跳过所有表，用于后台任务和跨租户的管理操作
+//
+//	db.WithoutRowFilters("orders").Where("created_at < ?", cutoff).Delete(&Order{})
+func (d *Database) WithoutRowFilters(tables ...string) *Database {
+	return d.session(d.db.WithContext(query.WithoutRowFilters(d.db.Statement.Context, tables...)))
+}
+
+// rowFilterKey 待追加的强制过滤条件在 Settings 中的键
+const rowFilterKey = "gosqlx:row_filter"
+
+// registerRowFilterCallbacks 注册强制过滤条件回调
+// 已有 WHERE 的语句在生成 WHERE 时将原条件整体加括号后追加，避免与 OR 条件的优先级混淆；
+// 没有 WHERE 的更新和删除同样留到生成时追加，GORM 按主键补充条件或因缺少条件拒绝执行的行为不变
+func registerRowFilterCallbacks(db *gorm.DB) error {
+	build, custom := db.ClauseBuilders["WHERE"]
+	db.ClauseBuilders["WHERE"] = func(c clause.Clause, builder clause.Builder) {
+		if stmt, ok := builder.(*gorm.Statement); ok {
+			if filter, ok := stmt.Settings.LoadAndDelete(rowFilterKey); ok {
+				if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+					c.Expression = clause.Where{Exprs: []clause.Expression{clause.And(where.Exprs...), filter.(clause.Expression)}}
+				}
+			}
+		}
+		if custom {
+			build(c, builder)
+			return
+		}
+		c.Build(builder)
+	}
+
+	callback := db.Callback()
+	registers := []func() error{
+		func() error {
+			return callback.Query().Before("gorm:query").Register("gosqlx:row_filter", rowFilterCallback(false))
+		},
+		func() error {
+			return callback.Update().Before("gorm:update").Register("gosqlx:row_filter", rowFilterCallback(true))
+		},
+		func() error {
+			return callback.Delete().Before("gorm:delete").Register("gosqlx:row_filter", rowFilterCallback(true))
+		},
+		func() error {
+			return callback.Row().Before("gorm:row").Register("gosqlx:row_filter", rowFilterCallback(false))
+		},
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return fmt.Errorf("注册强制过滤条件回调失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// rowFilterCallback 为语句的表追加强制过滤条件，write 表示更新和删除
+func rowFilterCallback(write bool) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		stmt := db.Statement
+		if db.Error != nil || stmt.SQL.Len() > 0 || stmt.Table == "" {
+			return
+		}
+		condition, args, err := query.RowFilterCondition(stmt.Context, stmt.Table)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if condition == "" {
+			return
+		}
+		filter := clause.Expr{SQL: condition, Vars: args}
+		if _, ok := stmt.Clauses["WHERE"]; !ok && (!write || db.AllowGlobalUpdate) {
+			stmt.AddClause(clause.Where{Exprs: []clause.Expression{filter}})
+			return
+		}
+		stmt.Settings.Store(rowFilterKey, filter)
+	}
+}
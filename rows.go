@@ -0,0 +1,233 @@
+package gosqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gzorm/gosqlx/query"
+)
+
+// ==================== 动态结果扫描 ====================
+
+// QueryMaps 执行查询，每行转换为 列名->值 的map，适用于没有对应结构体的动态查询
+// []byte 类型的值会转换为字符串
+func (d *Database) QueryMaps(sqlStr string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := d.Query(sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+	return ScanMaps(rows)
+}
+
+// ScanIndexed 执行查询，以 keyColumn 列的值为键将结果写入 dest，省去查询后遍历切片建索引
+// dest 为 map[K]T、map[K]*T 或按键分组的 map[K][]T（及其指针），规则见 query.ScanIndexed
+//
+//	users := make(map[int64]User)
+//	err := db.ScanIndexed(users, "id", "SELECT * FROM users WHERE id IN ?", ids)
+//
+//	var names map[int64]string
+//	err = db.ScanIndexed(&names, "id", "SELECT id, name FROM users")
+func (d *Database) ScanIndexed(dest interface{}, keyColumn string, sqlStr string, args ...interface{}) error {
+	rows, err := d.Query(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+	return query.ScanIndexed(rows, dest, keyColumn)
+}
+
+// QueryStrings 执行查询，返回字符串形式的结果，第一行为列名，NULL 转换为空字符串
+// 适用于管理工具展示和 CSV 导出
+func (d *Database) QueryStrings(sqlStr string, args ...interface{}) ([][]string, error) {
+	rows, err := d.Query(sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+	return ScanStrings(rows)
+}
+
+// QueryInto 执行查询，结果按列顺序写入 dest 并返回，dest 中已有的行切片会被复用
+// 适合反复读取大量小行的热点接口，配合 GetRowBuffer/PutRowBuffer 避免每次请求重新分配
+//
+//	buf := gosqlx.GetRowBuffer()
+//	defer gosqlx.PutRowBuffer(buf)
+//	buf, err := db.QueryInto(buf, "SELECT id, price FROM quotes WHERE day = ?", day)
+func (d *Database) QueryInto(dest [][]interface{}, sqlStr string, args ...interface{}) ([][]interface{}, error) {
+	rows, err := d.Query(sqlStr, args...)
+	if err != nil {
+		return dest[:0], fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+	return ScanInto(rows, dest)
+}
+
+// QueryRawBytes 执行查询，对每行以 sql.RawBytes 调用 fn，不复制、不装箱
+// values 引用驱动的缓冲区，仅在本次回调内有效，需要保留时自行复制；NULL 为 nil
+func (d *Database) QueryRawBytes(fn func(values []sql.RawBytes) error, sqlStr string, args ...interface{}) error {
+	rows, err := d.Query(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("查询失败: %w", err)
+	}
+	defer rows.Close()
+	return ScanRawBytes(rows, fn)
+}
+
+// rowBuffers 行缓冲池
+var rowBuffers = sync.Pool{New: func() interface{} { return new([][]interface{}) }}
+
+// GetRowBuffer 从池中取出行缓冲，用于 ScanInto/QueryInto 的 dest
+func GetRowBuffer() [][]interface{} {
+	return (*rowBuffers.Get().(*[][]interface{}))[:0]
+}
+
+// PutRowBuffer 将行缓冲放回池中，之后不能再使用其中的行
+func PutRowBuffer(buf [][]interface{}) {
+	for _, row := range buf[:cap(buf)] {
+		clear(row[:cap(row)])
+	}
+	buf = buf[:0]
+	rowBuffers.Put(&buf)
+}
+
+// ScanInto 将结果集的所有行按列顺序扫描到 dest 并返回
+// dest 的长度重置为 0 后追加，容量足够的行切片直接复用；值为驱动返回的原始类型（[]byte 不转换为字符串），NULL 为 nil
+func ScanInto(rows *sql.Rows, dest [][]interface{}) ([][]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return dest[:0], err
+	}
+	dest = dest[:0]
+	targets := make([]interface{}, len(columns))
+	for rows.Next() {
+		var row []interface{}
+		if len(dest) < cap(dest) {
+			row = dest[:len(dest)+1][len(dest)]
+		}
+		if cap(row) < len(columns) {
+			row = make([]interface{}, len(columns))
+		}
+		row = row[:len(columns)]
+		for i := range row {
+			targets[i] = &row[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return dest, err
+		}
+		dest = append(dest, row)
+	}
+	return dest, rows.Err()
+}
+
+// ScanRawBytes 遍历结果集，对每行以 sql.RawBytes 调用 fn，fn 返回错误时停止
+// values 在各行之间复用，仅在本次回调内有效
+func ScanRawBytes(rows *sql.Rows, fn func(values []sql.RawBytes) error) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]sql.RawBytes, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ScanMaps 将结果集的所有行扫描为map
+func ScanMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values, err := scanValues(rows, len(columns))
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// ScanStrings 将结果集扫描为字符串表格，第一行为列名
+func ScanStrings(rows *sql.Rows) ([][]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := [][]string{columns}
+	for rows.Next() {
+		values, err := scanValues(rows, len(columns))
+		if err != nil {
+			return nil, err
+		}
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = FormatValue(value)
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// FormatValue 将数据库返回的值格式化为字符串，NULL 返回空字符串，时间使用 RFC3339 格式
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// scanValues 扫描当前行的所有列，[]byte 转换为字符串
+func scanValues(rows *sql.Rows, count int) ([]interface{}, error) {
+	values := make([]interface{}, count)
+	targets := make([]interface{}, count)
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			values[i] = string(b)
+		}
+	}
+	return values, nil
+}
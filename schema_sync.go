@@ -0,0 +1,190 @@
+package gosqlx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gzorm/gosqlx/adapter"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ==================== 表结构同步 ====================
+
+// SyncOptions 表结构同步选项
+type SyncOptions struct {
+	DryRun           bool // 仅生成SQL，不执行
+	AllowDestructive bool // 允许破坏性变更（删除模型中不存在的列）
+	SkipIndexes      bool // 跳过索引同步
+}
+
+// SyncResult 表结构同步结果
+type SyncResult struct {
+	Statements []string // 已执行（DryRun时为待执行）的SQL
+	Skipped    []string // 因禁止破坏性变更而跳过的SQL
+}
+
+// SyncSchema 根据结构体标签同步表结构
+// 创建缺失的表、列和索引；删除多余列需开启 AllowDestructive，否则记录在 Skipped 中
+// 示例: db.SyncSchema(gosqlx.SyncOptions{DryRun: true}, &User{}, &Article{})
+func (d *Database) SyncSchema(opts SyncOptions, models ...interface{}) (*SyncResult, error) {
+	result := &SyncResult{}
+	migrator := d.db.Migrator()
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: d.db}
+		if err := stmt.Parse(model); err != nil {
+			return result, fmt.Errorf("解析模型失败: %w", err)
+		}
+		sch := stmt.Schema
+
+		var statements, skipped []string
+		if !migrator.HasTable(model) {
+			statements = append(statements, d.createTableSQL(stmt))
+			if !opts.SkipIndexes {
+				for _, idx := range sch.ParseIndexes() {
+					statements = append(statements, d.createIndexSQL(stmt, idx))
+				}
+			}
+		} else {
+			columnTypes, err := migrator.ColumnTypes(model)
+			if err != nil {
+				return result, fmt.Errorf("获取表 %s 列信息失败: %w", sch.Table, err)
+			}
+			existing := make(map[string]bool, len(columnTypes))
+			for _, columnType := range columnTypes {
+				existing[strings.ToLower(columnType.Name())] = true
+			}
+
+			// 添加缺失的列
+			for _, dbName := range sch.DBNames {
+				field := sch.FieldsByDBName[dbName]
+				if field.IgnoreMigration || existing[strings.ToLower(dbName)] {
+					continue
+				}
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD %s %s",
+					stmt.Quote(sch.Table), stmt.Quote(dbName), migrator.FullDataTypeOf(field).SQL))
+			}
+
+			// 删除多余的列
+			for _, columnType := range columnTypes {
+				if sch.LookUpField(columnType.Name()) != nil {
+					continue
+				}
+				dropSQL := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", stmt.Quote(sch.Table), stmt.Quote(columnType.Name()))
+				if opts.AllowDestructive {
+					statements = append(statements, dropSQL)
+				} else {
+					skipped = append(skipped, dropSQL)
+				}
+			}
+
+			// 添加缺失的索引
+			if !opts.SkipIndexes {
+				for _, idx := range sch.ParseIndexes() {
+					if !migrator.HasIndex(model, idx.Name) {
+						statements = append(statements, d.createIndexSQL(stmt, idx))
+					}
+				}
+			}
+		}
+
+		if !opts.DryRun {
+			for _, sql := range statements {
+				if err := d.db.Exec(sql).Error; err != nil {
+					return result, fmt.Errorf("执行同步SQL失败 [%s]: %w", sql, err)
+				}
+				result.Statements = append(result.Statements, sql)
+			}
+		} else {
+			result.Statements = append(result.Statements, statements...)
+		}
+		result.Skipped = append(result.Skipped, skipped...)
+	}
+
+	return result, nil
+}
+
+// createTableSQL 生成建表语句
+func (d *Database) createTableSQL(stmt *gorm.Statement) string {
+	migrator := d.db.Migrator()
+	sch := stmt.Schema
+
+	var (
+		definitions             []string
+		hasPrimaryKeyInDataType bool
+	)
+	for _, dbName := range sch.DBNames {
+		field := sch.FieldsByDBName[dbName]
+		if field.IgnoreMigration {
+			continue
+		}
+		dataType := migrator.FullDataTypeOf(field).SQL
+		hasPrimaryKeyInDataType = hasPrimaryKeyInDataType || strings.Contains(strings.ToUpper(dataType), "PRIMARY KEY")
+		definitions = append(definitions, stmt.Quote(dbName)+" "+dataType)
+	}
+
+	if !hasPrimaryKeyInDataType && len(sch.PrimaryFields) > 0 {
+		keys := make([]string, 0, len(sch.PrimaryFields))
+		for _, field := range sch.PrimaryFields {
+			keys = append(keys, stmt.Quote(field.DBName))
+		}
+		definitions = append(definitions, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+	}
+
+	uniques := sch.ParseUniqueConstraints()
+	names := make([]string, 0, len(uniques))
+	for name := range uniques {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		uni := uniques[name]
+		definitions = append(definitions, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)",
+			stmt.Quote(uni.Name), stmt.Quote(uni.Field.DBName)))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)", stmt.Quote(sch.Table), strings.Join(definitions, ", "))
+}
+
+// createIndexSQL 生成创建索引语句
+func (d *Database) createIndexSQL(stmt *gorm.Statement, idx *schema.Index) string {
+	columns := make([]string, 0, len(idx.Fields))
+	for _, opt := range idx.Fields {
+		if opt.Expression != "" {
+			columns = append(columns, opt.Expression)
+			continue
+		}
+		column := stmt.Quote(opt.DBName)
+		if opt.Sort != "" {
+			column += " " + opt.Sort
+		}
+		columns = append(columns, column)
+	}
+
+	createSQL := "CREATE "
+	if idx.Class != "" {
+		createSQL += idx.Class + " "
+	}
+	createSQL += fmt.Sprintf("INDEX %s ON %s (%s)", stmt.Quote(idx.Name), stmt.Quote(stmt.Schema.Table), strings.Join(columns, ", "))
+	if idx.Where != "" {
+		createSQL += " WHERE " + idx.Where
+	}
+	return createSQL
+}
+
+// EnsureIndexes 按模型的 mongo 标签创建或更新 MongoDB 集合索引，可在启动时重复执行，标签格式见 adapter.MongoIndexes
+// 示例: db.EnsureIndexes(&User{}, &Session{})
+func (d *Database) EnsureIndexes(models ...interface{}) error {
+	mongoAdapter, ok := d.adapter.(*adapter.MongoDB)
+	if !ok {
+		return fmt.Errorf("EnsureIndexes 仅支持 MongoDB，%s 请使用 SyncSchema", d.dbType)
+	}
+	for _, model := range models {
+		if err := mongoAdapter.EnsureIndexes(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package gosqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ==================== 优雅关闭 ====================
+
+// ErrShuttingDown 管理器正在关闭，不再创建新的数据库连接
+var ErrShuttingDown = errors.New("数据库管理器正在关闭")
+
+// ForceClosed 等待超时后被强制关闭的连接池
+type ForceClosed struct {
+	Key   string     // 数据库键（别名_模式）
+	InUse int        // 关闭时仍在使用的连接数
+	Held  []HeldConn // 关闭时仍被占用的连接
+}
+
+// ShutdownReport 关闭结果
+type ShutdownReport struct {
+	Closed      []string      // 正常关闭的数据库键
+	ForceClosed []ForceClosed // 强制关闭的连接池
+	Errors      []error       // 关闭连接池时的错误
+}
+
+// shutdownPollInterval 等待进行中操作时的检查间隔
+const shutdownPollInterval = 20 * time.Millisecond
+
+// Shutdown 优雅关闭所有数据库：停止创建新的数据库连接，等待进行中的操作完成（以 ctx 为上限），
+// 然后关闭连接池。ctx 到期时强制关闭剩余连接池并在报告中列出，返回的错误包装 ctx.Err()
+func (m *DatabaseManager) Shutdown(ctx context.Context) (*ShutdownReport, error) {
+	m.mutex.Lock()
+	m.closing = true
+	databases := make(map[string]*Database, len(m.databases))
+	for key, db := range m.databases {
+		databases[key] = db
+	}
+	m.databases = make(map[string]*Database)
+	m.mutex.Unlock()
+
+	keys := make([]string, 0, len(databases))
+	for key := range databases {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	report := &ShutdownReport{}
+	pending := keys
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		// 关闭已空闲的连接池
+		var busy []string
+		for _, key := range pending {
+			if databases[key].Stats().InUse > 0 {
+				busy = append(busy, key)
+				continue
+			}
+			if err := databases[key].Close(); err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("关闭数据库(%s)失败: %w", key, err))
+			}
+			report.Closed = append(report.Closed, key)
+		}
+		pending = busy
+		if len(pending) == 0 {
+			return report, errors.Join(report.Errors...)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			for _, key := range pending {
+				stats := databases[key].Stats()
+				report.ForceClosed = append(report.ForceClosed, ForceClosed{Key: key, InUse: stats.InUse, Held: stats.Held})
+				if err := databases[key].Close(); err != nil {
+					report.Errors = append(report.Errors, fmt.Errorf("关闭数据库(%s)失败: %w", key, err))
+				}
+			}
+			return report, fmt.Errorf("强制关闭 %s: %w", strings.Join(pending, ", "), errors.Join(append([]error{ctx.Err()}, report.Errors...)...))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ==================== 快照文件 ====================
+
+// 快照文件为 JSON Lines：每个表先写一行表头 {"table":...,"columns":[...],"rows":N}，再写 N 行 JSON 数组。
+// 时间写为 {"$time":"RFC3339Nano"}，非 UTF-8 的二进制写为 {"$bytes":"base64"}，读取时还原类型；
+// 整数读取为 int64，其他数字读取为 float64
+
+// fileHeader 表头
+type fileHeader struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	Rows    int      `json:"rows"`
+}
+
+// Write 将快照写入 w
+func (s *Snapshot) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, table := range s.Tables {
+		if err := encoder.Encode(fileHeader{Table: table.Name, Columns: table.Columns, Rows: len(table.Rows)}); err != nil {
+			return fmt.Errorf("写入表 %s 失败: %w", table.Name, err)
+		}
+		for _, row := range table.Rows {
+			encoded := make([]interface{}, len(row))
+			for i, value := range row {
+				encoded[i] = encodeValue(value)
+			}
+			if err := encoder.Encode(encoded); err != nil {
+				return fmt.Errorf("写入表 %s 失败: %w", table.Name, err)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// Read 读取 Write 写出的快照
+func Read(r io.Reader) (*Snapshot, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	decoder.UseNumber()
+	snap := &Snapshot{}
+	for {
+		var header fileHeader
+		if err := decoder.Decode(&header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return snap, nil
+			}
+			return nil, fmt.Errorf("读取表头失败: %w", err)
+		}
+		if header.Table == "" {
+			return nil, errors.New("快照文件格式错误：缺少表名")
+		}
+		table := &TableData{Name: header.Table, Columns: header.Columns, Rows: make([][]interface{}, 0, header.Rows)}
+		for i := 0; i < header.Rows; i++ {
+			var row []interface{}
+			if err := decoder.Decode(&row); err != nil {
+				return nil, fmt.Errorf("读取表 %s 第 %d 行失败: %w", header.Table, i+1, err)
+			}
+			if len(row) != len(header.Columns) {
+				return nil, fmt.Errorf("表 %s 第 %d 行有 %d 列，表头为 %d 列", header.Table, i+1, len(row), len(header.Columns))
+			}
+			for j, value := range row {
+				decoded, err := decodeValue(value)
+				if err != nil {
+					return nil, fmt.Errorf("读取表 %s 第 %d 行的列 %s 失败: %w", header.Table, i+1, header.Columns[j], err)
+				}
+				row[j] = decoded
+			}
+			table.Rows = append(table.Rows, row)
+		}
+		snap.Tables = append(snap.Tables, table)
+	}
+}
+
+// encodeValue 时间和二进制编码为带类型标记的对象
+func encodeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return map[string]string{"$time": v.Format(time.RFC3339Nano)}
+	case []byte:
+		return map[string]string{"$bytes": base64.StdEncoding.EncodeToString(v)}
+	}
+	return value
+}
+
+// decodeValue 还原带类型标记的对象和数字
+func decodeValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case map[string]interface{}:
+		if s, ok := v["$time"].(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+		if s, ok := v["$bytes"].(string); ok {
+			return base64.StdEncoding.DecodeString(s)
+		}
+		return nil, errors.New("未知的取值类型")
+	}
+	return value, nil
+}
@@ -0,0 +1,67 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/dialect"
+)
+
+// ==================== 导入 ====================
+
+// RestoreOptions 导入选项
+type RestoreOptions struct {
+	Replace   bool              // 导入前删除快照中各表的所有行（按依赖逆序），用于整体刷新预发库
+	TableMap  map[string]string // 表名映射，快照表名 -> 目标表名
+	ChunkSize int               // 每条 INSERT 的行数，0 表示按数据库参数上限计算
+	// OnProgress 每批插入后回调，inserted 为该表已插入的行数
+	OnProgress func(table string, inserted, total int)
+}
+
+// Restore 按快照中表的顺序（被引用的表在前）通过批量插入导入 db
+// 数据库支持事务时所有表在同一事务中导入，失败时目标库保持不变
+func Restore(ctx context.Context, db *gosqlx.Database, snap *Snapshot, options RestoreOptions) error {
+	if db == nil {
+		return ErrNoDatabase
+	}
+	target := func(name string) string {
+		if mapped, ok := options.TableMap[name]; ok {
+			return mapped
+		}
+		return name
+	}
+	quote := dialect.GetDialect(string(db.Type())).Quote
+
+	restore := func(tx *gosqlx.Database) error {
+		if options.Replace {
+			for i := len(snap.Tables) - 1; i >= 0; i-- {
+				table := target(snap.Tables[i].Name)
+				if err := tx.Exec("DELETE FROM " + quote(table)); err != nil {
+					return fmt.Errorf("清空表 %s 失败: %w", table, err)
+				}
+			}
+		}
+		for _, data := range snap.Tables {
+			table := target(data.Name)
+			err := tx.BatchInsertWithOptions(table, data.Columns, data.Rows, gosqlx.BatchInsertOptions{
+				ChunkSize: options.ChunkSize,
+				OnProgress: func(inserted, total int) {
+					if options.OnProgress != nil {
+						options.OnProgress(table, inserted, total)
+					}
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("导入表 %s 失败: %w", table, err)
+			}
+		}
+		return nil
+	}
+
+	db = db.WithContext(ctx)
+	if !db.Capabilities().Transactions {
+		return restore(db)
+	}
+	return db.Transaction(restore)
+}
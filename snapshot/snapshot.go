@@ -0,0 +1,478 @@
+// Package snapshot 从生产库抽取按条件过滤的数据子集，连同外键引用的行一起保存，
+// 对指定列执行可插拔的转换（脱敏）后写入文件，再通过批量插入导入预发库
+//
+//	snap, err := snapshot.Take(ctx, prodDB, []snapshot.Table{
+//		{Name: "orders", Where: "created_at >= ?", Args: []interface{}{since}},
+//	}, snapshot.Options{Transformers: map[string]snapshot.Transformer{"users.email": maskEmail}})
+//	err = snap.Write(file)
+//
+//	snap, err := snapshot.Read(file)
+//	err = snapshot.Restore(ctx, stagingDB, snap, snapshot.RestoreOptions{Replace: true})
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/dialect"
+	"github.com/gzorm/gosqlx/introspect"
+)
+
+// ErrNoDatabase 数据库为空
+var ErrNoDatabase = errors.New("数据库不能为空")
+
+// Transformer 列值转换函数，用于脱敏，与 export.Converter、sync.Converter 的签名相同
+type Transformer func(value interface{}) (interface{}, error)
+
+// Table 抽取的表及其过滤条件
+type Table struct {
+	Name  string        // 表名
+	Where string        // 过滤条件，为空时抽取整表
+	Args  []interface{} // 过滤条件参数
+}
+
+// Options 抽取选项
+type Options struct {
+	BatchSize int  // 按外键抽取引用行时每条 IN 查询的键数，默认 500
+	NoClosure bool // 只抽取指定的行，不补齐外键引用的行
+	// Transformers 列值转换，键为 "表.列" 或 "列"，在补齐外键引用的行之后执行；
+	// 转换主键或外键列会破坏行之间的引用，应只用于普通列
+	Transformers map[string]Transformer
+}
+
+// TableData 一个表的快照数据
+type TableData struct {
+	Name    string          // 表名
+	Columns []string        // 列名
+	Rows    [][]interface{} // 行，取值与 Columns 一一对应
+}
+
+// Snapshot 数据快照，Tables 按外键依赖排序，被引用的表在前
+type Snapshot struct {
+	Tables []*TableData
+}
+
+// Table 返回指定表的数据，不存在时返回 nil
+func (s *Snapshot) Table(name string) *TableData {
+	for _, table := range s.Tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+// RowCount 所有表的行数
+func (s *Snapshot) RowCount() int64 {
+	var n int64
+	for _, table := range s.Tables {
+		n += int64(len(table.Rows))
+	}
+	return n
+}
+
+// ==================== 抽取 ====================
+
+// tableState 抽取中的表
+type tableState struct {
+	data      *TableData
+	primary   []string        // 主键列
+	keys      []int           // 主键列在 Columns 中的位置，没有主键时按整行去重
+	seen      map[string]bool // 已抽取的行
+	requested map[string]bool // 已按外键请求过的引用值，键为 "列组\x1d取值"
+	foreign   []introspect.ForeignKey
+	scanned   int // 已检查外键的行数
+	order     int // 首次出现的顺序
+}
+
+// taker 一次抽取
+type taker struct {
+	ctx       context.Context
+	db        *gosqlx.Database
+	inspector *introspect.Inspector
+	dialect   dialect.Dialect
+	options   Options
+	tables    map[string]*tableState
+}
+
+// Take 抽取 tables 中满足条件的行，并递归补齐这些行通过外键引用的行（如订单引用的用户、用户引用的部门），
+// 保证导入时外键完整；然后对配置的列执行转换。结果保存在内存中，适合抽取用于测试和预发的数据子集
+func Take(ctx context.Context, db *gosqlx.Database, tables []Table, options Options) (*Snapshot, error) {
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 500
+	}
+	t := &taker{
+		ctx:       ctx,
+		db:        db,
+		inspector: introspect.New(db.SqlDB(), string(db.Type())).WithContext(ctx),
+		dialect:   dialect.GetDialect(string(db.Type())),
+		options:   options,
+		tables:    make(map[string]*tableState),
+	}
+	for _, table := range tables {
+		state, err := t.state(table.Name)
+		if err != nil {
+			return nil, err
+		}
+		query := "SELECT * FROM " + t.dialect.Quote(table.Name)
+		if table.Where != "" {
+			query += " WHERE " + table.Where
+		}
+		if err := t.fetch(state, query, table.Args); err != nil {
+			return nil, err
+		}
+	}
+	if !options.NoClosure {
+		if err := t.closure(); err != nil {
+			return nil, err
+		}
+	}
+
+	snap := &Snapshot{}
+	for _, state := range t.sorted() {
+		orderSelfReferences(state)
+		if err := t.transform(state.data); err != nil {
+			return nil, err
+		}
+		snap.Tables = append(snap.Tables, state.data)
+	}
+	return snap, nil
+}
+
+// state 返回表的抽取状态，首次出现时读取主键和外键
+func (t *taker) state(name string) (*tableState, error) {
+	if state, ok := t.tables[strings.ToLower(name)]; ok {
+		return state, nil
+	}
+	state := &tableState{
+		data:      &TableData{Name: name},
+		seen:      make(map[string]bool),
+		requested: make(map[string]bool),
+		order:     len(t.tables),
+	}
+	// 结构查询在读取数据之前完成，连接池只有一个连接时不会与打开的结果集互相等待
+	primary, err := t.inspector.PrimaryKeys(name)
+	if err != nil {
+		return nil, err
+	}
+	state.primary = primary
+	if !t.options.NoClosure {
+		if state.foreign, err = t.inspector.ForeignKeys(name); err != nil {
+			return nil, err
+		}
+	}
+	t.tables[strings.ToLower(name)] = state
+	return state, nil
+}
+
+// fetch 执行查询并把未抽取过的行加入表
+func (t *taker) fetch(state *tableState, query string, args []interface{}) error {
+	rows, err := t.db.DB().WithContext(t.ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return fmt.Errorf("查询表 %s 失败: %w", state.data.Name, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if state.data.Columns == nil {
+		state.data.Columns = columns
+		for _, key := range state.primary {
+			if i := columnIndex(columns, key); i >= 0 {
+				state.keys = append(state.keys, i)
+			}
+		}
+		if len(state.keys) != len(state.primary) {
+			state.keys = nil
+		}
+	}
+
+	for rows.Next() {
+		values, err := scanRow(rows, len(columns))
+		if err != nil {
+			return fmt.Errorf("读取表 %s 失败: %w", state.data.Name, err)
+		}
+		key := rowKey(values, state.keys)
+		if !state.seen[key] {
+			state.seen[key] = true
+			state.data.Rows = append(state.data.Rows, values)
+		}
+	}
+	return rows.Err()
+}
+
+// closure 反复检查新抽取的行引用的外键，抽取引用表中尚未抽取的行，直到没有新行
+func (t *taker) closure() error {
+	for {
+		progressed := false
+		for _, state := range t.sorted() {
+			if state.scanned == len(state.data.Rows) {
+				continue
+			}
+			pending := state.data.Rows[state.scanned:]
+			state.scanned = len(state.data.Rows)
+			progressed = true
+			for _, fk := range state.foreign {
+				if err := t.follow(state, fk, pending); err != nil {
+					return err
+				}
+			}
+		}
+		if !progressed {
+			return nil
+		}
+	}
+}
+
+// follow 抽取 rows 通过外键 fk 引用的行
+func (t *taker) follow(state *tableState, fk introspect.ForeignKey, rows [][]interface{}) error {
+	ref, err := t.state(fk.RefTable)
+	if err != nil {
+		return err
+	}
+	refColumns := fk.RefColumns
+	if len(refColumns) == 0 || refColumns[0] == "" {
+		refColumns = ref.primary // SQLite 未声明引用列时引用主键
+	}
+	if len(refColumns) != len(fk.Columns) {
+		return fmt.Errorf("外键 %s 的引用列与外键列数量不一致", fk.Name)
+	}
+	index := make([]int, len(fk.Columns))
+	for i, column := range fk.Columns {
+		if index[i] = columnIndex(state.data.Columns, column); index[i] < 0 {
+			return fmt.Errorf("表 %s 的查询结果中没有外键列 %s", state.data.Name, column)
+		}
+	}
+
+	prefix := strings.ToLower(strings.Join(refColumns, ",")) + "\x1d"
+	var values [][]interface{}
+	for _, row := range rows {
+		value := make([]interface{}, len(index))
+		null := false
+		for i, j := range index {
+			value[i] = row[j]
+			null = null || row[j] == nil
+		}
+		key := prefix + rowKey(value, nil)
+		if null || ref.requested[key] {
+			continue
+		}
+		ref.requested[key] = true
+		values = append(values, value)
+	}
+
+	for start := 0; start < len(values); start += t.options.BatchSize {
+		batch := values[start:min(start+t.options.BatchSize, len(values))]
+		query, args := t.inQuery(fk.RefTable, refColumns, batch)
+		if err := t.fetch(ref, query, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inQuery 按引用列的取值查询引用表，单列使用 IN，多列使用 OR 连接的条件组
+func (t *taker) inQuery(table string, columns []string, values [][]interface{}) (string, []interface{}) {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = t.dialect.Quote(column)
+	}
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if len(columns) == 1 {
+		marks := make([]string, len(values))
+		for i, value := range values {
+			marks[i] = "?"
+			args = append(args, value[0])
+		}
+		conditions = append(conditions, quoted[0]+" IN ("+strings.Join(marks, ", ")+")")
+	} else {
+		for _, value := range values {
+			parts := make([]string, len(columns))
+			for i := range columns {
+				parts[i] = quoted[i] + " = ?"
+			}
+			conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+			args = append(args, value...)
+		}
+	}
+	query := "SELECT * FROM " + t.dialect.Quote(table) + " WHERE " + strings.Join(conditions, " OR ")
+	return query, args
+}
+
+// sorted 按外键依赖排序表，被引用的表在前；存在循环引用时按首次出现的顺序
+func (t *taker) sorted() []*tableState {
+	states := make([]*tableState, 0, len(t.tables))
+	for _, state := range t.tables {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].order < states[j].order })
+
+	var (
+		result  []*tableState
+		visited = make(map[*tableState]int) // 1 访问中，2 已完成
+		visit   func(state *tableState)
+	)
+	visit = func(state *tableState) {
+		if visited[state] != 0 {
+			return
+		}
+		visited[state] = 1
+		for _, fk := range state.foreign {
+			if ref, ok := t.tables[strings.ToLower(fk.RefTable)]; ok && ref != state {
+				visit(ref)
+			}
+		}
+		visited[state] = 2
+		result = append(result, state)
+	}
+	for _, state := range states {
+		visit(state)
+	}
+	return result
+}
+
+// orderSelfReferences 引用自身的表（如员工引用上级）中，被引用的行排在引用它的行之前
+func orderSelfReferences(state *tableState) {
+	for _, fk := range state.foreign {
+		if !strings.EqualFold(fk.RefTable, state.data.Name) {
+			continue
+		}
+		refColumns := fk.RefColumns
+		if len(refColumns) == 0 || refColumns[0] == "" {
+			refColumns = state.primary
+		}
+		from, to := make([]int, len(fk.Columns)), make([]int, len(refColumns))
+		for i, column := range fk.Columns {
+			from[i] = columnIndex(state.data.Columns, column)
+		}
+		for i, column := range refColumns {
+			to[i] = columnIndex(state.data.Columns, column)
+		}
+		if len(from) != len(to) || containsNegative(from) || containsNegative(to) {
+			continue
+		}
+
+		rows := state.data.Rows
+		byKey := make(map[string]int, len(rows))
+		for i, row := range rows {
+			byKey[rowKey(row, to)] = i
+		}
+		ordered := make([][]interface{}, 0, len(rows))
+		visited := make([]bool, len(rows))
+		var visit func(i int)
+		visit = func(i int) {
+			if visited[i] {
+				return
+			}
+			visited[i] = true
+			if parent, ok := byKey[rowKey(rows[i], from)]; ok {
+				visit(parent)
+			}
+			ordered = append(ordered, rows[i])
+		}
+		for i := range rows {
+			visit(i)
+		}
+		state.data.Rows = ordered
+	}
+}
+
+// transform 对表的列执行转换
+func (t *taker) transform(data *TableData) error {
+	for i, column := range data.Columns {
+		transformer, ok := t.options.Transformers[data.Name+"."+column]
+		if !ok {
+			transformer, ok = t.options.Transformers[column]
+		}
+		if !ok {
+			continue
+		}
+		for _, row := range data.Rows {
+			value, err := transformer(row[i])
+			if err != nil {
+				return fmt.Errorf("转换列 %s.%s 失败: %w", data.Name, column, err)
+			}
+			row[i] = value
+		}
+	}
+	return nil
+}
+
+// ==================== 工具函数 ====================
+
+// scanRow 读取一行，合法 UTF-8 的 []byte 转换为字符串，其他 []byte 作为二进制保留
+func scanRow(rows *sql.Rows, width int) ([]interface{}, error) {
+	values := make([]interface{}, width)
+	targets := make([]interface{}, width)
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			if utf8.Valid(b) {
+				values[i] = string(b)
+			} else {
+				values[i] = append([]byte(nil), b...)
+			}
+		}
+	}
+	return values, nil
+}
+
+// rowKey 行的去重键，index 为空时使用整行
+func rowKey(values []interface{}, index []int) string {
+	var b strings.Builder
+	write := func(value interface{}) {
+		if value == nil {
+			b.WriteByte(0)
+		} else {
+			b.WriteString(gosqlx.FormatValue(value))
+		}
+		b.WriteByte(0x1f)
+	}
+	if len(index) == 0 {
+		for _, value := range values {
+			write(value)
+		}
+	}
+	for _, i := range index {
+		write(values[i])
+	}
+	return b.String()
+}
+
+// containsNegative 是否有未找到的列
+func containsNegative(index []int) bool {
+	for _, i := range index {
+		if i < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// columnIndex 查找列的位置（忽略大小写）
+func columnIndex(columns []string, name string) int {
+	for i, column := range columns {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
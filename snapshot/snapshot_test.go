@@ -0,0 +1,147 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// schema 部门、用户（引用部门和上级）、订单（引用用户）
+var schema = []string{
+	"PRAGMA foreign_keys = ON",
+	"CREATE TABLE departments (id INTEGER PRIMARY KEY, name TEXT)",
+	"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, dept_id INTEGER REFERENCES departments(id), manager_id INTEGER REFERENCES users(id), avatar BLOB)",
+	"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users(id), amount REAL, created_at DATETIME)",
+}
+
+// openSQLite 打开内存 SQLite 数据库并执行语句
+func openSQLite(t *testing.T, statements ...string) *gosqlx.Database {
+	ctx := gosqlx.NewContext(context.Background(), "snapshot", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	for _, statement := range append(schema, statements...) {
+		if err := db.Exec(statement); err != nil {
+			t.Fatalf("执行 %s 失败: %v", statement, err)
+		}
+	}
+	return db
+}
+
+// 测试抽取订单子集及其外键闭包，脱敏后写入文件并导入另一个库
+func TestTakeRestore(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	source := openSQLite(t,
+		"INSERT INTO departments VALUES (1, 'R&D'), (2, 'Sales'), (3, 'HR')",
+		"INSERT INTO users VALUES (1, 'Boss', 'boss@example.com', 1, NULL, NULL), (2, 'Ann', 'ann@example.com', 2, 1, X'FF00'), (3, 'Bob', 'bob@example.com', 3, NULL, NULL)",
+	)
+	if err := source.Exec("INSERT INTO orders VALUES (1, 2, 9.5, ?), (2, 3, 20, ?), (3, 2, 5, ?)", created, created, created); err != nil {
+		t.Fatalf("写入订单失败: %v", err)
+	}
+
+	snap, err := Take(ctx, source, []Table{{Name: "orders", Where: "user_id = ?", Args: []interface{}{2}}}, Options{
+		BatchSize: 1,
+		Transformers: map[string]Transformer{
+			"users.email": func(value interface{}) (interface{}, error) {
+				name, _, _ := strings.Cut(value.(string), "@")
+				return "user+" + name + "@test.invalid", nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("抽取失败: %v", err)
+	}
+	var names []string
+	for _, table := range snap.Tables {
+		names = append(names, table.Name)
+	}
+	if strings.Join(names, ",") != "departments,users,orders" {
+		t.Fatalf("表顺序 = %v，被引用的表应在前", names)
+	}
+	// 订单 1、3 -> 用户 2 -> 上级 1，部门 2 和 1
+	if n := len(snap.Table("orders").Rows); n != 2 {
+		t.Errorf("订单 = %d 行，期望 2", n)
+	}
+	users := snap.Table("users")
+	if len(users.Rows) != 2 || users.Rows[0][0] != int64(1) {
+		t.Errorf("用户 = %v，期望上级在前的 2 行", users.Rows)
+	}
+	if n := len(snap.Table("departments").Rows); n != 2 || snap.RowCount() != 6 {
+		t.Errorf("部门 = %d 行，共 %d 行", n, snap.RowCount())
+	}
+	for _, row := range users.Rows {
+		if !strings.HasSuffix(row[2].(string), "@test.invalid") {
+			t.Errorf("邮箱未脱敏: %v", row[2])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := snap.Write(&buf); err != nil {
+		t.Fatalf("写入快照失败: %v", err)
+	}
+	restored, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("读取快照失败: %v", err)
+	}
+
+	target := openSQLite(t, "INSERT INTO departments VALUES (9, 'Old')")
+	var progress int
+	err = Restore(ctx, target, restored, RestoreOptions{Replace: true, OnProgress: func(string, int, int) { progress++ }})
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if progress != 3 {
+		t.Errorf("进度回调 %d 次，期望 3", progress)
+	}
+	var count int64
+	if err := target.Raw("SELECT COUNT(*) FROM departments").Scan(&count).Error; err != nil || count != 2 {
+		t.Errorf("部门 = %d 行，期望清空后导入 2 行: %v", count, err)
+	}
+	var user struct {
+		Email  string
+		Avatar []byte
+	}
+	if err := target.Raw("SELECT email, avatar FROM users WHERE id = 2").Scan(&user).Error; err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if user.Email != "user+ann@test.invalid" || !bytes.Equal(user.Avatar, []byte{0xFF, 0x00}) {
+		t.Errorf("用户 = %+v", user)
+	}
+	var at time.Time
+	if err := target.Raw("SELECT created_at FROM orders WHERE id = 3").Scan(&at).Error; err != nil || !at.Equal(created) {
+		t.Errorf("created_at = %v，期望 %v: %v", at, created, err)
+	}
+}
+
+// 测试只抽取指定的行，外键引用的行缺失时导入失败并回滚
+func TestRestoreRollback(t *testing.T) {
+	ctx := context.Background()
+	source := openSQLite(t,
+		"INSERT INTO departments VALUES (1, 'R&D')",
+		"INSERT INTO users VALUES (1, 'Ann', 'ann@example.com', 1, NULL, NULL)",
+		"INSERT INTO orders VALUES (1, 1, 9.5, NULL)",
+	)
+	snap, err := Take(ctx, source, []Table{{Name: "departments"}, {Name: "orders"}}, Options{NoClosure: true})
+	if err != nil {
+		t.Fatalf("抽取失败: %v", err)
+	}
+	if snap.Table("users") != nil || snap.RowCount() != 2 {
+		t.Fatalf("NoClosure 时不应抽取用户: %d 行", snap.RowCount())
+	}
+
+	target := openSQLite(t)
+	if err := Restore(ctx, target, snap, RestoreOptions{}); err == nil {
+		t.Fatal("缺少引用的用户时期望导入失败")
+	}
+	var count int64
+	if err := target.Raw("SELECT COUNT(*) FROM departments").Scan(&count).Error; err != nil || count != 0 {
+		t.Errorf("失败后应回滚，部门 = %d 行: %v", count, err)
+	}
+}
@@ -0,0 +1,138 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	gosqlxerrors "github.com/gzorm/gosqlx/errors"
+)
+
+// ==================== SQLite 内存库与锁等待 ====================
+
+// defaultBusyTimeout SQLite 默认的锁等待时间
+const defaultBusyTimeout = 5 * time.Second
+
+// SQLiteMemoryDSN 返回命名的共享缓存内存库连接字符串，同一进程内同名的连接访问同一个库，不同名的库互不影响，
+// 用于并行测试各自使用独立的内存库：
+//
+//	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: gosqlx.SQLiteMemoryDSN(t.Name()), MaxOpen: 4})
+func SQLiteMemoryDSN(name string) string {
+	return "file:" + url.PathEscape(name) + "?mode=memory&cache=shared"
+}
+
+// sqliteMemory 判断连接字符串是否为内存库，shared 表示使用共享缓存（多个连接访问同一个库）
+func sqliteMemory(dsn string) (memory, shared bool) {
+	path, query, _ := strings.Cut(dsn, "?")
+	params, _ := url.ParseQuery(query)
+	memory = path == ":memory:" || path == "file::memory:" || path == "" ||
+		(strings.HasPrefix(path, "file:") && params.Get("mode") == "memory")
+	return memory, memory && params.Get("cache") == "shared"
+}
+
+// sqliteSource 在连接字符串未指定锁等待时间时加上 _busy_timeout（毫秒）
+func sqliteSource(dsn string, busyTimeout time.Duration) string {
+	if busyTimeout <= 0 {
+		return dsn
+	}
+	_, query, found := strings.Cut(dsn, "?")
+	params, _ := url.ParseQuery(query)
+	if params.Has("_busy_timeout") || params.Has("_timeout") {
+		return dsn
+	}
+	separator := "?"
+	if found {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s_busy_timeout=%d", dsn, separator, busyTimeout.Milliseconds())
+}
+
+// sqliteConfig 按 SQLite 连接字符串调整连接池配置，返回副本：
+// 未使用共享缓存的内存库每个连接是独立的库，限制为单个连接，语句在该连接上串行执行；
+// 内存库在最后一个连接关闭时销毁，因此保留至少一个空闲连接且连接不过期
+func sqliteConfig(config *Config, dsn string) *Config {
+	memory, shared := sqliteMemory(dsn)
+	if !memory {
+		return config
+	}
+	adjusted := *config
+	adjusted.MaxLifetime = 0
+	if !shared {
+		adjusted.MaxOpen, adjusted.MaxIdle = 1, 1
+		adjusted.WarmUp = min(adjusted.WarmUp, 1)
+	}
+	adjusted.MaxIdle = max(adjusted.MaxIdle, 1)
+	return &adjusted
+}
+
+// busyPool SQLite 连接池包装，不在事务中的语句返回 SQLITE_BUSY/SQLITE_LOCKED 时在 timeout 内退避重试
+// 共享缓存的表锁冲突（SQLITE_LOCKED）不受 busy_timeout 控制，由重试等待；事务中的语句不重试
+type busyPool struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// PrepareContext 预编译语句，不重试
+func (p *busyPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, query)
+}
+
+// ExecContext 执行语句，锁冲突时重试
+func (p *busyPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := p.retry(ctx, func() (err error) {
+		result, err = p.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryContext 执行查询，开始执行时锁冲突则重试，读取结果集期间的错误不重试
+func (p *busyPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := p.retry(ctx, func() (err error) {
+		rows, err = p.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext 执行单行查询，错误在 Scan 时才返回，不重试
+func (p *busyPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx 开启事务，事务连接不包装
+func (p *busyPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.db.BeginTx(ctx, opts)
+}
+
+// GetDBConn 返回底层连接池，供 gorm.DB.DB() 使用
+func (p *busyPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// retry 执行 fn，锁冲突时退避重试，直到成功、出现其他错误或超过 timeout
+func (p *busyPool) retry(ctx context.Context, fn func() error) error {
+	deadline := time.Now().Add(p.timeout)
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !sqliteBusy(err) || time.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(min(time.Duration(attempt)*5*time.Millisecond, 100*time.Millisecond)):
+		}
+	}
+}
+
+// sqliteBusy 是否为 SQLITE_BUSY 或 SQLITE_LOCKED 错误
+func sqliteBusy(err error) bool {
+	dbErr, ok := gosqlxerrors.Parse(err)
+	return ok && dbErr.Kind == gosqlxerrors.ErrLockTimeout
+}
@@ -0,0 +1,312 @@
+// Package sqltpl 基于 text/template 的SQL模板引擎，适用于构建器难以表达的复杂报表查询
+//
+// 模板从 .sql 文件加载，文件相对路径（去掉扩展名）即模板名，文件内可用 define 定义可复用片段。
+// 模板中的输出默认作为参数绑定（类似 MyBatis 的 #{}），不会拼接到SQL中:
+//
+//	-- report/sales.sql
+//	SELECT {{template "sales.columns" .}}
+//	FROM orders o
+//	WHERE 1 = 1
+//	{{if .Region}} AND o.region = {{.Region}}{{end}}
+//	{{if .Status}} AND o.status IN {{.Status}}{{end}}
+//	{{if .Keyword}} AND o.title LIKE {{printf "%%%s%%" .Keyword}}{{end}}
+//	ORDER BY {{ident .OrderBy}}
+//
+// 切片绑定为 (?, ?, ...)；标识符使用 ident 校验后输出，可信的SQL片段使用 raw 原样输出。
+// 渲染结果会去除 WHERE/SET 后多余的 AND、OR 和逗号以及空的 WHERE 子句
+package sqltpl
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// ErrTemplateNotFound 模板不存在
+var ErrTemplateNotFound = errors.New("SQL模板不存在")
+
+// ErrInvalidIdentifier 标识符不合法
+var ErrInvalidIdentifier = errors.New("不合法的SQL标识符")
+
+// identPattern 合法的标识符，允许 schema.table.column 形式
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// outputFuncs 直接输出SQL文本、不再自动绑定的函数
+var outputFuncs = map[string]bool{"bind": true, "ident": true, "raw": true}
+
+// Engine SQL模板引擎，可并发使用
+type Engine struct {
+	mutex sync.RWMutex
+	tmpl  *template.Template
+}
+
+// NewEngine 创建模板引擎
+func NewEngine() *Engine {
+	return &Engine{tmpl: template.New("").Option("missingkey=zero").Funcs(placeholderFuncs())}
+}
+
+// Funcs 注册自定义模板函数，需在加载模板之前调用；函数返回值同样作为参数绑定
+func (e *Engine) Funcs(funcs template.FuncMap) *Engine {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.tmpl.Funcs(funcs)
+	return e
+}
+
+// Parse 以指定名称解析模板文本
+func (e *Engine) Parse(name, text string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if _, err := e.tmpl.New(name).Parse(text); err != nil {
+		return fmt.Errorf("解析SQL模板 %s 失败: %w", name, err)
+	}
+	e.secure()
+	return nil
+}
+
+// ParseFS 加载文件系统中匹配的模板文件，模板名为文件路径去掉扩展名，支持 embed.FS
+func (e *Engine) ParseFS(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return fmt.Errorf("匹配SQL模板文件失败: %w", err)
+		}
+		for _, file := range files {
+			if err := e.parseFile(fsys, file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ParseDir 递归加载目录下的全部 .sql 文件
+func (e *Engine) ParseDir(dir string) error {
+	fsys := os.DirFS(dir)
+	return fs.WalkDir(fsys, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || path.Ext(file) != ".sql" {
+			return nil
+		}
+		return e.parseFile(fsys, file)
+	})
+}
+
+// parseFile 解析单个模板文件
+func (e *Engine) parseFile(fsys fs.FS, file string) error {
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return fmt.Errorf("读取SQL模板文件 %s 失败: %w", file, err)
+	}
+	return e.Parse(strings.TrimSuffix(file, path.Ext(file)), string(data))
+}
+
+// Names 返回已加载的模板名
+func (e *Engine) Names() []string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	var names []string
+	for _, t := range e.tmpl.Templates() {
+		if t.Name() != "" && t.Tree != nil {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render 渲染模板，返回使用 ? 占位符的SQL和参数
+func (e *Engine) Render(name string, data interface{}) (string, []interface{}, error) {
+	e.mutex.RLock()
+	t := e.tmpl.Lookup(name)
+	if t == nil || t.Tree == nil {
+		e.mutex.RUnlock()
+		return "", nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	// 克隆后替换占位函数，使每次渲染拥有独立的参数列表
+	cloned, err := e.tmpl.Clone()
+	e.mutex.RUnlock()
+	if err != nil {
+		return "", nil, fmt.Errorf("克隆SQL模板失败: %w", err)
+	}
+
+	binder := &binder{}
+	cloned.Funcs(binder.funcs())
+
+	var sqlStr strings.Builder
+	if err := cloned.ExecuteTemplate(&sqlStr, name, data); err != nil {
+		return "", nil, fmt.Errorf("渲染SQL模板 %s 失败: %w", name, err)
+	}
+	return tidy(sqlStr.String()), binder.args, nil
+}
+
+// Query 渲染模板并将查询结果扫描到 out
+func (e *Engine) Query(db *gosqlx.Database, out interface{}, name string, data interface{}) error {
+	sqlStr, args, err := e.Render(name, data)
+	if err != nil {
+		return err
+	}
+	return db.QueryRows(out, sqlStr, args...)
+}
+
+// QueryMaps 渲染模板并以 map 形式返回查询结果
+func (e *Engine) QueryMaps(db *gosqlx.Database, name string, data interface{}) ([]map[string]interface{}, error) {
+	sqlStr, args, err := e.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryMaps(sqlStr, args...)
+}
+
+// Rows 渲染模板并返回结果集，调用方负责关闭
+func (e *Engine) Rows(db *gosqlx.Database, name string, data interface{}) (*sql.Rows, error) {
+	sqlStr, args, err := e.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return db.Query(sqlStr, args...)
+}
+
+// Exec 渲染模板并执行，返回影响的行数
+func (e *Engine) Exec(db *gosqlx.Database, name string, data interface{}) (int64, error) {
+	sqlStr, args, err := e.Render(name, data)
+	if err != nil {
+		return 0, err
+	}
+	result := db.DB().Exec(sqlStr, args...)
+	return result.RowsAffected, result.Error
+}
+
+// ==================== 参数绑定 ====================
+
+// binder 收集一次渲染的参数
+type binder struct {
+	args []interface{}
+}
+
+// funcs 绑定到当前渲染的模板函数
+func (b *binder) funcs() template.FuncMap {
+	return template.FuncMap{
+		"bind":  b.bind,
+		"ident": ident,
+		"raw":   raw,
+	}
+}
+
+// bind 追加参数并返回占位符，切片展开为 (?, ?, ...)，空切片为 (NULL)
+func (b *binder) bind(value interface{}) string {
+	rv := reflect.ValueOf(value)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		if rv.Len() == 0 {
+			return "(NULL)"
+		}
+		placeholders := make([]string, rv.Len())
+		for i := range placeholders {
+			placeholders[i] = "?"
+			b.args = append(b.args, rv.Index(i).Interface())
+		}
+		return "(" + strings.Join(placeholders, ", ") + ")"
+	}
+	b.args = append(b.args, value)
+	return "?"
+}
+
+// placeholderFuncs 解析阶段使用的占位函数，渲染时由 binder 替换
+func placeholderFuncs() template.FuncMap {
+	return template.FuncMap{
+		"bind":  func(value interface{}) string { return "?" },
+		"ident": ident,
+		"raw":   raw,
+	}
+}
+
+// ident 校验并输出列名、表名等标识符
+func ident(name string) (string, error) {
+	if !identPattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+	}
+	return name, nil
+}
+
+// raw 原样输出可信的SQL片段
+func raw(fragment interface{}) string {
+	return fmt.Sprint(fragment)
+}
+
+// secure 将模板中所有未经 bind/ident/raw 处理的输出改写为参数绑定
+func (e *Engine) secure() {
+	for _, t := range e.tmpl.Templates() {
+		if t.Tree != nil {
+			secureNode(t.Tree, t.Tree.Root)
+		}
+	}
+}
+
+// secureNode 递归改写输出节点: {{.Name}} => {{.Name | bind}}
+func secureNode(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			secureNode(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) == 0 {
+			return
+		}
+		last := n.Pipe.Cmds[len(n.Pipe.Cmds)-1]
+		if id, ok := last.Args[0].(*parse.IdentifierNode); ok && outputFuncs[id.Ident] {
+			return
+		}
+		bind := parse.NewIdentifier("bind").SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{NodeType: parse.NodeCommand, Pos: n.Pos, Args: []parse.Node{bind}})
+	case *parse.IfNode:
+		secureNode(tree, n.List)
+		secureNode(tree, n.ElseList)
+	case *parse.RangeNode:
+		secureNode(tree, n.List)
+		secureNode(tree, n.ElseList)
+	case *parse.WithNode:
+		secureNode(tree, n.List)
+		secureNode(tree, n.ElseList)
+	}
+}
+
+// ==================== 结果整理 ====================
+
+var (
+	whereLeadingPattern = regexp.MustCompile(`(?i)\b(WHERE|HAVING)(\s+)(AND|OR)\s+`)
+	emptyWherePattern   = regexp.MustCompile(`(?i)[ \t]*\bWHERE(\s*)(\)|;|$|\b(ORDER|GROUP|LIMIT|HAVING|UNION|OFFSET|FETCH)\b)`)
+	trailingCommaRegexp = regexp.MustCompile(`,(\s*)(\)|$|\b(WHERE|FROM)\b)`)
+	blankLinePattern    = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+)
+
+// tidy 去除条件块留下的多余关键字、逗号和空行
+func tidy(sqlStr string) string {
+	sqlStr = whereLeadingPattern.ReplaceAllString(sqlStr, "$1$2")
+	sqlStr = emptyWherePattern.ReplaceAllString(sqlStr, "$1$2")
+	sqlStr = trailingCommaRegexp.ReplaceAllString(sqlStr, "$1$2")
+	sqlStr = blankLinePattern.ReplaceAllString(sqlStr, "\n")
+
+	lines := strings.Split(sqlStr, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,85 @@
+package sqltpl
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// 测试加载模板文件并渲染条件块、切片和片段引用
+func TestRender(t *testing.T) {
+	engine := NewEngine()
+	if err := engine.ParseFS(os.DirFS("testdata"), "*.sql", "report/*.sql"); err != nil {
+		t.Fatal(err)
+	}
+	if names := engine.Names(); !reflect.DeepEqual(names, []string{"common", "order.columns", "report/sales"}) {
+		t.Errorf("模板名不符合预期: %v", names)
+	}
+
+	sqlStr, args, err := engine.Render("report/sales", map[string]interface{}{
+		"Region":  "east",
+		"IDs":     []int{1, 2},
+		"Keyword": "phone",
+		"OrderBy": "o.amount",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT o.id, o.region, o.amount\nFROM orders o\nWHERE\n  o.region = ?\n  AND o.id IN (?, ?)\n  AND o.title LIKE ?\nORDER BY o.amount"
+	if sqlStr != want {
+		t.Errorf("期望:\n%s\n实际为:\n%s", want, sqlStr)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"east", 1, 2, "%phone%"}) {
+		t.Errorf("参数不符合预期: %v", args)
+	}
+
+	// 无条件时去除空的 WHERE
+	sqlStr, args, err = engine.Render("report/sales", map[string]interface{}{"OrderBy": "o.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT o.id, o.region, o.amount\nFROM orders o\nORDER BY o.id"; sqlStr != want || len(args) != 0 {
+		t.Errorf("期望:\n%s\n实际为:\n%s %v", want, sqlStr, args)
+	}
+}
+
+// 测试输出默认作为参数绑定，防止注入
+func TestRenderBindsOutput(t *testing.T) {
+	engine := NewEngine()
+	if err := engine.Parse("user.update", `UPDATE users SET
+{{- range $i, $c := .Columns}} {{ident $c.Name}} = {{$c.Value}},{{end}}
+WHERE name = {{.Name}} {{raw .Suffix}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	type column struct {
+		Name  string
+		Value interface{}
+	}
+	sqlStr, args, err := engine.Render("user.update", map[string]interface{}{
+		"Columns": []column{{"age", 30}, {"email", "a@example.com"}},
+		"Name":    "x' OR '1'='1",
+		"Suffix":  "AND deleted_at IS NULL",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE users SET age = ?, email = ?\nWHERE name = ? AND deleted_at IS NULL"; sqlStr != want {
+		t.Errorf("期望:\n%s\n实际为:\n%s", want, sqlStr)
+	}
+	if !reflect.DeepEqual(args, []interface{}{30, "a@example.com", "x' OR '1'='1"}) {
+		t.Errorf("参数不符合预期: %v", args)
+	}
+
+	_, _, err = engine.Render("user.update", map[string]interface{}{
+		"Columns": []column{{"age; DROP TABLE users", 1}},
+	})
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("期望返回标识符错误，实际为 %v", err)
+	}
+
+	if _, _, err := engine.Render("missing", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("期望返回模板不存在错误，实际为 %v", err)
+	}
+}
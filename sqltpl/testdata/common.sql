@@ -0,0 +1 @@
+{{define "order.columns"}}o.id, o.region, o.amount{{end}}
@@ -0,0 +1,13 @@
+SELECT {{template "order.columns" .}}
+FROM orders o
+WHERE
+{{- if .Region}}
+  AND o.region = {{.Region}}
+{{- end}}
+{{- if .IDs}}
+  AND o.id IN {{.IDs}}
+{{- end}}
+{{- if .Keyword}}
+  AND o.title LIKE {{printf "%%%s%%" .Keyword}}
+{{- end}}
+ORDER BY {{ident .OrderBy}}
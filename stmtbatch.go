@@ -0,0 +1,306 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gzorm/gosqlx/query"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// ==================== 语句批处理 ====================
+
+// errNotPipelined 连接不支持管道模式，改为按顺序执行
+var errNotPipelined = errors.New("连接不支持管道模式")
+
+// Batch 请求级语句批处理：排队多条相互独立的语句，Flush 时尽量在一次往返中发送
+// PostgreSQL（pgx 驱动）使用管道模式，所有语句在一个隐式事务中执行，其中一条失败时之后的语句也失败；
+// ClickHouse 将连续的同一条单行 INSERT 合并为一个数据块发送；事务中以及其他数据库按顺序逐条执行
+//
+//	b := db.NewBatch()
+//	user := b.Query("SELECT id, name FROM users WHERE id = ?", id)
+//	orders := b.Query("SELECT id, amount FROM orders WHERE user_id = ? LIMIT 10", id)
+//	b.Exec("UPDATE users SET last_seen = ? WHERE id = ?", time.Now(), id)
+//	if err := b.Flush(); err != nil { ... }
+//	// user.Rows, orders.Rows
+type Batch struct {
+	db    *Database
+	items []*BatchResult
+}
+
+// BatchResult 批处理中单条语句的结果，Flush 之后可用
+type BatchResult struct {
+	SQL          string                   // 语句
+	Args         []interface{}            // 参数
+	RowsAffected int64                    // Exec 影响的行数
+	Rows         []map[string]interface{} // Query 的结果行，[]byte 转换为字符串
+	Err          error                    // 语句的错误
+	query        bool
+}
+
+// NewBatch 创建语句批处理，在当前连接（事务中为事务连接）上执行
+func (d *Database) NewBatch() *Batch {
+	return &Batch{db: d}
+}
+
+// Exec 排队一条不返回结果的语句
+func (b *Batch) Exec(sqlStr string, args ...interface{}) *BatchResult {
+	result := &BatchResult{SQL: sqlStr, Args: args}
+	b.items = append(b.items, result)
+	return result
+}
+
+// Query 排队一条查询，结果行在 Flush 后写入 Rows
+func (b *Batch) Query(sqlStr string, args ...interface{}) *BatchResult {
+	result := &BatchResult{SQL: sqlStr, Args: args, query: true}
+	b.items = append(b.items, result)
+	return result
+}
+
+// Len 排队的语句数
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+// Flush 发送所有排队的语句并清空队列，返回第一条语句错误；各语句的结果和错误见 BatchResult
+func (b *Batch) Flush() error {
+	items := b.items
+	b.items = nil
+	if len(items) == 0 {
+		return nil
+	}
+
+	d := b.db
+	var err error
+	switch {
+	case d.dryRun != nil || d.inTx():
+		d.flushSequential(items)
+	case d.dbType == PostgresSQL:
+		if err = d.flushPipeline(items); errors.Is(err, errNotPipelined) {
+			d.flushSequential(items)
+		}
+	case d.dbType == ClickHouse:
+		err = d.flushClickHouse(items)
+	default:
+		d.flushSequential(items)
+	}
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Err != nil {
+			return fmt.Errorf("执行 %s 失败: %w", item.SQL, item.Err)
+		}
+	}
+	return nil
+}
+
+// inTx 当前是否在事务中
+func (d *Database) inTx() bool {
+	pool := d.db.Statement.ConnPool
+	if p, ok := pool.(*retryPool); ok {
+		pool = p.pool
+	}
+	_, ok := pool.(*sql.Tx)
+	return ok
+}
+
+// flushSequential 按顺序逐条执行，语句经由 GORM 执行，策略、超时、注释等回调照常生效
+func (d *Database) flushSequential(items []*BatchResult) {
+	for _, item := range items {
+		if item.query {
+			item.Rows, item.Err = d.QueryMaps(item.SQL, item.Args...)
+			continue
+		}
+		result := d.db.Exec(item.SQL, item.Args...)
+		item.RowsAffected, item.Err = result.RowsAffected, result.Error
+	}
+}
+
+// prepareBatchItem 执行前检查只读模式和语句策略，绑定参数并加上注释；返回 false 表示语句被拒绝
+func (d *Database) prepareBatchItem(ctx context.Context, item *BatchResult) (string, []interface{}, bool) {
+	if !item.query {
+		if item.Err = d.checkReadOnly(item.SQL); item.Err != nil {
+			return "", nil, false
+		}
+	}
+	if item.Err = d.checkPolicy(item.SQL); item.Err != nil {
+		return "", nil, false
+	}
+	args, err := d.bindValues(item.Args)
+	if item.Err = err; err != nil {
+		return "", nil, false
+	}
+	return d.commented(ctx, d.Rebind(item.SQL)), args, true
+}
+
+// flushPipeline 通过 pgx 管道模式在一次往返中发送所有语句
+func (d *Database) flushPipeline(items []*BatchResult) error {
+	ctx, cancel := d.statementContext()
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	var queued []*BatchResult
+	for _, item := range items {
+		sqlStr, args, ok := d.prepareBatchItem(ctx, item)
+		if !ok {
+			continue
+		}
+		batch.Queue(sqlStr, args...)
+		queued = append(queued, item)
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	release, err := d.throttle(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	conn, err := d.sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errNotPipelined
+		}
+		results := c.Conn().SendBatch(ctx, batch)
+		for _, item := range queued {
+			if item.query {
+				item.Rows, item.Err = pgxMaps(results)
+			} else {
+				tag, err := results.Exec()
+				item.RowsAffected, item.Err = tag.RowsAffected(), err
+			}
+			item.Err = query.TimeoutError(ctx, item.Err)
+		}
+		// 语句的错误已记录在各自的结果中，Close 只返回其余的错误
+		err := results.Close()
+		for _, item := range queued {
+			if item.Err != nil {
+				return nil
+			}
+		}
+		return err
+	})
+}
+
+// pgxMaps 读取管道中下一条查询的结果行
+func pgxMaps(results pgx.BatchResults) ([]map[string]interface{}, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	maps := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(fields))
+		for i, field := range fields {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[field.Name] = values[i]
+		}
+		maps = append(maps, row)
+	}
+	return maps, rows.Err()
+}
+
+// flushClickHouse 连续的同一条单行 INSERT 在一个事务中预编译，驱动将各行合并为一个数据块在提交时发送
+// 其他语句按顺序执行
+func (d *Database) flushClickHouse(items []*BatchResult) error {
+	for i := 0; i < len(items); {
+		j := i + 1
+		if singleRowInsert(items[i]) {
+			for j < len(items) && items[j].SQL == items[i].SQL && items[j].query == items[i].query {
+				j++
+			}
+		}
+		if j-i < 2 {
+			d.flushSequential(items[i:j])
+		} else if err := d.insertBlock(items[i:j]); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
+
+// singleRowInsert 是否为只有一组 VALUES 的 INSERT
+func singleRowInsert(item *BatchResult) bool {
+	if item.query {
+		return false
+	}
+	upper := strings.ToUpper(strings.TrimSpace(item.SQL))
+	index := strings.LastIndex(upper, "VALUES")
+	return strings.HasPrefix(upper, "INSERT") && index > 0 && strings.Count(upper[index:], "(") == 1
+}
+
+// insertBlock 将同一条 INSERT 的多行作为一个数据块插入，任一行失败时所有行返回同一错误
+func (d *Database) insertBlock(items []*BatchResult) error {
+	ctx, cancel := d.statementContext()
+	defer cancel()
+
+	var rows [][]interface{}
+	var sqlStr string
+	var queued []*BatchResult
+	for _, item := range items {
+		s, args, ok := d.prepareBatchItem(ctx, item)
+		if !ok {
+			continue
+		}
+		sqlStr = s
+		rows = append(rows, args)
+		queued = append(queued, item)
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	release, err := d.throttle(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	err = func() error {
+		tx, err := d.sqlDB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		stmt, err := tx.PrepareContext(ctx, sqlStr)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, args := range rows {
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}()
+	err = query.TimeoutError(ctx, err)
+	for _, item := range queued {
+		item.Err = err
+		if err == nil {
+			item.RowsAffected = 1
+		}
+	}
+	return nil
+}
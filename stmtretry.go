@@ -0,0 +1,185 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gzorm/gosqlx/adapter"
+	gosqlxerrors "github.com/gzorm/gosqlx/errors"
+	"gorm.io/gorm"
+)
+
+// ==================== 事务内语句重试 ====================
+
+// stmtSavepoint 语句重试使用的隐式保存点名
+const stmtSavepoint = "gosqlx_stmt"
+
+// WithStatementRetry 返回在事务中重试失败语句的数据库实例：事务中的每条语句执行前设置隐式保存点，
+// 语句因锁等待超时、序列化失败或死锁出错时回滚到保存点并重试该语句，最多重试 attempts 次，
+// 避免长事务中的一条语句失败导致整个事务重做；每条语句多两次往返，仅在需要时开启
+// MySQL 系和 SQLServer 的死锁会回滚整个事务，此时不重试语句，错误原样返回
+//
+//	err := db.WithStatementRetry(3).Transaction(func(tx *gosqlx.Database) error {
+//		...
+//	})
+func (d *Database) WithStatementRetry(attempts int) *Database {
+	s := d.session(d.db.WithContext(d.db.Statement.Context))
+	s.stmtRetry = attempts
+	s.wrapStatementRetry()
+	return s
+}
+
+// wrapStatementRetry 在事务中时为事务连接加上语句重试
+func (d *Database) wrapStatementRetry() {
+	if d.tx == nil {
+		return
+	}
+	pool := d.db.Statement.ConnPool
+	if p, ok := pool.(*retryPool); ok {
+		pool = p.pool
+	}
+	if d.stmtRetry <= 0 {
+		d.db.Statement.ConnPool = pool
+		return
+	}
+	if _, ok := pool.(gorm.TxCommitter); !ok || !d.Capabilities().Savepoints {
+		return
+	}
+	retry := &retryPool{pool: pool, dbType: d.dbType, attempts: d.stmtRetry}
+	retry.savepoint, retry.rollbackTo, retry.release = d.savepointStatements()
+	d.db.Statement.ConnPool = retry
+}
+
+// retryPool 事务连接包装，每条语句前设置隐式保存点，语句因可重试的错误失败时回滚到保存点后重试
+type retryPool struct {
+	pool     gorm.ConnPool
+	dbType   DatabaseType
+	attempts int
+	pending  bool // 查询语句的保存点在结果集关闭后（下一条语句前）释放
+
+	savepoint, rollbackTo, release string // 设置、回滚到和释放隐式保存点的语句，不支持释放时 release 为空
+}
+
+// PrepareContext 预编译语句，不重试
+func (p *retryPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.pool.PrepareContext(ctx, query)
+}
+
+// ExecContext 执行语句，失败时回滚到保存点重试
+func (p *retryPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := p.retry(ctx, query, false, func() (err error) {
+		result, err = p.pool.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryContext 执行查询，开始执行时失败则回滚到保存点重试，读取结果集期间的错误不重试
+func (p *retryPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := p.retry(ctx, query, true, func() (err error) {
+		rows, err = p.pool.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext 执行单行查询，错误在 Scan 时才返回，不重试
+func (p *retryPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	p.releasePending(ctx)
+	return p.pool.QueryRowContext(ctx, query, args...)
+}
+
+// Commit 提交事务
+func (p *retryPool) Commit() error {
+	return p.pool.(gorm.TxCommitter).Commit()
+}
+
+// Rollback 回滚事务
+func (p *retryPool) Rollback() error {
+	return p.pool.(gorm.TxCommitter).Rollback()
+}
+
+// retry 设置保存点后执行 fn，可重试的错误回滚到保存点后退避重试；query 为结果集时保存点延后释放
+func (p *retryPool) retry(ctx context.Context, statement string, query bool, fn func() error) error {
+	p.releasePending(ctx)
+	// 保存点和事务控制语句直接执行，避免释放隐式保存点时连带释放嵌套事务的保存点
+	switch keyword, _ := leadingKeyword(statement); keyword {
+	case "SAVEPOINT", "SAVE", "RELEASE", "ROLLBACK", "COMMIT", "BEGIN", "START":
+		return fn()
+	}
+
+	if _, err := p.pool.ExecContext(ctx, p.savepoint); err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if p.release != "" {
+				if query {
+					p.pending = true
+					return nil
+				}
+				_, err = p.pool.ExecContext(ctx, p.release)
+			}
+			return err
+		}
+		if attempt > p.attempts || !statementRetryable(p.dbType, err) {
+			return err
+		}
+		if _, rollbackErr := p.pool.ExecContext(ctx, p.rollbackTo); rollbackErr != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * 10 * time.Millisecond):
+		}
+	}
+}
+
+// releasePending 释放上一条查询语句的保存点
+func (p *retryPool) releasePending(ctx context.Context) {
+	if !p.pending {
+		return
+	}
+	p.pending = false
+	if p.release != "" {
+		_, _ = p.pool.ExecContext(ctx, p.release)
+	}
+}
+
+// savepointStatements 设置、回滚到和释放隐式保存点的语句：适配器实现 adapter.SavepointDialect 时使用其语法，
+// 否则为标准语法，Capabilities 不支持释放保存点时 release 为空
+func (d *Database) savepointStatements() (savepoint, rollbackTo, release string) {
+	if dialect, ok := d.adapter.(adapter.SavepointDialect); ok {
+		return dialect.SavepointSQL(stmtSavepoint)
+	}
+	savepoint, rollbackTo = "SAVEPOINT "+stmtSavepoint, "ROLLBACK TO SAVEPOINT "+stmtSavepoint
+	if d.Capabilities().ReleaseSavepoint {
+		release = "RELEASE SAVEPOINT " + stmtSavepoint
+	}
+	return savepoint, rollbackTo, release
+}
+
+// statementRetryable 判断语句失败后事务是否仍然有效且可重试该语句
+func statementRetryable(dbType DatabaseType, err error) bool {
+	dbErr, ok := gosqlxerrors.Parse(err)
+	if !ok {
+		return false
+	}
+	switch dbErr.Kind {
+	case gosqlxerrors.ErrLockTimeout, gosqlxerrors.ErrSerializationFailure:
+		return true
+	case gosqlxerrors.ErrDeadlock:
+		// MySQL 系和 SQLServer 选为死锁牺牲者时回滚整个事务，保存点已不存在
+		switch dbType {
+		case MySQL, MariaDB, TiDB, OceanBase, SQLServer:
+			return false
+		}
+		return true
+	}
+	return false
+}
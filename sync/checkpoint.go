@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// Position 单表同步位置
+type Position struct {
+	LastKey interface{} `json:"last_key"` // 最后同步的键值（按偏移分页时为nil）
+	Rows    int64       `json:"rows"`     // 已同步行数
+	Done    bool        `json:"done"`     // 是否已完成
+}
+
+// Checkpoint 断点存储接口
+type Checkpoint interface {
+	// Load 读取表的同步位置，不存在时返回nil
+	Load(table string) (*Position, error)
+	// Save 保存表的同步位置
+	Save(table string, pos *Position) error
+}
+
+// FileCheckpoint 基于JSON文件的断点存储
+type FileCheckpoint struct {
+	path      string
+	positions map[string]*Position
+}
+
+// NewFileCheckpoint 创建文件断点存储，文件存在时读取已有位置
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	c := &FileCheckpoint{path: path, positions: make(map[string]*Position)}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return c, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c.positions); err != nil {
+		return nil, err
+	}
+	for _, pos := range c.positions {
+		if number, ok := pos.LastKey.(json.Number); ok {
+			if i, err := number.Int64(); err == nil {
+				pos.LastKey = i
+			} else if f, err := number.Float64(); err == nil {
+				pos.LastKey = f
+			}
+		}
+	}
+	return c, nil
+}
+
+// Load 读取表的同步位置
+func (c *FileCheckpoint) Load(table string) (*Position, error) {
+	return c.positions[table], nil
+}
+
+// Save 保存表的同步位置，先写临时文件再替换，避免中断时文件损坏
+func (c *FileCheckpoint) Save(table string, pos *Position) error {
+	c.positions[table] = pos
+
+	content, err := json.MarshalIndent(c.positions, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,331 @@
+// Package sync 在不同类型的数据库之间流式复制表数据，支持类型转换、分批写入、进度回调和断点续传
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/dialect"
+)
+
+// ErrNoDatabase 源库或目标库为空
+var ErrNoDatabase = errors.New("源数据库和目标数据库不能为空")
+
+// Converter 自定义列值转换函数
+type Converter func(value interface{}) (interface{}, error)
+
+// Table 同步表配置
+type Table struct {
+	Source    string            // 源表
+	Target    string            // 目标表，为空时与源表相同
+	Columns   []string          // 同步列，为空时同步全部列
+	ColumnMap map[string]string // 列名映射，源列名 -> 目标列名
+	KeyColumn string            // 有序唯一键列，用于键集分页与断点续传；为空时按偏移分页
+	Where     string            // 源表过滤条件
+	Args      []interface{}     // 过滤条件参数
+}
+
+// Progress 同步进度
+type Progress struct {
+	Table   string        // 源表
+	Rows    int64         // 已同步行数（含断点前已同步的行）
+	Total   int64         // 源表总行数
+	LastKey interface{}   // 最后同步的键值
+	Elapsed time.Duration // 本次运行耗时
+}
+
+// Options 同步选项
+type Options struct {
+	BatchSize  int                  // 每批行数
+	Checkpoint Checkpoint           // 断点存储，为空时不记录断点
+	OnProgress func(Progress)       // 进度回调，每批写入后调用
+	Converters map[string]Converter // 列值转换，键为 "表.列" 或 "列"
+}
+
+// Syncer 跨库数据同步器
+type Syncer struct {
+	source        *gosqlx.Database
+	target        *gosqlx.Database
+	sourceDialect dialect.Dialect
+	options       Options
+}
+
+// NewSyncer 创建数据同步器
+func NewSyncer(source, target *gosqlx.Database, options Options) (*Syncer, error) {
+	if source == nil || target == nil {
+		return nil, ErrNoDatabase
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	return &Syncer{
+		source:        source,
+		target:        target,
+		sourceDialect: dialect.GetDialect(string(source.Type())),
+		options:       options,
+	}, nil
+}
+
+// Run 按顺序同步表数据
+func (s *Syncer) Run(ctx context.Context, tables ...Table) error {
+	for _, table := range tables {
+		if err := s.syncTable(ctx, table); err != nil {
+			return fmt.Errorf("同步表 %s 失败: %w", table.Source, err)
+		}
+	}
+	return nil
+}
+
+// syncTable 同步单表
+func (s *Syncer) syncTable(ctx context.Context, table Table) error {
+	if table.Target == "" {
+		table.Target = table.Source
+	}
+
+	pos := &Position{}
+	if s.options.Checkpoint != nil {
+		saved, err := s.options.Checkpoint.Load(table.Source)
+		if err != nil {
+			return fmt.Errorf("读取断点失败: %w", err)
+		}
+		if saved != nil {
+			pos = saved
+		}
+	}
+	if pos.Done {
+		return nil
+	}
+
+	total, err := s.count(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		columns, values, lastKey, err := s.fetch(ctx, table, pos)
+		if err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			if err := s.target.BatchInsert(table.Target, columns, values); err != nil {
+				return fmt.Errorf("写入目标表 %s 失败: %w", table.Target, err)
+			}
+			pos.Rows += int64(len(values))
+			pos.LastKey = lastKey
+		}
+		pos.Done = len(values) < s.options.BatchSize
+
+		if s.options.Checkpoint != nil {
+			if err := s.options.Checkpoint.Save(table.Source, pos); err != nil {
+				return fmt.Errorf("保存断点失败: %w", err)
+			}
+		}
+		if s.options.OnProgress != nil {
+			s.options.OnProgress(Progress{
+				Table:   table.Source,
+				Rows:    pos.Rows,
+				Total:   total,
+				LastKey: pos.LastKey,
+				Elapsed: time.Since(start),
+			})
+		}
+		if pos.Done {
+			return nil
+		}
+	}
+}
+
+// count 统计源表行数
+func (s *Syncer) count(ctx context.Context, table Table) (int64, error) {
+	query := "SELECT COUNT(*) FROM " + s.sourceDialect.Quote(table.Source)
+	if table.Where != "" {
+		query += " WHERE " + table.Where
+	}
+	var total int64
+	if err := s.source.DB().WithContext(ctx).Raw(query, table.Args...).Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("统计源表行数失败: %w", err)
+	}
+	return total, nil
+}
+
+// fetch 读取下一批数据，返回目标列名、转换后的数据和最后一行的键值
+func (s *Syncer) fetch(ctx context.Context, table Table, pos *Position) ([]string, [][]interface{}, interface{}, error) {
+	selectColumns := "*"
+	if len(table.Columns) > 0 {
+		quoted := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			quoted[i] = s.sourceDialect.Quote(column)
+		}
+		selectColumns = strings.Join(quoted, ", ")
+	}
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if table.Where != "" {
+		conditions = append(conditions, "("+table.Where+")")
+		args = append(args, table.Args...)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", selectColumns, s.sourceDialect.Quote(table.Source))
+	offset := 0
+	if table.KeyColumn != "" {
+		if pos.LastKey != nil {
+			conditions = append(conditions, s.sourceDialect.Quote(table.KeyColumn)+" > ?")
+			args = append(args, pos.LastKey)
+		}
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		query += " ORDER BY " + s.sourceDialect.Quote(table.KeyColumn)
+	} else {
+		if len(conditions) > 0 {
+			query += " WHERE " + strings.Join(conditions, " AND ")
+		}
+		if s.source.Type() == gosqlx.SQLServer {
+			query += " ORDER BY (SELECT NULL)" // OFFSET FETCH 必须带排序
+		}
+		offset = int(pos.Rows)
+	}
+	query = s.sourceDialect.BuildLimit(query, offset, s.options.BatchSize)
+
+	rows, err := s.source.DB().WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("读取源表失败: %w", err)
+	}
+	defer rows.Close()
+
+	sourceColumns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Oracle 偏移分页会附加行号列，需要排除
+	keep := make([]int, 0, len(sourceColumns))
+	keyIndex := -1
+	var targetColumns []string
+	for i, column := range sourceColumns {
+		if strings.EqualFold(column, "rnum") && len(table.Columns) == 0 && s.source.Type() == gosqlx.Oracle {
+			continue
+		}
+		if strings.EqualFold(column, table.KeyColumn) {
+			keyIndex = i
+		}
+		keep = append(keep, i)
+		if mapped, ok := table.ColumnMap[column]; ok {
+			column = mapped
+		}
+		targetColumns = append(targetColumns, column)
+	}
+	if table.KeyColumn != "" && keyIndex < 0 {
+		return nil, nil, nil, fmt.Errorf("结果中不包含键列 %s", table.KeyColumn)
+	}
+
+	var (
+		values  [][]interface{}
+		lastKey interface{}
+	)
+	for rows.Next() {
+		raw := make([]interface{}, len(sourceColumns))
+		dest := make([]interface{}, len(sourceColumns))
+		for i := range raw {
+			dest[i] = &raw[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, nil, err
+		}
+
+		row := make([]interface{}, 0, len(keep))
+		for _, i := range keep {
+			value, err := s.convert(table.Source, sourceColumns[i], columnTypes[i], raw[i])
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("转换列 %s 失败: %w", sourceColumns[i], err)
+			}
+			row = append(row, value)
+		}
+		values = append(values, row)
+		if keyIndex >= 0 {
+			lastKey = normalizeKey(raw[keyIndex])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	return targetColumns, values, lastKey, nil
+}
+
+// convert 将源库值转换为目标库可写入的值
+func (s *Syncer) convert(table, column string, columnType *sql.ColumnType, value interface{}) (interface{}, error) {
+	if converter, ok := s.options.Converters[table+"."+column]; ok {
+		return converter(value)
+	}
+	if converter, ok := s.options.Converters[column]; ok {
+		return converter(value)
+	}
+	return ConvertValue(value, columnType.DatabaseTypeName(), s.target.Type())
+}
+
+// ConvertValue 按源列类型和目标库类型转换值
+func ConvertValue(value interface{}, sourceType string, target gosqlx.DatabaseType) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		if isBinaryType(sourceType) {
+			return v, nil
+		}
+		return string(v), nil
+	case bool:
+		if target == gosqlx.Oracle {
+			if v {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case uint64:
+		// Postgres 不支持无符号整数，超出范围时转为字符串写入 NUMERIC
+		if target == gosqlx.PostgresSQL && v > math.MaxInt64 {
+			return fmt.Sprint(v), nil
+		}
+	case time.Time:
+		if target == gosqlx.ClickHouse && v.IsZero() {
+			return time.Unix(0, 0).UTC(), nil
+		}
+	}
+	return value, nil
+}
+
+// isBinaryType 判断是否为二进制列类型
+func isBinaryType(typeName string) bool {
+	typeName = strings.ToUpper(typeName)
+	for _, name := range []string{"BLOB", "BINARY", "BYTEA", "IMAGE", "RAW"} {
+		if strings.Contains(typeName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey 规范化键值，便于作为查询参数和断点存储
+func normalizeKey(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/adapter"
+)
+
+// 初始化DuckDB数据库连接，未导入 DuckDB 驱动时跳过（生成的语句由 adapter 包的 TestDuckDBStatements 覆盖）
+func initDuckDB(t *testing.T) *gosqlx.Database {
+	if !slices.Contains(sql.Drivers(), adapter.DuckDBDriverName) {
+		t.Skip("未导入 DuckDB 驱动")
+	}
+
+	config := &gosqlx.Config{
+		Type:        gosqlx.DuckDB,
+		Source:      filepath.Join(t.TempDir(), "test.duckdb"),
+		MaxIdle:     1,
+		MaxOpen:     1,
+		MaxLifetime: time.Hour,
+	}
+	ctx := gosqlx.NewContext(context.Background(), "test_duckdb", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, config)
+	if err != nil {
+		t.Fatalf("初始化数据库失败: %v", err)
+	}
+	return db
+}
+
+// 测试DuckDB批量插入、查询构建器和 Parquet 读写
+func TestDuckDBAnalytics(t *testing.T) {
+	db := initDuckDB(t)
+	defer db.Close()
+
+	if err := db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, kind VARCHAR, amount DOUBLE)"); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	rows := [][]interface{}{{1, "view", 1.5}, {2, "click", 2.5}, {3, "click", 4.0}}
+	if err := db.BatchInsert("events", []string{"id", "kind", "amount"}, rows); err != nil {
+		t.Fatalf("批量插入失败: %v", err)
+	}
+	if err := db.MergeInto("events", []string{"id", "kind", "amount"}, [][]interface{}{{1, "view", 3.0}}, []string{"id"}, []string{"amount"}); err != nil {
+		t.Fatalf("合并插入失败: %v", err)
+	}
+
+	total, err := db.NewQuery().Table("events").Where("kind = ?", "click").SumNum("amount")
+	if err != nil || total != 6.5 {
+		t.Errorf("聚合查询不符合预期: %v, %v", total, err)
+	}
+
+	// 导出为 Parquet 后通过表函数查询
+	path := filepath.Join(t.TempDir(), "events.parquet")
+	if err := adapter.NewDuckDB("").CopyToParquet(db.DB(), "SELECT * FROM events", path); err != nil {
+		t.Fatalf("导出 Parquet 失败: %v", err)
+	}
+	count, err := db.NewQuery().Table(adapter.ReadParquet(path)).Where("amount > ?", 2).CountNum()
+	if err != nil || count != 3 {
+		t.Errorf("查询 Parquet 不符合预期: %d, %v", count, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/adapter"
 	"github.com/gzorm/gosqlx/builder"
 	"github.com/gzorm/gosqlx/query"
 )
@@ -14,7 +15,7 @@ import (
 // 测试用的用户模型
 type MongoUser struct {
 	ID        int64     `db:"_id"`
-	Username  string    `db:"username"`
+	Username  string    `db:"username" mongo:"unique"`
 	Email     string    `db:"email"`
 	Age       int       `db:"age"`
 	Active    bool      `db:"active"`
@@ -22,16 +23,22 @@ type MongoUser struct {
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
+// TableName 用户集合名
+func (MongoUser) TableName() string { return "users" }
+
 // 测试用的文章模型
 type MongoArticle struct {
 	ID        string    `db:"_id"`
-	UserID    int64     `db:"user_id"`
+	UserID    int64     `db:"user_id" mongo:"index"`
 	Title     string    `db:"title"`
 	Content   string    `db:"content"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
+// TableName 文章集合名
+func (MongoArticle) TableName() string { return "articles" }
+
 // 用户文章关联查询结果
 type MongoUserArticle struct {
 	UserID       string `db:"user_id"`
@@ -85,16 +92,10 @@ func prepareMongoTestCollections(t *testing.T, db *gosqlx.Database) {
 		t.Logf("删除文章集合失败: %v", err)
 	}
 
-	// 创建用户集合索引
-	err = db.Exec("db.users.createIndex({username: 1}, {unique: true})")
+	// 按模型标签创建集合索引
+	err = db.EnsureIndexes(&MongoUser{}, &MongoArticle{})
 	if err != nil {
-		t.Fatalf("创建用户集合索引失败: %v", err)
-	}
-
-	// 创建文章集合索引
-	err = db.Exec("db.articles.createIndex({user_id: 1})")
-	if err != nil {
-		t.Fatalf("创建文章集合索引失败: %v", err)
+		t.Fatalf("创建集合索引失败: %v", err)
 	}
 }
 
@@ -913,10 +914,15 @@ func TestMongoIndex(t *testing.T) {
 	// 准备测试集合
 	prepareMongoTestCollections(t, db)
 
-	// 创建索引
-	err := db.Exec("db.users.createIndex({email: 1}, {unique: true, name: 'idx_email'})")
-	if err != nil {
-		t.Fatalf("创建索引失败: %v", err)
+	// 创建索引，重复执行时不应报错
+	type indexedUser struct {
+		MongoUser
+		Email string `db:"email" mongo:"unique:idx_email"`
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.EnsureIndexes(&indexedUser{}); err != nil {
+			t.Fatalf("创建索引失败: %v", err)
+		}
 	}
 
 	// 验证索引是否创建成功
@@ -960,6 +966,64 @@ func TestMongoIndex(t *testing.T) {
 	t.Logf("删除索引成功")
 }
 
+// 测试索引标签解析
+func TestMongoIndexTags(t *testing.T) {
+	type session struct {
+		TenantID  int64     `db:"tenant_id" mongo:"unique:uk_tenant_token"`
+		Token     string    `db:"token" mongo:"unique:uk_tenant_token"`
+		UserID    int64     `bson:"uid" mongo:"index,desc"`
+		Title     string    `db:"title" mongo:"text"`
+		Body      string    `db:"body" mongo:"text"`
+		ExpiresAt time.Time `db:"expires_at" mongo:"index,ttl:3600;index:idx_expires_user"`
+		Ignored   string    `db:"ignored"`
+	}
+
+	indexes, err := adapter.MongoIndexes(&session{})
+	if err != nil {
+		t.Fatalf("解析索引标签失败: %v", err)
+	}
+	got := make(map[string]adapter.MongoIndex)
+	for _, index := range indexes {
+		got[index.Name] = index
+	}
+	if len(got) != 5 {
+		t.Fatalf("索引数量不正确: %+v", indexes)
+	}
+	if index := got["uk_tenant_token"]; !index.Unique || len(index.Keys) != 2 || index.Keys[1].Key != "token" {
+		t.Errorf("复合唯一索引不正确: %+v", index)
+	}
+	if index, ok := got["uid_-1"]; !ok || index.Keys[0].Value != int32(-1) {
+		t.Errorf("降序索引不正确: %+v", indexes)
+	}
+	if index, ok := got["title_text_body_text"]; !ok || len(index.Keys) != 2 {
+		t.Errorf("文本索引不正确: %+v", indexes)
+	}
+	if index, ok := got["expires_at_1"]; !ok || index.ExpireAfter == nil || *index.ExpireAfter != 3600 {
+		t.Errorf("TTL 索引不正确: %+v", indexes)
+	}
+	if index, ok := got["idx_expires_user"]; !ok || index.ExpireAfter != nil {
+		t.Errorf("命名索引不正确: %+v", indexes)
+	}
+
+	invalid := []interface{}{
+		&struct {
+			A string `mongo:"fulltext"`
+		}{},
+		&struct {
+			A string `mongo:"index,ttl:abc"`
+		}{},
+		&struct {
+			A string `mongo:"index:idx_ab,ttl:60"`
+			B string `mongo:"index:idx_ab"`
+		}{},
+	}
+	for _, model := range invalid {
+		if _, err := adapter.MongoIndexes(model); err == nil {
+			t.Errorf("非法索引标签应返回错误: %T", model)
+		}
+	}
+}
+
 // 测试聚合管道
 func TestMongoAggregatePipeline(t *testing.T) {
 	// 初始化数据库
@@ -3,13 +3,14 @@ package test
 import (
 	"context"
 	"fmt"
-
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gzorm/gosqlx/adapter"
 	"github.com/gzorm/gosqlx/dialect"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	"github.com/gzorm/gosqlx"
 	"github.com/gzorm/gosqlx/builder"
@@ -1507,3 +1508,105 @@ func prepareTestData(t *testing.T, db *gosqlx.Database) {
 		}
 	}
 }
+
+func TestMySQLInsertOnDuplicate(t *testing.T) {
+	db := initMySQLDB(t)
+	defer db.Close()
+
+	if err := db.Exec("DROP TABLE IF EXISTS counters"); err != nil {
+		t.Fatalf("删除计数表失败: %v", err)
+	}
+	err := db.Exec(`
+		CREATE TABLE counters (
+			name VARCHAR(50) PRIMARY KEY,
+			count INT NOT NULL,
+			source VARCHAR(20) NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		t.Fatalf("创建计数表失败: %v", err)
+	}
+	defer db.Exec("DROP TABLE IF EXISTS counters")
+
+	mysqlAdapter := db.Adapter().(*adapter.MySQL)
+	columns := []string{"name", "count"}
+	inserted, err := mysqlAdapter.BatchInsertIgnore(db.DB(), "counters", columns, [][]interface{}{{"visits", 1}, {"visits", 9}, {"clicks", 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), inserted)
+
+	// 冲突时累加，并写入常量
+	updates := map[string]interface{}{"count": gorm.Expr("count + VALUES(count)"), "source": "adapter"}
+	err = mysqlAdapter.BatchInsertOnDuplicate(db.DB(), "counters", columns, [][]interface{}{{"visits", 3}, {"views", 4}}, updates)
+	assert.NoError(t, err)
+
+	// 构建器
+	_, err = db.NewQuery().Table("counters").InsertOnDuplicate(columns, [][]interface{}{{"clicks", 5}},
+		map[string]interface{}{"count": query.Raw("count + VALUES(count)"), "source": query.Raw("CONCAT(source, ?)", "builder")})
+	assert.NoError(t, err)
+	inserted, err = db.NewQuery().Table("counters").InsertIgnore(columns, [][]interface{}{{"clicks", 100}, {"likes", 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), inserted)
+
+	var rows []struct {
+		Name   string
+		Count  int
+		Source string
+	}
+	assert.NoError(t, db.DB().Raw("SELECT name, count, source FROM counters ORDER BY name").Scan(&rows).Error)
+	want := []string{"clicks:7:builder", "likes:1:", "views:4:", "visits:4:adapter"}
+	got := make([]string, len(rows))
+	for i, row := range rows {
+		got[i] = fmt.Sprintf("%s:%d:%s", row.Name, row.Count, row.Source)
+	}
+	assert.Equal(t, want, got)
+}
+
+// MySQLAccount 带有数据库默认值和生成列的模型
+type MySQLAccount struct {
+	ID      int64     `gorm:"primaryKey"`
+	Name    string    `gorm:"column:name"`
+	Upper   string    `gorm:"column:upper_name;->"`
+	Rev     int       `gorm:"column:rev;->"`
+	Created time.Time `gorm:"column:created;->"`
+}
+
+// TableName 表名
+func (MySQLAccount) TableName() string {
+	return "accounts"
+}
+
+// MySQL 不支持 RETURNING，插入后按主键重新查询
+func TestMySQLCreateRefresh(t *testing.T) {
+	db := initMySQLDB(t)
+	defer db.Close()
+
+	if err := db.Exec("DROP TABLE IF EXISTS accounts"); err != nil {
+		t.Fatalf("删除账户表失败: %v", err)
+	}
+	err := db.Exec(`
+		CREATE TABLE accounts (
+			id BIGINT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(50) NOT NULL,
+			upper_name VARCHAR(50) AS (UPPER(name)),
+			rev INT NOT NULL DEFAULT 1,
+			created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		t.Fatalf("创建账户表失败: %v", err)
+	}
+	defer db.Exec("DROP TABLE IF EXISTS accounts")
+
+	account := MySQLAccount{Name: "neo"}
+	assert.NoError(t, db.WithRefresh().Create(&account))
+	assert.Equal(t, "NEO", account.Upper)
+	assert.Equal(t, 1, account.Rev)
+	assert.False(t, account.Created.IsZero())
+
+	accounts := []MySQLAccount{{Name: "a"}, {Name: "b"}}
+	assert.NoError(t, db.WithRefresh().CreateInBatches(&accounts, 10))
+	for _, account := range accounts {
+		assert.Equal(t, strings.ToUpper(account.Name), account.Upper)
+		assert.Equal(t, 1, account.Rev)
+	}
+}
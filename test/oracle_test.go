@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/gzorm/gosqlx"
+	"github.com/gzorm/gosqlx/adapter"
 	"github.com/gzorm/gosqlx/query"
 	"testing"
 	"time"
@@ -719,3 +720,48 @@ func TestOracleQueryBuilderTransaction(t *testing.T) {
 
 	t.Logf("Query构建器事务操作成功，用户ID: %d", userID)
 }
+
+// 测试 ALTER SESSION 语句生成和管理语句的参数校验
+func TestOracleAlterSessionSQL(t *testing.T) {
+	o := adapter.NewOracle("")
+	cases := []struct {
+		key   string
+		value interface{}
+		want  string
+	}{
+		{"nls_date_format", "YYYY-MM-DD HH24:MI:SS", "ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY-MM-DD HH24:MI:SS'"},
+		{"NLS_NUMERIC_CHARACTERS", "'.,", "ALTER SESSION SET NLS_NUMERIC_CHARACTERS = '''.,'"},
+		{"current_schema", "hr", "ALTER SESSION SET CURRENT_SCHEMA = HR"},
+		{"SQL_TRACE", true, "ALTER SESSION SET SQL_TRACE = TRUE"},
+		{"DDL_LOCK_TIMEOUT", 30, "ALTER SESSION SET DDL_LOCK_TIMEOUT = 30"},
+	}
+	for _, c := range cases {
+		got, err := o.AlterSessionSQL(c.key, c.value)
+		if err != nil || got != c.want {
+			t.Errorf("AlterSessionSQL(%q, %v) = %q, %v, want %q", c.key, c.value, got, err, c.want)
+		}
+	}
+
+	invalid := []struct {
+		key   string
+		value interface{}
+	}{
+		{"NLS_DATE_FORMAT; DROP TABLE users", "x"},
+		{"CURRENT_SCHEMA", "hr; DROP"},
+		{"CURRENT_SCHEMA", "a.b"},
+		{"NLS_SORT", []string{"BINARY"}},
+	}
+	for _, c := range invalid {
+		if got, err := o.AlterSessionSQL(c.key, c.value); err == nil {
+			t.Errorf("AlterSessionSQL(%q, %v) = %q, 期望返回错误", c.key, c.value, got)
+		}
+	}
+
+	// 参数不合法时在访问数据库前返回错误
+	if err := o.KillSession(nil, -1, 5, 0, true); err == nil {
+		t.Error("KillSession 应拒绝负数会话标识")
+	}
+	if err := o.SetCurrentSchema(nil, "hr'"); err == nil {
+		t.Error("SetCurrentSchema 应拒绝非法模式名")
+	}
+}
@@ -1173,3 +1173,24 @@ func TestPostgresWindowFunction(t *testing.T) {
 
 	t.Logf("窗口函数查询成功，记录数: %d", len(results))
 }
+
+// 测试语句批处理通过管道模式在一次往返中执行
+func TestPostgresBatch(t *testing.T) {
+	db := initPostgresDB(t)
+	defer db.Close()
+
+	if err := db.Exec("DROP TABLE IF EXISTS batch_items; CREATE TABLE batch_items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	b := db.NewBatch()
+	for i := 1; i <= 3; i++ {
+		b.Exec("INSERT INTO batch_items (id, name) VALUES (?, ?)", i, fmt.Sprintf("item%d", i))
+	}
+	items := b.Query("SELECT id, name FROM batch_items WHERE id > ? ORDER BY id", 1)
+	if err := b.Flush(); err != nil {
+		t.Fatalf("Flush 失败: %v", err)
+	}
+	if len(items.Rows) != 2 || items.Rows[0]["name"] != "item2" {
+		t.Errorf("查询结果 = %v", items.Rows)
+	}
+}
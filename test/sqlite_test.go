@@ -1,16 +1,41 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"math/big"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/gzorm/gosqlx"
 	"github.com/gzorm/gosqlx/adapter"
+	"github.com/gzorm/gosqlx/archive"
+	"github.com/gzorm/gosqlx/docstore"
+	gosqlxerrors "github.com/gzorm/gosqlx/errors"
+	"github.com/gzorm/gosqlx/export"
+	"github.com/gzorm/gosqlx/idgen"
 	"github.com/gzorm/gosqlx/query"
+	"github.com/gzorm/gosqlx/queue"
+	"github.com/gzorm/gosqlx/retention"
+	"github.com/gzorm/gosqlx/sqltpl"
+	gosqlxsync "github.com/gzorm/gosqlx/sync"
+	gosqlxtesting "github.com/gzorm/gosqlx/testing"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // 用户结构体
@@ -1157,3 +1182,3549 @@ func TestSQLiteBuildDSN(t *testing.T) {
 
 	t.Logf("DSN构建成功: %s", dsn)
 }
+
+// 测试执行计划
+func TestSQLiteExplain(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	if err := db.Exec("CREATE INDEX idx_users_email ON users (email)"); err != nil {
+		t.Fatalf("创建索引失败: %v", err)
+	}
+
+	// 使用索引的查询
+	plan, err := query.NewQuery(db.SqlDB()).
+		Table("users").
+		Where("email = ?", "user@example.com").
+		Explain()
+	if err != nil {
+		t.Fatalf("获取执行计划失败: %v", err)
+	}
+	if plan.Dialect != "sqlite3" {
+		t.Errorf("期望方言为 sqlite3，实际为 %s", plan.Dialect)
+	}
+	if indexes := plan.Indexes(); len(indexes) != 1 || indexes[0] != "idx_users_email" {
+		t.Errorf("期望使用索引 idx_users_email，实际为 %v，计划: %s", indexes, plan.Text)
+	}
+	if plan.HasFullScan() {
+		t.Errorf("索引查询不应全表扫描，计划: %s", plan.Text)
+	}
+
+	// 全表扫描的查询
+	plan, err = query.NewQuery(db.DB()).
+		Table("users").
+		Where("age > ?", 18).
+		Explain()
+	if err != nil {
+		t.Fatalf("获取执行计划失败: %v", err)
+	}
+	if !plan.HasFullScan() {
+		t.Errorf("期望全表扫描，计划: %s", plan.Text)
+	}
+}
+
+// 表结构同步测试模型
+type SQLiteSyncUser struct {
+	ID       int64  `gorm:"primaryKey"`
+	Username string `gorm:"size:64;not null"`
+	Email    string `gorm:"size:128;index:idx_users_email"`
+	Nickname string `gorm:"size:64"`
+}
+
+func (SQLiteSyncUser) TableName() string {
+	return "users"
+}
+
+// 测试表结构同步
+func TestSQLiteSyncSchema(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	// 仅生成SQL
+	result, err := db.SyncSchema(gosqlx.SyncOptions{DryRun: true}, &SQLiteSyncUser{})
+	if err != nil {
+		t.Fatalf("生成同步SQL失败: %v", err)
+	}
+	expected := []string{
+		"ALTER TABLE `users` ADD `nickname` text",
+		"CREATE INDEX `idx_users_email` ON `users` (`email`)",
+	}
+	if strings.Join(result.Statements, ";") != strings.Join(expected, ";") {
+		t.Errorf("同步SQL不符合预期: %v", result.Statements)
+	}
+	if len(result.Skipped) != 3 {
+		t.Errorf("期望跳过 3 条破坏性变更，实际为 %v", result.Skipped)
+	}
+	if db.DB().Migrator().HasColumn(&SQLiteSyncUser{}, "nickname") {
+		t.Error("DryRun 模式不应执行SQL")
+	}
+
+	// 执行同步
+	if _, err = db.SyncSchema(gosqlx.SyncOptions{}, &SQLiteSyncUser{}); err != nil {
+		t.Fatalf("同步表结构失败: %v", err)
+	}
+	if !db.DB().Migrator().HasColumn(&SQLiteSyncUser{}, "nickname") {
+		t.Error("期望添加 nickname 列")
+	}
+	if !db.DB().Migrator().HasColumn(&SQLiteSyncUser{}, "age") {
+		t.Error("未开启 AllowDestructive 时不应删除列")
+	}
+
+	// 再次同步无变更
+	result, err = db.SyncSchema(gosqlx.SyncOptions{DryRun: true}, &SQLiteSyncUser{})
+	if err != nil || len(result.Statements) != 0 {
+		t.Errorf("期望无待执行SQL，实际为 %v, err=%v", result.Statements, err)
+	}
+}
+
+// 测试测试数据加载与事务回滚
+func TestSQLiteFixtures(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	h := gosqlxtesting.New(t, db).ExecSQLFile("testdata/schema.sql")
+
+	t.Run("tx", func(t *testing.T) {
+		tx := gosqlxtesting.New(t, db).Tx().LoadFixtures("testdata/fixtures")
+
+		var count int64
+		tx.DB().DB().Table("articles").Count(&count)
+		if count != 2 {
+			t.Errorf("期望文章数为 2，实际为 %d", count)
+		}
+
+		var username string
+		tx.DB().DB().Raw("SELECT u.username FROM articles a JOIN users u ON u.id = a.user_id WHERE a.id = ?", 2).Scan(&username)
+		if username != "user2" {
+			t.Errorf("期望作者为 user2，实际为 %s", username)
+		}
+	})
+
+	// 子测试结束后事务已回滚
+	var count int64
+	h.DB().DB().Table("users").Count(&count)
+	if count != 0 {
+		t.Errorf("期望事务回滚后用户数为 0，实际为 %d", count)
+	}
+}
+
+// planTB 记录失败信息的测试接口
+type planTB struct {
+	*testing.T
+	failures []string
+}
+
+func (tb *planTB) Fatalf(format string, args ...interface{}) {
+	tb.failures = append(tb.failures, fmt.Sprintf(format, args...))
+}
+
+// 测试执行计划回归检查
+func TestSQLitePlanRegression(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	h := gosqlxtesting.New(t, db).ExecSQL(`CREATE TABLE plan_orders (id INTEGER PRIMARY KEY, user_id INTEGER, status TEXT);
+		CREATE INDEX idx_plan_orders_user ON plan_orders (user_id)`)
+	dir := t.TempDir()
+	byUser := func() *query.Query { return db.NewQuery().Table("plan_orders").Where("user_id = ?", 1) }
+
+	// 记录基线后再次检查通过
+	h.Plans(gosqlxtesting.PlanOptions{Dir: dir, Update: true}).Add("by_user", byUser()).Check()
+	content, err := os.ReadFile(filepath.Join(dir, "sqlite3.json"))
+	if err != nil || !strings.Contains(string(content), "idx_plan_orders_user") {
+		t.Fatalf("基线 = %s, %v", content, err)
+	}
+	h.Plans(gosqlxtesting.PlanOptions{Dir: dir}).Add("by_user", byUser()).Check()
+
+	// 删除索引后变为全表扫描
+	h.ExecSQL("DROP INDEX idx_plan_orders_user")
+	tb := &planTB{T: t}
+	gosqlxtesting.New(tb, db).Plans(gosqlxtesting.PlanOptions{Dir: dir}).
+		Add("by_user", byUser()).
+		Add("by_status", db.NewQuery().Table("plan_orders").Where("status = ?", "paid")).
+		Check()
+	if len(tb.failures) != 1 || !strings.Contains(tb.failures[0], "by_user: 表 plan_orders 变为全表扫描") ||
+		!strings.Contains(tb.failures[0], "by_status: 基线") {
+		t.Errorf("回归信息 = %q", tb.failures)
+	}
+
+	// 允许全表扫描的表不报告
+	tb = &planTB{T: t}
+	gosqlxtesting.New(tb, db).Plans(gosqlxtesting.PlanOptions{Dir: dir, AllowFullScans: []string{"plan_orders"}}).Add("by_user", byUser()).Check()
+	if len(tb.failures) != 0 {
+		t.Errorf("不应报告允许的全表扫描: %q", tb.failures)
+	}
+}
+
+// 测试跨库数据同步与断点续传
+func TestSQLiteSync(t *testing.T) {
+	source := initSQLiteDB(t)
+	defer source.Close()
+	target := initSQLiteDB(t)
+	defer target.Close()
+
+	prepareSQLiteTestTables(t, source)
+	prepareSQLiteTestTables(t, target)
+
+	for i := 1; i <= 10; i++ {
+		if err := source.Exec("INSERT INTO users (username, email, age, active) VALUES (?, ?, ?, ?)",
+			fmt.Sprintf("user%d", i), fmt.Sprintf("user%d@example.com", i), 20+i, i%2); err != nil {
+			t.Fatalf("插入源数据失败: %v", err)
+		}
+	}
+
+	checkpointFile := fmt.Sprintf("./sync_checkpoint_%d.json", time.Now().UnixNano())
+	defer os.Remove(checkpointFile)
+	checkpoint, err := gosqlxsync.NewFileCheckpoint(checkpointFile)
+	if err != nil {
+		t.Fatalf("创建断点存储失败: %v", err)
+	}
+
+	// 第一次运行在写入首批后中断
+	ctx, cancel := context.WithCancel(context.Background())
+	var progress []gosqlxsync.Progress
+	syncer, err := gosqlxsync.NewSyncer(source, target, gosqlxsync.Options{
+		BatchSize:  4,
+		Checkpoint: checkpoint,
+		OnProgress: func(p gosqlxsync.Progress) {
+			progress = append(progress, p)
+			cancel()
+		},
+	})
+	if err != nil {
+		t.Fatalf("创建同步器失败: %v", err)
+	}
+	table := gosqlxsync.Table{Source: "users", KeyColumn: "id", Where: "age > ?", Args: []interface{}{20}}
+	if err := syncer.Run(ctx, table); err == nil {
+		t.Fatal("期望同步被中断")
+	}
+	if len(progress) != 1 || progress[0].Rows != 4 || progress[0].Total != 10 {
+		t.Fatalf("进度不符合预期: %+v", progress)
+	}
+
+	// 从断点恢复
+	checkpoint, err = gosqlxsync.NewFileCheckpoint(checkpointFile)
+	if err != nil {
+		t.Fatalf("读取断点失败: %v", err)
+	}
+	syncer, _ = gosqlxsync.NewSyncer(source, target, gosqlxsync.Options{BatchSize: 4, Checkpoint: checkpoint})
+	if err := syncer.Run(context.Background(), table); err != nil {
+		t.Fatalf("恢复同步失败: %v", err)
+	}
+
+	var count int64
+	target.DB().Table("users").Count(&count)
+	if count != 10 {
+		t.Errorf("期望目标表行数为 10，实际为 %d", count)
+	}
+	var email string
+	target.DB().Raw("SELECT email FROM users WHERE id = ?", 10).Scan(&email)
+	if email != "user10@example.com" {
+		t.Errorf("目标数据不符合预期: %s", email)
+	}
+
+	// 已完成的表再次运行不重复写入
+	if err := syncer.Run(context.Background(), table); err != nil {
+		t.Fatalf("重复同步失败: %v", err)
+	}
+	target.DB().Table("users").Count(&count)
+	if count != 10 {
+		t.Errorf("重复运行后期望行数为 10，实际为 %d", count)
+	}
+}
+
+// 测试基于锁表的分布式锁
+func TestSQLiteLocker(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	a := gosqlx.NewLocker(db)
+	b := gosqlx.NewLocker(db)
+
+	ok, err := a.AcquireLock("job:report", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("期望获取锁成功，ok=%v err=%v", ok, err)
+	}
+	if ok, err = b.AcquireLock("job:report", time.Minute); err != nil || ok {
+		t.Errorf("锁已被持有，期望获取失败，ok=%v err=%v", ok, err)
+	}
+	if ok, err = a.AcquireLock("job:report", time.Minute); err != nil || !ok {
+		t.Errorf("持有者重复获取应成功，ok=%v err=%v", ok, err)
+	}
+
+	if err = a.ReleaseLock("job:report"); err != nil {
+		t.Fatalf("释放锁失败: %v", err)
+	}
+	if err = a.ReleaseLock("job:report"); err != gosqlx.ErrLockNotHeld {
+		t.Errorf("重复释放期望返回 ErrLockNotHeld，实际为 %v", err)
+	}
+
+	// 过期后其他实例可获取
+	if ok, _ = b.AcquireLock("job:sync", 50*time.Millisecond); !ok {
+		t.Fatal("期望获取锁成功")
+	}
+	time.Sleep(100 * time.Millisecond)
+	ran, err := a.WithLock("job:sync", time.Minute, func() error { return nil })
+	if err != nil || !ran {
+		t.Errorf("锁过期后期望获取成功，ran=%v err=%v", ran, err)
+	}
+}
+
+// 测试任务队列的重试、死信与可见性超时
+func TestSQLiteQueue(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	q, err := queue.New(db, "emails", queue.Options{
+		VisibilityTimeout: 50 * time.Millisecond,
+		MaxAttempts:       2,
+		RetryDelay:        func(attempt int) time.Duration { return 0 },
+	})
+	if err != nil {
+		t.Fatalf("创建队列失败: %v", err)
+	}
+
+	type payload struct {
+		To string `json:"to"`
+	}
+	first, err := q.Enqueue(payload{To: "a@example.com"})
+	if err != nil {
+		t.Fatalf("添加任务失败: %v", err)
+	}
+	if _, err = q.EnqueueAt("later", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("添加延迟任务失败: %v", err)
+	}
+
+	// 失败后重试，超过最大次数进入死信
+	for attempt := 1; attempt <= 2; attempt++ {
+		job, err := q.Dequeue()
+		if err != nil || job == nil || job.ID != first.ID || job.Attempts != attempt {
+			t.Fatalf("第 %d 次领取任务不符合预期: %+v, err=%v", attempt, job, err)
+		}
+		var p payload
+		if err := job.Decode(&p); err != nil || p.To != "a@example.com" {
+			t.Errorf("任务内容不符合预期: %+v, err=%v", p, err)
+		}
+		if err := q.Fail(job, fmt.Errorf("发送失败")); err != nil {
+			t.Fatalf("标记失败出错: %v", err)
+		}
+	}
+	if job, _ := q.Dequeue(); job != nil {
+		t.Fatalf("延迟任务和死信任务不应被领取: %+v", job)
+	}
+	dead, err := q.DeadJobs(10)
+	if err != nil || len(dead) != 1 || dead[0].LastError != "发送失败" {
+		t.Fatalf("死信任务不符合预期: %+v, err=%v", dead, err)
+	}
+
+	// 重新排队后超时未确认，会被再次投递
+	if err := q.Requeue(first.ID); err != nil {
+		t.Fatalf("重新排队失败: %v", err)
+	}
+	job, _ := q.Dequeue()
+	if job == nil {
+		t.Fatal("期望领取到重新排队的任务")
+	}
+	time.Sleep(100 * time.Millisecond)
+	redelivered, _ := q.Dequeue()
+	if redelivered == nil || redelivered.ID != job.ID || redelivered.Attempts != 2 {
+		t.Fatalf("期望超时任务被重新投递: %+v", redelivered)
+	}
+	if err := q.Ack(job); err != queue.ErrJobLost {
+		t.Errorf("原执行者确认应返回 ErrJobLost，实际为 %v", err)
+	}
+	if err := q.Ack(redelivered); err != nil {
+		t.Errorf("确认任务失败: %v", err)
+	}
+}
+
+// 测试号段ID生成器
+func TestSQLiteTicketID(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	a, err := idgen.NewTicket(db, "orders", 10)
+	if err != nil {
+		t.Fatalf("创建号段生成器失败: %v", err)
+	}
+	b, _ := idgen.NewTicket(db, "orders", 10)
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 25; i++ {
+		for _, g := range []idgen.IDGenerator{a, b} {
+			id, err := g.Next()
+			if err != nil {
+				t.Fatalf("生成ID失败: %v", err)
+			}
+			if seen[id] {
+				t.Fatalf("ID重复: %d", id)
+			}
+			seen[id] = true
+		}
+	}
+	// 两个生成器各申请 3 个号段，共 60 个ID
+	for id := range seen {
+		if id < 1 || id > 60 {
+			t.Errorf("ID超出已申请号段: %d", id)
+		}
+	}
+
+	if _, err := idgen.NewSequence(db, "seq_orders"); err == nil {
+		t.Error("SQLite 不支持序列，期望返回错误")
+	}
+}
+
+// 测试行数估算与估算分页
+func TestSQLiteEstimateCount(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+	for i := 1; i <= 20; i++ {
+		if err := db.Exec("INSERT INTO users (username, email, age) VALUES (?, ?, ?)",
+			fmt.Sprintf("user%d", i), fmt.Sprintf("user%d@example.com", i), 20+i); err != nil {
+			t.Fatalf("插入测试数据失败: %v", err)
+		}
+	}
+
+	// 未收集统计信息时回退到精确计数
+	count, err := db.EstimateCount("users")
+	if err != nil || count != 20 {
+		t.Fatalf("期望行数为 20，实际为 %d, err=%v", count, err)
+	}
+
+	// 收集统计信息后使用估算值（删除数据后统计信息未更新）
+	if err := db.Exec("ANALYZE"); err != nil {
+		t.Fatalf("收集统计信息失败: %v", err)
+	}
+	if err := db.Exec("DELETE FROM users WHERE id > 15"); err != nil {
+		t.Fatalf("删除数据失败: %v", err)
+	}
+	if count, _ = db.EstimateCount("users"); count != 20 {
+		t.Errorf("期望估算行数为 20，实际为 %d", count)
+	}
+
+	var users []SQLiteUser
+	total, err := db.QueryPage(gosqlx.PageOptions{DB: db.DB(), ApproximateTotal: true}, &users, 2, 5, "users", []interface{}{"id DESC"})
+	if err != nil {
+		t.Fatalf("估算分页查询失败: %v", err)
+	}
+	if total != 20 || len(users) != 5 || users[0].ID != 10 {
+		t.Errorf("估算分页结果不符合预期: total=%d, users=%d", total, len(users))
+	}
+}
+
+// 测试批量更新与批量删除
+func TestSQLiteBatchUpdateDelete(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+	for i := 1; i <= 1200; i++ {
+		if err := db.Exec("INSERT INTO users (username, email, age) VALUES (?, ?, ?)",
+			fmt.Sprintf("user%d", i), fmt.Sprintf("user%d@example.com", i), 20); err != nil {
+			t.Fatalf("插入测试数据失败: %v", err)
+		}
+	}
+
+	// 超过参数上限时自动分批
+	rows := make([]map[string]interface{}, 0, 1200)
+	for i := 1; i <= 1200; i++ {
+		row := map[string]interface{}{"id": i, "age": 30 + i%10}
+		if i%2 == 0 {
+			row["email"] = fmt.Sprintf("new%d@example.com", i)
+		}
+		rows = append(rows, row)
+	}
+	affected, err := db.BatchUpdate("users", "id", rows)
+	if err != nil {
+		t.Fatalf("批量更新失败: %v", err)
+	}
+	if affected != 1200 {
+		t.Errorf("期望更新 1200 行，实际为 %d", affected)
+	}
+
+	var user SQLiteUser
+	if err := db.DB().Table("users").Where("id = ?", 7).Take(&user).Error; err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if user.Age != 37 || user.Email != "user7@example.com" {
+		t.Errorf("未包含的列不应被更新: %+v", user)
+	}
+	var updated SQLiteUser
+	if err := db.DB().Table("users").Where("id = ?", 8).Take(&updated).Error; err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if updated.Age != 38 || updated.Email != "new8@example.com" {
+		t.Errorf("批量更新结果不符合预期: %+v", updated)
+	}
+
+	if _, err := db.BatchUpdate("users", "id", []map[string]interface{}{{"age": 1}}); err == nil {
+		t.Error("缺少键列时应返回错误")
+	}
+
+	ids := make([]interface{}, 0, 1100)
+	for i := 1; i <= 1100; i++ {
+		ids = append(ids, i)
+	}
+	affected, err = db.BatchDeleteIn("users", "id", ids)
+	if err != nil {
+		t.Fatalf("批量删除失败: %v", err)
+	}
+	var count int64
+	db.DB().Table("users").Count(&count)
+	if affected != 1100 || count != 100 {
+		t.Errorf("期望删除 1100 行剩余 100 行，实际删除 %d 剩余 %d", affected, count)
+	}
+}
+
+// 测试超过参数上限的IN条件
+func TestSQLiteWhereInChunk(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+	for i := 1; i <= 30; i++ {
+		if err := db.Exec("INSERT INTO users (username, email, age) VALUES (?, ?, ?)",
+			fmt.Sprintf("user%d", i), fmt.Sprintf("user%d@example.com", i), 20+i); err != nil {
+			t.Fatalf("插入测试数据失败: %v", err)
+		}
+	}
+
+	ids := make([]int64, 0, 3000)
+	for i := int64(1); i <= 3000; i++ {
+		ids = append(ids, i)
+	}
+
+	var users []SQLiteUser
+	if err := query.NewQuery(db.SqlDB()).Table("users").WhereIn("id", ids).Get(&users); err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(users) != 30 {
+		t.Errorf("期望查询到 30 条记录，实际为 %d", len(users))
+	}
+
+	// Find 自动分批查询
+	var synced []SQLiteSyncUser
+	if err := db.Find(&synced, "id IN ?", ids); err != nil {
+		t.Fatalf("分批查询失败: %v", err)
+	}
+	if len(synced) != 30 {
+		t.Errorf("期望查询到 30 条记录，实际为 %d", len(synced))
+	}
+	synced = nil
+	if err := db.Find(&synced, ids); err != nil || len(synced) != 30 {
+		t.Errorf("按主键分批查询失败: %d, err=%v", len(synced), err)
+	}
+}
+
+// 测试动态结果扫描
+func TestSQLiteQueryMaps(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+	if err := db.Exec("INSERT INTO users (username, email, age) VALUES (?, ?, ?), (?, ?, ?)",
+		"alice", "alice@example.com", 30, "bob", "bob@example.com", 25); err != nil {
+		t.Fatalf("插入测试数据失败: %v", err)
+	}
+
+	maps, err := db.QueryMaps("SELECT id, username, NULL AS note, age FROM users ORDER BY id")
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(maps) != 2 || maps[0]["username"] != "alice" || maps[1]["note"] != nil {
+		t.Errorf("查询结果不符合预期: %v", maps)
+	}
+	if age, ok := maps[0]["age"].(int64); !ok || age != 30 {
+		t.Errorf("期望 age 为 int64(30)，实际为 %#v", maps[0]["age"])
+	}
+
+	table, err := db.QueryStrings("SELECT username, NULL AS note, age FROM users WHERE age > ? ORDER BY id", 20)
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	want := [][]string{{"username", "note", "age"}, {"alice", "", "30"}, {"bob", "", "25"}}
+	if fmt.Sprint(table) != fmt.Sprint(want) {
+		t.Errorf("期望 %v，实际为 %v", want, table)
+	}
+}
+
+// 测试复用行缓冲扫描和 RawBytes 扫描
+func TestSQLiteScanInto(t *testing.T) {
+	db := initSQLiteScanDB(t, 3)
+	defer db.Close()
+
+	buf := gosqlx.GetRowBuffer()
+	buf, err := db.QueryInto(buf, "SELECT id, name, price, note FROM quotes ORDER BY id")
+	if err != nil {
+		t.Fatalf("QueryInto() error = %v", err)
+	}
+	if len(buf) != 3 || buf[0][0] != int64(1) || gosqlx.FormatValue(buf[1][1]) != "q2" || buf[2][2] != 2.5 || buf[0][3] != nil {
+		t.Errorf("QueryInto() = %v", buf)
+	}
+
+	// 再次查询复用已有的行切片
+	first := &buf[0][0]
+	buf, err = db.QueryInto(buf, "SELECT id, name, price, note FROM quotes WHERE id > ? ORDER BY id", 1)
+	if err != nil || len(buf) != 2 || buf[0][0] != int64(2) || &buf[0][0] != first {
+		t.Errorf("复用行缓冲: %v %v", buf, err)
+	}
+	gosqlx.PutRowBuffer(buf)
+
+	var total float64
+	var names []string
+	err = db.QueryRawBytes(func(values []sql.RawBytes) error {
+		price, err := strconv.ParseFloat(string(values[1]), 64)
+		total += price
+		names = append(names, string(values[0]))
+		if values[2] != nil {
+			return errors.New("note 应为 NULL")
+		}
+		return err
+	}, "SELECT name, price, note FROM quotes ORDER BY id")
+	if err != nil || total != 4.5 || strings.Join(names, ",") != "q1,q2,q3" {
+		t.Errorf("QueryRawBytes: %v %v %v", total, names, err)
+	}
+	stop := errors.New("stop")
+	if err := db.QueryRawBytes(func([]sql.RawBytes) error { return stop }, "SELECT id FROM quotes"); !errors.Is(err, stop) {
+		t.Errorf("回调错误 = %v", err)
+	}
+}
+
+// initSQLiteScanDB 创建包含 n 行报价的内存数据库
+func initSQLiteScanDB(t testing.TB, n int) *gosqlx.Database {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_scan", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE quotes (id INTEGER PRIMARY KEY, name TEXT, price REAL, note TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	rows := make([][]interface{}, n)
+	for i := range rows {
+		rows[i] = []interface{}{i + 1, fmt.Sprintf("q%d", i+1), float64(i) + 0.5, nil}
+	}
+	if err := db.BatchInsert("quotes", []string{"id", "name", "price", "note"}, rows); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+	return db
+}
+
+// 基准测试：逐行扫描为 map、复用行缓冲和 RawBytes 读取 1000 行
+func BenchmarkSQLiteScan(b *testing.B) {
+	db := initSQLiteScanDB(b, 1000)
+	defer db.Close()
+	const query = "SELECT id, name, price, note FROM quotes"
+
+	b.Run("QueryMaps", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := db.QueryMaps(query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("QueryInto", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf, err := db.QueryInto(gosqlx.GetRowBuffer(), query)
+			if err != nil {
+				b.Fatal(err)
+			}
+			gosqlx.PutRowBuffer(buf)
+		}
+	})
+	b.Run("QueryRawBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var n int
+			if err := db.QueryRawBytes(func(values []sql.RawBytes) error { n += len(values[1]); return nil }, query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// 测试导入 CSV 和 JSON Lines
+func TestSQLiteImport(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	csvData := "user_name,email,age,active\n" +
+		"alice,alice@example.com,30,true\n" +
+		"bob,bob@example.com,abc,false\n" +
+		"carol,carol@example.com,,1\n" +
+		"dave,invalid,40,0\n"
+	result, err := export.Import(db, "users", strings.NewReader(csvData), export.CSV, export.ImportOptions{
+		BatchSize: 2,
+		Columns:   map[string]string{"user_name": "username"},
+		Validate: func(line int, row map[string]interface{}) error {
+			if !strings.Contains(row["email"].(string), "@") {
+				return fmt.Errorf("邮箱格式错误")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("导入 CSV 失败: %v", err)
+	}
+	if result.Inserted != 2 || len(result.Errors) != 2 {
+		t.Fatalf("期望插入 2 行、失败 2 行，实际插入 %d 行，错误 %v", result.Inserted, result.Errors)
+	}
+	if result.Errors[0].Line != 3 || result.Errors[1].Line != 5 {
+		t.Errorf("错误行号不符合预期: %v", result.Errors)
+	}
+
+	// 插入失败（违反非空约束）的行逐行定位
+	jsonData := `{"username": "erin", "email": "erin@example.com", "age": 22}
+{"username": "frank", "age": 33}
+
+{"username": "grace", "email": "grace@example.com", "age": 28.0, "active": false}
+not json`
+	result, err = export.Import(db, "users", strings.NewReader(jsonData), export.JSONLines, export.ImportOptions{})
+	if err != nil {
+		t.Fatalf("导入 JSON Lines 失败: %v", err)
+	}
+	if result.Inserted != 2 || len(result.Errors) != 2 {
+		t.Fatalf("期望插入 2 行、失败 2 行，实际插入 %d 行，错误 %v", result.Inserted, result.Errors)
+	}
+	if result.Errors[0].Line != 2 || result.Errors[1].Line != 5 {
+		t.Errorf("错误行号不符合预期: %v", result.Errors)
+	}
+
+	var count int64
+	db.DB().Table("users").Count(&count)
+	if count != 4 {
+		t.Errorf("期望表中有 4 行，实际为 %d", count)
+	}
+
+	if _, err := export.Import(db, "users", strings.NewReader("username\nx\ny\n"), export.CSV, export.ImportOptions{MaxErrors: 1}); err != export.ErrTooManyErrors {
+		t.Errorf("期望返回 ErrTooManyErrors，实际为 %v", err)
+	}
+}
+
+// 测试递归公用表表达式
+func TestSQLiteWithRecursive(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	if err := db.Exec("CREATE TABLE menus (id INTEGER PRIMARY KEY, parent_id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO menus VALUES (1, NULL, 'root'), (2, 1, 'a'), (3, 2, 'b'), (4, NULL, 'other')"); err != nil {
+		t.Fatalf("插入测试数据失败: %v", err)
+	}
+
+	anchor := query.NewQuery(db.SqlDB()).Table("menus").Select("id", "name").Where("id = ?", 1)
+	recursive := query.NewQuery(db.SqlDB()).Table("menus m").Select("m.id", "m.name").InnerJoin("tree t", "m.parent_id = t.id")
+	count, err := query.NewQuery(db.SqlDB()).WithRecursive("tree", anchor, recursive, "id", "name").Table("tree").CountNum()
+	if err != nil {
+		t.Fatalf("递归查询失败: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("期望子树包含 3 个节点，实际为 %d", count)
+	}
+}
+
+// 带校验标签的用户模型
+type SQLiteValidatedUser struct {
+	ID       int64  `gorm:"primaryKey"`
+	Username string `gorm:"size:64" validate:"required,max=10"`
+	Email    string `gorm:"size:128" validate:"required,email"`
+	Age      int    `validate:"omitempty,min=18,max=120"`
+	Active   bool
+}
+
+func (SQLiteValidatedUser) TableName() string {
+	return "users"
+}
+
+// 测试写入前校验
+func TestSQLiteValidation(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+	db.SetValidator(gosqlx.NewTagValidator())
+
+	err := db.Create(&SQLiteValidatedUser{Username: "a_very_long_name", Email: "invalid", Age: 10})
+	var fieldErrs gosqlx.ValidationErrors
+	if !errors.Is(err, gosqlx.ErrValidation) || !errors.As(err, &fieldErrs) {
+		t.Fatalf("期望返回校验错误，实际为 %v", err)
+	}
+	if len(fieldErrs) != 3 || fieldErrs[0].Field != "Username" || fieldErrs[1].Tag != "email" || fieldErrs[2].Param != "18" {
+		t.Errorf("校验错误不符合预期: %v", fieldErrs)
+	}
+
+	user := &SQLiteValidatedUser{Username: "alice", Email: "alice@example.com"}
+	if err := db.Create(user); err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	// Updates 只校验待更新的字段
+	if err := db.Updates(user, SQLiteValidatedUser{Age: 30}); err != nil {
+		t.Errorf("更新合法字段失败: %v", err)
+	}
+	if err := db.Updates(user, map[string]interface{}{"email": "bad"}); !errors.Is(err, gosqlx.ErrValidation) {
+		t.Errorf("期望 map 更新返回校验错误，实际为 %v", err)
+	}
+
+	// 批量创建时错误字段带有下标
+	err = db.CreateInBatches([]SQLiteValidatedUser{{Username: "bob", Email: "bob@example.com"}, {Username: "", Email: "c@example.com"}}, 10)
+	if !errors.As(err, &fieldErrs) || fieldErrs[0].Field != "[1].Username" {
+		t.Errorf("期望批量校验错误，实际为 %v", err)
+	}
+
+	// 自定义校验器
+	db.SetValidator(gosqlx.ValidatorFunc(func(value interface{}) error {
+		return errors.New("自定义校验失败")
+	}))
+	if err := db.Save(user); err == nil || err.Error() != "自定义校验失败" {
+		t.Errorf("期望自定义校验错误，实际为 %v", err)
+	}
+	db.SetValidator(nil)
+	if err := db.Save(user); err != nil {
+		t.Errorf("关闭校验后保存失败: %v", err)
+	}
+}
+
+// 订单状态枚举
+type SQLiteOrderStatus string
+
+const (
+	SQLiteOrderPending SQLiteOrderStatus = "pending"
+	SQLiteOrderDone    SQLiteOrderStatus = "done"
+)
+
+// IsValid 实现 gosqlx.Enum
+func (s SQLiteOrderStatus) IsValid() bool {
+	return s == SQLiteOrderPending || s == SQLiteOrderDone
+}
+
+// 带枚举字段的订单模型
+type SQLiteEnumOrder struct {
+	ID     int64 `gorm:"primaryKey"`
+	Status SQLiteOrderStatus
+	Prev   *SQLiteOrderStatus
+}
+
+func (SQLiteEnumOrder) TableName() string {
+	return "enum_orders"
+}
+
+// 测试写入前校验枚举取值
+func TestSQLiteEnumValidation(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	if err := db.Exec("CREATE TABLE enum_orders (id INTEGER PRIMARY KEY, status TEXT CHECK (status IN ('pending', 'done')), prev TEXT)"); err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	db.SetValidator(gosqlx.NewTagValidator())
+
+	if err := db.Create(&SQLiteEnumOrder{Status: SQLiteOrderPending}); err != nil {
+		t.Fatalf("写入合法取值失败: %v", err)
+	}
+	bad := SQLiteOrderStatus("lost")
+	err := db.Create(&SQLiteEnumOrder{Status: "shipped", Prev: &bad})
+	var fieldErrs gosqlx.ValidationErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) != 2 || fieldErrs[0].Tag != "enum" || fieldErrs[1].Field != "Prev" {
+		t.Fatalf("期望枚举校验错误，实际为 %v", err)
+	}
+	if fieldErrs[0].Error() != "Status 不是有效的取值: shipped" {
+		t.Errorf("错误信息不符合预期: %s", fieldErrs[0].Error())
+	}
+}
+
+// 测试试运行模式只生成SQL不执行
+func TestSQLiteDryRun(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	stmts, err := db.ToSQL(func(tx *gosqlx.Database) error {
+		if err := tx.Create(&SQLiteValidatedUser{Username: "dry", Email: "dry@example.com", Age: 20}); err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE users SET age = ? WHERE username = ?", 30, "dry"); err != nil {
+			return err
+		}
+		return tx.Transaction(func(tx *gosqlx.Database) error {
+			_, err := tx.BatchDeleteIn("users", "id", []interface{}{1, 2, 3})
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("试运行失败: %v", err)
+	}
+	if len(stmts) != 3 {
+		t.Fatalf("期望生成3条语句，实际为 %d: %v", len(stmts), stmts)
+	}
+	if !strings.HasPrefix(stmts[0].SQL, "INSERT INTO `users`") || len(stmts[0].Args) == 0 {
+		t.Errorf("INSERT 语句不符合预期: %s %v", stmts[0].SQL, stmts[0].Args)
+	}
+	if stmts[1].Preview != `UPDATE users SET age = 30 WHERE username = "dry"` {
+		t.Errorf("预览SQL不符合预期: %s", stmts[1].Preview)
+	}
+	if !strings.Contains(stmts[2].SQL, "DELETE FROM") || len(stmts[2].Args) != 3 {
+		t.Errorf("DELETE 语句不符合预期: %s %v", stmts[2].SQL, stmts[2].Args)
+	}
+
+	// 数据库未被修改
+	count, err := db.Count(&SQLiteValidatedUser{})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("试运行不应写入数据，实际有 %d 条记录", count)
+	}
+	if db.IsDryRun() || db.Statements() != nil {
+		t.Error("原实例不应处于试运行模式")
+	}
+}
+
+// 测试SQL模板查询和执行
+func TestSQLiteSQLTemplate(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	engine := sqltpl.NewEngine()
+	if err := engine.Parse("user.search", `SELECT username FROM users
+WHERE
+{{- if .MinAge}} AND age >= {{.MinAge}}{{end}}
+{{- if .Names}} AND username IN {{.Names}}{{end}}
+ORDER BY {{ident .OrderBy}}`); err != nil {
+		t.Fatalf("解析模板失败: %v", err)
+	}
+	if err := engine.Parse("user.deactivate", `UPDATE users SET active = 0 WHERE age < {{.Age}}`); err != nil {
+		t.Fatalf("解析模板失败: %v", err)
+	}
+
+	for i, name := range []string{"amy", "ben", "cat"} {
+		if err := db.Exec("INSERT INTO users (username, email, age, active) VALUES (?, ?, ?, 1)", name, name+"@example.com", 20+i*10); err != nil {
+			t.Fatalf("插入数据失败: %v", err)
+		}
+	}
+
+	var names []string
+	err := engine.Query(db, &names, "user.search", map[string]interface{}{"MinAge": 25, "Names": []string{"amy", "ben", "cat"}, "OrderBy": "username"})
+	if err != nil {
+		t.Fatalf("模板查询失败: %v", err)
+	}
+	if strings.Join(names, ",") != "ben,cat" {
+		t.Errorf("查询结果不符合预期: %v", names)
+	}
+
+	affected, err := engine.Exec(db, "user.deactivate", map[string]interface{}{"Age": 35})
+	if err != nil || affected != 2 {
+		t.Errorf("期望更新2行，实际为 %d: %v", affected, err)
+	}
+}
+
+// 测试命名语句注册、版本和执行统计
+func TestSQLiteNamedQuery(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	err := gosqlx.LoadQueries(fstest.MapFS{
+		"queries/user.sql": {Data: []byte(`-- name: test.user.byName
+SELECT username, email FROM users WHERE username = ?
+
+-- name: test.user.insert
+-- version: 2
+INSERT INTO users (username, email, age, active) VALUES (?, ?, ?, 1)
+`)},
+	}, "queries/*.sql")
+	if err != nil {
+		t.Fatalf("加载命名语句失败: %v", err)
+	}
+	v3 := gosqlx.RegisterQuery("test.user.insert", "INSERT INTO users (username, email, age, active) VALUES (?, ?, ?, 0)")
+	if v3.Version != 3 {
+		t.Errorf("期望新版本号为3，实际为 %d", v3.Version)
+	}
+
+	gosqlx.ResetQueryStats()
+	if _, err := db.Named("test.user.insert@2", "amy", "amy@example.com", 20).Exec(); err != nil {
+		t.Fatalf("执行命名语句失败: %v", err)
+	}
+	if _, err := db.Named("test.user.insert", "ben", "ben@example.com", 30).Exec(); err != nil {
+		t.Fatalf("执行命名语句失败: %v", err)
+	}
+
+	var user SQLiteValidatedUser
+	if err := db.Named("test.user.byName", "amy").Scan(&user); err != nil {
+		t.Fatalf("命名查询失败: %v", err)
+	}
+	if user.Email != "amy@example.com" {
+		t.Errorf("查询结果不符合预期: %+v", user)
+	}
+	rows, err := db.Named("test.user.byName", "ben").Maps()
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("命名查询失败: %v %v", rows, err)
+	}
+
+	if err := db.Named("test.user.missing").Scan(&user); !errors.Is(err, gosqlx.ErrQueryNotRegistered) {
+		t.Errorf("期望返回未注册错误，实际为 %v", err)
+	}
+
+	stats := gosqlx.QueryStats()
+	if len(stats) != 2 || stats[0].Name != "test.user.byName" || stats[0].Calls != 2 || stats[1].Calls != 2 {
+		t.Errorf("执行统计不符合预期: %+v", stats)
+	}
+}
+
+// 测试连接占用跟踪和泄漏检测
+func TestSQLiteLeakDetection(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	var (
+		mutex sync.Mutex
+		logs  []string
+	)
+	stop := db.DetectLeaks(gosqlx.LeakOptions{
+		Threshold: 20 * time.Millisecond,
+		Interval:  10 * time.Millisecond,
+		Logger: func(format string, args ...interface{}) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			logs = append(logs, fmt.Sprintf(format, args...))
+		},
+	})
+	defer stop()
+
+	tx := db.Begin()
+	stats := db.Stats()
+	if len(stats.Held) != 1 || stats.Held[0].Kind != gosqlx.HeldTransaction || !strings.Contains(stats.Held[0].Caller, "sqlite_test.go") {
+		t.Fatalf("期望跟踪到1个事务占用，实际为 %+v", stats.Held)
+	}
+	if stats.Held[0].Stack == "" {
+		t.Error("开启泄漏检测后应记录调用栈")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if held := db.Stats().LongHeld(20 * time.Millisecond); len(held) != 1 {
+		t.Errorf("期望1个长时间占用的连接，实际为 %d", len(held))
+	}
+	mutex.Lock()
+	if len(logs) != 1 || !strings.Contains(logs[0], "疑似连接泄漏") {
+		t.Errorf("期望报告1次泄漏，实际为 %v", logs)
+	}
+	mutex.Unlock()
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	if held := db.Stats().Held; len(held) != 0 {
+		t.Errorf("回滚后不应有占用的连接，实际为 %+v", held)
+	}
+
+	err := db.Transaction(func(tx *gosqlx.Database) error {
+		if len(db.Stats().Held) != 1 {
+			t.Error("事务执行期间应跟踪连接占用")
+		}
+		return nil
+	})
+	if err != nil || len(db.Stats().Held) != 0 {
+		t.Errorf("事务结束后不应有占用的连接: %v", err)
+	}
+}
+
+// 测试优雅关闭等待进行中的事务
+func TestSQLiteShutdown(t *testing.T) {
+	newManager := func() *gosqlx.DatabaseManager {
+		return gosqlx.NewDatabaseManager(gosqlx.NewConfigManager(gosqlx.NewConfigProvider(gosqlx.ConfigMap{
+			"development": {"shutdown": {Type: gosqlx.SQLite, Source: "file::memory:?cache=shared", MaxIdle: 2, MaxOpen: 2}},
+		})))
+	}
+	ctx := gosqlx.NewContext(context.Background(), "shutdown", gosqlx.ModeReadWrite)
+
+	// 进行中的事务在超时前结束，正常关闭
+	manager := newManager()
+	db, err := manager.GetDatabase(ctx)
+	if err != nil {
+		t.Fatalf("获取数据库失败: %v", err)
+	}
+	tx := db.Begin()
+	time.AfterFunc(50*time.Millisecond, func() { tx.Rollback() })
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	report, err := manager.Shutdown(shutdownCtx)
+	if err != nil || len(report.Closed) != 1 || len(report.ForceClosed) != 0 {
+		t.Fatalf("期望正常关闭，实际为 %+v: %v", report, err)
+	}
+	if _, err := manager.GetDatabase(ctx); !errors.Is(err, gosqlx.ErrShuttingDown) {
+		t.Errorf("关闭后期望返回 ErrShuttingDown，实际为 %v", err)
+	}
+
+	// 超时后强制关闭并报告占用的连接
+	manager = newManager()
+	db, err = manager.GetDatabase(ctx)
+	if err != nil {
+		t.Fatalf("获取数据库失败: %v", err)
+	}
+	tx = db.Begin()
+	defer tx.Rollback()
+
+	shutdownCtx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report, err = manager.Shutdown(shutdownCtx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期望返回超时错误，实际为 %v", err)
+	}
+	if len(report.ForceClosed) != 1 || report.ForceClosed[0].InUse != 1 || len(report.ForceClosed[0].Held) != 1 {
+		t.Errorf("强制关闭报告不符合预期: %+v", report)
+	}
+}
+
+// 测试语句超时
+func TestSQLiteQueryTimeout(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	const slowSQL = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 100000000) SELECT COUNT(*) FROM c"
+
+	var count int64
+	start := time.Now()
+	err := db.WithTimeout(50*time.Millisecond).ScanRaw(&count, slowSQL)
+	if !errors.Is(err, gosqlx.ErrQueryTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望返回 ErrQueryTimeout，实际为 %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("超时未及时取消语句，耗时 %s", elapsed)
+	}
+
+	// 原生执行和查询构建器同样受超时约束
+	if _, err := db.WithTimeout(50 * time.Millisecond).ExecWithResult(slowSQL); !errors.Is(err, gosqlx.ErrQueryTimeout) {
+		t.Errorf("期望 ExecWithResult 返回 ErrQueryTimeout，实际为 %v", err)
+	}
+	q := db.WithTimeout(50 * time.Millisecond).NewQuery().Table("(" + slowSQL + ") AS t")
+	var values []int64
+	if err := q.Get(&values); !errors.Is(err, gosqlx.ErrQueryTimeout) {
+		t.Errorf("期望查询构建器返回 ErrQueryTimeout，实际为 %v", err)
+	}
+
+	// 调用方取消不视为超时
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = db.WithContext(ctx).ScanRaw(&count, "SELECT 1")
+	if !errors.Is(err, context.Canceled) || errors.Is(err, gosqlx.ErrQueryTimeout) {
+		t.Errorf("期望返回取消错误，实际为 %v", err)
+	}
+
+	// 未超时的语句正常执行
+	if err := db.WithTimeout(time.Second).ScanRaw(&count, "SELECT COUNT(*) FROM users"); err != nil {
+		t.Errorf("查询失败: %v", err)
+	}
+}
+
+// 上下文测试模型
+type SQLiteNote struct {
+	ID   int64
+	Body string
+}
+
+func (SQLiteNote) TableName() string {
+	return "notes"
+}
+
+// 测试 Database 不绑定创建时上下文的取消信号，并支持未设置 Context 字段的 Context
+func TestSQLiteDatabaseContext(t *testing.T) {
+	config := &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1}
+
+	// 创建时的上下文取消后，缓存复用的实例仍可执行语句
+	ctx, cancel := gosqlx.NewContext(context.Background(), "sqlite_ctx", gosqlx.ModeReadWrite).WithCancel()
+	db, err := gosqlx.NewDatabase(ctx, config)
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	cancel()
+	if err := db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := db.Create(&SQLiteNote{Body: "a"}); err != nil {
+		t.Errorf("上下文取消后 Create 失败: %v", err)
+	}
+	var notes []SQLiteNote
+	if err := db.Find(&notes); err != nil || len(notes) != 1 {
+		t.Errorf("上下文取消后 Find = %d 条, %v", len(notes), err)
+	}
+
+	// 直接构造的 Context 没有嵌入的标准上下文
+	literal, err := gosqlx.NewDatabase(&gosqlx.Context{Nick: "sqlite_literal", Mode: gosqlx.ModeReadWrite}, config)
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer literal.Close()
+	if err := literal.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := literal.Create(&SQLiteNote{Body: "a"}); err != nil {
+		t.Errorf("Create 失败: %v", err)
+	}
+	err = literal.Transaction(func(tx *gosqlx.Database) error {
+		return tx.Create(&SQLiteNote{Body: "b"})
+	})
+	if err != nil {
+		t.Errorf("Transaction 失败: %v", err)
+	}
+	if count, err := literal.Count(&SQLiteNote{}); err != nil || count != 2 {
+		t.Errorf("记录数 = %d, %v，期望 2", count, err)
+	}
+}
+
+// 预加载测试模型
+type SQLitePreloadUser struct {
+	ID       int64
+	Username string
+	Email    string
+	Articles []SQLitePreloadArticle `gorm:"foreignKey:UserID"`
+}
+
+func (SQLitePreloadUser) TableName() string {
+	return "users"
+}
+
+type SQLitePreloadArticle struct {
+	ID     int64
+	UserID int64
+	Title  string
+	User   *SQLitePreloadUser `gorm:"foreignKey:UserID"`
+}
+
+func (SQLitePreloadArticle) TableName() string {
+	return "articles"
+}
+
+// 测试关联预加载
+func TestSQLitePreload(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	for i := 1; i <= 3; i++ {
+		if err := db.Exec("INSERT INTO users (username, email) VALUES (?, ?)", fmt.Sprintf("user%d", i), fmt.Sprintf("user%d@example.com", i)); err != nil {
+			t.Fatalf("插入用户失败: %v", err)
+		}
+		for j := 1; j <= i; j++ {
+			if err := db.Exec("INSERT INTO articles (user_id, title) VALUES (?, ?)", i, fmt.Sprintf("go %d-%d", i, j)); err != nil {
+				t.Fatalf("插入文章失败: %v", err)
+			}
+		}
+	}
+
+	// 统计实际执行的查询数
+	var queries int
+	if err := db.DB().Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { queries++ }); err != nil {
+		t.Fatalf("注册回调失败: %v", err)
+	}
+
+	var users []SQLitePreloadUser
+	if err := db.Find(&users, gosqlx.Preload("Articles"), "id > ?", 0); err != nil {
+		t.Fatalf("预加载查询失败: %v", err)
+	}
+	if len(users) != 3 || len(users[0].Articles) != 1 || len(users[2].Articles) != 3 {
+		t.Fatalf("预加载结果不符合预期: %+v", users)
+	}
+	if queries != 2 {
+		t.Errorf("期望执行2条查询（主查询+IN查询），实际为 %d", queries)
+	}
+
+	// 关联条件
+	var user SQLitePreloadUser
+	if err := db.First(&user, gosqlx.Preload("Articles", "title = ?", "go 3-2"), "username = ?", "user3"); err != nil {
+		t.Fatalf("带条件预加载失败: %v", err)
+	}
+	if len(user.Articles) != 1 || user.Articles[0].Title != "go 3-2" {
+		t.Errorf("关联条件未生效: %+v", user.Articles)
+	}
+
+	// JOIN 加载 belongs_to 关联
+	var articles []SQLitePreloadArticle
+	if err := db.Find(&articles, gosqlx.JoinPreload("User"), "articles.user_id = ?", 2); err != nil {
+		t.Fatalf("JOIN预加载失败: %v", err)
+	}
+	if len(articles) != 2 || articles[0].User == nil || articles[0].User.Username != "user2" {
+		t.Errorf("JOIN预加载结果不符合预期: %+v", articles)
+	}
+}
+
+// 文档存储测试模型
+type SQLiteProfile struct {
+	Email   string            `json:"email"`
+	Name    string            `json:"name"`
+	Age     int               `json:"age"`
+	Tags    []string          `json:"tags"`
+	Address map[string]string `json:"address"`
+}
+
+// 测试JSON文档存储
+func TestSQLiteDocStore(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	profiles, err := docstore.New[SQLiteProfile](db, "profiles", docstore.Options{
+		Indexes: []docstore.Index{{Field: "email", Unique: true}, {Field: "address.city"}},
+	})
+	if err != nil {
+		t.Fatalf("创建文档集合失败: %v", err)
+	}
+
+	alice := SQLiteProfile{Email: "alice@example.com", Name: "Alice", Age: 30, Tags: []string{"admin"}, Address: map[string]string{"city": "Berlin"}}
+	bob := SQLiteProfile{Email: "bob@example.com", Name: "Bob", Age: 25, Address: map[string]string{"city": "Paris"}}
+	if err := profiles.Insert("u1", alice); err != nil {
+		t.Fatalf("插入文档失败: %v", err)
+	}
+	if err := profiles.Save("u2", bob); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	if err := profiles.Insert("u1", alice); !errors.Is(gosqlxerrors.Classify(err), gosqlxerrors.ErrDuplicateKey) {
+		t.Errorf("期望返回唯一约束错误，实际为 %v", err)
+	}
+
+	// 替换文档内容，索引列同步更新
+	bob.Address["city"] = "Berlin"
+	if err := profiles.Save("u2", bob); err != nil {
+		t.Fatalf("更新文档失败: %v", err)
+	}
+	doc, err := profiles.Get("u2")
+	if err != nil || doc.Value.Address["city"] != "Berlin" || doc.Value.Email != "bob@example.com" {
+		t.Fatalf("读取文档失败: %+v, %v", doc, err)
+	}
+
+	// 索引字段和非索引字段查询
+	docs, err := profiles.Find(docstore.Filter{"address.city": "Berlin"})
+	if err != nil || len(docs) != 2 {
+		t.Fatalf("按索引字段查询失败: %d, %v", len(docs), err)
+	}
+	docs, err = profiles.Find(docstore.Filter{"address.city": "Berlin", "age": 30})
+	if err != nil || len(docs) != 1 || docs[0].ID != "u1" {
+		t.Fatalf("按非索引字段查询失败: %+v, %v", docs, err)
+	}
+	if _, err := profiles.Find(docstore.Filter{"age'; --": 1}); !errors.Is(err, docstore.ErrInvalidField) {
+		t.Errorf("期望拒绝非法字段，实际为 %v", err)
+	}
+
+	// 索引列可通过普通方式访问
+	var city string
+	if err := db.ScanRaw(&city, "SELECT address_city FROM profiles WHERE email = ?", "alice@example.com"); err != nil || city != "Berlin" {
+		t.Errorf("索引列内容不符合预期: %q, %v", city, err)
+	}
+
+	// 事务中与其他表一起写入
+	err = db.Transaction(func(tx *gosqlx.Database) error {
+		if err := profiles.WithTx(tx).Save("u3", SQLiteProfile{Email: "carol@example.com"}); err != nil {
+			return err
+		}
+		return errors.New("回滚")
+	})
+	if err == nil {
+		t.Fatal("期望事务返回错误")
+	}
+	if _, err := profiles.Get("u3"); !errors.Is(err, docstore.ErrNotFound) {
+		t.Errorf("事务回滚后文档不应存在: %v", err)
+	}
+
+	// 新增索引字段后回填
+	profiles, err = docstore.New[SQLiteProfile](db, "profiles", docstore.Options{
+		Indexes: []docstore.Index{{Field: "email", Unique: true}, {Field: "address.city"}, {Field: "age", Type: "INTEGER"}},
+	})
+	if err != nil {
+		t.Fatalf("添加索引字段失败: %v", err)
+	}
+	if err := profiles.Reindex(); err != nil {
+		t.Fatalf("回填索引列失败: %v", err)
+	}
+	if count, err := profiles.Count(docstore.Filter{"age": 25}); err != nil || count != 1 {
+		t.Errorf("按回填的索引列查询失败: %d, %v", count, err)
+	}
+
+	page, total, err := profiles.FindPage(nil, 2, 1)
+	if err != nil || total != 2 || len(page) != 1 || page[0].ID != "u2" {
+		t.Errorf("分页查询不符合预期: %+v, %d, %v", page, total, err)
+	}
+	if err := profiles.Delete("u1"); err != nil {
+		t.Errorf("删除文档失败: %v", err)
+	}
+	if err := profiles.Delete("u1"); !errors.Is(err, docstore.ErrNotFound) {
+		t.Errorf("期望返回 ErrNotFound，实际为 %v", err)
+	}
+}
+
+// 测试 INSERT ... SELECT 和 UPDATE ... JOIN
+func TestSQLiteInsertSelectUpdateJoin(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	if err := db.Exec("INSERT INTO users (username, email, age, active) VALUES (?, ?, ?, ?), (?, ?, ?, ?)",
+		"alice", "alice@example.com", 30, 1, "bob", "bob@example.com", 25, 0); err != nil {
+		t.Fatalf("插入用户失败: %v", err)
+	}
+	if err := db.Exec("DROP TABLE IF EXISTS users_archive"); err != nil {
+		t.Fatalf("删除users_archive表失败: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE users_archive (id INTEGER PRIMARY KEY, username TEXT, note TEXT)"); err != nil {
+		t.Fatalf("创建users_archive表失败: %v", err)
+	}
+
+	sub := query.NewQuery(db.SqlDB()).Table("users").Select("id", "username").Where("active = ?", 0)
+	n, err := query.NewQuery(db.SqlDB()).InsertFromSelect("users_archive", []string{"id", "username"}, sub)
+	if err != nil || n != 1 {
+		t.Fatalf("INSERT ... SELECT 失败: %d, %v", n, err)
+	}
+
+	n, err = query.NewQuery(db.SqlDB()).Table("users_archive").Alias("a").Where("a.note IS NULL").
+		UpdateWithJoin("users u", "a.id = u.id", map[string]interface{}{"a.note": query.Raw("u.email || ?", "#archived")})
+	if err != nil || n != 1 {
+		t.Fatalf("UPDATE ... JOIN 失败: %d, %v", n, err)
+	}
+
+	var note string
+	if err := db.ScanRaw(&note, "SELECT note FROM users_archive WHERE username = ?", "bob"); err != nil || note != "bob@example.com#archived" {
+		t.Errorf("回填结果不符合预期: %q, %v", note, err)
+	}
+}
+
+type sqliteHookKey struct{}
+
+type SQLiteHookUser struct {
+	ID       int64 `gorm:"primaryKey"`
+	Username string
+	Email    string
+	Age      int
+	Calls    []string `gorm:"-"`
+}
+
+func (SQLiteHookUser) TableName() string {
+	return "users"
+}
+
+func (u *SQLiteHookUser) BeforeCreate(ctx context.Context) error {
+	if u.Username == "" {
+		return errors.New("用户名不能为空")
+	}
+	u.Email = fmt.Sprintf("%s@%v", u.Username, ctx.Value(sqliteHookKey{}))
+	u.Calls = append(u.Calls, "BeforeCreate")
+	return nil
+}
+
+func (u *SQLiteHookUser) AfterCreate(ctx context.Context) error {
+	u.Calls = append(u.Calls, "AfterCreate")
+	return nil
+}
+
+func (u *SQLiteHookUser) BeforeUpdate(ctx context.Context) error {
+	u.Calls = append(u.Calls, "BeforeUpdate")
+	return nil
+}
+
+func (u *SQLiteHookUser) AfterFind(ctx context.Context) error {
+	u.Username = strings.ToUpper(u.Username)
+	return nil
+}
+
+// 测试生命周期钩子
+func TestSQLiteHooks(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	prepareSQLiteTestTables(t, db)
+
+	tx := db.WithContext(context.WithValue(context.Background(), sqliteHookKey{}, "example.com"))
+	user := &SQLiteHookUser{Username: "alice"}
+	if err := tx.Create(user); err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	if user.Email != "alice@example.com" || strings.Join(user.Calls, ",") != "BeforeCreate,AfterCreate" {
+		t.Errorf("创建钩子不符合预期: %q %v", user.Email, user.Calls)
+	}
+	if err := db.Create(&SQLiteHookUser{}); err == nil || err.Error() != "用户名不能为空" {
+		t.Errorf("期望 BeforeCreate 拒绝写入，实际为 %v", err)
+	}
+
+	// Save 按主键区分创建和更新
+	user.Calls = nil
+	user.Age = 20
+	if err := db.Save(user); err != nil || strings.Join(user.Calls, ",") != "BeforeUpdate" {
+		t.Errorf("Save 钩子不符合预期: %v %v", user.Calls, err)
+	}
+	users := []SQLiteHookUser{{Username: "bob"}, {Username: "carol"}}
+	if err := tx.CreateInBatches(&users, 10); err != nil || users[1].Email != "carol@example.com" {
+		t.Errorf("批量创建钩子不符合预期: %+v %v", users, err)
+	}
+
+	// 查询钩子同时作用于 GORM 查询和原生SQL
+	var found SQLiteHookUser
+	if err := db.First(&found, "username = ?", "alice"); err != nil || found.Username != "ALICE" {
+		t.Errorf("First 钩子不符合预期: %q %v", found.Username, err)
+	}
+	var list []*SQLiteHookUser
+	if err := db.QueryRows(&list, "SELECT * FROM users ORDER BY id"); err != nil || len(list) != 3 || list[2].Username != "CAROL" {
+		t.Errorf("QueryRows 钩子不符合预期: %v", err)
+	}
+	var byIDs []SQLiteHookUser
+	if _, err := db.FindByIDs(&byIDs, []int64{list[2].ID, list[0].ID}); err != nil || len(byIDs) != 2 || byIDs[0].Username != "CAROL" || byIDs[1].Username != "ALICE" {
+		t.Errorf("FindByIDs 钩子不符合预期: %+v %v", byIDs, err)
+	}
+}
+
+// 测试注册第三方数据库方言
+func TestSQLiteRegisterDialect(t *testing.T) {
+	gosqlx.RegisterDialect("litefork", sqlite.Open)
+
+	ctx := gosqlx.NewContext(context.Background(), "litefork", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: "litefork", Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("使用注册的方言连接失败: %v", err)
+	}
+	defer db.Close()
+
+	// 未注册适配器时批量插入不可用，基础操作正常
+	if db.Adapter() != nil {
+		t.Errorf("未注册适配器时应为 nil，实际为 %T", db.Adapter())
+	}
+	if err := db.Exec("CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatalf("执行SQL失败: %v", err)
+	}
+	if err := db.BatchInsert("t", []string{"id"}, [][]interface{}{{1}}); err == nil {
+		t.Error("未注册适配器时批量插入应返回错误")
+	}
+
+	adapter.Register("litefork", func(o adapter.Options) adapter.Adapter {
+		return adapter.NewSQLite(o.DSN).WithMaxOpen(o.MaxOpen)
+	})
+	db2, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: "litefork", Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db2.Close()
+	if _, ok := db2.Adapter().(*adapter.SQLite); !ok {
+		t.Errorf("期望使用注册的适配器，实际为 %T", db2.Adapter())
+	}
+
+	if _, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: "unknown"}); err == nil {
+		t.Error("未注册的数据库类型应返回错误")
+	}
+}
+
+// 测试连接诊断
+func TestSQLiteDoctor(t *testing.T) {
+	report := gosqlx.Doctor(&gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if !report.OK() || len(report.Checks) != 5 || report.Version == "" {
+		t.Fatalf("内存数据库诊断失败:\n%s", report)
+	}
+	for _, check := range report.Checks {
+		if check.Status != gosqlx.DoctorOK {
+			t.Errorf("检查项 %s = %s: %s", check.Name, check.Status, check.Message)
+		}
+	}
+
+	report = gosqlx.Doctor(&gosqlx.Config{Type: gosqlx.SQLite, Source: "/nonexistent-dir/app.db"})
+	if report.OK() || report.Checks[1].Name != "dsn" || report.Checks[1].Status != gosqlx.DoctorFail {
+		t.Errorf("目录不存在时应失败:\n%s", report)
+	}
+
+	// 地址未写在 tcp() 中
+	report = gosqlx.Doctor(&gosqlx.Config{Type: gosqlx.MySQL, Source: "root:secret@localhost:3306/app"})
+	if report.OK() || report.Checks[1].Hint == "" || report.Checks[2].Status != gosqlx.DoctorSkip {
+		t.Errorf("错误的 MySQL 连接字符串应失败:\n%s", report)
+	}
+
+	report = gosqlx.Doctor(&gosqlx.Config{Type: gosqlx.MySQL, Source: "root:secret@tcp(127.0.0.1:1)/app"})
+	if report.OK() || report.Checks[1].Status != gosqlx.DoctorWarn || report.Checks[2].Status != gosqlx.DoctorFail {
+		t.Errorf("无法连接时应失败:\n%s", report)
+	}
+	if strings.Contains(report.String(), "secret") {
+		t.Errorf("报告中不应包含密码:\n%s", report)
+	}
+
+	report = gosqlx.Doctor(&gosqlx.Config{Type: "db2", Source: "x"})
+	if report.OK() || !strings.Contains(report.Checks[0].Hint, "RegisterDialect") {
+		t.Errorf("不支持的类型应失败:\n%s", report)
+	}
+}
+
+// 测试为语句附加来源注释
+func TestSQLiteComment(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_comment", gosqlx.ModeReadWrite).WithComment("svc", "checkout")
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{
+		Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1,
+		Comment: map[string]string{"app": "orders"},
+	})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+
+	traced := db.WithComment("trace", "abc*/123")
+	if err := traced.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT, email TEXT, age INTEGER, active BOOLEAN)`); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	stmts, err := traced.ToSQL(func(tx *gosqlx.Database) error {
+		if err := tx.Create(&SQLiteValidatedUser{Username: "a", Email: "a@example.com", Age: 20}); err != nil {
+			return err
+		}
+		if err := tx.Updates(&SQLiteValidatedUser{ID: 1}, map[string]interface{}{"age": 30}); err != nil {
+			return err
+		}
+		if err := tx.Delete(&SQLiteValidatedUser{}, 1); err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE users SET age = ?", 1)
+	})
+	if err != nil {
+		t.Fatalf("试运行失败: %v", err)
+	}
+	const comment = "/* app=orders svc=checkout trace=abc_/123 */ "
+	for _, stmt := range stmts {
+		if !strings.HasPrefix(stmt.SQL, comment) {
+			t.Errorf("语句缺少注释: %s", stmt.SQL)
+		}
+	}
+
+	// 带注释的语句可以正常执行
+	if err := traced.Create(&SQLiteValidatedUser{Username: "b", Email: "b@example.com", Age: 20}); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+	var users []SQLiteValidatedUser
+	if err := traced.Find(&users); err != nil || len(users) != 1 {
+		t.Fatalf("查询失败: %v %v", users, err)
+	}
+	if _, err := traced.ExecWithResult("UPDATE users SET age = ?", 21); err != nil {
+		t.Fatalf("执行失败: %v", err)
+	}
+	var count int64
+	if err := traced.NewQuery().Table("users").Count().First(&count); err != nil || count != 1 {
+		t.Fatalf("查询构建器执行失败: %d %v", count, err)
+	}
+}
+
+// 测试日志中隐藏参数值和字面量
+func TestSQLiteLogSanitizer(t *testing.T) {
+	sanitized := []struct {
+		sql  string
+		want string
+	}{
+		{`SELECT * FROM "users" WHERE email = 'a@b.com' AND age > 18 AND id = ?`, `SELECT * FROM "users" WHERE email = ? AND age > ? AND id = ?`},
+		{`UPDATE t1 SET name = 'O''Brien', score = -1.5e3 WHERE col2 = $1`, `UPDATE t1 SET name = ?, score = -? WHERE col2 = $1`},
+		{`/* trace=abc123 */ SELECT [col 1] FROM t WHERE x = :p1 -- 42`, `/* trace=abc123 */ SELECT [col 1] FROM t WHERE x = :p1 -- 42`},
+	}
+	for _, tt := range sanitized {
+		if got := gosqlx.SanitizeSQL(tt.sql, false); got != tt.want {
+			t.Errorf("SanitizeSQL(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+	if a, b := gosqlx.SanitizeSQL("x = 'secret'", true), gosqlx.SanitizeSQL("y = 'secret'", true); a[4:] != b[4:] || strings.Contains(a, "secret") {
+		t.Errorf("哈希占位符 = %q %q", a, b)
+	}
+
+	var buf strings.Builder
+	base := logger.New(log.New(&buf, "", 0), logger.Config{LogLevel: logger.Info})
+	for _, hash := range []bool{false, true} {
+		buf.Reset()
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gosqlx.NewSanitizedLogger(base, hash)})
+		if err != nil {
+			t.Fatalf("连接失败: %v", err)
+		}
+		db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT)")
+		db.Exec("INSERT INTO users (email) VALUES (?)", "alice@example.com")
+		db.Exec("UPDATE users SET email = 'bob@example.com' WHERE id = 1")
+		if out := buf.String(); strings.Contains(out, "example.com") || !strings.Contains(out, "UPDATE users SET email =") {
+			t.Errorf("hash=%v 日志 = %s", hash, out)
+		}
+		if hash && strings.Count(buf.String(), "'#") != 2 {
+			t.Errorf("哈希日志 = %s", buf.String())
+		}
+	}
+
+	// 只开启 Debug、未设置 LogParameterValues 时同样隐藏参数值，设置后原样输出
+	for _, values := range []bool{false, true} {
+		ctx := gosqlx.NewContext(context.Background(), "sqlite_log_debug", gosqlx.ModeReadWrite)
+		db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", Debug: true, LogParameterValues: values})
+		if err != nil {
+			t.Fatalf("连接失败: %v", err)
+		}
+		filter, ok := db.DB().Logger.(gorm.ParamsFilter)
+		if !ok {
+			db.Close()
+			t.Fatalf("Debug 日志 %T 未实现 ParamsFilter", db.DB().Logger)
+		}
+		sqlStr, params := filter.ParamsFilter(context.Background(), "SELECT * FROM users WHERE email = 'a@b.com' AND id = ?", 42)
+		if leaked := strings.Contains(sqlStr, "a@b.com") || len(params) > 0; leaked != values {
+			t.Errorf("LogParameterValues=%v 日志语句 = %q %v", values, sqlStr, params)
+		}
+		db.Close()
+	}
+}
+
+// 测试事务提交和回滚回调以及环境事务
+func TestSQLiteTxHooks(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_tx_hooks", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	// 仓储方法只接收 ctx，在环境事务中自动加入事务
+	insert := func(ctx context.Context, name string) error {
+		return db.WithContext(ctx).Exec("INSERT INTO events (name) VALUES (?)", name)
+	}
+
+	var calls []string
+	errRollback := errors.New("rollback")
+	err = db.Transaction(func(tx *gosqlx.Database) error {
+		tx.OnCommit(func() { calls = append(calls, "commit") })
+		tx.OnRollback(func() { calls = append(calls, "rollback") })
+		if current, ok := gosqlx.FromContext(tx.Context()); !ok || current != tx {
+			t.Error("FromContext 未返回当前事务")
+		}
+		if err := insert(tx.Context(), "a"); err != nil {
+			return err
+		}
+		// 嵌套事务回滚只执行其中注册的回调
+		_ = db.WithContext(tx.Context()).Transaction(func(nested *gosqlx.Database) error {
+			nested.OnCommit(func() { calls = append(calls, "nested commit") })
+			nested.OnRollback(func() { calls = append(calls, "nested rollback") })
+			if err := insert(nested.Context(), "b"); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if len(calls) != 1 || calls[0] != "nested rollback" {
+			t.Errorf("嵌套事务回滚后 calls = %v", calls)
+		}
+		return tx.Transaction(func(nested *gosqlx.Database) error {
+			nested.OnCommit(func() { calls = append(calls, "nested commit") })
+			return insert(nested.Context(), "c")
+		})
+	})
+	if err != nil {
+		t.Fatalf("事务失败: %v", err)
+	}
+	if want := []string{"nested rollback", "commit", "nested commit"}; fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	var names []string
+	if err := db.Raw("SELECT name FROM events ORDER BY id").Scan(&names).Error; err != nil || fmt.Sprint(names) != "[a c]" {
+		t.Errorf("names = %v, %v", names, err)
+	}
+
+	// 回滚时执行回调，事务结束后不再作为环境事务
+	calls = nil
+	tx := db.Begin()
+	tx.OnCommit(func() { calls = append(calls, "commit") })
+	tx.OnRollback(func() { calls = append(calls, "rollback") })
+	if err := insert(tx.Context(), "d"); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	if fmt.Sprint(calls) != "[rollback]" {
+		t.Errorf("calls = %v", calls)
+	}
+	if _, ok := gosqlx.FromContext(tx.Context()); ok {
+		t.Error("事务结束后不应返回环境事务")
+	}
+	if err := insert(tx.Context(), "e"); err != nil {
+		t.Fatalf("事务结束后插入失败: %v", err)
+	}
+
+	// 不在事务中时立即执行
+	ran := false
+	db.OnCommit(func() { ran = true })
+	if !ran {
+		t.Error("不在事务中时 OnCommit 应立即执行")
+	}
+}
+
+// 测试通过上下文传递的环境事务
+func TestSQLiteWithTx(t *testing.T) {
+	manager := gosqlx.NewDatabaseManager(gosqlx.NewConfigManager(gosqlx.NewConfigProvider(gosqlx.ConfigMap{
+		"development": {"ambient": {Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1}},
+	})))
+	defer manager.CloseAll()
+	ctx := gosqlx.NewContext(context.Background(), "ambient", gosqlx.ModeReadWrite)
+	db, err := manager.GetDatabase(ctx)
+	if err != nil {
+		t.Fatalf("获取数据库失败: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE accounts (id INTEGER PRIMARY KEY, balance INTEGER)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	// 业务代码只接收上下文，不关心是否在事务中
+	deposit := func(ctx *gosqlx.Context, amount int) error {
+		db, err := manager.GetDatabase(ctx)
+		if err != nil {
+			return err
+		}
+		return db.Exec("INSERT INTO accounts (balance) VALUES (?)", amount)
+	}
+	count := func() int64 {
+		var n int64
+		db.Raw("SELECT COUNT(*) FROM accounts").Scan(&n)
+		return n
+	}
+
+	tx := db.Begin()
+	txCtx := ctx.WithTx(tx)
+	if got, err := manager.GetDatabase(txCtx); err != nil || !got.InTransaction() {
+		t.Fatalf("GetDatabase 应返回事务实例: %v", err)
+	}
+	if err := deposit(txCtx, 100); err != nil {
+		t.Fatalf("事务中写入失败: %v", err)
+	}
+	if err := deposit(txCtx, 200); err != nil {
+		t.Fatalf("事务中写入失败: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	if n := count(); n != 0 {
+		t.Errorf("回滚后记录数 = %d", n)
+	}
+
+	// 事务结束后回到普通连接
+	if got, _ := manager.GetDatabase(txCtx); got.InTransaction() {
+		t.Error("事务结束后不应返回事务实例")
+	}
+	if err := deposit(txCtx, 300); err != nil || count() != 1 {
+		t.Errorf("事务结束后写入失败: %v", err)
+	}
+
+	// 不在事务中的实例不会放入上下文
+	if _, ok := gosqlx.FromContext(gosqlx.WithTx(context.Background(), db)); ok {
+		t.Error("非事务实例不应作为环境事务")
+	}
+}
+
+// 测试合并插入返回每行的操作和主键
+func TestSQLiteMergeIntoReturning(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_upsert", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE members (id INTEGER PRIMARY KEY AUTOINCREMENT, tenant TEXT, email TEXT, name TEXT, UNIQUE (tenant, email))"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO members (tenant, email, name) VALUES ('t1', 'a@x.com', 'A'), ('t2', 'a@x.com', 'A2')"); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+
+	columns := []string{"tenant", "email", "name"}
+	values := [][]interface{}{
+		{"t1", "b@x.com", "B"},
+		{"t1", "a@x.com", "A new"},
+		{"t2", "c@x.com", "C"},
+	}
+	results, err := db.MergeIntoReturning("members", columns, values, []string{"tenant", "email"}, []string{"name"}, "id")
+	if err != nil {
+		t.Fatalf("合并插入失败: %v", err)
+	}
+	want := []gosqlx.UpsertAction{gosqlx.UpsertInserted, gosqlx.UpsertUpdated, gosqlx.UpsertInserted}
+	for i, result := range results {
+		if result.Row != i || result.Action != want[i] || result.ID == nil {
+			t.Errorf("results[%d] = %+v, want %s", i, result, want[i])
+		}
+	}
+	if fmt.Sprint(results[1].ID) != "1" {
+		t.Errorf("已存在行的主键 = %v", results[1].ID)
+	}
+	var name string
+	if err := db.Raw("SELECT name FROM members WHERE tenant = 't1' AND email = 'a@x.com'").Scan(&name).Error; err != nil || name != "A new" {
+		t.Errorf("name = %q, %v", name, err)
+	}
+
+	// 不指定主键列和更新列，同一批中重复的键只有第一次是插入
+	results, err = db.MergeIntoReturning("members", columns, [][]interface{}{{"t3", "d@x.com", "D"}, {"t3", "d@x.com", "D"}}, []string{"tenant", "email"}, nil, "")
+	if err != nil {
+		t.Fatalf("合并插入失败: %v", err)
+	}
+	if results[0].Action != gosqlx.UpsertInserted || results[1].Action != gosqlx.UpsertUpdated || results[0].ID != nil {
+		t.Errorf("results = %+v", results)
+	}
+
+	if _, err := db.MergeIntoReturning("members", columns, values, []string{"missing"}, nil, "id"); err == nil {
+		t.Error("键列不在插入列中时应返回错误")
+	}
+}
+
+// 变更更新测试模型
+type SQLiteMember struct {
+	ID        int64 `gorm:"primaryKey"`
+	Name      string
+	Email     string
+	Age       int
+	UpdatedAt time.Time
+}
+
+func (SQLiteMember) TableName() string {
+	return "members"
+}
+
+// 测试只更新变化的列
+func TestSQLiteUpdateChanged(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_update_changed", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.DB().AutoMigrate(&SQLiteMember{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	profile := SQLiteMember{Name: "alice", Email: "alice@example.com", Age: 20}
+	if err := db.Create(&profile); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+
+	original := profile
+	// 其他请求同时修改了 age
+	if err := db.Exec("UPDATE members SET age = 30 WHERE id = ?", profile.ID); err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	profile.Name = "alice2"
+	changes, err := db.UpdateChanged(&profile, original)
+	if err != nil {
+		t.Fatalf("UpdateChanged() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Column != "name" || changes[0].Old != "alice" || changes[0].New != "alice2" {
+		t.Errorf("changes = %+v", changes)
+	}
+	var stored SQLiteMember
+	if err := db.First(&stored, profile.ID); err != nil || stored.Name != "alice2" || stored.Age != 30 {
+		t.Errorf("stored = %+v, %v", stored, err)
+	}
+
+	// 没有变化时不执行语句
+	stmts, err := db.ToSQL(func(tx *gosqlx.Database) error {
+		changes, err := tx.UpdateChanged(&profile, profile)
+		if len(changes) != 0 {
+			t.Errorf("无变化时 changes = %+v", changes)
+		}
+		return err
+	})
+	if err != nil || len(stmts) != 0 {
+		t.Errorf("无变化时执行了语句: %v %v", stmts, err)
+	}
+
+	if _, err := db.UpdateChanged(profile, original); err == nil {
+		t.Error("model 不是指针时应返回错误")
+	}
+	if _, err := db.Diff(&profile, SQLiteUser{}); err == nil {
+		t.Error("类型不同时应返回错误")
+	}
+}
+
+// 测试并发计数的分页查询和不计数的分页查询
+func TestSQLiteQueryPageConcurrent(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_page", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: t.TempDir() + "/page.db", MaxIdle: 4, MaxOpen: 4})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	for i := 1; i <= 25; i++ {
+		if err := db.Exec("INSERT INTO items (name) VALUES (?)", fmt.Sprintf("item%d", i)); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+
+	type item struct {
+		ID   int64
+		Name string
+	}
+	var items []item
+	total, err := db.QueryPage(db.DB(), &items, 2, 10, "items", []interface{}{"id"}, "id > ?", 0)
+	if err != nil || total != 25 || len(items) != 10 || items[0].ID != 11 {
+		t.Fatalf("分页结果 total=%d items=%v err=%v", total, items, err)
+	}
+
+	// 不计数，多取一行判断是否有下一页
+	for _, tt := range []struct {
+		page    int
+		hasMore bool
+		length  int
+	}{{2, true, 10}, {3, false, 5}} {
+		items = nil
+		options := &gosqlx.PageOptions{WithoutTotal: true}
+		total, err = db.QueryPage(options, &items, tt.page, 10, "items", []interface{}{"id"})
+		if err != nil || total != -1 || options.HasMore != tt.hasMore || len(items) != tt.length {
+			t.Errorf("第 %d 页 total=%d hasMore=%v items=%d err=%v", tt.page, total, options.HasMore, len(items), err)
+		}
+	}
+
+	// 事务中顺序执行
+	err = db.Transaction(func(tx *gosqlx.Database) error {
+		items = nil
+		total, err := tx.QueryPage(tx.DB(), &items, 3, 10, "items", []interface{}{"id"})
+		if total != 25 || len(items) != 5 {
+			t.Errorf("事务中分页结果 total=%d items=%d", total, len(items))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("事务中分页失败: %v", err)
+	}
+}
+
+// 测试保留策略分批删除和归档过期数据
+func TestSQLiteRetention(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	if err := db.Exec("CREATE TABLE login_logs (id INTEGER PRIMARY KEY, user_id INTEGER, created_at DATETIME)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	now := time.Now()
+	for i := 1; i <= 25; i++ {
+		createdAt := now.Add(-retention.Days(100))
+		if i > 20 {
+			createdAt = now.Add(-retention.Days(10))
+		}
+		if err := db.Exec("INSERT INTO login_logs (user_id, created_at) VALUES (?, ?)", i%2, createdAt); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+
+	var archived []int64
+	policy := retention.Policy{
+		Table:  "login_logs",
+		Column: "created_at",
+		Retain: retention.Days(90),
+		Where:  "user_id = ?",
+		Args:   []interface{}{1},
+		Archive: func(ctx context.Context, tx *gorm.DB, rows []map[string]interface{}) error {
+			for _, row := range rows {
+				archived = append(archived, row["id"].(int64))
+			}
+			return nil
+		},
+	}
+
+	// 试运行只统计
+	runner := retention.New(db, retention.Options{BatchSize: 4, DryRun: true})
+	if err := runner.Register(policy); err != nil {
+		t.Fatalf("注册策略失败: %v", err)
+	}
+	results, err := runner.Run(context.Background())
+	if err != nil || len(results) != 1 || results[0].Deleted != 10 || len(archived) != 0 {
+		t.Fatalf("试运行结果 %+v err=%v", results, err)
+	}
+
+	runner = retention.New(db, retention.Options{BatchSize: 4, Sleep: time.Millisecond})
+	if err := runner.Register(policy); err != nil {
+		t.Fatalf("注册策略失败: %v", err)
+	}
+	results, err = runner.Run(context.Background())
+	if err != nil || results[0].Deleted != 10 || results[0].Batches != 3 || len(archived) != 10 {
+		t.Fatalf("执行结果 %+v archived=%v err=%v", results, archived, err)
+	}
+
+	var remaining int64
+	db.DB().Table("login_logs").Count(&remaining)
+	if remaining != 15 {
+		t.Errorf("剩余 %d 行，期望 15 行", remaining)
+	}
+
+	// 归档失败时不删除
+	runner = retention.New(db, retention.Options{})
+	runner.Register(retention.Policy{Table: "login_logs", Column: "created_at", Retain: retention.Days(90), Archive: func(ctx context.Context, tx *gorm.DB, rows []map[string]interface{}) error {
+		return errors.New("存储不可用")
+	}})
+	if _, err = runner.Run(context.Background()); err == nil {
+		t.Fatal("期望归档失败")
+	}
+	db.DB().Table("login_logs").Count(&remaining)
+	if remaining != 15 {
+		t.Errorf("归档失败后剩余 %d 行，期望 15 行", remaining)
+	}
+}
+
+// corruptTarget 回读时返回错误校验和的归档目标
+type corruptTarget struct {
+	*archive.DatabaseTarget
+}
+
+func (c corruptTarget) Checksum(ctx context.Context, columns []string, key string, keys []interface{}) (string, error) {
+	return "corrupt", nil
+}
+
+// 测试归档到另一个数据库和文件，校验后删除源数据并记录断点
+func TestSQLiteArchive(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+	cold := initSQLiteDB(t)
+	defer cold.Close()
+
+	for _, d := range []*gosqlx.Database{db, cold} {
+		if err := d.Exec("CREATE TABLE orders (id INTEGER PRIMARY KEY, amount REAL, note TEXT, created_at DATETIME)"); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+	cutoff := time.Now().AddDate(0, -6, 0)
+	for i := 1; i <= 10; i++ {
+		createdAt := cutoff.AddDate(0, -1, 0)
+		if i > 7 {
+			createdAt = time.Now()
+		}
+		var note interface{}
+		if i%2 == 0 {
+			note = fmt.Sprintf("order %d", i)
+		}
+		if err := db.Exec("INSERT INTO orders (id, amount, note, created_at) VALUES (?, ?, ?, ?)", i, float64(i)*1.5, note, createdAt); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+	count := func(d *gosqlx.Database) int64 {
+		var n int64
+		d.DB().Table("orders").Count(&n)
+		return n
+	}
+	options := archive.Options{Where: "created_at < ?", Args: []interface{}{cutoff}, BatchSize: 3}
+
+	// 校验失败时不删除源数据
+	_, err := archive.New(db, "orders", corruptTarget{archive.NewDatabaseTarget(cold, "orders")}, options).Run(context.Background())
+	if !errors.Is(err, archive.ErrVerify) || count(db) != 10 {
+		t.Fatalf("期望校验失败且源数据不变，err=%v 源表 %d 行", err, count(db))
+	}
+
+	checkpoint, err := archive.NewTableCheckpoint(db, "")
+	if err != nil {
+		t.Fatalf("创建断点表失败: %v", err)
+	}
+	options.Checkpoint = checkpoint
+	result, err := archive.New(db, "orders", archive.NewDatabaseTarget(cold, "orders"), options).Run(context.Background())
+	if err != nil {
+		t.Fatalf("归档失败: %v", err)
+	}
+	if result.Copied != 7 || result.Deleted != 7 || result.Batches != 3 || result.LastKey != "7" {
+		t.Errorf("归档结果 %+v", result)
+	}
+	if count(db) != 3 || count(cold) != 7 {
+		t.Errorf("源表 %d 行、归档表 %d 行", count(db), count(cold))
+	}
+	if last, _ := checkpoint.Load(context.Background(), "orders"); last != "7" {
+		t.Errorf("断点为 %q，期望 7", last)
+	}
+
+	// 只复制到文件，断点之后的数据保留在源表
+	var buf bytes.Buffer
+	result, err = archive.New(db, "orders", archive.NewWriterTarget(&buf, export.JSONLines, export.Options{}), archive.Options{
+		Name: "orders_file", KeepSource: true, Checkpoint: checkpoint,
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("归档到文件失败: %v", err)
+	}
+	if result.Copied != 3 || result.Deleted != 0 || strings.Count(buf.String(), "\n") != 3 || count(db) != 3 {
+		t.Errorf("归档到文件结果 %+v 输出 %q", result, buf.String())
+	}
+
+	// 从断点继续，没有新数据
+	buf.Reset()
+	result, err = archive.New(db, "orders", archive.NewWriterTarget(&buf, export.JSONLines, export.Options{}), archive.Options{
+		Name: "orders_file", KeepSource: true, Checkpoint: checkpoint,
+	}).Run(context.Background())
+	if err != nil || result.Copied != 0 || buf.Len() != 0 {
+		t.Errorf("从断点继续结果 %+v err=%v", result, err)
+	}
+}
+
+// 测试只读模式拒绝写操作
+func TestSQLiteReadOnly(t *testing.T) {
+	source := t.TempDir() + "/readonly.db"
+	rw, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_ro", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: source})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer rw.Close()
+	if err := rw.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := rw.Exec("INSERT INTO notes (body) VALUES (?)", "hello"); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+
+	ro, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_ro", gosqlx.ModeReadOnly), &gosqlx.Config{Type: gosqlx.SQLite, Source: source, ReadOnlyIntent: true})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer ro.Close()
+
+	type note struct {
+		ID   int64
+		Body string
+	}
+	var notes []note
+	if err := ro.QueryRows(&notes, "/* report */ SELECT id, body FROM notes"); err != nil || len(notes) != 1 {
+		t.Fatalf("只读查询失败: %v %v", notes, err)
+	}
+	var count int64
+	if err := ro.Table("notes").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("只读计数失败: %d %v", count, err)
+	}
+
+	writes := map[string]error{
+		"Create":      ro.Table("notes").Create(map[string]interface{}{"body": "x"}).Error,
+		"Update":      ro.Table("notes").Where("id = ?", 1).Update("body", "x").Error,
+		"Delete":      ro.Exec("DELETE FROM notes WHERE id = ?", 1),
+		"DDL":         ro.Exec("DROP TABLE notes"),
+		"RawRows":     ro.Raw("UPDATE notes SET body = 'x' RETURNING id").Scan(&notes).Error,
+		"Transaction": ro.Transaction(func(tx *gosqlx.Database) error { return tx.Exec("INSERT INTO notes (body) VALUES ('x')") }),
+		"QueryBuilder": func() error {
+			_, err := ro.NewQuery().InsertFromSelect("notes", []string{"body"}, ro.NewQuery().Table("notes").Select("body"))
+			return err
+		}(),
+	}
+	_, err = ro.ExecWithResult("UPDATE notes SET body = 'x'")
+	writes["ExecWithResult"] = err
+	for name, err := range writes {
+		if !errors.Is(err, gosqlx.ErrReadOnly) {
+			t.Errorf("%s: 期望 ErrReadOnly，得到 %v", name, err)
+		}
+	}
+	if err := rw.Table("notes").Where("body = ?", "hello").Count(&count).Error; err != nil || count != 1 {
+		t.Errorf("只读实例的写操作不应生效，count=%d err=%v", count, err)
+	}
+
+	for sqlStr, want := range map[string]bool{
+		"SELECT * FROM notes":                                false,
+		"  (SELECT 1) UNION (SELECT 2)":                      false,
+		"-- audit\nselect 1":                                 false,
+		"WITH t AS (SELECT 1) SELECT * FROM t":               false,
+		"WITH d AS (DELETE FROM notes RETURNING *) SELECT 1": true,
+		"EXPLAIN SELECT 'delete'":                            false,
+		"SELECT id INTO @id FROM notes":                      false,
+		"SELECT * INTO notes_copy FROM notes":                true,
+		"PRAGMA table_info(notes)":                           false,
+		"PRAGMA user_version = 2":                            true,
+		"insert into notes values (1)":                       true,
+		"CALL refresh()":                                     true,
+		"TRUNCATE notes":                                     true,
+	} {
+		if got := gosqlx.IsWriteStatement(sqlStr); got != want {
+			t.Errorf("IsWriteStatement(%q) = %v，期望 %v", sqlStr, got, want)
+		}
+	}
+}
+
+// 测试语句策略按数据库别名拒绝语句并记录违规
+func TestSQLiteStatementPolicy(t *testing.T) {
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_policy", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE accounts (id INTEGER PRIMARY KEY, name TEXT, secret TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO accounts (name, secret) VALUES ('a', 's'), ('b', 's')"); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+
+	var violations []gosqlx.PolicyViolation
+	gosqlx.SetPolicyAuditor(func(v gosqlx.PolicyViolation) { violations = append(violations, v) })
+	defer gosqlx.SetPolicyAuditor(nil)
+	gosqlx.SetPolicy("sqlite_policy", &gosqlx.StatementPolicy{
+		DenyDDL:                true,
+		DenyDeleteWithoutWhere: true,
+		DenyUpdateWithoutWhere: true,
+		DenySelectStar:         []string{"accounts"},
+	})
+	defer gosqlx.SetPolicy("sqlite_policy", nil)
+
+	type account struct {
+		ID   int64
+		Name string
+	}
+	var accounts []account
+	denied := map[string]error{
+		"DDL":          db.Exec("DROP TABLE accounts"),
+		"DeleteAll":    db.Exec("DELETE FROM accounts"),
+		"UpdateAll":    db.Exec("UPDATE accounts SET name = 'x' -- WHERE id = 1"),
+		"GlobalUpdate": db.DB().Session(&gorm.Session{AllowGlobalUpdate: true}).Table("accounts").Update("name", "x").Error,
+		"RawStar":      db.QueryRows(&accounts, "SELECT * FROM accounts"),
+		"ORMStar":      db.DB().Table("accounts").Find(&accounts).Error,
+	}
+	_, err = db.ExecWithResult("DELETE FROM accounts")
+	denied["ExecWithResult"] = err
+	for name, err := range denied {
+		if !errors.Is(err, gosqlx.ErrPolicyViolation) {
+			t.Errorf("%s: 期望 ErrPolicyViolation，得到 %v", name, err)
+		}
+	}
+	if len(violations) != len(denied) || !violations[0].Denied || violations[0].Rule != gosqlx.RuleDDL {
+		t.Errorf("违规记录 %+v", violations)
+	}
+
+	allowed := map[string]error{
+		"Delete":  db.Exec("DELETE FROM accounts WHERE id = ?", 0),
+		"Select":  db.QueryRows(&accounts, "SELECT id, name FROM accounts"),
+		"ORM":     db.DB().Table("accounts").Select("id", "name").Find(&accounts).Error,
+		"Count":   db.DB().Table("accounts").Count(new(int64)).Error,
+		"Literal": db.QueryRows(&accounts, "SELECT id, 'SELECT * FROM accounts' AS name FROM accounts"),
+	}
+	for name, err := range allowed {
+		if err != nil {
+			t.Errorf("%s: 期望允许执行，得到 %v", name, err)
+		}
+	}
+
+	// 只记录不拒绝
+	violations = nil
+	gosqlx.SetPolicy("sqlite_policy", &gosqlx.StatementPolicy{DenySelectStar: []string{"*"}, AuditOnly: true})
+	if err := db.QueryRows(&accounts, "SELECT * FROM accounts"); err != nil || len(accounts) != 2 {
+		t.Errorf("AuditOnly 时应允许执行: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Denied {
+		t.Errorf("AuditOnly 违规记录 %+v", violations)
+	}
+}
+
+type tenantKey struct{}
+
+func TestSQLiteRowFilter(t *testing.T) {
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_row_filter", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE tenant_orders (id INTEGER PRIMARY KEY, tenant_id INTEGER, status INTEGER)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO tenant_orders (tenant_id, status) VALUES (1, 1), (1, 2), (2, 1), (2, 2)"); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+
+	gosqlx.RegisterRowFilter("tenant_orders", func(ctx context.Context) (string, []interface{}, error) {
+		tenantID, ok := ctx.Value(tenantKey{}).(int64)
+		if !ok {
+			return "", nil, errors.New("缺少租户ID")
+		}
+		return "tenant_id = ?", []interface{}{tenantID}, nil
+	})
+	defer gosqlx.ClearRowFilters("tenant_orders")
+
+	type tenantOrder struct {
+		ID       int64
+		TenantID int64
+		Status   int
+	}
+	tenant := db.WithContext(context.WithValue(context.Background(), tenantKey{}, int64(1)))
+
+	// 缺少租户时拒绝执行
+	var orders []tenantOrder
+	if err := db.DB().Table("tenant_orders").Find(&orders).Error; err == nil {
+		t.Error("缺少租户ID时应拒绝查询")
+	}
+
+	if err := tenant.DB().Table("tenant_orders").Find(&orders).Error; err != nil || len(orders) != 2 {
+		t.Errorf("Find 期望2行，得到 %d: %v", len(orders), err)
+	}
+	// OR 条件不能绕过过滤条件
+	if err := tenant.DB().Table("tenant_orders").Where("status = ?", 1).Or("status = ?", 2).Find(&orders).Error; err != nil || len(orders) != 2 {
+		t.Errorf("OR 查询期望2行，得到 %d: %v", len(orders), err)
+	}
+	var total int64
+	if err := tenant.DB().Table("tenant_orders").Count(&total).Error; err != nil || total != 2 {
+		t.Errorf("Count 期望2，得到 %d: %v", total, err)
+	}
+	var first tenantOrder
+	if err := tenant.DB().Table("tenant_orders").Where("id = ?", 3).First(&first).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("其他租户的行应不可见: %v", err)
+	}
+
+	// 查询构建器
+	if err := tenant.NewQuery().Table("tenant_orders").Where("status = ?", 1).Get(&orders); err != nil || len(orders) != 1 {
+		t.Errorf("查询构建器期望1行，得到 %d: %v", len(orders), err)
+	}
+
+	// 更新和删除只影响当前租户，没有条件的删除仍被 GORM 拒绝
+	if err := tenant.DB().Table("tenant_orders").Where("status = ?", 1).Update("status", 3).Error; err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	if err := tenant.DB().Delete(&tenantOrder{}).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("没有条件的删除应被拒绝: %v", err)
+	}
+	if result := tenant.DB().Delete(&tenantOrder{ID: 3}); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("不应删除其他租户的行: %v %d", result.Error, result.RowsAffected)
+	}
+
+	// 跳过过滤条件
+	admin := db.WithoutRowFilters()
+	if err := admin.DB().Table("tenant_orders").Where("status = ?", 3).Count(&total).Error; err != nil || total != 1 {
+		t.Errorf("状态3期望1行，得到 %d: %v", total, err)
+	}
+	if err := admin.NewQuery().Table("tenant_orders").Get(&orders); err != nil || len(orders) != 4 {
+		t.Errorf("跳过过滤条件期望4行，得到 %d: %v", len(orders), err)
+	}
+}
+
+// 测试按复制延迟路由只读副本，副本的延迟由心跳表模拟
+func TestSQLiteReplicaLag(t *testing.T) {
+	dir := t.TempDir()
+	configs := map[string]*gosqlx.Config{}
+	for name, lag := range map[string]int{"lag": 0, "lag_readonly": 10, "lag_readonly_1": 1} {
+		source := dir + "/" + name + ".db"
+		db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), name, gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: source})
+		if err != nil {
+			t.Fatalf("连接失败: %v", err)
+		}
+		if err := db.Exec("CREATE TABLE heartbeat (lag INTEGER, name TEXT)"); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+		if err := db.Exec("INSERT INTO heartbeat (lag, name) VALUES (?, ?)", lag, name); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+		db.Close()
+		configs[name] = &gosqlx.Config{Type: gosqlx.SQLite, Source: source}
+	}
+	manager := gosqlx.NewDatabaseManager(gosqlx.NewConfigManager(gosqlx.NewConfigProvider(gosqlx.ConfigMap{"development": configs})))
+	defer manager.CloseAll()
+	manager.SetReplicaOptions(gosqlx.ReplicaOptions{Probe: func(ctx context.Context, db *gosqlx.Database) (time.Duration, error) {
+		var seconds int64
+		err := db.WithContext(ctx).Raw("SELECT lag FROM heartbeat").Scan(&seconds).Error
+		return time.Duration(seconds) * time.Second, err
+	}})
+
+	statuses, err := manager.ReplicaLag("lag")
+	if err != nil || len(statuses) != 2 {
+		t.Fatalf("ReplicaLag 失败: %v %+v", err, statuses)
+	}
+	if statuses[0].Name != "lag_readonly" || statuses[0].Lag != 10*time.Second || statuses[1].Lag != time.Second {
+		t.Errorf("ReplicaLag %+v", statuses)
+	}
+
+	served := func(ctx *gosqlx.Context) string {
+		db, err := manager.GetDatabase(ctx)
+		if err != nil {
+			t.Fatalf("获取数据库失败: %v", err)
+		}
+		var name string
+		if err := db.Raw("SELECT name FROM heartbeat").Scan(&name).Error; err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+		return name
+	}
+	readOnly := func() *gosqlx.Context {
+		return gosqlx.NewContext(context.Background(), "lag", gosqlx.ModeReadOnly)
+	}
+	if name := served(readOnly()); name != "lag_readonly" {
+		t.Errorf("未设置 MaxStaleness 时应使用第一个副本，得到 %s", name)
+	}
+	if name := served(readOnly().WithMaxStaleness(5 * time.Second)); name != "lag_readonly_1" {
+		t.Errorf("应跳过延迟过大的副本，得到 %s", name)
+	}
+	if name := served(readOnly().WithMaxStaleness(500 * time.Millisecond)); name != "lag" {
+		t.Errorf("所有副本延迟过大时应读主库，得到 %s", name)
+	}
+	if _, err := manager.ReplicaLag("missing"); err == nil {
+		t.Error("没有副本时应返回错误")
+	}
+}
+
+func TestSQLiteLimit(t *testing.T) {
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_limit", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	defer gosqlx.SetLimit("sqlite_limit", nil)
+
+	// 速率：突发 2 条后需要等待 1 秒，超过 MaxWait 被拒绝
+	gosqlx.SetLimit("sqlite_limit", &gosqlx.Limit{QPS: 1, Burst: 2, MaxWait: 50 * time.Millisecond})
+	var n int
+	for i := 0; i < 2; i++ {
+		if err := db.Raw("SELECT 1").Scan(&n).Error; err != nil {
+			t.Fatalf("第 %d 条语句失败: %v", i+1, err)
+		}
+	}
+	if err := db.Raw("SELECT 1").Scan(&n).Error; !errors.Is(err, gosqlx.ErrThrottled) {
+		t.Errorf("期望 ErrThrottled，得到 %v", err)
+	}
+	if _, err := db.ExecWithResult("SELECT 1"); !errors.Is(err, gosqlx.ErrThrottled) {
+		t.Errorf("ExecWithResult 期望 ErrThrottled，得到 %v", err)
+	}
+
+	// 速率：等待时间在 MaxWait 内时排队执行
+	gosqlx.SetLimit("sqlite_limit", &gosqlx.Limit{QPS: 20, Burst: 1, MaxWait: time.Second})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := db.Raw("SELECT 1").Scan(&n).Error; err != nil {
+			t.Fatalf("排队的语句失败: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("3 条语句应至少排队 100ms，实际 %v", elapsed)
+	}
+
+	// 并发：占用唯一的额度时其他语句排队超时
+	gosqlx.SetLimit("sqlite_limit", &gosqlx.Limit{MaxConcurrent: 1, MaxWait: 20 * time.Millisecond})
+	done := make(chan error, 1)
+	go func() {
+		done <- db.Exec("WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 2000000) SELECT COUNT(*) FROM c")
+	}()
+	for i := 0; i < 100; i++ {
+		if stats, _ := gosqlx.GetLimitStats("sqlite_limit"); stats.Running == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	err = db.Raw("SELECT 1").Scan(&n).Error
+	if err := <-done; err != nil {
+		t.Fatalf("长查询失败: %v", err)
+	}
+	if !errors.Is(err, gosqlx.ErrThrottled) {
+		t.Errorf("并发已满时期望 ErrThrottled，得到 %v", err)
+	}
+	if stats, ok := gosqlx.GetLimitStats("sqlite_limit"); !ok || stats.Running != 0 || stats.Throttled != 1 {
+		t.Errorf("限流状态 %+v", stats)
+	}
+	if err := db.Raw("SELECT 1").Scan(&n).Error; err != nil {
+		t.Errorf("额度释放后应可执行: %v", err)
+	}
+}
+
+func TestSQLiteWarmUp(t *testing.T) {
+	source := t.TempDir() + "/warmup.db"
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_warmup", gosqlx.ModeReadWrite), &gosqlx.Config{
+		Type: gosqlx.SQLite, Source: source, MaxIdle: 3, MaxOpen: 10, WarmUp: 5, KeepAlive: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	// 预热的连接数不超过 MaxIdle
+	if stats := db.SqlDB().Stats(); stats.OpenConnections != 3 || stats.Idle != 3 {
+		t.Errorf("预热后连接池 %+v", stats)
+	}
+
+	// 保活不会占用或关闭空闲连接
+	time.Sleep(70 * time.Millisecond)
+	if stats := db.SqlDB().Stats(); stats.Idle != 3 || stats.InUse != 0 {
+		t.Errorf("保活后连接池 %+v", stats)
+	}
+	var n int
+	if err := db.Raw("SELECT 1").Scan(&n).Error; err != nil || n != 1 {
+		t.Errorf("查询失败: %v", err)
+	}
+}
+
+func TestSQLiteConnInit(t *testing.T) {
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_conn_init", gosqlx.ModeReadWrite), &gosqlx.Config{
+		Type:     gosqlx.SQLite,
+		Source:   t.TempDir() + "/conn_init.db",
+		MaxIdle:  3,
+		MaxOpen:  3,
+		WarmUp:   3,
+		ConnInit: []string{"PRAGMA busy_timeout = 4321", "CREATE TEMP TABLE session_marker (id INTEGER)"},
+	})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+
+	// 同时占用所有连接，每个连接都执行过初始化语句
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.SqlDB().Conn(ctx)
+		if err != nil {
+			t.Fatalf("获取连接失败: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	for i, conn := range conns {
+		var timeout int
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&timeout); err != nil || timeout != 4321 {
+			t.Errorf("连接 %d: busy_timeout = %d, %v", i, timeout, err)
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO session_marker (id) VALUES (?)", i); err != nil {
+			t.Errorf("连接 %d: 临时表不存在: %v", i, err)
+		}
+		conn.Close()
+	}
+
+	_, err = gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_conn_init", gosqlx.ModeReadWrite), &gosqlx.Config{
+		Type: gosqlx.SQLite, Source: ":memory:", ConnInit: []string{"SET search_path TO app"},
+	})
+	if err == nil {
+		t.Error("初始化语句失败时应返回错误")
+	}
+}
+
+// 测试事务中语句因锁冲突失败时回滚到隐式保存点重试，不影响事务中已执行的语句
+func TestSQLiteStatementRetry(t *testing.T) {
+	source := t.TempDir() + "/stmt_retry.db?_busy_timeout=0"
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_stmt_retry", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: source, MaxIdle: 2, MaxOpen: 2})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.Exec("CREATE TABLE jobs (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	// holdLock 在另一个连接上持有写锁，hold 后释放
+	holdLock := func(hold time.Duration) {
+		conn, err := db.SqlDB().Conn(context.Background())
+		if err != nil {
+			t.Fatalf("获取连接失败: %v", err)
+		}
+		if _, err := conn.ExecContext(context.Background(), "BEGIN IMMEDIATE"); err != nil {
+			t.Fatalf("加锁失败: %v", err)
+		}
+		go func() {
+			time.Sleep(hold)
+			// 被测事务持有共享锁时提交会返回 database is locked，重试直到释放写锁
+			for i := 0; i < 100; i++ {
+				if _, err := conn.ExecContext(context.Background(), "COMMIT"); err == nil {
+					break
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			conn.Close()
+		}()
+	}
+	insertLocked := func(db *gosqlx.Database, name string) error {
+		return db.Transaction(func(tx *gosqlx.Database) error {
+			// 临时表不占用主库的锁，用于确认重试不会回滚之前的语句
+			if err := tx.Exec("CREATE TEMP TABLE IF NOT EXISTS steps (name TEXT)"); err != nil {
+				return err
+			}
+			if err := tx.Exec("INSERT INTO steps (name) VALUES (?)", name); err != nil {
+				return err
+			}
+			holdLock(60 * time.Millisecond)
+			if err := tx.Exec("INSERT INTO jobs (name) VALUES (?)", name); err != nil {
+				return err
+			}
+			var steps int64
+			if err := tx.Raw("SELECT COUNT(*) FROM steps WHERE name = ?", name).Scan(&steps).Error; err != nil || steps != 1 {
+				return fmt.Errorf("之前的语句被回滚: %d %v", steps, err)
+			}
+			return nil
+		})
+	}
+
+	if err := insertLocked(db, "once"); !gosqlxerrors.IsRetryable(err) {
+		t.Fatalf("未开启重试时期望锁冲突，得到 %v", err)
+	}
+	time.Sleep(80 * time.Millisecond)
+
+	if err := insertLocked(db.WithStatementRetry(10), "retried"); err != nil {
+		t.Fatalf("开启重试后应成功: %v", err)
+	}
+	var names []string
+	if err := db.DB().Table("jobs").Pluck("name", &names).Error; err != nil || len(names) != 1 || names[0] != "retried" {
+		t.Errorf("期望只有 retried，得到 %v %v", names, err)
+	}
+
+	// 嵌套事务的保存点不受隐式保存点影响
+	err = db.WithStatementRetry(3).Transaction(func(tx *gosqlx.Database) error {
+		if err := tx.Exec("INSERT INTO jobs (name) VALUES ('outer')"); err != nil {
+			return err
+		}
+		_ = tx.Transaction(func(inner *gosqlx.Database) error {
+			if err := inner.Exec("INSERT INTO jobs (name) VALUES ('inner')"); err != nil {
+				return err
+			}
+			return errors.New("回滚嵌套事务")
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("事务失败: %v", err)
+	}
+	if err := db.DB().Table("jobs").Order("id").Pluck("name", &names).Error; err != nil || len(names) != 2 || names[1] != "outer" {
+		t.Errorf("期望 retried、outer，得到 %v %v", names, err)
+	}
+
+	// GORM 的 INSERT ... RETURNING 走 QueryContext，之后的语句只释放保存点，不能回滚插入
+	type job struct {
+		ID   int64 `gorm:"primaryKey"`
+		Name string
+	}
+	err = db.WithStatementRetry(3).Transaction(func(tx *gosqlx.Database) error {
+		if err := tx.DB().Table("jobs").Create(&job{Name: "returning"}).Error; err != nil {
+			return err
+		}
+		var count int64
+		return tx.Raw("SELECT COUNT(*) FROM jobs").Scan(&count).Error
+	})
+	if err != nil {
+		t.Fatalf("事务失败: %v", err)
+	}
+	var count int64
+	if err := db.DB().Table("jobs").Where("name = ?", "returning").Count(&count).Error; err != nil || count != 1 {
+		t.Errorf("期望查询后插入的行已提交，得到 %d %v", count, err)
+	}
+}
+
+// 测试按主键批量查询
+func TestSQLiteFindByIDs(t *testing.T) {
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_find_by_ids", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.DB().AutoMigrate(&SQLiteMember{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	members := make([]SQLiteMember, 0, 2500)
+	for i := 1; i <= 2500; i++ {
+		members = append(members, SQLiteMember{ID: int64(i), Name: fmt.Sprintf("member%d", i)})
+	}
+	if err := db.CreateInBatches(&members, 100); err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+
+	// 超过 IN 上限时分批，结果按 ids 的顺序排列，重复主键只返回一行
+	ids := []int{2500, 3, 9999, 1200, 3}
+	for i := 2000; i >= 1; i-- {
+		ids = append(ids, i)
+	}
+	var found []SQLiteMember
+	missing, err := db.FindByIDs(&found, ids)
+	if err != nil {
+		t.Fatalf("FindByIDs() error = %v", err)
+	}
+	if len(missing) != 1 || missing[0] != 9999 {
+		t.Errorf("missing = %v", missing)
+	}
+	if len(found) != 2001 || found[0].ID != 2500 || found[1].ID != 3 || found[2].ID != 1200 || found[3].ID != 2000 || found[2000].ID != 1 {
+		t.Fatalf("found 顺序错误: len=%d", len(found))
+	}
+	if found[0].Name != "member2500" {
+		t.Errorf("found[0] = %+v", found[0])
+	}
+
+	// 指针切片、事务内顺序执行
+	err = db.Transaction(func(tx *gosqlx.Database) error {
+		var rows []*SQLiteMember
+		missing, err := tx.FindByIDs(&rows, []int64{5, 4})
+		if err != nil {
+			return err
+		}
+		if len(missing) != 0 || len(rows) != 2 || rows[0].ID != 5 || rows[1].ID != 4 {
+			t.Errorf("rows = %v, missing = %v", rows, missing)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("事务内 FindByIDs() error = %v", err)
+	}
+
+	var empty []SQLiteMember
+	if missing, err := db.FindByIDs(&empty, []int64{}); err != nil || missing != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("空主键: %v %v %v", empty, missing, err)
+	}
+	if _, err := db.FindByIDs(&SQLiteMember{}, []int64{1}); err == nil {
+		t.Error("结果不是切片时应返回错误")
+	}
+}
+
+// 测试由数据库序列化查询结果为 JSON
+func TestSQLiteToJSON(t *testing.T) {
+	db, err := gosqlx.NewDatabase(gosqlx.NewContext(context.Background(), "sqlite_to_json", gosqlx.ModeReadWrite), &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer db.Close()
+	if err := db.DB().AutoMigrate(&SQLiteMember{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	for _, m := range []SQLiteMember{{ID: 1, Name: "alice", Age: 20}, {ID: 2, Name: `bo"b`, Age: 30}, {ID: 3, Name: "carol", Age: 20}} {
+		if err := db.Create(&m); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+
+	data, err := query.NewQuery(db.SqlDB()).Table("members").Select("id", "name", "age * 2 AS double_age").
+		Where("id > ?", 1).OrderByDesc("id").ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		t.Fatalf("结果不是合法的 JSON: %s", data)
+	}
+	if len(rows) != 2 || rows[0]["id"] != 3.0 || rows[1]["name"] != `bo"b` || rows[1]["double_age"] != 60.0 {
+		t.Errorf("rows = %s", data)
+	}
+
+	data, err = query.NewQuery(db.SqlDB()).Table("members").Distinct("age").OrderByAsc("age").ToJSON()
+	if err != nil || string(data) != `[{"age":20},{"age":30}]` {
+		t.Errorf("DISTINCT ToJSON() = %s, %v", data, err)
+	}
+	data, err = query.NewQuery(db.SqlDB()).Table("members").Where("id > ?", 100).ToJSON()
+	if err != nil || string(data) != "[]" {
+		t.Errorf("空结果 ToJSON() = %s, %v", data, err)
+	}
+}
+
+// 测试批量插入按参数上限分批
+func TestSQLiteBatchInsertChunked(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+	prepareSQLiteTestTables(t, db)
+
+	columns := []string{"username", "email", "age", "active"}
+	values := make([][]interface{}, 1000)
+	for i := range values {
+		values[i] = []interface{}{fmt.Sprintf("chunk%d", i), fmt.Sprintf("chunk%d@example.com", i), i % 80, i % 2}
+	}
+
+	// 每行 4 个参数，999 个参数上限下每批 249 行
+	var progress []int
+	err := db.BatchInsertWithOptions("users", columns, values, gosqlx.BatchInsertOptions{
+		OnProgress: func(inserted, total int) {
+			if total != len(values) {
+				t.Errorf("total = %d", total)
+			}
+			progress = append(progress, inserted)
+		},
+	})
+	if err != nil {
+		t.Fatalf("批量插入失败: %v", err)
+	}
+	if fmt.Sprint(progress) != "[249 498 747 996 1000]" {
+		t.Errorf("progress = %v", progress)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil || count != 1000 {
+		t.Fatalf("count = %d, %v", count, err)
+	}
+
+	// 第二批违反 NOT NULL：默认整体回滚，ChunkTx 时保留第一批
+	bad := make([][]interface{}, 20)
+	for i := range bad {
+		bad[i] = []interface{}{fmt.Sprintf("bad%d", i), "bad@example.com", 1, 1}
+	}
+	bad[15][0] = nil
+	countBad := func() int {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE username LIKE 'bad%'").Scan(&n); err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+		return n
+	}
+	if err := db.BatchInsertWithOptions("users", columns, bad, gosqlx.BatchInsertOptions{ChunkSize: 10}); err == nil {
+		t.Fatal("期望插入失败")
+	}
+	if n := countBad(); n != 0 {
+		t.Errorf("同一事务失败后应全部回滚，实际插入 %d 行", n)
+	}
+	if err := db.BatchInsertWithOptions("users", columns, bad, gosqlx.BatchInsertOptions{ChunkSize: 10, ChunkTx: true}); err == nil {
+		t.Fatal("期望插入失败")
+	}
+	if n := countBad(); n != 10 {
+		t.Errorf("ChunkTx 失败后应保留第一批，实际插入 %d 行", n)
+	}
+}
+
+// 测试适配器特性
+func TestSQLiteCapabilities(t *testing.T) {
+	db := initSQLiteDB(t)
+	defer db.Close()
+
+	caps := db.Capabilities()
+	if !caps.Transactions || !caps.Savepoints || !caps.CTE || caps.SkipLocked || caps.MaxBindParams != 999 {
+		t.Errorf("Capabilities() = %+v", caps)
+	}
+	if db.ParamLimit() != caps.MaxBindParams {
+		t.Errorf("ParamLimit() = %d, want %d", db.ParamLimit(), caps.MaxBindParams)
+	}
+
+	// 各适配器的参数上限与 IN 列表上限
+	for name, want := range map[string][2]int{"sqlserver": {2000, 0}, "oracle": {32000, 1000}, "postgres": {60000, 0}} {
+		factory, ok := adapter.Lookup(name)
+		if !ok {
+			t.Fatalf("适配器 %s 未注册", name)
+		}
+		caps := factory(adapter.Options{}).Capabilities()
+		if caps.MaxBindParams != want[0] || caps.MaxInList != want[1] {
+			t.Errorf("%s Capabilities() = %+v", name, caps)
+		}
+	}
+	if caps := adapter.NewClickHouse("").Capabilities(); caps.Transactions || caps.Upsert {
+		t.Errorf("clickhouse Capabilities() = %+v", caps)
+	}
+
+	// 连接后按服务端版本返回特性：测试使用的 SQLite 支持 RETURNING
+	if !caps.Returning || !caps.ReleaseSavepoint || caps.OutputClause {
+		t.Errorf("sqlite Capabilities() = %+v", caps)
+	}
+	if caps := adapter.NewSQLServer("").Capabilities(); !caps.Returning || !caps.OutputClause || caps.ReleaseSavepoint {
+		t.Errorf("sqlserver Capabilities() = %+v", caps)
+	}
+	for _, tc := range []struct {
+		name, version string
+		returning     bool
+		skipLocked    bool
+	}{
+		{"mariadb", "10.4.32-MariaDB", false, false},
+		{"mariadb", "10.5.23-MariaDB-log", true, false},
+		{"mariadb", "10.11.6-MariaDB", true, true},
+		{"mysql", "5.7.44-log", false, false},
+		{"mysql", "8.0.36", false, true},
+	} {
+		factory, _ := adapter.Lookup(tc.name)
+		a := factory(adapter.Options{})
+		a.(adapter.VersionAware).SetServerVersion(tc.version)
+		if caps := a.Capabilities(); caps.Returning != tc.returning || caps.SkipLocked != tc.skipLocked {
+			t.Errorf("%s %s Capabilities() = %+v", tc.name, tc.version, caps)
+		}
+	}
+	sqlite := adapter.NewSQLite("")
+	sqlite.SetServerVersion("3.31.1")
+	if sqlite.Capabilities().Returning {
+		t.Error("SQLite 3.31 不应支持 RETURNING")
+	}
+}
+
+func TestSQLiteMemoryConcurrency(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_memory", gosqlx.ModeReadWrite)
+
+	// 普通内存库限制为单个连接，连接池配置更大时表仍然可见
+	plain, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 4, MaxOpen: 4, MaxLifetime: time.Millisecond})
+	if err != nil {
+		t.Fatalf("连接内存库失败: %v", err)
+	}
+	defer plain.Close()
+	if stats := plain.SqlDB().Stats(); stats.MaxOpenConnections != 1 {
+		t.Errorf("MaxOpenConnections = %d, want 1", stats.MaxOpenConnections)
+	}
+
+	// 命名的共享缓存内存库，多个连接并发写入
+	shared, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: gosqlx.SQLiteMemoryDSN(t.Name()), MaxIdle: 4, MaxOpen: 4})
+	if err != nil {
+		t.Fatalf("连接共享内存库失败: %v", err)
+	}
+	defer shared.Close()
+
+	for _, db := range []*gosqlx.Database{plain, shared} {
+		if err := db.Exec("CREATE TABLE counters (id INTEGER PRIMARY KEY AUTOINCREMENT, worker INTEGER)"); err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < 25; i++ {
+					if err := db.Exec("INSERT INTO counters (worker) VALUES (?)", worker); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}(worker)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("并发写入失败: %v", err)
+		}
+		var count int64
+		if err := db.DB().Raw("SELECT COUNT(*) FROM counters").Scan(&count).Error; err != nil || count != 200 {
+			t.Errorf("COUNT = %d, %v, want 200", count, err)
+		}
+	}
+
+	// 不同名的内存库互不影响
+	other, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: gosqlx.SQLiteMemoryDSN(t.Name() + "_other"), MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接共享内存库失败: %v", err)
+	}
+	defer other.Close()
+	if err := other.Exec("SELECT COUNT(*) FROM counters"); err == nil {
+		t.Error("不同名的内存库不应看到 counters 表")
+	}
+}
+
+func TestSQLiteBackupTo(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_backup", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	defer db.Close()
+	sqliteAdapter := db.Adapter().(*adapter.SQLite)
+
+	if err := db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)"); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	for i := 0; i < 500; i++ {
+		if err := db.Exec("INSERT INTO notes (body) VALUES (?)", strings.Repeat("x", 100)); err != nil {
+			t.Fatalf("插入失败: %v", err)
+		}
+	}
+
+	problems, err := sqliteAdapter.IntegrityCheck(db.DB(), false)
+	if err != nil || problems != nil {
+		t.Fatalf("IntegrityCheck() = %v, %v", problems, err)
+	}
+	if problems, err := sqliteAdapter.IntegrityCheck(db.DB(), true); err != nil || problems != nil {
+		t.Fatalf("IntegrityCheck(quick) = %v, %v", problems, err)
+	}
+	if err := sqliteAdapter.Optimize(db.DB()); err != nil {
+		t.Fatalf("Optimize() 失败: %v", err)
+	}
+
+	// 备份内存库到文件，覆盖已有文件
+	path := t.TempDir() + "/backup.db"
+	if err := os.WriteFile(path, []byte("stale"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteAdapter.BackupTo(db.DB(), path); err != nil {
+		t.Fatalf("BackupTo() 失败: %v", err)
+	}
+	backup, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: path, MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("打开备份失败: %v", err)
+	}
+	defer backup.Close()
+	var count int64
+	if err := backup.DB().Raw("SELECT COUNT(*) FROM notes").Scan(&count).Error; err != nil || count != 500 {
+		t.Errorf("备份中 COUNT = %d, %v, want 500", count, err)
+	}
+	if problems, err := backup.Adapter().(*adapter.SQLite).IntegrityCheck(backup.DB(), false); err != nil || problems != nil {
+		t.Errorf("备份 IntegrityCheck() = %v, %v", problems, err)
+	}
+
+	if err := sqliteAdapter.BackupTo(db.DB(), t.TempDir()+"/missing/backup.db"); err == nil {
+		t.Error("目录不存在时 BackupTo() 应返回错误")
+	}
+}
+
+func TestSQLiteInsertReturning(t *testing.T) {
+	gosqlx.RegisterDialect("litefork", sqlite.Open)
+	for _, dbType := range []gosqlx.DatabaseType{gosqlx.SQLite, "litefork"} {
+		// litefork 没有 RETURNING 路径，使用 LastInsertId 后查询
+		ctx := gosqlx.NewContext(context.Background(), "sqlite_returning", gosqlx.ModeReadWrite)
+		db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: dbType, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+		if err != nil {
+			t.Fatalf("%s: 连接数据库失败: %v", dbType, err)
+		}
+		err = db.Exec("CREATE TABLE tickets (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT NOT NULL, status TEXT NOT NULL DEFAULT 'open')")
+		if err != nil {
+			t.Fatalf("%s: 建表失败: %v", dbType, err)
+		}
+
+		id, err := db.InsertReturningID("tickets", []string{"title"}, []interface{}{"first"}, "id")
+		if err != nil || id != 1 {
+			t.Errorf("%s: InsertReturningID() = %d, %v, want 1", dbType, id, err)
+		}
+
+		var ticket struct {
+			ID     int64
+			Title  string
+			Status string
+		}
+		err = db.InsertReturning(&ticket, "tickets", []string{"title"}, []interface{}{"second"}, "id", "id", "title", "status")
+		if err != nil || ticket.ID != 2 || ticket.Title != "second" || ticket.Status != "open" {
+			t.Errorf("%s: InsertReturning() = %+v, %v", dbType, ticket, err)
+		}
+
+		// 主键由调用方指定
+		row := map[string]interface{}{}
+		err = db.InsertReturning(&row, "tickets", []string{"id", "title"}, []interface{}{10, "third"}, "id")
+		if err != nil || fmt.Sprint(row["id"]) != "10" || row["status"] != "open" {
+			t.Errorf("%s: InsertReturning(map) = %v, %v", dbType, row, err)
+		}
+
+		if _, err := db.InsertReturningID("tickets", []string{"title"}, nil, "id"); err == nil {
+			t.Errorf("%s: 值个数与列数不一致时应返回错误", dbType)
+		}
+		db.Close()
+	}
+}
+
+// 测试 ValueFormat 统一时间、定点数和 uint64 参数的格式
+func TestSQLiteValueFormat(t *testing.T) {
+	ctx := gosqlx.NewContext(context.Background(), "sqlite_values", gosqlx.ModeReadWrite)
+	format := gosqlx.DefaultValueFormat(gosqlx.SQLite)
+	format.TimePrecision = time.Millisecond
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1, ValueFormat: format})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	defer db.Close()
+
+	type Reading struct {
+		ID        int64
+		Counter   string
+		Amount    string
+		TakenAt   time.Time
+		CheckedAt *time.Time
+	}
+	if err := db.DB().AutoMigrate(&Reading{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	taken := time.Date(2024, 5, 6, 7, 8, 9, 123456789, shanghai)
+	reading := Reading{Counter: "1", Amount: "0", TakenAt: taken, CheckedAt: &taken}
+	if err := db.Create(&reading); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// 超出 int64 范围的 uint64 和定点数绑定为字符串
+	if err := db.Exec("UPDATE readings SET counter = ?, amount = ? WHERE id = ?", uint64(math.MaxUint64), big.NewRat(5, 4), reading.ID); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if _, err := db.ExecWithResult("UPDATE readings SET amount = ? WHERE id = ? AND taken_at = ?", big.NewRat(1, 3), reading.ID, taken); err == nil {
+		t.Error("无法精确表示的定点数应返回错误")
+	}
+
+	var raw struct {
+		Counter string
+		Amount  string
+		TakenAt string
+	}
+	if err := db.ScanRaw(&raw, "SELECT counter, amount, taken_at FROM readings WHERE id = ?", reading.ID); err != nil {
+		t.Fatalf("ScanRaw() error = %v", err)
+	}
+	if raw.Counter != "18446744073709551615" || raw.Amount != "1.25" || raw.TakenAt != "2024-05-05T23:08:09.123Z" {
+		t.Errorf("写入的值 = %+v", raw)
+	}
+
+	// 查询条件中的时间同样转换，扫描到模型的时间转换到 UTC
+	var found Reading
+	if err := db.First(&found, "taken_at = ?", taken); err != nil {
+		t.Fatalf("First() error = %v", err)
+	}
+	if found.TakenAt.Location() != time.UTC || found.CheckedAt.Location() != time.UTC || !found.TakenAt.Equal(taken.Truncate(time.Millisecond)) {
+		t.Errorf("扫描的时间 = %v, %v", found.TakenAt, found.CheckedAt)
+	}
+
+	if _, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", ValueFormat: &gosqlx.ValueFormat{TimeZone: "Mars/Base"}}); err == nil {
+		t.Error("无效的时区应返回错误")
+	}
+}
+
+// 测试语句批处理在 SQLite 上按顺序执行
+func TestSQLiteBatch(t *testing.T) {
+	db := initSQLiteScanDB(t, 3)
+	defer db.Close()
+
+	b := db.NewBatch()
+	update := b.Exec("UPDATE quotes SET note = ? WHERE id <= ?", "seen", 2)
+	quotes := b.Query("SELECT id, name, note FROM quotes WHERE note = ? ORDER BY id", "seen")
+	count := b.Query("SELECT COUNT(*) AS n FROM quotes")
+	if b.Len() != 3 {
+		t.Fatalf("Len = %d", b.Len())
+	}
+	if err := b.Flush(); err != nil {
+		t.Fatalf("Flush 失败: %v", err)
+	}
+	if b.Len() != 0 || update.RowsAffected != 2 || len(quotes.Rows) != 2 || quotes.Rows[1]["name"] != "q2" || count.Rows[0]["n"] != int64(3) {
+		t.Errorf("结果 = %d %v %v", update.RowsAffected, quotes.Rows, count.Rows)
+	}
+
+	// 失败的语句不影响其他语句，Flush 返回第一条错误
+	b.Exec("INSERT INTO missing_table (id) VALUES (1)")
+	insert := b.Exec("INSERT INTO quotes (id, name) VALUES (?, ?)", 4, "q4")
+	if err := b.Flush(); err == nil || !strings.Contains(err.Error(), "missing_table") {
+		t.Errorf("期望 missing_table 错误，实际为 %v", err)
+	}
+	if insert.Err != nil || insert.RowsAffected != 1 {
+		t.Errorf("插入结果 = %d, %v", insert.RowsAffected, insert.Err)
+	}
+
+	// 事务中在事务连接上执行，回滚后不可见
+	err := db.Transaction(func(tx *gosqlx.Database) error {
+		b := tx.NewBatch()
+		b.Exec("DELETE FROM quotes")
+		left := b.Query("SELECT id FROM quotes")
+		if err := b.Flush(); err != nil || len(left.Rows) != 0 {
+			t.Errorf("事务中结果 = %v, %v", left.Rows, err)
+		}
+		return errors.New("rollback")
+	})
+	var n int64
+	if db.DB().Table("quotes").Count(&n); err == nil || n != 4 {
+		t.Errorf("回滚后行数 = %d, %v", n, err)
+	}
+}
+
+// 测试并行查询的并发上限和错误处理
+func TestSQLiteParallel(t *testing.T) {
+	db := initSQLiteScanDB(t, 5)
+	defer db.Close()
+
+	var total, expensive int64
+	var names []string
+	err := db.Parallel(context.Background(),
+		func(db *gosqlx.Database) error { return db.DB().Table("quotes").Count(&total).Error },
+		func(db *gosqlx.Database) error {
+			return db.DB().Table("quotes").Where("price > ?", 2).Count(&expensive).Error
+		},
+		func(db *gosqlx.Database) error {
+			return db.DB().Table("quotes").Order("id").Limit(2).Pluck("name", &names).Error
+		},
+	)
+	if err != nil || total != 5 || expensive != 3 || strings.Join(names, ",") != "q1,q2" {
+		t.Errorf("结果 = %d %d %v, %v", total, expensive, names, err)
+	}
+
+	// 同时执行的函数数不超过 Workers
+	var mutex sync.Mutex
+	var active, peak int
+	fns := make([]func(db *gosqlx.Database) error, 8)
+	for i := range fns {
+		fns[i] = func(*gosqlx.Database) error {
+			mutex.Lock()
+			active++
+			peak = max(peak, active)
+			mutex.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mutex.Lock()
+			active--
+			mutex.Unlock()
+			return nil
+		}
+	}
+	if err := db.ParallelWithOptions(context.Background(), gosqlx.ParallelOptions{Workers: 2}, fns...); err != nil || peak != 2 {
+		t.Errorf("并发峰值 = %d, %v", peak, err)
+	}
+
+	// 第一个错误时取消其余函数
+	failed := errors.New("failed")
+	start := time.Now()
+	err = db.Parallel(context.Background(),
+		func(*gosqlx.Database) error { return failed },
+		func(db *gosqlx.Database) error {
+			select {
+			case <-db.DB().Statement.Context.Done():
+				return db.DB().Statement.Context.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		},
+	)
+	if err != failed || time.Since(start) > time.Second {
+		t.Errorf("期望立即返回 failed，实际为 %v (%v)", err, time.Since(start))
+	}
+
+	// 收集所有错误
+	err = db.ParallelWithOptions(context.Background(), gosqlx.ParallelOptions{CollectAll: true},
+		func(*gosqlx.Database) error { return nil },
+		func(db *gosqlx.Database) error { return db.Exec("SELECT * FROM missing_a") },
+		func(*gosqlx.Database) error { return failed },
+	)
+	if !errors.Is(err, failed) || !strings.Contains(err.Error(), "第 2 个查询失败") || !strings.Contains(err.Error(), "第 3 个查询失败") {
+		t.Errorf("合并的错误 = %v", err)
+	}
+
+	// 事务中按顺序在事务连接上执行
+	err = db.Transaction(func(tx *gosqlx.Database) error {
+		if err := tx.Exec("DELETE FROM quotes WHERE id > 1"); err != nil {
+			return err
+		}
+		return db.Parallel(tx.Context(),
+			func(db *gosqlx.Database) error { return db.DB().Table("quotes").Count(&total).Error },
+			func(db *gosqlx.Database) error {
+				if !db.InTransaction() {
+					return errors.New("未在事务中执行")
+				}
+				return nil
+			},
+		)
+	})
+	if err != nil || total != 1 {
+		t.Errorf("事务中结果 = %d, %v", total, err)
+	}
+}
+
+// 测试按键列将原生查询结果写入 map
+func TestSQLiteScanIndexed(t *testing.T) {
+	db := initSQLiteScanDB(t, 4)
+	defer db.Close()
+
+	type quote struct {
+		ID    int64   `db:"id"`
+		Name  string  `db:"name"`
+		Price float64 `db:"price"`
+	}
+	quotes := make(map[string]quote)
+	if err := db.ScanIndexed(quotes, "name", "SELECT id, name, price FROM quotes WHERE id > ?", 1); err != nil {
+		t.Fatalf("ScanIndexed 失败: %v", err)
+	}
+	if len(quotes) != 3 || quotes["q3"].ID != 3 || quotes["q3"].Price != 2.5 {
+		t.Errorf("quotes = %+v", quotes)
+	}
+
+	var parity map[int64][]int64
+	if err := db.ScanIndexed(&parity, "odd", "SELECT id % 2 AS odd, id FROM quotes ORDER BY id"); err != nil {
+		t.Fatalf("分组 ScanIndexed 失败: %v", err)
+	}
+	if !reflect.DeepEqual(parity, map[int64][]int64{0: {2, 4}, 1: {1, 3}}) {
+		t.Errorf("parity = %v", parity)
+	}
+}
+
+// SQLiteNullUser 使用 Null 字段的模型
+type SQLiteNullUser struct {
+	ID       int64                   `gorm:"primaryKey" db:"id"`
+	Nickname gosqlx.Null[string]     `db:"nickname"`
+	Level    gosqlx.Null[int32]      `db:"level"`
+	Deleted  gosqlx.Null[time.Time]  `db:"deleted"`
+	Note     sql.NullString          `db:"note"`
+	Score    *float64                `db:"score"`
+	Status   gosqlx.Null[OrderState] `db:"status"`
+}
+
+// TableName 表名，sqlite_ 开头的表名为 SQLite 保留
+func (SQLiteNullUser) TableName() string {
+	return "null_users"
+}
+
+// OrderState 自定义字符串类型
+type OrderState string
+
+// 测试 Null[T]、sql.Null* 和指针字段的写入与扫描
+func TestSQLiteNull(t *testing.T) {
+	db := initSQLiteScanDB(t, 0)
+	defer db.Close()
+	if err := db.DB().AutoMigrate(&SQLiteNullUser{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	deleted := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	users := []SQLiteNullUser{
+		{ID: 1, Nickname: gosqlx.NullOf("neo"), Level: gosqlx.NullOf[int32](3), Deleted: gosqlx.NullOf(deleted),
+			Note: sql.NullString{String: "n", Valid: true}, Status: gosqlx.NullOf(OrderState("paid"))},
+		{ID: 2},
+	}
+	if err := db.Create(&users); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO null_users (id, nickname, level) VALUES (?, ?, ?)", 3, gosqlx.NullOf("raw"), gosqlx.Null[int32]{}); err != nil {
+		t.Fatalf("原生语句写入失败: %v", err)
+	}
+
+	check := func(name string, got []SQLiteNullUser) {
+		t.Helper()
+		if len(got) != 3 {
+			t.Fatalf("%s: 行数 = %d", name, len(got))
+		}
+		first := got[0]
+		if first.Nickname.Or("") != "neo" || first.Level.V != 3 || !first.Deleted.V.Equal(deleted) || first.Note.String != "n" ||
+			first.Score != nil || first.Status.V != "paid" {
+			t.Errorf("%s: 第一行 = %+v", name, first)
+		}
+		if got[1].Nickname.Valid || got[1].Level.Valid || got[1].Deleted.Valid || got[1].Note.Valid || got[1].Status.Valid {
+			t.Errorf("%s: NULL 行 = %+v", name, got[1])
+		}
+		if got[2].Nickname.V != "raw" || got[2].Level.Valid {
+			t.Errorf("%s: 原生写入的行 = %+v", name, got[2])
+		}
+	}
+	var viaGorm []SQLiteNullUser
+	if err := db.DB().Order("id").Find(&viaGorm).Error; err != nil {
+		t.Fatalf("GORM 查询失败: %v", err)
+	}
+	check("GORM", viaGorm)
+	var viaBuilder []SQLiteNullUser
+	if err := db.NewQuery().Table("null_users").OrderBy("id").Get(&viaBuilder); err != nil {
+		t.Fatalf("查询构建器查询失败: %v", err)
+	}
+	check("查询构建器", viaBuilder)
+
+	data, err := json.Marshal(viaBuilder[1].Nickname)
+	if err != nil || string(data) != "null" {
+		t.Errorf("NULL 的 JSON = %s, %v", data, err)
+	}
+	var decoded gosqlx.Null[int32]
+	if err := json.Unmarshal([]byte("7"), &decoded); err != nil || decoded.Ptr() == nil || *decoded.Ptr() != 7 {
+		t.Errorf("JSON 解码 = %+v, %v", decoded, err)
+	}
+}
+
+// SQLiteAccount 带有数据库默认值和生成列的模型，-> 标签的列只读，插入时由数据库填充
+type SQLiteAccount struct {
+	ID      int64  `gorm:"primaryKey"`
+	Name    string `gorm:"column:name"`
+	Upper   string `gorm:"column:upper_name;->"`
+	Rev     int    `gorm:"column:rev;->"`
+	Created string `gorm:"column:created;->"`
+}
+
+// TableName 表名
+func (SQLiteAccount) TableName() string {
+	return "accounts"
+}
+
+// 测试插入后刷新数据库填充的列
+func TestSQLiteCreateRefresh(t *testing.T) {
+	db := initSQLiteScanDB(t, 0)
+	defer db.Close()
+	err := db.Exec(`CREATE TABLE accounts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		upper_name TEXT GENERATED ALWAYS AS (upper(name)) VIRTUAL,
+		rev INTEGER NOT NULL DEFAULT 1,
+		created TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+
+	plain := SQLiteAccount{Name: "plain"}
+	if err := db.Create(&plain); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if plain.ID == 0 || plain.Upper != "" || plain.Rev != 0 {
+		t.Errorf("未开启刷新时 = %+v", plain)
+	}
+
+	account := SQLiteAccount{Name: "neo"}
+	if err := db.WithRefresh().Create(&account); err != nil {
+		t.Fatalf("刷新写入失败: %v", err)
+	}
+	if account.ID != 2 || account.Upper != "NEO" || account.Rev != 1 || account.Created == "" {
+		t.Errorf("刷新后 = %+v", account)
+	}
+
+	accounts := []*SQLiteAccount{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := db.WithRefresh().CreateInBatches(accounts, 2); err != nil {
+		t.Fatalf("批量刷新写入失败: %v", err)
+	}
+	for i, account := range accounts {
+		if account.ID != int64(i+3) || account.Upper != strings.ToUpper(account.Name) || account.Rev != 1 || account.Created == "" {
+			t.Errorf("第 %d 行刷新后 = %+v", i, account)
+		}
+	}
+
+	err = db.Transaction(func(tx *gosqlx.Database) error {
+		account := SQLiteAccount{Name: "tx"}
+		if err := tx.WithRefresh().Create(&account); err != nil {
+			return err
+		}
+		if account.Upper != "TX" || account.Rev != 1 {
+			t.Errorf("事务中刷新后 = %+v", account)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("事务失败: %v", err)
+	}
+}
+
+// SQLiteSubscriber 以 email 唯一的模型
+type SQLiteSubscriber struct {
+	ID    int64  `gorm:"primaryKey"`
+	Email string `gorm:"column:email;uniqueIndex"`
+	Name  string `gorm:"column:name"`
+	Level int    `gorm:"column:level"`
+}
+
+// TableName 表名
+func (SQLiteSubscriber) TableName() string {
+	return "subscribers"
+}
+
+// 测试按冲突处理策略批量创建
+func TestSQLiteCreateInBatchesOnConflict(t *testing.T) {
+	db := initSQLiteScanDB(t, 0)
+	defer db.Close()
+	if err := db.DB().AutoMigrate(&SQLiteSubscriber{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	members := func() []SQLiteSubscriber {
+		return []SQLiteSubscriber{{Email: "a@x", Name: "A", Level: 1}, {Email: "b@x", Name: "B", Level: 1}, {Email: "c@x", Name: "C", Level: 1}}
+	}
+	load := func() map[string]SQLiteSubscriber {
+		var rows []SQLiteSubscriber
+		if err := db.DB().Find(&rows).Error; err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+		byEmail := make(map[string]SQLiteSubscriber, len(rows))
+		for _, row := range rows {
+			byEmail[row.Email] = row
+		}
+		return byEmail
+	}
+
+	initial := members()[:2]
+	summary, err := db.CreateInBatchesOnConflict(&initial, 2, gosqlx.ConflictPolicy{Action: gosqlx.ConflictDoNothing})
+	if err != nil || summary != (gosqlx.CreateSummary{Inserted: 2}) {
+		t.Fatalf("首次写入 = %+v, %v", summary, err)
+	}
+
+	// 重复执行：已存在的行跳过
+	again := members()
+	again[0].Name = "changed"
+	summary, err = db.CreateInBatchesOnConflict(&again, 2, gosqlx.ConflictPolicy{Action: gosqlx.ConflictDoNothing})
+	if err != nil || summary != (gosqlx.CreateSummary{Inserted: 1, Skipped: 2}) {
+		t.Fatalf("跳过冲突 = %+v, %v", summary, err)
+	}
+	if got := load(); len(got) != 3 || got["a@x"].Name != "A" {
+		t.Errorf("跳过冲突后 = %+v", got)
+	}
+
+	// 按唯一列更新指定的列
+	updates := members()
+	for i := range updates {
+		updates[i].Name, updates[i].Level = "U"+updates[i].Name, 2
+	}
+	updates = append(updates, SQLiteSubscriber{Email: "d@x", Name: "D", Level: 2})
+	summary, err = db.CreateInBatchesOnConflict(updates, 3, gosqlx.ConflictPolicy{
+		Action: gosqlx.ConflictUpdateColumns, On: []string{"email"}, UpdateColumns: []string{"name"},
+	})
+	if err != nil || summary != (gosqlx.CreateSummary{Inserted: 1, Updated: 3}) {
+		t.Fatalf("更新冲突 = %+v, %v", summary, err)
+	}
+	if got := load(); len(got) != 4 || got["a@x"].Name != "UA" || got["a@x"].Level != 1 || got["d@x"].Level != 2 {
+		t.Errorf("更新冲突后 = %+v", got)
+	}
+
+	// 按主键覆盖所有列
+	current := load()
+	replaced := []*SQLiteSubscriber{{ID: current["b@x"].ID, Email: "b@x", Name: "RB", Level: 3}, {Email: "e@x", Name: "E"}}
+	summary, err = db.CreateInBatchesOnConflict(replaced, 10, gosqlx.ConflictPolicy{Action: gosqlx.ConflictReplace})
+	if err != nil || summary != (gosqlx.CreateSummary{Inserted: 1, Updated: 1}) {
+		t.Fatalf("覆盖冲突 = %+v, %v", summary, err)
+	}
+	if got := load(); len(got) != 5 || got["b@x"].Name != "RB" || got["b@x"].Level != 3 {
+		t.Errorf("覆盖冲突后 = %+v", got)
+	}
+
+	if _, err := db.CreateInBatchesOnConflict(&again, 2, gosqlx.ConflictPolicy{Action: gosqlx.ConflictUpdateColumns}); err == nil {
+		t.Error("缺少更新列时应报错")
+	}
+}
@@ -0,0 +1,14 @@
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	username TEXT NOT NULL,
+	email TEXT NOT NULL,
+	age INTEGER DEFAULT 0
+);
+
+CREATE TABLE articles (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	content TEXT,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
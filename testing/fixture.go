@@ -0,0 +1,265 @@
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gzorm/gosqlx"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture 单表测试数据
+type Fixture struct {
+	Table string                   // 表名
+	Rows  []map[string]interface{} // 数据行
+}
+
+// LoadFixtures 加载测试数据文件（支持 .yml/.yaml/.json），参数为文件或目录
+// 文件内容为数据行列表时以文件名作为表名，为对象时以键作为表名
+// 加载前按外键依赖逆序清空相关表，再按依赖顺序插入数据
+func (h *Harness) LoadFixtures(paths ...string) *Harness {
+	h.tb.Helper()
+
+	var fixtures []Fixture
+	for _, path := range paths {
+		files, err := fixtureFiles(path)
+		if err != nil {
+			h.tb.Fatalf("读取测试数据失败 %s: %v", path, err)
+		}
+		for _, file := range files {
+			loaded, err := ParseFixtureFile(file)
+			if err != nil {
+				h.tb.Fatalf("解析测试数据失败 %s: %v", file, err)
+			}
+			fixtures = append(fixtures, loaded...)
+		}
+	}
+
+	if err := InsertFixtures(h.db, fixtures...); err != nil {
+		h.tb.Fatalf("加载测试数据失败: %v", err)
+	}
+	return h
+}
+
+// InsertFixtures 按外键依赖顺序写入测试数据
+func InsertFixtures(db *gosqlx.Database, fixtures ...Fixture) error {
+	deps := make(map[string][]string, len(fixtures))
+	for _, fixture := range fixtures {
+		if _, ok := deps[fixture.Table]; ok {
+			continue
+		}
+		tables, err := referencedTables(db, fixture.Table)
+		if err != nil {
+			return fmt.Errorf("获取表 %s 外键失败: %w", fixture.Table, err)
+		}
+		deps[fixture.Table] = tables
+	}
+	fixtures = SortFixtures(fixtures, deps)
+
+	gdb := db.DB()
+	// 逆序清空，避免违反外键约束
+	cleared := make(map[string]bool)
+	for i := len(fixtures) - 1; i >= 0; i-- {
+		table := fixtures[i].Table
+		if cleared[table] {
+			continue
+		}
+		cleared[table] = true
+		if err := gdb.Exec("DELETE FROM " + gdb.Statement.Quote(table)).Error; err != nil {
+			return fmt.Errorf("清空表 %s 失败: %w", table, err)
+		}
+	}
+
+	for _, fixture := range fixtures {
+		for _, row := range fixture.Rows {
+			if err := gdb.Table(fixture.Table).Create(row).Error; err != nil {
+				return fmt.Errorf("写入表 %s 失败: %w", fixture.Table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SortFixtures 按外键依赖对测试数据排序，被引用的表在前
+// deps 为表名到其引用表列表的映射，存在循环依赖时保持原有顺序
+func SortFixtures(fixtures []Fixture, deps map[string][]string) []Fixture {
+	index := make(map[string]int, len(fixtures))
+	for i, fixture := range fixtures {
+		if _, ok := index[strings.ToLower(fixture.Table)]; !ok {
+			index[strings.ToLower(fixture.Table)] = i
+		}
+	}
+
+	sorted := make([]Fixture, 0, len(fixtures))
+	state := make(map[string]int) // 1: 访问中 2: 已完成
+	var visit func(table string)
+	visit = func(table string) {
+		key := strings.ToLower(table)
+		if state[key] != 0 {
+			return
+		}
+		state[key] = 1
+		for _, ref := range deps[table] {
+			if _, ok := index[strings.ToLower(ref)]; ok && !strings.EqualFold(ref, table) {
+				visit(fixtures[index[strings.ToLower(ref)]].Table)
+			}
+		}
+		state[key] = 2
+		for _, fixture := range fixtures {
+			if strings.EqualFold(fixture.Table, table) {
+				sorted = append(sorted, fixture)
+			}
+		}
+	}
+	for _, fixture := range fixtures {
+		visit(fixture.Table)
+	}
+	return sorted
+}
+
+// ParseFixtureFile 解析测试数据文件
+func ParseFixtureFile(path string) ([]Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(content, &data)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&data)
+	default:
+		return nil, fmt.Errorf("不支持的测试数据格式: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := data.(type) {
+	case []interface{}:
+		table := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		rows, err := toRows(v)
+		if err != nil {
+			return nil, err
+		}
+		return []Fixture{{Table: table, Rows: rows}}, nil
+	case map[string]interface{}:
+		tables := make([]string, 0, len(v))
+		for table := range v {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+
+		fixtures := make([]Fixture, 0, len(tables))
+		for _, table := range tables {
+			list, ok := v[table].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("表 %s 的测试数据必须为列表", table)
+			}
+			rows, err := toRows(list)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, Fixture{Table: table, Rows: rows})
+		}
+		return fixtures, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("无效的测试数据格式: %s", path)
+}
+
+// fixtureFiles 展开目录下的测试数据文件
+func fixtureFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yml", ".yaml", ".json":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// toRows 转换数据行
+func toRows(list []interface{}) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("数据行必须为对象: %v", item)
+		}
+		for key, value := range row {
+			if number, ok := value.(json.Number); ok {
+				if i, err := number.Int64(); err == nil {
+					row[key] = i
+				} else if f, err := number.Float64(); err == nil {
+					row[key] = f
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// referencedTables 查询表通过外键引用的表
+func referencedTables(db *gosqlx.Database, table string) ([]string, error) {
+	var query string
+	switch db.Type() {
+	case gosqlx.SQLite:
+		var rows []map[string]interface{}
+		if err := db.DB().Raw("PRAGMA foreign_key_list(" + db.DB().Statement.Quote(table) + ")").Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		var tables []string
+		for _, row := range rows {
+			tables = append(tables, fmt.Sprint(row["table"]))
+		}
+		return tables, nil
+	case gosqlx.MySQL, gosqlx.TiDB, gosqlx.MariaDB, gosqlx.OceanBase:
+		query = "SELECT DISTINCT REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE " +
+			"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL"
+	case gosqlx.PostgresSQL:
+		query = "SELECT DISTINCT ccu.table_name FROM information_schema.table_constraints tc " +
+			"JOIN information_schema.constraint_column_usage ccu ON tc.constraint_name = ccu.constraint_name " +
+			"AND tc.table_schema = ccu.table_schema WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_name = ?"
+	case gosqlx.SQLServer:
+		query = "SELECT DISTINCT OBJECT_NAME(referenced_object_id) FROM sys.foreign_keys WHERE parent_object_id = OBJECT_ID(?)"
+	case gosqlx.Oracle:
+		query = "SELECT DISTINCT r.TABLE_NAME FROM USER_CONSTRAINTS c JOIN USER_CONSTRAINTS r " +
+			"ON c.R_CONSTRAINT_NAME = r.CONSTRAINT_NAME WHERE c.CONSTRAINT_TYPE = 'R' AND c.TABLE_NAME = UPPER(?)"
+	default:
+		return nil, nil
+	}
+
+	var tables []string
+	if err := db.DB().Raw(query, table).Scan(&tables).Error; err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
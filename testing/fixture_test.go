@@ -0,0 +1,33 @@
+package testing
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 测试SQL脚本拆分
+func TestSplitStatements(t *testing.T) {
+	statements := SplitStatements("CREATE TABLE a (id INT);\n\nINSERT INTO a VALUES ('x;y');\n;INSERT INTO a VALUES (2)")
+	expected := []string{"CREATE TABLE a (id INT)", "INSERT INTO a VALUES ('x;y')", "INSERT INTO a VALUES (2)"}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("拆分结果不符合预期: %q", statements)
+	}
+}
+
+// 测试按外键依赖排序
+func TestSortFixtures(t *testing.T) {
+	fixtures := []Fixture{{Table: "comments"}, {Table: "articles"}, {Table: "users"}}
+	deps := map[string][]string{
+		"comments": {"articles", "users"},
+		"articles": {"users"},
+		"users":    {"users"}, // 自引用
+	}
+
+	var tables []string
+	for _, fixture := range SortFixtures(fixtures, deps) {
+		tables = append(tables, fixture.Table)
+	}
+	if !reflect.DeepEqual(tables, []string{"users", "articles", "comments"}) {
+		t.Errorf("排序结果不符合预期: %v", tables)
+	}
+}
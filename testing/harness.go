@@ -0,0 +1,109 @@
+// Package testing 提供数据库测试辅助工具：建表、加载测试数据以及按测试回滚事务
+package testing
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// TB 测试接口，兼容 *testing.T 和 *testing.B
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+// Harness 测试数据库辅助工具
+type Harness struct {
+	tb TB
+	db *gosqlx.Database
+}
+
+// New 创建测试辅助工具
+func New(tb TB, db *gosqlx.Database) *Harness {
+	return &Harness{tb: tb, db: db}
+}
+
+// DB 获取当前数据库（事务中为事务数据库）
+func (h *Harness) DB() *gosqlx.Database {
+	return h.db
+}
+
+// ExecSQL 执行SQL脚本，多条语句以分号分隔
+func (h *Harness) ExecSQL(script string) *Harness {
+	h.tb.Helper()
+	for _, stmt := range SplitStatements(script) {
+		if err := h.db.Exec(stmt); err != nil {
+			h.tb.Fatalf("执行SQL失败 [%s]: %v", stmt, err)
+		}
+	}
+	return h
+}
+
+// ExecSQLFile 执行SQL文件
+func (h *Harness) ExecSQLFile(paths ...string) *Harness {
+	h.tb.Helper()
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			h.tb.Fatalf("读取SQL文件失败 %s: %v", path, err)
+		}
+		h.ExecSQL(string(content))
+	}
+	return h
+}
+
+// CreateModels 根据模型结构体创建表结构
+func (h *Harness) CreateModels(models ...interface{}) *Harness {
+	h.tb.Helper()
+	if _, err := h.db.SyncSchema(gosqlx.SyncOptions{}, models...); err != nil {
+		h.tb.Fatalf("创建模型表失败: %v", err)
+	}
+	return h
+}
+
+// Tx 开启事务，测试结束时自动回滚
+// 返回的辅助工具在事务中执行，可继续加载测试数据
+func (h *Harness) Tx() *Harness {
+	h.tb.Helper()
+	tx := h.db.Begin()
+	if err := tx.DB().Error; err != nil {
+		h.tb.Fatalf("开启事务失败: %v", err)
+	}
+	h.tb.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+	return &Harness{tb: h.tb, db: tx}
+}
+
+// SplitStatements 按分号拆分SQL脚本，忽略引号内的分号和空语句
+func SplitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      rune
+	)
+	for _, r := range script {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ';':
+			if stmt := strings.TrimSpace(current.String()); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
@@ -0,0 +1,218 @@
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gzorm/gosqlx/query"
+)
+
+// UpdatePlansEnv 设置为非空时，Check 将当前执行计划写为新的基线
+const UpdatePlansEnv = "GOSQLX_UPDATE_PLANS"
+
+// PlanOptions 执行计划回归检查选项
+type PlanOptions struct {
+	Dir            string             // 基线目录，每个方言一个 <方言>.json 文件，默认 testdata/plans
+	MaxCostGrowth  float64            // 代价相对基线允许增长的比例，默认 0.5 即 50%；计划不提供代价时比较预估行数
+	CostGrowth     map[string]float64 // 按方言覆盖 MaxCostGrowth，如 {"postgres": 0.2}
+	AllowFullScans []string           // 允许全表扫描的表，如字典表等小表
+	Update         bool               // 写入新基线，未设置时读取环境变量 GOSQLX_UPDATE_PLANS
+}
+
+// PlanRecord 归一化后的执行计划，作为基线保存
+// 节点只保留访问方式、表和索引，去掉编号、行数等随数据变化的信息
+type PlanRecord struct {
+	SQL       string   `json:"sql"`
+	Nodes     []string `json:"nodes,omitempty"`     // 计划节点，格式为 "访问方式 表 索引"
+	Text      []string `json:"text,omitempty"`      // 仅提供文本计划的数据库，数字替换为 ?
+	FullScans []string `json:"fullScans,omitempty"` // 全表扫描的表，文本计划为 *
+	Cost      float64  `json:"cost,omitempty"`      // 预估代价，计划不提供代价时为预估行数
+}
+
+// PlanSuite 执行计划回归检查，记录注册查询的执行计划并与基线比较
+// 查询新增了全表扫描，或代价超过基线的 1+MaxCostGrowth 倍时测试失败:
+//
+//	plans := gosqlxtesting.New(t, db).Plans(gosqlxtesting.PlanOptions{})
+//	plans.Add("orders_by_user", db.NewQuery().Table("orders").Where("user_id = ?", 1))
+//	plans.Check()
+//
+// 首次运行或有意修改查询后，以 GOSQLX_UPDATE_PLANS=1 go test ./... 重新生成基线并提交
+type PlanSuite struct {
+	h       *Harness
+	opts    PlanOptions
+	names   []string
+	queries map[string]*query.Query
+}
+
+// Plans 创建执行计划回归检查
+func (h *Harness) Plans(opts PlanOptions) *PlanSuite {
+	if opts.Dir == "" {
+		opts.Dir = filepath.Join("testdata", "plans")
+	}
+	if opts.MaxCostGrowth <= 0 {
+		opts.MaxCostGrowth = 0.5
+	}
+	if !opts.Update {
+		opts.Update = os.Getenv(UpdatePlansEnv) != ""
+	}
+	return &PlanSuite{h: h, opts: opts, queries: make(map[string]*query.Query)}
+}
+
+// Add 注册查询，名称在基线文件中唯一
+func (s *PlanSuite) Add(name string, q *query.Query) *PlanSuite {
+	s.h.tb.Helper()
+	if _, ok := s.queries[name]; ok {
+		s.h.tb.Fatalf("执行计划查询 %s 重复注册", name)
+	}
+	s.names = append(s.names, name)
+	s.queries[name] = q
+	return s
+}
+
+// Check 获取所有注册查询的执行计划并与基线比较，更新模式下写入新基线
+func (s *PlanSuite) Check() {
+	s.h.tb.Helper()
+	records := make(map[string]map[string]PlanRecord) // 方言 -> 查询名 -> 计划
+	for _, name := range s.names {
+		plan, err := s.queries[name].Explain()
+		if err != nil {
+			s.h.tb.Fatalf("获取 %s 的执行计划失败: %v", name, err)
+		}
+		if records[plan.Dialect] == nil {
+			records[plan.Dialect] = make(map[string]PlanRecord)
+		}
+		records[plan.Dialect][name] = NormalizePlan(plan)
+	}
+
+	var failures []string
+	for dialect, current := range records {
+		path := filepath.Join(s.opts.Dir, dialect+".json")
+		baseline, err := readPlans(path)
+		if err != nil {
+			s.h.tb.Fatalf("读取执行计划基线失败: %v", err)
+		}
+		if s.opts.Update {
+			for name, record := range current {
+				baseline[name] = record
+			}
+			if err := writePlans(path, baseline); err != nil {
+				s.h.tb.Fatalf("写入执行计划基线失败: %v", err)
+			}
+			continue
+		}
+		for _, name := range s.names {
+			record, ok := current[name]
+			if !ok {
+				continue
+			}
+			base, ok := baseline[name]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("%s: 基线 %s 中没有该查询，请设置 %s=1 重新生成", name, path, UpdatePlansEnv))
+				continue
+			}
+			failures = append(failures, s.compare(dialect, name, base, record)...)
+		}
+	}
+	if len(failures) > 0 {
+		s.h.tb.Fatalf("执行计划回归:\n%s", strings.Join(failures, "\n"))
+	}
+}
+
+// compare 比较当前计划与基线，返回回归描述
+func (s *PlanSuite) compare(dialect, name string, base, current PlanRecord) []string {
+	var failures []string
+	growth := s.opts.MaxCostGrowth
+	if g, ok := s.opts.CostGrowth[dialect]; ok {
+		growth = g
+	}
+	allowed := make(map[string]bool)
+	for _, tables := range [][]string{s.opts.AllowFullScans, base.FullScans} {
+		for _, table := range tables {
+			allowed[table] = true
+		}
+	}
+	for _, table := range current.FullScans {
+		if !allowed[table] {
+			failures = append(failures, fmt.Sprintf("%s: 表 %s 变为全表扫描\n  基线: %s\n  当前: %s",
+				name, table, strings.Join(base.plan(), "; "), strings.Join(current.plan(), "; ")))
+		}
+	}
+	if base.Cost > 0 && current.Cost > base.Cost*(1+growth) {
+		failures = append(failures, fmt.Sprintf("%s: 代价从 %.2f 增长到 %.2f，超过 %.0f%%",
+			name, base.Cost, current.Cost, growth*100))
+	}
+	return failures
+}
+
+// plan 计划的节点或文本
+func (r PlanRecord) plan() []string {
+	if len(r.Nodes) > 0 {
+		return r.Nodes
+	}
+	return r.Text
+}
+
+// planNumber 文本计划中的数字（行数、代价、耗时等）
+var planNumber = regexp.MustCompile(`\b\d+(\.\d+)?\b`)
+
+// NormalizePlan 将执行计划归一化为可比较的基线记录
+func NormalizePlan(plan *query.ExplainPlan) PlanRecord {
+	record := PlanRecord{SQL: plan.SQL, Cost: plan.TotalCost()}
+	if record.Cost == 0 {
+		record.Cost = plan.TotalRows()
+	}
+	seen := make(map[string]bool)
+	for _, node := range plan.Nodes {
+		record.Nodes = append(record.Nodes, strings.Join(strings.Fields(strings.Join([]string{node.Operation, node.Table, node.Index}, " ")), " "))
+		if node.FullScan() && !seen[node.Table] {
+			seen[node.Table] = true
+			record.FullScans = append(record.FullScans, node.Table)
+		}
+	}
+	if len(plan.Nodes) == 0 {
+		for _, line := range strings.Split(plan.Text, "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				record.Text = append(record.Text, planNumber.ReplaceAllString(line, "?"))
+			}
+		}
+		if plan.HasFullScan() {
+			record.FullScans = []string{"*"}
+		}
+	}
+	sort.Strings(record.FullScans)
+	return record
+}
+
+// readPlans 读取基线文件，文件不存在时返回空基线
+func readPlans(path string) (map[string]PlanRecord, error) {
+	records := make(map[string]PlanRecord)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &records); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return records, nil
+}
+
+// writePlans 写入基线文件，按查询名排序便于审阅差异
+func writePlans(path string, records map[string]PlanRecord) error {
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
@@ -0,0 +1,241 @@
+package gosqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 限流 ====================
+
+// ErrThrottled 语句因数据库别名的限流配置被拒绝：排队超时、排队已满或上下文在排队期间结束
+var ErrThrottled = errors.New("数据库语句被限流")
+
+// Limit 数据库别名的限流配置，在 GORM 回调链中执行语句前排队等待
+// 覆盖 Database 的 ORM 方法和 Exec/Raw/Query 等原生语句；query 包构建器直接执行的语句不在限流范围内
+type Limit struct {
+	QPS           float64       // 每秒语句数，0 表示不限制
+	Burst         int           // 允许的突发语句数，默认为 QPS（至少 1）
+	MaxConcurrent int           // 同时执行的语句数，0 表示不限制；返回结果集的语句在取得结果集后即释放
+	MaxWait       time.Duration // 最长排队时间，默认 1 秒
+	MaxQueue      int           // 排队的语句数上限，超过时立即拒绝，0 表示不限制
+}
+
+// LimitStats 限流器的当前状态
+type LimitStats struct {
+	Running   int   // 正在执行的语句数
+	Waiting   int   // 排队的语句数
+	Throttled int64 // 累计被拒绝的语句数
+}
+
+// limiter 单个数据库别名的限流器：令牌桶限制速率，信号量限制并发
+type limiter struct {
+	limit     Limit
+	mutex     sync.Mutex
+	tokens    float64
+	last      time.Time
+	slots     chan struct{}
+	waiting   atomic.Int32
+	throttled atomic.Int64
+}
+
+// limiters 按数据库别名保存的限流器
+var limiters = struct {
+	mutex  sync.RWMutex
+	byNick map[string]*limiter
+}{
+	byNick: make(map[string]*limiter),
+}
+
+// SetLimit 设置数据库别名的限流配置，limit 为nil时取消限流；已在排队的语句按原配置执行
+//
+//	gosqlx.SetLimit("orders_primary", &gosqlx.Limit{QPS: 500, MaxConcurrent: 32, MaxWait: 200 * time.Millisecond})
+func SetLimit(nick string, limit *Limit) {
+	limiters.mutex.Lock()
+	defer limiters.mutex.Unlock()
+	if limit == nil {
+		delete(limiters.byNick, nick)
+		return
+	}
+	limiters.byNick[nick] = newLimiter(*limit)
+}
+
+// GetLimitStats 返回数据库别名限流器的当前状态，未配置限流时返回 false
+func GetLimitStats(nick string) (LimitStats, bool) {
+	l := limiterFor(nick)
+	if l == nil {
+		return LimitStats{}, false
+	}
+	return LimitStats{
+		Running:   len(l.slots),
+		Waiting:   int(l.waiting.Load()),
+		Throttled: l.throttled.Load(),
+	}, true
+}
+
+// newLimiter 创建限流器
+func newLimiter(limit Limit) *limiter {
+	if limit.Burst <= 0 {
+		limit.Burst = max(int(limit.QPS), 1)
+	}
+	if limit.MaxWait <= 0 {
+		limit.MaxWait = time.Second
+	}
+	l := &limiter{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+	if limit.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	return l
+}
+
+// limiterFor 获取别名的限流器
+func limiterFor(nick string) *limiter {
+	limiters.mutex.RLock()
+	defer limiters.mutex.RUnlock()
+	return limiters.byNick[nick]
+}
+
+// acquire 等待速率和并发额度，返回释放并发额度的函数
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	waiting := int(l.waiting.Add(1))
+	defer l.waiting.Add(-1)
+	if l.limit.MaxQueue > 0 && waiting > l.limit.MaxQueue {
+		return nil, l.reject(errors.New("排队已满"))
+	}
+
+	deadline := time.NewTimer(l.limit.MaxWait)
+	defer deadline.Stop()
+
+	if delay, ok := l.reserve(); !ok {
+		return nil, l.reject(fmt.Errorf("超过 %.0f 条/秒", l.limit.QPS))
+	} else if delay > 0 {
+		wait := time.NewTimer(delay)
+		defer wait.Stop()
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			l.cancelReservation()
+			return nil, l.reject(ctx.Err())
+		}
+	}
+
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+	case <-deadline.C:
+		return nil, l.reject(fmt.Errorf("超过 %d 条并发", l.limit.MaxConcurrent))
+	case <-ctx.Done():
+		return nil, l.reject(ctx.Err())
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-l.slots }) }, nil
+}
+
+// reserve 预留一个令牌，返回需要等待的时间；等待超过 MaxWait 时不预留
+func (l *limiter) reserve() (time.Duration, bool) {
+	if l.limit.QPS <= 0 {
+		return 0, true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.limit.QPS, float64(l.limit.Burst))
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	delay := time.Duration((1 - l.tokens) / l.limit.QPS * float64(time.Second))
+	if delay > l.limit.MaxWait {
+		return 0, false
+	}
+	l.tokens--
+	return delay, true
+}
+
+// cancelReservation 归还未使用的令牌
+func (l *limiter) cancelReservation() {
+	l.mutex.Lock()
+	l.tokens++
+	l.mutex.Unlock()
+}
+
+// reject 记录并返回限流错误
+func (l *limiter) reject(cause error) error {
+	l.throttled.Add(1)
+	return fmt.Errorf("%w: %w", ErrThrottled, cause)
+}
+
+// throttleReleaseKey 并发额度释放函数在 Settings 中的键前缀
+const throttleReleaseKey = "gosqlx:throttle_release"
+
+// registerThrottleCallbacks 注册限流回调：执行前排队等待额度，执行后释放并发额度
+func registerThrottleCallbacks(db *gorm.DB, nick string) error {
+	callback := db.Callback()
+	begin, end := beginThrottle(nick), endThrottle
+	registers := []func() error{
+		func() error { return callback.Create().Before("*").Register("gosqlx:throttle", begin) },
+		func() error { return callback.Create().After("*").Register("gosqlx:throttle_end", end) },
+		func() error { return callback.Query().Before("*").Register("gosqlx:throttle", begin) },
+		func() error { return callback.Query().After("*").Register("gosqlx:throttle_end", end) },
+		func() error { return callback.Update().Before("*").Register("gosqlx:throttle", begin) },
+		func() error { return callback.Update().After("*").Register("gosqlx:throttle_end", end) },
+		func() error { return callback.Delete().Before("*").Register("gosqlx:throttle", begin) },
+		func() error { return callback.Delete().After("*").Register("gosqlx:throttle_end", end) },
+		func() error { return callback.Raw().Before("*").Register("gosqlx:throttle", begin) },
+		func() error { return callback.Raw().After("*").Register("gosqlx:throttle_end", end) },
+		func() error { return callback.Row().Before("*").Register("gosqlx:throttle", begin) },
+		func() error { return callback.Row().After("*").Register("gosqlx:throttle_end", end) },
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return fmt.Errorf("注册限流回调失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// beginThrottle 按别名的限流配置等待额度
+func beginThrottle(nick string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		l := limiterFor(nick)
+		if l == nil {
+			return
+		}
+		release, err := l.acquire(db.Statement.Context)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		db.Statement.Settings.Store(fmt.Sprintf("%s:%p", throttleReleaseKey, db.Statement), release)
+	}
+}
+
+// endThrottle 释放并发额度
+func endThrottle(db *gorm.DB) {
+	if release, ok := db.Statement.Settings.LoadAndDelete(fmt.Sprintf("%s:%p", throttleReleaseKey, db.Statement)); ok {
+		release.(func())()
+	}
+}
+
+// throttle 为不经过 GORM 执行的语句等待额度
+func (d *Database) throttle(ctx context.Context) (func(), error) {
+	if d.ctx == nil {
+		return func() {}, nil
+	}
+	l := limiterFor(d.ctx.Nick)
+	if l == nil {
+		return func() {}, nil
+	}
+	return l.acquire(ctx)
+}
@@ -0,0 +1,146 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gzorm/gosqlx/query"
+	"gorm.io/gorm"
+)
+
+// ==================== 语句超时 ====================
+
+// ErrQueryTimeout 语句执行超过 Context.Timeout 或 WithTimeout 设置的时间，
+// 同时可通过 errors.Is(err, context.DeadlineExceeded) 判断
+var ErrQueryTimeout = query.ErrQueryTimeout
+
+// queryTimeoutKey 语句超时在上下文中的键
+type queryTimeoutKey struct{}
+
+// WithTimeout 返回使用指定语句超时的数据库实例，覆盖 Context.Timeout；timeout<=0 表示不限制
+//
+//	err := db.WithTimeout(30 * time.Second).Exec("CALL rebuild_report()")
+func (d *Database) WithTimeout(timeout time.Duration) *Database {
+	return d.session(d.db.WithContext(context.WithValue(d.db.Statement.Context, queryTimeoutKey{}, timeout)))
+}
+
+// WithContext 返回在 ctx 下执行语句的数据库实例，ctx 取消时语句随之取消，语句超时仍然生效
+// ctx 中有同一数据库的环境事务（见 FromContext）时返回的实例在该事务中执行
+func (d *Database) WithContext(ctx context.Context) *Database {
+	timeout := d.queryTimeout()
+	if tx, ok := d.ambientTx(ctx); ok {
+		d = tx
+	}
+	return d.session(d.db.WithContext(context.WithValue(ctx, queryTimeoutKey{}, timeout)))
+}
+
+// queryTimeout 当前生效的语句超时
+func (d *Database) queryTimeout() time.Duration {
+	timeout, _ := d.db.Statement.Context.Value(queryTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// statementContext 不经过 GORM 执行的语句使用的上下文
+func (d *Database) statementContext() (context.Context, context.CancelFunc) {
+	ctx := d.db.Statement.Context
+	if timeout := d.queryTimeout(); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+// NewQuery 创建使用当前连接（事务中为事务连接）、上下文、语句超时和注释属性的查询构建器，只读模式下构建器也是只读的
+func (d *Database) NewQuery() *query.Query {
+	var conn interface{} = d.sqlDB
+	pool := d.db.Statement.ConnPool
+	if p, ok := pool.(*retryPool); ok {
+		pool = p.pool
+	}
+	if tx, ok := pool.(*sql.Tx); ok {
+		conn = tx
+	}
+	q := query.NewQuery(conn).Dialect(string(d.dbType)).WithContext(d.db.Statement.Context).Timeout(d.queryTimeout()).Comment(d.comment)
+	if d.ctx != nil && d.ctx.IsReadOnly() {
+		q.ReadOnly()
+	}
+	return q
+}
+
+// timeoutContext 创建 Database 默认的语句上下文，只携带 Context.Timeout 和 Context.WithComment 设置的注释属性；
+// Database 按别名和模式缓存复用，不能绑定创建时 ctx 的取消信号，调用方的上下文通过 WithContext 按次绑定
+func timeoutContext(ctx *Context) context.Context {
+	attrs := query.CommentAttrs(ctx.parent())
+	kv := make([]string, 0, len(attrs)*2)
+	for key, value := range attrs {
+		kv = append(kv, key, value)
+	}
+	return context.WithValue(query.WithComment(context.Background(), kv...), queryTimeoutKey{}, ctx.Timeout)
+}
+
+// timeoutCancelKey 语句超时取消函数在 Settings 中的键前缀
+const timeoutCancelKey = "gosqlx:timeout_cancel"
+
+// registerTimeoutCallbacks 注册语句超时回调：执行前为语句设置截止时间，执行后释放上下文并转换超时错误
+// Row 回调返回的结果集在读取期间仍受截止时间约束，上下文在到期时释放，读取结果集的错误由调用方转换
+func registerTimeoutCallbacks(db *gorm.DB) error {
+	callback := db.Callback()
+	registers := []func() error{
+		func() error { return callback.Create().Before("*").Register("gosqlx:timeout", beginTimeout) },
+		func() error { return callback.Create().After("*").Register("gosqlx:timeout_end", endTimeout) },
+		func() error { return callback.Query().Before("*").Register("gosqlx:timeout", beginTimeout) },
+		func() error { return callback.Query().After("*").Register("gosqlx:timeout_end", endTimeout) },
+		func() error { return callback.Update().Before("*").Register("gosqlx:timeout", beginTimeout) },
+		func() error { return callback.Update().After("*").Register("gosqlx:timeout_end", endTimeout) },
+		func() error { return callback.Delete().Before("*").Register("gosqlx:timeout", beginTimeout) },
+		func() error { return callback.Delete().After("*").Register("gosqlx:timeout_end", endTimeout) },
+		func() error { return callback.Raw().Before("*").Register("gosqlx:timeout", beginTimeout) },
+		func() error { return callback.Raw().After("*").Register("gosqlx:timeout_end", endTimeout) },
+		func() error { return callback.Row().Before("*").Register("gosqlx:timeout", beginRowTimeout) },
+		func() error { return callback.Row().After("*").Register("gosqlx:timeout_end", convertTimeout) },
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return fmt.Errorf("注册语句超时回调失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// beginTimeout 为语句设置截止时间
+func beginTimeout(db *gorm.DB) {
+	timeout, _ := db.Statement.Context.Value(queryTimeoutKey{}).(time.Duration)
+	if timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+	db.Statement.Context = ctx
+	db.Statement.Settings.Store(fmt.Sprintf("%s:%p", timeoutCancelKey, db.Statement), cancel)
+}
+
+// beginRowTimeout 为返回结果集的语句设置截止时间
+func beginRowTimeout(db *gorm.DB) {
+	timeout, _ := db.Statement.Context.Value(queryTimeoutKey{}).(time.Duration)
+	if timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+	db.Statement.Context = ctx
+	context.AfterFunc(ctx, cancel)
+}
+
+// endTimeout 转换超时错误并释放上下文
+func endTimeout(db *gorm.DB) {
+	convertTimeout(db)
+	if cancel, ok := db.Statement.Settings.LoadAndDelete(fmt.Sprintf("%s:%p", timeoutCancelKey, db.Statement)); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
+
+// convertTimeout 语句因截止时间失败时，将错误包装为 ErrQueryTimeout
+func convertTimeout(db *gorm.DB) {
+	if db.Error != nil {
+		db.Error = query.TimeoutError(db.Statement.Context, db.Error)
+	}
+}
@@ -0,0 +1,177 @@
+package gosqlx
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 事务回调与环境事务 ====================
+
+// txKey 环境事务在上下文中的键
+type txKey struct{}
+
+// txState 事务状态，记录提交和回滚后执行的回调
+type txState struct {
+	mutex      sync.Mutex
+	onCommit   []func()
+	onRollback []func()
+	done       bool // 事务已结束
+}
+
+// OnCommit 注册事务提交成功后执行的回调，如清理缓存、发布事件，回调按注册顺序执行
+// 嵌套事务中注册的回调在最外层事务提交后执行；不在事务中时立即执行，试运行模式下不执行
+//
+//	err := db.Transaction(func(tx *gosqlx.Database) error {
+//		tx.OnCommit(func() { cache.Delete(key) })
+//		return tx.Updates(&order, map[string]interface{}{"status": 2})
+//	})
+func (d *Database) OnCommit(fn func()) {
+	if d.dryRun != nil {
+		return
+	}
+	if d.tx == nil {
+		fn()
+		return
+	}
+	d.tx.mutex.Lock()
+	defer d.tx.mutex.Unlock()
+	d.tx.onCommit = append(d.tx.onCommit, fn)
+}
+
+// OnRollback 注册事务回滚后执行的回调，回调按注册的逆序执行
+// 嵌套事务回滚到保存点时只执行其中注册的回调；不在事务中或试运行模式下不执行
+func (d *Database) OnRollback(fn func()) {
+	if d.dryRun != nil || d.tx == nil {
+		return
+	}
+	d.tx.mutex.Lock()
+	defer d.tx.mutex.Unlock()
+	d.tx.onRollback = append(d.tx.onRollback, fn)
+}
+
+// InTransaction 判断是否在事务中
+func (d *Database) InTransaction() bool {
+	return d.tx != nil
+}
+
+// FromContext 返回上下文中的环境事务，Transaction 和 Begin 开启的事务会放入 tx.Context() 和语句上下文中；
+// 事务结束后不再返回。WithContext 和 Transaction 遇到同一数据库的环境事务时自动加入，
+// 因此接收 ctx 的仓储方法无需关心是否在事务中:
+//
+//	db.Transaction(func(tx *gosqlx.Database) error {
+//		return orders.Create(tx.Context(), order) // 内部 db.WithContext(ctx) 使用该事务
+//	})
+func FromContext(ctx context.Context) (*Database, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tx, ok := ctx.Value(txKey{}).(*Database)
+	if !ok || tx.tx.finished() {
+		return nil, false
+	}
+	return tx, true
+}
+
+// WithTx 返回携带环境事务的上下文，用于将 Begin 开启的事务传给只接收 ctx 的业务代码；
+// DatabaseManager.GetDatabase 和 WithContext 遇到该上下文时返回事务实例。tx 不在事务中时原样返回 ctx
+//
+//	tx := db.Begin()
+//	ctx = gosqlx.WithTx(ctx, tx)
+//	err := service.PlaceOrder(ctx, order) // 内部 manager.GetDatabase(...) 取得的是 tx
+func WithTx(ctx context.Context, tx *Database) context.Context {
+	if tx == nil || tx.tx == nil {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// WithTx 返回携带环境事务的新上下文
+func (c *Context) WithTx(tx *Database) *Context {
+	return &Context{
+		Context:      WithTx(c.parent(), tx),
+		Nick:         c.Nick,
+		Mode:         c.Mode,
+		DBType:       c.DBType,
+		Timeout:      c.Timeout,
+		MaxStaleness: c.MaxStaleness,
+	}
+}
+
+// ambientTx 返回上下文中属于同一数据库的环境事务
+func (d *Database) ambientTx(ctx context.Context) (*Database, bool) {
+	if d.tx != nil {
+		return nil, false
+	}
+	tx, ok := FromContext(ctx)
+	if !ok || tx.sqlDB != d.sqlDB {
+		return nil, false
+	}
+	return tx, true
+}
+
+// withTx 创建事务数据库实例，并将其作为环境事务放入上下文
+func (d *Database) withTx(db *gorm.DB, state *txState) *Database {
+	tx := d.session(db)
+	tx.tx = state
+	tx.db = db.WithContext(context.WithValue(db.Statement.Context, txKey{}, tx))
+	tx.wrapStatementRetry()
+	if d.ctx != nil {
+		tx.ctx = d.ctx.WithValue(txKey{}, tx)
+	}
+	return tx
+}
+
+// endTx 事务结束后执行回调；嵌套事务提交时回调交由外层事务执行
+func (d *Database) endTx(state *txState, committed bool) {
+	if d.tx != nil && committed {
+		state.mutex.Lock()
+		onCommit, onRollback := state.onCommit, state.onRollback
+		state.done = true
+		state.mutex.Unlock()
+
+		d.tx.mutex.Lock()
+		d.tx.onCommit = append(d.tx.onCommit, onCommit...)
+		d.tx.onRollback = append(d.tx.onRollback, onRollback...)
+		d.tx.mutex.Unlock()
+		return
+	}
+	state.finish(committed)
+}
+
+// finished 判断事务是否已结束
+func (s *txState) finished() bool {
+	if s == nil {
+		return true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.done
+}
+
+// finish 标记事务结束并执行对应的回调，重复调用只执行一次
+func (s *txState) finish(committed bool) {
+	s.mutex.Lock()
+	if s.done {
+		s.mutex.Unlock()
+		return
+	}
+	s.done = true
+	onCommit, onRollback := s.onCommit, s.onRollback
+	s.onCommit, s.onRollback = nil, nil
+	s.mutex.Unlock()
+
+	if committed {
+		for _, fn := range onCommit {
+			fn()
+		}
+		return
+	}
+	for i := len(onRollback) - 1; i >= 0; i-- {
+		onRollback[i]()
+	}
+}
@@ -0,0 +1,524 @@
+package gosqlx
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ==================== 合并插入结果 ====================
+
+// UpsertAction 合并插入对单行执行的操作
+type UpsertAction string
+
+// 合并插入操作
+const (
+	UpsertInserted UpsertAction = "inserted" // 新插入
+	UpsertUpdated  UpsertAction = "updated"  // 已存在，执行了更新
+)
+
+// UpsertResult 合并插入单行的结果
+type UpsertResult struct {
+	Row    int          // 行在 values 中的下标
+	Action UpsertAction // 执行的操作
+	ID     interface{}  // 结果行的 idColumn 取值，未指定 idColumn 时为 nil
+}
+
+// MergeIntoReturning 合并插入（UPSERT）并按 values 的顺序返回每行是插入还是更新以及结果行的主键
+// PostgreSQL 使用 RETURNING，SQLServer 使用 MERGE ... OUTPUT，单条语句完成；
+// 其他数据库在事务中先查询已存在的键，再执行 MergeInto，最后查询主键，并发写入同一键时状态以先查询的结果为准。
+// keyColumns 必须包含在 columns 中，键列取值按字符串比较；超过参数上限时自动分批，所有批次在同一事务中执行
+//
+//	results, err := db.MergeIntoReturning("users", []string{"email", "name"}, rows, []string{"email"}, []string{"name"}, "id")
+//	for _, r := range results {
+//		if r.Action == gosqlx.UpsertInserted { ... }
+//	}
+func (d *Database) MergeIntoReturning(table string, columns []string, values [][]interface{}, keyColumns []string, updateColumns []string, idColumn string) ([]UpsertResult, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if len(keyColumns) == 0 {
+		return nil, errors.New("合并插入缺少键列")
+	}
+	if !d.Capabilities().Upsert {
+		return nil, fmt.Errorf("%s 不支持合并插入返回结果", d.dbType)
+	}
+	keyIndex := make([]int, len(keyColumns))
+	for i, key := range keyColumns {
+		keyIndex[i] = -1
+		for j, column := range columns {
+			if strings.EqualFold(column, key) {
+				keyIndex[i] = j
+			}
+		}
+		if keyIndex[i] < 0 {
+			return nil, fmt.Errorf("键列 %s 不在插入列中", key)
+		}
+	}
+	// 没有更新列时以键列自身更新，使已存在的行也能返回结果
+	if len(updateColumns) == 0 {
+		updateColumns = keyColumns[:1]
+	}
+
+	results := make([]UpsertResult, len(values))
+	for i := range results {
+		results[i].Row = i
+	}
+	size := max(d.ParamLimit()/len(columns), 1)
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(values); start += size {
+			end := min(start+size, len(values))
+			upsert := upsertBatch{table: table, columns: columns, keyColumns: keyColumns, keyIndex: keyIndex,
+				updateColumns: updateColumns, idColumn: idColumn, rows: values[start:end], results: results[start:end]}
+			var err error
+			switch d.dbType {
+			case PostgresSQL:
+				err = upsert.returning(tx)
+			case SQLServer:
+				err = upsert.output(tx)
+			default:
+				err = d.upsertTwoStep(tx, &upsert)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("合并插入失败: %w", err)
+	}
+	return results, nil
+}
+
+// upsertBatch 一批合并插入的行及其结果
+type upsertBatch struct {
+	table         string
+	columns       []string
+	keyColumns    []string
+	keyIndex      []int // 键列在 columns 中的下标
+	updateColumns []string
+	idColumn      string
+	rows          [][]interface{}
+	results       []UpsertResult
+}
+
+// returning PostgreSQL: INSERT ... ON CONFLICT DO UPDATE ... RETURNING，xmax = 0 表示新插入的行
+func (u *upsertBatch) returning(tx *gorm.DB) error {
+	quoted := quoteColumns(tx, u.columns)
+	sets := make([]string, len(u.updateColumns))
+	for i, column := range u.updateColumns {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", tx.Statement.Quote(column), tx.Statement.Quote(column))
+	}
+	returning := quoteColumns(tx, u.keyColumns)
+	if u.idColumn != "" {
+		returning = append(returning, tx.Statement.Quote(u.idColumn))
+	}
+	placeholders, args := u.values()
+	sqlStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) DO UPDATE SET %s RETURNING %s, (xmax = 0) AS inserted",
+		tx.Statement.Quote(u.table), strings.Join(quoted, ", "), placeholders,
+		strings.Join(quoteColumns(tx, u.keyColumns), ", "), strings.Join(sets, ", "), strings.Join(returning, ", "))
+	return u.scan(tx.Raw(sqlStr, args...), func(action interface{}) UpsertAction {
+		if inserted, _ := action.(bool); inserted {
+			return UpsertInserted
+		}
+		return UpsertUpdated
+	})
+}
+
+// output SQLServer: MERGE ... OUTPUT $action，源数据使用 VALUES 表值构造器
+func (u *upsertBatch) output(tx *gorm.DB) error {
+	quoted := quoteColumns(tx, u.columns)
+	on := make([]string, len(u.keyColumns))
+	for i, column := range u.keyColumns {
+		on[i] = fmt.Sprintf("target.%s = source.%s", tx.Statement.Quote(column), tx.Statement.Quote(column))
+	}
+	sets := make([]string, len(u.updateColumns))
+	for i, column := range u.updateColumns {
+		sets[i] = fmt.Sprintf("target.%s = source.%s", tx.Statement.Quote(column), tx.Statement.Quote(column))
+	}
+	sources := make([]string, len(quoted))
+	for i, column := range quoted {
+		sources[i] = "source." + column
+	}
+	output := make([]string, 0, len(u.keyColumns)+1)
+	for _, column := range u.keyColumns {
+		output = append(output, "inserted."+tx.Statement.Quote(column))
+	}
+	if u.idColumn != "" {
+		output = append(output, "inserted."+tx.Statement.Quote(u.idColumn))
+	}
+	placeholders, args := u.values()
+	sqlStr := fmt.Sprintf("MERGE INTO %s AS target USING (VALUES %s) AS source (%s) ON %s "+
+		"WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s) OUTPUT %s, $action;",
+		tx.Statement.Quote(u.table), placeholders, strings.Join(quoted, ", "), strings.Join(on, " AND "),
+		strings.Join(sets, ", "), strings.Join(quoted, ", "), strings.Join(sources, ", "), strings.Join(output, ", "))
+	return u.scan(tx.Raw(sqlStr, args...), func(action interface{}) UpsertAction {
+		if upsertString(action) == "INSERT" {
+			return UpsertInserted
+		}
+		return UpsertUpdated
+	})
+}
+
+// upsertTwoStep 先查询已存在的键，再执行 MergeInto，最后查询结果行的主键
+func (d *Database) upsertTwoStep(tx *gorm.DB, u *upsertBatch) error {
+	if d.adapter == nil {
+		return errors.New("数据库适配器不支持合并插入")
+	}
+	existing, err := d.upsertKeys(tx, u, false)
+	if err != nil {
+		return err
+	}
+	if err := d.adapter.MergeInto(tx, u.table, u.columns, u.rows, u.keyColumns, u.updateColumns); err != nil {
+		return err
+	}
+
+	// 同一批中重复的键只有第一次出现时是插入
+	seen := make(map[string]bool, len(u.rows))
+	for i, row := range u.rows {
+		key := u.rowKey(row)
+		if _, ok := existing[key]; ok || seen[key] {
+			u.results[i].Action = UpsertUpdated
+		} else {
+			u.results[i].Action = UpsertInserted
+		}
+		seen[key] = true
+	}
+	if u.idColumn == "" {
+		return nil
+	}
+	ids, err := d.upsertKeys(tx, u, true)
+	if err != nil {
+		return err
+	}
+	for i, row := range u.rows {
+		u.results[i].ID = ids[u.rowKey(row)]
+	}
+	return nil
+}
+
+// upsertKeys 查询批次中已存在的键，withID 为 true 时同时返回 idColumn 的取值
+func (d *Database) upsertKeys(tx *gorm.DB, u *upsertBatch, withID bool) (map[string]interface{}, error) {
+	selects := quoteColumns(tx, u.keyColumns)
+	if withID {
+		selects = append(selects, tx.Statement.Quote(u.idColumn))
+	}
+	found := make(map[string]interface{}, len(u.rows))
+	size := max(d.inListLimit()/len(u.keyColumns), 1)
+	for start := 0; start < len(u.rows); start += size {
+		chunk := u.rows[start:min(start+size, len(u.rows))]
+		var (
+			where string
+			args  []interface{}
+		)
+		if len(u.keyColumns) == 1 {
+			keys := make([]interface{}, len(chunk))
+			for i, row := range chunk {
+				keys[i] = row[u.keyIndex[0]]
+			}
+			where, args = tx.Statement.Quote(u.keyColumns[0])+" IN ?", []interface{}{keys}
+		} else {
+			conditions := make([]string, len(chunk))
+			for i, row := range chunk {
+				parts := make([]string, len(u.keyColumns))
+				for j, column := range u.keyColumns {
+					parts[j] = tx.Statement.Quote(column) + " = ?"
+					args = append(args, row[u.keyIndex[j]])
+				}
+				conditions[i] = "(" + strings.Join(parts, " AND ") + ")"
+			}
+			where = strings.Join(conditions, " OR ")
+		}
+
+		rows, err := tx.Raw(fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+			strings.Join(selects, ", "), tx.Statement.Quote(u.table), where), args...).Rows()
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			dest := make([]interface{}, len(selects))
+			pointers := make([]interface{}, len(selects))
+			for i := range dest {
+				pointers[i] = &dest[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			var id interface{}
+			if withID {
+				id = dest[len(dest)-1]
+			}
+			found[upsertKey(dest[:len(u.keyColumns)])] = id
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// values 生成批次的 VALUES 占位符和参数
+func (u *upsertBatch) values() (string, []interface{}) {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(u.columns)), ", ") + ")"
+	placeholders := make([]string, len(u.rows))
+	args := make([]interface{}, 0, len(u.rows)*len(u.columns))
+	for i, values := range u.rows {
+		placeholders[i] = row
+		args = append(args, values...)
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// scan 读取返回的键列、主键和操作列，按键列匹配到批次中的行
+func (u *upsertBatch) scan(query *gorm.DB, action func(interface{}) UpsertAction) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[string][]int, len(u.rows))
+	for i, row := range u.rows {
+		key := u.rowKey(row)
+		index[key] = append(index[key], i)
+	}
+	width := len(u.keyColumns) + 1
+	if u.idColumn != "" {
+		width++
+	}
+	for rows.Next() {
+		dest := make([]interface{}, width)
+		pointers := make([]interface{}, width)
+		for i := range dest {
+			pointers[i] = &dest[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		for _, i := range index[upsertKey(dest[:len(u.keyColumns)])] {
+			u.results[i].Action = action(dest[width-1])
+			if u.idColumn != "" {
+				u.results[i].ID = dest[len(u.keyColumns)]
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// rowKey 行的键列取值
+func (u *upsertBatch) rowKey(row []interface{}) string {
+	values := make([]interface{}, len(u.keyIndex))
+	for i, index := range u.keyIndex {
+		values[i] = row[index]
+	}
+	return upsertKey(values)
+}
+
+// upsertKey 将键列取值转换为可比较的字符串
+func upsertKey(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = upsertString(value)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// upsertString 将数据库返回值或参数转换为字符串
+func upsertString(value interface{}) string {
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, _ = valuer.Value()
+	}
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// quoteColumns 为列名加上引号
+func quoteColumns(tx *gorm.DB, columns []string) []string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = tx.Statement.Quote(column)
+	}
+	return quoted
+}
+
+// ==================== 批量创建的冲突处理 ====================
+
+// ConflictAction CreateInBatchesOnConflict 遇到主键或唯一键冲突时的处理方式
+type ConflictAction int
+
+// 冲突处理方式
+const (
+	ConflictDoNothing     ConflictAction = iota + 1 // 跳过冲突的行
+	ConflictUpdateColumns                           // 更新 UpdateColumns 指定的列
+	ConflictReplace                                 // 以新行覆盖冲突行除主键外的所有列
+)
+
+// ConflictPolicy 批量创建的冲突处理策略
+type ConflictPolicy struct {
+	Action        ConflictAction
+	On            []string // 判定冲突的列（主键或唯一约束的列），默认为主键；ConflictDoNothing 且为空时任一唯一约束冲突都跳过
+	UpdateColumns []string // ConflictUpdateColumns 时更新的列
+}
+
+// CreateSummary 批量创建各行的处理结果统计
+type CreateSummary struct {
+	Inserted int64 // 新插入的行数
+	Updated  int64 // 冲突后更新的行数
+	Skipped  int64 // 冲突后跳过的行数
+}
+
+// CreateInBatchesOnConflict 按冲突处理策略批量创建记录，返回插入、更新和跳过的行数，用于可重复执行的批量导入
+// 冲突子句由各数据库的 GORM 方言生成：PostgreSQL、SQLite、DuckDB 使用 ON CONFLICT，MySQL 系使用 ON DUPLICATE KEY UPDATE，
+// SQLServer、Oracle 使用 MERGE；所有批次在同一事务中执行。跳过的行数取自影响行数，
+// 更新的行数按执行前已存在的 On 列取值统计（同一批中重复的键计为更新），On 列为零值的行计为插入
+//
+//	summary, err := db.CreateInBatchesOnConflict(&users, 500, gosqlx.ConflictPolicy{
+//		Action: gosqlx.ConflictUpdateColumns, On: []string{"email"}, UpdateColumns: []string{"name", "updated_at"},
+//	})
+func (d *Database) CreateInBatchesOnConflict(value interface{}, batchSize int, policy ConflictPolicy) (CreateSummary, error) {
+	var summary CreateSummary
+	if !d.Capabilities().Upsert {
+		return summary, fmt.Errorf("%s 不支持冲突处理", d.dbType)
+	}
+	onConflict, err := policy.clause()
+	if err != nil {
+		return summary, err
+	}
+	if err := d.validate(value); err != nil {
+		return summary, err
+	}
+	stmt := &gorm.Statement{DB: d.db}
+	if err := stmt.Parse(value); err != nil {
+		return summary, fmt.Errorf("解析模型失败: %w", err)
+	}
+	keys := policy.On
+	if len(keys) == 0 && policy.Action != ConflictDoNothing {
+		keys = stmt.Schema.PrimaryFieldDBNames
+	}
+	keyFields := make([]*schema.Field, len(keys))
+	for i, key := range keys {
+		if keyFields[i] = stmt.Schema.LookUpField(key); keyFields[i] == nil {
+			return summary, fmt.Errorf("模型 %s 没有冲突列 %s", stmt.Schema.Name, key)
+		}
+	}
+	if len(keyFields) == 0 && policy.Action != ConflictDoNothing {
+		return summary, fmt.Errorf("模型 %s 没有主键，需指定冲突列", stmt.Schema.Name)
+	}
+
+	rows := insertedRows(reflect.ValueOf(value))
+	if len(rows) == 0 {
+		return summary, nil
+	}
+	if batchSize <= 0 {
+		batchSize = len(rows)
+	}
+	if err := d.runHooks(beforeCreate, value); err != nil {
+		return summary, err
+	}
+	err = d.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(rows); start += batchSize {
+			chunk := rows[start:min(start+batchSize, len(rows))]
+			existing := 0
+			if policy.Action != ConflictDoNothing {
+				var err error
+				if existing, err = d.conflictingRows(tx, stmt.Schema.Table, keyFields, chunk); err != nil {
+					return err
+				}
+			}
+
+			batch := reflect.MakeSlice(reflect.SliceOf(chunk[0].Type()), len(chunk), len(chunk))
+			for i, row := range chunk {
+				batch.Index(i).Set(row)
+			}
+			result := tx.Clauses(onConflict).Create(batch.Interface())
+			if result.Error != nil {
+				return result.Error
+			}
+			if policy.Action == ConflictDoNothing {
+				summary.Inserted += result.RowsAffected
+				summary.Skipped += int64(len(chunk)) - result.RowsAffected
+			} else {
+				summary.Inserted += int64(len(chunk) - existing)
+				summary.Updated += int64(existing)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return CreateSummary{}, fmt.Errorf("批量创建失败: %w", err)
+	}
+	return summary, d.runHooks(afterCreate, value)
+}
+
+// clause 生成 GORM 的冲突子句
+func (p ConflictPolicy) clause() (clause.OnConflict, error) {
+	onConflict := clause.OnConflict{}
+	for _, column := range p.On {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	switch p.Action {
+	case ConflictDoNothing:
+		onConflict.DoNothing = true
+	case ConflictUpdateColumns:
+		if len(p.UpdateColumns) == 0 {
+			return onConflict, errors.New("冲突时更新的列不能为空")
+		}
+		onConflict.DoUpdates = clause.AssignmentColumns(p.UpdateColumns)
+	case ConflictReplace:
+		onConflict.UpdateAll = true
+	default:
+		return onConflict, fmt.Errorf("不支持的冲突处理方式 %d", p.Action)
+	}
+	return onConflict, nil
+}
+
+// conflictingRows 统计批次中冲突列取值已存在（或在批次中重复）的行数
+func (d *Database) conflictingRows(tx *gorm.DB, table string, keyFields []*schema.Field, rows []reflect.Value) (int, error) {
+	u := upsertBatch{table: table, keyIndex: make([]int, len(keyFields))}
+	for i, field := range keyFields {
+		u.keyColumns = append(u.keyColumns, field.DBName)
+		u.keyIndex[i] = i
+	}
+	ctx := tx.Statement.Context
+	for _, row := range rows {
+		values := make([]interface{}, len(keyFields))
+		zero := false
+		for j, field := range keyFields {
+			var isZero bool
+			values[j], isZero = field.ValueOf(ctx, row.Elem())
+			zero = zero || isZero
+		}
+		if !zero {
+			u.rows = append(u.rows, values)
+		}
+	}
+	if len(u.rows) == 0 {
+		return 0, nil
+	}
+	existing, err := d.upsertKeys(tx, &u, false)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	seen := make(map[string]bool, len(u.rows))
+	for _, row := range u.rows {
+		key := u.rowKey(row)
+		if _, ok := existing[key]; ok || seen[key] {
+			count++
+		}
+		seen[key] = true
+	}
+	return count, nil
+}
@@ -0,0 +1,249 @@
+// Package uuid 以各数据库合适的列类型存储的 UUID
+//
+// UUID 可直接作为模型字段使用：MySQL 系和 SQLServer 存为 BINARY(16)，Oracle 存为 RAW(16)，SQLite 存为 BLOB，
+// PostgreSQL、DuckDB 和 ClickHouse 使用原生 uuid 类型。二进制形式按 RFC 4122 的字节序存储，
+// NewV7 生成的按时间递增的 UUID 在二进制列上按字节比较即为生成顺序，适合作为聚簇主键
+//
+//	type Order struct {
+//		ID   uuid.UUID `gorm:"primaryKey"`
+//		Name string
+//	}
+//	db.Create(&Order{ID: uuid.MustNewV7(), Name: "first"})
+package uuid
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	guuid "github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// UUID 按 RFC 4122 字节序存储的 UUID
+type UUID [16]byte
+
+// Nil 全零的 UUID
+var Nil UUID
+
+// ErrInvalidUUID 无法解析的 UUID
+var ErrInvalidUUID = errors.New("无效的 UUID")
+
+// NewV7 生成版本 7 的 UUID：前 48 位为毫秒时间戳，同一毫秒内递增，按字节比较即为生成顺序
+func NewV7() (UUID, error) {
+	u, err := guuid.NewV7()
+	if err != nil {
+		return Nil, fmt.Errorf("生成 UUID 失败: %w", err)
+	}
+	return UUID(u), nil
+}
+
+// MustNewV7 生成版本 7 的 UUID，失败时 panic
+func MustNewV7() UUID {
+	u, err := NewV7()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// NewV4 生成随机的版本 4 UUID
+func NewV4() (UUID, error) {
+	u, err := guuid.NewRandom()
+	if err != nil {
+		return Nil, fmt.Errorf("生成 UUID 失败: %w", err)
+	}
+	return UUID(u), nil
+}
+
+// Parse 解析 xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx、带花括号或 urn:uuid: 前缀以及不带连字符的 32 位十六进制文本
+func Parse(s string) (UUID, error) {
+	u, err := guuid.Parse(s)
+	if err != nil {
+		return Nil, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+	return UUID(u), nil
+}
+
+// MustParse 解析 UUID 文本，失败时 panic
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// FromBytes 按 RFC 4122 字节序解析 16 字节的二进制形式
+func FromBytes(b []byte) (UUID, error) {
+	if len(b) != 16 {
+		return Nil, fmt.Errorf("%w: 长度为 %d 字节", ErrInvalidUUID, len(b))
+	}
+	return UUID(b), nil
+}
+
+// String 返回 xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx 形式的文本
+func (u UUID) String() string {
+	return guuid.UUID(u).String()
+}
+
+// Bytes 返回 16 字节的二进制形式
+func (u UUID) Bytes() []byte {
+	return u[:]
+}
+
+// IsNil 是否为全零的 UUID
+func (u UUID) IsNil() bool {
+	return u == Nil
+}
+
+// Version 返回 UUID 的版本号
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// MarshalText 实现 encoding.TextMarshaler，JSON 中为文本形式
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ==================== database/sql ====================
+
+// Scan 实现 sql.Scanner，支持 16 字节的二进制形式和文本形式
+func (u *UUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*u = Nil
+		return nil
+	case guuid.UUID:
+		*u = UUID(v)
+		return nil
+	case [16]byte:
+		*u = v
+		return nil
+	case []byte:
+		if len(v) == 16 {
+			*u = UUID(v)
+			return nil
+		}
+		return u.UnmarshalText(v)
+	case string:
+		return u.UnmarshalText([]byte(v))
+	}
+	return fmt.Errorf("%w: 无法将 %T 扫描到 UUID", ErrInvalidUUID, src)
+}
+
+// Value 实现 driver.Valuer，返回 16 字节的二进制形式；原生SQL中 PostgreSQL、DuckDB 和 ClickHouse 的 uuid 列需传入 String()
+func (u UUID) Value() (driver.Value, error) {
+	return u.Bytes(), nil
+}
+
+// ==================== GORM ====================
+
+// GormDataType 通用数据类型
+func (UUID) GormDataType() string { return "uuid" }
+
+// GormDBDataType 各数据库的列类型
+func (UUID) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres", "duckdb":
+		return "uuid"
+	case "clickhouse":
+		return "UUID"
+	case "oracle":
+		return "RAW(16)"
+	case "sqlite":
+		return "BLOB"
+	default:
+		return "BINARY(16)"
+	}
+}
+
+// GormValue 根据方言生成写入值：原生 uuid 类型写入文本形式，其余写入二进制形式
+func (u UUID) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if textual(db.Dialector.Name()) {
+		return clause.Expr{SQL: "?", Vars: []interface{}{u.String()}}
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{u.Bytes()}}
+}
+
+// textual 方言是否使用原生 uuid 类型
+func textual(dialect string) bool {
+	switch dialect {
+	case "postgres", "duckdb", "clickhouse":
+		return true
+	}
+	return false
+}
+
+// ==================== SQLServer uniqueidentifier ====================
+
+// GUID SQLServer uniqueidentifier 列使用的 UUID
+// 驱动以 SQL Server 的字节序（前三段小端）返回 uniqueidentifier，GUID 扫描时转换为 RFC 4122 字节序；
+// uniqueidentifier 按最后 6 字节优先排序，NewV7 生成的值在其上不保持顺序，需要按生成顺序聚簇时使用 UUID（BINARY(16)）
+type GUID UUID
+
+// UUID 返回对应的 UUID
+func (g GUID) UUID() UUID {
+	return UUID(g)
+}
+
+// String 返回 xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx 形式的文本
+func (g GUID) String() string {
+	return UUID(g).String()
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (g GUID) MarshalText() ([]byte, error) {
+	return UUID(g).MarshalText()
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (g *GUID) UnmarshalText(text []byte) error {
+	return (*UUID)(g).UnmarshalText(text)
+}
+
+// Scan 实现 sql.Scanner，16 字节的二进制形式按 SQL Server 的字节序解析
+func (g *GUID) Scan(src interface{}) error {
+	if b, ok := src.([]byte); ok && len(b) == 16 {
+		*g = GUID(swapGUID(b))
+		return nil
+	}
+	return (*UUID)(g).Scan(src)
+}
+
+// Value 实现 driver.Valuer，返回文本形式，由 SQL Server 转换为 uniqueidentifier
+func (g GUID) Value() (driver.Value, error) {
+	return g.String(), nil
+}
+
+// GormDataType 通用数据类型
+func (GUID) GormDataType() string { return "uuid" }
+
+// GormDBDataType SQLServer 的列类型
+func (GUID) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return "uniqueidentifier"
+}
+
+// swapGUID 在 SQL Server 字节序与 RFC 4122 字节序之间转换（前三段反转字节）
+func swapGUID(b []byte) UUID {
+	var u UUID
+	copy(u[:], b)
+	u[0], u[1], u[2], u[3] = u[3], u[2], u[1], u[0]
+	u[4], u[5] = u[5], u[4]
+	u[6], u[7] = u[7], u[6]
+	return u
+}
@@ -0,0 +1,119 @@
+package uuid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gzorm/gosqlx/builder"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 测试解析、文本形式和 JSON
+func TestParse(t *testing.T) {
+	u, err := Parse("{0190A1B2-C3D4-7E5F-8A6B-7C8D9E0F1A2B}")
+	if err != nil || u.String() != "0190a1b2-c3d4-7e5f-8a6b-7c8d9e0f1a2b" || u.Version() != 7 {
+		t.Fatalf("Parse() = %v, %v", u, err)
+	}
+	if _, err := Parse("not-a-uuid"); !errors.Is(err, ErrInvalidUUID) {
+		t.Errorf("期望 ErrInvalidUUID，得到 %v", err)
+	}
+	if _, err := FromBytes([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidUUID) {
+		t.Errorf("期望 ErrInvalidUUID，得到 %v", err)
+	}
+
+	data, err := json.Marshal(struct{ ID UUID }{u})
+	if err != nil || string(data) != `{"ID":"0190a1b2-c3d4-7e5f-8a6b-7c8d9e0f1a2b"}` {
+		t.Errorf("json.Marshal() = %s, %v", data, err)
+	}
+	var decoded struct{ ID UUID }
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID != u {
+		t.Errorf("json.Unmarshal() = %v, %v", decoded.ID, err)
+	}
+}
+
+// 测试 NewV7 按生成顺序递增
+func TestNewV7(t *testing.T) {
+	prev := MustNewV7()
+	for i := 0; i < 1000; i++ {
+		next := MustNewV7()
+		if next.Version() != 7 || bytes.Compare(prev[:], next[:]) >= 0 {
+			t.Fatalf("第 %d 个 UUID %s 不大于 %s", i, next, prev)
+		}
+		prev = next
+	}
+}
+
+// 测试扫描二进制、文本形式和 SQLServer 字节序
+func TestScan(t *testing.T) {
+	want := MustParse("00112233-4455-6677-8899-aabbccddeeff")
+	for _, src := range []interface{}{want.Bytes(), want.String(), []byte(want.String()), [16]byte(want)} {
+		var u UUID
+		if err := u.Scan(src); err != nil || u != want {
+			t.Errorf("Scan(%T) = %v, %v", src, u, err)
+		}
+	}
+	var u UUID
+	if err := u.Scan(12); err == nil {
+		t.Error("扫描整数应返回错误")
+	}
+
+	// SQL Server 返回的 uniqueidentifier 前三段为小端
+	var g GUID
+	if err := g.Scan([]byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}); err != nil || g.UUID() != want {
+		t.Errorf("GUID.Scan() = %v, %v", g, err)
+	}
+	if v, _ := g.Value(); v != want.String() {
+		t.Errorf("GUID.Value() = %v", v)
+	}
+}
+
+// 测试 UUID 字段在 SQLite 上以二进制形式存储和查询
+func TestSQLiteRoundTrip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	type Order struct {
+		ID     UUID `gorm:"primaryKey"`
+		Parent *UUID
+		Name   string
+	}
+	if err := db.AutoMigrate(&Order{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	var ddl string
+	db.Raw("SELECT sql FROM sqlite_master WHERE name = 'orders'").Scan(&ddl)
+	if !bytes.Contains([]byte(ddl), []byte("`id` BLOB")) {
+		t.Errorf("建表语句 = %s", ddl)
+	}
+
+	first, second := MustNewV7(), MustNewV7()
+	if err := db.Create(&[]Order{{ID: second, Parent: &first, Name: "second"}, {ID: first, Name: "first"}}).Error; err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	var orders []Order
+	if err := db.Order("id").Find(&orders).Error; err != nil || len(orders) != 2 {
+		t.Fatalf("查询失败: %v, %v", orders, err)
+	}
+	if orders[0].ID != first || orders[0].Parent != nil || orders[1].ID != second || *orders[1].Parent != first {
+		t.Errorf("按 id 排序的结果 = %+v", orders)
+	}
+
+	var length int
+	db.Raw("SELECT length(id) FROM orders WHERE id = ?", first).Scan(&length)
+	if length != 16 {
+		t.Errorf("id 长度 = %d，期望 16 字节", length)
+	}
+	cond, err := builder.UUIDFromTextSQL("sqlite3", "?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if err := db.Raw("SELECT name FROM orders WHERE id = "+cond, second.String()).Scan(&name).Error; err != nil || name != "second" {
+		t.Errorf("按文本形式查询 = %q, %v", name, err)
+	}
+}
@@ -0,0 +1,392 @@
+package gosqlx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ==================== 写入前校验 ====================
+
+// ErrValidation 校验失败，可通过 errors.Is 判断，通过 errors.As 获取 ValidationErrors
+var ErrValidation = errors.New("数据校验失败")
+
+// Validator 写入前的数据校验器
+// 可使用内置的 TagValidator，也可通过 ValidatorFunc 接入 go-playground/validator 等第三方实现:
+//
+//	v := validator.New()
+//	db.SetValidator(gosqlx.ValidatorFunc(v.Struct))
+type Validator interface {
+	// Validate 校验结构体（或结构体切片）的所有字段
+	Validate(value interface{}) error
+}
+
+// PartialValidator 支持只校验部分字段的校验器，用于 Updates 只更新非零字段的场景
+type PartialValidator interface {
+	Validator
+	// ValidatePartial 只校验指定字段
+	ValidatePartial(value interface{}, fields ...string) error
+}
+
+// ValidatorFunc 函数形式的校验器
+type ValidatorFunc func(value interface{}) error
+
+// Validate 调用校验函数
+func (f ValidatorFunc) Validate(value interface{}) error {
+	return f(value)
+}
+
+// Enum 取值限定在固定集合内的类型，如 gen/model 由 ENUM 或 CHECK (col IN (...)) 列生成的字符串类型，
+// 也可以是自定义的整数常量类型。TagValidator 对实现该接口的非零字段（包括指针）校验 IsValid，无需额外标签
+type Enum interface {
+	// IsValid 取值是否在允许的集合内
+	IsValid() bool
+}
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field string      // 字段路径，如 Email、Address.City、[2].Name
+	Tag   string      // 未通过的规则
+	Param string      // 规则参数
+	Value interface{} // 字段值
+}
+
+// Error 实现 error 接口
+func (e FieldError) Error() string {
+	switch e.Tag {
+	case "required":
+		return fmt.Sprintf("%s 不能为空", e.Field)
+	case "min":
+		return fmt.Sprintf("%s 不能小于 %s", e.Field, e.Param)
+	case "max":
+		return fmt.Sprintf("%s 不能大于 %s", e.Field, e.Param)
+	case "len":
+		return fmt.Sprintf("%s 长度必须为 %s", e.Field, e.Param)
+	case "email":
+		return fmt.Sprintf("%s 不是有效的邮箱地址", e.Field)
+	case "url":
+		return fmt.Sprintf("%s 不是有效的URL", e.Field)
+	case "oneof":
+		return fmt.Sprintf("%s 必须是 [%s] 之一", e.Field, e.Param)
+	case "enum":
+		return fmt.Sprintf("%s 不是有效的取值: %v", e.Field, e.Value)
+	}
+	return fmt.Sprintf("%s 未通过 %s 校验", e.Field, e.Tag)
+}
+
+// ValidationErrors 字段校验错误列表
+type ValidationErrors []FieldError
+
+// Error 实现 error 接口
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is 支持 errors.Is(err, ErrValidation)
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// SetValidator 设置写入前校验器，Create/CreateInBatches/Save 校验全部字段，
+// Updates 在校验器实现 PartialValidator 时只校验待更新的字段；传入 nil 关闭校验
+func (d *Database) SetValidator(v Validator) *Database {
+	d.validator = v
+	return d
+}
+
+// validate 校验待写入的值
+func (d *Database) validate(value interface{}) error {
+	if d.validator == nil || value == nil {
+		return nil
+	}
+	return d.validator.Validate(value)
+}
+
+// validatePartial 校验 Updates 的值，结构体只校验非零字段，map 只校验包含的字段
+func (d *Database) validatePartial(model interface{}, values interface{}) error {
+	partial, ok := d.validator.(PartialValidator)
+	if !ok || values == nil {
+		return nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(values))
+	switch rv.Kind() {
+	case reflect.Struct:
+		var fields []string
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Type().Field(i).IsExported() && !rv.Field(i).IsZero() {
+				fields = append(fields, rv.Type().Field(i).Name)
+			}
+		}
+		return partial.ValidatePartial(values, fields...)
+	case reflect.Map:
+		// 将 map 的值填充到模型副本中，按模型的标签校验
+		mt := reflect.Indirect(reflect.ValueOf(model)).Type()
+		if mt.Kind() != reflect.Struct {
+			return nil
+		}
+		copied := reflect.New(mt).Elem()
+		copied.Set(reflect.Indirect(reflect.ValueOf(model)))
+		var fields []string
+		iter := rv.MapRange()
+		for iter.Next() {
+			field, ok := lookupField(mt, fmt.Sprint(iter.Key().Interface()))
+			if !ok {
+				continue
+			}
+			value := reflect.ValueOf(iter.Value().Interface())
+			target := copied.FieldByIndex(field.Index)
+			if !value.IsValid() {
+				target.Set(reflect.Zero(target.Type()))
+			} else if value.Type().AssignableTo(target.Type()) {
+				target.Set(value)
+			} else if value.Type().ConvertibleTo(target.Type()) {
+				target.Set(value.Convert(target.Type()))
+			} else {
+				continue // 表达式等无法校验的值
+			}
+			fields = append(fields, field.Name)
+		}
+		return partial.ValidatePartial(copied.Addr().Interface(), fields...)
+	}
+	return nil
+}
+
+// lookupField 按字段名或蛇形列名查找结构体字段
+func lookupField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.EqualFold(field.Name, name) || toSnakeCase(field.Name) == strings.ToLower(name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// toSnakeCase 驼峰转蛇形
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && !(name[i-1] >= 'A' && name[i-1] <= 'Z') {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ==================== 内置标签校验器 ====================
+
+// TagValidator 基于 validate 结构体标签的内置校验器
+// 支持 required、omitempty、min、max、len、email、url、oneof 规则，多个规则以逗号分隔:
+//
+//	Name  string `validate:"required,max=100"`
+//	Email string `validate:"omitempty,email"`
+//	Role  string `validate:"oneof=admin user"`
+//
+// min/max/len 对字符串和切片比较长度（字符数），对数值比较大小；嵌套结构体会递归校验；
+// 实现 Enum 的字段额外校验取值是否在允许的集合内
+type TagValidator struct {
+	TagName string // 标签名，默认 validate
+}
+
+// NewTagValidator 创建内置标签校验器
+func NewTagValidator() *TagValidator {
+	return &TagValidator{TagName: "validate"}
+}
+
+// Validate 校验结构体或结构体切片的所有字段
+func (v *TagValidator) Validate(value interface{}) error {
+	return v.ValidatePartial(value)
+}
+
+// ValidatePartial 只校验指定的顶层字段，未指定字段时校验全部字段
+func (v *TagValidator) ValidatePartial(value interface{}, fields ...string) error {
+	var only map[string]bool
+	if len(fields) > 0 {
+		only = make(map[string]bool, len(fields))
+		for _, field := range fields {
+			only[field] = true
+		}
+	}
+
+	var errs ValidationErrors
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			v.validateStruct(reflect.Indirect(rv.Index(i)), fmt.Sprintf("[%d].", i), only, &errs)
+		}
+	case reflect.Struct:
+		v.validateStruct(rv, "", only, &errs)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateStruct 校验结构体字段
+func (v *TagValidator) validateStruct(rv reflect.Value, prefix string, only map[string]bool, errs *ValidationErrors) {
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	tagName := v.TagName
+	if tagName == "" {
+		tagName = "validate"
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() || (only != nil && !only[field.Name]) {
+			continue
+		}
+		fv := rv.Field(i)
+		path := prefix + field.Name
+
+		if tag := field.Tag.Get(tagName); tag != "" && tag != "-" {
+			if fieldErr, ok := checkRules(fv, path, tag); !ok {
+				*errs = append(*errs, fieldErr)
+				continue
+			}
+		}
+
+		if fieldErr, ok := checkEnum(fv, path); !ok {
+			*errs = append(*errs, fieldErr)
+			continue
+		}
+
+		// 递归校验嵌套结构体
+		nested := reflect.Indirect(fv)
+		if nested.Kind() == reflect.Struct && nested.Type() != reflect.TypeOf(time.Time{}) {
+			v.validateStruct(nested, path+".", nil, errs)
+		}
+	}
+}
+
+// checkEnum 校验实现 Enum 的字段，零值和空指针视为未设置，由 required 规则校验
+func checkEnum(fv reflect.Value, path string) (FieldError, bool) {
+	value := reflect.Indirect(fv)
+	if !value.IsValid() || value.IsZero() || !value.CanInterface() {
+		return FieldError{}, true
+	}
+	if enum, ok := value.Interface().(Enum); ok && !enum.IsValid() {
+		return FieldError{Field: path, Tag: "enum", Value: value.Interface()}, false
+	}
+	return FieldError{}, true
+}
+
+// emailPattern 邮箱格式
+var emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+
+// checkRules 按规则校验字段值，返回首个未通过的规则
+func checkRules(fv reflect.Value, path, tag string) (FieldError, bool) {
+	// 空指针视为零值
+	value := fv
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			break
+		}
+		value = value.Elem()
+	}
+	isZero := !value.IsValid() || value.IsZero() || (value.Kind() == reflect.Ptr && value.IsNil())
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		fieldErr := FieldError{Field: path, Tag: name, Param: param}
+		if value.IsValid() && value.CanInterface() {
+			fieldErr.Value = value.Interface()
+		}
+
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			if isZero {
+				return FieldError{}, true
+			}
+			continue
+		case "required":
+			if isZero {
+				return fieldErr, false
+			}
+			continue
+		}
+		if value.Kind() == reflect.Ptr {
+			continue // 空指针不参与其他规则
+		}
+
+		var ok bool
+		switch name {
+		case "min", "max", "len":
+			ok = compareSize(value, name, param)
+		case "email":
+			ok = value.Kind() == reflect.String && emailPattern.MatchString(value.String())
+		case "url":
+			u, err := url.Parse(value.String())
+			ok = value.Kind() == reflect.String && err == nil && u.Scheme != "" && u.Host != ""
+		case "oneof":
+			ok = false
+			current := fmt.Sprint(value.Interface())
+			for _, option := range strings.Fields(param) {
+				if option == current {
+					ok = true
+					break
+				}
+			}
+		default:
+			ok = true // 未知规则忽略，便于与第三方校验器共用标签
+		}
+		if !ok {
+			return fieldErr, false
+		}
+	}
+	return FieldError{}, true
+}
+
+// compareSize 比较长度或数值大小
+func compareSize(value reflect.Value, rule, param string) bool {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return true
+	}
+
+	var size float64
+	switch value.Kind() {
+	case reflect.String:
+		size = float64(utf8.RuneCountInString(value.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size = float64(value.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		return true
+	}
+
+	switch rule {
+	case "min":
+		return size >= limit
+	case "max":
+		return size <= limit
+	default:
+		return size == limit
+	}
+}
@@ -0,0 +1,271 @@
+package gosqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ==================== 参数绑定与扫描格式 ====================
+
+// ValueFormat 时间、定点数和 uint64 参数的绑定格式以及时间的扫描时区，各数据库驱动对这些类型的默认处理不同
+// （如 MySQL 驱动按 loc 参数转换 DATETIME，ClickHouse DateTime64、Oracle TIMESTAMP WITH TIME ZONE 和
+// SQLServer datetimeoffset 保留的精度和时区各不相同），统一格式后同一模型在不同数据库上写入和读出的值一致
+// 作用于经由 GORM 执行的语句（包括 Raw/Exec）和 ExecWithResult，不作用于 SqlDB() 和查询构建器
+type ValueFormat struct {
+	// 绑定时将 time.Time 转换到该时区，GORM 查询（Find/First 等）扫描到模型的时间字段也转换到该时区，
+	// 取值为 "UTC"、"Local" 或 IANA 时区名，为空时保持驱动的默认行为
+	TimeZone string `json:"timeZone"`
+	// 绑定时将 time.Time 截断到该精度，如 time.Microsecond，避免超出列精度的部分在不同数据库上被舍入或截断，0 表示不截断
+	TimePrecision time.Duration `json:"timePrecision"`
+	// *big.Rat 和 *big.Float 绑定为保留该位数小数的字符串，0 表示按精确值输出，无法用有限位小数表示的 *big.Rat 返回错误
+	// *big.Int 总是绑定为整数字符串；实现 driver.Valuer 的定点数类型按其 Value 绑定
+	DecimalScale int `json:"decimalScale"`
+	// 超出 int64 范围的 uint64 绑定为十进制字符串，用于不接受该范围 uint64 参数的驱动（PostgreSQL、SQLite、SQLServer、Oracle）
+	Uint64AsString bool `json:"uint64AsString"`
+}
+
+// DefaultValueFormat 返回数据库常用的参数格式：
+// MySQL 系的 DATETIME 不带时区，统一以 UTC 写入，截断到微秒；ClickHouse 以 UTC 写入，截断到 DateTime64(3) 的毫秒；
+// PostgreSQL、DuckDB 和 Oracle 的时间截断到微秒，SQLServer 截断到 datetime2/datetimeoffset 的 100 纳秒，
+// 带时区的列保留原时区；SQLite 以 UTC 写入，使文本形式的时间可按字符串比较
+func DefaultValueFormat(dbType DatabaseType) *ValueFormat {
+	switch dbType {
+	case MySQL, MariaDB, TiDB, OceanBase:
+		return &ValueFormat{TimeZone: "UTC", TimePrecision: time.Microsecond}
+	case ClickHouse:
+		return &ValueFormat{TimeZone: "UTC", TimePrecision: time.Millisecond}
+	case PostgresSQL, DuckDB, Oracle:
+		return &ValueFormat{TimePrecision: time.Microsecond, Uint64AsString: true}
+	case SQLServer:
+		return &ValueFormat{TimePrecision: 100 * time.Nanosecond, Uint64AsString: true}
+	case SQLite:
+		return &ValueFormat{TimeZone: "UTC", Uint64AsString: true}
+	default:
+		return &ValueFormat{}
+	}
+}
+
+// timeFieldType time.Time 的类型
+var timeFieldType = reflect.TypeOf(time.Time{})
+
+// valueBinder 按 ValueFormat 转换参数
+type valueBinder struct {
+	location  *time.Location
+	precision time.Duration
+	scale     int
+	uint64Str bool
+}
+
+// newValueBinder 解析 ValueFormat
+func newValueBinder(format *ValueFormat) (*valueBinder, error) {
+	b := &valueBinder{precision: format.TimePrecision, scale: format.DecimalScale, uint64Str: format.Uint64AsString}
+	if format.TimeZone != "" {
+		location, err := time.LoadLocation(format.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("解析时区 %s 失败: %w", format.TimeZone, err)
+		}
+		b.location = location
+	}
+	if b.scale < 0 {
+		return nil, fmt.Errorf("定点数小数位数不能为负数: %d", b.scale)
+	}
+	return b, nil
+}
+
+// bind 转换单个参数，不需要转换的参数原样返回
+func (b *valueBinder) bind(value interface{}) (interface{}, error) {
+	if n, ok := value.(nullable); ok {
+		v, valid := n.nullValue()
+		if !valid {
+			return nil, nil
+		}
+		return b.bind(v)
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return b.bindTime(v), nil
+	case sql.NullTime:
+		if !v.Valid {
+			return nil, nil
+		}
+		return b.bindTime(v.Time), nil
+	case *time.Time:
+		if v == nil {
+			return v, nil
+		}
+		return b.bindTime(*v), nil
+	case uint64:
+		return b.bindUint64(v), nil
+	case uint:
+		return b.bindUint64(uint64(v)), nil
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		return v.String(), nil
+	case *big.Float:
+		if v == nil {
+			return nil, nil
+		}
+		if b.scale > 0 {
+			return v.Text('f', b.scale), nil
+		}
+		return v.Text('f', -1), nil
+	case *big.Rat:
+		if v == nil {
+			return nil, nil
+		}
+		if b.scale > 0 {
+			return v.FloatString(b.scale), nil
+		}
+		digits, exact := v.FloatPrec()
+		if !exact {
+			return nil, fmt.Errorf("定点数 %s 无法用有限位小数表示，请设置 DecimalScale", v.RatString())
+		}
+		return v.FloatString(digits), nil
+	}
+	return value, nil
+}
+
+// bindTime 转换时区并截断精度
+func (b *valueBinder) bindTime(t time.Time) time.Time {
+	if b.precision > 0 {
+		t = t.Truncate(b.precision)
+	}
+	if b.location != nil {
+		t = t.In(b.location)
+	}
+	return t
+}
+
+// bindUint64 超出 int64 范围时按配置转换为字符串，范围内转换为 int64
+func (b *valueBinder) bindUint64(v uint64) interface{} {
+	if v > math.MaxInt64 {
+		if b.uint64Str {
+			return strconv.FormatUint(v, 10)
+		}
+		return v
+	}
+	return int64(v)
+}
+
+// bindValues 转换绕过 GORM 执行的语句的参数，未配置 ValueFormat 时原样返回
+func (d *Database) bindValues(values []interface{}) ([]interface{}, error) {
+	if d.binder == nil {
+		return values, nil
+	}
+	bound := make([]interface{}, len(values))
+	for i, value := range values {
+		v, err := d.binder.bind(value)
+		if err != nil {
+			return nil, err
+		}
+		bound[i] = v
+	}
+	return bound, nil
+}
+
+// valueDialector 方言包装，GORM 写入参数占位符时按 ValueFormat 转换刚加入的参数
+type valueDialector struct {
+	gorm.Dialector
+	binder *valueBinder
+}
+
+// BindVarTo 转换最后加入的参数后写入占位符
+func (d valueDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	if n := len(stmt.Vars); n > 0 {
+		bound, err := d.binder.bind(stmt.Vars[n-1])
+		if err != nil {
+			stmt.AddError(err)
+		} else {
+			stmt.Vars[n-1] = bound
+		}
+	}
+	d.Dialector.BindVarTo(writer, stmt, v)
+}
+
+// SavePoint 转交被包装方言的保存点实现
+func (d valueDialector) SavePoint(tx *gorm.DB, name string) error {
+	if savePointer, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return savePointer.SavePoint(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+// RollbackTo 转交被包装方言的保存点实现
+func (d valueDialector) RollbackTo(tx *gorm.DB, name string) error {
+	if savePointer, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return savePointer.RollbackTo(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+// Translate 转交被包装方言的错误转换
+func (d valueDialector) Translate(err error) error {
+	if translator, ok := d.Dialector.(gorm.ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+	return err
+}
+
+// registerValueCallbacks 注册将模型时间字段转换到 ValueFormat 时区的查询回调
+func registerValueCallbacks(db *gorm.DB, binder *valueBinder) error {
+	if binder.location == nil {
+		return nil
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("gosqlx:time_zone", scanTimeZone(binder.location)); err != nil {
+		return fmt.Errorf("注册时区转换回调失败: %w", err)
+	}
+	return nil
+}
+
+// scanTimeZone 将查询结果中模型的 time.Time 和 *time.Time 字段转换到 location
+func scanTimeZone(location *time.Location) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil || db.DryRun {
+			return
+		}
+		var fields []*schema.Field
+		for _, field := range db.Statement.Schema.Fields {
+			if t := field.FieldType; t == timeFieldType || (t.Kind() == reflect.Ptr && t.Elem() == timeFieldType) {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			return
+		}
+		convert := func(rv reflect.Value) {
+			for _, field := range fields {
+				fv := field.ReflectValueOf(db.Statement.Context, rv)
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				if t := fv.Interface().(time.Time); !t.IsZero() && fv.CanSet() {
+					fv.Set(reflect.ValueOf(t.In(location)))
+				}
+			}
+		}
+		switch rv := reflect.Indirect(db.Statement.ReflectValue); rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+					convert(elem)
+				}
+			}
+		case reflect.Struct:
+			convert(rv)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gzorm/gosqlx"
+)
+
+// openSQLite 打开内存 SQLite 数据库并写入订单
+func openSQLite(t *testing.T, statements ...string) *gosqlx.Database {
+	ctx := gosqlx.NewContext(context.Background(), "verify", gosqlx.ModeReadWrite)
+	db, err := gosqlx.NewDatabase(ctx, &gosqlx.Config{Type: gosqlx.SQLite, Source: ":memory:", MaxIdle: 1, MaxOpen: 1})
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	statements = append([]string{
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, amount REAL, status TEXT, paid BOOLEAN, updated_at TEXT)",
+		"INSERT INTO orders VALUES (1, 9.5, 'paid', 1, '2024-01-01'), (2, 20, 'new', 0, '2024-01-01'), (3, NULL, 'new', 0, '2024-01-01'), (10, 5, 'paid', 1, '2024-01-01')",
+	}, statements...)
+	for _, statement := range statements {
+		if err := db.Exec(statement); err != nil {
+			t.Fatalf("执行 %s 失败: %v", statement, err)
+		}
+	}
+	return db
+}
+
+// 测试比较两个库的查询结果
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+	q := Query{Name: "orders", SQL: "SELECT id, amount, status, paid, updated_at FROM orders ORDER BY id", Key: []string{"id"}}
+
+	left := openSQLite(t)
+	right := openSQLite(t, "UPDATE orders SET updated_at = '2024-06-01'")
+	report, err := Compare(ctx, left, right, q, Options{})
+	if err != nil {
+		t.Fatalf("比较失败: %v", err)
+	}
+	if report.OK() || report.Different != 4 {
+		t.Fatalf("updated_at 不同时期望 4 行不同: %s", report)
+	}
+	q.IgnoreCols = []string{"UPDATED_AT"}
+	report, err = Compare(ctx, left, right, q, Options{})
+	if err != nil || !report.OK() || report.Matched != 4 || report.LeftChecksum != report.RightChecksum {
+		t.Fatalf("忽略 updated_at 后期望一致: %v %v", report, err)
+	}
+
+	right = openSQLite(t,
+		"UPDATE orders SET status = 'refunded', amount = 0 WHERE id = 2",
+		"UPDATE orders SET amount = 0 WHERE id = 3",
+		"DELETE FROM orders WHERE id = 1",
+		"INSERT INTO orders VALUES (4, 1, 'new', 0, '2024-01-01'), (11, 1, 'new', 0, '2024-01-01')",
+	)
+	report, err = Compare(ctx, left, right, q, Options{})
+	if err != nil {
+		t.Fatalf("比较失败: %v", err)
+	}
+	if report.LeftRows != 4 || report.RightRows != 5 || report.Matched != 1 || report.Different != 2 ||
+		report.MissingRight != 1 || report.MissingLeft != 2 {
+		t.Fatalf("统计不符: %s", report)
+	}
+	want := []struct {
+		key     int64
+		kind    string
+		columns int
+	}{{1, MissingRight, 0}, {2, Different, 2}, {3, Different, 1}, {4, MissingLeft, 0}, {11, MissingLeft, 0}}
+	if len(report.Mismatches) != len(want) {
+		t.Fatalf("不一致的键 = %+v", report.Mismatches)
+	}
+	for i, w := range want {
+		m := report.Mismatches[i]
+		if m.Key[0] != w.key || m.Kind != w.kind || len(m.Columns) != w.columns {
+			t.Errorf("第 %d 个不一致 = %+v，期望 %+v", i, m, w)
+		}
+	}
+
+	report, err = Compare(ctx, left, right, q, Options{MaxMismatches: 2})
+	if err != nil || len(report.Mismatches) != 2 || report.MissingLeft != 2 {
+		t.Fatalf("超过上限后只计数: %v %v", report, err)
+	}
+
+	// 规范化后 NULL 与 0 视为相同
+	report, err = Compare(ctx, left, right, q, Options{Normalize: func(column string, value interface{}) interface{} {
+		if column == "amount" && value == nil {
+			return 0.0
+		}
+		return value
+	}})
+	if err != nil || report.Different != 1 {
+		t.Fatalf("规范化后期望 1 行不同: %v %v", report, err)
+	}
+}
+
+// 测试结果集未按键排序和列不一致
+func TestCompareErrors(t *testing.T) {
+	ctx := context.Background()
+	left, right := openSQLite(t), openSQLite(t)
+
+	_, err := Compare(ctx, left, right, Query{SQL: "SELECT id, status FROM orders ORDER BY id DESC", Key: []string{"id"}}, Options{})
+	if !errors.Is(err, ErrUnordered) {
+		t.Errorf("期望 ErrUnordered，实际为 %v", err)
+	}
+	_, err = Compare(ctx, left, right, Query{
+		SQL:      "SELECT id, status FROM orders ORDER BY id",
+		RightSQL: "SELECT id, amount FROM orders ORDER BY id",
+		Key:      []string{"id"},
+	}, Options{})
+	if err == nil {
+		t.Error("列不一致时期望返回错误")
+	}
+	if _, err = Compare(ctx, left, right, Query{SQL: "SELECT status FROM orders", Key: []string{"id"}}, Options{}); err == nil {
+		t.Error("缺少键列时期望返回错误")
+	}
+	if _, err = Compare(ctx, left, nil, Query{Key: []string{"id"}}, Options{}); !errors.Is(err, ErrNoDatabase) {
+		t.Errorf("期望 ErrNoDatabase，实际为 %v", err)
+	}
+}
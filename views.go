@@ -0,0 +1,59 @@
+package gosqlx
+
+import (
+	"errors"
+
+	"github.com/gzorm/gosqlx/adapter"
+)
+
+// ==================== 视图与触发器 ====================
+
+// errNoAdapter 没有适配器时无法生成方言相关的 DDL
+var errNoAdapter = errors.New("数据库适配器未初始化")
+
+// CreateOrReplaceView 创建视图，同名视图已存在时替换其定义
+// 示例: db.CreateOrReplaceView("active_users", "SELECT id, name FROM users WHERE status = 1")
+func (d *Database) CreateOrReplaceView(name, query string) error {
+	if d.adapter == nil {
+		return errNoAdapter
+	}
+	return d.adapter.CreateOrReplaceView(d.db, name, query)
+}
+
+// DropView 删除视图，不存在时忽略
+func (d *Database) DropView(name string) error {
+	if d.adapter == nil {
+		return errNoAdapter
+	}
+	return d.adapter.DropView(d.db, name)
+}
+
+// ListViews 列出当前数据库（模式）中的视图
+func (d *Database) ListViews() ([]string, error) {
+	if d.adapter == nil {
+		return nil, errNoAdapter
+	}
+	return d.adapter.ListViews(d.db)
+}
+
+// CreateTrigger 创建行级触发器，同名触发器已存在时替换；不支持触发器的数据库返回 adapter.ErrTriggerUnsupported
+// 示例:
+//
+//	err := db.CreateTrigger(adapter.Trigger{
+//		Name: "orders_touch", Table: "orders", Timing: "BEFORE", Events: []string{"UPDATE"},
+//		Body: "SET NEW.updated_at = NOW()",
+//	})
+func (d *Database) CreateTrigger(trigger adapter.Trigger) error {
+	if d.adapter == nil {
+		return errNoAdapter
+	}
+	return d.adapter.CreateTrigger(d.db, trigger)
+}
+
+// DropTrigger 删除表上的触发器，不存在时忽略
+func (d *Database) DropTrigger(table, name string) error {
+	if d.adapter == nil {
+		return errNoAdapter
+	}
+	return d.adapter.DropTrigger(d.db, table, name)
+}
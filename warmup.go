@@ -0,0 +1,104 @@
+package gosqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ==================== 连接预热与保活 ====================
+
+// warmUpTimeout 预热连接的默认超时时间，Context.Timeout 大于 0 时使用 Context.Timeout
+const warmUpTimeout = 30 * time.Second
+
+// warmUp 并发建立 n 个连接并逐个 Ping，归还后留在连接池中作为空闲连接
+// 任一连接建立失败（如账号密码错误、网络不通）时返回错误，使配置问题在启动时暴露
+func warmUp(ctx *Context, sqlDB *sql.DB, n int) error {
+	timeout := warmUpTimeout
+	if ctx.Timeout > 0 {
+		timeout = ctx.Timeout
+	}
+	warmCtx, cancel := context.WithTimeout(ctx.parent(), timeout)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		conns []*sql.Conn
+		errs  []error
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := sqlDB.Conn(warmCtx)
+			if err == nil {
+				err = conn.PingContext(warmCtx)
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if conn != nil {
+				conns = append(conns, conn)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 全部建立后再归还，避免复用刚归还的连接
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("预热连接失败: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// startKeepAlive 每隔 interval 对空闲连接执行一次 Ping，避免空闲连接被负载均衡器或防火墙静默断开，返回停止函数
+func startKeepAlive(sqlDB *sql.DB, interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				pingIdle(sqlDB, interval)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// pingIdle 取出当前所有空闲连接执行 Ping 后归还，已断开的连接由 database/sql 丢弃，
+// 下次使用时重新建立；取连接和 Ping 的总时间不超过 timeout
+func pingIdle(sqlDB *sql.DB, timeout time.Duration) {
+	idle := sqlDB.Stats().Idle
+	if idle == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conns := make([]*sql.Conn, 0, idle)
+	for i := 0; i < idle; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			break
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		_ = conn.PingContext(ctx)
+		_ = conn.Close()
+	}
+}